@@ -3,6 +3,7 @@ package main
 import (
 	"bytes"
 	"context"
+	"log"
 	"os"
 	"sort"
 	"strings"
@@ -13,11 +14,15 @@ import (
 	cd_vfs "github.com/buildbarn/bb-clientd/pkg/filesystem/virtual"
 	"github.com/buildbarn/bb-clientd/pkg/outputpathpersistency"
 	"github.com/buildbarn/bb-clientd/pkg/proto/configuration/bb_clientd"
+	"github.com/buildbarn/bb-clientd/pkg/proto/outputpaths"
+	cd_remoteoutputservice "github.com/buildbarn/bb-clientd/pkg/remoteoutputservice"
 	re_cas "github.com/buildbarn/bb-remote-execution/pkg/cas"
 	re_filesystem "github.com/buildbarn/bb-remote-execution/pkg/filesystem"
 	re_vfs "github.com/buildbarn/bb-remote-execution/pkg/filesystem/virtual"
 	virtual_configuration "github.com/buildbarn/bb-remote-execution/pkg/filesystem/virtual/configuration"
 	"github.com/buildbarn/bb-remote-execution/pkg/proto/remoteoutputservice"
+	"github.com/buildbarn/bb-storage/pkg/auth"
+	"github.com/buildbarn/bb-storage/pkg/blobstore"
 	blobstore_configuration "github.com/buildbarn/bb-storage/pkg/blobstore/configuration"
 	"github.com/buildbarn/bb-storage/pkg/blobstore/grpcservers"
 	"github.com/buildbarn/bb-storage/pkg/builder"
@@ -80,13 +85,18 @@ func main() {
 		// This is necessary, because it isn't always possible to
 		// directly propagate I/O errors returned by the virtual file
 		// system to clients.
-		retryingContentAddressableStorage := bareContentAddressableStorage
-		if maximumDelay := configuration.MaximumFileSystemRetryDelay; maximumDelay != nil {
+		maximumDelay := configuration.MaximumFileSystemRetryDelay
+		if maximumDelay != nil {
 			if err := maximumDelay.CheckValid(); err != nil {
 				return util.StatusWrap(err, "Invalid maximum file system retry delay")
 			}
-			retryingContentAddressableStorage = cd_blobstore.NewErrorRetryingBlobAccess(
-				bareContentAddressableStorage,
+		}
+		newRetryingContentAddressableStorage := func(base blobstore.BlobAccess) blobstore.BlobAccess {
+			if maximumDelay == nil {
+				return base
+			}
+			return cd_blobstore.NewErrorRetryingBlobAccess(
+				base,
 				clock.SystemClock,
 				random.FastThreadSafeGenerator,
 				util.DefaultErrorLogger,
@@ -94,6 +104,28 @@ func main() {
 				30*time.Second,
 				maximumDelay.AsDuration())
 		}
+		retryingContentAddressableStorage := newRetryingContentAddressableStorage(bareContentAddressableStorage)
+
+		// Optionally read the contents of files that are backed by
+		// the Content Addressable Storage in fixed size chunks.
+		chunkSizeBytes := configuration.CasFileReadChunkSizeBytes
+		if chunkSizeBytes != 0 && (chunkSizeBytes < 4*1024 || chunkSizeBytes > 16*1024*1024) {
+			return status.Errorf(codes.InvalidArgument, "CAS file read chunk size of %d bytes is not between 4 KiB and 16 MiB", chunkSizeBytes)
+		}
+		newCASFileContentAddressableStorage := func(base blobstore.BlobAccess) blobstore.BlobAccess {
+			if chunkSizeBytes != 0 {
+				base = cd_blobstore.NewChunkedReadingBlobAccess(base, int(chunkSizeBytes))
+			}
+
+			// Optionally force validation of the contents of
+			// files that are backed by the Content Addressable
+			// Storage.
+			if configuration.VerifyCasFileContents {
+				base = cd_blobstore.NewContentVerifyingBlobAccess(base)
+			}
+			return base
+		}
+		casFileContentAddressableStorage := newCASFileContentAddressableStorage(retryingContentAddressableStorage)
 
 		// Create the virtual file system.
 		mount, rootHandleAllocator, err := virtual_configuration.NewMountFromConfiguration(
@@ -106,6 +138,25 @@ func main() {
 			return util.StatusWrap(err, "Failed to create virtual file system mount")
 		}
 
+		// Optionally report calls that invalidate directory entries
+		// in the kernel and fail to complete in time. These calls
+		// deadlock if the kernel is waiting for a lock that is held
+		// by the caller.
+		var blockingCallWatchdog *cd_vfs.BlockingCallWatchdog
+		if threshold := configuration.BlockingCallWatchdogThreshold; threshold != nil {
+			if err := threshold.CheckValid(); err != nil {
+				return util.StatusWrap(err, "Invalid blocking call watchdog threshold")
+			}
+			blockingCallWatchdog = cd_vfs.NewBlockingCallWatchdog(clock.SystemClock, threshold.AsDuration(), util.DefaultErrorLogger)
+			rootHandleAllocator = cd_vfs.NewBlockingCallWatchingHandleAllocator(rootHandleAllocator, blockingCallWatchdog)
+		}
+
+		// Keep track of the handles of all directories, so that
+		// InvalidateCache() can drop directory entries cached by
+		// the kernel that are suspected to be stale.
+		directoryHandleTracker := cd_vfs.NewDirectoryHandleTracker()
+		rootHandleAllocator = cd_vfs.NewDirectoryHandleTrackingHandleAllocator(rootHandleAllocator, directoryHandleTracker)
+
 		// Factories for virtual file system nodes corresponding to
 		// plain files, executable files, directories and trees.
 		//
@@ -124,7 +175,7 @@ func main() {
 		casFileFactory := re_vfs.NewResolvableHandleAllocatingCASFileFactory(
 			re_vfs.NewBlobAccessCASFileFactory(
 				context.Background(),
-				retryingContentAddressableStorage,
+				casFileContentAddressableStorage,
 				util.DefaultErrorLogger),
 			rootHandleAllocator.New())
 		decomposedCASDirectoryFactory := cd_vfs.NewDecomposedCASDirectoryFactory(
@@ -132,12 +183,14 @@ func main() {
 			casFileFactory,
 			directoryFetcher,
 			rootHandleAllocator.New(),
+			configuration.ComputeCasDirectoryLinkCounts,
 			util.DefaultErrorLogger)
 		treeCASDirectoryFactory := cd_vfs.NewTreeCASDirectoryFactory(
 			context.Background(),
 			casFileFactory,
 			directoryFetcher,
 			rootHandleAllocator.New(),
+			configuration.ComputeCasDirectoryLinkCounts,
 			util.DefaultErrorLogger)
 
 		// Factory function for per instance name "blobs" directories
@@ -212,6 +265,7 @@ func main() {
 			re_vfs.BaseSymlinkFactory,
 			rootHandleAllocator.New())
 		outputPathFactory := cd_vfs.NewInMemoryOutputPathFactory(filePool, symlinkFactory, rootHandleAllocator, sort.Sort, clock.SystemClock)
+		var preloadDigestFunction *digest.Function
 		if persistencyConfiguration := configuration.OutputPathPersistency; persistencyConfiguration != nil {
 			// Upload local files at the end of every build. This
 			// decorator needs to be added before
@@ -245,16 +299,195 @@ func main() {
 				clock.SystemClock,
 				util.DefaultErrorLogger,
 				symlinkFactory)
+
+			// Optionally load output paths from persistent
+			// state, prior to a build being started.
+			if preloadingConfiguration := persistencyConfiguration.Preloading; preloadingConfiguration != nil {
+				instanceName, err := digest.NewInstanceName(preloadingConfiguration.InstanceName)
+				if err != nil {
+					return util.StatusWrapf(err, "Invalid output path preloading instance name %#v", preloadingConfiguration.InstanceName)
+				}
+				digestFunction, err := instanceName.GetDigestFunction(preloadingConfiguration.DigestFunction, 0)
+				if err != nil {
+					return util.StatusWrap(err, "Invalid output path preloading digest function")
+				}
+				preloadDigestFunction = &digestFunction
+			}
+		}
+
+		// Optionally only permit modifications to output paths
+		// through the Remote Output Service.
+		if configuration.ReadOnlyOutputPaths {
+			outputPathFactory = cd_vfs.NewReadOnlyOutputPathFactory(outputPathFactory)
+		}
+		if blockingCallWatchdog != nil {
+			outputPathFactory = cd_vfs.NewBlockingCallWatchingOutputPathFactory(outputPathFactory, blockingCallWatchdog)
+		}
+
+		// Permit all clients to modify output paths, unless an
+		// authorizer is configured explicitly.
+		remoteOutputServiceAuthorizer := auth.NewStaticAuthorizer(func(digest.InstanceName) bool { return true })
+		if authorizerConfiguration := configuration.RemoteOutputServiceAuthorizer; authorizerConfiguration != nil {
+			remoteOutputServiceAuthorizer, err = auth.DefaultAuthorizerFactory.NewAuthorizerFromConfiguration(authorizerConfiguration)
+			if err != nil {
+				return util.StatusWrap(err, "Failed to create Remote Output Service authorizer")
+			}
+		}
+
+		if batchSize := configuration.FindMissingBlobsBatchSize; batchSize > 10*blobstore.RecommendedFindMissingDigestsCount {
+			log.Printf("Warning: A FindMissingBlobs() batch size of %d digests is likely to cause requests to exceed gRPC message size limits", batchSize)
+		}
+
+		var startBuildConcurrency *semaphore.Weighted
+		if concurrency := configuration.MaximumConcurrentStartBuilds; concurrency > 0 {
+			startBuildConcurrency = semaphore.NewWeighted(concurrency)
+		}
+
+		var maximumStartBuildDuration time.Duration
+		if duration := configuration.MaximumStartBuildDuration; duration != nil {
+			if err := duration.CheckValid(); err != nil {
+				return util.StatusWrap(err, "Invalid maximum StartBuild() duration")
+			}
+			maximumStartBuildDuration = duration.AsDuration()
+		}
+
+		var staleBuildGracePeriod time.Duration
+		if duration := configuration.StaleBuildGracePeriod; duration != nil {
+			if err := duration.CheckValid(); err != nil {
+				return util.StatusWrap(err, "Invalid stale build grace period")
+			}
+			staleBuildGracePeriod = duration.AsDuration()
+		}
+
+		var maximumLookupRetryDelay time.Duration
+		if duration := configuration.MaximumLookupRetryDelay; duration != nil {
+			if err := duration.CheckValid(); err != nil {
+				return util.StatusWrap(err, "Invalid maximum lookup retry delay")
+			}
+			maximumLookupRetryDelay = duration.AsDuration()
+		}
+
+		syscallErrorMapping, err := cd_vfs.NewSyscallErrorMappingFromConfiguration(configuration.SyscallErrorCodes)
+		if err != nil {
+			return util.StatusWrap(err, "Invalid syscall error codes")
+		}
+
+		var batchCreateFailureLogger util.ErrorLogger
+		if configuration.LogBatchCreateFailures {
+			batchCreateFailureLogger = util.DefaultErrorLogger
+		}
+
+		boundDirectories := make(map[path.Component]*remoteexecution.Digest, len(configuration.BoundDirectories))
+		for name, treeDigest := range configuration.BoundDirectories {
+			component, err := cd_vfs.NewReservedDirectoryName(name)
+			if err != nil {
+				return util.StatusWrapf(err, "Invalid name for bound directory %#v", name)
+			}
+			boundDirectories[component] = treeDigest
+		}
+
+		var finalizedBuildsDirectoryName path.Component
+		if name := configuration.FinalizedBuildsDirectoryName; name != "" {
+			finalizedBuildsDirectoryName, err = cd_vfs.NewReservedDirectoryName(name)
+			if err != nil {
+				return util.StatusWrap(err, "Invalid finalized builds directory name")
+			}
+		}
+
+		defaultDigestFunction := configuration.DefaultDigestFunction
+		if defaultDigestFunction != remoteexecution.DigestFunction_UNKNOWN {
+			if _, err := digest.EmptyInstanceName.GetDigestFunction(defaultDigestFunction, 0); err != nil {
+				return util.StatusWrap(err, "Invalid default digest function")
+			}
+		}
+
+		// Optionally let output paths of specific output bases or
+		// instance names use a separate Content Addressable
+		// Storage, so that the traffic of tenants is isolated.
+		var outputPathStorageSelector cd_vfs.OutputPathStorageSelector
+		if storageConfigurations := configuration.OutputPathStorages; len(storageConfigurations) > 0 {
+			prefixSelector := cd_vfs.NewPrefixOutputPathStorageSelector()
+			for i, storageConfiguration := range storageConfigurations {
+				info, err := blobstore_configuration.NewBlobAccessFromConfiguration(
+					dependenciesGroup,
+					storageConfiguration.ContentAddressableStorage,
+					blobstore_configuration.NewCASBlobAccessCreator(grpcClientFactory, int(configuration.MaximumMessageSizeBytes)))
+				if err != nil {
+					return util.StatusWrapf(err, "Failed to create Content Addressable Storage of output path storage %d", i)
+				}
+				storageRetryingContentAddressableStorage := newRetryingContentAddressableStorage(info.BlobAccess)
+				storageDirectoryFetcher, err := re_cas.NewCachingDirectoryFetcherFromConfiguration(
+					configuration.DirectoryCache,
+					re_cas.NewBlobAccessDirectoryFetcher(
+						storageRetryingContentAddressableStorage,
+						int(configuration.MaximumMessageSizeBytes),
+						configuration.MaximumTreeSizeBytes))
+				if err != nil {
+					return util.StatusWrapf(err, "Failed to create caching directory fetcher of output path storage %d", i)
+				}
+				storage := cd_vfs.OutputPathStorage{
+					BareContentAddressableStorage:     info.BlobAccess,
+					RetryingContentAddressableStorage: newCASFileContentAddressableStorage(storageRetryingContentAddressableStorage),
+					DirectoryFetcher:                  storageDirectoryFetcher,
+				}
+				for _, prefix := range storageConfiguration.OutputBaseIdPrefixes {
+					if err := prefixSelector.AddOutputBaseIDPrefix(prefix, storage); err != nil {
+						return util.StatusWrapf(err, "Output path storage %d", i)
+					}
+				}
+				for _, prefix := range storageConfiguration.InstanceNamePrefixes {
+					instanceNamePrefix, err := digest.NewInstanceName(prefix)
+					if err != nil {
+						return util.StatusWrapf(err, "Invalid instance name prefix %#v of output path storage %d", prefix, i)
+					}
+					prefixSelector.AddInstanceNamePrefix(instanceNamePrefix, storage)
+				}
+			}
+			outputPathStorageSelector = prefixSelector
 		}
 
 		outputsDirectory := cd_vfs.NewRemoteOutputServiceDirectory(
 			rootHandleAllocator,
 			outputPathFactory,
 			bareContentAddressableStorage,
-			retryingContentAddressableStorage,
+			casFileContentAddressableStorage,
 			directoryFetcher,
 			symlinkFactory,
-			configuration.MaximumTreeSizeBytes)
+			remoteOutputServiceAuthorizer,
+			configuration.MaximumTreeSizeBytes,
+			clock.SystemClock,
+			cd_vfs.RemoteOutputServiceDirectoryOptions{
+				MaximumSymlinkRedirections:            int(configuration.MaximumSymlinkRedirections),
+				MaximumPathDepth:                      int(configuration.MaximumPathDepth),
+				MaximumSymlinkTargetLength:            int(configuration.MaximumSymlinkTargetLength),
+				MaximumBatchStatResponseSizeBytes:     int(configuration.MaximumBatchStatResponseSizeBytes),
+				FindMissingBlobsBatchSize:             int(configuration.FindMissingBlobsBatchSize),
+				ExportUploadConcurrency:               int(configuration.MaximumConcurrentUploadsPerExport),
+				MaximumEntryRemovalEvents:             int(configuration.MaximumEntryRemovalEvents),
+				StartBuildConcurrency:                 startBuildConcurrency,
+				MaximumStartBuildDuration:             maximumStartBuildDuration,
+				StaleBuildGracePeriod:                 staleBuildGracePeriod,
+				MaximumLookupRetryDelay:               maximumLookupRetryDelay,
+				MaximumConcurrentFetchesPerOutputPath: configuration.MaximumConcurrentFetchesPerOutputPath,
+				FileFetchWeight:                       int64(configuration.FileFetchWeight),
+				DirectoryFetchWeight:                  int64(configuration.DirectoryFetchWeight),
+				MaximumConcurrentBuilds:               int(configuration.MaximumConcurrentBuilds),
+				RetainedFinalizedBuilds:               int(configuration.RetainedFinalizedBuildsPerOutputBase),
+				FinalizedBuildsDirectoryName:          finalizedBuildsDirectoryName,
+				BoundDirectories:                      boundDirectories,
+				CopyFilesAcrossInstanceNames:          configuration.CopyFilesAcrossInstanceNames,
+				RewriteAbsoluteSymlinkTargets:         configuration.RewriteAbsoluteSymlinkTargets,
+				RejectInstanceNameChanges:             configuration.RejectInstanceNameChanges,
+				FinalizeBuildWaitsForFetches:          configuration.FinalizeBuildWaitsForFetches,
+				MetricsOutputBaseIDs:                  configuration.RemoteOutputServiceMetricsOutputBaseIds,
+				OutputPathStorageSelector:             outputPathStorageSelector,
+				DirectoryHandleTracker:                directoryHandleTracker,
+				BatchCreateFailureLogger:              batchCreateFailureLogger,
+				LogBatchCreateFailurePaths:            configuration.LogBatchCreateFailurePaths,
+				SyscallErrorMapping:                   syscallErrorMapping,
+				DefaultDigestFunction:                 defaultDigestFunction,
+				PreloadDigestFunction:                 preloadDigestFunction,
+			})
 
 		// Construct the top-level directory of the virtual file system
 		// mount. It contains three subdirectories:
@@ -290,6 +523,35 @@ func main() {
 			return util.StatusWrap(err, "Failed to expose virtual file system mount")
 		}
 
+		// Optionally log calls against the Remote Output Service
+		// that take a long time to complete.
+		var remoteOutputServiceServer remoteoutputservice.RemoteOutputServiceServer = outputsDirectory
+		if threshold := configuration.RemoteOutputServiceSlowRequestThreshold; threshold != nil {
+			if err := threshold.CheckValid(); err != nil {
+				return util.StatusWrap(err, "Invalid Remote Output Service slow request threshold")
+			}
+			remoteOutputServiceServer = cd_remoteoutputservice.NewSlowRequestLoggingServer(
+				remoteOutputServiceServer,
+				clock.SystemClock,
+				util.DefaultErrorLogger,
+				threshold.AsDuration())
+		}
+
+		// Optionally record calls against the Remote Output
+		// Service, so that they can be replayed later on.
+		if recordingConfiguration := configuration.RemoteOutputServiceRecording; recordingConfiguration != nil {
+			recordingDirectory, err := filesystem.NewLocalDirectory(recordingConfiguration.DirectoryPath)
+			if err != nil {
+				return util.StatusWrap(err, "Failed to open Remote Output Service recording directory")
+			}
+			remoteOutputServiceServer = cd_remoteoutputservice.NewRecordingServer(
+				remoteOutputServiceServer,
+				recordingDirectory,
+				clock.SystemClock,
+				util.DefaultErrorLogger,
+				recordingConfiguration.MaximumSizeBytes)
+		}
+
 		// Create a gRPC server that forwards requests to backend clusters.
 		if err := bb_grpc.NewServersFromConfigurationAndServe(
 			configuration.GrpcServers,
@@ -306,8 +568,11 @@ func main() {
 						configuration.MaximumMessageSizeBytes))
 				bytestream.RegisterByteStreamServer(
 					s,
-					grpcservers.NewByteStreamServer(
-						bareContentAddressableStorage,
+					cd_vfs.NewOutputPathByteStreamServer(
+						grpcservers.NewByteStreamServer(
+							bareContentAddressableStorage,
+							1<<16),
+						outputsDirectory,
 						1<<16))
 				remoteexecution.RegisterCapabilitiesServer(
 					s,
@@ -319,7 +584,8 @@ func main() {
 						})))
 				remoteexecution.RegisterExecutionServer(s, buildQueue)
 
-				remoteoutputservice.RegisterRemoteOutputServiceServer(s, outputsDirectory)
+				remoteoutputservice.RegisterRemoteOutputServiceServer(s, remoteOutputServiceServer)
+				outputpaths.RegisterOutputPathsServer(s, outputsDirectory)
 			},
 			siblingsGroup,
 		); err != nil {
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"log"
+	"os"
+
+	"github.com/buildbarn/bb-clientd/pkg/proto/remoteoutputservicerecording"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/remoteoutputservice"
+	"github.com/buildbarn/bb-storage/pkg/program"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protodelim"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// bb_clientd_replay reads a recording of calls against the Remote
+// Output Service that was created by bb_clientd's
+// remote_output_service_recording option, and sends the calls to
+// another instance of bb_clientd. The results of all calls are logged,
+// so that they can be compared against the behavior observed by the
+// client that issued the calls originally.
+func main() {
+	program.RunMain(func(ctx context.Context, siblingsGroup, dependenciesGroup program.Group) error {
+		if len(os.Args) != 3 {
+			return status.Error(codes.InvalidArgument, "Usage: bb_clientd_replay grpc_address recording")
+		}
+
+		connection, err := grpc.Dial(os.Args[1], grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			return util.StatusWrapf(err, "Failed to connect to %#v", os.Args[1])
+		}
+		defer connection.Close()
+		client := remoteoutputservice.NewRemoteOutputServiceClient(connection)
+
+		f, err := os.Open(os.Args[2])
+		if err != nil {
+			return util.StatusWrapf(err, "Failed to open recording %#v", os.Args[2])
+		}
+		defer f.Close()
+
+		reader := bufio.NewReader(f)
+		for i := 0; ; i++ {
+			var call remoteoutputservicerecording.RecordedCall
+			if err := protodelim.UnmarshalFrom(reader, &call); errors.Is(err, io.EOF) {
+				return nil
+			} else if err != nil {
+				return util.StatusWrapf(err, "Failed to read call %d", i)
+			}
+
+			var method string
+			var response proto.Message
+			switch request := call.Request.(type) {
+			case *remoteoutputservicerecording.RecordedCall_StartBuild:
+				method = "StartBuild"
+				response, err = client.StartBuild(ctx, request.StartBuild)
+			case *remoteoutputservicerecording.RecordedCall_BatchCreate:
+				method = "BatchCreate"
+				response, err = client.BatchCreate(ctx, request.BatchCreate)
+			case *remoteoutputservicerecording.RecordedCall_BatchStat:
+				method = "BatchStat"
+				response, err = client.BatchStat(ctx, request.BatchStat)
+			case *remoteoutputservicerecording.RecordedCall_FinalizeBuild:
+				method = "FinalizeBuild"
+				response, err = client.FinalizeBuild(ctx, request.FinalizeBuild)
+			default:
+				return status.Errorf(codes.InvalidArgument, "Call %d has an unknown type", i)
+			}
+
+			recordedAt := call.Time.AsTime()
+			if err != nil {
+				log.Printf("Call %d (%s, recorded at %s) failed: %s", i, method, recordedAt, err)
+			} else if marshaled, err := protojson.Marshal(response); err != nil {
+				return util.StatusWrapf(err, "Failed to marshal response of call %d", i)
+			} else {
+				log.Printf("Call %d (%s, recorded at %s) succeeded: %s", i, method, recordedAt, marshaled)
+			}
+		}
+	})
+}
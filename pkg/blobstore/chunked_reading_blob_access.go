@@ -0,0 +1,118 @@
+package blobstore
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"github.com/buildbarn/bb-storage/pkg/blobstore"
+	"github.com/buildbarn/bb-storage/pkg/blobstore/buffer"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type chunkedReadingBlobAccess struct {
+	blobstore.BlobAccess
+	chunkSizeBytes int64
+
+	lock             sync.Mutex
+	lastChunkDigest  digest.Digest
+	lastChunkOffset  int64
+	lastChunkContent []byte
+}
+
+// NewChunkedReadingBlobAccess creates a decorator for BlobAccess that
+// causes ReadAt() calls against buffers returned by Get() to be
+// translated to reads of chunks of a fixed size, aligned to that size.
+// This allows the granularity at which the contents of files backed by
+// the Content Addressable Storage are fetched to be tuned to the
+// storage backend, regardless of the size of reads issued through
+// FUSE/NFSv4.
+//
+// The most recently read chunk is retained, so that sequential reads
+// that are smaller than the chunk size don't cause the same chunk to
+// be fetched repeatedly.
+func NewChunkedReadingBlobAccess(base blobstore.BlobAccess, chunkSizeBytes int) blobstore.BlobAccess {
+	return &chunkedReadingBlobAccess{
+		BlobAccess:     base,
+		chunkSizeBytes: int64(chunkSizeBytes),
+	}
+}
+
+func (ba *chunkedReadingBlobAccess) Get(ctx context.Context, digest digest.Digest) buffer.Buffer {
+	return buffer.NewValidatedBufferFromReaderAt(
+		&chunkedBlobReader{
+			blobAccess: ba,
+			context:    ctx,
+			digest:     digest,
+		},
+		digest.GetSizeBytes())
+}
+
+// getChunk returns the contents of a single chunk of a blob, either
+// by returning the most recently read chunk or by reading it from the
+// backend.
+func (ba *chunkedReadingBlobAccess) getChunk(ctx context.Context, blobDigest digest.Digest, chunkOffset int64) ([]byte, error) {
+	ba.lock.Lock()
+	if ba.lastChunkContent != nil && ba.lastChunkDigest == blobDigest && ba.lastChunkOffset == chunkOffset {
+		chunk := ba.lastChunkContent
+		ba.lock.Unlock()
+		return chunk, nil
+	}
+	ba.lock.Unlock()
+
+	chunkSizeBytes := ba.chunkSizeBytes
+	if remainingBytes := blobDigest.GetSizeBytes() - chunkOffset; chunkSizeBytes > remainingBytes {
+		chunkSizeBytes = remainingBytes
+	}
+	chunk := make([]byte, chunkSizeBytes)
+	if n, err := ba.BlobAccess.Get(ctx, blobDigest).ReadAt(chunk, chunkOffset); n != len(chunk) {
+		if err == nil || err == io.EOF {
+			err = status.Errorf(codes.Internal, "Blob is %d bytes shorter than expected", int64(len(chunk)-n))
+		}
+		return nil, err
+	}
+
+	ba.lock.Lock()
+	ba.lastChunkDigest = blobDigest
+	ba.lastChunkOffset = chunkOffset
+	ba.lastChunkContent = chunk
+	ba.lock.Unlock()
+	return chunk, nil
+}
+
+// chunkedBlobReader is the ReadAtCloser that backs buffers returned by
+// chunkedReadingBlobAccess.Get().
+type chunkedBlobReader struct {
+	blobAccess *chunkedReadingBlobAccess
+	context    context.Context
+	digest     digest.Digest
+}
+
+func (r *chunkedBlobReader) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, status.Errorf(codes.InvalidArgument, "Negative read offset: %d", off)
+	}
+	sizeBytes := r.digest.GetSizeBytes()
+	n := 0
+	for n < len(p) && off < sizeBytes {
+		chunkOffset := off - off%r.blobAccess.chunkSizeBytes
+		chunk, err := r.blobAccess.getChunk(r.context, r.digest, chunkOffset)
+		if err != nil {
+			return n, err
+		}
+		copied := copy(p[n:], chunk[off-chunkOffset:])
+		n += copied
+		off += int64(copied)
+	}
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+func (r *chunkedBlobReader) Close() error {
+	return nil
+}
@@ -0,0 +1,77 @@
+package blobstore_test
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-clientd/internal/mock"
+	"github.com/buildbarn/bb-clientd/pkg/blobstore"
+	"github.com/buildbarn/bb-storage/pkg/blobstore/buffer"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestChunkedReadingBlobAccessGet(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	baseBlobAccess := mock.NewMockBlobAccess(ctrl)
+	blobAccess := blobstore.NewChunkedReadingBlobAccess(baseBlobAccess, 4)
+
+	blobDigest := digest.MustNewDigest("instance_name", remoteexecution.DigestFunction_MD5, "e9e8a2e2ca7c9bd3e1e2f2a2a2ab9a8d", 10)
+
+	t.Run("BackendFailure", func(t *testing.T) {
+		baseBlobAccess.EXPECT().Get(ctx, blobDigest).
+			Return(buffer.NewBufferFromError(status.Error(codes.Unavailable, "Server not reachable")))
+
+		var p [2]byte
+		_, err := blobAccess.Get(ctx, blobDigest).ReadAt(p[:], 5)
+		testutil.RequireEqualStatus(t, status.Error(codes.Unavailable, "Server not reachable"), err)
+	})
+
+	t.Run("BlobTooShort", func(t *testing.T) {
+		baseBlobAccess.EXPECT().Get(ctx, blobDigest).
+			Return(buffer.NewValidatedBufferFromByteSlice([]byte("0123456")))
+
+		var p [2]byte
+		_, err := blobAccess.Get(ctx, blobDigest).ReadAt(p[:], 9)
+		testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Blob is 2 bytes shorter than expected"), err)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		// A read spanning two chunks should cause both chunks
+		// to be fetched in their entirety.
+		baseBlobAccess.EXPECT().Get(ctx, blobDigest).
+			Return(buffer.NewValidatedBufferFromByteSlice([]byte("0123456789"))).
+			Times(2)
+
+		var p [3]byte
+		n, err := blobAccess.Get(ctx, blobDigest).ReadAt(p[:], 3)
+		require.NoError(t, err)
+		require.Equal(t, 3, n)
+		require.Equal(t, []byte("345"), p[:])
+
+		// Successive reads within the last chunk should not
+		// cause it to be fetched again.
+		n, err = blobAccess.Get(ctx, blobDigest).ReadAt(p[:2], 6)
+		require.NoError(t, err)
+		require.Equal(t, 2, n)
+		require.Equal(t, []byte("67"), p[:2])
+
+		// The final chunk is smaller than the chunk size.
+		// Reading beyond it should yield EOF.
+		baseBlobAccess.EXPECT().Get(ctx, blobDigest).
+			Return(buffer.NewValidatedBufferFromByteSlice([]byte("0123456789")))
+
+		n, err = blobAccess.Get(ctx, blobDigest).ReadAt(p[:], 8)
+		require.Equal(t, io.EOF, err)
+		require.Equal(t, 2, n)
+		require.Equal(t, []byte("89"), p[:2])
+	})
+}
@@ -0,0 +1,87 @@
+package blobstore
+
+import (
+	"context"
+
+	"github.com/buildbarn/bb-storage/pkg/blobstore"
+	"github.com/buildbarn/bb-storage/pkg/blobstore/buffer"
+	"github.com/buildbarn/bb-storage/pkg/blobstore/slicing"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/util"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type concurrencyLimitingBlobAccess struct {
+	blobstore.BlobAccess
+	semaphore     Semaphore
+	queuedFetches prometheus.Gauge
+}
+
+// NewConcurrencyLimitingBlobAccess creates a decorator for BlobAccess
+// that limits the number of Get() and GetFromComposite() calls whose
+// buffers are in flight. A slot in the semaphore is acquired before
+// the call is forwarded, and is released once the returned buffer has
+// been consumed or discarded. Calls that cannot acquire a slot block
+// until one becomes available, or until their context is cancelled.
+//
+// This provides backpressure when clients of the virtual file system
+// read files faster than the Content Addressable Storage is able to
+// return them, as opposed to letting the number of concurrent fetches
+// grow without bound. The number of calls that are waiting for a slot
+// is exposed through the provided gauge. The gauge may be nil if the
+// semaphore already tracks this, as is the case for semaphores created
+// through NewWeightedFairSemaphores().
+func NewConcurrencyLimitingBlobAccess(base blobstore.BlobAccess, semaphore Semaphore, queuedFetches prometheus.Gauge) blobstore.BlobAccess {
+	return &concurrencyLimitingBlobAccess{
+		BlobAccess:    base,
+		semaphore:     semaphore,
+		queuedFetches: queuedFetches,
+	}
+}
+
+func (ba *concurrencyLimitingBlobAccess) acquire(ctx context.Context) error {
+	if ba.semaphore.TryAcquire(1) {
+		return nil
+	}
+	if ba.queuedFetches != nil {
+		ba.queuedFetches.Inc()
+		defer ba.queuedFetches.Dec()
+	}
+	if err := ba.semaphore.Acquire(ctx, 1); err != nil {
+		return util.StatusFromContext(ctx)
+	}
+	return nil
+}
+
+func (ba *concurrencyLimitingBlobAccess) Get(ctx context.Context, digest digest.Digest) buffer.Buffer {
+	if err := ba.acquire(ctx); err != nil {
+		return buffer.NewBufferFromError(util.StatusWrap(err, "Failed to wait for other fetches to complete"))
+	}
+	return buffer.WithErrorHandler(
+		ba.BlobAccess.Get(ctx, digest),
+		&concurrencyLimitingErrorHandler{blobAccess: ba})
+}
+
+func (ba *concurrencyLimitingBlobAccess) GetFromComposite(ctx context.Context, parentDigest, childDigest digest.Digest, slicer slicing.BlobSlicer) buffer.Buffer {
+	if err := ba.acquire(ctx); err != nil {
+		return buffer.NewBufferFromError(util.StatusWrap(err, "Failed to wait for other fetches to complete"))
+	}
+	return buffer.WithErrorHandler(
+		ba.BlobAccess.GetFromComposite(ctx, parentDigest, childDigest, slicer),
+		&concurrencyLimitingErrorHandler{blobAccess: ba})
+}
+
+// concurrencyLimitingErrorHandler is used by
+// concurrencyLimitingBlobAccess to release the slot in the semaphore
+// once a buffer is consumed.
+type concurrencyLimitingErrorHandler struct {
+	blobAccess *concurrencyLimitingBlobAccess
+}
+
+func (eh *concurrencyLimitingErrorHandler) OnError(err error) (buffer.Buffer, error) {
+	return nil, err
+}
+
+func (eh *concurrencyLimitingErrorHandler) Done() {
+	eh.blobAccess.semaphore.Release(1)
+}
@@ -0,0 +1,77 @@
+package blobstore_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-clientd/internal/mock"
+	"github.com/buildbarn/bb-clientd/pkg/blobstore"
+	"github.com/buildbarn/bb-storage/pkg/blobstore/buffer"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus"
+	prometheus_testutil "github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"golang.org/x/sync/semaphore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestConcurrencyLimitingBlobAccessGet(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	baseBlobAccess := mock.NewMockBlobAccess(ctrl)
+	queuedFetches := prometheus.NewGauge(prometheus.GaugeOpts{Name: "queued_fetches"})
+	blobAccess := blobstore.NewConcurrencyLimitingBlobAccess(baseBlobAccess, semaphore.NewWeighted(1), queuedFetches)
+
+	helloDigest := digest.MustNewDigest("instance_name", remoteexecution.DigestFunction_MD5, "8b1a9953c4611296a827abf8c47804d7", 5)
+
+	// Obtain a buffer without consuming it, so that the only slot
+	// remains occupied. The buffer needs to be backed by a reader, as
+	// buffers that are already in memory release their slot
+	// immediately.
+	baseBlobAccess.EXPECT().Get(ctx, helloDigest).
+		Return(buffer.NewCASBufferFromReader(helloDigest, io.NopCloser(bytes.NewBufferString("Hello")), buffer.UserProvided))
+	b1 := blobAccess.Get(ctx, helloDigest)
+
+	t.Run("ContextCancelled", func(t *testing.T) {
+		// Calls that are blocked on other fetches should
+		// respect cancellation of their context.
+		ctxCancelled, cancel := context.WithCancel(ctx)
+		cancel()
+
+		_, err := blobAccess.Get(ctxCancelled, helloDigest).ToByteSlice(10)
+		testutil.RequireEqualStatus(t, status.Error(codes.Canceled, "Failed to wait for other fetches to complete: context canceled"), err)
+		require.Equal(t, 0.0, prometheus_testutil.ToFloat64(queuedFetches))
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		// A second call should block until the buffer of the
+		// first call is consumed.
+		baseBlobAccess.EXPECT().Get(ctx, helloDigest).
+			Return(buffer.NewValidatedBufferFromByteSlice([]byte("Hello")))
+		result := make(chan []byte, 1)
+		go func() {
+			data, err := blobAccess.Get(ctx, helloDigest).ToByteSlice(10)
+			require.NoError(t, err)
+			result <- data
+		}()
+
+		require.Eventually(t, func() bool {
+			return prometheus_testutil.ToFloat64(queuedFetches) == 1.0
+		}, 10*time.Second, time.Millisecond)
+
+		data, err := b1.ToByteSlice(10)
+		require.NoError(t, err)
+		require.Equal(t, []byte("Hello"), data)
+
+		require.Equal(t, []byte("Hello"), <-result)
+		require.Equal(t, 0.0, prometheus_testutil.ToFloat64(queuedFetches))
+	})
+}
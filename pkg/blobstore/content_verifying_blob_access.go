@@ -0,0 +1,44 @@
+package blobstore
+
+import (
+	"context"
+
+	"github.com/buildbarn/bb-storage/pkg/blobstore"
+	"github.com/buildbarn/bb-storage/pkg/blobstore/buffer"
+	"github.com/buildbarn/bb-storage/pkg/blobstore/slicing"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+)
+
+type contentVerifyingBlobAccess struct {
+	blobstore.BlobAccess
+}
+
+// NewContentVerifyingBlobAccess creates a decorator for BlobAccess that
+// forces the contents of blobs returned by Get() and
+// GetFromComposite() to be validated against their digest, regardless
+// of whether the storage backend is trusted to return valid data.
+//
+// Validation requires that blobs are read in their entirety, even if
+// only a small part of them is requested. This makes it expensive to
+// use in combination with FUSE/NFSv4 based file access, which is why
+// bb_clientd only enables it when configured explicitly.
+func NewContentVerifyingBlobAccess(base blobstore.BlobAccess) blobstore.BlobAccess {
+	return &contentVerifyingBlobAccess{
+		BlobAccess: base,
+	}
+}
+
+func verifyBuffer(blobDigest digest.Digest, b buffer.Buffer) buffer.Buffer {
+	return buffer.NewCASBufferFromReader(
+		blobDigest,
+		b.ToReader(),
+		buffer.BackendProvided(buffer.Irreparable(blobDigest)))
+}
+
+func (ba *contentVerifyingBlobAccess) Get(ctx context.Context, digest digest.Digest) buffer.Buffer {
+	return verifyBuffer(digest, ba.BlobAccess.Get(ctx, digest))
+}
+
+func (ba *contentVerifyingBlobAccess) GetFromComposite(ctx context.Context, parentDigest, childDigest digest.Digest, slicer slicing.BlobSlicer) buffer.Buffer {
+	return verifyBuffer(childDigest, ba.BlobAccess.GetFromComposite(ctx, parentDigest, childDigest, slicer))
+}
@@ -0,0 +1,198 @@
+package blobstore
+
+import (
+	"container/list"
+	"context"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Semaphore is the subset of the methods of semaphore.Weighted that is
+// used by decorators that limit the number of concurrent fetches. It
+// permits these decorators to be used in combination with semaphores
+// that share slots between multiple classes of fetches.
+type Semaphore interface {
+	Acquire(ctx context.Context, n int64) error
+	TryAcquire(n int64) bool
+	Release(n int64)
+}
+
+// weightedFairSemaphoreStrideBase is the value that is divided by the
+// weight of a class to obtain the amount by which its pass value is
+// advanced every time a waiter is admitted.
+const weightedFairSemaphoreStrideBase = 1 << 32
+
+// WeightedFairSemaphoreClass contains the properties of a single
+// class of callers of a semaphore created by NewWeightedFairSemaphores.
+type WeightedFairSemaphoreClass struct {
+	// The relative share of slots that callers of this class
+	// obtain while other classes are waiting as well.
+	Weight int64
+	// Gauge for tracking the number of callers of this class that
+	// are waiting for a slot to become available.
+	QueuedAcquisitions prometheus.Gauge
+}
+
+type weightedFairSemaphores struct {
+	lock      sync.Mutex
+	available int64
+	classes   []*weightedFairSemaphore
+}
+
+type weightedFairSemaphore struct {
+	shared             *weightedFairSemaphores
+	stride             uint64
+	pass               uint64
+	waiters            list.List
+	queuedAcquisitions prometheus.Gauge
+}
+
+type weightedFairSemaphoreWaiter struct {
+	n     int64
+	ready chan struct{}
+}
+
+// NewWeightedFairSemaphores creates a set of semaphores, one for each
+// provided class, that share a fixed number of slots. Slots are handed
+// out immediately if no callers are waiting. Once callers of multiple
+// classes are waiting, slots are handed out in proportion to the
+// weights of the classes, using stride scheduling. Callers within the
+// same class are admitted in FIFO order.
+//
+// This can be used to prevent one class of fetches from starving
+// another class, while still permitting a class to use all slots if no
+// other class needs them.
+func NewWeightedFairSemaphores(capacity int64, classes []WeightedFairSemaphoreClass) []Semaphore {
+	shared := &weightedFairSemaphores{
+		available: capacity,
+	}
+	semaphores := make([]Semaphore, 0, len(classes))
+	for _, class := range classes {
+		if class.Weight <= 0 {
+			panic("Semaphore classes must have a positive weight")
+		}
+		s := &weightedFairSemaphore{
+			shared:             shared,
+			stride:             uint64(weightedFairSemaphoreStrideBase / class.Weight),
+			queuedAcquisitions: class.QueuedAcquisitions,
+		}
+		shared.classes = append(shared.classes, s)
+		semaphores = append(semaphores, s)
+	}
+	return semaphores
+}
+
+// hasWaitersLocked returns whether callers of any class are waiting
+// for slots to become available.
+func (ss *weightedFairSemaphores) hasWaitersLocked() bool {
+	for _, s := range ss.classes {
+		if s.waiters.Len() > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// getNextClassLocked returns the class with waiters that has the
+// lowest pass value, meaning that it has received the smallest share
+// of slots relative to its weight.
+func (ss *weightedFairSemaphores) getNextClassLocked() *weightedFairSemaphore {
+	var next *weightedFairSemaphore
+	for _, s := range ss.classes {
+		if s.waiters.Len() > 0 && (next == nil || s.pass < next.pass) {
+			next = s
+		}
+	}
+	return next
+}
+
+// notifyWaitersLocked admits waiting callers for as long as slots are
+// available.
+func (ss *weightedFairSemaphores) notifyWaitersLocked() {
+	for {
+		s := ss.getNextClassLocked()
+		if s == nil {
+			return
+		}
+		front := s.waiters.Front()
+		w := front.Value.(*weightedFairSemaphoreWaiter)
+		if w.n > ss.available {
+			// Don't let other classes overtake the caller,
+			// as that may starve callers requesting
+			// multiple slots.
+			return
+		}
+		ss.available -= w.n
+		s.waiters.Remove(front)
+		s.queuedAcquisitions.Dec()
+		s.pass += s.stride
+		close(w.ready)
+	}
+}
+
+func (s *weightedFairSemaphore) Acquire(ctx context.Context, n int64) error {
+	ss := s.shared
+	ss.lock.Lock()
+	if ss.available >= n && !ss.hasWaitersLocked() {
+		ss.available -= n
+		ss.lock.Unlock()
+		return nil
+	}
+
+	if s.waiters.Len() == 0 {
+		// The class was idle. Prevent it from claiming slots
+		// for the time it was idle, by not letting its pass
+		// value lag behind that of other waiting classes.
+		if next := ss.getNextClassLocked(); next != nil && s.pass < next.pass {
+			s.pass = next.pass
+		}
+	}
+	w := &weightedFairSemaphoreWaiter{
+		n:     n,
+		ready: make(chan struct{}),
+	}
+	element := s.waiters.PushBack(w)
+	s.queuedAcquisitions.Inc()
+	ss.lock.Unlock()
+
+	select {
+	case <-ctx.Done():
+		ss.lock.Lock()
+		select {
+		case <-w.ready:
+			// Acquired the slots right after the context
+			// was cancelled. Hand them back.
+			ss.available += n
+		default:
+			s.waiters.Remove(element)
+			s.queuedAcquisitions.Dec()
+		}
+		ss.notifyWaitersLocked()
+		ss.lock.Unlock()
+		return ctx.Err()
+	case <-w.ready:
+		return nil
+	}
+}
+
+func (s *weightedFairSemaphore) TryAcquire(n int64) bool {
+	ss := s.shared
+	ss.lock.Lock()
+	defer ss.lock.Unlock()
+
+	if ss.available >= n && !ss.hasWaitersLocked() {
+		ss.available -= n
+		return true
+	}
+	return false
+}
+
+func (s *weightedFairSemaphore) Release(n int64) {
+	ss := s.shared
+	ss.lock.Lock()
+	defer ss.lock.Unlock()
+
+	ss.available += n
+	ss.notifyWaitersLocked()
+}
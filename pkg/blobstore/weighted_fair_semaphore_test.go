@@ -0,0 +1,70 @@
+package blobstore_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/buildbarn/bb-clientd/pkg/blobstore"
+	"github.com/prometheus/client_golang/prometheus"
+	prometheus_testutil "github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWeightedFairSemaphores(t *testing.T) {
+	ctx := context.Background()
+
+	queuedA := prometheus.NewGauge(prometheus.GaugeOpts{Name: "queued_a"})
+	queuedB := prometheus.NewGauge(prometheus.GaugeOpts{Name: "queued_b"})
+	semaphores := blobstore.NewWeightedFairSemaphores(1, []blobstore.WeightedFairSemaphoreClass{
+		{Weight: 3, QueuedAcquisitions: queuedA},
+		{Weight: 1, QueuedAcquisitions: queuedB},
+	})
+
+	// Acquiring the only slot should succeed immediately.
+	require.True(t, semaphores[0].TryAcquire(1))
+	require.False(t, semaphores[1].TryAcquire(1))
+
+	t.Run("ContextCancelled", func(t *testing.T) {
+		ctxCancelled, cancel := context.WithCancel(ctx)
+		cancel()
+
+		require.Equal(t, context.Canceled, semaphores[1].Acquire(ctxCancelled, 1))
+		require.Equal(t, 0.0, prometheus_testutil.ToFloat64(queuedB))
+	})
+
+	t.Run("WeightedOrder", func(t *testing.T) {
+		// Let both classes have callers waiting. Slots should
+		// be handed out in proportion to the weights of the
+		// classes, until the queue of one class is exhausted.
+		order := make(chan string, 8)
+		for i := 0; i < 4; i++ {
+			go func() {
+				require.NoError(t, semaphores[0].Acquire(ctx, 1))
+				order <- "A"
+			}()
+			go func() {
+				require.NoError(t, semaphores[1].Acquire(ctx, 1))
+				order <- "B"
+			}()
+		}
+		require.Eventually(t, func() bool {
+			return prometheus_testutil.ToFloat64(queuedA) == 4.0 && prometheus_testutil.ToFloat64(queuedB) == 4.0
+		}, 10*time.Second, time.Millisecond)
+
+		var observed []string
+		for i := 0; i < 8; i++ {
+			semaphores[0].Release(1)
+			observed = append(observed, <-order)
+		}
+		require.Equal(t, []string{"A", "B", "A", "A", "A", "B", "B", "B"}, observed)
+		require.Equal(t, 0.0, prometheus_testutil.ToFloat64(queuedA))
+		require.Equal(t, 0.0, prometheus_testutil.ToFloat64(queuedB))
+
+		// Once all slots are released, they may be acquired
+		// immediately again.
+		semaphores[1].Release(1)
+		require.True(t, semaphores[1].TryAcquire(1))
+		semaphores[1].Release(1)
+	})
+}
@@ -0,0 +1,62 @@
+package cas
+
+import (
+	"context"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	cd_blobstore "github.com/buildbarn/bb-clientd/pkg/blobstore"
+	"github.com/buildbarn/bb-remote-execution/pkg/cas"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/util"
+)
+
+type concurrencyLimitingDirectoryFetcher struct {
+	base      cas.DirectoryFetcher
+	semaphore cd_blobstore.Semaphore
+}
+
+// NewConcurrencyLimitingDirectoryFetcher creates a decorator for
+// DirectoryFetcher that limits the number of calls that are in flight.
+// A slot in the semaphore is acquired before the call is forwarded,
+// and is released once the call returns.
+//
+// In combination with NewWeightedFairSemaphores(), this permits
+// fetches of directories and fetches of file contents to share the
+// same limit, without one being able to starve the other.
+func NewConcurrencyLimitingDirectoryFetcher(base cas.DirectoryFetcher, semaphore cd_blobstore.Semaphore) cas.DirectoryFetcher {
+	return &concurrencyLimitingDirectoryFetcher{
+		base:      base,
+		semaphore: semaphore,
+	}
+}
+
+func (df *concurrencyLimitingDirectoryFetcher) acquire(ctx context.Context) error {
+	if err := df.semaphore.Acquire(ctx, 1); err != nil {
+		return util.StatusWrap(util.StatusFromContext(ctx), "Failed to wait for other fetches to complete")
+	}
+	return nil
+}
+
+func (df *concurrencyLimitingDirectoryFetcher) GetDirectory(ctx context.Context, directoryDigest digest.Digest) (*remoteexecution.Directory, error) {
+	if err := df.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer df.semaphore.Release(1)
+	return df.base.GetDirectory(ctx, directoryDigest)
+}
+
+func (df *concurrencyLimitingDirectoryFetcher) GetTreeRootDirectory(ctx context.Context, treeDigest digest.Digest) (*remoteexecution.Directory, error) {
+	if err := df.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer df.semaphore.Release(1)
+	return df.base.GetTreeRootDirectory(ctx, treeDigest)
+}
+
+func (df *concurrencyLimitingDirectoryFetcher) GetTreeChildDirectory(ctx context.Context, treeDigest, childDigest digest.Digest) (*remoteexecution.Directory, error) {
+	if err := df.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer df.semaphore.Release(1)
+	return df.base.GetTreeChildDirectory(ctx, treeDigest, childDigest)
+}
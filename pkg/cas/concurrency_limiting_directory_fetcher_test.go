@@ -0,0 +1,53 @@
+package cas_test
+
+import (
+	"context"
+	"testing"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-clientd/internal/mock"
+	"github.com/buildbarn/bb-clientd/pkg/cas"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"golang.org/x/sync/semaphore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestConcurrencyLimitingDirectoryFetcher(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	baseDirectoryFetcher := mock.NewMockDirectoryFetcher(ctrl)
+	s := semaphore.NewWeighted(1)
+	directoryFetcher := cas.NewConcurrencyLimitingDirectoryFetcher(baseDirectoryFetcher, s)
+
+	treeDigest := digest.MustNewDigest("instance_name", remoteexecution.DigestFunction_MD5, "8b1a9953c4611296a827abf8c47804d7", 5)
+
+	t.Run("ContextCancelled", func(t *testing.T) {
+		// Calls that are blocked on other fetches should
+		// respect cancellation of their context.
+		require.True(t, s.TryAcquire(1))
+		defer s.Release(1)
+		ctxCancelled, cancel := context.WithCancel(ctx)
+		cancel()
+
+		_, err := directoryFetcher.GetTreeRootDirectory(ctxCancelled, treeDigest)
+		testutil.RequireEqualStatus(t, status.Error(codes.Canceled, "Failed to wait for other fetches to complete: context canceled"), err)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		// The slot should be released once the call returns.
+		baseDirectoryFetcher.EXPECT().GetTreeRootDirectory(ctx, treeDigest).
+			Return(&remoteexecution.Directory{}, nil).
+			Times(2)
+
+		for i := 0; i < 2; i++ {
+			directory, err := directoryFetcher.GetTreeRootDirectory(ctx, treeDigest)
+			require.NoError(t, err)
+			testutil.RequireEqualProto(t, &remoteexecution.Directory{}, directory)
+		}
+	})
+}
@@ -3,15 +3,25 @@ package cas
 import (
 	"context"
 	"fmt"
+	"sync/atomic"
 
 	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
 	"github.com/buildbarn/bb-remote-execution/pkg/cas"
 	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type treeDirectoryWalker struct {
 	fetcher    cas.DirectoryFetcher
 	treeDigest digest.Digest
+
+	// Set when the Tree object was found to be absent from the
+	// CAS. This is shared by all directories in the tree, so that
+	// the entire subtree is marked as invalid.
+	missing atomic.Bool
 }
 
 // NewTreeDirectoryWalker creates a DirectoryWalker that assumes that
@@ -38,12 +48,29 @@ func (dw *treeDirectoryWalker) GetContainingDigest() digest.Digest {
 	return dw.treeDigest
 }
 
+func (dw *treeDirectoryWalker) getDirectory(getDirectory func() (*remoteexecution.Directory, error)) (*remoteexecution.Directory, error) {
+	if dw.missing.Load() {
+		return nil, status.Error(codes.NotFound, "Tree was previously found to be absent from the Content Addressable Storage, meaning this directory needs to be rebuilt")
+	}
+	directory, err := getDirectory()
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			dw.missing.Store(true)
+			return nil, util.StatusWrap(err, "Tree is absent from the Content Addressable Storage, meaning this directory needs to be rebuilt")
+		}
+		return nil, err
+	}
+	return directory, nil
+}
+
 type treeRootDirectoryWalker struct {
 	treeDirectoryWalker
 }
 
 func (dw *treeRootDirectoryWalker) GetDirectory(ctx context.Context) (*remoteexecution.Directory, error) {
-	return dw.fetcher.GetTreeRootDirectory(ctx, dw.treeDigest)
+	return dw.getDirectory(func() (*remoteexecution.Directory, error) {
+		return dw.fetcher.GetTreeRootDirectory(ctx, dw.treeDigest)
+	})
 }
 
 func (dw *treeRootDirectoryWalker) GetDescription() string {
@@ -56,7 +83,9 @@ type treeChildDirectoryWalker struct {
 }
 
 func (dw *treeChildDirectoryWalker) GetDirectory(ctx context.Context) (*remoteexecution.Directory, error) {
-	return dw.fetcher.GetTreeChildDirectory(ctx, dw.treeDigest, dw.childDigest)
+	return dw.getDirectory(func() (*remoteexecution.Directory, error) {
+		return dw.fetcher.GetTreeChildDirectory(ctx, dw.treeDigest, dw.childDigest)
+	})
 }
 
 func (dw *treeChildDirectoryWalker) GetDescription() string {
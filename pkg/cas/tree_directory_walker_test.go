@@ -114,3 +114,28 @@ func TestTreeDirectoryWalker(t *testing.T) {
 			childDirectoryWalker.GetContainingDigest())
 	})
 }
+
+func TestTreeDirectoryWalkerNotFound(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	directoryFetcher := mock.NewMockDirectoryFetcher(ctrl)
+	treeDigest := digest.MustNewDigest("example", remoteexecution.DigestFunction_MD5, "6884a9e20905b512d1122a2b1ad8ba16", 123)
+	rootDirectoryWalker := cas.NewTreeDirectoryWalker(directoryFetcher, treeDigest)
+	childDigest := digest.MustNewDigest("example", remoteexecution.DigestFunction_MD5, "4df5f448a5e6b3c41e6aae7a8a9832aa", 456)
+	childDirectoryWalker := rootDirectoryWalker.GetChild(childDigest)
+
+	// If the Tree object has disappeared from the CAS, the error
+	// should clearly indicate that the directory needs to be
+	// rebuilt.
+	directoryFetcher.EXPECT().GetTreeChildDirectory(ctx, treeDigest, childDigest).
+		Return(nil, status.Error(codes.NotFound, "Object not found"))
+	_, err := childDirectoryWalker.GetDirectory(ctx)
+	testutil.RequireEqualStatus(t, status.Error(codes.NotFound, "Tree is absent from the Content Addressable Storage, meaning this directory needs to be rebuilt: Object not found"), err)
+
+	// All other directories in the tree should be marked as invalid
+	// as well. There is no need to contact the CAS again.
+	_, err = rootDirectoryWalker.GetDirectory(ctx)
+	testutil.RequireEqualStatus(t, status.Error(codes.NotFound, "Tree was previously found to be absent from the Content Addressable Storage, meaning this directory needs to be rebuilt"), err)
+	_, err = childDirectoryWalker.GetDirectory(ctx)
+	testutil.RequireEqualStatus(t, status.Error(codes.NotFound, "Tree was previously found to be absent from the Content Addressable Storage, meaning this directory needs to be rebuilt"), err)
+}
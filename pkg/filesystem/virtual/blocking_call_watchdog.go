@@ -0,0 +1,72 @@
+package virtual
+
+import (
+	"runtime"
+	"time"
+
+	"github.com/buildbarn/bb-storage/pkg/clock"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// BlockingCallWatchdog reports calls that take longer than a given
+// threshold to complete, together with a dump of the stacks of all
+// goroutines. It is used to diagnose deadlocks caused by calls that
+// may block on the kernel, such as NotifyRemoval() and
+// RemoveAllChildren(), being made while locks are held that the kernel
+// needs to acquire as well.
+//
+// Calls that are watched are expected to be infrequent. For each call,
+// a timer is started, and a goroutine waits for it to either expire or
+// be stopped. No stack dumps are generated unless the threshold is
+// exceeded.
+type BlockingCallWatchdog struct {
+	clock       clock.Clock
+	threshold   time.Duration
+	errorLogger util.ErrorLogger
+}
+
+// NewBlockingCallWatchdog creates a BlockingCallWatchdog that reports
+// calls that block for longer than the provided threshold through an
+// ErrorLogger.
+func NewBlockingCallWatchdog(clock clock.Clock, threshold time.Duration, errorLogger util.ErrorLogger) *BlockingCallWatchdog {
+	return &BlockingCallWatchdog{
+		clock:       clock,
+		threshold:   threshold,
+		errorLogger: errorLogger,
+	}
+}
+
+// Run a function, reporting it if it fails to complete within the
+// threshold. The name of the call is used to identify it in the
+// report.
+func (w *BlockingCallWatchdog) Run(name string, f func()) {
+	timer, t := w.clock.NewTimer(w.threshold)
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-t:
+			w.errorLogger.Log(status.Errorf(codes.DeadlineExceeded, "%s has been blocked for more than %s, which may indicate a deadlock. Stacks of all goroutines:\n%s", name, w.threshold, getAllGoroutineStacks()))
+		case <-done:
+		}
+	}()
+
+	f()
+	timer.Stop()
+	close(done)
+}
+
+// getAllGoroutineStacks returns the stacks of all goroutines,
+// growing the buffer until they fit.
+func getAllGoroutineStacks() []byte {
+	buf := make([]byte, 64*1024)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			return buf[:n]
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+}
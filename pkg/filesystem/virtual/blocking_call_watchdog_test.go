@@ -0,0 +1,56 @@
+package virtual_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/buildbarn/bb-clientd/internal/mock"
+	"github.com/buildbarn/bb-clientd/pkg/filesystem/virtual"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestBlockingCallWatchdog(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	clock := mock.NewMockClock(ctrl)
+	errorLogger := mock.NewMockErrorLogger(ctrl)
+	watchdog := virtual.NewBlockingCallWatchdog(clock, time.Minute, errorLogger)
+
+	t.Run("NotBlocked", func(t *testing.T) {
+		// Calls that complete in time should not be reported.
+		timer := mock.NewMockTimer(ctrl)
+		clock.EXPECT().NewTimer(time.Minute).Return(timer, nil)
+		timer.EXPECT().Stop().Return(true)
+
+		called := false
+		watchdog.Run("NotifyRemoval()", func() { called = true })
+		require.True(t, called)
+	})
+
+	t.Run("Blocked", func(t *testing.T) {
+		// Calls that exceed the threshold should be reported,
+		// including the stacks of all goroutines.
+		timer := mock.NewMockTimer(ctrl)
+		timerChannel := make(chan time.Time, 1)
+		clock.EXPECT().NewTimer(time.Minute).Return(timer, timerChannel)
+		logged := make(chan struct{})
+		errorLogger.EXPECT().Log(gomock.Any()).Do(func(err error) {
+			s := status.Convert(err)
+			require.Equal(t, codes.DeadlineExceeded, s.Code())
+			require.True(t, strings.HasPrefix(s.Message(), "NotifyRemoval() has been blocked for more than 1m0s, which may indicate a deadlock. Stacks of all goroutines:\ngoroutine "))
+			require.Contains(t, s.Message(), "TestBlockingCallWatchdog")
+			close(logged)
+		})
+		timer.EXPECT().Stop().Return(false)
+
+		watchdog.Run("NotifyRemoval()", func() {
+			timerChannel <- time.Unix(1000, 0)
+			<-logged
+		})
+	})
+}
@@ -0,0 +1,53 @@
+package virtual
+
+import (
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/virtual"
+	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+)
+
+type blockingCallWatchingHandleAllocator struct {
+	base     virtual.StatefulHandleAllocator
+	watchdog *BlockingCallWatchdog
+}
+
+// NewBlockingCallWatchingHandleAllocator creates a decorator for
+// StatefulHandleAllocator that lets a BlockingCallWatchdog monitor
+// calls to NotifyRemoval() against directory handles. These calls
+// invalidate directory entries in the kernel, meaning they deadlock if
+// the kernel is waiting for a lock held by the caller.
+func NewBlockingCallWatchingHandleAllocator(base virtual.StatefulHandleAllocator, watchdog *BlockingCallWatchdog) virtual.StatefulHandleAllocator {
+	return &blockingCallWatchingHandleAllocator{
+		base:     base,
+		watchdog: watchdog,
+	}
+}
+
+func (ha *blockingCallWatchingHandleAllocator) New() virtual.StatefulHandleAllocation {
+	return &blockingCallWatchingHandleAllocation{
+		StatefulHandleAllocation: ha.base.New(),
+		watchdog:                 ha.watchdog,
+	}
+}
+
+type blockingCallWatchingHandleAllocation struct {
+	virtual.StatefulHandleAllocation
+	watchdog *BlockingCallWatchdog
+}
+
+func (hn *blockingCallWatchingHandleAllocation) AsStatefulDirectory(directory virtual.Directory) virtual.StatefulDirectoryHandle {
+	return &blockingCallWatchingDirectoryHandle{
+		StatefulDirectoryHandle: hn.StatefulHandleAllocation.AsStatefulDirectory(directory),
+		watchdog:                hn.watchdog,
+	}
+}
+
+type blockingCallWatchingDirectoryHandle struct {
+	virtual.StatefulDirectoryHandle
+	watchdog *BlockingCallWatchdog
+}
+
+func (dh *blockingCallWatchingDirectoryHandle) NotifyRemoval(name path.Component) {
+	dh.watchdog.Run("NotifyRemoval() of directory entry "+name.String(), func() {
+		dh.StatefulDirectoryHandle.NotifyRemoval(name)
+	})
+}
@@ -0,0 +1,46 @@
+package virtual
+
+import (
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/virtual"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+	"github.com/buildbarn/bb-storage/pkg/util"
+)
+
+type blockingCallWatchingOutputPathFactory struct {
+	OutputPathFactory
+	watchdog *BlockingCallWatchdog
+}
+
+// NewBlockingCallWatchingOutputPathFactory creates a decorator for
+// OutputPathFactory that lets a BlockingCallWatchdog monitor calls to
+// RemoveAllChildren() against output paths. These calls invalidate
+// directory entries in the kernel, meaning they deadlock if the kernel
+// is waiting for a lock held by the caller.
+func NewBlockingCallWatchingOutputPathFactory(base OutputPathFactory, watchdog *BlockingCallWatchdog) OutputPathFactory {
+	return &blockingCallWatchingOutputPathFactory{
+		OutputPathFactory: base,
+		watchdog:          watchdog,
+	}
+}
+
+func (opf *blockingCallWatchingOutputPathFactory) StartInitialBuild(outputBaseID path.Component, casFileFactory virtual.CASFileFactory, digestFunction digest.Function, errorLogger util.ErrorLogger) OutputPath {
+	return &blockingCallWatchingOutputPath{
+		OutputPath:   opf.OutputPathFactory.StartInitialBuild(outputBaseID, casFileFactory, digestFunction, errorLogger),
+		watchdog:     opf.watchdog,
+		outputBaseID: outputBaseID,
+	}
+}
+
+type blockingCallWatchingOutputPath struct {
+	OutputPath
+	watchdog     *BlockingCallWatchdog
+	outputBaseID path.Component
+}
+
+func (op *blockingCallWatchingOutputPath) RemoveAllChildren(forbidNewChildren bool) (err error) {
+	op.watchdog.Run("RemoveAllChildren() on output path "+op.outputBaseID.String(), func() {
+		err = op.OutputPath.RemoveAllChildren(forbidNewChildren)
+	})
+	return
+}
@@ -0,0 +1,142 @@
+package virtual
+
+import (
+	"context"
+	"sort"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-clientd/pkg/proto/outputpaths"
+	re_cas "github.com/buildbarn/bb-remote-execution/pkg/cas"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// buildDiffer is used by DiffBuilds() to compare the contents of two
+// Tree objects. Directories that have the same digest in both Trees
+// are skipped, meaning that only the parts of the Trees that differ
+// need to be traversed.
+type buildDiffer struct {
+	context            context.Context
+	directoryFetcher   re_cas.DirectoryFetcher
+	oldTreeDigest      digest.Digest
+	newTreeDigest      digest.Digest
+	maximumDifferences int
+
+	differences []*outputpaths.PathDifference
+	truncated   bool
+}
+
+func newBuildDiffer(ctx context.Context, directoryFetcher re_cas.DirectoryFetcher, oldTreeDigest, newTreeDigest digest.Digest, maximumDifferences int) *buildDiffer {
+	return &buildDiffer{
+		context:            ctx,
+		directoryFetcher:   directoryFetcher,
+		oldTreeDigest:      oldTreeDigest,
+		newTreeDigest:      newTreeDigest,
+		maximumDifferences: maximumDifferences,
+	}
+}
+
+// getDiffedNodes returns all children of a directory, keyed by name.
+func getDiffedNodes(directory *remoteexecution.Directory) map[string]*outputpaths.DiffedNode {
+	nodes := make(map[string]*outputpaths.DiffedNode, len(directory.Files)+len(directory.Directories)+len(directory.Symlinks))
+	for _, entry := range directory.Files {
+		nodes[entry.Name] = &outputpaths.DiffedNode{
+			NodeType: &outputpaths.DiffedNode_File{File: entry},
+		}
+	}
+	for _, entry := range directory.Directories {
+		nodes[entry.Name] = &outputpaths.DiffedNode{
+			NodeType: &outputpaths.DiffedNode_Directory{Directory: entry},
+		}
+	}
+	for _, entry := range directory.Symlinks {
+		nodes[entry.Name] = &outputpaths.DiffedNode{
+			NodeType: &outputpaths.DiffedNode_Symlink{Symlink: entry},
+		}
+	}
+	return nodes
+}
+
+// fetchChildDirectory loads a child directory from one of the Trees
+// being compared.
+func (bd *buildDiffer) fetchChildDirectory(treeDigest digest.Digest, entry *remoteexecution.DirectoryNode, dPath *path.Trace) (*remoteexecution.Directory, error) {
+	childDigest, err := treeDigest.GetDigestFunction().NewDigestFromProto(entry.Digest)
+	if err != nil {
+		return nil, util.StatusWrapf(err, "Invalid digest for directory %#v", dPath.String())
+	}
+	directory, err := bd.directoryFetcher.GetTreeChildDirectory(bd.context, treeDigest, childDigest)
+	if err != nil {
+		return nil, util.StatusWrapf(err, "Failed to fetch directory %#v", dPath.String())
+	}
+	return directory, nil
+}
+
+// diffDirectories compares the contents of a directory in both Trees.
+// Children that are directories in both Trees are compared
+// recursively. All other children that differ are reported as a
+// whole, meaning that the contents of added and removed directories
+// are not reported individually.
+func (bd *buildDiffer) diffDirectories(oldDirectory, newDirectory *remoteexecution.Directory, dPath *path.Trace) error {
+	if err := bd.context.Err(); err != nil {
+		return util.StatusFromContext(bd.context)
+	}
+
+	oldNodes := getDiffedNodes(oldDirectory)
+	newNodes := getDiffedNodes(newDirectory)
+	names := make([]string, 0, len(oldNodes)+len(newNodes))
+	for name := range oldNodes {
+		names = append(names, name)
+	}
+	for name := range newNodes {
+		if _, ok := oldNodes[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, nameStr := range names {
+		oldNode, newNode := oldNodes[nameStr], newNodes[nameStr]
+		if proto.Equal(oldNode, newNode) {
+			continue
+		}
+		name, ok := path.NewComponent(nameStr)
+		if !ok {
+			return status.Errorf(codes.InvalidArgument, "Directory %#v contains a child with invalid name %#v", dPath.String(), nameStr)
+		}
+		childPath := dPath.Append(name)
+
+		oldChild, newChild := oldNode.GetDirectory(), newNode.GetDirectory()
+		if oldChild != nil && newChild != nil {
+			oldChildDirectory, err := bd.fetchChildDirectory(bd.oldTreeDigest, oldChild, childPath)
+			if err != nil {
+				return err
+			}
+			newChildDirectory, err := bd.fetchChildDirectory(bd.newTreeDigest, newChild, childPath)
+			if err != nil {
+				return err
+			}
+			if err := bd.diffDirectories(oldChildDirectory, newChildDirectory, childPath); err != nil {
+				return err
+			}
+		} else {
+			if len(bd.differences) >= bd.maximumDifferences {
+				bd.truncated = true
+				return nil
+			}
+			bd.differences = append(bd.differences, &outputpaths.PathDifference{
+				Path:    childPath.String(),
+				OldNode: oldNode,
+				NewNode: newNode,
+			})
+		}
+		if bd.truncated {
+			return nil
+		}
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+package virtual
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/buildbarn/bb-clientd/pkg/proto/outputpaths"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// retainedBuildEvents is the number of recent events that
+// WatchBuilds() is able to replay to clients that resume streaming.
+const retainedBuildEvents = 1000
+
+// retainedEntryRemovalEvents is the number of recent ENTRY_REMOVED
+// events that WatchBuilds() is able to replay. These are retained
+// separately from other events, so that a single build removing many
+// entries does not cause state transitions of builds to be discarded.
+const retainedEntryRemovalEvents = 1000
+
+// buildEventLog keeps track of recent state transitions of builds and
+// output bases, so that they can be streamed to clients through
+// WatchBuilds().
+//
+// The log has its own lock, so that it can be appended to while
+// holding the lock of RemoteOutputServiceDirectory. Watchers only
+// acquire the lock of the log.
+type buildEventLog struct {
+	lock                sync.Mutex
+	events              []*outputpaths.BuildEvent
+	entryRemovalEvents  []*outputpaths.BuildEvent
+	lastCursor          uint64
+	lastDiscardedCursor uint64
+	changed             chan struct{}
+}
+
+func newBuildEventLog() *buildEventLog {
+	return &buildEventLog{
+		changed: make(chan struct{}),
+	}
+}
+
+// append an event to the log, waking up all watchers.
+func (l *buildEventLog) append(eventType outputpaths.BuildEvent_Type, outputBaseID outputBasePath, buildID string, t time.Time) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.events = append(l.events, l.newEventLocked(&outputpaths.BuildEvent{
+		Type:         eventType,
+		OutputBaseId: outputBaseID.String(),
+		BuildId:      buildID,
+		Timestamp:    timestamppb.New(t),
+	}))
+	if len(l.events) > retainedBuildEvents {
+		l.lastDiscardedCursor = l.events[len(l.events)-retainedBuildEvents-1].Cursor
+		l.events = l.events[len(l.events)-retainedBuildEvents:]
+	}
+}
+
+// appendEntryRemoval appends an event to the log, indicating that an
+// entry was removed from an output path while checking its contents
+// for existence. Unlike other events, these may be discarded without
+// causing clients that resume streaming to fail.
+func (l *buildEventLog) appendEntryRemoval(outputBaseID outputBasePath, buildID string, t time.Time, entryRemoval *outputpaths.BuildEvent_EntryRemoval) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.entryRemovalEvents = append(l.entryRemovalEvents, l.newEventLocked(&outputpaths.BuildEvent{
+		Type:         outputpaths.BuildEvent_ENTRY_REMOVED,
+		OutputBaseId: outputBaseID.String(),
+		BuildId:      buildID,
+		Timestamp:    timestamppb.New(t),
+		EntryRemoval: entryRemoval,
+	}))
+	if len(l.entryRemovalEvents) > retainedEntryRemovalEvents {
+		l.entryRemovalEvents = l.entryRemovalEvents[len(l.entryRemovalEvents)-retainedEntryRemovalEvents:]
+	}
+}
+
+// newEventLocked assigns a cursor to a new event, and wakes up all
+// watchers.
+func (l *buildEventLog) newEventLocked(event *outputpaths.BuildEvent) *outputpaths.BuildEvent {
+	l.lastCursor++
+	event.Cursor = l.lastCursor
+	close(l.changed)
+	l.changed = make(chan struct{})
+	return event
+}
+
+// getLastCursor returns the cursor of the most recently emitted
+// event, or zero if no events have been emitted.
+func (l *buildEventLog) getLastCursor() uint64 {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return l.lastCursor
+}
+
+// getEventsAfter returns all events whose cursor is greater than the
+// one provided, in order of increasing cursor. In addition to that, it
+// returns a channel that is closed when more events are emitted.
+func (l *buildEventLog) getEventsAfter(cursor uint64) ([]*outputpaths.BuildEvent, <-chan struct{}, error) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if cursor > l.lastCursor {
+		return nil, nil, status.Errorf(codes.OutOfRange, "Cursor %d is greater than the cursor of the most recent event, %d", cursor, l.lastCursor)
+	}
+	if cursor < l.lastDiscardedCursor {
+		return nil, nil, status.Errorf(codes.OutOfRange, "Events following cursor %d are no longer retained, as events up to cursor %d have been discarded", cursor, l.lastDiscardedCursor)
+	}
+
+	// Merge both sets of events, which are both sorted by cursor.
+	events := getBuildEventsAfter(l.events, cursor)
+	entryRemovalEvents := getBuildEventsAfter(l.entryRemovalEvents, cursor)
+	merged := make([]*outputpaths.BuildEvent, 0, len(events)+len(entryRemovalEvents))
+	for len(events) > 0 && len(entryRemovalEvents) > 0 {
+		if events[0].Cursor < entryRemovalEvents[0].Cursor {
+			merged = append(merged, events[0])
+			events = events[1:]
+		} else {
+			merged = append(merged, entryRemovalEvents[0])
+			entryRemovalEvents = entryRemovalEvents[1:]
+		}
+	}
+	merged = append(merged, events...)
+	merged = append(merged, entryRemovalEvents...)
+	return merged, l.changed, nil
+}
+
+// getBuildEventsAfter returns the suffix of a list of events sorted by
+// cursor that have a cursor greater than the one provided.
+func getBuildEventsAfter(events []*outputpaths.BuildEvent, cursor uint64) []*outputpaths.BuildEvent {
+	return events[sort.Search(len(events), func(i int) bool { return events[i].Cursor > cursor }):]
+}
+
+// WatchBuilds streams events that are emitted when builds are started,
+// finalized or aborted, when output bases are cleaned, and when entries
+// are removed from output paths while checking them for existence.
+func (d *RemoteOutputServiceDirectory) WatchBuilds(request *outputpaths.WatchBuildsRequest, server outputpaths.OutputPaths_WatchBuildsServer) error {
+	var cursor uint64
+	if start, ok := request.Start.(*outputpaths.WatchBuildsRequest_AfterCursor); ok {
+		cursor = start.AfterCursor
+	} else {
+		cursor = d.buildEvents.getLastCursor()
+	}
+
+	ctx := server.Context()
+	for {
+		events, changed, err := d.buildEvents.getEventsAfter(cursor)
+		if err != nil {
+			return err
+		}
+		if len(events) > 0 {
+			if err := server.Send(&outputpaths.WatchBuildsResponse{
+				Events: events,
+			}); err != nil {
+				return err
+			}
+			cursor = events[len(events)-1].Cursor
+		}
+
+		select {
+		case <-ctx.Done():
+			return util.StatusFromContext(ctx)
+		case <-changed:
+		}
+	}
+}
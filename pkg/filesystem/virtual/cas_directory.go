@@ -32,10 +32,11 @@ type CASDirectoryContext interface {
 type casDirectory struct {
 	virtual.ReadOnlyDirectory
 
-	directoryContext CASDirectoryContext
-	digestFunction   digest.Function
-	handleAllocator  virtual.ResolvableHandleAllocator
-	sizeBytes        uint64
+	directoryContext  CASDirectoryContext
+	digestFunction    digest.Function
+	handleAllocator   virtual.ResolvableHandleAllocator
+	sizeBytes         uint64
+	computeLinkCounts bool
 }
 
 // NewCASDirectory creates an immutable directory that is backed by a
@@ -43,12 +44,24 @@ type casDirectory struct {
 // order to load the Directory message and to instantiate inodes for any
 // of its children, calls are made into a CASDirectoryContext object.
 //
+// POSIX requires that the link count of a directory is equal to two
+// plus the number of subdirectories. Reporting this requires loading
+// the Directory message, which is why by default the link count is
+// reported as ImplicitDirectoryLinkCount, indicating that it is not
+// tracked. If computeLinkCounts is set, the Directory message is
+// loaded whenever the link count is requested. This permits tools like
+// find(1) to skip stat() calls on leaves, at the cost of loading the
+// contents of directories whose attributes are merely requested (e.g.,
+// when listing their parent directory). If the Directory message
+// cannot be loaded, ImplicitDirectoryLinkCount is reported instead.
+//
 // TODO: Reimplement this on top of cas.DirectoryWalker.
-func NewCASDirectory(directoryContext CASDirectoryContext, digestFunction digest.Function, handleAllocation virtual.ResolvableHandleAllocation, sizeBytes uint64) (virtual.Directory, virtual.HandleResolver) {
+func NewCASDirectory(directoryContext CASDirectoryContext, digestFunction digest.Function, handleAllocation virtual.ResolvableHandleAllocation, sizeBytes uint64, computeLinkCounts bool) (virtual.Directory, virtual.HandleResolver) {
 	d := &casDirectory{
-		directoryContext: directoryContext,
-		digestFunction:   digestFunction,
-		sizeBytes:        sizeBytes,
+		directoryContext:  directoryContext,
+		digestFunction:    digestFunction,
+		sizeBytes:         sizeBytes,
+		computeLinkCounts: computeLinkCounts,
 	}
 	d.handleAllocator = handleAllocation.AsResolvableAllocator(d.resolveHandle)
 	return d.createSelf(), d.resolveHandle
@@ -89,14 +102,23 @@ func (d *casDirectory) resolveHandle(r io.ByteReader) (virtual.DirectoryChild, v
 
 func (d *casDirectory) VirtualGetAttributes(ctx context.Context, requested virtual.AttributesMask, attributes *virtual.Attributes) {
 	attributes.SetChangeID(0)
-	// This should be 2 + nDirectories, but that requires us to load
-	// the directory. This is highly inefficient and error prone.
-	attributes.SetLinkCount(virtual.ImplicitDirectoryLinkCount)
+	attributes.SetLinkCount(d.getLinkCount(requested))
 	attributes.SetFileType(filesystem.FileTypeDirectory)
 	attributes.SetPermissions(virtual.PermissionsRead | virtual.PermissionsExecute)
 	attributes.SetSizeBytes(d.sizeBytes)
 }
 
+func (d *casDirectory) getLinkCount(requested virtual.AttributesMask) uint32 {
+	// This should be 2 + nDirectories, but that requires us to load
+	// the directory. Only do so if explicitly enabled.
+	if d.computeLinkCounts && requested&virtual.AttributesMaskLinkCount != 0 {
+		if directory, s := d.directoryContext.GetDirectoryContents(); s == virtual.StatusOK {
+			return virtual.EmptyDirectoryLinkCount + uint32(len(directory.Directories))
+		}
+	}
+	return virtual.ImplicitDirectoryLinkCount
+}
+
 func (d *casDirectory) VirtualLookup(ctx context.Context, name path.Component, requested virtual.AttributesMask, out *virtual.Attributes) (virtual.DirectoryChild, virtual.Status) {
 	directory, s := d.directoryContext.GetDirectoryContents()
 	if s != virtual.StatusOK {
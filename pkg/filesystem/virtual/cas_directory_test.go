@@ -61,7 +61,8 @@ func TestCASDirectoryVirtualLookup(t *testing.T) {
 		directoryContext,
 		digest.MustNewFunction("example", remoteexecution.DigestFunction_SHA256),
 		rootHandleAllocation,
-		/* sizeBytes = */ 42)
+		/* sizeBytes = */ 42,
+		/* computeLinkCounts = */ false)
 
 	t.Run("IOError", func(t *testing.T) {
 		// I/O error while loading directory contents. There is
@@ -253,7 +254,8 @@ func TestCASDirectoryVirtualReadDir(t *testing.T) {
 		directoryContext,
 		digest.MustNewFunction("example", remoteexecution.DigestFunction_SHA256),
 		rootHandleAllocation,
-		/* sizeBytes = */ 42)
+		/* sizeBytes = */ 42,
+		/* computeLinkCounts = */ false)
 
 	t.Run("IOError", func(t *testing.T) {
 		// I/O error while loading directory contents. There is
@@ -518,6 +520,84 @@ func TestCASDirectoryVirtualReadDir(t *testing.T) {
 	})
 }
 
+func TestCASDirectoryVirtualGetAttributes(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	directoryContext := mock.NewMockCASDirectoryContext(ctrl)
+	rootHandleAllocation := mock.NewMockResolvableHandleAllocation(ctrl)
+	handleAllocator := mock.NewMockResolvableHandleAllocator(ctrl)
+	rootHandleAllocation.EXPECT().AsResolvableAllocator(gomock.Any()).Return(handleAllocator)
+	casDirectoryExpectLookupSelf(t, ctrl, handleAllocator)
+	d, _ := cd_vfs.NewCASDirectory(
+		directoryContext,
+		digest.MustNewFunction("example", remoteexecution.DigestFunction_SHA256),
+		rootHandleAllocation,
+		/* sizeBytes = */ 42,
+		/* computeLinkCounts = */ true)
+
+	t.Run("LinkCountNotRequested", func(t *testing.T) {
+		// The directory contents should not be loaded if the
+		// link count is not requested.
+		var out re_vfs.Attributes
+		d.VirtualGetAttributes(ctx, re_vfs.AttributesMaskSizeBytes, &out)
+		require.Equal(
+			t,
+			(&re_vfs.Attributes{}).
+				SetChangeID(0).
+				SetFileType(filesystem.FileTypeDirectory).
+				SetLinkCount(re_vfs.ImplicitDirectoryLinkCount).
+				SetPermissions(re_vfs.PermissionsRead|re_vfs.PermissionsExecute).
+				SetSizeBytes(42),
+			&out)
+	})
+
+	t.Run("IOError", func(t *testing.T) {
+		// When the directory contents cannot be loaded, fall
+		// back to reporting that the link count is not tracked.
+		directoryContext.EXPECT().GetDirectoryContents().Return(nil, re_vfs.StatusErrIO)
+
+		var out re_vfs.Attributes
+		d.VirtualGetAttributes(ctx, re_vfs.AttributesMaskLinkCount, &out)
+		require.Equal(t, re_vfs.ImplicitDirectoryLinkCount, out.GetLinkCount())
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		// The link count should be two plus the number of
+		// subdirectories.
+		directoryContext.EXPECT().GetDirectoryContents().Return(&remoteexecution.Directory{
+			Directories: []*remoteexecution.DirectoryNode{
+				{
+					Name: "directory1",
+					Digest: &remoteexecution.Digest{
+						Hash:      "47473788bad5e9991fcd8e8a2b6012745031089ebe6cc7342f78bf92570e4f52",
+						SizeBytes: 42,
+					},
+				},
+				{
+					Name: "directory2",
+					Digest: &remoteexecution.Digest{
+						Hash:      "47473788bad5e9991fcd8e8a2b6012745031089ebe6cc7342f78bf92570e4f52",
+						SizeBytes: 42,
+					},
+				},
+			},
+			Files: []*remoteexecution.FileNode{
+				{
+					Name: "file",
+					Digest: &remoteexecution.Digest{
+						Hash:      "059458af6543753150ceb7bcd4cc215e8aaabd61934ff6c67acdd9e7fb4cc96d",
+						SizeBytes: 34,
+					},
+				},
+			},
+		}, re_vfs.StatusOK)
+
+		var out re_vfs.Attributes
+		d.VirtualGetAttributes(ctx, re_vfs.AttributesMaskLinkCount, &out)
+		require.Equal(t, uint32(4), out.GetLinkCount())
+	})
+}
+
 func TestCASDirectoryHandleResolver(t *testing.T) {
 	ctrl, ctx := gomock.WithContext(context.Background(), t)
 
@@ -530,7 +610,8 @@ func TestCASDirectoryHandleResolver(t *testing.T) {
 		directoryContext,
 		digest.MustNewFunction("example", remoteexecution.DigestFunction_SHA256),
 		rootHandleAllocation,
-		/* sizeBytes = */ 42)
+		/* sizeBytes = */ 42,
+		/* computeLinkCounts = */ false)
 
 	t.Run("EmptyIdentifier", func(t *testing.T) {
 		// A variable length encoded integer should be provided
@@ -14,22 +14,24 @@ import (
 type decomposedCASDirectoryFactory struct {
 	re_vfs.CASFileFactory
 
-	context          context.Context
-	directoryFetcher re_cas.DirectoryFetcher
-	handleAllocator  *re_vfs.ResolvableDigestHandleAllocator
-	errorLogger      util.ErrorLogger
+	context           context.Context
+	directoryFetcher  re_cas.DirectoryFetcher
+	handleAllocator   *re_vfs.ResolvableDigestHandleAllocator
+	computeLinkCounts bool
+	errorLogger       util.ErrorLogger
 }
 
 // NewDecomposedCASDirectoryFactory creates a CASDirectoryFactory that
 // is capable of creating directories that are backed by individual REv2
 // Directory messages that are stored in a Content Addressable Storage
 // (CAS).
-func NewDecomposedCASDirectoryFactory(ctx context.Context, casFileFactory re_vfs.CASFileFactory, directoryFetcher re_cas.DirectoryFetcher, handleAllocation re_vfs.StatelessHandleAllocation, errorLogger util.ErrorLogger) CASDirectoryFactory {
+func NewDecomposedCASDirectoryFactory(ctx context.Context, casFileFactory re_vfs.CASFileFactory, directoryFetcher re_cas.DirectoryFetcher, handleAllocation re_vfs.StatelessHandleAllocation, computeLinkCounts bool, errorLogger util.ErrorLogger) CASDirectoryFactory {
 	cdf := &decomposedCASDirectoryFactory{
-		CASFileFactory:   casFileFactory,
-		context:          ctx,
-		directoryFetcher: directoryFetcher,
-		errorLogger:      errorLogger,
+		CASFileFactory:    casFileFactory,
+		context:           ctx,
+		directoryFetcher:  directoryFetcher,
+		computeLinkCounts: computeLinkCounts,
+		errorLogger:       errorLogger,
 	}
 	cdf.handleAllocator = re_vfs.NewResolvableDigestHandleAllocator(handleAllocation, cdf.resolve)
 	return cdf
@@ -50,7 +52,8 @@ func (cdf *decomposedCASDirectoryFactory) createDirectory(blobDigest digest.Dige
 		},
 		blobDigest.GetDigestFunction(),
 		cdf.handleAllocator.New(blobDigest),
-		uint64(blobDigest.GetSizeBytes()))
+		uint64(blobDigest.GetSizeBytes()),
+		cdf.computeLinkCounts)
 }
 
 func (cdf *decomposedCASDirectoryFactory) resolve(blobDigest digest.Digest, r io.ByteReader) (re_vfs.DirectoryChild, re_vfs.Status) {
@@ -76,6 +76,7 @@ func TestDecomposedCASDirectoryFactoryLookupDirectory(t *testing.T) {
 		casFileFactory,
 		directoryFetcher,
 		rootHandleAllocation,
+		/* computeLinkCounts = */ false,
 		errorLogger)
 
 	directoryDigest := digest.MustNewDigest("hello", remoteexecution.DigestFunction_SHA256, "e0f28d311a9b2deff103e32f6105b2b29d636c287797ca72077a648cd736cd36", 123)
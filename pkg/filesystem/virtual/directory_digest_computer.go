@@ -0,0 +1,162 @@
+package virtual
+
+import (
+	"context"
+	"syscall"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/virtual"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/remoteoutputservice"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// directoryDigestComputer is used by ExtendedBatchStat() to compute the
+// digests of REv2 Directory messages corresponding to directories in an
+// output path. Unlike treeExporter, it does not upload any data to the
+// Content Addressable Storage. Digests of files are obtained in the
+// same way as BatchStat() does, using the digest function of the build.
+//
+// Digests of directories are cached, so that directories that are
+// contained in multiple paths provided to ExtendedBatchStat() are only
+// processed once. As the cache is keyed by directory, a single instance
+// should only be used for the duration of a single request.
+type directoryDigestComputer struct {
+	context        context.Context
+	digestFunction digest.Function
+
+	digests map[virtual.PrepopulatedDirectory]directoryDigestResult
+}
+
+// directoryDigestResult is the cached outcome of computing the digest
+// of a single directory. If ok is false, the digest could not be
+// computed, because one of the files in the directory did not provide a
+// digest.
+type directoryDigestResult struct {
+	digest digest.Digest
+	ok     bool
+}
+
+func newDirectoryDigestComputer(ctx context.Context, digestFunction digest.Function) *directoryDigestComputer {
+	return &directoryDigestComputer{
+		context:        ctx,
+		digestFunction: digestFunction,
+		digests:        map[virtual.PrepopulatedDirectory]directoryDigestResult{},
+	}
+}
+
+// getDigest returns the digest of the REv2 Directory message
+// corresponding to a directory. False is returned if the digest cannot
+// be computed accurately. This may happen if files contained in the
+// directory are opened for writing.
+func (dc *directoryDigestComputer) getDigest(d virtual.PrepopulatedDirectory, dPath *path.Trace) (digest.Digest, bool, error) {
+	if result, ok := dc.digests[d]; ok {
+		return result.digest, result.ok, nil
+	}
+
+	directory, ok, err := dc.getDirectory(d, dPath)
+	if err != nil {
+		return digest.BadDigest, false, err
+	}
+	result := directoryDigestResult{digest: digest.BadDigest}
+	if ok {
+		data, err := proto.Marshal(directory)
+		if err != nil {
+			return digest.BadDigest, false, util.StatusWrapf(err, "Failed to marshal directory %#v", dPath.String())
+		}
+		digestGenerator := dc.digestFunction.NewGenerator(int64(len(data)))
+		if _, err := digestGenerator.Write(data); err != nil {
+			panic(err)
+		}
+		result = directoryDigestResult{
+			digest: digestGenerator.Sum(),
+			ok:     true,
+		}
+	}
+	dc.digests[d] = result
+	return result.digest, result.ok, nil
+}
+
+// checkDigestFunction returns an error if a leaf is backed by an
+// object in the Content Addressable Storage whose digest uses a
+// different hashing algorithm than the build. Such leaves report their
+// digest as is, meaning that including it in a Directory message would
+// yield a digest that mixes digest functions. Differences in instance
+// name are permitted, as they do not affect the digest's contents.
+func (dc *directoryDigestComputer) checkDigestFunction(leaf virtual.NativeLeaf) error {
+	for _, blobDigest := range leaf.GetContainingDigests().Items() {
+		if childDigestFunction := blobDigest.GetDigestFunction(); childDigestFunction.GetEnumValue() != dc.digestFunction.GetEnumValue() {
+			return status.Errorf(
+				codes.FailedPrecondition,
+				"Digest %#v uses digest function %s, while the build uses digest function %s",
+				blobDigest.String(),
+				childDigestFunction.GetEnumValue(),
+				dc.digestFunction.GetEnumValue())
+		}
+	}
+	return nil
+}
+
+// getDirectory converts the contents of a single directory to an REv2
+// Directory message.
+func (dc *directoryDigestComputer) getDirectory(d virtual.PrepopulatedDirectory, dPath *path.Trace) (*remoteexecution.Directory, bool, error) {
+	directories, leaves, err := d.LookupAllChildren()
+	if err != nil {
+		return nil, false, util.StatusWrapf(err, "Failed to look up children of directory %#v", dPath.String())
+	}
+
+	var directory remoteexecution.Directory
+	for _, entry := range directories {
+		childDigest, ok, err := dc.getDigest(entry.Child, dPath.Append(entry.Name))
+		if err != nil || !ok {
+			return nil, false, err
+		}
+		directory.Directories = append(directory.Directories, &remoteexecution.DirectoryNode{
+			Name:   entry.Name.String(),
+			Digest: childDigest.GetProto(),
+		})
+	}
+
+	for _, entry := range leaves {
+		childPath := dPath.Append(entry.Name)
+		target, err := entry.Child.Readlink()
+		if err == nil {
+			directory.Symlinks = append(directory.Symlinks, &remoteexecution.SymlinkNode{
+				Name:   entry.Name.String(),
+				Target: target,
+			})
+			continue
+		} else if err != syscall.EINVAL {
+			return nil, false, util.StatusWrapf(err, "Failed to read symbolic link %#v", childPath.String())
+		}
+
+		fileStatus, err := entry.Child.GetOutputServiceFileStatus(&dc.digestFunction)
+		if err != nil {
+			return nil, false, util.StatusWrapf(err, "Failed to obtain status of file %#v", childPath.String())
+		}
+		file, ok := fileStatus.FileType.(*remoteoutputservice.FileStatus_File_)
+		if !ok || file.File.Digest == nil {
+			return nil, false, nil
+		}
+		if err := dc.checkDigestFunction(entry.Child); err != nil {
+			return nil, false, util.StatusWrapf(err, "Invalid digest for file %#v", childPath.String())
+		}
+		var attributes virtual.Attributes
+		entry.Child.VirtualGetAttributes(dc.context, virtual.AttributesMaskPermissions, &attributes)
+		permissions, ok := attributes.GetPermissions()
+		if !ok {
+			panic("Leaf did not provide permissions, even though they were requested")
+		}
+		directory.Files = append(directory.Files, &remoteexecution.FileNode{
+			Name:         entry.Name.String(),
+			Digest:       file.File.Digest,
+			IsExecutable: permissions&virtual.PermissionsExecute != 0,
+		})
+	}
+	return &directory, true, nil
+}
@@ -0,0 +1,108 @@
+package virtual
+
+import (
+	"context"
+	"sync"
+
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/virtual"
+	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+)
+
+// DirectoryHandleTracker keeps track of the handles of all stateful
+// directories that are currently allocated, indexed by inode number.
+// This makes it possible to invalidate directory entries in the
+// kernel, even if the directory has been wrapped by decorators that
+// don't provide access to its handle.
+type DirectoryHandleTracker struct {
+	lock    sync.Mutex
+	handles map[uint64]virtual.StatefulDirectoryHandle
+}
+
+// NewDirectoryHandleTracker creates a DirectoryHandleTracker that does
+// not track any directory handles. Handles can be registered by
+// creating a StatefulHandleAllocator through
+// NewDirectoryHandleTrackingHandleAllocator().
+func NewDirectoryHandleTracker() *DirectoryHandleTracker {
+	return &DirectoryHandleTracker{
+		handles: map[uint64]virtual.StatefulDirectoryHandle{},
+	}
+}
+
+// NotifyRemoval calls NotifyRemoval() against the handle of a
+// directory, causing the kernel to drop any state it has cached for
+// the directory entry with the provided name. False is returned if the
+// handle of the directory is not being tracked.
+func (t *DirectoryHandleTracker) NotifyRemoval(ctx context.Context, directory virtual.Directory, name path.Component) bool {
+	var attributes virtual.Attributes
+	directory.VirtualGetAttributes(ctx, virtual.AttributesMaskInodeNumber, &attributes)
+
+	t.lock.Lock()
+	handle, ok := t.handles[attributes.GetInodeNumber()]
+	t.lock.Unlock()
+	if !ok {
+		return false
+	}
+	handle.NotifyRemoval(name)
+	return true
+}
+
+type directoryHandleTrackingHandleAllocator struct {
+	base    virtual.StatefulHandleAllocator
+	tracker *DirectoryHandleTracker
+}
+
+// NewDirectoryHandleTrackingHandleAllocator creates a decorator for
+// StatefulHandleAllocator that registers all stateful directory handles
+// that it allocates with a DirectoryHandleTracker. Handles are
+// unregistered when released.
+func NewDirectoryHandleTrackingHandleAllocator(base virtual.StatefulHandleAllocator, tracker *DirectoryHandleTracker) virtual.StatefulHandleAllocator {
+	return &directoryHandleTrackingHandleAllocator{
+		base:    base,
+		tracker: tracker,
+	}
+}
+
+func (ha *directoryHandleTrackingHandleAllocator) New() virtual.StatefulHandleAllocation {
+	return &directoryHandleTrackingHandleAllocation{
+		StatefulHandleAllocation: ha.base.New(),
+		tracker:                  ha.tracker,
+	}
+}
+
+type directoryHandleTrackingHandleAllocation struct {
+	virtual.StatefulHandleAllocation
+	tracker *DirectoryHandleTracker
+}
+
+func (hn *directoryHandleTrackingHandleAllocation) AsStatefulDirectory(directory virtual.Directory) virtual.StatefulDirectoryHandle {
+	handle := hn.StatefulHandleAllocation.AsStatefulDirectory(directory)
+	var attributes virtual.Attributes
+	handle.GetAttributes(virtual.AttributesMaskInodeNumber, &attributes)
+	inodeNumber := attributes.GetInodeNumber()
+
+	t := hn.tracker
+	t.lock.Lock()
+	t.handles[inodeNumber] = handle
+	t.lock.Unlock()
+	return &directoryHandleTrackingDirectoryHandle{
+		StatefulDirectoryHandle: handle,
+		tracker:                 t,
+		inodeNumber:             inodeNumber,
+	}
+}
+
+type directoryHandleTrackingDirectoryHandle struct {
+	virtual.StatefulDirectoryHandle
+	tracker     *DirectoryHandleTracker
+	inodeNumber uint64
+}
+
+func (dh *directoryHandleTrackingDirectoryHandle) Release() {
+	t := dh.tracker
+	t.lock.Lock()
+	if t.handles[dh.inodeNumber] == dh.StatefulDirectoryHandle {
+		delete(t.handles, dh.inodeNumber)
+	}
+	t.lock.Unlock()
+	dh.StatefulDirectoryHandle.Release()
+}
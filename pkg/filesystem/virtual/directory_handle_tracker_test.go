@@ -0,0 +1,67 @@
+package virtual_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/buildbarn/bb-clientd/internal/mock"
+	cd_vfs "github.com/buildbarn/bb-clientd/pkg/filesystem/virtual"
+	re_vfs "github.com/buildbarn/bb-remote-execution/pkg/filesystem/virtual"
+	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDirectoryHandleTracker(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	baseHandleAllocator := mock.NewMockStatefulHandleAllocator(ctrl)
+	tracker := cd_vfs.NewDirectoryHandleTracker()
+	handleAllocator := cd_vfs.NewDirectoryHandleTrackingHandleAllocator(baseHandleAllocator, tracker)
+
+	// Allocate a handle for a directory having inode number 123.
+	directory := mock.NewMockVirtualDirectory(ctrl)
+	baseHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	baseHandleAllocator.EXPECT().New().Return(baseHandleAllocation)
+	baseHandle := mock.NewMockStatefulDirectoryHandle(ctrl)
+	baseHandleAllocation.EXPECT().AsStatefulDirectory(directory).Return(baseHandle)
+	baseHandle.EXPECT().GetAttributes(re_vfs.AttributesMaskInodeNumber, gomock.Any()).
+		Do(func(requested re_vfs.AttributesMask, attributes *re_vfs.Attributes) {
+			attributes.SetInodeNumber(123)
+		})
+	handle := handleAllocator.New().AsStatefulDirectory(directory)
+
+	getAttributes := func(ctx context.Context, requested re_vfs.AttributesMask, attributes *re_vfs.Attributes) {
+		attributes.SetInodeNumber(123)
+	}
+
+	t.Run("UnknownDirectory", func(t *testing.T) {
+		otherDirectory := mock.NewMockVirtualDirectory(ctrl)
+		otherDirectory.EXPECT().VirtualGetAttributes(ctx, re_vfs.AttributesMaskInodeNumber, gomock.Any()).
+			Do(func(ctx context.Context, requested re_vfs.AttributesMask, attributes *re_vfs.Attributes) {
+				attributes.SetInodeNumber(456)
+			})
+
+		require.False(t, tracker.NotifyRemoval(ctx, otherDirectory, path.MustNewComponent("foo")))
+	})
+
+	t.Run("KnownDirectory", func(t *testing.T) {
+		// The directory may be wrapped by a decorator, as the
+		// handle is looked up by inode number.
+		wrappedDirectory := mock.NewMockVirtualDirectory(ctrl)
+		wrappedDirectory.EXPECT().VirtualGetAttributes(ctx, re_vfs.AttributesMaskInodeNumber, gomock.Any()).Do(getAttributes)
+		baseHandle.EXPECT().NotifyRemoval(path.MustNewComponent("foo"))
+
+		require.True(t, tracker.NotifyRemoval(ctx, wrappedDirectory, path.MustNewComponent("foo")))
+	})
+
+	t.Run("ReleasedDirectory", func(t *testing.T) {
+		// Once released, the handle should no longer be tracked.
+		baseHandle.EXPECT().Release()
+		handle.Release()
+
+		directory.EXPECT().VirtualGetAttributes(ctx, re_vfs.AttributesMaskInodeNumber, gomock.Any()).Do(getAttributes)
+
+		require.False(t, tracker.NotifyRemoval(ctx, directory, path.MustNewComponent("foo")))
+	})
+}
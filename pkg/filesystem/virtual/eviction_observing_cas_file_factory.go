@@ -0,0 +1,115 @@
+package virtual
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/virtual"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	outputPathEvictionPrometheusMetrics sync.Once
+
+	outputPathEvictedCASFilesTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "buildbarn",
+			Subsystem: "clientd",
+			Name:      "output_path_evicted_cas_files_total",
+			Help:      "Number of files backed by the Content Addressable Storage that were removed from output paths.",
+		},
+		[]string{"output_base_id"})
+)
+
+// newEvictedCASFilesCounter returns a Prometheus counter that can be
+// used to count the number of files backed by the Content Addressable
+// Storage that are removed from an output path.
+func newEvictedCASFilesCounter(outputBaseIDLabel string) prometheus.Counter {
+	outputPathEvictionPrometheusMetrics.Do(func() {
+		prometheus.MustRegister(outputPathEvictedCASFilesTotal)
+	})
+
+	return outputPathEvictedCASFilesTotal.WithLabelValues(outputBaseIDLabel)
+}
+
+// CASFileEvictionObserver is a callback that is invoked by files
+// created through NewEvictionObservingCASFileFactory() when they are
+// removed from the file system.
+//
+// As the callback may be invoked while locks on the containing
+// directory are held, it must not block or call into the file system.
+// It should merely perform bookkeeping, such as incrementing a counter.
+type CASFileEvictionObserver func(blobDigest digest.Digest)
+
+type evictionObservingCASFileFactory struct {
+	base     virtual.CASFileFactory
+	observer CASFileEvictionObserver
+}
+
+// NewEvictionObservingCASFileFactory creates a decorator for
+// CASFileFactory that invokes a callback when files that it creates
+// are removed from the file system, either because they are unlinked
+// explicitly, or because they get replaced or purged as part of
+// starting or cleaning a build. This can be used to detect thrashing,
+// where the same files are repeatedly created and removed.
+//
+// As handle allocators may discard calls to Unlink() against
+// stateless files, this decorator should be placed on top of the
+// CASFileFactory that allocates handles.
+func NewEvictionObservingCASFileFactory(base virtual.CASFileFactory, observer CASFileEvictionObserver) virtual.CASFileFactory {
+	return &evictionObservingCASFileFactory{
+		base:     base,
+		observer: observer,
+	}
+}
+
+func (cff *evictionObservingCASFileFactory) LookupFile(blobDigest digest.Digest, isExecutable bool, readMonitor virtual.FileReadMonitor) virtual.NativeLeaf {
+	l := &evictionObservingNativeLeaf{
+		NativeLeaf: cff.base.LookupFile(blobDigest, isExecutable, readMonitor),
+		factory:    cff,
+		blobDigest: blobDigest,
+	}
+	l.linkCount.Store(1)
+	return l
+}
+
+// evictionObservingNativeLeaf is a decorator for NativeLeaf that keeps
+// track of the number of directory entries that refer to the file. The
+// eviction observer is called when the last entry is removed.
+type evictionObservingNativeLeaf struct {
+	virtual.NativeLeaf
+	factory    *evictionObservingCASFileFactory
+	blobDigest digest.Digest
+	linkCount  atomic.Int64
+}
+
+func (l *evictionObservingNativeLeaf) Link() virtual.Status {
+	if s := l.NativeLeaf.Link(); s != virtual.StatusOK {
+		return s
+	}
+	l.linkCount.Add(1)
+	return virtual.StatusOK
+}
+
+func (l *evictionObservingNativeLeaf) Unlink() {
+	l.NativeLeaf.Unlink()
+	if l.linkCount.Add(-1) == 0 {
+		l.factory.observer(l.blobDigest)
+	}
+}
+
+// creationCountingCASFileFactory is a decorator for CASFileFactory that
+// increments a counter for every file that is created. When combined
+// with a CASFileEvictionObserver that decrements the same counter, it
+// keeps track of the number of files backed by the Content Addressable
+// Storage that are present in output paths.
+type creationCountingCASFileFactory struct {
+	virtual.CASFileFactory
+	counter *atomic.Int64
+}
+
+func (cff *creationCountingCASFileFactory) LookupFile(blobDigest digest.Digest, isExecutable bool, readMonitor virtual.FileReadMonitor) virtual.NativeLeaf {
+	cff.counter.Add(1)
+	return cff.CASFileFactory.LookupFile(blobDigest, isExecutable, readMonitor)
+}
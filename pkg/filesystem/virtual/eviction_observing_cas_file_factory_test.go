@@ -0,0 +1,74 @@
+package virtual_test
+
+import (
+	"testing"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-clientd/internal/mock"
+	cd_vfs "github.com/buildbarn/bb-clientd/pkg/filesystem/virtual"
+	re_vfs "github.com/buildbarn/bb-remote-execution/pkg/filesystem/virtual"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvictionObservingCASFileFactory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	baseCASFileFactory := mock.NewMockCASFileFactory(ctrl)
+	var evictedDigests []digest.Digest
+	casFileFactory := cd_vfs.NewEvictionObservingCASFileFactory(
+		baseCASFileFactory,
+		func(blobDigest digest.Digest) {
+			evictedDigests = append(evictedDigests, blobDigest)
+		})
+
+	blobDigest := digest.MustNewDigest("hello", remoteexecution.DigestFunction_MD5, "8b1a9953c4611296a827abf8c47804d7", 5)
+
+	t.Run("SingleLink", func(t *testing.T) {
+		// Unlinking a file that has no additional hard links
+		// should immediately report it as being evicted.
+		baseLeaf := mock.NewMockNativeLeaf(ctrl)
+		baseCASFileFactory.EXPECT().LookupFile(blobDigest, false, nil).Return(baseLeaf)
+
+		leaf := casFileFactory.LookupFile(blobDigest, false, nil)
+		baseLeaf.EXPECT().Unlink()
+		leaf.Unlink()
+		require.Equal(t, []digest.Digest{blobDigest}, evictedDigests)
+		evictedDigests = nil
+	})
+
+	t.Run("MultipleLinks", func(t *testing.T) {
+		// The observer should only be called once the last hard
+		// link to the file has been removed.
+		baseLeaf := mock.NewMockNativeLeaf(ctrl)
+		baseCASFileFactory.EXPECT().LookupFile(blobDigest, true, nil).Return(baseLeaf)
+
+		leaf := casFileFactory.LookupFile(blobDigest, true, nil)
+		baseLeaf.EXPECT().Link().Return(re_vfs.StatusOK)
+		require.Equal(t, re_vfs.StatusOK, leaf.Link())
+
+		baseLeaf.EXPECT().Unlink().Times(2)
+		leaf.Unlink()
+		require.Empty(t, evictedDigests)
+		leaf.Unlink()
+		require.Equal(t, []digest.Digest{blobDigest}, evictedDigests)
+		evictedDigests = nil
+	})
+
+	t.Run("LinkFailure", func(t *testing.T) {
+		// Hard links that could not be created by the
+		// underlying file should not be counted.
+		baseLeaf := mock.NewMockNativeLeaf(ctrl)
+		baseCASFileFactory.EXPECT().LookupFile(blobDigest, false, nil).Return(baseLeaf)
+
+		leaf := casFileFactory.LookupFile(blobDigest, false, nil)
+		baseLeaf.EXPECT().Link().Return(re_vfs.StatusErrStale)
+		require.Equal(t, re_vfs.StatusErrStale, leaf.Link())
+
+		baseLeaf.EXPECT().Unlink()
+		leaf.Unlink()
+		require.Equal(t, []digest.Digest{blobDigest}, evictedDigests)
+		evictedDigests = nil
+	})
+}
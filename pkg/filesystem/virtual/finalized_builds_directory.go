@@ -0,0 +1,245 @@
+package virtual
+
+import (
+	"context"
+	"time"
+
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/virtual"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/filesystem"
+	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DefaultFinalizedBuildsDirectoryName is the name of the directory in
+// the root of the Remote Output Service under which the contents of
+// retained finalized builds are exposed, unless configured otherwise.
+// Output base IDs and symbolic links created through
+// CreateRootSymlink() may not use this name.
+var DefaultFinalizedBuildsDirectoryName = path.MustNewComponent(".history")
+
+// commonOutputDirectoryNames contains names of directories that build
+// clients commonly create. Reserved directories may not use these
+// names, as they are likely to collide with actual build outputs.
+var commonOutputDirectoryNames = map[string]struct{}{
+	"_tmp":      {},
+	"bazel-out": {},
+	"bin":       {},
+	"execroot":  {},
+	"external":  {},
+	"genfiles":  {},
+	"testlogs":  {},
+}
+
+// NewReservedDirectoryName validates the name of a directory that is
+// reserved for use by the Remote Output Service, such as the directory
+// under which retained finalized builds are exposed.
+func NewReservedDirectoryName(name string) (path.Component, error) {
+	component, ok := path.NewComponent(name)
+	if !ok {
+		return path.Component{}, status.Errorf(codes.InvalidArgument, "Name %s", getInvalidComponentReason(name))
+	}
+	if _, ok := commonOutputDirectoryNames[name]; ok {
+		return path.Component{}, status.Errorf(codes.InvalidArgument, "Name %#v is commonly used by build outputs", name)
+	}
+	return component, nil
+}
+
+// finalizedBuild contains the state of a build whose contents have
+// been retained after the build was finalized.
+type finalizedBuild struct {
+	id           string
+	treeDigest   digest.Digest
+	finalizeTime time.Time
+
+	// The number of bytes fetched from the Content Addressable
+	// Storage between StartBuild() and FinalizeBuild().
+	casBytesFetched uint64
+
+	// The number of fetches against the Content Addressable Storage
+	// that failed between StartBuild() and FinalizeBuild().
+	casFetchFailures uint64
+
+	// The metadata provided through SetBuildMetadata() at the time
+	// the build was finalized.
+	clientMetadata map[string]string
+
+	// The root directory of the Tree containing the contents of the
+	// output path. If the build ID is not a valid filename, the build
+	// is not exposed through the virtual file system.
+	rootDirectory virtual.Directory
+	name          *path.Component
+	cookie        uint64
+}
+
+// snapshotOutputPath uploads the contents of an output path to the
+// Content Addressable Storage, so that they can be retained once the
+// build is finalized. Failures are logged against the output path, as
+// they should not cause FinalizeBuild() to fail.
+func (d *RemoteOutputServiceDirectory) snapshotOutputPath(ctx context.Context, outputPathState *outputPathState, buildID string) *finalizedBuild {
+	d.lock.Lock()
+	buildState := outputPathState.buildState
+	d.lock.Unlock()
+	if buildState == nil || buildState.id != buildID {
+		return nil
+	}
+
+	treeDigest, err := newTreeExporter(ctx, outputPathState.bareContentAddressableStorage, buildState.digestFunction, d.exportUploadConcurrency).
+		exportTree(outputPathState.rootDirectory, nil)
+	if err != nil {
+		outputPathState.errorLogger.Log(util.StatusWrapf(err, "Failed to retain contents of build %#v", buildID))
+		return nil
+	}
+	return &finalizedBuild{
+		id:           buildID,
+		treeDigest:   treeDigest,
+		finalizeTime: d.clock.Now(),
+	}
+}
+
+// addFinalizedBuildLocked adds a build to the list of retained
+// finalized builds of an output base. If this causes the number of
+// retained builds to exceed the configured limit, the least recently
+// finalized build is removed. Removals of directory entries are
+// returned, so that the caller can call NotifyRemoval() after dropping
+// the directory lock.
+func (d *RemoteOutputServiceDirectory) addFinalizedBuildLocked(outputPathState *outputPathState, build *finalizedBuild) []directoryEntryRemoval {
+	// Build IDs are expected to be unique. Discard any build that
+	// was retained previously under the same ID.
+	var removals []directoryEntryRemoval
+	for outputBaseID, builds := range d.finalizedBuilds {
+		for i, oldBuild := range builds {
+			if oldBuild.id == build.id {
+				removals = append(removals, d.removeFinalizedBuildsLocked(builds[i:i+1])...)
+				if remaining := append(builds[:i:i], builds[i+1:]...); len(remaining) > 0 {
+					d.finalizedBuilds[outputBaseID] = remaining
+				} else {
+					delete(d.finalizedBuilds, outputBaseID)
+				}
+				break
+			}
+		}
+	}
+
+	if name, ok := path.NewComponent(build.id); ok {
+		if outputPathState.treeCASDirectoryFactory == nil {
+			outputPathState.treeCASDirectoryFactory = NewTreeCASDirectoryFactory(
+				context.Background(),
+				outputPathState.casFileFactory,
+				outputPathState.directoryFetcher,
+				d.handleAllocator.New(),
+				/* computeLinkCounts = */ false,
+				outputPathState.errorLogger)
+		}
+		build.rootDirectory = outputPathState.treeCASDirectoryFactory.LookupDirectory(build.treeDigest)
+		build.name = &name
+		build.cookie = d.changeID
+		d.finalizedBuildsByName[name] = build
+	}
+	d.changeID++
+
+	outputBaseID := outputPathState.outputBaseID
+	builds := append(d.finalizedBuilds[outputBaseID], build)
+	if excess := len(builds) - d.retainedFinalizedBuilds; excess > 0 {
+		removals = append(removals, d.removeFinalizedBuildsLocked(builds[:excess])...)
+		builds = builds[excess:]
+	}
+	d.finalizedBuilds[outputBaseID] = builds
+	return removals
+}
+
+// removeFinalizedBuildsLocked removes builds from the virtual file
+// system. The caller is responsible for removing them from the list of
+// retained builds of the output base.
+func (d *RemoteOutputServiceDirectory) removeFinalizedBuildsLocked(builds []*finalizedBuild) []directoryEntryRemoval {
+	var removals []directoryEntryRemoval
+	for _, build := range builds {
+		if build.name != nil {
+			delete(d.finalizedBuildsByName, *build.name)
+			removals = append(removals, directoryEntryRemoval{
+				handle: d.finalizedBuildsDirectory.handle,
+				name:   *build.name,
+			})
+		}
+	}
+	if len(builds) > 0 {
+		d.changeID++
+	}
+	return removals
+}
+
+// finalizedBuildsDirectory is the directory in the root of the Remote
+// Output Service that contains the retained finalized builds of all
+// output bases, named after their build ID.
+type finalizedBuildsDirectory struct {
+	virtual.ReadOnlyDirectory
+
+	service *RemoteOutputServiceDirectory
+	handle  virtual.StatefulDirectoryHandle
+	cookie  uint64
+}
+
+func (fd *finalizedBuildsDirectory) getAttributesLocked(requested virtual.AttributesMask, attributes *virtual.Attributes) {
+	attributes.SetFileType(filesystem.FileTypeDirectory)
+	attributes.SetPermissions(virtual.PermissionsRead | virtual.PermissionsExecute)
+	attributes.SetSizeBytes(0)
+	attributes.SetChangeID(fd.service.changeID)
+	attributes.SetLinkCount(virtual.EmptyDirectoryLinkCount + uint32(len(fd.service.finalizedBuildsByName)))
+	fd.handle.GetAttributes(requested, attributes)
+}
+
+func (fd *finalizedBuildsDirectory) VirtualGetAttributes(ctx context.Context, requested virtual.AttributesMask, attributes *virtual.Attributes) {
+	fd.service.lock.Lock()
+	defer fd.service.lock.Unlock()
+
+	fd.getAttributesLocked(requested, attributes)
+}
+
+func (fd *finalizedBuildsDirectory) VirtualLookup(ctx context.Context, name path.Component, requested virtual.AttributesMask, out *virtual.Attributes) (virtual.DirectoryChild, virtual.Status) {
+	d := fd.service
+	d.lock.Lock()
+	build, ok := d.finalizedBuildsByName[name]
+	d.lock.Unlock()
+	if !ok {
+		return virtual.DirectoryChild{}, virtual.StatusErrNoEnt
+	}
+	build.rootDirectory.VirtualGetAttributes(ctx, requested, out)
+	return virtual.DirectoryChild{}.FromDirectory(build.rootDirectory), virtual.StatusOK
+}
+
+func (fd *finalizedBuildsDirectory) VirtualOpenChild(ctx context.Context, name path.Component, shareAccess virtual.ShareMask, createAttributes *virtual.Attributes, existingOptions *virtual.OpenExistingOptions, requested virtual.AttributesMask, openedFileAttributes *virtual.Attributes) (virtual.Leaf, virtual.AttributesMask, virtual.ChangeInfo, virtual.Status) {
+	d := fd.service
+	d.lock.Lock()
+	_, ok := d.finalizedBuildsByName[name]
+	d.lock.Unlock()
+	if ok {
+		return virtual.ReadOnlyDirectoryOpenChildWrongFileType(existingOptions, virtual.StatusErrIsDir)
+	}
+	return virtual.ReadOnlyDirectoryOpenChildDoesntExist(createAttributes)
+}
+
+func (fd *finalizedBuildsDirectory) VirtualReadDir(ctx context.Context, firstCookie uint64, requested virtual.AttributesMask, reporter virtual.DirectoryEntryReporter) virtual.Status {
+	d := fd.service
+	d.lock.Lock()
+	var entries []directoryEntry
+	for name, build := range d.finalizedBuildsByName {
+		if build.cookie >= firstCookie {
+			entries = append(entries, directoryEntry{
+				cookie:        build.cookie,
+				name:          name,
+				child:         virtual.DirectoryChild{}.FromDirectory(build.rootDirectory),
+				getAttributes: build.rootDirectory.VirtualGetAttributes,
+			})
+		}
+	}
+	d.lock.Unlock()
+
+	// Attributes of the root directories of the builds are obtained
+	// without holding the directory lock, as they may need to be
+	// loaded from the Content Addressable Storage.
+	reportDirectoryEntries(ctx, entries, requested, reporter)
+	return virtual.StatusOK
+}
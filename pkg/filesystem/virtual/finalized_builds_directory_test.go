@@ -0,0 +1,250 @@
+package virtual_test
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-clientd/internal/mock"
+	cd_vfs "github.com/buildbarn/bb-clientd/pkg/filesystem/virtual"
+	cd_testutil "github.com/buildbarn/bb-clientd/pkg/filesystem/virtual/testutil"
+	"github.com/buildbarn/bb-clientd/pkg/proto/outputpaths"
+	re_cas "github.com/buildbarn/bb-remote-execution/pkg/cas"
+	re_filesystem "github.com/buildbarn/bb-remote-execution/pkg/filesystem"
+	re_vfs "github.com/buildbarn/bb-remote-execution/pkg/filesystem/virtual"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/remoteoutputservice"
+	"github.com/buildbarn/bb-storage/pkg/auth"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+	"github.com/buildbarn/bb-storage/pkg/random"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRemoteOutputServiceDirectoryRetainFinalizedBuilds(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	contentAddressableStorage := cd_testutil.NewFakeContentAddressableStorage()
+	handleAllocator := re_vfs.NewFUSEHandleAllocator(random.FastThreadSafeGenerator)
+	symlinkFactory := re_vfs.NewHandleAllocatingSymlinkFactory(
+		re_vfs.BaseSymlinkFactory,
+		handleAllocator.New())
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(1000, 0)).AnyTimes()
+	d := newTestRemoteOutputServiceDirectory(
+		handleAllocator,
+		cd_vfs.NewInMemoryOutputPathFactory(re_filesystem.InMemoryFilePool, symlinkFactory, handleAllocator, sort.Sort, clock),
+		contentAddressableStorage,
+		contentAddressableStorage,
+		re_cas.NewBlobAccessDirectoryFetcher(
+			contentAddressableStorage,
+			/* maximumDirectorySizeBytes = */ 10000,
+			/* maximumTreeSizeBytes = */ 10000),
+		symlinkFactory,
+		auth.NewStaticAuthorizer(func(digest.InstanceName) bool { return true }),
+		clock,
+		cd_vfs.RemoteOutputServiceDirectoryOptions{
+			RetainedFinalizedBuilds: 2,
+		})
+
+	// Run a build that creates a single file, whose contents
+	// differ per build.
+	digestFunction := digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256)
+	runBuild := func(buildID, contents string) {
+		_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+			OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+			BuildId:          buildID,
+			DigestFunction:   remoteexecution.DigestFunction_SHA256,
+			OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+		})
+		require.NoError(t, err)
+		_, err = d.SetBuildMetadata(ctx, &outputpaths.SetBuildMetadataRequest{
+			BuildId:  buildID,
+			Metadata: map[string]string{"invocation_id": buildID},
+		})
+		require.NoError(t, err)
+		_, err = d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+			BuildId:         buildID,
+			PathPrefix:      "bazel-out",
+			CleanPathPrefix: true,
+			Files: []*remoteexecution.OutputFile{
+				{
+					Path:   "hello.txt",
+					Digest: contentAddressableStorage.PutBlob(digestFunction, []byte(contents)).GetProto(),
+				},
+			},
+		})
+		require.NoError(t, err)
+		_, err = d.FinalizeBuild(ctx, &remoteoutputservice.FinalizeBuildRequest{
+			BuildId: buildID,
+		})
+		require.NoError(t, err)
+	}
+
+	// Read the contents of the file of a retained build through the
+	// virtual file system.
+	readFinalizedBuild := func(buildID string) (string, re_vfs.Status) {
+		child, s := d.VirtualLookup(ctx, path.MustNewComponent(".history"), 0, &re_vfs.Attributes{})
+		require.Equal(t, re_vfs.StatusOK, s)
+		history, _ := child.GetPair()
+		child, s = history.VirtualLookup(ctx, path.MustNewComponent(buildID), 0, &re_vfs.Attributes{})
+		if s != re_vfs.StatusOK {
+			return "", s
+		}
+		rootDirectory, _ := child.GetPair()
+		child, s = rootDirectory.VirtualLookup(ctx, path.MustNewComponent("bazel-out"), 0, &re_vfs.Attributes{})
+		require.Equal(t, re_vfs.StatusOK, s)
+		bazelOut, _ := child.GetPair()
+		child, s = bazelOut.VirtualLookup(ctx, path.MustNewComponent("hello.txt"), 0, &re_vfs.Attributes{})
+		require.Equal(t, re_vfs.StatusOK, s)
+		_, file := child.GetPair()
+		var buf [100]byte
+		n, eof, s := file.VirtualRead(buf[:], 0)
+		require.Equal(t, re_vfs.StatusOK, s)
+		require.True(t, eof)
+		return string(buf[:n]), re_vfs.StatusOK
+	}
+
+	listFinalizedBuilds := func() []string {
+		response, err := d.ListFinalizedBuilds(ctx, &outputpaths.ListFinalizedBuildsRequest{
+			OutputBaseId: "9da951b8cb759233037166e28f7ea186",
+		})
+		require.NoError(t, err)
+		var buildIDs []string
+		for _, build := range response.Builds {
+			require.NotNil(t, build.RootTreeDigest)
+			require.Equal(t, int64(1000), build.FinalizeTime.Seconds)
+			require.Equal(t, map[string]string{"invocation_id": build.BuildId}, build.ClientMetadata)
+			buildIDs = append(buildIDs, build.BuildId)
+		}
+		return buildIDs
+	}
+
+	t.Run("ReservedOutputBaseID", func(t *testing.T) {
+		_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+			OutputBaseId:     ".history",
+			BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			DigestFunction:   remoteexecution.DigestFunction_SHA256,
+			OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Output base ID may not start with reserved name \".history\""), err)
+	})
+
+	t.Run("SingleBuild", func(t *testing.T) {
+		runBuild("37f5dbef-b117-4fb6-bce8-5c147cb603b4", "Build 1")
+		require.Equal(t, []string{"37f5dbef-b117-4fb6-bce8-5c147cb603b4"}, listFinalizedBuilds())
+
+		contents, s := readFinalizedBuild("37f5dbef-b117-4fb6-bce8-5c147cb603b4")
+		require.Equal(t, re_vfs.StatusOK, s)
+		require.Equal(t, "Build 1", contents)
+	})
+
+	t.Run("GarbageCollection", func(t *testing.T) {
+		// Once more than two builds are finalized, the oldest
+		// build should be removed. The contents of the other
+		// builds should not be affected by successive builds.
+		runBuild("0a3c4a01-f2b1-4f4d-9d5f-8e1e1f2b3c4d", "Build 2")
+		runBuild("b4e6ec5c-8d9a-4f8d-a3f4-2f3c1e5d6a7b", "Build 3")
+		require.Equal(t, []string{
+			"b4e6ec5c-8d9a-4f8d-a3f4-2f3c1e5d6a7b",
+			"0a3c4a01-f2b1-4f4d-9d5f-8e1e1f2b3c4d",
+		}, listFinalizedBuilds())
+
+		_, s := readFinalizedBuild("37f5dbef-b117-4fb6-bce8-5c147cb603b4")
+		require.Equal(t, re_vfs.StatusErrNoEnt, s)
+		contents, s := readFinalizedBuild("0a3c4a01-f2b1-4f4d-9d5f-8e1e1f2b3c4d")
+		require.Equal(t, re_vfs.StatusOK, s)
+		require.Equal(t, "Build 2", contents)
+		contents, s = readFinalizedBuild("b4e6ec5c-8d9a-4f8d-a3f4-2f3c1e5d6a7b")
+		require.Equal(t, re_vfs.StatusOK, s)
+		require.Equal(t, "Build 3", contents)
+	})
+
+	t.Run("DiffBuilds", func(t *testing.T) {
+		_, err := d.DiffBuilds(ctx, &outputpaths.DiffBuildsRequest{
+			OutputBaseId: "9da951b8cb759233037166e28f7ea186",
+			OldBuildId:   "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			NewBuildId:   "b4e6ec5c-8d9a-4f8d-a3f4-2f3c1e5d6a7b",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.NotFound, "Output base does not have a retained build with ID \"37f5dbef-b117-4fb6-bce8-5c147cb603b4\""), err)
+
+		// Comparing a build against itself should not yield
+		// any differences.
+		response, err := d.DiffBuilds(ctx, &outputpaths.DiffBuildsRequest{
+			OutputBaseId: "9da951b8cb759233037166e28f7ea186",
+			OldBuildId:   "b4e6ec5c-8d9a-4f8d-a3f4-2f3c1e5d6a7b",
+			NewBuildId:   "b4e6ec5c-8d9a-4f8d-a3f4-2f3c1e5d6a7b",
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &outputpaths.DiffBuildsResponse{}, response)
+
+		// The builds only differ in the contents of a single
+		// file, which should be reported with both digests.
+		response, err = d.DiffBuilds(ctx, &outputpaths.DiffBuildsRequest{
+			OutputBaseId: "9da951b8cb759233037166e28f7ea186",
+			OldBuildId:   "0a3c4a01-f2b1-4f4d-9d5f-8e1e1f2b3c4d",
+			NewBuildId:   "b4e6ec5c-8d9a-4f8d-a3f4-2f3c1e5d6a7b",
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &outputpaths.DiffBuildsResponse{
+			Differences: []*outputpaths.PathDifference{
+				{
+					Path: "bazel-out/hello.txt",
+					OldNode: &outputpaths.DiffedNode{
+						NodeType: &outputpaths.DiffedNode_File{
+							File: &remoteexecution.FileNode{
+								Name:   "hello.txt",
+								Digest: contentAddressableStorage.PutBlob(digestFunction, []byte("Build 2")).GetProto(),
+							},
+						},
+					},
+					NewNode: &outputpaths.DiffedNode{
+						NodeType: &outputpaths.DiffedNode_File{
+							File: &remoteexecution.FileNode{
+								Name:   "hello.txt",
+								Digest: contentAddressableStorage.PutBlob(digestFunction, []byte("Build 3")).GetProto(),
+							},
+						},
+					},
+				},
+			},
+		}, response)
+	})
+
+	t.Run("Clean", func(t *testing.T) {
+		// Cleaning the output base should also discard its
+		// retained builds.
+		_, err := d.Clean(ctx, &remoteoutputservice.CleanRequest{
+			OutputBaseId: "9da951b8cb759233037166e28f7ea186",
+		})
+		require.NoError(t, err)
+		require.Empty(t, listFinalizedBuilds())
+
+		_, s := readFinalizedBuild("b4e6ec5c-8d9a-4f8d-a3f4-2f3c1e5d6a7b")
+		require.Equal(t, re_vfs.StatusErrNoEnt, s)
+	})
+}
+
+func TestNewReservedDirectoryName(t *testing.T) {
+	t.Run("InvalidFilename", func(t *testing.T) {
+		_, err := cd_vfs.NewReservedDirectoryName("a/b")
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Name contains a slash"), err)
+	})
+
+	t.Run("CommonOutputDirectoryName", func(t *testing.T) {
+		_, err := cd_vfs.NewReservedDirectoryName("bazel-out")
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Name \"bazel-out\" is commonly used by build outputs"), err)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		name, err := cd_vfs.NewReservedDirectoryName(".bb_clientd_history")
+		require.NoError(t, err)
+		require.Equal(t, path.MustNewComponent(".bb_clientd_history"), name)
+	})
+}
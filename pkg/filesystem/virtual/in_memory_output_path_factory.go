@@ -48,6 +48,11 @@ func (opf *inMemoryOutputPathFactory) StartInitialBuild(outputBaseID path.Compon
 	}
 }
 
+func (opf *inMemoryOutputPathFactory) HasPersistentState(outputBaseID path.Component) bool {
+	// No persistent state associated with in-memory output paths.
+	return false
+}
+
 func (opf *inMemoryOutputPathFactory) Clean(outputBaseID path.Component) error {
 	// No persistent state associated with in-memory output paths.
 	return nil
@@ -58,3 +63,8 @@ type inMemoryOutputPath struct {
 }
 
 func (op inMemoryOutputPath) FinalizeBuild(ctx context.Context, digestFunction digest.Function) {}
+
+func (op inMemoryOutputPath) Rename(outputBaseID path.Component) error {
+	// No persistent state associated with in-memory output paths.
+	return nil
+}
@@ -76,6 +76,14 @@ func (op *localFileUploadingOutputPath) FinalizeBuild(ctx context.Context, diges
 	}
 }
 
+func (op *localFileUploadingOutputPath) Rename(outputBaseID path.Component) error {
+	if err := op.OutputPath.Rename(outputBaseID); err != nil {
+		return err
+	}
+	op.outputBaseID = outputBaseID
+	return nil
+}
+
 type localFileUploader struct {
 	context                   context.Context
 	contentAddressableStorage blobstore.BlobAccess
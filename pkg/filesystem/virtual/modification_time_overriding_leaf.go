@@ -0,0 +1,85 @@
+package virtual
+
+import (
+	"context"
+	"time"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/virtual"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/outputpathpersistency"
+	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// modificationTimeOverridingLeaf is a decorator for NativeLeaf that
+// reports a fixed modification time. It is used to expose files whose
+// modification time is provided through REv2 node properties, such as
+// OutputFile.node_properties.mtime in BatchCreate() requests.
+type modificationTimeOverridingLeaf struct {
+	virtual.NativeLeaf
+	modificationTime time.Time
+}
+
+func newModificationTimeOverridingLeaf(base virtual.NativeLeaf, modificationTime time.Time) virtual.NativeLeaf {
+	return &modificationTimeOverridingLeaf{
+		NativeLeaf:       base,
+		modificationTime: modificationTime,
+	}
+}
+
+// getModificationTimeFromNodeProperties extracts the modification time
+// from an REv2 NodeProperties message. It returns nil if no
+// modification time is present.
+func getModificationTimeFromNodeProperties(nodeProperties *remoteexecution.NodeProperties) (*time.Time, error) {
+	mtime := nodeProperties.GetMtime()
+	if mtime == nil {
+		return nil, nil
+	}
+	if err := mtime.CheckValid(); err != nil {
+		return nil, util.StatusWrapWithCode(err, codes.InvalidArgument, "Invalid modification time")
+	}
+	if mtime.Seconds < 0 {
+		return nil, status.Error(codes.InvalidArgument, "Modification time precedes the Unix epoch")
+	}
+	modificationTime := mtime.AsTime()
+	return &modificationTime, nil
+}
+
+func (l *modificationTimeOverridingLeaf) AppendOutputPathPersistencyDirectoryNode(directory *outputpathpersistency.Directory, name path.Component) {
+	l.NativeLeaf.AppendOutputPathPersistencyDirectoryNode(directory, name)
+
+	// Preserve the modification time across restarts by storing
+	// it in the FileNode that was appended by the base leaf.
+	if files := directory.Files; len(files) > 0 {
+		if file := files[len(files)-1]; file.Name == name.String() {
+			file.NodeProperties = &remoteexecution.NodeProperties{
+				Mtime: timestamppb.New(l.modificationTime),
+			}
+		}
+	}
+}
+
+func (l *modificationTimeOverridingLeaf) VirtualGetAttributes(ctx context.Context, requested virtual.AttributesMask, attributes *virtual.Attributes) {
+	l.NativeLeaf.VirtualGetAttributes(ctx, requested, attributes)
+	attributes.SetLastDataModificationTime(l.modificationTime)
+}
+
+func (l *modificationTimeOverridingLeaf) VirtualOpenSelf(ctx context.Context, shareAccess virtual.ShareMask, options *virtual.OpenExistingOptions, requested virtual.AttributesMask, attributes *virtual.Attributes) virtual.Status {
+	if s := l.NativeLeaf.VirtualOpenSelf(ctx, shareAccess, options, requested, attributes); s != virtual.StatusOK {
+		return s
+	}
+	attributes.SetLastDataModificationTime(l.modificationTime)
+	return virtual.StatusOK
+}
+
+func (l *modificationTimeOverridingLeaf) VirtualSetAttributes(ctx context.Context, in *virtual.Attributes, requested virtual.AttributesMask, out *virtual.Attributes) virtual.Status {
+	if s := l.NativeLeaf.VirtualSetAttributes(ctx, in, requested, out); s != virtual.StatusOK {
+		return s
+	}
+	out.SetLastDataModificationTime(l.modificationTime)
+	return virtual.StatusOK
+}
@@ -0,0 +1,106 @@
+package virtual
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// outputBasePath is the parsed form of an output base ID, as provided
+// to StartBuild(), Clean(), etc. Output base IDs consist of one or
+// more valid filenames separated by slashes (e.g., "team/project").
+// Output paths belonging to output base IDs with multiple components
+// are exposed in nested directories.
+//
+// The zero value corresponds to the root directory of the Remote
+// Output Service. It is used to refer to the parent of output base IDs
+// consisting of a single component.
+type outputBasePath struct {
+	value string
+}
+
+// getInvalidComponentReason returns a description of why a string
+// cannot be converted to a path.Component. This allows clients that
+// generate names programmatically to determine what needs to be
+// corrected.
+func getInvalidComponentReason(name string) string {
+	switch {
+	case name == "":
+		return "is empty"
+	case name == "." || name == "..":
+		return fmt.Sprintf("may not be %#v", name)
+	case strings.ContainsRune(name, '/'):
+		return "contains a slash"
+	case strings.ContainsRune(name, 0):
+		return "contains a null byte"
+	default:
+		return "is not a valid filename"
+	}
+}
+
+// newOutputBasePath parses an output base ID provided by a client.
+func newOutputBasePath(s string) (outputBasePath, error) {
+	for i, component := range strings.Split(s, "/") {
+		if _, ok := path.NewComponent(component); !ok {
+			return outputBasePath{}, status.Errorf(codes.InvalidArgument, "Output base ID must consist of one or more valid filenames separated by slashes: Component %d %s", i+1, getInvalidComponentReason(component))
+		}
+	}
+	return outputBasePath{value: s}, nil
+}
+
+func (id outputBasePath) String() string {
+	return id.value
+}
+
+// append a single component to the output base ID, yielding the
+// output base ID of a child directory.
+func (id outputBasePath) append(name path.Component) outputBasePath {
+	if id.value == "" {
+		return outputBasePath{value: name.String()}
+	}
+	return outputBasePath{value: id.value + "/" + name.String()}
+}
+
+// getParent returns the output base ID of the directory containing
+// the output path, and the name of the output path within that
+// directory.
+func (id outputBasePath) getParent() (outputBasePath, path.Component) {
+	i := strings.LastIndexByte(id.value, '/')
+	if i < 0 {
+		return outputBasePath{}, path.MustNewComponent(id.value)
+	}
+	return outputBasePath{value: id.value[:i]}, path.MustNewComponent(id.value[i+1:])
+}
+
+// getComponents returns the components of the output base ID.
+func (id outputBasePath) getComponents() []path.Component {
+	var components []path.Component
+	for _, component := range strings.Split(id.value, "/") {
+		components = append(components, path.MustNewComponent(component))
+	}
+	return components
+}
+
+// getFlattenedName returns a single filename that corresponds to the
+// output base ID, which is provided to OutputPathFactory. This
+// ensures that persistent state of output paths can continue to be
+// stored in a single directory.
+//
+// For output base IDs consisting of a single component, the component
+// is returned as is. This keeps persistent state created by older
+// versions of bb_clientd accessible. For other output base IDs, the
+// components are percent-encoded and joined using "%2F".
+func (id outputBasePath) getFlattenedName() path.Component {
+	components := strings.Split(id.value, "/")
+	if len(components) == 1 {
+		return path.MustNewComponent(id.value)
+	}
+	for i, component := range components {
+		components[i] = strings.ReplaceAll(component, "%", "%25")
+	}
+	return path.MustNewComponent(strings.Join(components, "%2F"))
+}
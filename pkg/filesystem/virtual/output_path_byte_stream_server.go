@@ -0,0 +1,224 @@
+package virtual
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/virtual"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/remoteoutputservice"
+	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/genproto/googleapis/bytestream"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// outputPathResourceNamePrefix is the prefix of ByteStream resource
+// names that refer to files in output paths, as opposed to objects in
+// the Content Addressable Storage. Resource names have the form
+// "outputs/${build_id}/${path}".
+const outputPathResourceNamePrefix = "outputs/"
+
+type outputPathByteStreamServer struct {
+	bytestream.ByteStreamServer
+	directory     *RemoteOutputServiceDirectory
+	readChunkSize int
+}
+
+// NewOutputPathByteStreamServer creates a decorator for
+// ByteStreamServer that permits reading files stored in output paths.
+// Read() requests whose resource name has the form
+// "outputs/${build_id}/${path}" are handled by resolving the path
+// relative to the root of the output path of the running build, in the
+// same way as BatchStat() does. All other requests are forwarded to the
+// base server.
+//
+// This allows clients that don't have access to the virtual file
+// system to read the contents of output files over gRPC. Files that
+// are backed by the Content Addressable Storage are loaded from it on
+// demand.
+func NewOutputPathByteStreamServer(base bytestream.ByteStreamServer, directory *RemoteOutputServiceDirectory, readChunkSize int) bytestream.ByteStreamServer {
+	return &outputPathByteStreamServer{
+		ByteStreamServer: base,
+		directory:        directory,
+		readChunkSize:    readChunkSize,
+	}
+}
+
+func (s *outputPathByteStreamServer) Read(request *bytestream.ReadRequest, server bytestream.ByteStream_ReadServer) error {
+	resourceName, ok := strings.CutPrefix(request.ResourceName, outputPathResourceNamePrefix)
+	if !ok {
+		return s.ByteStreamServer.Read(request, server)
+	}
+	buildID, filePath, ok := strings.Cut(resourceName, "/")
+	if !ok {
+		return status.Errorf(codes.InvalidArgument, "Resource name %#v does not have the form \"outputs/${build_id}/${path}\"", request.ResourceName)
+	}
+	if request.ReadOffset < 0 {
+		return status.Errorf(codes.InvalidArgument, "Negative read offset: %d", request.ReadOffset)
+	}
+	if request.ReadLimit < 0 {
+		return status.Errorf(codes.InvalidArgument, "Negative read limit: %d", request.ReadLimit)
+	}
+
+	leaf, err := s.directory.lookupFile(server.Context(), buildID, filePath)
+	if err != nil {
+		return err
+	}
+	return readLeaf(server.Context(), leaf, uint64(request.ReadOffset), uint64(request.ReadLimit), s.readChunkSize, 1, func(data []byte) error {
+		return server.Send(&bytestream.ReadResponse{Data: data})
+	})
+}
+
+// lookupFile resolves a path within the output path of a running
+// build, returning the regular file to which it refers. Symbolic links
+// are followed, as long as they don't point to a location outside the
+// output path.
+func (d *RemoteOutputServiceDirectory) lookupFile(ctx context.Context, buildID, filePath string) (virtual.NativeLeaf, error) {
+	outputPathState, buildState, err := d.getOutputPathAndBuildState(buildID)
+	if err != nil {
+		return nil, err
+	}
+
+	statWalker := statWalker{
+		followSymlinks: true,
+		symlinksLeft:   d.maximumSymlinkRedirections,
+		stack:          util.NewNonEmptyStack[virtual.PrepopulatedDirectory](outputPathState.rootDirectory),
+		depthLimit:     newPathDepthLimit(d.maximumPathDepth),
+		fileStatus: &remoteoutputservice.FileStatus{
+			FileType: &remoteoutputservice.FileStatus_External_{},
+		},
+	}
+	resolvedPath, scopeWalker := path.EmptyBuilder.Join(
+		buildState.scopeWalkerFactory.New(&statWalker))
+	if err := path.Resolve(filePath, scopeWalker); err == syscall.ENOENT {
+		return nil, status.Errorf(codes.NotFound, "Path %#v does not exist", filePath)
+	} else if err != nil {
+		return nil, util.StatusWrapf(d.syscallErrorMapping.convert(err), "Failed to resolve path %#v beyond %#v", filePath, resolvedPath.String())
+	}
+
+	if statWalker.unsupportedFileType {
+		return nil, status.Errorf(codes.InvalidArgument, "Path %#v does not resolve to a regular file", filePath)
+	}
+	switch statWalker.fileStatus.FileType.(type) {
+	case *remoteoutputservice.FileStatus_File_:
+		return statWalker.leaf, nil
+	case *remoteoutputservice.FileStatus_Directory_:
+		return nil, status.Errorf(codes.InvalidArgument, "Path %#v resolves to a directory", filePath)
+	case *remoteoutputservice.FileStatus_External_:
+		return nil, status.Errorf(codes.InvalidArgument, "Path %#v resolves to %#v, which is outside the output path", filePath, resolvedPath.String())
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "Path %#v does not resolve to a regular file", filePath)
+	}
+}
+
+// readLeaf reads the contents of a file in chunks, starting at a given
+// offset. If the read limit is non-zero, no more than the provided
+// number of bytes are read.
+//
+// If readConcurrency is greater than one, up to that many chunks are
+// read in parallel. Chunks are still passed to send() in order, meaning
+// that no more than readConcurrency chunks are buffered.
+func readLeaf(ctx context.Context, leaf virtual.NativeLeaf, readOffset, readLimit uint64, readChunkSize, readConcurrency int, send func([]byte) error) error {
+	var attributes virtual.Attributes
+	if s := leaf.VirtualOpenSelf(ctx, virtual.ShareMaskRead, &virtual.OpenExistingOptions{}, virtual.AttributesMaskSizeBytes, &attributes); s != virtual.StatusOK {
+		if s == virtual.StatusErrStale {
+			return status.Error(codes.NotFound, "File was removed while being opened")
+		}
+		return status.Errorf(codes.FailedPrecondition, "Failed to open file with status %d", s)
+	}
+	defer leaf.VirtualClose(virtual.ShareMaskRead)
+
+	sizeBytes, ok := attributes.GetSizeBytes()
+	if !ok {
+		panic("File did not provide a size, even though it was requested")
+	}
+	if readOffset > sizeBytes {
+		return status.Errorf(codes.OutOfRange, "Read offset %d exceeds the file size of %d bytes", readOffset, sizeBytes)
+	}
+	endOffset := sizeBytes
+	if readLimit > 0 && readLimit < sizeBytes-readOffset {
+		endOffset = readOffset + readLimit
+	}
+
+	if readConcurrency < 1 {
+		readConcurrency = 1
+	}
+	reads := make([]leafChunkRead, readConcurrency)
+	for readOffset < endOffset {
+		if err := ctx.Err(); err != nil {
+			return util.StatusFromContext(ctx)
+		}
+
+		// Determine which chunks to read next.
+		pendingReads := reads[:0]
+		for offset := readOffset; offset < endOffset && len(pendingReads) < readConcurrency; {
+			r := &reads[len(pendingReads)]
+			if r.buf == nil {
+				r.buf = make([]byte, readChunkSize)
+			}
+			r.chunk = r.buf
+			if remaining := endOffset - offset; uint64(len(r.chunk)) > remaining {
+				r.chunk = r.chunk[:remaining]
+			}
+			r.offset = offset
+			pendingReads = pendingReads[:len(pendingReads)+1]
+			offset += uint64(len(r.chunk))
+		}
+
+		if len(pendingReads) == 1 {
+			pendingReads[0].read(leaf)
+		} else {
+			var wg sync.WaitGroup
+			for i := range pendingReads {
+				wg.Add(1)
+				go func(r *leafChunkRead) {
+					r.read(leaf)
+					wg.Done()
+				}(&pendingReads[i])
+			}
+			wg.Wait()
+		}
+
+		// Send the chunks in order. If a read returned less data
+		// than requested, any chunks that follow it are
+		// discarded, as they were read at the wrong offset.
+		for _, r := range pendingReads {
+			if r.status != virtual.StatusOK {
+				return status.Errorf(codes.Internal, "Failed to read file at offset %d", r.offset)
+			}
+			if r.n > 0 {
+				if err := send(r.chunk[:r.n]); err != nil {
+					return err
+				}
+				readOffset += uint64(r.n)
+			}
+			if r.eof || r.n == 0 {
+				return nil
+			}
+			if r.n < len(r.chunk) {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// leafChunkRead holds the state of a single read performed by
+// readLeaf().
+type leafChunkRead struct {
+	buf    []byte
+	chunk  []byte
+	offset uint64
+
+	n      int
+	eof    bool
+	status virtual.Status
+}
+
+func (r *leafChunkRead) read(leaf virtual.NativeLeaf) {
+	r.n, r.eof, r.status = leaf.VirtualRead(r.chunk, r.offset)
+}
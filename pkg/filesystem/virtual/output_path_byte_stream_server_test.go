@@ -0,0 +1,161 @@
+package virtual_test
+
+import (
+	"context"
+	"testing"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-clientd/internal/mock"
+	"github.com/buildbarn/bb-clientd/pkg/filesystem/virtual"
+	cd_testutil "github.com/buildbarn/bb-clientd/pkg/filesystem/virtual/testutil"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/remoteoutputservice"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/genproto/googleapis/bytestream"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestOutputPathByteStreamServerRead(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	contentAddressableStorage := cd_testutil.NewFakeContentAddressableStorage()
+	directory := cd_testutil.NewInMemoryRemoteOutputServiceDirectory(contentAddressableStorage)
+	baseServer := mock.NewMockByteStreamServer(ctrl)
+	byteStreamServer := virtual.NewOutputPathByteStreamServer(baseServer, directory, 3)
+
+	_, err := directory.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		DigestFunction:   remoteexecution.DigestFunction_SHA256,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+
+	fileDigest := contentAddressableStorage.PutBlob(
+		digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256),
+		[]byte("Hello"))
+	_, err = directory.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+		BuildId:    "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		PathPrefix: "bazel-out",
+		Files: []*remoteexecution.OutputFile{
+			{
+				Path:   "hello.txt",
+				Digest: fileDigest.GetProto(),
+			},
+		},
+		Symlinks: []*remoteexecution.OutputSymlink{
+			{
+				Path:   "link.txt",
+				Target: "hello.txt",
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	t.Run("ForwardedToBase", func(t *testing.T) {
+		// Requests for objects in the Content Addressable
+		// Storage should be forwarded.
+		server := mock.NewMockByteStreamReadServer(ctrl)
+		request := &bytestream.ReadRequest{
+			ResourceName: "blobs/185f8db32271fe25f561a6fc938b2e264306ec304eda518007d1764826381969/5",
+		}
+		baseServer.EXPECT().Read(request, server).Return(status.Error(codes.NotFound, "Blob not found"))
+
+		testutil.RequireEqualStatus(
+			t,
+			status.Error(codes.NotFound, "Blob not found"),
+			byteStreamServer.Read(request, server))
+	})
+
+	t.Run("InvalidResourceName", func(t *testing.T) {
+		server := mock.NewMockByteStreamReadServer(ctrl)
+
+		testutil.RequireEqualStatus(
+			t,
+			status.Error(codes.InvalidArgument, "Resource name \"outputs/37f5dbef-b117-4fb6-bce8-5c147cb603b4\" does not have the form \"outputs/${build_id}/${path}\""),
+			byteStreamServer.Read(&bytestream.ReadRequest{
+				ResourceName: "outputs/37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			}, server))
+	})
+
+	t.Run("UnknownBuild", func(t *testing.T) {
+		server := mock.NewMockByteStreamReadServer(ctrl)
+		server.EXPECT().Context().Return(ctx).AnyTimes()
+
+		testutil.RequireEqualStatus(
+			t,
+			status.Error(codes.FailedPrecondition, "Build ID is not associated with any running build"),
+			byteStreamServer.Read(&bytestream.ReadRequest{
+				ResourceName: "outputs/0a3c4a01-f2b1-4f4d-9d5f-8e1e1f2b3c4d/bazel-out/hello.txt",
+			}, server))
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		server := mock.NewMockByteStreamReadServer(ctrl)
+		server.EXPECT().Context().Return(ctx).AnyTimes()
+
+		testutil.RequireEqualStatus(
+			t,
+			status.Error(codes.NotFound, "Path \"bazel-out/nonexistent.txt\" does not exist"),
+			byteStreamServer.Read(&bytestream.ReadRequest{
+				ResourceName: "outputs/37f5dbef-b117-4fb6-bce8-5c147cb603b4/bazel-out/nonexistent.txt",
+			}, server))
+	})
+
+	t.Run("Directory", func(t *testing.T) {
+		server := mock.NewMockByteStreamReadServer(ctrl)
+		server.EXPECT().Context().Return(ctx).AnyTimes()
+
+		testutil.RequireEqualStatus(
+			t,
+			status.Error(codes.InvalidArgument, "Path \"bazel-out\" resolves to a directory"),
+			byteStreamServer.Read(&bytestream.ReadRequest{
+				ResourceName: "outputs/37f5dbef-b117-4fb6-bce8-5c147cb603b4/bazel-out",
+			}, server))
+	})
+
+	t.Run("OutOfRange", func(t *testing.T) {
+		server := mock.NewMockByteStreamReadServer(ctrl)
+		server.EXPECT().Context().Return(ctx).AnyTimes()
+
+		testutil.RequireEqualStatus(
+			t,
+			status.Error(codes.OutOfRange, "Read offset 6 exceeds the file size of 5 bytes"),
+			byteStreamServer.Read(&bytestream.ReadRequest{
+				ResourceName: "outputs/37f5dbef-b117-4fb6-bce8-5c147cb603b4/bazel-out/hello.txt",
+				ReadOffset:   6,
+			}, server))
+	})
+
+	t.Run("FullRead", func(t *testing.T) {
+		// Files should be returned in chunks. Symbolic links
+		// should be followed.
+		server := mock.NewMockByteStreamReadServer(ctrl)
+		server.EXPECT().Context().Return(ctx).AnyTimes()
+		gomock.InOrder(
+			server.EXPECT().Send(testutil.EqProto(t, &bytestream.ReadResponse{Data: []byte("Hel")})),
+			server.EXPECT().Send(testutil.EqProto(t, &bytestream.ReadResponse{Data: []byte("lo")})))
+
+		require.NoError(t, byteStreamServer.Read(&bytestream.ReadRequest{
+			ResourceName: "outputs/37f5dbef-b117-4fb6-bce8-5c147cb603b4/bazel-out/link.txt",
+		}, server))
+	})
+
+	t.Run("RangeRead", func(t *testing.T) {
+		server := mock.NewMockByteStreamReadServer(ctrl)
+		server.EXPECT().Context().Return(ctx).AnyTimes()
+		gomock.InOrder(
+			server.EXPECT().Send(testutil.EqProto(t, &bytestream.ReadResponse{Data: []byte("ell")})),
+			server.EXPECT().Send(testutil.EqProto(t, &bytestream.ReadResponse{Data: []byte("o")})))
+
+		require.NoError(t, byteStreamServer.Read(&bytestream.ReadRequest{
+			ResourceName: "outputs/37f5dbef-b117-4fb6-bce8-5c147cb603b4/bazel-out/hello.txt",
+			ReadOffset:   1,
+			ReadLimit:    4,
+		}, server))
+	})
+}
@@ -0,0 +1,117 @@
+package virtual
+
+import (
+	"sync"
+
+	"github.com/buildbarn/bb-clientd/pkg/proto/outputpaths"
+	"github.com/buildbarn/bb-storage/pkg/clock"
+	"github.com/buildbarn/bb-storage/pkg/util"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var (
+	outputPathErrorsPrometheusMetrics sync.Once
+
+	outputPathErrorsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "buildbarn",
+			Subsystem: "clientd",
+			Name:      "output_path_errors_total",
+			Help:      "Number of errors that occurred while loading the contents of output paths.",
+		},
+		[]string{"output_base_id"})
+)
+
+// OutputPathErrorLoggerFactory is called by
+// RemoteOutputServiceDirectory every time an output path is created,
+// to obtain the ErrorLogger to which errors are forwarded that occur
+// while lazily loading the contents of the output path. This permits
+// routing errors of different output bases to different destinations.
+type OutputPathErrorLoggerFactory interface {
+	NewErrorLogger(outputBaseID string) util.ErrorLogger
+}
+
+// maximumOutputPathErrors is the maximum number of errors that are
+// retained per output path. Older errors are discarded if this limit
+// is exceeded, so that memory usage remains bounded if clients never
+// call GetOutputPathErrors().
+const maximumOutputPathErrors = 100
+
+// outputPathErrorLogger is an implementation of ErrorLogger that is
+// used by output paths created by RemoteOutputServiceDirectory. In
+// addition to forwarding errors to a base ErrorLogger, it retains the
+// most recent errors, so that they may be returned to clients through
+// GetOutputPathErrors(). Errors are also counted through Prometheus
+// metrics.
+type outputPathErrorLogger struct {
+	base    util.ErrorLogger
+	clock   clock.Clock
+	counter prometheus.Counter
+
+	lock                 sync.Mutex
+	errors               []*outputpaths.OutputPathError
+	discardedErrorsCount uint64
+}
+
+func newOutputPathErrorLogger(base util.ErrorLogger, clock clock.Clock, outputBaseIDLabel string) *outputPathErrorLogger {
+	outputPathErrorsPrometheusMetrics.Do(func() {
+		prometheus.MustRegister(outputPathErrorsTotal)
+	})
+
+	return &outputPathErrorLogger{
+		base:    base,
+		clock:   clock,
+		counter: outputPathErrorsTotal.WithLabelValues(outputBaseIDLabel),
+	}
+}
+
+func (el *outputPathErrorLogger) Log(err error) {
+	el.counter.Inc()
+
+	// Errors caused by failing fetches against the Content
+	// Addressable Storage are prefixed with the type of object, so
+	// that they can be told apart in logs.
+	fetchedObjectType := getFetchedObjectType(err)
+	switch fetchedObjectType {
+	case outputpaths.FetchedObjectType_BLOB:
+		el.base.Log(util.StatusWrap(err, "Failed to fetch blob"))
+	case outputpaths.FetchedObjectType_TREE:
+		el.base.Log(util.StatusWrap(err, "Failed to fetch tree"))
+	default:
+		el.base.Log(err)
+	}
+
+	outputPathError := &outputpaths.OutputPathError{
+		Time:              timestamppb.New(el.clock.Now()),
+		Status:            status.Convert(err).Proto(),
+		FetchedObjectType: fetchedObjectType,
+	}
+
+	el.lock.Lock()
+	defer el.lock.Unlock()
+
+	if len(el.errors) >= maximumOutputPathErrors {
+		copy(el.errors, el.errors[1:])
+		el.errors = el.errors[:len(el.errors)-1]
+		el.discardedErrorsCount++
+	}
+	el.errors = append(el.errors, outputPathError)
+}
+
+// drain returns all errors retained by the error logger, and clears
+// them.
+func (el *outputPathErrorLogger) drain() *outputpaths.GetOutputPathErrorsResponse {
+	el.lock.Lock()
+	defer el.lock.Unlock()
+
+	response := &outputpaths.GetOutputPathErrorsResponse{
+		Errors:               el.errors,
+		DiscardedErrorsCount: el.discardedErrorsCount,
+	}
+	el.errors = nil
+	el.discardedErrorsCount = 0
+	return response
+}
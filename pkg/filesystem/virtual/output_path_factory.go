@@ -19,6 +19,11 @@ type OutputPath interface {
 	// Implementations of OutputPath may use this method to persist
 	// state.
 	FinalizeBuild(ctx context.Context, digestFunction digest.Function)
+
+	// Rename() is called when the output base ID of the output path
+	// is changed. Implementations of OutputPath that persist state
+	// should associate it with the new output base ID.
+	Rename(outputBaseID path.Component) error
 }
 
 // OutputPathFactory is an interface that is invoked by
@@ -30,6 +35,13 @@ type OutputPathFactory interface {
 	// was cleaned previously.
 	StartInitialBuild(outputBaseID path.Component, casFileFactory virtual.CASFileFactory, digestFunction digest.Function, errorLogger util.ErrorLogger) OutputPath
 
+	// HasPersistentState() returns whether persistent state is
+	// associated with an output base ID that hasn't been observed
+	// before. RemoteOutputServiceDirectory may use this to expose
+	// the results of a previous build prior to StartBuild() being
+	// called, by calling StartInitialBuild() right away.
+	HasPersistentState(outputBaseID path.Component) bool
+
 	// Clean() is called when the RemoteOutputServiceDirectory
 	// service is instructed to clean an output path that is not yet
 	// managed by the RemoteOutputServiceDirectory. This may occur
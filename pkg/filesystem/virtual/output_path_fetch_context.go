@@ -0,0 +1,112 @@
+package virtual
+
+import (
+	"context"
+	"sync"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	re_cas "github.com/buildbarn/bb-remote-execution/pkg/cas"
+	"github.com/buildbarn/bb-storage/pkg/blobstore"
+	"github.com/buildbarn/bb-storage/pkg/blobstore/buffer"
+	"github.com/buildbarn/bb-storage/pkg/blobstore/slicing"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+)
+
+// outputPathFetchContext holds the context that is used to fetch the
+// contents of files and directories of an output path from the Content
+// Addressable Storage. As these fetches are performed lazily, they
+// can't use the context of a gRPC request.
+//
+// The context can be cancelled by AbortBuild(), causing fetches that
+// are in flight to fail. Fetches that are started afterwards use a new
+// context.
+type outputPathFetchContext struct {
+	lock   sync.Mutex
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func newOutputPathFetchContext() *outputPathFetchContext {
+	fc := &outputPathFetchContext{}
+	fc.ctx, fc.cancel = context.WithCancel(context.Background())
+	return fc
+}
+
+func (fc *outputPathFetchContext) get() context.Context {
+	fc.lock.Lock()
+	defer fc.lock.Unlock()
+	return fc.ctx
+}
+
+// cancelFetches cancels all fetches that are in flight.
+func (fc *outputPathFetchContext) cancelFetches() {
+	fc.lock.Lock()
+	cancel := fc.cancel
+	fc.ctx, fc.cancel = context.WithCancel(context.Background())
+	fc.lock.Unlock()
+	cancel()
+}
+
+// bind returns a context that is cancelled if either the provided
+// context or the current fetch context is cancelled. The returned
+// function must be called once the context is no longer used.
+func (fc *outputPathFetchContext) bind(ctx context.Context) (context.Context, context.CancelFunc) {
+	fetchCtx := fc.get()
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-fetchCtx.Done():
+			cancel()
+		case <-done:
+		}
+	}()
+	return ctx, func() {
+		close(done)
+		cancel()
+	}
+}
+
+// fetchContextBlobAccess is a decorator for BlobAccess that lets Get()
+// and GetFromComposite() calls use the fetch context of an output path.
+// It is used to read files in an output path. As the returned buffers
+// are read lazily, the context provided by the CAS file factory is
+// replaced, as opposed to being bound to the fetch context.
+type fetchContextBlobAccess struct {
+	blobstore.BlobAccess
+	fetchContext *outputPathFetchContext
+}
+
+func (ba *fetchContextBlobAccess) Get(ctx context.Context, digest digest.Digest) buffer.Buffer {
+	return ba.BlobAccess.Get(ba.fetchContext.get(), digest)
+}
+
+func (ba *fetchContextBlobAccess) GetFromComposite(ctx context.Context, parentDigest, childDigest digest.Digest, slicer slicing.BlobSlicer) buffer.Buffer {
+	return ba.BlobAccess.GetFromComposite(ba.fetchContext.get(), parentDigest, childDigest, slicer)
+}
+
+// fetchContextDirectoryFetcher is a decorator for DirectoryFetcher
+// that causes fetches to be cancelled along with the fetch context of
+// an output path.
+type fetchContextDirectoryFetcher struct {
+	base         re_cas.DirectoryFetcher
+	fetchContext *outputPathFetchContext
+}
+
+func (df *fetchContextDirectoryFetcher) GetDirectory(ctx context.Context, directoryDigest digest.Digest) (*remoteexecution.Directory, error) {
+	ctx, cancel := df.fetchContext.bind(ctx)
+	defer cancel()
+	return df.base.GetDirectory(ctx, directoryDigest)
+}
+
+func (df *fetchContextDirectoryFetcher) GetTreeRootDirectory(ctx context.Context, treeDigest digest.Digest) (*remoteexecution.Directory, error) {
+	ctx, cancel := df.fetchContext.bind(ctx)
+	defer cancel()
+	return df.base.GetTreeRootDirectory(ctx, treeDigest)
+}
+
+func (df *fetchContextDirectoryFetcher) GetTreeChildDirectory(ctx context.Context, treeDigest, childDigest digest.Digest) (*remoteexecution.Directory, error) {
+	ctx, cancel := df.fetchContext.bind(ctx)
+	defer cancel()
+	return df.base.GetTreeChildDirectory(ctx, treeDigest, childDigest)
+}
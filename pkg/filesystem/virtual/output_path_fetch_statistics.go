@@ -0,0 +1,500 @@
+package virtual
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-clientd/pkg/proto/outputpaths"
+	re_cas "github.com/buildbarn/bb-remote-execution/pkg/cas"
+	"github.com/buildbarn/bb-storage/pkg/blobstore"
+	"github.com/buildbarn/bb-storage/pkg/blobstore/buffer"
+	"github.com/buildbarn/bb-storage/pkg/blobstore/slicing"
+	"github.com/buildbarn/bb-storage/pkg/clock"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/util"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+var (
+	outputPathFetchPrometheusMetrics sync.Once
+
+	outputPathFetchDurationSecondsBuckets = util.DecimalExponentialBuckets(-3, 6, 2)
+	outputPathFetchDurationSeconds        = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "buildbarn",
+			Subsystem: "clientd",
+			Name:      "output_path_fetch_duration_seconds",
+			Help:      "Amount of time spent fetching objects from the Content Addressable Storage on behalf of output paths, in seconds.",
+			Buckets:   outputPathFetchDurationSecondsBuckets,
+		},
+		[]string{"output_base_id", "object_type"})
+
+	outputPathFetchFailuresTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "buildbarn",
+			Subsystem: "clientd",
+			Name:      "output_path_fetch_failures_total",
+			Help:      "Number of fetches against the Content Addressable Storage on behalf of output paths that failed.",
+		},
+		[]string{"output_base_id", "object_type"})
+
+	outputPathFetchedBytesTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "buildbarn",
+			Subsystem: "clientd",
+			Name:      "output_path_fetched_bytes_total",
+			Help:      "Number of bytes fetched from the Content Addressable Storage on behalf of output paths.",
+		},
+		[]string{"output_base_id", "object_type"})
+
+	outputPathQueuedFetches = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "buildbarn",
+			Subsystem: "clientd",
+			Name:      "output_path_queued_fetches",
+			Help:      "Number of fetches against the Content Addressable Storage on behalf of output paths that are waiting for other fetches to complete.",
+		},
+		[]string{"output_base_id", "object_type"})
+
+	outputPathLastValidationTimeSeconds = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "buildbarn",
+			Subsystem: "clientd",
+			Name:      "output_path_last_validation_time_seconds",
+			Help:      "Time at which the contents of output paths were last checked for existence in the Content Addressable Storage, in seconds since the Epoch.",
+		},
+		[]string{"output_base_id"})
+)
+
+func registerOutputPathFetchPrometheusMetrics() {
+	outputPathFetchPrometheusMetrics.Do(func() {
+		prometheus.MustRegister(outputPathFetchDurationSeconds)
+		prometheus.MustRegister(outputPathFetchFailuresTotal)
+		prometheus.MustRegister(outputPathFetchedBytesTotal)
+		prometheus.MustRegister(outputPathQueuedFetches)
+		prometheus.MustRegister(outputPathLastValidationTimeSeconds)
+	})
+}
+
+// otherOutputBaseIDLabel is the value of the "output_base_id" label
+// that is used for output bases whose output base ID is not part of
+// the allowlist provided to NewRemoteOutputServiceDirectory().
+const otherOutputBaseIDLabel = "other"
+
+// outputPathFetchStatistics tracks the latency of fetches against the
+// Content Addressable Storage that are performed on behalf of a
+// single output path, so that they can be returned to clients through
+// GetOutputPathStats().
+type outputPathFetchStatistics struct {
+	blob fetchLatencyDistribution
+	tree fetchLatencyDistribution
+
+	blobFailures fetchFailureCounter
+	treeFailures fetchFailureCounter
+
+	blobBytesFetched fetchedBytesCounter
+	treeBytesFetched fetchedBytesCounter
+
+	// The total number of bytes fetched on behalf of the output
+	// path, regardless of the type of object. Builds record the
+	// value of this counter when started, so that the number of
+	// bytes fetched during the build can be computed.
+	bytesFetched atomic.Uint64
+
+	// The total number of fetches that failed, regardless of the
+	// type of object.
+	failures atomic.Uint64
+
+	// Fetches that are currently in progress.
+	inFlight inFlightFetches
+}
+
+// newOutputPathFetchStatistics creates statistics for a single output
+// path. Fetches are also recorded in the provided aggregate statistics,
+// so that GetDaemonStats() can report them without iterating over all
+// output paths.
+func newOutputPathFetchStatistics(outputBaseIDLabel string, aggregate *outputPathFetchStatistics) *outputPathFetchStatistics {
+	registerOutputPathFetchPrometheusMetrics()
+
+	s := &outputPathFetchStatistics{
+		blob: newFetchLatencyDistribution(outputPathFetchDurationSeconds.WithLabelValues(outputBaseIDLabel, "Blob")),
+		tree: newFetchLatencyDistribution(outputPathFetchDurationSeconds.WithLabelValues(outputBaseIDLabel, "Tree")),
+	}
+	s.blobFailures = fetchFailureCounter{
+		counter: outputPathFetchFailuresTotal.WithLabelValues(outputBaseIDLabel, "Blob"),
+		total:   &s.failures,
+	}
+	s.treeFailures = fetchFailureCounter{
+		counter: outputPathFetchFailuresTotal.WithLabelValues(outputBaseIDLabel, "Tree"),
+		total:   &s.failures,
+	}
+	s.blobBytesFetched = fetchedBytesCounter{
+		counter: outputPathFetchedBytesTotal.WithLabelValues(outputBaseIDLabel, "Blob"),
+		total:   &s.bytesFetched,
+	}
+	s.treeBytesFetched = fetchedBytesCounter{
+		counter: outputPathFetchedBytesTotal.WithLabelValues(outputBaseIDLabel, "Tree"),
+		total:   &s.bytesFetched,
+	}
+	s.blob.aggregate = &aggregate.blob
+	s.tree.aggregate = &aggregate.tree
+	return s
+}
+
+// newAggregateFetchStatistics creates statistics that are shared by all
+// output paths. Unlike the statistics of individual output paths, they
+// are not reset when output paths are cleaned.
+func newAggregateFetchStatistics() *outputPathFetchStatistics {
+	return &outputPathFetchStatistics{
+		blob: newFetchLatencyDistribution(nil),
+		tree: newFetchLatencyDistribution(nil),
+	}
+}
+
+// newQueuedFetchesGauge returns a Prometheus gauge that can be used to
+// track the number of fetches on behalf of an output path that are
+// blocked, because the maximum number of concurrent fetches has been
+// reached.
+func newQueuedFetchesGauge(outputBaseIDLabel, objectType string) prometheus.Gauge {
+	registerOutputPathFetchPrometheusMetrics()
+
+	return outputPathQueuedFetches.WithLabelValues(outputBaseIDLabel, objectType)
+}
+
+// newLastValidationTimeGauge returns a Prometheus gauge that can be
+// used to track when filterMissingChildren() last completed
+// successfully against an output path. Operators may use this to alert
+// on output paths that have not been validated for a long time, as
+// these are more likely to reference objects that have been evicted
+// from the Content Addressable Storage.
+func newLastValidationTimeGauge(outputBaseIDLabel string) prometheus.Gauge {
+	registerOutputPathFetchPrometheusMetrics()
+
+	return outputPathLastValidationTimeSeconds.WithLabelValues(outputBaseIDLabel)
+}
+
+// getBytesFetched returns the total number of bytes fetched from the
+// Content Addressable Storage on behalf of the output path.
+func (s *outputPathFetchStatistics) getBytesFetched() uint64 {
+	return s.bytesFetched.Load()
+}
+
+// getFailures returns the total number of fetches against the Content
+// Addressable Storage on behalf of the output path that failed.
+func (s *outputPathFetchStatistics) getFailures() uint64 {
+	return s.failures.Load()
+}
+
+func (s *outputPathFetchStatistics) getProto() *outputpaths.GetOutputPathStatsResponse {
+	return &outputpaths.GetOutputPathStatsResponse{
+		BlobFetchLatency: s.blob.getProto(),
+		TreeFetchLatency: s.tree.getProto(),
+	}
+}
+
+// fetchLatencyDistribution keeps track of the number of fetches whose
+// duration falls within each of the buckets of the corresponding
+// Prometheus histogram. Unlike the Prometheus histogram, it is not
+// shared between output paths. This allows percentiles to be
+// estimated for individual output paths.
+type fetchLatencyDistribution struct {
+	observer  prometheus.Observer
+	aggregate *fetchLatencyDistribution
+
+	lock         sync.Mutex
+	bucketCounts []uint64
+	count        uint64
+}
+
+func newFetchLatencyDistribution(observer prometheus.Observer) fetchLatencyDistribution {
+	return fetchLatencyDistribution{
+		observer: observer,
+		// The final bucket holds all fetches whose duration
+		// exceeds the upper bound of the last bucket.
+		bucketCounts: make([]uint64, len(outputPathFetchDurationSecondsBuckets)+1),
+	}
+}
+
+func (ld *fetchLatencyDistribution) observe(duration time.Duration) {
+	seconds := duration.Seconds()
+	ld.observer.Observe(seconds)
+
+	// Like Prometheus, treat bucket boundaries as inclusive upper
+	// bounds.
+	bucket := sort.SearchFloat64s(outputPathFetchDurationSecondsBuckets, seconds)
+	ld.observeBucket(bucket)
+	if ld.aggregate != nil {
+		ld.aggregate.observeBucket(bucket)
+	}
+}
+
+func (ld *fetchLatencyDistribution) observeBucket(bucket int) {
+	ld.lock.Lock()
+	defer ld.lock.Unlock()
+
+	ld.bucketCounts[bucket]++
+	ld.count++
+}
+
+// getQuantileLocked estimates a quantile of the durations of fetches
+// in the same way as PromQL's histogram_quantile() function, by
+// assuming that fetches are distributed linearly within buckets.
+func (ld *fetchLatencyDistribution) getQuantileLocked(q float64) time.Duration {
+	buckets := outputPathFetchDurationSecondsBuckets
+	rank := q * float64(ld.count)
+	var countBelow uint64
+	for bucket, count := range ld.bucketCounts {
+		if count > 0 && float64(countBelow+count) >= rank {
+			if bucket == len(buckets) {
+				// The upper bound of the final bucket is
+				// infinite. Return its lower bound instead.
+				return time.Duration(buckets[len(buckets)-1] * float64(time.Second))
+			}
+			lowerBound := 0.0
+			if bucket > 0 {
+				lowerBound = buckets[bucket-1]
+			}
+			upperBound := buckets[bucket]
+			seconds := lowerBound + (upperBound-lowerBound)*(rank-float64(countBelow))/float64(count)
+			return time.Duration(seconds * float64(time.Second))
+		}
+		countBelow += count
+	}
+	return 0
+}
+
+func (ld *fetchLatencyDistribution) getProto() *outputpaths.FetchLatencyDistribution {
+	ld.lock.Lock()
+	defer ld.lock.Unlock()
+
+	distribution := &outputpaths.FetchLatencyDistribution{
+		Count: ld.count,
+	}
+	if ld.count > 0 {
+		distribution.P50 = durationpb.New(ld.getQuantileLocked(0.5))
+		distribution.P95 = durationpb.New(ld.getQuantileLocked(0.95))
+		distribution.P99 = durationpb.New(ld.getQuantileLocked(0.99))
+	}
+	return distribution
+}
+
+// fetchedBytesCounter keeps track of the number of bytes fetched from
+// the Content Addressable Storage for a single type of object. Counts
+// are both exported through Prometheus and accumulated in a total that
+// is shared by all types of objects.
+type fetchedBytesCounter struct {
+	counter prometheus.Counter
+	total   *atomic.Uint64
+}
+
+func (c *fetchedBytesCounter) add(sizeBytes int64) {
+	if sizeBytes > 0 {
+		c.counter.Add(float64(sizeBytes))
+		c.total.Add(uint64(sizeBytes))
+	}
+}
+
+// fetchFailureCounter keeps track of the number of fetches of a single
+// type of object that failed. Similar to fetchedBytesCounter, counts
+// are both exported through Prometheus and accumulated in a total.
+type fetchFailureCounter struct {
+	counter prometheus.Counter
+	total   *atomic.Uint64
+}
+
+func (c *fetchFailureCounter) inc() {
+	c.counter.Inc()
+	c.total.Add(1)
+}
+
+// inFlightFetches keeps track of the number of fetches on behalf of an
+// output path that are in progress. This permits FinalizeBuild() to
+// wait for outstanding fetches to complete.
+type inFlightFetches struct {
+	lock  sync.Mutex
+	count int
+	idle  chan struct{}
+}
+
+func (f *inFlightFetches) start() {
+	f.lock.Lock()
+	f.count++
+	f.lock.Unlock()
+}
+
+func (f *inFlightFetches) done() {
+	f.lock.Lock()
+	f.count--
+	if f.count == 0 && f.idle != nil {
+		close(f.idle)
+		f.idle = nil
+	}
+	f.lock.Unlock()
+}
+
+// wait blocks until no fetches are in progress. As fetches may be
+// started while waiting, this is not guaranteed to terminate on an
+// output path that is accessed continuously.
+func (f *inFlightFetches) wait(ctx context.Context) error {
+	f.lock.Lock()
+	if f.count == 0 {
+		f.lock.Unlock()
+		return nil
+	}
+	if f.idle == nil {
+		f.idle = make(chan struct{})
+	}
+	idle := f.idle
+	f.lock.Unlock()
+
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return util.StatusFromContext(ctx)
+	}
+}
+
+// fetchTimingBlobAccess is a decorator for BlobAccess that records the
+// duration of Get() and GetFromComposite() calls in a
+// fetchLatencyDistribution. It is used to measure the latency of
+// reading files in an output path.
+type fetchTimingBlobAccess struct {
+	blobstore.BlobAccess
+	clock        clock.Clock
+	distribution *fetchLatencyDistribution
+	failures     *fetchFailureCounter
+	bytesFetched *fetchedBytesCounter
+	inFlight     *inFlightFetches
+}
+
+func (ba *fetchTimingBlobAccess) Get(ctx context.Context, digest digest.Digest) buffer.Buffer {
+	ba.inFlight.start()
+	timeStart := ba.clock.Now()
+	return buffer.WithErrorHandler(
+		ba.BlobAccess.Get(ctx, digest),
+		&fetchTimingErrorHandler{
+			blobAccess: ba,
+			timeStart:  timeStart,
+			sizeBytes:  digest.GetSizeBytes(),
+		})
+}
+
+func (ba *fetchTimingBlobAccess) GetFromComposite(ctx context.Context, parentDigest, childDigest digest.Digest, slicer slicing.BlobSlicer) buffer.Buffer {
+	ba.inFlight.start()
+	timeStart := ba.clock.Now()
+	return buffer.WithErrorHandler(
+		ba.BlobAccess.GetFromComposite(ctx, parentDigest, childDigest, slicer),
+		&fetchTimingErrorHandler{
+			blobAccess: ba,
+			timeStart:  timeStart,
+			sizeBytes:  childDigest.GetSizeBytes(),
+		})
+}
+
+// fetchTimingErrorHandler is used by fetchTimingBlobAccess to record
+// the duration of a fetch once its buffer is consumed. The size of
+// the object is only accounted for if the fetch succeeded.
+type fetchTimingErrorHandler struct {
+	blobAccess *fetchTimingBlobAccess
+	timeStart  time.Time
+	sizeBytes  int64
+	failed     bool
+}
+
+func (eh *fetchTimingErrorHandler) OnError(err error) (buffer.Buffer, error) {
+	eh.blobAccess.failures.inc()
+	eh.failed = true
+	return nil, newFetchFailure(err, outputpaths.FetchedObjectType_BLOB)
+}
+
+func (eh *fetchTimingErrorHandler) Done() {
+	eh.blobAccess.distribution.observe(eh.blobAccess.clock.Now().Sub(eh.timeStart))
+	if !eh.failed {
+		eh.blobAccess.bytesFetched.add(eh.sizeBytes)
+	}
+	eh.blobAccess.inFlight.done()
+}
+
+// fetchTimingDirectoryFetcher is a decorator for DirectoryFetcher that
+// records the duration of calls in a fetchLatencyDistribution. It is
+// used to measure the latency of loading directories in an output
+// path.
+//
+// As DirectoryFetcher does not report the size of the objects that
+// were fetched, the number of bytes fetched is approximated by the
+// size of the Directory messages that are returned.
+type fetchTimingDirectoryFetcher struct {
+	base         re_cas.DirectoryFetcher
+	clock        clock.Clock
+	distribution *fetchLatencyDistribution
+	failures     *fetchFailureCounter
+	bytesFetched *fetchedBytesCounter
+	inFlight     *inFlightFetches
+}
+
+func (df *fetchTimingDirectoryFetcher) observe(timeStart time.Time, directory *remoteexecution.Directory, err error) (*remoteexecution.Directory, error) {
+	df.distribution.observe(df.clock.Now().Sub(timeStart))
+	df.inFlight.done()
+	if err != nil {
+		df.failures.inc()
+		return nil, newFetchFailure(err, outputpaths.FetchedObjectType_TREE)
+	}
+	df.bytesFetched.add(int64(proto.Size(directory)))
+	return directory, nil
+}
+
+func (df *fetchTimingDirectoryFetcher) GetDirectory(ctx context.Context, directoryDigest digest.Digest) (*remoteexecution.Directory, error) {
+	df.inFlight.start()
+	timeStart := df.clock.Now()
+	directory, err := df.base.GetDirectory(ctx, directoryDigest)
+	return df.observe(timeStart, directory, err)
+}
+
+func (df *fetchTimingDirectoryFetcher) GetTreeRootDirectory(ctx context.Context, treeDigest digest.Digest) (*remoteexecution.Directory, error) {
+	df.inFlight.start()
+	timeStart := df.clock.Now()
+	directory, err := df.base.GetTreeRootDirectory(ctx, treeDigest)
+	return df.observe(timeStart, directory, err)
+}
+
+func (df *fetchTimingDirectoryFetcher) GetTreeChildDirectory(ctx context.Context, treeDigest, childDigest digest.Digest) (*remoteexecution.Directory, error) {
+	df.inFlight.start()
+	timeStart := df.clock.Now()
+	directory, err := df.base.GetTreeChildDirectory(ctx, treeDigest, childDigest)
+	return df.observe(timeStart, directory, err)
+}
+
+// newFetchFailure annotates an error returned by the Content
+// Addressable Storage with the type of object that was being fetched.
+// As the annotation is stored as a status detail, it is preserved when
+// the error is wrapped. This allows outputPathErrorLogger to determine
+// the type of object once the error is logged.
+func newFetchFailure(err error, objectType outputpaths.FetchedObjectType) error {
+	s, errDetails := status.Convert(err).WithDetails(&outputpaths.FetchFailure{
+		ObjectType: objectType,
+	})
+	if errDetails != nil {
+		return err
+	}
+	return s.Err()
+}
+
+// getFetchedObjectType returns the type of object that was being
+// fetched when an error annotated by newFetchFailure() occurred.
+func getFetchedObjectType(err error) outputpaths.FetchedObjectType {
+	for _, detail := range status.Convert(err).Details() {
+		if fetchFailure, ok := detail.(*outputpaths.FetchFailure); ok {
+			return fetchFailure.ObjectType
+		}
+	}
+	return outputpaths.FetchedObjectType_UNKNOWN
+}
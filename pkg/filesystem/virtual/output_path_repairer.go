@@ -0,0 +1,139 @@
+package virtual
+
+import (
+	"context"
+	"sort"
+	"syscall"
+
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/virtual"
+	"github.com/buildbarn/bb-storage/pkg/blobstore"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+	"github.com/buildbarn/bb-storage/pkg/util"
+)
+
+// outputPathRepairCandidate is a file in an output path that is
+// removed by outputPathRepairer if one of the objects on which it
+// depends is absent from the Content Addressable Storage.
+type outputPathRepairCandidate struct {
+	directory virtual.PrepopulatedDirectory
+	name      path.Component
+	leaf      virtual.NativeLeaf
+	path      *path.Trace
+}
+
+// outputPathRepairer is used by RepairOutputPath() to remove files from
+// an output path whose contents are no longer present in the Content
+// Addressable Storage. Unlike filterMissingChildren(), it traverses
+// into directories, so that it can detect files that were evicted
+// after the directory containing them was validated.
+type outputPathRepairer struct {
+	context                   context.Context
+	contentAddressableStorage blobstore.BlobAccess
+	batchSize                 int
+
+	queue        map[digest.Digest][]outputPathRepairCandidate
+	removed      map[string]struct{}
+	removedPaths []string
+}
+
+func newOutputPathRepairer(ctx context.Context, contentAddressableStorage blobstore.BlobAccess, batchSize int) *outputPathRepairer {
+	return &outputPathRepairer{
+		context:                   ctx,
+		contentAddressableStorage: contentAddressableStorage,
+		batchSize:                 batchSize,
+		queue:                     map[digest.Digest][]outputPathRepairCandidate{},
+		removed:                   map[string]struct{}{},
+	}
+}
+
+// repairDirectory queues all files contained in a directory and its
+// subdirectories for existence checking. Directories whose contents
+// have not been loaded yet are loaded from the Content Addressable
+// Storage.
+func (pr *outputPathRepairer) repairDirectory(d virtual.PrepopulatedDirectory, dPath *path.Trace) error {
+	directories, leaves, err := d.LookupAllChildren()
+	if err != nil {
+		return util.StatusWrapf(err, "Failed to look up children of directory %#v", dPath.String())
+	}
+
+	for _, entry := range directories {
+		if err := pr.repairDirectory(entry.Child, dPath.Append(entry.Name)); err != nil {
+			return err
+		}
+	}
+	for _, entry := range leaves {
+		candidate := outputPathRepairCandidate{
+			directory: d,
+			name:      entry.Name,
+			leaf:      entry.Child,
+			path:      dPath.Append(entry.Name),
+		}
+		for _, blobDigest := range entry.Child.GetContainingDigests().Items() {
+			if len(pr.queue) >= pr.batchSize {
+				if err := pr.flush(); err != nil {
+					return err
+				}
+			}
+			pr.queue[blobDigest] = append(pr.queue[blobDigest], candidate)
+		}
+	}
+	return nil
+}
+
+// flush calls FindMissingBlobs() against all queued digests, removing
+// the files that depend on any of the missing objects.
+func (pr *outputPathRepairer) flush() error {
+	if len(pr.queue) == 0 {
+		return nil
+	}
+	set := digest.NewSetBuilder()
+	for blobDigest := range pr.queue {
+		set.Add(blobDigest)
+	}
+	missing, err := pr.contentAddressableStorage.FindMissing(pr.context, set.Build())
+	if err != nil {
+		return util.StatusWrap(err, "Failed to find missing blobs")
+	}
+	for _, blobDigest := range missing.Items() {
+		for _, candidate := range pr.queue[blobDigest] {
+			if err := pr.remove(candidate); err != nil {
+				return err
+			}
+		}
+	}
+	pr.queue = map[digest.Digest][]outputPathRepairCandidate{}
+	return nil
+}
+
+// remove a single file from the output path. As the output path may be
+// modified while it is being repaired, the file is only removed if it
+// is still present under the same name.
+func (pr *outputPathRepairer) remove(candidate outputPathRepairCandidate) error {
+	candidatePath := candidate.path.String()
+	if _, ok := pr.removed[candidatePath]; ok {
+		return nil
+	}
+	child, err := candidate.directory.LookupChild(candidate.name)
+	if err == syscall.ENOENT {
+		return nil
+	} else if err != nil {
+		return util.StatusWrapf(err, "Failed to look up file %#v", candidatePath)
+	}
+	if _, leaf := child.GetPair(); leaf != candidate.leaf {
+		return nil
+	}
+	if err := candidate.directory.Remove(candidate.name); err != nil && err != syscall.ENOENT {
+		return util.StatusWrapf(err, "Failed to remove file %#v", candidatePath)
+	}
+	pr.removed[candidatePath] = struct{}{}
+	pr.removedPaths = append(pr.removedPaths, candidatePath)
+	return nil
+}
+
+// getRemovedPaths returns the paths of all files that were removed, in
+// sorted order.
+func (pr *outputPathRepairer) getRemovedPaths() []string {
+	sort.Strings(pr.removedPaths)
+	return pr.removedPaths
+}
@@ -0,0 +1,109 @@
+package virtual
+
+import (
+	"strings"
+
+	re_cas "github.com/buildbarn/bb-remote-execution/pkg/cas"
+	"github.com/buildbarn/bb-storage/pkg/blobstore"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/util"
+)
+
+// OutputPathStorage contains the backends that are used by a single
+// output path to access the Content Addressable Storage.
+type OutputPathStorage struct {
+	// Used to check for the existence of objects, and to upload
+	// the contents of the output path.
+	BareContentAddressableStorage blobstore.BlobAccess
+
+	// Used to load the contents of files. This backend should
+	// retry in case of errors, as is the case for the
+	// retryingContentAddressableStorage that is provided to
+	// NewRemoteOutputServiceDirectory().
+	RetryingContentAddressableStorage blobstore.BlobAccess
+
+	// Used to load the contents of directories.
+	DirectoryFetcher re_cas.DirectoryFetcher
+}
+
+// OutputPathStorageSelector is called by RemoteOutputServiceDirectory
+// every time an output path is created, to determine which backends
+// the output path should use to access the Content Addressable
+// Storage. This permits isolating the traffic of output paths that
+// belong to different tenants.
+//
+// If no backends are selected, the ones provided to
+// NewRemoteOutputServiceDirectory() are used.
+//
+// The selector is also called when a build is started against an
+// existing output path using a different instance name. As the
+// backends of an output path can't be changed, the build is rejected
+// if other backends are selected. Backends are compared using ==.
+type OutputPathStorageSelector interface {
+	SelectOutputPathStorage(outputBaseID string, instanceName digest.InstanceName) (OutputPathStorage, bool)
+}
+
+// PrefixOutputPathStorageSelector is an implementation of
+// OutputPathStorageSelector that selects backends based on the output
+// base ID and the instance name of the output path. Prefixes of output
+// base IDs take precedence over prefixes of instance names. If multiple
+// prefixes match, the longest one is used.
+type PrefixOutputPathStorageSelector struct {
+	outputBaseIDPrefixes map[string]OutputPathStorage
+	instanceNamePrefixes *digest.InstanceNameTrie
+	instanceNameStorages []OutputPathStorage
+}
+
+var _ OutputPathStorageSelector = (*PrefixOutputPathStorageSelector)(nil)
+
+// NewPrefixOutputPathStorageSelector creates a
+// PrefixOutputPathStorageSelector that does not select any backends.
+// Prefixes may be added by calling AddOutputBaseIDPrefix() and
+// AddInstanceNamePrefix().
+func NewPrefixOutputPathStorageSelector() *PrefixOutputPathStorageSelector {
+	return &PrefixOutputPathStorageSelector{
+		outputBaseIDPrefixes: map[string]OutputPathStorage{},
+		instanceNamePrefixes: digest.NewInstanceNameTrie(),
+	}
+}
+
+// AddOutputBaseIDPrefix causes output paths whose output base ID is
+// equal to or nested below a given prefix to use a set of backends.
+func (s *PrefixOutputPathStorageSelector) AddOutputBaseIDPrefix(prefix string, storage OutputPathStorage) error {
+	if prefix != "" {
+		if _, err := newOutputBasePath(prefix); err != nil {
+			return util.StatusWrapf(err, "Invalid output base ID prefix %#v", prefix)
+		}
+	}
+	s.outputBaseIDPrefixes[prefix] = storage
+	return nil
+}
+
+// AddInstanceNamePrefix causes output paths whose instance name is
+// equal to or nested below a given prefix to use a set of backends.
+func (s *PrefixOutputPathStorageSelector) AddInstanceNamePrefix(prefix digest.InstanceName, storage OutputPathStorage) {
+	s.instanceNamePrefixes.Set(prefix, len(s.instanceNameStorages))
+	s.instanceNameStorages = append(s.instanceNameStorages, storage)
+}
+
+// SelectOutputPathStorage returns the backends associated with the
+// longest matching prefix of the output base ID or the instance name.
+func (s *PrefixOutputPathStorageSelector) SelectOutputPathStorage(outputBaseID string, instanceName digest.InstanceName) (OutputPathStorage, bool) {
+	for prefix := outputBaseID; ; {
+		if storage, ok := s.outputBaseIDPrefixes[prefix]; ok {
+			return storage, true
+		}
+		if prefix == "" {
+			break
+		}
+		if i := strings.LastIndexByte(prefix, '/'); i >= 0 {
+			prefix = prefix[:i]
+		} else {
+			prefix = ""
+		}
+	}
+	if i := s.instanceNamePrefixes.GetLongestPrefix(instanceName); i >= 0 {
+		return s.instanceNameStorages[i], true
+	}
+	return OutputPathStorage{}, false
+}
@@ -0,0 +1,90 @@
+package virtual_test
+
+import (
+	"testing"
+
+	"github.com/buildbarn/bb-clientd/internal/mock"
+	cd_vfs "github.com/buildbarn/bb-clientd/pkg/filesystem/virtual"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestPrefixOutputPathStorageSelector(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	newStorage := func() cd_vfs.OutputPathStorage {
+		return cd_vfs.OutputPathStorage{
+			BareContentAddressableStorage:     mock.NewMockBlobAccess(ctrl),
+			RetryingContentAddressableStorage: mock.NewMockBlobAccess(ctrl),
+			DirectoryFetcher:                  mock.NewMockDirectoryFetcher(ctrl),
+		}
+	}
+	tenantStorage := newStorage()
+	tenantTeamStorage := newStorage()
+	instanceNameStorage := newStorage()
+	nestedInstanceNameStorage := newStorage()
+
+	selector := cd_vfs.NewPrefixOutputPathStorageSelector()
+	require.NoError(t, selector.AddOutputBaseIDPrefix("tenant", tenantStorage))
+	require.NoError(t, selector.AddOutputBaseIDPrefix("tenant/team", tenantTeamStorage))
+	selector.AddInstanceNamePrefix(digest.MustNewInstanceName("hello"), instanceNameStorage)
+	selector.AddInstanceNamePrefix(digest.MustNewInstanceName("hello/world"), nestedInstanceNameStorage)
+
+	t.Run("InvalidOutputBaseIDPrefix", func(t *testing.T) {
+		testutil.RequireEqualStatus(
+			t,
+			status.Error(codes.InvalidArgument, "Invalid output base ID prefix \"tenant//team\": Output base ID must consist of one or more valid filenames separated by slashes: Component 2 is empty"),
+			selector.AddOutputBaseIDPrefix("tenant//team", newStorage()))
+	})
+
+	t.Run("OutputBaseIDPrefix", func(t *testing.T) {
+		storage, ok := selector.SelectOutputPathStorage("tenant", digest.MustNewInstanceName("hello"))
+		require.True(t, ok)
+		require.Equal(t, tenantStorage, storage)
+
+		storage, ok = selector.SelectOutputPathStorage("tenant/other", digest.EmptyInstanceName)
+		require.True(t, ok)
+		require.Equal(t, tenantStorage, storage)
+
+		// The longest matching prefix should be used.
+		storage, ok = selector.SelectOutputPathStorage("tenant/team/output_base", digest.EmptyInstanceName)
+		require.True(t, ok)
+		require.Equal(t, tenantTeamStorage, storage)
+
+		// Prefixes should only match entire components.
+		storage, ok = selector.SelectOutputPathStorage("tenant/teams", digest.EmptyInstanceName)
+		require.True(t, ok)
+		require.Equal(t, tenantStorage, storage)
+	})
+
+	t.Run("InstanceNamePrefix", func(t *testing.T) {
+		storage, ok := selector.SelectOutputPathStorage("tenants", digest.MustNewInstanceName("hello/mars"))
+		require.True(t, ok)
+		require.Equal(t, instanceNameStorage, storage)
+
+		storage, ok = selector.SelectOutputPathStorage("output_base", digest.MustNewInstanceName("hello/world/nested"))
+		require.True(t, ok)
+		require.Equal(t, nestedInstanceNameStorage, storage)
+	})
+
+	t.Run("NoMatch", func(t *testing.T) {
+		_, ok := selector.SelectOutputPathStorage("output_base", digest.MustNewInstanceName("goodbye"))
+		require.False(t, ok)
+	})
+
+	t.Run("EmptyOutputBaseIDPrefix", func(t *testing.T) {
+		// An empty prefix matches all output bases, meaning
+		// that instance name prefixes are no longer consulted.
+		defaultStorage := newStorage()
+		require.NoError(t, selector.AddOutputBaseIDPrefix("", defaultStorage))
+
+		storage, ok := selector.SelectOutputPathStorage("output_base", digest.MustNewInstanceName("hello"))
+		require.True(t, ok)
+		require.Equal(t, defaultStorage, storage)
+	})
+}
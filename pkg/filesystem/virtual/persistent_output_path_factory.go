@@ -89,7 +89,15 @@ func (sr *stateRestorer) restoreDirectoryRecursive(reader outputpathpersistency.
 		if err != nil {
 			return util.StatusWrapf(err, "Failed to obtain digest for file %#v", childPath.String())
 		}
-		initialNodes[component] = virtual.InitialNode{}.FromLeaf(sr.casFileFactory.LookupFile(childDigest, entry.IsExecutable, nil))
+		modificationTime, err := getModificationTimeFromNodeProperties(entry.NodeProperties)
+		if err != nil {
+			return util.StatusWrapf(err, "Failed to obtain node properties for file %#v", childPath.String())
+		}
+		leaf := sr.casFileFactory.LookupFile(childDigest, entry.IsExecutable, nil)
+		if modificationTime != nil {
+			leaf = newModificationTimeOverridingLeaf(leaf, *modificationTime)
+		}
+		initialNodes[component] = virtual.InitialNode{}.FromLeaf(leaf)
 	}
 	for _, entry := range contents.Symlinks {
 		component, ok := path.NewComponent(entry.Name)
@@ -129,7 +137,14 @@ func (opf *persistentOutputPathFactory) StartInitialBuild(outputBaseID path.Comp
 		err = sr.restoreDirectoryRecursive(reader, rootDirectory.Contents, d, nil)
 		reader.Close()
 		if err != nil {
+			// Don't expose a partially restored output
+			// path, as its contents may be inconsistent.
+			// Start with an empty output path instead.
 			opf.errorLogger.Log(util.StatusWrapf(err, "Failed to restore state file for output path %#v", outputBaseID.String()))
+			initialCreationTime = nil
+			if err := d.RemoveAllChildren(false); err != nil {
+				opf.errorLogger.Log(util.StatusWrapf(err, "Failed to remove partially restored contents of output path %#v", outputBaseID.String()))
+			}
 		}
 	}
 	if initialCreationTime == nil {
@@ -146,6 +161,18 @@ func (opf *persistentOutputPathFactory) StartInitialBuild(outputBaseID path.Comp
 	}
 }
 
+func (opf *persistentOutputPathFactory) HasPersistentState(outputBaseID path.Component) bool {
+	reader, _, err := opf.store.Read(outputBaseID)
+	if err != nil {
+		// State files that are corrupted are still reported,
+		// so that StartInitialBuild() logs the error and
+		// starts with an empty output path.
+		return status.Code(err) != codes.NotFound
+	}
+	reader.Close()
+	return true
+}
+
 func (opf *persistentOutputPathFactory) Clean(outputBaseID path.Component) error {
 	if err := opf.base.Clean(outputBaseID); err != nil {
 		return err
@@ -171,6 +198,27 @@ func (op *persistentOutputPath) FinalizeBuild(ctx context.Context, digestFunctio
 	}
 }
 
+func (op *persistentOutputPath) Rename(outputBaseID path.Component) error {
+	if err := op.OutputPath.Rename(outputBaseID); err != nil {
+		return err
+	}
+
+	// Store the contents of the output path under the new output
+	// base ID, and remove the state file of the old one. This
+	// prevents the old output base ID from reloading the contents
+	// after a restart.
+	oldOutputBaseID := op.outputBaseID
+	op.outputBaseID = outputBaseID
+	if err := op.saveOutputPath(); err != nil {
+		op.outputBaseID = oldOutputBaseID
+		return util.StatusWrapf(err, "Failed to save the contents of output path %#v", outputBaseID.String())
+	}
+	if err := op.factory.store.Clean(oldOutputBaseID); err != nil {
+		return util.StatusWrapf(err, "Failed to remove persistent state for output path %#v", oldOutputBaseID.String())
+	}
+	return nil
+}
+
 func (op *persistentOutputPath) saveOutputPath() error {
 	writer, err := op.factory.store.Write(op.outputBaseID)
 	if err != nil {
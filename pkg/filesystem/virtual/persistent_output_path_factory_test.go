@@ -17,6 +17,7 @@ import (
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 func TestPersistentOutputPathFactoryStartInitialBuild(t *testing.T) {
@@ -92,6 +93,7 @@ func TestPersistentOutputPathFactoryStartInitialBuild(t *testing.T) {
 		}, nil)
 		reader.EXPECT().Close()
 		globalErrorLogger.EXPECT().Log(testutil.EqStatus(t, status.Error(codes.InvalidArgument, "Failed to restore state file for output path \"0226bea917a1c8c9c2ad4f7d4229de01\": Directory \"hello/world\" inside directory \".\" has an invalid name")))
+		baseOutputPath.EXPECT().RemoveAllChildren(false)
 		clock.EXPECT().Now().Return(time.Unix(1000, 0))
 
 		outputPath := outputPathFactory.StartInitialBuild(outputBaseID, casFileFactory, digestFunction, fileErrorLogger)
@@ -128,6 +130,7 @@ func TestPersistentOutputPathFactoryStartInitialBuild(t *testing.T) {
 
 		reader.EXPECT().Close()
 		globalErrorLogger.EXPECT().Log(testutil.EqStatus(t, status.Error(codes.Internal, "Failed to restore state file for output path \"054f6c2d674d23e67e011b1bb1ba7a5e\": Failed to load directory \"hello\": Disk I/O failure")))
+		baseOutputPath.EXPECT().RemoveAllChildren(false)
 		clock.EXPECT().Now().Return(time.Unix(1000, 0))
 
 		outputPath := outputPathFactory.StartInitialBuild(outputBaseID, casFileFactory, digestFunction, fileErrorLogger)
@@ -175,6 +178,60 @@ func TestPersistentOutputPathFactoryStartInitialBuild(t *testing.T) {
 		file1.EXPECT().Unlink()
 		reader.EXPECT().Close()
 		globalErrorLogger.EXPECT().Log(testutil.EqStatus(t, status.Error(codes.InvalidArgument, "Failed to restore state file for output path \"0226bea917a1c8c9c2ad4f7d4229de01\": Failed to obtain digest for file \"file2\": Hash has length 20, while 64 characters were expected")))
+		baseOutputPath.EXPECT().RemoveAllChildren(false)
+		clock.EXPECT().Now().Return(time.Unix(1000, 0))
+
+		outputPath := outputPathFactory.StartInitialBuild(outputBaseID, casFileFactory, digestFunction, fileErrorLogger)
+		require.NotNil(t, outputPath)
+	})
+
+	t.Run("PartiallyRestoredStateFile", func(t *testing.T) {
+		// If a state file is corrupted, directories that were
+		// already restored should be removed, so that the
+		// output path is exposed as being empty. Failures to
+		// remove them should be logged as well.
+		baseOutputPath := mock.NewMockOutputPath(ctrl)
+		outputBaseID := path.MustNewComponent("5ad5c0b2a8d2e8c2b6af5e4f2e4cb54d")
+		casFileFactory := mock.NewMockCASFileFactory(ctrl)
+		fileErrorLogger := mock.NewMockErrorLogger(ctrl)
+		baseOutputPathFactory.EXPECT().StartInitialBuild(outputBaseID, casFileFactory, digestFunction, fileErrorLogger).
+			Return(baseOutputPath)
+		reader := mock.NewMockOutputPathPersistencyReadCloser(ctrl)
+		store.EXPECT().Read(outputBaseID).Return(reader, &outputpathpersistency.RootDirectory{
+			InitialCreationTime: &timestamppb.Timestamp{Seconds: 500},
+			Contents: &outputpathpersistency.Directory{
+				Directories: []*outputpathpersistency.DirectoryNode{
+					{
+						Name: "hello",
+						FileRegion: &outputpathpersistency.FileRegion{
+							OffsetBytes: 123,
+							SizeBytes:   456,
+						},
+					},
+				},
+				Symlinks: []*remoteexecution.SymlinkNode{
+					{
+						Name:   "..",
+						Target: "/etc/passwd",
+					},
+				},
+			},
+		}, nil)
+		childReader := mock.NewMockOutputPathPersistencyReadCloser(ctrl)
+		reader.EXPECT().ReadDirectory(testutil.EqProto(t, &outputpathpersistency.FileRegion{
+			OffsetBytes: 123,
+			SizeBytes:   456,
+		})).Return(childReader, &outputpathpersistency.Directory{}, nil)
+		childDirectory := mock.NewMockPrepopulatedDirectory(ctrl)
+		baseOutputPath.EXPECT().CreateAndEnterPrepopulatedDirectory(path.MustNewComponent("hello")).Return(childDirectory, nil)
+		childDirectory.EXPECT().CreateChildren(map[path.Component]re_vfs.InitialNode{}, true)
+		reader.EXPECT().Close()
+		globalErrorLogger.EXPECT().Log(testutil.EqStatus(t, status.Error(codes.InvalidArgument, "Failed to restore state file for output path \"5ad5c0b2a8d2e8c2b6af5e4f2e4cb54d\": Symlink \"..\" inside directory \".\" has an invalid name")))
+		baseOutputPath.EXPECT().RemoveAllChildren(false).Return(status.Error(codes.Internal, "Disk I/O failure"))
+		globalErrorLogger.EXPECT().Log(testutil.EqStatus(t, status.Error(codes.Internal, "Failed to remove partially restored contents of output path \"5ad5c0b2a8d2e8c2b6af5e4f2e4cb54d\": Disk I/O failure")))
+
+		// As the output path is reset, it should be treated as
+		// if it was created from scratch.
 		clock.EXPECT().Now().Return(time.Unix(1000, 0))
 
 		outputPath := outputPathFactory.StartInitialBuild(outputBaseID, casFileFactory, digestFunction, fileErrorLogger)
@@ -245,6 +302,41 @@ func TestPersistentOutputPathFactoryStartInitialBuild(t *testing.T) {
 	// TODO: Are there more cases we want to test?
 }
 
+func TestPersistentOutputPathFactoryHasPersistentState(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	baseOutputPathFactory := mock.NewMockOutputPathFactory(ctrl)
+	store := mock.NewMockOutputPathPersistencyStore(ctrl)
+	clock := mock.NewMockClock(ctrl)
+	errorLogger := mock.NewMockErrorLogger(ctrl)
+	symlinkFactory := mock.NewMockSymlinkFactory(ctrl)
+	outputPathFactory := cd_vfs.NewPersistentOutputPathFactory(baseOutputPathFactory, store, clock, errorLogger, symlinkFactory)
+	outputBaseID := path.MustNewComponent("c0a1a9c5c2a4b4608b6ab4ae4b2fd8e0")
+
+	t.Run("NotFound", func(t *testing.T) {
+		store.EXPECT().Read(outputBaseID).Return(nil, nil, status.Error(codes.NotFound, "Failed to open state file: no such file or directory"))
+
+		require.False(t, outputPathFactory.HasPersistentState(outputBaseID))
+	})
+
+	t.Run("Malformed", func(t *testing.T) {
+		// State files that cannot be loaded should still be
+		// reported, so that the error gets logged by
+		// StartInitialBuild().
+		store.EXPECT().Read(outputBaseID).Return(nil, nil, status.Error(codes.InvalidArgument, "Invalid magic"))
+
+		require.True(t, outputPathFactory.HasPersistentState(outputBaseID))
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		reader := mock.NewMockOutputPathPersistencyReadCloser(ctrl)
+		store.EXPECT().Read(outputBaseID).Return(reader, &outputpathpersistency.RootDirectory{}, nil)
+		reader.EXPECT().Close()
+
+		require.True(t, outputPathFactory.HasPersistentState(outputBaseID))
+	})
+}
+
 func TestPersistentOutputPathFactoryClean(t *testing.T) {
 	ctrl := gomock.NewController(t)
 
@@ -0,0 +1,215 @@
+package virtual
+
+import (
+	"context"
+
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/virtual"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/filesystem"
+	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+	"github.com/buildbarn/bb-storage/pkg/util"
+)
+
+type readOnlyOutputPathFactory struct {
+	OutputPathFactory
+}
+
+// NewReadOnlyOutputPathFactory creates a decorator for
+// OutputPathFactory that prevents output paths from being modified
+// through the virtual file system. Operations such as creating,
+// removing and renaming files, or opening files for writing, fail
+// with EROFS.
+//
+// Output paths may still be modified through the Remote Output
+// Service (e.g., by calling BatchCreate()), as those operations call
+// into PrepopulatedDirectory directly. This decorator can thus be used
+// to prevent users from accidentally corrupting the contents of output
+// paths. It should only be used if builds don't need to write into
+// output paths themselves, which is the case if all actions are
+// executed remotely.
+//
+// Directories and files are wrapped as they are looked up. File systems
+// that are capable of resolving nodes without performing a lookup
+// (e.g., NFSv4 through PUTFH) may therefore bypass this decorator.
+func NewReadOnlyOutputPathFactory(base OutputPathFactory) OutputPathFactory {
+	return &readOnlyOutputPathFactory{
+		OutputPathFactory: base,
+	}
+}
+
+func (opf *readOnlyOutputPathFactory) StartInitialBuild(outputBaseID path.Component, casFileFactory virtual.CASFileFactory, digestFunction digest.Function, errorLogger util.ErrorLogger) OutputPath {
+	return &readOnlyOutputPath{
+		OutputPath: opf.OutputPathFactory.StartInitialBuild(outputBaseID, casFileFactory, digestFunction, errorLogger),
+	}
+}
+
+// readOnlyOutputPath is the root directory of an output path created
+// by readOnlyOutputPathFactory. All operations against the virtual file
+// system are forwarded to readOnlyDirectory, while all other
+// operations are forwarded to the underlying OutputPath.
+type readOnlyOutputPath struct {
+	OutputPath
+}
+
+func (op *readOnlyOutputPath) directory() *readOnlyDirectory {
+	return &readOnlyDirectory{Directory: op.OutputPath}
+}
+
+func (op *readOnlyOutputPath) VirtualLink(ctx context.Context, name path.Component, leaf virtual.Leaf, requested virtual.AttributesMask, out *virtual.Attributes) (virtual.ChangeInfo, virtual.Status) {
+	return op.directory().VirtualLink(ctx, name, leaf, requested, out)
+}
+
+func (op *readOnlyOutputPath) VirtualLookup(ctx context.Context, name path.Component, requested virtual.AttributesMask, out *virtual.Attributes) (virtual.DirectoryChild, virtual.Status) {
+	return op.directory().VirtualLookup(ctx, name, requested, out)
+}
+
+func (op *readOnlyOutputPath) VirtualMkdir(name path.Component, requested virtual.AttributesMask, out *virtual.Attributes) (virtual.Directory, virtual.ChangeInfo, virtual.Status) {
+	return op.directory().VirtualMkdir(name, requested, out)
+}
+
+func (op *readOnlyOutputPath) VirtualMknod(ctx context.Context, name path.Component, fileType filesystem.FileType, requested virtual.AttributesMask, out *virtual.Attributes) (virtual.Leaf, virtual.ChangeInfo, virtual.Status) {
+	return op.directory().VirtualMknod(ctx, name, fileType, requested, out)
+}
+
+func (op *readOnlyOutputPath) VirtualOpenChild(ctx context.Context, name path.Component, shareAccess virtual.ShareMask, createAttributes *virtual.Attributes, existingOptions *virtual.OpenExistingOptions, requested virtual.AttributesMask, openedFileAttributes *virtual.Attributes) (virtual.Leaf, virtual.AttributesMask, virtual.ChangeInfo, virtual.Status) {
+	return op.directory().VirtualOpenChild(ctx, name, shareAccess, createAttributes, existingOptions, requested, openedFileAttributes)
+}
+
+func (op *readOnlyOutputPath) VirtualReadDir(ctx context.Context, firstCookie uint64, requested virtual.AttributesMask, reporter virtual.DirectoryEntryReporter) virtual.Status {
+	return op.directory().VirtualReadDir(ctx, firstCookie, requested, reporter)
+}
+
+func (op *readOnlyOutputPath) VirtualRename(oldName path.Component, newDirectory virtual.Directory, newName path.Component) (virtual.ChangeInfo, virtual.ChangeInfo, virtual.Status) {
+	return op.directory().VirtualRename(oldName, newDirectory, newName)
+}
+
+func (op *readOnlyOutputPath) VirtualRemove(name path.Component, removeDirectory, removeLeaf bool) (virtual.ChangeInfo, virtual.Status) {
+	return op.directory().VirtualRemove(name, removeDirectory, removeLeaf)
+}
+
+func (op *readOnlyOutputPath) VirtualSetAttributes(ctx context.Context, in *virtual.Attributes, requested virtual.AttributesMask, out *virtual.Attributes) virtual.Status {
+	return op.directory().VirtualSetAttributes(ctx, in, requested, out)
+}
+
+func (op *readOnlyOutputPath) VirtualSymlink(ctx context.Context, pointedTo []byte, linkName path.Component, requested virtual.AttributesMask, out *virtual.Attributes) (virtual.Leaf, virtual.ChangeInfo, virtual.Status) {
+	return op.directory().VirtualSymlink(ctx, pointedTo, linkName, requested, out)
+}
+
+// readOnlyDirectory is a decorator for Directory that disables all
+// operations that mutate the directory contents. Children that are
+// returned are wrapped as well, so that the read-only property applies
+// to the full directory hierarchy.
+type readOnlyDirectory struct {
+	virtual.Directory
+}
+
+// newReadOnlyChild wraps a directory or leaf that is returned by one of
+// the methods of readOnlyDirectory.
+func newReadOnlyChild(child virtual.DirectoryChild) virtual.DirectoryChild {
+	if directory, leaf := child.GetPair(); directory != nil {
+		return virtual.DirectoryChild{}.FromDirectory(&readOnlyDirectory{Directory: directory})
+	} else if leaf != nil {
+		return virtual.DirectoryChild{}.FromLeaf(&readOnlyLeaf{Leaf: leaf})
+	}
+	return child
+}
+
+func (readOnlyDirectory) VirtualLink(ctx context.Context, name path.Component, leaf virtual.Leaf, requested virtual.AttributesMask, out *virtual.Attributes) (virtual.ChangeInfo, virtual.Status) {
+	return virtual.ChangeInfo{}, virtual.StatusErrROFS
+}
+
+func (d *readOnlyDirectory) VirtualLookup(ctx context.Context, name path.Component, requested virtual.AttributesMask, out *virtual.Attributes) (virtual.DirectoryChild, virtual.Status) {
+	child, s := d.Directory.VirtualLookup(ctx, name, requested, out)
+	if s != virtual.StatusOK {
+		return virtual.DirectoryChild{}, s
+	}
+	return newReadOnlyChild(child), virtual.StatusOK
+}
+
+func (readOnlyDirectory) VirtualMkdir(name path.Component, requested virtual.AttributesMask, out *virtual.Attributes) (virtual.Directory, virtual.ChangeInfo, virtual.Status) {
+	return nil, virtual.ChangeInfo{}, virtual.StatusErrROFS
+}
+
+func (readOnlyDirectory) VirtualMknod(ctx context.Context, name path.Component, fileType filesystem.FileType, requested virtual.AttributesMask, out *virtual.Attributes) (virtual.Leaf, virtual.ChangeInfo, virtual.Status) {
+	return nil, virtual.ChangeInfo{}, virtual.StatusErrROFS
+}
+
+func (d *readOnlyDirectory) VirtualOpenChild(ctx context.Context, name path.Component, shareAccess virtual.ShareMask, createAttributes *virtual.Attributes, existingOptions *virtual.OpenExistingOptions, requested virtual.AttributesMask, openedFileAttributes *virtual.Attributes) (virtual.Leaf, virtual.AttributesMask, virtual.ChangeInfo, virtual.Status) {
+	if createAttributes != nil {
+		// Files may not be created. Only permit opening files
+		// that already exist.
+		if _, s := d.Directory.VirtualLookup(ctx, name, 0, &virtual.Attributes{}); s == virtual.StatusErrNoEnt {
+			return virtual.ReadOnlyDirectoryOpenChildDoesntExist(createAttributes)
+		} else if s != virtual.StatusOK {
+			return nil, 0, virtual.ChangeInfo{}, s
+		}
+		if existingOptions == nil {
+			return nil, 0, virtual.ChangeInfo{}, virtual.StatusErrExist
+		}
+	}
+	if shareAccess&^virtual.ShareMaskRead != 0 || existingOptions.Truncate {
+		return nil, 0, virtual.ChangeInfo{}, virtual.StatusErrROFS
+	}
+
+	leaf, respected, changeInfo, s := d.Directory.VirtualOpenChild(ctx, name, shareAccess, nil, existingOptions, requested, openedFileAttributes)
+	if s != virtual.StatusOK {
+		return nil, 0, virtual.ChangeInfo{}, s
+	}
+	return &readOnlyLeaf{Leaf: leaf}, respected, changeInfo, virtual.StatusOK
+}
+
+func (d *readOnlyDirectory) VirtualReadDir(ctx context.Context, firstCookie uint64, requested virtual.AttributesMask, reporter virtual.DirectoryEntryReporter) virtual.Status {
+	return d.Directory.VirtualReadDir(ctx, firstCookie, requested, readOnlyDirectoryEntryReporter{base: reporter})
+}
+
+func (readOnlyDirectory) VirtualRename(oldName path.Component, newDirectory virtual.Directory, newName path.Component) (virtual.ChangeInfo, virtual.ChangeInfo, virtual.Status) {
+	return virtual.ChangeInfo{}, virtual.ChangeInfo{}, virtual.StatusErrROFS
+}
+
+func (readOnlyDirectory) VirtualRemove(name path.Component, removeDirectory, removeLeaf bool) (virtual.ChangeInfo, virtual.Status) {
+	return virtual.ChangeInfo{}, virtual.StatusErrROFS
+}
+
+func (readOnlyDirectory) VirtualSetAttributes(ctx context.Context, in *virtual.Attributes, requested virtual.AttributesMask, out *virtual.Attributes) virtual.Status {
+	return virtual.StatusErrROFS
+}
+
+func (readOnlyDirectory) VirtualSymlink(ctx context.Context, pointedTo []byte, linkName path.Component, requested virtual.AttributesMask, out *virtual.Attributes) (virtual.Leaf, virtual.ChangeInfo, virtual.Status) {
+	return nil, virtual.ChangeInfo{}, virtual.StatusErrROFS
+}
+
+// readOnlyDirectoryEntryReporter is a decorator for
+// DirectoryEntryReporter that is used by readOnlyDirectory to wrap
+// children that are returned by VirtualReadDir().
+type readOnlyDirectoryEntryReporter struct {
+	base virtual.DirectoryEntryReporter
+}
+
+func (r readOnlyDirectoryEntryReporter) ReportEntry(nextCookie uint64, name path.Component, child virtual.DirectoryChild, attributes *virtual.Attributes) bool {
+	return r.base.ReportEntry(nextCookie, name, newReadOnlyChild(child), attributes)
+}
+
+// readOnlyLeaf is a decorator for Leaf that disables all operations
+// that mutate the file's contents or attributes.
+type readOnlyLeaf struct {
+	virtual.Leaf
+}
+
+func (readOnlyLeaf) VirtualAllocate(off, size uint64) virtual.Status {
+	return virtual.StatusErrROFS
+}
+
+func (l *readOnlyLeaf) VirtualOpenSelf(ctx context.Context, shareAccess virtual.ShareMask, options *virtual.OpenExistingOptions, requested virtual.AttributesMask, attributes *virtual.Attributes) virtual.Status {
+	if shareAccess&^virtual.ShareMaskRead != 0 || options.Truncate {
+		return virtual.StatusErrROFS
+	}
+	return l.Leaf.VirtualOpenSelf(ctx, shareAccess, options, requested, attributes)
+}
+
+func (readOnlyLeaf) VirtualSetAttributes(ctx context.Context, in *virtual.Attributes, requested virtual.AttributesMask, out *virtual.Attributes) virtual.Status {
+	return virtual.StatusErrROFS
+}
+
+func (readOnlyLeaf) VirtualWrite(buf []byte, offset uint64) (int, virtual.Status) {
+	return 0, virtual.StatusErrROFS
+}
@@ -0,0 +1,103 @@
+package virtual_test
+
+import (
+	"context"
+	"testing"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-clientd/internal/mock"
+	cd_vfs "github.com/buildbarn/bb-clientd/pkg/filesystem/virtual"
+	re_vfs "github.com/buildbarn/bb-remote-execution/pkg/filesystem/virtual"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadOnlyOutputPathFactory(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	baseOutputPathFactory := mock.NewMockOutputPathFactory(ctrl)
+	outputPathFactory := cd_vfs.NewReadOnlyOutputPathFactory(baseOutputPathFactory)
+
+	casFileFactory := mock.NewMockCASFileFactory(ctrl)
+	digestFunction := digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256)
+	errorLogger := mock.NewMockErrorLogger(ctrl)
+	baseOutputPath := mock.NewMockOutputPath(ctrl)
+	baseOutputPathFactory.EXPECT().StartInitialBuild(path.MustNewComponent("9da951b8cb759233037166e28f7ea186"), casFileFactory, digestFunction, errorLogger).
+		Return(baseOutputPath)
+	outputPath := outputPathFactory.StartInitialBuild(path.MustNewComponent("9da951b8cb759233037166e28f7ea186"), casFileFactory, digestFunction, errorLogger)
+
+	t.Run("Mutations", func(t *testing.T) {
+		// Operations that modify the contents of the output
+		// path through the virtual file system should fail.
+		_, _, s := outputPath.VirtualMkdir(path.MustNewComponent("dir"), 0, &re_vfs.Attributes{})
+		require.Equal(t, re_vfs.StatusErrROFS, s)
+
+		_, s = outputPath.VirtualRemove(path.MustNewComponent("file"), true, true)
+		require.Equal(t, re_vfs.StatusErrROFS, s)
+
+		_, _, s = outputPath.VirtualSymlink(ctx, []byte("target"), path.MustNewComponent("symlink"), 0, &re_vfs.Attributes{})
+		require.Equal(t, re_vfs.StatusErrROFS, s)
+
+		_, _, s = outputPath.VirtualRename(path.MustNewComponent("a"), outputPath, path.MustNewComponent("b"))
+		require.Equal(t, re_vfs.StatusErrROFS, s)
+	})
+
+	t.Run("PrepopulatedDirectory", func(t *testing.T) {
+		// Operations performed through the Remote Output Service
+		// should still be forwarded.
+		baseOutputPath.EXPECT().RemoveAll(path.MustNewComponent("file"))
+		require.NoError(t, outputPath.RemoveAll(path.MustNewComponent("file")))
+	})
+
+	t.Run("OpenChild", func(t *testing.T) {
+		// Creating new files should fail with EROFS.
+		baseOutputPath.EXPECT().VirtualLookup(ctx, path.MustNewComponent("new_file"), re_vfs.AttributesMask(0), gomock.Any()).
+			Return(re_vfs.DirectoryChild{}, re_vfs.StatusErrNoEnt)
+		_, _, _, s := outputPath.VirtualOpenChild(ctx, path.MustNewComponent("new_file"), re_vfs.ShareMaskWrite, &re_vfs.Attributes{}, nil, 0, &re_vfs.Attributes{})
+		require.Equal(t, re_vfs.StatusErrROFS, s)
+
+		// Opening existing files for writing should also fail.
+		_, _, _, s = outputPath.VirtualOpenChild(ctx, path.MustNewComponent("file"), re_vfs.ShareMaskWrite, nil, &re_vfs.OpenExistingOptions{}, 0, &re_vfs.Attributes{})
+		require.Equal(t, re_vfs.StatusErrROFS, s)
+
+		// Opening existing files for reading is permitted.
+		// The resulting file should be read-only as well.
+		leaf := mock.NewMockVirtualLeaf(ctrl)
+		baseOutputPath.EXPECT().VirtualOpenChild(ctx, path.MustNewComponent("file"), re_vfs.ShareMaskRead, nil, &re_vfs.OpenExistingOptions{}, re_vfs.AttributesMask(0), gomock.Any()).
+			Return(leaf, re_vfs.AttributesMask(0), re_vfs.ChangeInfo{}, re_vfs.StatusOK)
+		openedLeaf, _, _, s := outputPath.VirtualOpenChild(ctx, path.MustNewComponent("file"), re_vfs.ShareMaskRead, nil, &re_vfs.OpenExistingOptions{}, 0, &re_vfs.Attributes{})
+		require.Equal(t, re_vfs.StatusOK, s)
+
+		_, s = openedLeaf.VirtualWrite([]byte("Hello"), 0)
+		require.Equal(t, re_vfs.StatusErrROFS, s)
+	})
+
+	t.Run("Lookup", func(t *testing.T) {
+		// Directories and files that are looked up should be
+		// read-only as well.
+		directory := mock.NewMockVirtualDirectory(ctrl)
+		baseOutputPath.EXPECT().VirtualLookup(ctx, path.MustNewComponent("dir"), re_vfs.AttributesMask(0), gomock.Any()).
+			Return(re_vfs.DirectoryChild{}.FromDirectory(directory), re_vfs.StatusOK)
+		child, s := outputPath.VirtualLookup(ctx, path.MustNewComponent("dir"), 0, &re_vfs.Attributes{})
+		require.Equal(t, re_vfs.StatusOK, s)
+		childDirectory, _ := child.GetPair()
+
+		_, s = childDirectory.VirtualRemove(path.MustNewComponent("file"), false, true)
+		require.Equal(t, re_vfs.StatusErrROFS, s)
+
+		leaf := mock.NewMockVirtualLeaf(ctrl)
+		directory.EXPECT().VirtualLookup(ctx, path.MustNewComponent("file"), re_vfs.AttributesMaskSizeBytes, gomock.Any()).
+			Return(re_vfs.DirectoryChild{}.FromLeaf(leaf), re_vfs.StatusOK)
+		child, s = childDirectory.VirtualLookup(ctx, path.MustNewComponent("file"), re_vfs.AttributesMaskSizeBytes, &re_vfs.Attributes{})
+		require.Equal(t, re_vfs.StatusOK, s)
+		_, childLeaf := child.GetPair()
+
+		require.Equal(t, re_vfs.StatusErrROFS, childLeaf.VirtualOpenSelf(ctx, re_vfs.ShareMaskWrite, &re_vfs.OpenExistingOptions{}, 0, &re_vfs.Attributes{}))
+		require.Equal(t, re_vfs.StatusErrROFS, childLeaf.VirtualSetAttributes(ctx, (&re_vfs.Attributes{}).SetSizeBytes(0), 0, &re_vfs.Attributes{}))
+
+		leaf.EXPECT().VirtualOpenSelf(ctx, re_vfs.ShareMaskRead, &re_vfs.OpenExistingOptions{}, re_vfs.AttributesMask(0), gomock.Any())
+		require.Equal(t, re_vfs.StatusOK, childLeaf.VirtualOpenSelf(ctx, re_vfs.ShareMaskRead, &re_vfs.OpenExistingOptions{}, 0, &re_vfs.Attributes{}))
+	})
+}
@@ -2,44 +2,162 @@ package virtual
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"math"
+	"runtime"
+	"sort"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"syscall"
+	"time"
 
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	cd_blobstore "github.com/buildbarn/bb-clientd/pkg/blobstore"
 	cd_cas "github.com/buildbarn/bb-clientd/pkg/cas"
+	"github.com/buildbarn/bb-clientd/pkg/proto/outputpaths"
 	re_cas "github.com/buildbarn/bb-remote-execution/pkg/cas"
 	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/virtual"
 	"github.com/buildbarn/bb-remote-execution/pkg/proto/remoteoutputservice"
+	"github.com/buildbarn/bb-storage/pkg/auth"
 	"github.com/buildbarn/bb-storage/pkg/blobstore"
+	"github.com/buildbarn/bb-storage/pkg/blobstore/buffer"
+	"github.com/buildbarn/bb-storage/pkg/clock"
 	"github.com/buildbarn/bb-storage/pkg/digest"
 	"github.com/buildbarn/bb-storage/pkg/filesystem"
 	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
 	"github.com/buildbarn/bb-storage/pkg/util"
+	"github.com/prometheus/client_golang/prometheus"
 
+	"golang.org/x/sync/semaphore"
+	status_pb "google.golang.org/genproto/googleapis/rpc/status"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/emptypb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type buildState struct {
 	id                 string
+	startTime          time.Time
+	outputPath         string
+	outputPathSuffix   string
+	outputPathAliases  map[string]string
 	digestFunction     digest.Function
 	scopeWalkerFactory *path.VirtualRootScopeWalkerFactory
+
+	// Whether filterMissingChildren() completed successfully for
+	// this build, meaning that successive calls to StartBuild()
+	// using the same build ID don't need to call it again.
+	filteredMissingChildren bool
+
+	// How paths that resolve to a location outside the output path
+	// are reported, as set through SetExternalPathPolicy().
+	externalPathPolicy outputpaths.ExternalPathPolicy
+
+	// Normalized paths of directories that were finalized through
+	// FinalizeSubtree(). BatchCreate() refuses to modify these.
+	finalizedSubtrees map[string]struct{}
+
+	// Normalized path that is prepended to paths provided to
+	// BatchCreate() and BatchStat(), as set through
+	// SetDefaultPathPrefix().
+	defaultPathPrefix string
+
+	// Key-value pairs provided by the client through
+	// SetBuildMetadata(). The map is replaced as a whole, so that it
+	// may be returned without holding the directory lock.
+	clientMetadata map[string]string
+
+	// Whether the build was sealed through SealBuild(), meaning
+	// that the output path may no longer be modified through the
+	// Remote Output Service.
+	sealed bool
+
+	// The last time a request was made against the build. This is
+	// only tracked if a stale build grace period is configured.
+	lastActivityTime time.Time
+
+	// The number of bytes the output path had fetched from the
+	// Content Addressable Storage at the time the build was
+	// started. Used to compute the number of bytes fetched on
+	// behalf of this build.
+	bytesFetchedAtStart uint64
+
+	// The number of fetches of the output path that had failed at
+	// the time the build was started.
+	fetchFailuresAtStart uint64
+
+	// The number of FindMissingBlobs() calls that StartBuild()
+	// issued while checking the contents of the output path, and
+	// the total number of digests provided to these calls.
+	findMissingCalls uint64
+	blobsChecked     uint64
+
+	// The progress of checking the contents of the output path
+	// for existence, while StartBuild() is doing so.
+	filterProgress *startBuildProgress
 }
 
+// precreatedOutputPathDigestFunction is the digest function that is
+// used by output paths created through VirtualMkdir() if preloading is
+// not enabled.
+var precreatedOutputPathDigestFunction = digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256)
+
 type outputPathState struct {
-	buildState     *buildState
-	rootDirectory  OutputPath
-	casFileFactory virtual.CASFileFactory
-
-	// Circular linked list, used by VirtualReadDir(). By only
-	// inserting new output paths at the end and ensuring that
-	// cookies are monotonically increasing, we can reliably perform
-	// partial reads against this directory.
-	previous     *outputPathState
-	next         *outputPathState
+	buildState                    *buildState
+	rootDirectory                 OutputPath
+	casFileFactory                virtual.CASFileFactory
+	bareContentAddressableStorage blobstore.BlobAccess
+	directoryFetcher              re_cas.DirectoryFetcher
+	errorLogger                   *outputPathErrorLogger
+	fetchStatistics               *outputPathFetchStatistics
+	fetchContext                  *outputPathFetchContext
+
+	// The backends returned by the OutputPathStorageSelector when
+	// the output path was created, if any. As the CAS file factory
+	// of the output path can't be replaced, builds for which other
+	// backends would be selected are rejected.
+	storage OutputPathStorage
+
+	// The start time of the last build for which
+	// filterMissingChildren() completed successfully. This is used
+	// to report how likely it is that the output path references
+	// objects that have been evicted from the Content Addressable
+	// Storage.
+	lastValidationTime      time.Time
+	lastValidationTimeGauge prometheus.Gauge
+
+	// The time at which the output path was created, and the start
+	// time of the last build run against it. These are used by
+	// ListOutputPaths() to report output paths that have changed
+	// since a given point in time.
+	creationTime       time.Time
+	lastBuildStartTime time.Time
+
+	// The state of the build that was most recently finalized, if
+	// no other build has been started against the output path
+	// since. ReopenBuild() uses this to resume the build.
+	finalizedBuildState *buildState
+
+	// The digest function used by the most recently started build.
+	// This is used to reject builds against other instance names,
+	// if configured. CloneOutputPath() uses it to reject source
+	// output paths whose contents are incompatible with the build.
+	lastDigestFunction *digest.Function
+
+	// Used to expose the contents of retained finalized builds.
+	// Only created once the first build is retained.
+	treeCASDirectoryFactory CASDirectoryFactory
+
+	// Cookies are derived from a monotonically increasing counter,
+	// so that VirtualReadDir() can reliably perform partial reads
+	// by returning entries in order of creation.
 	cookie       uint64
-	outputBaseID path.Component
+	outputBaseID outputBasePath
 }
 
 // RemoteOutputServiceDirectory is FUSE directory that acts as the
@@ -52,12 +170,20 @@ type outputPathState struct {
 // used to start and finalize builds, but also to perform bulk creation
 // and stat() operations.
 //
+// Output paths are exposed as children of this directory, named after
+// their output base ID. Output base IDs may consist of multiple
+// components separated by slashes (e.g., "team/project"), in which case
+// the output path is placed inside nested directories. These nested
+// directories are read-only and only list the output paths and nested
+// directories contained within them.
+//
 // This implementation of the Remote Output Service is relatively
 // simple:
 //
 //   - There is no persistency of build information across restarts.
-//   - No snapshotting of completed builds takes place, meaning that only
-//     the results of the latest build of a given output base are exposed.
+//   - Snapshots of completed builds are only taken if retention of
+//     finalized builds is enabled. Snapshots are read-only, and are
+//     exposed separately from the output path.
 //   - Every output path is backed by an InMemoryPrepopulatedDirectory,
 //     meaning that memory usage may be high.
 //   - No automatic garbage collection of old output paths is performed.
@@ -67,53 +193,515 @@ type outputPathState struct {
 type RemoteOutputServiceDirectory struct {
 	virtual.ReadOnlyDirectory
 
-	handleAllocator                   virtual.StatefulHandleAllocator
-	handle                            virtual.StatefulDirectoryHandle
-	outputPathFactory                 OutputPathFactory
-	bareContentAddressableStorage     blobstore.BlobAccess
-	retryingContentAddressableStorage blobstore.BlobAccess
-	directoryFetcher                  re_cas.DirectoryFetcher
-	symlinkFactory                    virtual.SymlinkFactory
-	maximumTreeSizeBytes              int64
+	handleAllocator                       virtual.StatefulHandleAllocator
+	handle                                virtual.StatefulDirectoryHandle
+	outputPathFactory                     OutputPathFactory
+	bareContentAddressableStorage         blobstore.BlobAccess
+	retryingContentAddressableStorage     blobstore.BlobAccess
+	directoryFetcher                      re_cas.DirectoryFetcher
+	symlinkFactory                        virtual.SymlinkFactory
+	authorizer                            auth.Authorizer
+	maximumTreeSizeBytes                  int64
+	maximumSymlinkRedirections            int
+	maximumPathDepth                      int
+	maximumSymlinkTargetLength            int
+	maximumBatchStatResponseSizeBytes     int
+	exportUploadConcurrency               int
+	maximumEntryRemovalEvents             int
+	findMissingBlobsBatchSize             int
+	startBuildConcurrency                 *semaphore.Weighted
+	maximumStartBuildDuration             time.Duration
+	staleBuildGracePeriod                 time.Duration
+	maximumLookupRetryDelay               time.Duration
+	maximumConcurrentFetchesPerOutputPath int64
+	fileFetchWeight                       int64
+	directoryFetchWeight                  int64
+	maximumConcurrentBuilds               int
+	retainedFinalizedBuilds               int
+	finalizedBuildsDirectoryName          path.Component
+	boundDirectories                      []boundDirectory
+	copyFilesAcrossInstanceNames          bool
+	rewriteAbsoluteSymlinkTargets         bool
+	rejectInstanceNameChanges             bool
+	finalizeBuildWaitsForFetches          bool
+	metricsOutputBaseIDs                  map[string]struct{}
+	outputPathStorageSelector             OutputPathStorageSelector
+	outputPathErrorLoggerFactory          OutputPathErrorLoggerFactory
+	directoryHandleTracker                *DirectoryHandleTracker
+	batchCreateFailureLogger              util.ErrorLogger
+	logBatchCreateFailurePaths            bool
+	syscallErrorMapping                   SyscallErrorMapping
+	clock                                 clock.Clock
+	defaultDigestFunction                 remoteexecution.DigestFunction_Value
+	preloadDigestFunction                 *digest.Function
+	aggregateFetchStatistics              *outputPathFetchStatistics
+	virtualOperationTimers                virtualOperationTimers
+	casFiles                              atomic.Int64
+	finalizedBuildsDirectory              *finalizedBuildsDirectory
+	buildEvents                           *buildEventLog
 
-	lock          sync.Mutex
-	changeID      uint64
-	outputBaseIDs map[path.Component]*outputPathState
-	buildIDs      map[string]*outputPathState
-	outputPaths   outputPathState
+	lock                  sync.Mutex
+	changeID              uint64
+	outputBaseIDs         map[outputBasePath]*outputPathState
+	outputBaseGroups      map[outputBasePath]*outputBaseGroupDirectory
+	buildIDs              map[string]*outputPathState
+	rootSymlinks          map[path.Component]*rootSymlink
+	finalizedBuilds       map[outputBasePath][]*finalizedBuild
+	finalizedBuildsByName map[path.Component]*finalizedBuild
+}
+
+// rootSymlink is a symbolic link stored in the top-level directory,
+// alongside the output paths. These are created by calling
+// CreateRootSymlink().
+type rootSymlink struct {
+	leaf         virtual.NativeLeaf
+	outputBaseID outputBasePath
+	cookie       uint64
 }
 
 var (
 	_ virtual.Directory                             = &RemoteOutputServiceDirectory{}
 	_ remoteoutputservice.RemoteOutputServiceServer = &RemoteOutputServiceDirectory{}
+	_ outputpaths.OutputPathsServer                 = &RemoteOutputServiceDirectory{}
 )
 
+// RemoteOutputServiceDirectoryOptions contains the optional parameters
+// of NewRemoteOutputServiceDirectory(). Fields that are left at their
+// zero value are assigned the defaults that are documented below.
+type RemoteOutputServiceDirectoryOptions struct {
+	// BatchStat() and StatStream() expand at most
+	// MaximumSymlinkRedirections symbolic links per path. Paths for
+	// which this limit is exceeded are reported as being external,
+	// referring to the path as resolved so far. This causes the
+	// client to stat() the path itself, which will fail with ELOOP
+	// in case of a cycle. Defaults to 40.
+	MaximumSymlinkRedirections int
+
+	// Paths provided to BatchCreate() and BatchStat() may not
+	// traverse more than MaximumPathDepth directories. This bounds
+	// the amount of memory needed to resolve pathologically deep
+	// paths. Defaults to 1024.
+	MaximumPathDepth int
+
+	// Targets of symbolic links created through BatchCreate() may
+	// not be longer than MaximumSymlinkTargetLength bytes. Defaults
+	// to 4096.
+	MaximumSymlinkTargetLength int
+
+	// If MaximumBatchStatResponseSizeBytes is positive, calls to
+	// BatchStat() whose response would exceed this size fail with
+	// RESOURCE_EXHAUSTED. This allows clients to split these
+	// requests, as opposed to receiving opaque errors from the gRPC
+	// library.
+	MaximumBatchStatResponseSizeBytes int
+
+	// StartBuild() calls FindMissingBlobs() against the Content
+	// Addressable Storage for all files and directories contained
+	// in the output path, passing at most FindMissingBlobsBatchSize
+	// digests per call. Defaults to
+	// blobstore.RecommendedFindMissingDigestsCount.
+	FindMissingBlobsBatchSize int
+
+	// When converting the contents of an output path to a Tree
+	// object, files that are not backed by the Content Addressable
+	// Storage are uploaded. At most ExportUploadConcurrency files
+	// are uploaded concurrently per conversion. Defaults to 1.
+	ExportUploadConcurrency int
+
+	// Entries that are removed from the output path while its
+	// contents are checked for existence are counted. In addition
+	// to that, at most MaximumEntryRemovalEvents ENTRY_REMOVED
+	// events are emitted through WatchBuilds() per check, reporting
+	// the digest of each entry that was removed.
+	MaximumEntryRemovalEvents int
+
+	// As checking the contents of output paths may put a
+	// significant amount of load on the Content Addressable
+	// Storage, the number of output paths that are traversed
+	// concurrently is limited by StartBuildConcurrency. Calls to
+	// StartBuild() that exceed this limit block until capacity
+	// becomes available. Other calls that traverse output paths in
+	// full, such as ExportTree() and VerifyTree(), share the same
+	// limit. If unset, the number of traversals is not limited.
+	StartBuildConcurrency *semaphore.Weighted
+
+	// If MaximumStartBuildDuration is non-zero, StartBuild() fails
+	// if checking the contents of the output path takes longer than
+	// the provided duration, regardless of the deadline provided by
+	// the client.
+	MaximumStartBuildDuration time.Duration
+
+	// If StartBuild() is called for an output base that still has a
+	// build running, the existing build is finalized forcefully. If
+	// StaleBuildGracePeriod is non-zero, StartBuild() instead fails
+	// if the existing build has been active within the provided
+	// duration, as it is likely still in use by another client.
+	StaleBuildGracePeriod time.Duration
+
+	// Directories in output paths may need to be loaded from the
+	// Content Addressable Storage while resolving paths provided to
+	// BatchStat(). If MaximumLookupRetryDelay is non-zero, lookups
+	// that fail with an infrastructure error are retried using
+	// exponential backoff, for at most the provided duration.
+	MaximumLookupRetryDelay time.Duration
+
+	// Files in output paths are loaded from the Content Addressable
+	// Storage lazily. The number of fetches that may be in flight
+	// for a single output path is limited by
+	// MaximumConcurrentFetchesPerOutputPath. Reads that exceed this
+	// limit block until other fetches complete. If unset, the
+	// number of fetches is not limited.
+	MaximumConcurrentFetchesPerOutputPath int64
+
+	// If both FileFetchWeight and DirectoryFetchWeight are
+	// non-zero, fetches of directories are subject to the same
+	// limit as fetches of files. While both kinds of fetches are
+	// blocked, slots are handed out in proportion to these weights.
+	FileFetchWeight      int64
+	DirectoryFetchWeight int64
+
+	// The number of builds that may be running at the same time
+	// across all output bases is limited by
+	// MaximumConcurrentBuilds. If unset, the number of builds is
+	// not limited.
+	MaximumConcurrentBuilds int
+
+	// If RetainedFinalizedBuilds is non-zero, the contents of output
+	// paths are uploaded to the Content Addressable Storage when
+	// builds are finalized. The most recent RetainedFinalizedBuilds
+	// builds of every output base remain accessible through a
+	// directory named FinalizedBuildsDirectoryName, which defaults
+	// to DefaultFinalizedBuildsDirectoryName.
+	RetainedFinalizedBuilds      int
+	FinalizedBuildsDirectoryName path.Component
+
+	// Every output path contains the directories provided in
+	// BoundDirectories at its root, whose contents are loaded from
+	// Tree objects in the Content Addressable Storage. These
+	// directories are recreated every time the output path is
+	// checked for existence by StartBuild(), and may not be
+	// modified through BatchCreate().
+	BoundDirectories map[path.Component]*remoteexecution.Digest
+
+	// If CopyFilesAcrossInstanceNames is set, files in the output
+	// path that use a different instance name than the one provided
+	// to StartBuild() are copied into the new instance name, as
+	// opposed to being removed.
+	CopyFilesAcrossInstanceNames bool
+
+	// If RewriteAbsoluteSymlinkTargets is set, absolute targets of
+	// symbolic links created through BatchCreate() that refer to
+	// locations inside the output path are converted to relative
+	// paths.
+	RewriteAbsoluteSymlinkTargets bool
+
+	// If RejectInstanceNameChanges is set, StartBuild() fails if the
+	// instance name differs from the one used by the previous build
+	// of the same output base.
+	RejectInstanceNameChanges bool
+
+	// If FinalizeBuildWaitsForFetches is set, FinalizeBuild() blocks
+	// until all fetches against the Content Addressable Storage
+	// that are in progress on behalf of the output path have
+	// completed.
+	FinalizeBuildWaitsForFetches bool
+
+	// The latency of fetches against the Content Addressable
+	// Storage is exposed through Prometheus metrics. Only output
+	// bases whose ID is part of MetricsOutputBaseIDs are labeled
+	// with their output base ID, so that the cardinality of these
+	// metrics remains bounded.
+	MetricsOutputBaseIDs []string
+
+	// If OutputPathStorageSelector is set, it is used to select the
+	// backends that output paths use to access the Content
+	// Addressable Storage. This permits isolating the traffic of
+	// tenants.
+	OutputPathStorageSelector OutputPathStorageSelector
+
+	// Errors that occur while lazily loading the contents of output
+	// paths are forwarded to util.DefaultErrorLogger, unless
+	// OutputPathErrorLoggerFactory is set. In that case it is used
+	// to obtain an ErrorLogger for every output path.
+	OutputPathErrorLoggerFactory OutputPathErrorLoggerFactory
+
+	// If DirectoryHandleTracker is set, InvalidateCache() may be
+	// used to drop state cached by the kernel for entries in output
+	// paths. The tracker must be attached to the handle allocator
+	// that is used by the OutputPathFactory.
+	DirectoryHandleTracker *DirectoryHandleTracker
+
+	// If BatchCreateFailureLogger is set, failed calls to
+	// BatchCreate() are logged, together with the path prefix and
+	// the number of entries in the request. As the paths of the
+	// entries may be large in number and sensitive, they are only
+	// included if LogBatchCreateFailurePaths is set.
+	BatchCreateFailureLogger   util.ErrorLogger
+	LogBatchCreateFailurePaths bool
+
+	// Syscall errors that occur while resolving paths provided to
+	// BatchStat() and BatchCreate() are converted to gRPC status
+	// codes using SyscallErrorMapping. Defaults to
+	// DefaultSyscallErrorMapping.
+	SyscallErrorMapping SyscallErrorMapping
+
+	// If StartBuild() is called without specifying a digest
+	// function, DefaultDigestFunction is used instead. If unset,
+	// clients are required to always specify one.
+	DefaultDigestFunction remoteexecution.DigestFunction_Value
+
+	// If PreloadDigestFunction is set, output paths that have
+	// persistent state are created as soon as they are looked up,
+	// using the provided digest function. This makes the results of
+	// the previous build accessible, even if no build has been
+	// started yet.
+	PreloadDigestFunction *digest.Function
+}
+
 // NewRemoteOutputServiceDirectory creates a new instance of
 // RemoteOutputServiceDirectory.
-func NewRemoteOutputServiceDirectory(handleAllocator virtual.StatefulHandleAllocator, outputPathFactory OutputPathFactory, bareContentAddressableStorage, retryingContentAddressableStorage blobstore.BlobAccess, directoryFetcher re_cas.DirectoryFetcher, symlinkFactory virtual.SymlinkFactory, maximumTreeSizeBytes int64) *RemoteOutputServiceDirectory {
+//
+// The authorizer is consulted before performing operations that modify
+// the contents of an output path, or the state of a build. The output
+// base ID is provided to the authorizer in the form of an instance
+// name.
+func NewRemoteOutputServiceDirectory(handleAllocator virtual.StatefulHandleAllocator, outputPathFactory OutputPathFactory, bareContentAddressableStorage, retryingContentAddressableStorage blobstore.BlobAccess, directoryFetcher re_cas.DirectoryFetcher, symlinkFactory virtual.SymlinkFactory, authorizer auth.Authorizer, maximumTreeSizeBytes int64, clock clock.Clock, options RemoteOutputServiceDirectoryOptions) *RemoteOutputServiceDirectory {
+	if options.MaximumSymlinkRedirections <= 0 {
+		options.MaximumSymlinkRedirections = 40
+	}
+	if options.MaximumPathDepth <= 0 {
+		options.MaximumPathDepth = 1024
+	}
+	if options.MaximumSymlinkTargetLength <= 0 {
+		options.MaximumSymlinkTargetLength = 4096
+	}
+	if options.FindMissingBlobsBatchSize <= 0 {
+		options.FindMissingBlobsBatchSize = blobstore.RecommendedFindMissingDigestsCount
+	}
+	if options.ExportUploadConcurrency <= 0 {
+		options.ExportUploadConcurrency = 1
+	}
+	if options.StartBuildConcurrency == nil {
+		options.StartBuildConcurrency = semaphore.NewWeighted(math.MaxInt64)
+	}
+	if options.MaximumConcurrentFetchesPerOutputPath <= 0 {
+		options.MaximumConcurrentFetchesPerOutputPath = math.MaxInt64
+	}
+	if options.MaximumConcurrentBuilds <= 0 {
+		options.MaximumConcurrentBuilds = math.MaxInt
+	}
+	if options.FinalizedBuildsDirectoryName == (path.Component{}) {
+		options.FinalizedBuildsDirectoryName = DefaultFinalizedBuildsDirectoryName
+	}
+	if options.SyscallErrorMapping == nil {
+		options.SyscallErrorMapping = DefaultSyscallErrorMapping
+	}
+
 	d := &RemoteOutputServiceDirectory{
-		handleAllocator:                   handleAllocator,
-		outputPathFactory:                 outputPathFactory,
-		bareContentAddressableStorage:     bareContentAddressableStorage,
-		retryingContentAddressableStorage: retryingContentAddressableStorage,
-		directoryFetcher:                  directoryFetcher,
-		symlinkFactory:                    symlinkFactory,
-		maximumTreeSizeBytes:              maximumTreeSizeBytes,
+		handleAllocator:                       handleAllocator,
+		outputPathFactory:                     outputPathFactory,
+		bareContentAddressableStorage:         bareContentAddressableStorage,
+		retryingContentAddressableStorage:     retryingContentAddressableStorage,
+		directoryFetcher:                      directoryFetcher,
+		symlinkFactory:                        symlinkFactory,
+		authorizer:                            authorizer,
+		maximumTreeSizeBytes:                  maximumTreeSizeBytes,
+		maximumSymlinkRedirections:            options.MaximumSymlinkRedirections,
+		maximumPathDepth:                      options.MaximumPathDepth,
+		maximumSymlinkTargetLength:            options.MaximumSymlinkTargetLength,
+		maximumBatchStatResponseSizeBytes:     options.MaximumBatchStatResponseSizeBytes,
+		exportUploadConcurrency:               options.ExportUploadConcurrency,
+		maximumEntryRemovalEvents:             options.MaximumEntryRemovalEvents,
+		findMissingBlobsBatchSize:             options.FindMissingBlobsBatchSize,
+		startBuildConcurrency:                 options.StartBuildConcurrency,
+		maximumStartBuildDuration:             options.MaximumStartBuildDuration,
+		staleBuildGracePeriod:                 options.StaleBuildGracePeriod,
+		maximumLookupRetryDelay:               options.MaximumLookupRetryDelay,
+		maximumConcurrentFetchesPerOutputPath: options.MaximumConcurrentFetchesPerOutputPath,
+		fileFetchWeight:                       options.FileFetchWeight,
+		directoryFetchWeight:                  options.DirectoryFetchWeight,
+		maximumConcurrentBuilds:               options.MaximumConcurrentBuilds,
+		retainedFinalizedBuilds:               options.RetainedFinalizedBuilds,
+		finalizedBuildsDirectoryName:          options.FinalizedBuildsDirectoryName,
+		copyFilesAcrossInstanceNames:          options.CopyFilesAcrossInstanceNames,
+		rewriteAbsoluteSymlinkTargets:         options.RewriteAbsoluteSymlinkTargets,
+		rejectInstanceNameChanges:             options.RejectInstanceNameChanges,
+		finalizeBuildWaitsForFetches:          options.FinalizeBuildWaitsForFetches,
+		outputPathStorageSelector:             options.OutputPathStorageSelector,
+		outputPathErrorLoggerFactory:          options.OutputPathErrorLoggerFactory,
+		directoryHandleTracker:                options.DirectoryHandleTracker,
+		batchCreateFailureLogger:              options.BatchCreateFailureLogger,
+		logBatchCreateFailurePaths:            options.LogBatchCreateFailurePaths,
+		syscallErrorMapping:                   options.SyscallErrorMapping,
+		clock:                                 clock,
+		defaultDigestFunction:                 options.DefaultDigestFunction,
+		preloadDigestFunction:                 options.PreloadDigestFunction,
+		aggregateFetchStatistics:              newAggregateFetchStatistics(),
+		virtualOperationTimers:                newVirtualOperationTimers(clock),
+		buildEvents:                           newBuildEventLog(),
 
-		outputBaseIDs: map[path.Component]*outputPathState{},
-		buildIDs:      map[string]*outputPathState{},
+		outputBaseIDs:         map[outputBasePath]*outputPathState{},
+		outputBaseGroups:      map[outputBasePath]*outputBaseGroupDirectory{},
+		buildIDs:              map[string]*outputPathState{},
+		rootSymlinks:          map[path.Component]*rootSymlink{},
+		finalizedBuilds:       map[outputBasePath][]*finalizedBuild{},
+		finalizedBuildsByName: map[path.Component]*finalizedBuild{},
+	}
+	for name, treeDigest := range options.BoundDirectories {
+		d.boundDirectories = append(d.boundDirectories, boundDirectory{
+			name:       name,
+			treeDigest: treeDigest,
+		})
+	}
+	sort.Slice(d.boundDirectories, func(i, j int) bool {
+		return d.boundDirectories[i].name.String() < d.boundDirectories[j].name.String()
+	})
+	d.metricsOutputBaseIDs = make(map[string]struct{}, len(options.MetricsOutputBaseIDs))
+	for _, outputBaseID := range options.MetricsOutputBaseIDs {
+		d.metricsOutputBaseIDs[outputBaseID] = struct{}{}
+	}
+	if options.RetainedFinalizedBuilds > 0 {
+		d.finalizedBuildsDirectory = &finalizedBuildsDirectory{
+			service: d,
+			cookie:  d.changeID,
+		}
+		d.finalizedBuildsDirectory.handle = handleAllocator.New().AsStatefulDirectory(d.finalizedBuildsDirectory)
+		d.changeID++
 	}
 	d.handle = handleAllocator.New().AsStatefulDirectory(d)
-	d.outputPaths.previous = &d.outputPaths
-	d.outputPaths.next = &d.outputPaths
 	return d
 }
 
+// authorizeOutputBase checks whether the caller is permitted to modify
+// the contents of an output path, or the state of a build running
+// against it. This function must be called without holding the
+// directory lock, as authorizers may block.
+func (d *RemoteOutputServiceDirectory) authorizeOutputBase(ctx context.Context, outputBaseID outputBasePath) error {
+	instanceName, err := digest.NewInstanceName(outputBaseID.String())
+	if err != nil {
+		return util.StatusWrap(err, "Output base ID cannot be used as an instance name for authorization")
+	}
+	if err := auth.AuthorizeSingleInstanceName(ctx, d.authorizer, instanceName); err != nil {
+		return util.StatusWrap(err, "Authorization")
+	}
+	return nil
+}
+
 // Clean all build outputs associated with a single output base.
 func (d *RemoteOutputServiceDirectory) Clean(ctx context.Context, request *remoteoutputservice.CleanRequest) (*emptypb.Empty, error) {
-	outputBaseID, ok := path.NewComponent(request.OutputBaseId)
-	if !ok {
-		return nil, status.Error(codes.InvalidArgument, "Output base ID is not a valid filename")
+	if err := d.clean(ctx, request, nil); err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// CleanStream is identical to Clean(), except that the number of
+// entries removed from the output path is streamed to the client.
+func (d *RemoteOutputServiceDirectory) CleanStream(request *remoteoutputservice.CleanRequest, server outputpaths.OutputPaths_CleanStreamServer) error {
+	progress := cleanProgress{
+		send: func(response *outputpaths.CleanStreamResponse) error {
+			return server.Send(response)
+		},
+	}
+	if err := d.clean(server.Context(), request, &progress); err != nil {
+		return err
+	}
+	return progress.report()
+}
+
+// cleanProgressInterval is the number of entries that CleanStream()
+// removes between sending progress messages to the client.
+const cleanProgressInterval = 1000
+
+// cleanProgress keeps track of the number of entries removed by
+// CleanStream(), so that it can be reported to the client.
+type cleanProgress struct {
+	entriesRemoved uint64
+	send           func(*outputpaths.CleanStreamResponse) error
+}
+
+func (p *cleanProgress) report() error {
+	return p.send(&outputpaths.CleanStreamResponse{
+		EntriesRemoved: p.entriesRemoved,
+	})
+}
+
+// entryRemoved records that a single entry has been removed,
+// periodically reporting progress to the client. It returns an error if
+// removal should be interrupted.
+func (p *cleanProgress) entryRemoved(ctx context.Context) error {
+	p.entriesRemoved++
+	if p.entriesRemoved%cleanProgressInterval == 0 {
+		if err := p.report(); err != nil {
+			return err
+		}
+	}
+	return util.StatusFromContext(ctx)
+}
+
+// removeChildrenWithProgress removes the contents of an output path one
+// entry at a time, periodically reporting progress. Unlike
+// RemoveAllChildren(), this can be interrupted, in which case the
+// entries that have not been visited yet remain present.
+//
+// FilterChildren() is used to traverse the output path, as it doesn't
+// cause directories whose contents have not been loaded to be fetched
+// from the Content Addressable Storage. As it only reports files and
+// directories whose contents have not been loaded, the directories
+// that remain are removed afterwards.
+func removeChildrenWithProgress(ctx context.Context, rootDirectory virtual.PrepopulatedDirectory, progress *cleanProgress) error {
+	var errIteration error
+	if err := rootDirectory.FilterChildren(func(node virtual.InitialNode, remove virtual.ChildRemover) bool {
+		if err := remove(); err != nil {
+			errIteration = util.StatusWrap(err, "Failed to remove entry from output path")
+			return false
+		}
+		if err := progress.entryRemoved(ctx); err != nil {
+			errIteration = err
+			return false
+		}
+		return true
+	}); err != nil {
+		return err
+	}
+	if errIteration != nil {
+		return errIteration
+	}
+	return removeDirectoriesWithProgress(ctx, rootDirectory, progress)
+}
+
+// removeDirectoriesWithProgress removes the directories contained in a
+// directory, after their contents have been removed by
+// removeChildrenWithProgress().
+func removeDirectoriesWithProgress(ctx context.Context, directory virtual.PrepopulatedDirectory, progress *cleanProgress) error {
+	directories, _, err := directory.LookupAllChildren()
+	if err != nil {
+		return util.StatusWrap(err, "Failed to look up directories in output path")
+	}
+	for _, entry := range directories {
+		if err := removeDirectoriesWithProgress(ctx, entry.Child, progress); err != nil {
+			return err
+		}
+		if err := directory.RemoveAll(entry.Name); err != nil {
+			return util.StatusWrapf(err, "Failed to remove directory %#v from output path", entry.Name.String())
+		}
+		if err := progress.entryRemoved(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// clean removes all build outputs associated with a single output
+// base. If progress is set, the contents of the output path are
+// removed incrementally, reporting progress along the way.
+func (d *RemoteOutputServiceDirectory) clean(ctx context.Context, request *remoteoutputservice.CleanRequest, progress *cleanProgress) error {
+	outputBaseID, err := newOutputBasePath(request.OutputBaseId)
+	if err != nil {
+		return err
+	}
+	if err := d.authorizeOutputBase(ctx, outputBaseID); err != nil {
+		return err
 	}
 
 	d.lock.Lock()
@@ -124,43 +712,299 @@ func (d *RemoteOutputServiceDirectory) Clean(ctx context.Context, request *remot
 		// must be done without holding the directory lock, as
 		// NotifyRemoval() calls generated by the output path
 		// could deadlock otherwise.
+		if progress != nil {
+			if err := removeChildrenWithProgress(ctx, outputPathState.rootDirectory, progress); err != nil {
+				return err
+			}
+		}
 		if err := outputPathState.rootDirectory.RemoveAllChildren(true); err != nil {
-			return nil, err
+			return err
 		}
 
+		var removals []directoryEntryRemoval
 		d.lock.Lock()
 		if outputPathState == d.outputBaseIDs[outputBaseID] {
 			delete(d.outputBaseIDs, outputBaseID)
-			outputPathState.previous.next = outputPathState.next
-			outputPathState.next.previous = outputPathState.previous
 			d.changeID++
 			if buildState := outputPathState.buildState; buildState != nil {
 				delete(d.buildIDs, buildState.id)
 				outputPathState.buildState = nil
+				d.buildEvents.append(outputpaths.BuildEvent_BUILD_ABORTED, outputBaseID, buildState.id, d.clock.Now())
 			}
+			removals = d.removeEmptyOutputBaseGroupsLocked(outputBaseID)
+		}
+		for _, name := range d.removeRootSymlinksLocked(outputBaseID) {
+			removals = append(removals, directoryEntryRemoval{handle: d.handle, name: name})
 		}
+		removals = append(removals, d.removeFinalizedBuildsLocked(d.finalizedBuilds[outputBaseID])...)
+		delete(d.finalizedBuilds, outputBaseID)
 		d.lock.Unlock()
 
-		d.handle.NotifyRemoval(outputBaseID)
-	} else if err := d.outputPathFactory.Clean(outputBaseID); err != nil {
+		for _, removal := range removals {
+			removal.notify()
+		}
+	} else if err := d.outputPathFactory.Clean(outputBaseID.getFlattenedName()); err != nil {
 		// This output path hasn't been accessed since startup.
 		// It may be the case that there is persistent state
 		// associated with this output path, so make sure that
 		// is removed as well.
+		return err
+	}
+	d.buildEvents.append(outputpaths.BuildEvent_OUTPUT_BASE_CLEANED, outputBaseID, "", d.clock.Now())
+	return nil
+}
+
+// RenameOutputBase changes the output base ID of an output path that
+// has no running build, while preserving its contents.
+func (d *RemoteOutputServiceDirectory) RenameOutputBase(ctx context.Context, request *outputpaths.RenameOutputBaseRequest) (*emptypb.Empty, error) {
+	outputBaseID, err := newOutputBasePath(request.OutputBaseId)
+	if err != nil {
+		return nil, err
+	}
+	newOutputBaseID, err := newOutputBasePath(request.NewOutputBaseId)
+	if err != nil {
+		return nil, util.StatusWrap(err, "Invalid new output base ID")
+	}
+	if newOutputBaseID.getComponents()[0] == d.finalizedBuildsDirectoryName {
+		return nil, status.Errorf(codes.InvalidArgument, "New output base ID may not start with reserved name %#v", d.finalizedBuildsDirectoryName.String())
+	}
+	if err := d.authorizeOutputBase(ctx, outputBaseID); err != nil {
+		return nil, err
+	}
+	if err := d.authorizeOutputBase(ctx, newOutputBaseID); err != nil {
+		return nil, err
+	}
+
+	// Rename any persistent state associated with the output path
+	// prior to updating the directory hierarchy, so that failures
+	// leave the output path accessible under its current name. This
+	// must be done without holding the directory lock, as the output
+	// path may traverse its contents.
+	d.lock.Lock()
+	outputPathState, err := d.getRenamableOutputPathLocked(outputBaseID, newOutputBaseID)
+	d.lock.Unlock()
+	if err != nil {
 		return nil, err
 	}
+	if err := outputPathState.rootDirectory.Rename(newOutputBaseID.getFlattenedName()); err != nil {
+		return nil, util.StatusWrap(err, "Failed to rename output path")
+	}
+
+	// Validate the request once more, as builds may have been
+	// started in the meantime. If so, revert the rename.
+	d.lock.Lock()
+	if current, err := d.getRenamableOutputPathLocked(outputBaseID, newOutputBaseID); err != nil || current != outputPathState {
+		d.lock.Unlock()
+		if err == nil {
+			err = status.Error(codes.Aborted, "Output base ID was associated with another output path while renaming")
+		}
+		if errRevert := outputPathState.rootDirectory.Rename(outputBaseID.getFlattenedName()); errRevert != nil {
+			outputPathState.errorLogger.Log(util.StatusWrap(errRevert, "Failed to revert rename of output path"))
+		}
+		return nil, err
+	}
+
+	// Remove the output path from its current location, including
+	// any symbolic links that belong to it.
+	delete(d.outputBaseIDs, outputBaseID)
+	removals := d.removeEmptyOutputBaseGroupsLocked(outputBaseID)
+	for _, name := range d.removeRootSymlinksLocked(outputBaseID) {
+		removals = append(removals, directoryEntryRemoval{handle: d.handle, name: name})
+	}
+
+	// Add the output path under its new name. The kernel may have
+	// cached the absence of any of the directories leading up to
+	// it, so invalidate those as well.
+	d.createOutputBaseGroupsLocked(newOutputBaseID)
+	outputPathState.outputBaseID = newOutputBaseID
+	outputPathState.cookie = d.changeID
+	d.outputBaseIDs[newOutputBaseID] = outputPathState
+	d.changeID++
+	for id := newOutputBaseID; id != (outputBasePath{}); {
+		parentID, name := id.getParent()
+		removals = append(removals, directoryEntryRemoval{
+			handle: d.getDirectoryHandleLocked(parentID),
+			name:   name,
+		})
+		id = parentID
+	}
+
+	if builds, ok := d.finalizedBuilds[outputBaseID]; ok {
+		delete(d.finalizedBuilds, outputBaseID)
+		d.finalizedBuilds[newOutputBaseID] = builds
+	}
+	d.lock.Unlock()
+
+	for _, removal := range removals {
+		removal.notify()
+	}
 	return &emptypb.Empty{}, nil
 }
 
+// getRenamableOutputPathLocked returns the output path associated with
+// an output base ID, if it may be renamed to a new output base ID.
+func (d *RemoteOutputServiceDirectory) getRenamableOutputPathLocked(outputBaseID, newOutputBaseID outputBasePath) (*outputPathState, error) {
+	outputPathState, ok := d.outputBaseIDs[outputBaseID]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "Output base ID is not associated with any output path")
+	}
+	if outputPathState.buildState != nil {
+		return nil, status.Error(codes.FailedPrecondition, "Output base has a running build")
+	}
+	if _, ok := d.outputBaseIDs[newOutputBaseID]; ok {
+		return nil, status.Error(codes.AlreadyExists, "New output base ID is already associated with an output path")
+	}
+	if err := d.checkOutputBaseIDConflictsLocked(newOutputBaseID); err != nil {
+		return nil, err
+	}
+	return outputPathState, nil
+}
+
+// removeRootSymlinksLocked removes all symbolic links in the top-level
+// directory that belong to a given output base. The names of the
+// symbolic links are returned, so that the caller can call
+// NotifyRemoval() after dropping the directory lock.
+func (d *RemoteOutputServiceDirectory) removeRootSymlinksLocked(outputBaseID outputBasePath) []path.Component {
+	var names []path.Component
+	for name, symlink := range d.rootSymlinks {
+		if symlink.outputBaseID == outputBaseID {
+			delete(d.rootSymlinks, name)
+			symlink.leaf.Unlink()
+			names = append(names, name)
+		}
+	}
+	if len(names) > 0 {
+		d.changeID++
+	}
+	return names
+}
+
+// directoryEntryRemoval is a directory entry that has been removed
+// while holding the directory lock. NotifyRemoval() needs to be called
+// after the lock has been released.
+type directoryEntryRemoval struct {
+	handle  virtual.StatefulDirectoryHandle
+	name    path.Component
+	release virtual.StatefulDirectoryHandle
+}
+
+func (r *directoryEntryRemoval) notify() {
+	r.handle.NotifyRemoval(r.name)
+	if r.release != nil {
+		r.release.Release()
+	}
+}
+
+// getDirectoryHandleLocked returns the handle of the directory
+// corresponding to an output base ID, which is either the root
+// directory or one of the output base group directories.
+func (d *RemoteOutputServiceDirectory) getDirectoryHandleLocked(id outputBasePath) virtual.StatefulDirectoryHandle {
+	if id == (outputBasePath{}) {
+		return d.handle
+	}
+	return d.outputBaseGroups[id].handle
+}
+
+// removeEmptyOutputBaseGroupsLocked is called after an output path is
+// removed. It removes the output base group directories containing it
+// that have become empty. The removals of the output path and the
+// output base group directories are returned, so that the caller can
+// call NotifyRemoval() after dropping the directory lock.
+func (d *RemoteOutputServiceDirectory) removeEmptyOutputBaseGroupsLocked(removedID outputBasePath) []directoryEntryRemoval {
+	parentID, name := removedID.getParent()
+	removals := []directoryEntryRemoval{{
+		handle: d.getDirectoryHandleLocked(parentID),
+		name:   name,
+	}}
+	for parentID != (outputBasePath{}) && d.countChildrenLocked(parentID) == 0 {
+		group := d.outputBaseGroups[parentID]
+		delete(d.outputBaseGroups, parentID)
+		parentID, name = parentID.getParent()
+		removals = append(removals, directoryEntryRemoval{
+			handle:  d.getDirectoryHandleLocked(parentID),
+			name:    name,
+			release: group.handle,
+		})
+	}
+	return removals
+}
+
+// countChildrenLocked returns the number of output paths and output
+// base group directories contained in a directory.
+func (d *RemoteOutputServiceDirectory) countChildrenLocked(id outputBasePath) int {
+	count := 0
+	for childID := range d.outputBaseIDs {
+		if parentID, _ := childID.getParent(); parentID == id {
+			count++
+		}
+	}
+	for childID := range d.outputBaseGroups {
+		if parentID, _ := childID.getParent(); parentID == id {
+			count++
+		}
+	}
+	return count
+}
+
+// checkOutputBaseIDConflictsLocked checks whether an output path for a
+// given output base ID can be created. This is not possible if one of
+// the prefixes of the output base ID is used by another output path,
+// or if the output base ID is a prefix of other output base IDs.
+func (d *RemoteOutputServiceDirectory) checkOutputBaseIDConflictsLocked(id outputBasePath) error {
+	if _, ok := d.outputBaseGroups[id]; ok {
+		return status.Errorf(codes.FailedPrecondition, "Output base ID %#v is a prefix of the output base IDs of other output paths", id.String())
+	}
+	for parentID, _ := id.getParent(); parentID != (outputBasePath{}); parentID, _ = parentID.getParent() {
+		if _, ok := d.outputBaseIDs[parentID]; ok {
+			return status.Errorf(codes.FailedPrecondition, "Output base ID %#v is a prefix of output base ID %#v", parentID.String(), id.String())
+		}
+	}
+	return nil
+}
+
+// startBuildProgress keeps track of the progress made by
+// filterMissingChildren(). If send is set, the progress is reported
+// every time a batch of objects has been checked for existence.
+//
+// Counters are updated atomically, as GetActiveBuild() may read them
+// while filterMissingChildren() is running.
+type startBuildProgress struct {
+	entriesScanned   atomic.Uint64
+	blobsChecked     atomic.Uint64
+	entriesRemoved   atomic.Uint64
+	findMissingCalls atomic.Uint64
+	queuedDigests    atomic.Uint64
+	send             func(*outputpaths.StartBuildProgress) error
+}
+
+func (p *startBuildProgress) getProto() *outputpaths.StartBuildProgress {
+	return &outputpaths.StartBuildProgress{
+		EntriesScanned:   p.entriesScanned.Load(),
+		BlobsChecked:     p.blobsChecked.Load(),
+		EntriesRemoved:   p.entriesRemoved.Load(),
+		FindMissingCalls: p.findMissingCalls.Load(),
+		QueuedDigests:    p.queuedDigests.Load(),
+	}
+}
+
+func (p *startBuildProgress) report() error {
+	if p.send == nil {
+		return nil
+	}
+	return p.send(p.getProto())
+}
+
 // findMissingAndRemove is called during StartBuild() to remove a single
 // batch of files from the output path that are no longer present in the
-// Content Addressable Storage.
-func (d *RemoteOutputServiceDirectory) findMissingAndRemove(ctx context.Context, queue map[digest.Digest][]func() error) error {
+// Content Addressable Storage. It is also used by ExtendedBatchCreate()
+// to check for the existence of Tree objects.
+func (d *RemoteOutputServiceDirectory) findMissingAndRemove(ctx context.Context, contentAddressableStorage blobstore.BlobAccess, queue map[digest.Digest][]func() error, progress *startBuildProgress) error {
 	set := digest.NewSetBuilder()
 	for digest := range queue {
 		set.Add(digest)
 	}
-	missing, err := d.bareContentAddressableStorage.FindMissing(ctx, set.Build())
+	progress.findMissingCalls.Add(1)
+	missing, err := contentAddressableStorage.FindMissing(ctx, set.Build())
 	if err != nil {
 		return util.StatusWrap(err, "Failed to find missing blobs")
 	}
@@ -171,17 +1015,87 @@ func (d *RemoteOutputServiceDirectory) findMissingAndRemove(ctx context.Context,
 			}
 		}
 	}
-	return nil
+	progress.blobsChecked.Add(uint64(len(queue)))
+	progress.queuedDigests.Store(0)
+	return progress.report()
+}
+
+// copyBlob copies a single blob stored in the Content Addressable
+// Storage between instance names.
+func copyBlob(ctx context.Context, contentAddressableStorage blobstore.BlobAccess, from, to digest.Digest) error {
+	b := contentAddressableStorage.Get(ctx, from)
+	return contentAddressableStorage.Put(ctx, to, buffer.NewCASBufferFromReader(to, b.ToReader(), buffer.UserProvided))
+}
+
+// newCopyOrRemoveFunc returns a function that is called by
+// findMissingAndRemove() for files that are absent in the instance name
+// used by the build, but present in the output path under a different
+// instance name. It attempts to copy the file into the new instance
+// name, falling back to removing the file if copying fails. Results are
+// cached, so that files sharing the same contents are copied once.
+func newCopyOrRemoveFunc(ctx context.Context, contentAddressableStorage blobstore.BlobAccess, from, to digest.Digest, copyResults map[digest.Digest]error, errorLogger util.ErrorLogger, removeFunc virtual.ChildRemover) func() error {
+	return func() error {
+		err, ok := copyResults[to]
+		if !ok {
+			err = copyBlob(ctx, contentAddressableStorage, from, to)
+			if err != nil {
+				errorLogger.Log(util.StatusWrapf(err, "Failed to copy file with digest %#v to instance name %#v", from.String(), to.GetInstanceName().String()))
+			}
+			copyResults[to] = err
+		}
+		if err != nil {
+			return removeFunc()
+		}
+		return nil
+	}
 }
 
 // filterMissingChildren is called during StartBuild() to traverse over
 // all files in the output path, calling FindMissingBlobs() on them to
 // ensure that they will not disappear during the build. Any files that
 // are missing are removed from the output path.
-func (d *RemoteOutputServiceDirectory) filterMissingChildren(ctx context.Context, rootDirectory virtual.PrepopulatedDirectory, digestFunction digest.Function) error {
+func (d *RemoteOutputServiceDirectory) filterMissingChildren(ctx context.Context, outputPathState *outputPathState, buildID string, digestFunction digest.Function, progress *startBuildProgress) error {
+	if d.startBuildConcurrency.Acquire(ctx, 1) != nil {
+		return util.StatusFromContext(ctx)
+	}
+	defer d.startBuildConcurrency.Release(1)
+
 	queue := map[digest.Digest][]func() error{}
+	copyResults := map[digest.Digest]error{}
+	contentAddressableStorage := outputPathState.bareContentAddressableStorage
+	remainingEntryRemovalEvents := d.maximumEntryRemovalEvents
 	var savedErr error
-	if err := rootDirectory.FilterChildren(func(node virtual.InitialNode, removeFunc virtual.ChildRemover) bool {
+	if err := outputPathState.rootDirectory.FilterChildren(func(node virtual.InitialNode, baseRemoveFunc virtual.ChildRemover) bool {
+		progress.entriesScanned.Add(1)
+
+		// Directories are queued once for every digest they
+		// contain, meaning this function may be called multiple
+		// times for the same node. Only count and report the
+		// first removal.
+		removed := false
+		removeFunc := func(reason outputpaths.BuildEvent_EntryRemoval_Reason, blobDigest *digest.Digest) error {
+			if removed {
+				return nil
+			}
+			if err := baseRemoveFunc(); err != nil {
+				return err
+			}
+			removed = true
+			progress.entriesRemoved.Add(1)
+			if remainingEntryRemovalEvents > 0 {
+				remainingEntryRemovalEvents--
+				entryRemoval := &outputpaths.BuildEvent_EntryRemoval{
+					Reason: reason,
+				}
+				if blobDigest != nil {
+					entryRemoval.Digest = blobDigest.GetProto()
+					entryRemoval.DigestFunction = blobDigest.GetDigestFunction().GetEnumValue()
+				}
+				d.buildEvents.appendEntryRemoval(outputPathState.outputBaseID, buildID, d.clock.Now(), entryRemoval)
+			}
+			return nil
+		}
+
 		// Obtain the transitive closure of digests on which
 		// this file or directory depends.
 		var digests digest.Set
@@ -193,7 +1107,7 @@ func (d *RemoteOutputServiceDirectory) filterMissingChildren(ctx context.Context
 			// entirely.
 			if status.Code(savedErr) == codes.NotFound {
 				savedErr = nil
-				if err := removeFunc(); err != nil {
+				if err := removeFunc(outputpaths.BuildEvent_EntryRemoval_DIRECTORY_UNAVAILABLE, nil); err != nil {
 					savedErr = util.StatusWrap(err, "Failed to remove non-existent directory")
 					return false
 				}
@@ -208,26 +1122,55 @@ func (d *RemoteOutputServiceDirectory) filterMissingChildren(ctx context.Context
 		// the build client to copy files between clusters, or
 		// reupload them with a different hash. This may be
 		// slower than requiring a rebuild.
+		//
+		// If enabled, files that only use a different instance
+		// name are copied into the new instance name if they
+		// are missing. They are only removed if copying fails.
+		type pendingDigest struct {
+			digest        digest.Digest
+			onMissingFunc func() error
+		}
+		pendingDigests := make([]pendingDigest, 0, digests.Length())
 		for _, blobDigest := range digests.Items() {
-			if !blobDigest.UsesDigestFunction(digestFunction) {
-				if err := removeFunc(); err != nil {
-					savedErr = util.StatusWrapf(err, "Failed to remove file with different instance name or digest function with digest %#v", blobDigest.String())
-					return false
+			blobDigest := blobDigest
+			if blobDigest.UsesDigestFunction(digestFunction) {
+				pendingDigests = append(pendingDigests, pendingDigest{
+					digest: blobDigest,
+					onMissingFunc: func() error {
+						return removeFunc(outputpaths.BuildEvent_EntryRemoval_MISSING, &blobDigest)
+					},
+				})
+				continue
+			}
+			if _, leaf := node.GetPair(); leaf != nil && d.copyFilesAcrossInstanceNames && blobDigest.GetDigestFunction().GetEnumValue() == digestFunction.GetEnumValue() {
+				if newDigest, err := digestFunction.NewDigest(blobDigest.GetHashString(), blobDigest.GetSizeBytes()); err == nil {
+					pendingDigests = append(pendingDigests, pendingDigest{
+						digest: newDigest,
+						onMissingFunc: newCopyOrRemoveFunc(ctx, contentAddressableStorage, blobDigest, newDigest, copyResults, outputPathState.errorLogger, func() error {
+							return removeFunc(outputpaths.BuildEvent_EntryRemoval_COPY_FAILED, &blobDigest)
+						}),
+					})
+					continue
 				}
-				return true
 			}
+			if err := removeFunc(outputpaths.BuildEvent_EntryRemoval_INCOMPATIBLE_DIGEST, &blobDigest); err != nil {
+				savedErr = util.StatusWrapf(err, "Failed to remove file with different instance name or digest function with digest %#v", blobDigest.String())
+				return false
+			}
+			return true
 		}
 
-		for _, blobDigest := range digests.Items() {
-			if len(queue) >= blobstore.RecommendedFindMissingDigestsCount {
+		for _, pendingDigest := range pendingDigests {
+			if len(queue) >= d.findMissingBlobsBatchSize {
 				// Maximum number of digests reached.
-				savedErr = d.findMissingAndRemove(ctx, queue)
+				savedErr = d.findMissingAndRemove(ctx, contentAddressableStorage, queue, progress)
 				if savedErr != nil {
 					return false
 				}
 				queue = map[digest.Digest][]func() error{}
 			}
-			queue[blobDigest] = append(queue[blobDigest], removeFunc)
+			queue[pendingDigest.digest] = append(queue[pendingDigest.digest], pendingDigest.onMissingFunc)
+			progress.queuedDigests.Store(uint64(len(queue)))
 		}
 		return true
 	}); err != nil {
@@ -239,550 +1182,4050 @@ func (d *RemoteOutputServiceDirectory) filterMissingChildren(ctx context.Context
 
 	// Process the final batch of files.
 	if len(queue) > 0 {
-		return d.findMissingAndRemove(ctx, queue)
+		return d.findMissingAndRemove(ctx, contentAddressableStorage, queue, progress)
 	}
 	return nil
 }
 
-// StartBuild is called by a build client to indicate that a new build
-// in a given output base is starting.
-func (d *RemoteOutputServiceDirectory) StartBuild(ctx context.Context, request *remoteoutputservice.StartBuildRequest) (*remoteoutputservice.StartBuildResponse, error) {
-	// Compute the full output path and the output path suffix. The
-	// former needs to be used by us, while the latter is
-	// communicated back to the client.
-	outputPath, scopeWalker := path.EmptyBuilder.Join(path.NewAbsoluteScopeWalker(path.VoidComponentWalker))
-	if err := path.Resolve(request.OutputPathPrefix, scopeWalker); err != nil {
-		return nil, util.StatusWrap(err, "Failed to resolve output path prefix")
+// getBuildBytesFetched returns the number of bytes that have been
+// fetched from the Content Addressable Storage on behalf of the output
+// path since a given build was started.
+func (ops *outputPathState) getBuildBytesFetched(buildState *buildState) uint64 {
+	return ops.fetchStatistics.getBytesFetched() - buildState.bytesFetchedAtStart
+}
+
+// getBuildFetchFailures returns the number of fetches against the
+// Content Addressable Storage on behalf of the output path that failed
+// since a given build was started.
+func (ops *outputPathState) getBuildFetchFailures(buildState *buildState) uint64 {
+	return ops.fetchStatistics.getFailures() - buildState.fetchFailuresAtStart
+}
+
+// selectOutputPathStorage returns the backends that the
+// OutputPathStorageSelector selects for an output path. If none are
+// selected, the zero value is returned.
+func (d *RemoteOutputServiceDirectory) selectOutputPathStorage(outputBaseID outputBasePath, instanceName digest.InstanceName) OutputPathStorage {
+	if d.outputPathStorageSelector != nil {
+		if storage, ok := d.outputPathStorageSelector.SelectOutputPathStorage(outputBaseID.String(), instanceName); ok {
+			return storage
+		}
 	}
-	outputPathSuffix, scopeWalker := path.EmptyBuilder.Join(path.VoidScopeWalker)
-	outputPath, scopeWalker = outputPath.Join(scopeWalker)
-	outputBaseID, ok := path.NewComponent(request.OutputBaseId)
-	if !ok {
-		return nil, status.Error(codes.InvalidArgument, "Output base ID is not a valid filename")
+	return OutputPathStorage{}
+}
+
+// createOutputPathLocked creates a new output path for a given output
+// base ID, and adds it to the list of output paths exposed by this
+// directory.
+func (d *RemoteOutputServiceDirectory) createOutputPathLocked(outputBaseID outputBasePath, digestFunction digest.Function) *outputPathState {
+	// Errors are captured, so that clients can obtain them by
+	// calling GetOutputPathErrors().
+	//
+	// TODO: We should also propagate errors back to the build
+	// client as part of the Remote Output Service protocol. This
+	// allows the client to retry, or at least display the error
+	// immediately, so that users don't need to check logs.
+	baseErrorLogger := util.DefaultErrorLogger
+	if d.outputPathErrorLoggerFactory != nil {
+		baseErrorLogger = d.outputPathErrorLoggerFactory.NewErrorLogger(outputBaseID.String())
 	}
-	componentWalker, err := scopeWalker.OnScope(false)
-	if err != nil {
-		return nil, util.StatusWrap(err, "Failed to resolve output path")
+	outputBaseIDLabel := otherOutputBaseIDLabel
+	if _, ok := d.metricsOutputBaseIDs[outputBaseID.String()]; ok {
+		outputBaseIDLabel = outputBaseID.String()
 	}
-	if _, err := componentWalker.OnTerminal(outputBaseID); err != nil {
-		return nil, util.StatusWrap(err, "Failed to resolve output path")
+	errorLogger := newOutputPathErrorLogger(baseErrorLogger, d.clock, outputBaseIDLabel)
+	fetchStatistics := newOutputPathFetchStatistics(outputBaseIDLabel, d.aggregateFetchStatistics)
+	var blobFetchSemaphore cd_blobstore.Semaphore = semaphore.NewWeighted(d.maximumConcurrentFetchesPerOutputPath)
+	blobQueuedFetches := newQueuedFetchesGauge(outputBaseIDLabel, "Blob")
+	bareContentAddressableStorage := d.bareContentAddressableStorage
+	retryingContentAddressableStorage := d.retryingContentAddressableStorage
+	directoryFetcher := d.directoryFetcher
+	storage := d.selectOutputPathStorage(outputBaseID, digestFunction.GetInstanceName())
+	if storage != (OutputPathStorage{}) {
+		bareContentAddressableStorage = storage.BareContentAddressableStorage
+		retryingContentAddressableStorage = storage.RetryingContentAddressableStorage
+		directoryFetcher = storage.DirectoryFetcher
 	}
-
-	// Create a virtual root based on the output path and provided
-	// aliases. This will be used to properly resolve targets of
-	// symbolic links stored in the output path.
-	scopeWalkerFactory, err := path.NewVirtualRootScopeWalkerFactory(outputPath.String(), request.OutputPathAliases)
-	if err != nil {
-		return nil, err
+	if d.fileFetchWeight > 0 && d.directoryFetchWeight > 0 {
+		semaphores := cd_blobstore.NewWeightedFairSemaphores(
+			d.maximumConcurrentFetchesPerOutputPath,
+			[]cd_blobstore.WeightedFairSemaphoreClass{
+				{Weight: d.fileFetchWeight, QueuedAcquisitions: blobQueuedFetches},
+				{Weight: d.directoryFetchWeight, QueuedAcquisitions: newQueuedFetchesGauge(outputBaseIDLabel, "Tree")},
+			})
+		blobFetchSemaphore, blobQueuedFetches = semaphores[0], nil
+		directoryFetcher = cd_cas.NewConcurrencyLimitingDirectoryFetcher(directoryFetcher, semaphores[1])
+	}
+	evictedCASFiles := newEvictedCASFilesCounter(outputBaseIDLabel)
+	fetchContext := newOutputPathFetchContext()
+	casFileFactory := &creationCountingCASFileFactory{
+		CASFileFactory: NewEvictionObservingCASFileFactory(
+			newReadMeasuringCASFileFactory(
+				virtual.NewStatelessHandleAllocatingCASFileFactory(
+					virtual.NewBlobAccessCASFileFactory(
+						context.Background(),
+						&fetchContextBlobAccess{
+							BlobAccess: &fetchTimingBlobAccess{
+								BlobAccess: cd_blobstore.NewConcurrencyLimitingBlobAccess(
+									retryingContentAddressableStorage,
+									blobFetchSemaphore,
+									blobQueuedFetches),
+								clock:        d.clock,
+								distribution: &fetchStatistics.blob,
+								failures:     &fetchStatistics.blobFailures,
+								bytesFetched: &fetchStatistics.blobBytesFetched,
+								inFlight:     &fetchStatistics.inFlight,
+							},
+							fetchContext: fetchContext,
+						},
+						errorLogger),
+					d.handleAllocator.New()),
+				d.clock),
+			func(blobDigest digest.Digest) {
+				evictedCASFiles.Inc()
+				d.casFiles.Add(-1)
+			}),
+		counter: &d.casFiles,
 	}
 
-	instanceName, err := digest.NewInstanceName(request.InstanceName)
-	if err != nil {
-		return nil, util.StatusWrapf(err, "Failed to parse instance name %#v", request.InstanceName)
+	d.createOutputBaseGroupsLocked(outputBaseID)
+	state := &outputPathState{
+		rootDirectory:                 d.outputPathFactory.StartInitialBuild(outputBaseID.getFlattenedName(), casFileFactory, digestFunction, errorLogger),
+		casFileFactory:                casFileFactory,
+		bareContentAddressableStorage: bareContentAddressableStorage,
+		storage:                       storage,
+		directoryFetcher: &fetchContextDirectoryFetcher{
+			base: &fetchTimingDirectoryFetcher{
+				base:         directoryFetcher,
+				clock:        d.clock,
+				distribution: &fetchStatistics.tree,
+				failures:     &fetchStatistics.treeFailures,
+				bytesFetched: &fetchStatistics.treeBytesFetched,
+				inFlight:     &fetchStatistics.inFlight,
+			},
+			fetchContext: fetchContext,
+		},
+		errorLogger:             errorLogger,
+		fetchStatistics:         fetchStatistics,
+		fetchContext:            fetchContext,
+		lastValidationTimeGauge: newLastValidationTimeGauge(outputBaseIDLabel),
+		creationTime:            d.clock.Now(),
+
+		cookie:       d.changeID,
+		outputBaseID: outputBaseID,
 	}
-	digestFunction, err := instanceName.GetDigestFunction(request.DigestFunction, 0)
-	if err != nil {
-		return nil, err
+	d.outputBaseIDs[outputBaseID] = state
+	d.changeID++
+	return state
+}
+
+// createOutputBaseGroupsLocked is called prior to adding an output
+// path to the directory hierarchy. It creates directories for all
+// prefixes of the output base ID that don't have one yet.
+func (d *RemoteOutputServiceDirectory) createOutputBaseGroupsLocked(outputBaseID outputBasePath) {
+	// Output paths take precedence over symbolic links created
+	// through CreateRootSymlink() that have the same name.
+	topLevelName := outputBaseID.getComponents()[0]
+	if symlink, ok := d.rootSymlinks[topLevelName]; ok {
+		delete(d.rootSymlinks, topLevelName)
+		symlink.leaf.Unlink()
 	}
 
-	d.lock.Lock()
-	state, ok := d.buildIDs[request.BuildId]
-	if !ok {
-		state, ok = d.outputBaseIDs[outputBaseID]
-		if ok {
-			if buildState := state.buildState; buildState != nil {
-				// A previous build is running that wasn't
-				// finalized properly. Forcefully finalize it.
-				delete(d.buildIDs, buildState.id)
-				state.buildState = nil
+	var groupID outputBasePath
+	components := outputBaseID.getComponents()
+	for _, component := range components[:len(components)-1] {
+		groupID = groupID.append(component)
+		if _, ok := d.outputBaseGroups[groupID]; !ok {
+			group := &outputBaseGroupDirectory{
+				service: d,
+				id:      groupID,
+				cookie:  d.changeID,
 			}
-		} else {
-			// No previous builds have been run for this
-			// output base. Create a new output path.
-			//
-			// TODO: This should not use DefaultErrorLogger.
-			// Instead, we should capture errors, so that we
-			// can propagate them back to the build client.
-			// This allows the client to retry, or at least
-			// display the error immediately, so that users
-			// don't need to check logs.
-			errorLogger := util.DefaultErrorLogger
-			casFileFactory := virtual.NewStatelessHandleAllocatingCASFileFactory(
-				virtual.NewBlobAccessCASFileFactory(
-					context.Background(),
-					d.retryingContentAddressableStorage,
-					errorLogger),
-				d.handleAllocator.New())
-			state = &outputPathState{
-				rootDirectory:  d.outputPathFactory.StartInitialBuild(outputBaseID, casFileFactory, digestFunction, errorLogger),
-				casFileFactory: casFileFactory,
-
-				previous:     d.outputPaths.previous,
-				next:         &d.outputPaths,
-				cookie:       d.changeID,
-				outputBaseID: outputBaseID,
-			}
-			d.outputBaseIDs[outputBaseID] = state
-			state.previous.next = state
-			state.next.previous = state
+			group.handle = d.handleAllocator.New().AsStatefulDirectory(group)
+			d.outputBaseGroups[groupID] = group
 			d.changeID++
 		}
+	}
+}
+
+// startBuildParameters contains the properties of a StartBuild()
+// request that have been validated, and are needed to start the build.
+type startBuildParameters struct {
+	buildID            string
+	outputBaseID       outputBasePath
+	outputPath         string
+	outputPathSuffix   string
+	outputPathAliases  map[string]string
+	digestFunction     digest.Function
+	scopeWalkerFactory *path.VirtualRootScopeWalkerFactory
+}
 
-		// Allow BatchCreate() and BatchStat() requests for the
-		// new build ID.
-		state.buildState = &buildState{
-			id:                 request.BuildId,
-			digestFunction:     digestFunction,
-			scopeWalkerFactory: scopeWalkerFactory,
+// validateOutputPathPrefix checks whether an output path prefix
+// provided by a client is an absolute UNIX-style path. Compared to the
+// errors returned by path.Resolve(), this provides more specific
+// messages, allowing clients to determine what is wrong with their
+// configuration.
+func validateOutputPathPrefix(outputPathPrefix string) error {
+	if outputPathPrefix == "" {
+		return status.Error(codes.InvalidArgument, "No output path prefix provided")
+	}
+	if outputPathPrefix[0] != '/' {
+		if strings.ContainsRune(outputPathPrefix, '\\') || (len(outputPathPrefix) >= 2 && outputPathPrefix[1] == ':') {
+			return status.Errorf(codes.InvalidArgument, "Output path prefix %#v appears to be a Windows path, while an absolute UNIX-style path was expected", outputPathPrefix)
 		}
-		d.buildIDs[request.BuildId] = state
+		return status.Errorf(codes.InvalidArgument, "Output path prefix %#v is not absolute", outputPathPrefix)
 	}
-	d.lock.Unlock()
-
-	// Call ContentAddressableStorage.FindMissingBlobs() on all of
-	// the files and tree objects contained within the output path,
-	// so that we have the certainty that they don't disappear
-	// during the build. Remove all of the files and directories
-	// that are missing, so that the client can detect their absence
-	// and rebuild them.
-	if err := d.filterMissingChildren(ctx, state.rootDirectory, digestFunction); err != nil {
-		return nil, util.StatusWrap(err, "Failed to filter contents of the output path")
+	for i, component := range strings.Split(outputPathPrefix[1:], "/") {
+		switch component {
+		case "", ".", "..":
+		default:
+			if _, ok := path.NewComponent(component); !ok {
+				return status.Errorf(codes.InvalidArgument, "Output path prefix %#v is invalid: Component %d %s", outputPathPrefix, i+1, getInvalidComponentReason(component))
+			}
+		}
 	}
-
-	return &remoteoutputservice.StartBuildResponse{
-		// TODO: Fill in InitialOutputPathContents, so that the
-		// client can skip parts of its analysis. The easiest
-		// way to achieve this would be to freeze the contents
-		// of the output path between builds.
-		OutputPathSuffix: outputPathSuffix.String(),
-	}, nil
+	return nil
 }
 
-// getOutputPathAndBuildState returns the state objects associated with
-// a given build ID. This function is used by all gRPC methods that can
-// only be invoked as part of a build (e.g., BatchCreate(), BatchStat()).
-func (d *RemoteOutputServiceDirectory) getOutputPathAndBuildState(buildID string) (*outputPathState, *buildState, error) {
-	d.lock.Lock()
-	defer d.lock.Unlock()
-
-	outputPathState, ok := d.buildIDs[buildID]
-	if !ok {
-		return nil, nil, status.Error(codes.FailedPrecondition, "Build ID is not associated with any running build")
+// resolveOutputPath computes the full output path and the output path
+// suffix of an output base. The former needs to be used by us, while
+// the latter is communicated back to the client.
+func (d *RemoteOutputServiceDirectory) resolveOutputPath(ctx context.Context, outputPathPrefix, outputBaseIDStr string) (outputBasePath, string, string, error) {
+	if err := validateOutputPathPrefix(outputPathPrefix); err != nil {
+		return outputBasePath{}, "", "", err
 	}
-	return outputPathState, outputPathState.buildState, nil
+	outputPath, scopeWalker := path.EmptyBuilder.Join(path.NewAbsoluteScopeWalker(path.VoidComponentWalker))
+	if err := path.Resolve(outputPathPrefix, scopeWalker); err != nil {
+		return outputBasePath{}, "", "", util.StatusWrap(err, "Failed to resolve output path prefix")
+	}
+	outputPathSuffix, scopeWalker := path.EmptyBuilder.Join(path.VoidScopeWalker)
+	outputPath, scopeWalker = outputPath.Join(scopeWalker)
+	outputBaseID, err := newOutputBasePath(outputBaseIDStr)
+	if err != nil {
+		return outputBasePath{}, "", "", err
+	}
+	if outputBaseID.getComponents()[0] == d.finalizedBuildsDirectoryName {
+		return outputBasePath{}, "", "", status.Errorf(codes.InvalidArgument, "Output base ID may not start with reserved name %#v", d.finalizedBuildsDirectoryName.String())
+	}
+	if err := d.authorizeOutputBase(ctx, outputBaseID); err != nil {
+		return outputBasePath{}, "", "", err
+	}
+	if err := path.Resolve(outputBaseID.String(), scopeWalker); err != nil {
+		return outputBasePath{}, "", "", util.StatusWrap(err, "Failed to resolve output path")
+	}
+	return outputBaseID, outputPath.String(), outputPathSuffix.String(), nil
 }
 
-// directoryCreatingComponentWalker is an implementation of
-// ComponentWalker that is used by BatchCreate() to resolve the path
-// prefix under which all provided files, symbolic links and directories
-// should be created.
-//
-// This resolver forcefully creates all intermediate pathname
-// components, removing any non-directories that are in the way.
-type directoryCreatingComponentWalker struct {
-	stack util.NonEmptyStack[virtual.PrepopulatedDirectory]
-}
+// parseStartBuildRequest validates the contents of a StartBuild()
+// request. This can be done without holding any locks.
+func (d *RemoteOutputServiceDirectory) parseStartBuildRequest(ctx context.Context, request *remoteoutputservice.StartBuildRequest) (*startBuildParameters, error) {
+	outputBaseID, outputPath, outputPathSuffix, err := d.resolveOutputPath(ctx, request.OutputPathPrefix, request.OutputBaseId)
+	if err != nil {
+		return nil, err
+	}
 
-func (cw *directoryCreatingComponentWalker) OnDirectory(name path.Component) (path.GotDirectoryOrSymlink, error) {
-	child, err := cw.stack.Peek().CreateAndEnterPrepopulatedDirectory(name)
+	// Create a virtual root based on the output path and provided
+	// aliases. This will be used to properly resolve targets of
+	// symbolic links stored in the output path.
+	if err := validateOutputPathAliases(outputPath, request.OutputPathAliases); err != nil {
+		return nil, err
+	}
+	scopeWalkerFactory, err := path.NewVirtualRootScopeWalkerFactory(outputPath, request.OutputPathAliases)
 	if err != nil {
 		return nil, err
 	}
-	cw.stack.Push(child)
-	return path.GotDirectory{
-		Child:        cw,
-		IsReversible: true,
+
+	instanceName, err := digest.NewInstanceName(request.InstanceName)
+	if err != nil {
+		return nil, util.StatusWrapf(err, "Failed to parse instance name %#v", request.InstanceName)
+	}
+	digestFunctionValue := request.DigestFunction
+	if digestFunctionValue == remoteexecution.DigestFunction_UNKNOWN {
+		digestFunctionValue = d.defaultDigestFunction
+	}
+	digestFunction, err := instanceName.GetDigestFunction(digestFunctionValue, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return &startBuildParameters{
+		buildID:            request.BuildId,
+		outputBaseID:       outputBaseID,
+		outputPath:         outputPath,
+		outputPathSuffix:   outputPathSuffix,
+		outputPathAliases:  request.OutputPathAliases,
+		digestFunction:     digestFunction,
+		scopeWalkerFactory: scopeWalkerFactory,
 	}, nil
 }
 
-func (cw *directoryCreatingComponentWalker) OnTerminal(name path.Component) (*path.GotSymlink, error) {
-	return path.OnTerminalViaOnDirectory(cw, name)
+// isAtOrBelowAbsolutePath returns true if an absolute path is equal to,
+// or is located below another absolute path. Both paths must be
+// provided without a trailing slash, meaning that the root directory is
+// represented by the empty string.
+func isAtOrBelowAbsolutePath(p, ancestor string) bool {
+	return p == ancestor || strings.HasPrefix(p, ancestor+"/")
 }
 
-func (cw *directoryCreatingComponentWalker) OnUp() (path.ComponentWalker, error) {
-	if _, ok := cw.stack.PopSingle(); !ok {
-		return nil, status.Error(codes.InvalidArgument, "Path resolves to a location outside the output path")
+// validateOutputPathAliases checks that none of the output path aliases
+// provided to StartBuild() are placed at, above or below the output
+// path or any of the other aliases. Such configurations would cause
+// the symbolic links that are used to expand aliases to be nested,
+// causing symlink expansion to loop or yield unexpected results.
+func validateOutputPathAliases(outputPath string, aliases map[string]string) error {
+	aliasPaths := make([]string, 0, len(aliases))
+	for aliasPath := range aliases {
+		aliasPaths = append(aliasPaths, aliasPath)
 	}
-	return cw, nil
+	sort.Strings(aliasPaths)
+
+	normalizedAliasPaths := make([]string, 0, len(aliasPaths))
+	for i, aliasPath := range aliasPaths {
+		normalizedAliasPath, scopeWalker := path.EmptyBuilder.Join(path.NewAbsoluteScopeWalker(path.VoidComponentWalker))
+		if err := path.Resolve(aliasPath, scopeWalker); err != nil {
+			return util.StatusWrapf(err, "Failed to resolve alias path %#v", aliasPath)
+		}
+		normalizedPath := strings.TrimSuffix(normalizedAliasPath.String(), "/")
+		if isAtOrBelowAbsolutePath(normalizedPath, outputPath) || isAtOrBelowAbsolutePath(outputPath, normalizedPath) {
+			return status.Errorf(codes.InvalidArgument, "Alias path %#v overlaps with output path %#v", aliasPath, outputPath)
+		}
+		for j, otherPath := range normalizedAliasPaths {
+			if isAtOrBelowAbsolutePath(normalizedPath, otherPath) || isAtOrBelowAbsolutePath(otherPath, normalizedPath) {
+				return status.Errorf(codes.InvalidArgument, "Alias paths %#v and %#v overlap", aliasPaths[j], aliasPaths[i])
+			}
+		}
+		normalizedAliasPaths = append(normalizedAliasPaths, normalizedPath)
+	}
+	return nil
 }
 
-func (cw *directoryCreatingComponentWalker) createChild(outputPath string, initialNode virtual.InitialNode) error {
-	outputParentCreator := parentDirectoryCreatingComponentWalker{
-		stack: cw.stack.Copy(),
+// startedBuild is returned by startBuildLocked(), containing the state
+// of the output path in which the build was started.
+type startedBuild struct {
+	state      *outputPathState
+	buildState *buildState
+	// Whether the build ID was already in use, meaning that the
+	// client reattached to a build that is already running. Such
+	// builds may not be rolled back.
+	reattached bool
+	// Whether filterMissingChildren() still needs to be called
+	// against the output path.
+	needsFiltering bool
+	// Whether a symbolic link created through CreateRootSymlink()
+	// was displaced by a newly created output path.
+	displacedSymlink bool
+}
+
+// startBuildLocked registers a build, creating a new output path if
+// none exists for the output base ID.
+//
+// If the build ID is already in use, the client is reattaching to a
+// running build (e.g., after reconnecting). The existing build is
+// returned as is, so that the response matches that of the original
+// call to StartBuild().
+func (d *RemoteOutputServiceDirectory) startBuildLocked(p *startBuildParameters) (startedBuild, error) {
+	if state, ok := d.buildIDs[p.buildID]; ok {
+		if state.outputBaseID != p.outputBaseID {
+			return startedBuild{}, status.Errorf(codes.InvalidArgument, "Build ID is already in use by output base ID %#v", state.outputBaseID.String())
+		}
+		d.touchBuildLocked(state.buildState)
+		return startedBuild{
+			state:          state,
+			buildState:     state.buildState,
+			reattached:     true,
+			needsFiltering: !state.buildState.filteredMissingChildren,
+		}, nil
 	}
-	if err := path.Resolve(outputPath, path.NewRelativeScopeWalker(&outputParentCreator)); err != nil {
-		return util.StatusWrap(err, "Failed to resolve path")
+
+	displacedSymlink := false
+	state, ok := d.outputBaseIDs[p.outputBaseID]
+	instanceName := p.digestFunction.GetInstanceName()
+	if ok && d.rejectInstanceNameChanges && state.lastDigestFunction != nil && state.lastDigestFunction.GetInstanceName() != instanceName {
+		return startedBuild{}, status.Errorf(codes.FailedPrecondition, "Output base was last used with instance name %#v, while this build uses instance name %#v", state.lastDigestFunction.GetInstanceName().String(), instanceName.String())
 	}
-	name := outputParentCreator.TerminalName
-	if name == nil {
-		return status.Errorf(codes.InvalidArgument, "Path resolves to a directory")
+	if ok && state.lastDigestFunction != nil && state.lastDigestFunction.GetInstanceName() != instanceName {
+		if d.selectOutputPathStorage(p.outputBaseID, instanceName) != state.storage {
+			return startedBuild{}, status.Errorf(codes.FailedPrecondition, "Output base was last used with instance name %#v, which uses different storage than instance name %#v used by this build. Clean the output base to use this instance name", state.lastDigestFunction.GetInstanceName().String(), instanceName.String())
+		}
+	}
+	if ok && state.buildState != nil && d.staleBuildGracePeriod > 0 {
+		if buildState := state.buildState; d.clock.Now().Before(buildState.lastActivityTime.Add(d.staleBuildGracePeriod)) {
+			return startedBuild{}, status.Errorf(codes.FailedPrecondition, "Output base ID is in use by build %#v, which was active less than %s ago", buildState.id, d.staleBuildGracePeriod)
+		}
+	}
+	if !ok || state.buildState == nil {
+		if err := d.checkConcurrentBuildsLimitLocked(); err != nil {
+			return startedBuild{}, err
+		}
+	}
+	if ok {
+		state.finalizedBuildState = nil
+		if buildState := state.buildState; buildState != nil {
+			// A previous build is running that wasn't
+			// finalized properly. Forcefully finalize it.
+			delete(d.buildIDs, buildState.id)
+			state.buildState = nil
+			d.buildEvents.append(outputpaths.BuildEvent_BUILD_FINALIZED, p.outputBaseID, buildState.id, d.clock.Now())
+		}
+	} else {
+		// No previous builds have been run for this output
+		// base. Create a new output path.
+		if err := d.checkOutputBaseIDConflictsLocked(p.outputBaseID); err != nil {
+			return startedBuild{}, err
+		}
+		_, displacedSymlink = d.rootSymlinks[p.outputBaseID.getComponents()[0]]
+		state = d.createOutputPathLocked(p.outputBaseID, p.digestFunction)
 	}
-	return outputParentCreator.stack.Peek().CreateChildren(
-		map[path.Component]virtual.InitialNode{
-			*name: initialNode,
-		},
-		true)
-}
 
-// parentDirectoryCreatingComponentWalker is an implementation of
-// ComponentWalker that is used by BatchCreate() to resolve the parent
-// directory of the path where a file, directory or symlink needs to be
-// created.
-type parentDirectoryCreatingComponentWalker struct {
-	path.TerminalNameTrackingComponentWalker
-	stack util.NonEmptyStack[virtual.PrepopulatedDirectory]
-}
+	// Allow BatchCreate() and BatchStat() requests for the new
+	// build ID.
+	newBuildState := &buildState{
+		id:                 p.buildID,
+		startTime:          d.clock.Now(),
+		outputPath:         p.outputPath,
+		outputPathSuffix:   p.outputPathSuffix,
+		outputPathAliases:  p.outputPathAliases,
+		digestFunction:     p.digestFunction,
+		scopeWalkerFactory: p.scopeWalkerFactory,
 
-func (cw *parentDirectoryCreatingComponentWalker) OnDirectory(name path.Component) (path.GotDirectoryOrSymlink, error) {
-	child, err := cw.stack.Peek().CreateAndEnterPrepopulatedDirectory(name)
-	if err != nil {
-		return nil, err
+		bytesFetchedAtStart:  state.fetchStatistics.getBytesFetched(),
+		fetchFailuresAtStart: state.fetchStatistics.getFailures(),
 	}
-	cw.stack.Push(child)
-	return path.GotDirectory{
-		Child:        cw,
-		IsReversible: true,
+	if d.staleBuildGracePeriod > 0 {
+		newBuildState.lastActivityTime = newBuildState.startTime
+	}
+	state.buildState = newBuildState
+	state.lastDigestFunction = &newBuildState.digestFunction
+	state.lastBuildStartTime = newBuildState.startTime
+	d.buildIDs[p.buildID] = state
+	d.buildEvents.append(outputpaths.BuildEvent_BUILD_STARTED, p.outputBaseID, p.buildID, newBuildState.startTime)
+	return startedBuild{
+		state:            state,
+		buildState:       newBuildState,
+		needsFiltering:   true,
+		displacedSymlink: displacedSymlink,
 	}, nil
 }
 
-func (cw *parentDirectoryCreatingComponentWalker) OnUp() (path.ComponentWalker, error) {
-	if _, ok := cw.stack.PopSingle(); !ok {
-		return nil, status.Error(codes.InvalidArgument, "Path resolves to a location outside the output path")
+// touchBuildLocked records that a request was made against a running
+// build, so that StartBuild() can determine whether the build is
+// stale.
+func (d *RemoteOutputServiceDirectory) touchBuildLocked(buildState *buildState) {
+	if d.staleBuildGracePeriod > 0 {
+		buildState.lastActivityTime = d.clock.Now()
 	}
-	return cw, nil
 }
 
-// BatchCreate can be called by a build client to create files, symbolic
-// links and directories.
-//
-// Because files and directories are provided in the form of OutputFile
-// and OutputDirectory messages, this implementation is capable of
-// creating files and directories whose contents get loaded from the
-// Content Addressable Storage lazily.
-func (d *RemoteOutputServiceDirectory) BatchCreate(ctx context.Context, request *remoteoutputservice.BatchCreateRequest) (*emptypb.Empty, error) {
-	outputPathState, buildState, err := d.getOutputPathAndBuildState(request.BuildId)
-	if err != nil {
-		return nil, err
+// checkConcurrentBuildsLimitLocked returns an error if starting another
+// build would cause the number of builds that are running at the same
+// time to exceed the configured maximum.
+func (d *RemoteOutputServiceDirectory) checkConcurrentBuildsLimitLocked() error {
+	if len(d.buildIDs) >= d.maximumConcurrentBuilds {
+		return status.Errorf(codes.ResourceExhausted, "The maximum number of %d concurrent builds has been reached", d.maximumConcurrentBuilds)
 	}
+	return nil
+}
 
-	// Resolve the path prefix. Optionally, remove all of its contents.
-	prefixCreator := directoryCreatingComponentWalker{
-		stack: util.NewNonEmptyStack[virtual.PrepopulatedDirectory](outputPathState.rootDirectory),
-	}
-	if err := path.Resolve(request.PathPrefix, path.NewRelativeScopeWalker(&prefixCreator)); err != nil {
-		return nil, util.StatusWrap(err, "Failed to create path prefix directory")
-	}
-	if request.CleanPathPrefix {
-		if err := prefixCreator.stack.Peek().RemoveAllChildren(false); err != nil {
-			return nil, util.StatusWrap(err, "Failed to clean path prefix directory")
+// boundDirectory is a directory that is placed at the root of every
+// output path, whose contents are loaded from a Tree object.
+type boundDirectory struct {
+	name       path.Component
+	treeDigest *remoteexecution.Digest
+}
+
+// removeBoundDirectories removes directories placed at the root of an
+// output path by createBoundDirectories(). This is done prior to
+// calling filterMissingChildren(), so that their contents are not
+// checked for existence.
+func (d *RemoteOutputServiceDirectory) removeBoundDirectories(outputPathState *outputPathState) error {
+	for _, bd := range d.boundDirectories {
+		if err := outputPathState.rootDirectory.RemoveAll(bd.name); err != nil && err != syscall.ENOENT {
+			return util.StatusWrapf(err, "Failed to remove bound directory %#v", bd.name.String())
 		}
 	}
+	return nil
+}
 
-	// Create requested files.
-	for _, entry := range request.Files {
-		childDigest, err := buildState.digestFunction.NewDigestFromProto(entry.Digest)
+// createBoundDirectories places directories at the root of an output
+// path whose contents are loaded from the Content Addressable Storage
+// lazily. As these are regular directories in the output path,
+// BatchStat() reports paths inside of them as being part of the output
+// path.
+func (d *RemoteOutputServiceDirectory) createBoundDirectories(outputPathState *outputPathState, buildState *buildState) error {
+	if len(d.boundDirectories) == 0 {
+		return nil
+	}
+	children := make(map[path.Component]virtual.InitialNode, len(d.boundDirectories))
+	for _, bd := range d.boundDirectories {
+		treeDigest, err := buildState.digestFunction.NewDigestFromProto(bd.treeDigest)
 		if err != nil {
-			return nil, util.StatusWrapf(err, "Invalid digest for file %#v", entry.Path)
-		}
-		leaf := outputPathState.casFileFactory.LookupFile(childDigest, entry.IsExecutable, nil)
-		if err := prefixCreator.createChild(entry.Path, virtual.InitialNode{}.FromLeaf(leaf)); err != nil {
-			leaf.Unlink()
-			return nil, util.StatusWrapf(err, "Failed to create file %#v", entry.Path)
+			return util.StatusWrapf(err, "Invalid digest for bound directory %#v", bd.name.String())
 		}
+		children[bd.name] = virtual.InitialNode{}.FromDirectory(
+			virtual.NewCASInitialContentsFetcher(
+				context.Background(),
+				cd_cas.NewTreeDirectoryWalker(outputPathState.directoryFetcher, treeDigest),
+				outputPathState.casFileFactory,
+				d.symlinkFactory,
+				buildState.digestFunction))
+	}
+	if err := outputPathState.rootDirectory.CreateChildren(children, true); err != nil {
+		return util.StatusWrap(err, "Failed to create bound directories")
 	}
+	return nil
+}
 
-	// Create requested directories.
-	for _, entry := range request.Directories {
-		childDigest, err := buildState.digestFunction.NewDigestFromProto(entry.TreeDigest)
-		if err != nil {
-			return nil, util.StatusWrapf(err, "Invalid digest for directory %#v", entry.Path)
-		}
-		if sizeBytes := childDigest.GetSizeBytes(); sizeBytes > d.maximumTreeSizeBytes {
-			return nil, status.Errorf(codes.InvalidArgument, "Directory %#v is %d bytes in size, which exceeds the permitted maximum of %d bytes", entry.Path, sizeBytes, d.maximumTreeSizeBytes)
+// checkBoundDirectories checks whether a path provided to BatchCreate()
+// refers to a location that is part of, or contains, one of the
+// directories placed at the root of every output path.
+func (d *RemoteOutputServiceDirectory) checkBoundDirectories(paths ...string) error {
+	if len(d.boundDirectories) == 0 {
+		return nil
+	}
+	p, err := normalizeOutputPath(paths...)
+	if err != nil {
+		return err
+	}
+	for _, bd := range d.boundDirectories {
+		boundPath := bd.name.String()
+		if isInOutputPathSubtree(p, boundPath) {
+			return status.Errorf(codes.FailedPrecondition, "Path %#v is part of read-only bound directory %#v", p, boundPath)
 		}
-		if err := prefixCreator.createChild(
-			entry.Path,
-			virtual.InitialNode{}.FromDirectory(
-				virtual.NewCASInitialContentsFetcher(
-					context.Background(),
-					cd_cas.NewTreeDirectoryWalker(d.directoryFetcher, childDigest),
-					outputPathState.casFileFactory,
-					d.symlinkFactory,
-					buildState.digestFunction))); err != nil {
-			return nil, util.StatusWrapf(err, "Failed to create directory %#v", entry.Path)
+		if isInOutputPathSubtree(boundPath, p) {
+			return status.Errorf(codes.FailedPrecondition, "Path %#v contains read-only bound directory %#v", p, boundPath)
 		}
 	}
+	return nil
+}
 
-	// Create requested symbolic links.
-	for _, entry := range request.Symlinks {
-		leaf := d.symlinkFactory.LookupSymlink([]byte(entry.Target))
-		if err := prefixCreator.createChild(entry.Path, virtual.InitialNode{}.FromLeaf(leaf)); err != nil {
-			leaf.Unlink()
-			return nil, util.StatusWrapf(err, "Failed to create symbolic link %#v", entry.Path)
-		}
+// filterStartedBuild calls filterMissingChildren() against the output
+// path of a build returned by startBuildLocked(), unless this was
+// already done by a previous call to StartBuild() using the same build
+// ID. Bound directories are recreated afterwards.
+func (d *RemoteOutputServiceDirectory) filterStartedBuild(ctx context.Context, build startedBuild, progress *startBuildProgress) error {
+	if !build.needsFiltering {
+		return nil
+	}
+	if err := d.removeBoundDirectories(build.state); err != nil {
+		return err
 	}
 
-	return &emptypb.Empty{}, nil
-}
+	// Permit GetActiveBuild() to report the progress made while
+	// checking the contents of the output path for existence.
+	d.lock.Lock()
+	build.buildState.filterProgress = progress
+	d.lock.Unlock()
+	defer func() {
+		d.lock.Lock()
+		if build.buildState.filterProgress == progress {
+			build.buildState.filterProgress = nil
+		}
+		d.lock.Unlock()
+	}()
 
-// statWalker is an implementation of ScopeWalker and ComponentWalker
-// that is used by BatchStat() to resolve the file or directory
-// corresponding to a requested path. It is capable of expanding
-// symbolic links, if encountered.
-type statWalker struct {
-	followSymlinks bool
-	digestFunction *digest.Function
+	filterCtx := ctx
+	if d.maximumStartBuildDuration > 0 {
+		var cancel context.CancelFunc
+		filterCtx, cancel = context.WithTimeout(ctx, d.maximumStartBuildDuration)
+		defer cancel()
+	}
+	if err := d.filterMissingChildren(filterCtx, build.state, build.buildState.id, build.buildState.digestFunction, progress); err != nil {
+		if ctx.Err() == nil && filterCtx.Err() == context.DeadlineExceeded {
+			return status.Errorf(codes.DeadlineExceeded, "Checking the contents of the output path did not complete within %s", d.maximumStartBuildDuration)
+		}
+		return err
+	}
+	if err := d.createBoundDirectories(build.state, build.buildState); err != nil {
+		return err
+	}
+	// The start time of the build is captured before calling
+	// FindMissingBlobs(), meaning that all objects referenced by the
+	// output path were known to exist at that point in time.
+	validationTime := build.buildState.startTime
+	d.lock.Lock()
+	build.buildState.filteredMissingChildren = true
+	build.buildState.findMissingCalls = progress.findMissingCalls.Load()
+	build.buildState.blobsChecked = progress.blobsChecked.Load()
+	build.state.lastValidationTime = validationTime
+	d.lock.Unlock()
+	build.state.lastValidationTimeGauge.Set(float64(validationTime.UnixNano()) / 1e9)
+	return nil
+}
 
-	stack      util.NonEmptyStack[virtual.PrepopulatedDirectory]
-	fileStatus *remoteoutputservice.FileStatus
+// StartBuild is called by a build client to indicate that a new build
+// in a given output base is starting.
+//
+// Calling StartBuild() with the ID of a build that is already running
+// is idempotent. The response is identical to that of the original
+// call, and the contents of the output path are not checked for
+// existence in the Content Addressable Storage again if this completed
+// successfully before. This permits clients to cheaply reattach to a
+// build after reconnecting.
+func (d *RemoteOutputServiceDirectory) StartBuild(ctx context.Context, request *remoteoutputservice.StartBuildRequest) (*remoteoutputservice.StartBuildResponse, error) {
+	return d.startBuild(ctx, request, &startBuildProgress{})
 }
 
-func (cw *statWalker) OnScope(absolute bool) (path.ComponentWalker, error) {
-	if absolute {
-		cw.stack.PopAll()
-	}
-	// Currently in a known directory.
-	cw.fileStatus = &remoteoutputservice.FileStatus{
-		FileType: &remoteoutputservice.FileStatus_Directory_{},
+// StartBuildStream is identical to StartBuild(), except that progress
+// of checking the contents of the output path for existence in the
+// Content Addressable Storage is streamed to the client.
+func (d *RemoteOutputServiceDirectory) StartBuildStream(request *remoteoutputservice.StartBuildRequest, server outputpaths.OutputPaths_StartBuildStreamServer) error {
+	response, err := d.startBuild(server.Context(), request, &startBuildProgress{
+		send: func(progress *outputpaths.StartBuildProgress) error {
+			return server.Send(&outputpaths.StartBuildStreamResponse{
+				Event: &outputpaths.StartBuildStreamResponse_Progress{
+					Progress: progress,
+				},
+			})
+		},
+	})
+	if err != nil {
+		return err
 	}
-	return cw, nil
+	return server.Send(&outputpaths.StartBuildStreamResponse{
+		Event: &outputpaths.StartBuildStreamResponse_Response{
+			Response: response,
+		},
+	})
 }
 
-func (cw *statWalker) OnDirectory(name path.Component) (path.GotDirectoryOrSymlink, error) {
-	child, err := cw.stack.Peek().LookupChild(name)
+// CleanAndStartBuild is identical to calling Clean() followed by
+// StartBuild(), except that the output path is not removed in the
+// process. The build is registered first, after which the contents of
+// the output path are removed.
+func (d *RemoteOutputServiceDirectory) CleanAndStartBuild(ctx context.Context, request *remoteoutputservice.StartBuildRequest) (*remoteoutputservice.StartBuildResponse, error) {
+	p, err := d.parseStartBuildRequest(ctx, request)
 	if err != nil {
 		return nil, err
 	}
 
-	directory, leaf := child.GetPair()
-	if directory != nil {
-		// Got a directory.
-		cw.stack.Push(directory)
-		return path.GotDirectory{
-			Child:        cw,
-			IsReversible: true,
-		}, nil
+	d.lock.Lock()
+	if _, ok := d.buildIDs[p.buildID]; ok {
+		d.lock.Unlock()
+		return nil, status.Error(codes.FailedPrecondition, "Build ID is already in use, and the outputs of running builds cannot be cleaned")
+	}
+	build, err := d.startBuildLocked(p)
+	d.lock.Unlock()
+	if err != nil {
+		return nil, err
 	}
 
-	target, err := leaf.Readlink()
+	if build.displacedSymlink {
+		d.handle.NotifyRemoval(p.outputBaseID.getComponents()[0])
+	}
+
+	// Remove all data stored inside the output path. This must be
+	// done without holding the directory lock, as NotifyRemoval()
+	// calls generated by the output path could deadlock otherwise.
+	if err := build.state.rootDirectory.RemoveAllChildren(true); err != nil {
+		return nil, util.StatusWrap(err, "Failed to clean output path")
+	}
+
+	var removals []directoryEntryRemoval
+	d.lock.Lock()
+	for _, name := range d.removeRootSymlinksLocked(p.outputBaseID) {
+		removals = append(removals, directoryEntryRemoval{handle: d.handle, name: name})
+	}
+	removals = append(removals, d.removeFinalizedBuildsLocked(d.finalizedBuilds[p.outputBaseID])...)
+	delete(d.finalizedBuilds, p.outputBaseID)
+	d.buildEvents.append(outputpaths.BuildEvent_OUTPUT_BASE_CLEANED, p.outputBaseID, "", d.clock.Now())
+	d.lock.Unlock()
+	for _, removal := range removals {
+		removal.notify()
+	}
+
+	// The output path is empty at this point. Filtering it is
+	// still needed to mark the build as validated, and to create
+	// bound directories.
+	if err := d.filterStartedBuild(ctx, build, &startBuildProgress{}); err != nil {
+		return nil, util.StatusWrap(err, "Failed to filter contents of the output path")
+	}
+	return &remoteoutputservice.StartBuildResponse{
+		OutputPathSuffix: build.buildState.outputPathSuffix,
+	}, nil
+}
+
+func (d *RemoteOutputServiceDirectory) startBuild(ctx context.Context, request *remoteoutputservice.StartBuildRequest, progress *startBuildProgress) (*remoteoutputservice.StartBuildResponse, error) {
+	p, err := d.parseStartBuildRequest(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	d.lock.Lock()
+	build, err := d.startBuildLocked(p)
+	d.lock.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	if build.displacedSymlink {
+		d.handle.NotifyRemoval(p.outputBaseID.getComponents()[0])
+	}
+
+	// Call ContentAddressableStorage.FindMissingBlobs() on all of
+	// the files and tree objects contained within the output path,
+	// so that we have the certainty that they don't disappear
+	// during the build. Remove all of the files and directories
+	// that are missing, so that the client can detect their absence
+	// and rebuild them.
+	if err := d.filterStartedBuild(ctx, build, progress); err != nil {
+		return nil, util.StatusWrap(err, "Failed to filter contents of the output path")
+	}
+
+	return &remoteoutputservice.StartBuildResponse{
+		// TODO: Fill in InitialOutputPathContents, so that the
+		// client can skip parts of its analysis. The easiest
+		// way to achieve this would be to freeze the contents
+		// of the output path between builds.
+		OutputPathSuffix: build.buildState.outputPathSuffix,
+	}, nil
+}
+
+// rollbackBuildsLocked undoes the registration of builds that were
+// started by BatchStartBuild(), so that no BatchCreate() and
+// BatchStat() requests may be issued against them. Output paths that
+// were created in the process are retained, as they may be reused by
+// successive builds. Builds that were reattached to, or that have
+// already been displaced by successive calls to StartBuild(), are left
+// alone.
+func (d *RemoteOutputServiceDirectory) rollbackBuildsLocked(builds []startedBuild) {
+	for _, build := range builds {
+		if !build.reattached && build.state.buildState == build.buildState {
+			delete(d.buildIDs, build.buildState.id)
+			build.state.buildState = nil
+			d.buildEvents.append(outputpaths.BuildEvent_BUILD_ABORTED, build.state.outputBaseID, build.buildState.id, d.clock.Now())
+		}
+	}
+}
+
+// BatchStartBuild starts builds for multiple output bases atomically.
+// If any of the builds fails to start, the builds that were started as
+// part of this request are finalized.
+func (d *RemoteOutputServiceDirectory) BatchStartBuild(ctx context.Context, request *outputpaths.BatchStartBuildRequest) (*outputpaths.BatchStartBuildResponse, error) {
+	parameters := make([]*startBuildParameters, 0, len(request.Requests))
+	buildIDs := map[string]struct{}{}
+	outputBaseIDs := map[outputBasePath]struct{}{}
+	for i, startBuildRequest := range request.Requests {
+		p, err := d.parseStartBuildRequest(ctx, startBuildRequest)
+		if err != nil {
+			return nil, util.StatusWrapf(err, "Request at index %d", i)
+		}
+		if _, ok := buildIDs[p.buildID]; ok {
+			return nil, status.Errorf(codes.InvalidArgument, "Request at index %d: Build ID %#v is used by multiple requests", i, p.buildID)
+		}
+		buildIDs[p.buildID] = struct{}{}
+		if _, ok := outputBaseIDs[p.outputBaseID]; ok {
+			return nil, status.Errorf(codes.InvalidArgument, "Request at index %d: Output base ID %#v is used by multiple requests", i, p.outputBaseID.String())
+		}
+		outputBaseIDs[p.outputBaseID] = struct{}{}
+		parameters = append(parameters, p)
+	}
+
+	d.lock.Lock()
+	builds := make([]startedBuild, 0, len(parameters))
+	for i, p := range parameters {
+		build, err := d.startBuildLocked(p)
+		if err != nil {
+			d.rollbackBuildsLocked(builds)
+			d.lock.Unlock()
+			return nil, util.StatusWrapf(err, "Request at index %d", i)
+		}
+		builds = append(builds, build)
+	}
+	d.lock.Unlock()
+
+	for i, build := range builds {
+		if build.displacedSymlink {
+			d.handle.NotifyRemoval(parameters[i].outputBaseID.getComponents()[0])
+		}
+	}
+
+	// Filter the contents of all output paths, as done by
+	// StartBuild(). Any failure causes all builds to be rolled
+	// back.
+	response := &outputpaths.BatchStartBuildResponse{
+		Responses: make([]*remoteoutputservice.StartBuildResponse, 0, len(builds)),
+	}
+	for i, build := range builds {
+		if err := d.filterStartedBuild(ctx, build, &startBuildProgress{}); err != nil {
+			d.lock.Lock()
+			d.rollbackBuildsLocked(builds)
+			d.lock.Unlock()
+			return nil, util.StatusWrapf(err, "Request at index %d: Failed to filter contents of the output path", i)
+		}
+		response.Responses = append(response.Responses, &remoteoutputservice.StartBuildResponse{
+			OutputPathSuffix: build.buildState.outputPathSuffix,
+		})
+	}
+	return response, nil
+}
+
+// getOutputPathAndBuildState returns the state objects associated with
+// a given build ID. This function is used by all gRPC methods that can
+// only be invoked as part of a build (e.g., BatchCreate(), BatchStat()).
+func (d *RemoteOutputServiceDirectory) getOutputPathAndBuildState(buildID string) (*outputPathState, *buildState, error) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	outputPathState, ok := d.buildIDs[buildID]
+	if !ok {
+		return nil, nil, status.Error(codes.FailedPrecondition, "Build ID is not associated with any running build")
+	}
+	d.touchBuildLocked(outputPathState.buildState)
+	return outputPathState, outputPathState.buildState, nil
+}
+
+// pathDepthLimit is embedded into implementations of ComponentWalker
+// that keep track of the directories they traverse, so that the number
+// of directories a single path may traverse is bounded.
+type pathDepthLimit struct {
+	depth        int
+	maximumDepth int
+}
+
+func newPathDepthLimit(maximumDepth int) pathDepthLimit {
+	return pathDepthLimit{maximumDepth: maximumDepth}
+}
+
+// enter is called when traversing into a directory.
+func (l *pathDepthLimit) enter() error {
+	if l.depth >= l.maximumDepth {
+		return status.Errorf(codes.InvalidArgument, "Path exceeds the maximum depth of %d directories", l.maximumDepth)
+	}
+	l.depth++
+	return nil
+}
+
+// leave is called when traversing to a parent directory.
+func (l *pathDepthLimit) leave() {
+	l.depth--
+}
+
+// pathValidatingComponentWalker is an implementation of ComponentWalker
+// that is used by BatchCreate() to validate paths before any
+// directories are created. As BatchCreate() never follows symbolic
+// links, tracking the depth relative to the root of the output path is
+// sufficient to determine whether a path stays within the output path.
+type pathValidatingComponentWalker struct {
+	path.TerminalNameTrackingComponentWalker
+	depth int
+}
+
+func (cw *pathValidatingComponentWalker) OnDirectory(name path.Component) (path.GotDirectoryOrSymlink, error) {
+	cw.depth++
+	return path.GotDirectory{
+		Child:        cw,
+		IsReversible: true,
+	}, nil
+}
+
+func (cw *pathValidatingComponentWalker) OnUp() (path.ComponentWalker, error) {
+	if cw.depth == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Path resolves to a location outside the output path")
+	}
+	cw.depth--
+	return cw, nil
+}
+
+// validateBatchCreatePath checks whether a path provided to
+// BatchCreate() is relative and does not resolve to a location outside
+// the output path. The depth at which resolution starts is provided,
+// and the depth of the resulting location is returned.
+func validateBatchCreatePath(p string, depth int) (int, error) {
+	validator := pathValidatingComponentWalker{
+		depth: depth,
+	}
+	if err := path.Resolve(p, path.NewRelativeScopeWalker(&validator)); err != nil {
+		return 0, err
+	}
+	if validator.TerminalName != nil {
+		validator.depth++
+	}
+	return validator.depth, nil
+}
+
+// pathNormalizingComponentWalker is an implementation of
+// ComponentWalker that converts paths provided to BatchCreate() and
+// FinalizeSubtree() to a normalized form, so that they can be compared
+// against subtrees that have been finalized. Like BatchCreate(), it
+// does not follow symbolic links.
+type pathNormalizingComponentWalker struct {
+	components []string
+}
+
+func (cw *pathNormalizingComponentWalker) OnDirectory(name path.Component) (path.GotDirectoryOrSymlink, error) {
+	cw.components = append(cw.components, name.String())
+	return path.GotDirectory{
+		Child:        cw,
+		IsReversible: true,
+	}, nil
+}
+
+func (cw *pathNormalizingComponentWalker) OnTerminal(name path.Component) (*path.GotSymlink, error) {
+	cw.components = append(cw.components, name.String())
+	return nil, nil
+}
+
+func (cw *pathNormalizingComponentWalker) OnUp() (path.ComponentWalker, error) {
+	if len(cw.components) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Path resolves to a location outside the output path")
+	}
+	cw.components = cw.components[:len(cw.components)-1]
+	return cw, nil
+}
+
+// normalizeOutputPath converts one or more paths that are relative to
+// each other to a single path relative to the root of the output path.
+// The root of the output path itself is returned as the empty string.
+func normalizeOutputPath(paths ...string) (string, error) {
+	var normalizer pathNormalizingComponentWalker
+	for _, p := range paths {
+		if err := path.Resolve(p, path.NewRelativeScopeWalker(&normalizer)); err != nil {
+			return "", err
+		}
+	}
+	return strings.Join(normalizer.components, "/"), nil
+}
+
+// isInOutputPathSubtree returns true if a normalized path is equal to,
+// or is located below another normalized path.
+func isInOutputPathSubtree(p, subtree string) bool {
+	return subtree == "" || p == subtree || strings.HasPrefix(p, subtree+"/")
+}
+
+// applyDefaultPathPrefix prepends the default path prefix of a build to
+// a path provided to BatchCreate() or BatchStat(). Paths that are
+// already equal to or located below the default path prefix are
+// returned as is, as are paths that cannot be normalized. The latter
+// ensures that errors are reported against the original path.
+func (d *RemoteOutputServiceDirectory) applyDefaultPathPrefix(buildState *buildState, p string) string {
+	d.lock.Lock()
+	defaultPathPrefix := buildState.defaultPathPrefix
+	d.lock.Unlock()
+
+	if defaultPathPrefix == "" {
+		return p
+	}
+	normalizedPath, err := normalizeOutputPath(p)
+	if err != nil || isInOutputPathSubtree(normalizedPath, defaultPathPrefix) {
+		return p
+	}
+	if p == "" {
+		return defaultPathPrefix
+	}
+	return defaultPathPrefix + "/" + p
+}
+
+// checkFinalizedSubtrees returns an error if modifying the contents of
+// the output path at a given location would alter the contents of a
+// subtree that was finalized through FinalizeSubtree().
+func (d *RemoteOutputServiceDirectory) checkFinalizedSubtrees(buildState *buildState, paths ...string) error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	if len(buildState.finalizedSubtrees) == 0 {
+		return nil
+	}
+	p, err := normalizeOutputPath(paths...)
+	if err != nil {
+		return err
+	}
+	for subtree := range buildState.finalizedSubtrees {
+		if isInOutputPathSubtree(p, subtree) {
+			return status.Errorf(codes.FailedPrecondition, "Path %#v is part of finalized subtree %#v", p, subtree)
+		}
+		if isInOutputPathSubtree(subtree, p) {
+			return status.Errorf(codes.FailedPrecondition, "Path %#v contains finalized subtree %#v", p, subtree)
+		}
+	}
+	return nil
+}
+
+// validateBatchCreateEntryPath checks whether the path of an entry
+// provided to BatchCreate() stays within the output path, and does not
+// refer to a location that is part of a finalized subtree.
+func (d *RemoteOutputServiceDirectory) validateBatchCreateEntryPath(buildState *buildState, pathPrefix string, prefixDepth int, entryPath string) error {
+	if _, err := validateBatchCreatePath(entryPath, prefixDepth); err != nil {
+		return err
+	}
+	if err := d.checkBoundDirectories(pathPrefix, entryPath); err != nil {
+		return err
+	}
+	return d.checkFinalizedSubtrees(buildState, pathPrefix, entryPath)
+}
+
+// directoryCreatingComponentWalker is an implementation of
+// ComponentWalker that is used by BatchCreate() to resolve the path
+// prefix under which all provided files, symbolic links and directories
+// should be created.
+//
+// This resolver forcefully creates all intermediate pathname
+// components, removing any non-directories that are in the way.
+type directoryCreatingComponentWalker struct {
+	stack        util.NonEmptyStack[virtual.PrepopulatedDirectory]
+	depthLimit   pathDepthLimit
+	errorMapping SyscallErrorMapping
+}
+
+func (cw *directoryCreatingComponentWalker) OnDirectory(name path.Component) (path.GotDirectoryOrSymlink, error) {
+	if err := cw.depthLimit.enter(); err != nil {
+		return nil, err
+	}
+	child, err := cw.stack.Peek().CreateAndEnterPrepopulatedDirectory(name)
+	if err != nil {
+		return nil, err
+	}
+	cw.stack.Push(child)
+	return path.GotDirectory{
+		Child:        cw,
+		IsReversible: true,
+	}, nil
+}
+
+func (cw *directoryCreatingComponentWalker) OnTerminal(name path.Component) (*path.GotSymlink, error) {
+	return path.OnTerminalViaOnDirectory(cw, name)
+}
+
+func (cw *directoryCreatingComponentWalker) OnUp() (path.ComponentWalker, error) {
+	if _, ok := cw.stack.PopSingle(); !ok {
+		return nil, status.Error(codes.InvalidArgument, "Path resolves to a location outside the output path")
+	}
+	cw.depthLimit.leave()
+	return cw, nil
+}
+
+func (cw *directoryCreatingComponentWalker) createChild(outputPath string, initialNode virtual.InitialNode) error {
+	outputParentCreator := parentDirectoryCreatingComponentWalker{
+		stack:      cw.stack.Copy(),
+		depthLimit: cw.depthLimit,
+	}
+	if err := path.Resolve(outputPath, path.NewRelativeScopeWalker(&outputParentCreator)); err != nil {
+		return util.StatusWrap(cw.errorMapping.convert(err), "Failed to resolve path")
+	}
+	name := outputParentCreator.TerminalName
+	if name == nil {
+		return status.Errorf(codes.InvalidArgument, "Path resolves to a directory")
+	}
+	return cw.errorMapping.convert(outputParentCreator.stack.Peek().CreateChildren(
+		map[path.Component]virtual.InitialNode{
+			*name: initialNode,
+		},
+		true))
+}
+
+// parentDirectoryCreatingComponentWalker is an implementation of
+// ComponentWalker that is used by BatchCreate() to resolve the parent
+// directory of the path where a file, directory or symlink needs to be
+// created.
+type parentDirectoryCreatingComponentWalker struct {
+	path.TerminalNameTrackingComponentWalker
+	stack      util.NonEmptyStack[virtual.PrepopulatedDirectory]
+	depthLimit pathDepthLimit
+}
+
+func (cw *parentDirectoryCreatingComponentWalker) OnDirectory(name path.Component) (path.GotDirectoryOrSymlink, error) {
+	if err := cw.depthLimit.enter(); err != nil {
+		return nil, err
+	}
+	child, err := cw.stack.Peek().CreateAndEnterPrepopulatedDirectory(name)
+	if err != nil {
+		return nil, err
+	}
+	cw.stack.Push(child)
+	return path.GotDirectory{
+		Child:        cw,
+		IsReversible: true,
+	}, nil
+}
+
+func (cw *parentDirectoryCreatingComponentWalker) OnUp() (path.ComponentWalker, error) {
+	if _, ok := cw.stack.PopSingle(); !ok {
+		return nil, status.Error(codes.InvalidArgument, "Path resolves to a location outside the output path")
+	}
+	cw.depthLimit.leave()
+	return cw, nil
+}
+
+// prepareBatchCreate performs the validation and setup that is shared
+// by BatchCreate() and BestEffortBatchCreate(). It resolves the path
+// prefix directory, optionally removing its contents, and returns the
+// path prefix and the number of levels it is located below the root of
+// the output path. The latter is needed to validate entry paths.
+//
+// If a default path prefix has been set through
+// SetDefaultPathPrefix(), the path prefix that is returned includes it.
+// The request itself is left untouched, as it may be logged or
+// replayed afterwards.
+func (d *RemoteOutputServiceDirectory) prepareBatchCreate(ctx context.Context, request *remoteoutputservice.BatchCreateRequest) (*outputPathState, *buildState, *directoryCreatingComponentWalker, string, int, error) {
+	outputPathState, buildState, err := d.getOutputPathAndBuildState(request.BuildId)
+	if err != nil {
+		return nil, nil, nil, "", 0, err
+	}
+	if err := d.authorizeOutputBase(ctx, outputPathState.outputBaseID); err != nil {
+		return nil, nil, nil, "", 0, err
+	}
+	if err := d.checkBuildNotSealed(buildState); err != nil {
+		return nil, nil, nil, "", 0, err
+	}
+	pathPrefix := d.applyDefaultPathPrefix(buildState, request.PathPrefix)
+	prefixDepth, err := validateBatchCreatePath(pathPrefix, 0)
+	if err != nil {
+		return nil, nil, nil, "", 0, util.StatusWrap(err, "Failed to create path prefix directory")
+	}
+	if request.CleanPathPrefix {
+		if err := d.checkBoundDirectories(pathPrefix); err != nil {
+			return nil, nil, nil, "", 0, util.StatusWrap(err, "Failed to clean path prefix directory")
+		}
+		if err := d.checkFinalizedSubtrees(buildState, pathPrefix); err != nil {
+			return nil, nil, nil, "", 0, util.StatusWrap(err, "Failed to clean path prefix directory")
+		}
+	}
+	return outputPathState, buildState, &directoryCreatingComponentWalker{
+		stack:        util.NewNonEmptyStack[virtual.PrepopulatedDirectory](outputPathState.rootDirectory),
+		depthLimit:   newPathDepthLimit(d.maximumPathDepth),
+		errorMapping: d.syscallErrorMapping,
+	}, pathPrefix, prefixDepth, nil
+}
+
+// createBatchCreatePathPrefix resolves the path prefix provided to
+// BatchCreate(). Optionally, it removes all of its contents. If
+// reportRemovedPaths is set, the names of the children that were
+// removed are returned in sorted order.
+func createBatchCreatePathPrefix(prefixCreator *directoryCreatingComponentWalker, pathPrefix string, cleanPathPrefix, reportRemovedPaths bool) ([]string, error) {
+	if err := path.Resolve(pathPrefix, path.NewRelativeScopeWalker(prefixCreator)); err != nil {
+		return nil, util.StatusWrap(prefixCreator.errorMapping.convert(err), "Failed to create path prefix directory")
+	}
+	var removedPaths []string
+	if cleanPathPrefix {
+		prefixDirectory := prefixCreator.stack.Peek()
+		if reportRemovedPaths {
+			directories, leaves, err := prefixDirectory.LookupAllChildren()
+			if err != nil {
+				return nil, util.StatusWrap(err, "Failed to look up children of path prefix directory")
+			}
+			removedPaths = make([]string, 0, len(directories)+len(leaves))
+			for _, entry := range directories {
+				removedPaths = append(removedPaths, entry.Name.String())
+			}
+			for _, entry := range leaves {
+				removedPaths = append(removedPaths, entry.Name.String())
+			}
+			sort.Strings(removedPaths)
+		}
+		if err := prefixDirectory.RemoveAllChildren(false); err != nil {
+			return nil, util.StatusWrap(err, "Failed to clean path prefix directory")
+		}
+	}
+	return removedPaths, nil
+}
+
+// isNoopBatchCreate returns true if a request provided to BatchCreate()
+// contains no entries, and does not request that the path prefix is
+// cleaned. Such requests have no effect, apart from validating the
+// build ID and path prefix. The path prefix directory is not created
+// for these, so that they remain cheap.
+func isNoopBatchCreate(request *remoteoutputservice.BatchCreateRequest) bool {
+	return len(request.Files) == 0 && len(request.Directories) == 0 && len(request.Symlinks) == 0 && !request.CleanPathPrefix
+}
+
+// newBatchCreateDigest converts a digest provided to BatchCreate() to
+// a Digest object, using the digest function of the build. If the
+// length of the hash doesn't match the digest function, the digest
+// function that the client likely used is reported. Such mismatches
+// are typically caused by bugs in the client, where digests are
+// computed using a different digest function than the one provided to
+// StartBuild().
+func newBatchCreateDigest(digestFunction digest.Function, blobDigest *remoteexecution.Digest) (digest.Digest, error) {
+	newDigest, err := digestFunction.NewDigestFromProto(blobDigest)
+	if err != nil && blobDigest != nil {
+		// The hash of the empty blob has the length that is
+		// expected for this digest function.
+		hashLength := len(blobDigest.Hash)
+		if expectedHashLength := len(digestFunction.NewGenerator(0).Sum().GetHashString()); hashLength != expectedHashLength {
+			if providedDigestFunction, errInfer := digestFunction.GetInstanceName().GetDigestFunction(remoteexecution.DigestFunction_UNKNOWN, hashLength); errInfer == nil {
+				return digest.BadDigest, status.Errorf(
+					codes.InvalidArgument,
+					"Hash has length %d, which corresponds to digest function %s, while the build uses digest function %s",
+					hashLength,
+					providedDigestFunction.GetEnumValue(),
+					digestFunction.GetEnumValue())
+			}
+		}
+	}
+	return newDigest, err
+}
+
+// createFile creates a single file requested through BatchCreate().
+func (d *RemoteOutputServiceDirectory) createFile(outputPathState *outputPathState, buildState *buildState, prefixCreator *directoryCreatingComponentWalker, entry *remoteexecution.OutputFile) error {
+	childDigest, err := newBatchCreateDigest(buildState.digestFunction, entry.Digest)
+	if err != nil {
+		return util.StatusWrapf(err, "Invalid digest for file %#v", entry.Path)
+	}
+	modificationTime, err := getModificationTimeFromNodeProperties(entry.NodeProperties)
+	if err != nil {
+		return util.StatusWrapf(err, "Invalid node properties for file %#v", entry.Path)
+	}
+	leaf := outputPathState.casFileFactory.LookupFile(childDigest, entry.IsExecutable, nil)
+	if modificationTime != nil {
+		leaf = newModificationTimeOverridingLeaf(leaf, *modificationTime)
+	}
+	if err := prefixCreator.createChild(entry.Path, virtual.InitialNode{}.FromLeaf(leaf)); err != nil {
+		leaf.Unlink()
+		return util.StatusWrapf(err, "Failed to create file %#v", entry.Path)
+	}
+	return nil
+}
+
+// createDirectory creates a single directory requested through
+// BatchCreate(), whose contents are loaded from the Content Addressable
+// Storage lazily.
+func (d *RemoteOutputServiceDirectory) createDirectory(outputPathState *outputPathState, buildState *buildState, prefixCreator *directoryCreatingComponentWalker, entry *remoteexecution.OutputDirectory) error {
+	childDigest, err := newBatchCreateDigest(buildState.digestFunction, entry.TreeDigest)
+	if err != nil {
+		return util.StatusWrapf(err, "Invalid digest for directory %#v", entry.Path)
+	}
+	if sizeBytes := childDigest.GetSizeBytes(); sizeBytes > d.maximumTreeSizeBytes {
+		return status.Errorf(codes.InvalidArgument, "Directory %#v is %d bytes in size, which exceeds the permitted maximum of %d bytes", entry.Path, sizeBytes, d.maximumTreeSizeBytes)
+	}
+	if err := prefixCreator.createChild(
+		entry.Path,
+		virtual.InitialNode{}.FromDirectory(
+			virtual.NewCASInitialContentsFetcher(
+				context.Background(),
+				cd_cas.NewTreeDirectoryWalker(outputPathState.directoryFetcher, childDigest),
+				outputPathState.casFileFactory,
+				d.symlinkFactory,
+				buildState.digestFunction))); err != nil {
+		return util.StatusWrapf(err, "Failed to create directory %#v", entry.Path)
+	}
+	return nil
+}
+
+// symlinkTargetScopeWalker is an implementation of ScopeWalker that is
+// used by rewriteSymlinkTarget() to determine whether the target of a
+// symbolic link refers to a location inside the output path. It is
+// wrapped by the build's VirtualRootScopeWalkerFactory, meaning that
+// OnScope() is only called if the target resolves to a location at or
+// below the root of the output path.
+type symlinkTargetScopeWalker struct {
+	normalizer       pathNormalizingComponentWalker
+	insideOutputPath bool
+}
+
+func (sw *symlinkTargetScopeWalker) OnScope(absolute bool) (path.ComponentWalker, error) {
+	sw.normalizer.components = sw.normalizer.components[:0]
+	sw.insideOutputPath = absolute
+	return &sw.normalizer, nil
+}
+
+// rewriteSymlinkTarget converts the absolute target of a symbolic link
+// created through BatchCreate() to a path that is relative to the
+// directory containing the symbolic link, if the target refers to a
+// location inside the output path. Targets that are relative, refer to
+// a location outside the output path, or cannot be resolved are
+// returned as is.
+func rewriteSymlinkTarget(buildState *buildState, symlinkPath []string, target string) string {
+	if !strings.HasPrefix(target, "/") {
+		return target
+	}
+	var targetWalker symlinkTargetScopeWalker
+	if err := path.Resolve(target, buildState.scopeWalkerFactory.New(&targetWalker)); err != nil || !targetWalker.insideOutputPath {
+		return target
+	}
+
+	// Strip the components that the directory containing the
+	// symbolic link and the target have in common, and traverse
+	// upward for the remaining components of the former.
+	directory := symlinkPath[:len(symlinkPath)-1]
+	targetComponents := targetWalker.normalizer.components
+	common := 0
+	for common < len(directory) && common < len(targetComponents) && directory[common] == targetComponents[common] {
+		common++
+	}
+	relativeComponents := make([]string, 0, len(directory)-common+len(targetComponents)-common)
+	for range directory[common:] {
+		relativeComponents = append(relativeComponents, "..")
+	}
+	relativeComponents = append(relativeComponents, targetComponents[common:]...)
+	if len(relativeComponents) == 0 {
+		return "."
+	}
+	return strings.Join(relativeComponents, "/")
+}
+
+// validateSymlinkTarget checks whether the target of a symbolic link
+// provided to BatchCreate() is well formed. Operating systems don't
+// permit empty targets or targets containing null bytes, while overly
+// long targets cannot be resolved.
+func (d *RemoteOutputServiceDirectory) validateSymlinkTarget(entry *remoteexecution.OutputSymlink) error {
+	target := entry.Target
+	if target == "" {
+		return status.Errorf(codes.InvalidArgument, "Symbolic link %#v has an empty target", entry.Path)
+	}
+	if strings.IndexByte(target, 0) >= 0 {
+		return status.Errorf(codes.InvalidArgument, "Target of symbolic link %#v contains a null byte", entry.Path)
+	}
+	if len(target) > d.maximumSymlinkTargetLength {
+		return status.Errorf(codes.InvalidArgument, "Target of symbolic link %#v is %d bytes in size, which exceeds the permitted maximum of %d bytes", entry.Path, len(target), d.maximumSymlinkTargetLength)
+	}
+	return nil
+}
+
+// createSymlink creates a single symbolic link requested through
+// BatchCreate().
+func (d *RemoteOutputServiceDirectory) createSymlink(buildState *buildState, pathPrefix string, prefixCreator *directoryCreatingComponentWalker, entry *remoteexecution.OutputSymlink) error {
+	if err := d.validateSymlinkTarget(entry); err != nil {
+		return err
+	}
+	target := entry.Target
+	if d.rewriteAbsoluteSymlinkTargets {
+		if symlinkPath, err := normalizeOutputPath(pathPrefix, entry.Path); err == nil && symlinkPath != "" {
+			target = rewriteSymlinkTarget(buildState, strings.Split(symlinkPath, "/"), target)
+		}
+	}
+	leaf := d.symlinkFactory.LookupSymlink([]byte(target))
+	if err := prefixCreator.createChild(entry.Path, virtual.InitialNode{}.FromLeaf(leaf)); err != nil {
+		leaf.Unlink()
+		return util.StatusWrapf(err, "Failed to create symbolic link %#v", entry.Path)
+	}
+	return nil
+}
+
+// BatchCreate can be called by a build client to create files, symbolic
+// links and directories.
+//
+// Because files and directories are provided in the form of OutputFile
+// and OutputDirectory messages, this implementation is capable of
+// creating files and directories whose contents get loaded from the
+// Content Addressable Storage lazily.
+//
+// If OutputFile.node_properties.mtime is set, the file is exposed with
+// the provided modification time. This can't be reported through
+// BatchStat(), as the Remote Output Service protocol provides no field
+// for it.
+//
+// Requests that contain no entries only create the path prefix
+// directory if clean_path_prefix is set, in which case its contents are
+// removed. Requests that contain no entries and don't set
+// clean_path_prefix merely validate the build ID and path prefix.
+func (d *RemoteOutputServiceDirectory) BatchCreate(ctx context.Context, request *remoteoutputservice.BatchCreateRequest) (*emptypb.Empty, error) {
+	if _, err := d.batchCreate(ctx, request, false, ""); err != nil {
+		d.logBatchCreateFailure(request, err)
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// logBatchCreateFailure logs a failed call to BatchCreate(), so that
+// the request can be reproduced. By default, only the number of
+// entries is logged.
+func (d *RemoteOutputServiceDirectory) logBatchCreateFailure(request *remoteoutputservice.BatchCreateRequest, err error) {
+	if d.batchCreateFailureLogger == nil {
+		return
+	}
+	var paths string
+	if d.logBatchCreateFailurePaths {
+		filePaths := make([]string, 0, len(request.Files))
+		for _, entry := range request.Files {
+			filePaths = append(filePaths, entry.Path)
+		}
+		directoryPaths := make([]string, 0, len(request.Directories))
+		for _, entry := range request.Directories {
+			directoryPaths = append(directoryPaths, entry.Path)
+		}
+		symlinkPaths := make([]string, 0, len(request.Symlinks))
+		for _, entry := range request.Symlinks {
+			symlinkPaths = append(symlinkPaths, entry.Path)
+		}
+		paths = fmt.Sprintf(" (files %q, directories %q, symbolic links %q)", filePaths, directoryPaths, symlinkPaths)
+	}
+	d.batchCreateFailureLogger.Log(util.StatusWrapf(
+		err,
+		"BatchCreate() for build %#v with path prefix %#v, %d files, %d directories and %d symbolic links%s failed",
+		request.BuildId,
+		request.PathPrefix,
+		len(request.Files),
+		len(request.Directories),
+		len(request.Symlinks),
+		paths))
+}
+
+// ExtendedBatchCreate is identical to BatchCreate(), except that it
+// can optionally check for the existence of the Tree objects of
+// directories prior to creating any entries, and confine the entries
+// to an allowed scope.
+func (d *RemoteOutputServiceDirectory) ExtendedBatchCreate(ctx context.Context, request *outputpaths.ExtendedBatchCreateRequest) (*outputpaths.ExtendedBatchCreateResponse, error) {
+	batchCreateRequest := request.Request
+	if batchCreateRequest == nil {
+		return nil, status.Error(codes.InvalidArgument, "No BatchCreate() request provided")
+	}
+	directoryStatuses, err := d.batchCreate(ctx, batchCreateRequest, request.ValidateTreeDigests, request.AllowedScope)
+	if err != nil {
+		d.logBatchCreateFailure(batchCreateRequest, err)
+		return nil, err
+	}
+	return &outputpaths.ExtendedBatchCreateResponse{
+		Directories: directoryStatuses,
+	}, nil
+}
+
+// findMissingTreeDigests is called by ExtendedBatchCreate() to check
+// whether the Tree objects of directories are present in the Content
+// Addressable Storage. If one or more of them are absent, the status
+// of every directory is returned.
+func (d *RemoteOutputServiceDirectory) findMissingTreeDigests(ctx context.Context, outputPathState *outputPathState, buildState *buildState, directories []*remoteexecution.OutputDirectory) ([]*status_pb.Status, error) {
+	directoryErrors := make([]error, len(directories))
+	foundMissing := false
+	queue := map[digest.Digest][]func() error{}
+	var progress startBuildProgress
+	for i, entry := range directories {
+		treeDigest, err := newBatchCreateDigest(buildState.digestFunction, entry.TreeDigest)
+		if err != nil {
+			return nil, util.StatusWrapf(err, "Invalid digest for directory %#v", entry.Path)
+		}
+		if len(queue) >= d.findMissingBlobsBatchSize {
+			// Maximum number of digests reached.
+			if err := d.findMissingAndRemove(ctx, outputPathState.bareContentAddressableStorage, queue, &progress); err != nil {
+				return nil, err
+			}
+			queue = map[digest.Digest][]func() error{}
+		}
+		i, entryPath := i, entry.Path
+		queue[treeDigest] = append(queue[treeDigest], func() error {
+			directoryErrors[i] = status.Errorf(codes.NotFound, "Tree for directory %#v with digest %#v is not present in the Content Addressable Storage", entryPath, treeDigest.String())
+			foundMissing = true
+			return nil
+		})
+	}
+	if len(queue) > 0 {
+		if err := d.findMissingAndRemove(ctx, outputPathState.bareContentAddressableStorage, queue, &progress); err != nil {
+			return nil, err
+		}
+	}
+	if !foundMissing {
+		return nil, nil
+	}
+
+	directoryStatuses := make([]*status_pb.Status, 0, len(directories))
+	for _, err := range directoryErrors {
+		directoryStatuses = append(directoryStatuses, status.Convert(err).Proto())
+	}
+	return directoryStatuses, nil
+}
+
+// checkBatchCreateScope checks whether all entries provided to
+// ExtendedBatchCreate() are located at or below an allowed scope. This
+// function assumes that the paths of all entries have already been
+// validated. The path prefix of the request is provided separately, as
+// it may include the default path prefix of the build.
+func checkBatchCreateScope(request *remoteoutputservice.BatchCreateRequest, requestPathPrefix, allowedScope string) error {
+	scope, err := normalizeOutputPath(allowedScope)
+	if err != nil {
+		return util.StatusWrapf(err, "Invalid allowed scope %#v", allowedScope)
+	}
+	pathPrefix, err := normalizeOutputPath(requestPathPrefix)
+	if err != nil {
+		return util.StatusWrap(err, "Invalid path prefix")
+	}
+	if !isInOutputPathSubtree(pathPrefix, scope) {
+		if !isInOutputPathSubtree(scope, pathPrefix) {
+			return status.Errorf(codes.InvalidArgument, "Path prefix %#v neither is located inside allowed scope %#v, nor contains it", pathPrefix, scope)
+		}
+		if request.CleanPathPrefix {
+			return status.Errorf(codes.PermissionDenied, "Cleaning path prefix %#v would remove entries outside allowed scope %#v", pathPrefix, scope)
+		}
+	}
+
+	checkEntryPath := func(entryPath string) error {
+		p, err := normalizeOutputPath(requestPathPrefix, entryPath)
+		if err != nil {
+			return err
+		}
+		if !isInOutputPathSubtree(p, scope) {
+			return status.Errorf(codes.PermissionDenied, "Path resolves to %#v, which is outside allowed scope %#v", p, scope)
+		}
+		return nil
+	}
+	for _, entry := range request.Files {
+		if err := checkEntryPath(entry.Path); err != nil {
+			return util.StatusWrapf(err, "Invalid path for file %#v", entry.Path)
+		}
+	}
+	for _, entry := range request.Directories {
+		if err := checkEntryPath(entry.Path); err != nil {
+			return util.StatusWrapf(err, "Invalid path for directory %#v", entry.Path)
+		}
+	}
+	for _, entry := range request.Symlinks {
+		if err := checkEntryPath(entry.Path); err != nil {
+			return util.StatusWrapf(err, "Invalid path for symbolic link %#v", entry.Path)
+		}
+	}
+	return nil
+}
+
+// batchCreate contains the implementation shared by BatchCreate() and
+// ExtendedBatchCreate(). If validateTreeDigests is set and one or more
+// Tree objects of directories are absent, no entries are created, and
+// the status of every directory is returned. If allowedScope is set,
+// all entries must be located at or below it.
+func (d *RemoteOutputServiceDirectory) batchCreate(ctx context.Context, request *remoteoutputservice.BatchCreateRequest, validateTreeDigests bool, allowedScope string) ([]*status_pb.Status, error) {
+	outputPathState, buildState, prefixCreator, pathPrefix, prefixDepth, err := d.prepareBatchCreate(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate all paths prior to creating any directories, so that
+	// invalid requests don't leave intermediate directories behind.
+	for _, entry := range request.Files {
+		if err := d.validateBatchCreateEntryPath(buildState, pathPrefix, prefixDepth, entry.Path); err != nil {
+			return nil, util.StatusWrapf(err, "Invalid path for file %#v", entry.Path)
+		}
+	}
+	for _, entry := range request.Directories {
+		if err := d.validateBatchCreateEntryPath(buildState, pathPrefix, prefixDepth, entry.Path); err != nil {
+			return nil, util.StatusWrapf(err, "Invalid path for directory %#v", entry.Path)
+		}
+	}
+	for _, entry := range request.Symlinks {
+		if err := d.validateBatchCreateEntryPath(buildState, pathPrefix, prefixDepth, entry.Path); err != nil {
+			return nil, util.StatusWrapf(err, "Invalid path for symbolic link %#v", entry.Path)
+		}
+		if err := d.validateSymlinkTarget(entry); err != nil {
+			return nil, err
+		}
+	}
+	if allowedScope != "" {
+		if err := checkBatchCreateScope(request, pathPrefix, allowedScope); err != nil {
+			return nil, err
+		}
+	}
+
+	if validateTreeDigests && len(request.Directories) > 0 {
+		if directoryStatuses, err := d.findMissingTreeDigests(ctx, outputPathState, buildState, request.Directories); err != nil || directoryStatuses != nil {
+			return directoryStatuses, err
+		}
+	}
+
+	if isNoopBatchCreate(request) {
+		return nil, nil
+	}
+	if _, err := createBatchCreatePathPrefix(prefixCreator, pathPrefix, request.CleanPathPrefix, false); err != nil {
+		return nil, err
+	}
+	for _, entry := range request.Files {
+		if err := d.createFile(outputPathState, buildState, prefixCreator, entry); err != nil {
+			return nil, err
+		}
+	}
+	for _, entry := range request.Directories {
+		if err := d.createDirectory(outputPathState, buildState, prefixCreator, entry); err != nil {
+			return nil, err
+		}
+	}
+	for _, entry := range request.Symlinks {
+		if err := d.createSymlink(buildState, pathPrefix, prefixCreator, entry); err != nil {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+// BestEffortBatchCreate is identical to BatchCreate(), except that it
+// continues creating files, directories and symbolic links if
+// individual entries fail. The status of every entry is returned, so
+// that the client can retry the ones that failed.
+func (d *RemoteOutputServiceDirectory) BestEffortBatchCreate(ctx context.Context, request *remoteoutputservice.BatchCreateRequest) (*outputpaths.BestEffortBatchCreateResponse, error) {
+	outputPathState, buildState, prefixCreator, pathPrefix, prefixDepth, err := d.prepareBatchCreate(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	if isNoopBatchCreate(request) {
+		return &outputpaths.BestEffortBatchCreateResponse{}, nil
+	}
+	if _, err := createBatchCreatePathPrefix(prefixCreator, pathPrefix, request.CleanPathPrefix, false); err != nil {
+		return nil, err
+	}
+
+	response := &outputpaths.BestEffortBatchCreateResponse{
+		Files:       make([]*status_pb.Status, 0, len(request.Files)),
+		Directories: make([]*status_pb.Status, 0, len(request.Directories)),
+		Symlinks:    make([]*status_pb.Status, 0, len(request.Symlinks)),
+	}
+	for _, entry := range request.Files {
+		if err = d.validateBatchCreateEntryPath(buildState, pathPrefix, prefixDepth, entry.Path); err != nil {
+			err = util.StatusWrapf(err, "Invalid path for file %#v", entry.Path)
+		} else {
+			err = d.createFile(outputPathState, buildState, prefixCreator, entry)
+		}
+		response.Files = append(response.Files, status.Convert(err).Proto())
+	}
+	for _, entry := range request.Directories {
+		if err = d.validateBatchCreateEntryPath(buildState, pathPrefix, prefixDepth, entry.Path); err != nil {
+			err = util.StatusWrapf(err, "Invalid path for directory %#v", entry.Path)
+		} else {
+			err = d.createDirectory(outputPathState, buildState, prefixCreator, entry)
+		}
+		response.Directories = append(response.Directories, status.Convert(err).Proto())
+	}
+	for _, entry := range request.Symlinks {
+		if err = d.validateBatchCreateEntryPath(buildState, pathPrefix, prefixDepth, entry.Path); err != nil {
+			err = util.StatusWrapf(err, "Invalid path for symbolic link %#v", entry.Path)
+		} else {
+			err = d.createSymlink(buildState, pathPrefix, prefixCreator, entry)
+		}
+		response.Symlinks = append(response.Symlinks, status.Convert(err).Proto())
+	}
+	return response, nil
+}
+
+// CreateStream is identical to BatchCreate(), except that entries are
+// streamed by the client, and are created as soon as they are
+// received. This allows clients to create large numbers of outputs
+// without exceeding gRPC message size limits.
+func (d *RemoteOutputServiceDirectory) CreateStream(server outputpaths.OutputPaths_CreateStreamServer) error {
+	request, err := server.Recv()
+	if err == io.EOF {
+		return status.Error(codes.InvalidArgument, "Stream does not contain a header")
+	} else if err != nil {
+		return err
+	}
+	header, ok := request.Entry.(*outputpaths.CreateStreamRequest_Header)
+	if !ok {
+		return status.Error(codes.InvalidArgument, "First message in the stream does not contain a header")
+	}
+	batchCreateRequest := &remoteoutputservice.BatchCreateRequest{
+		BuildId:         header.Header.BuildId,
+		PathPrefix:      header.Header.PathPrefix,
+		CleanPathPrefix: header.Header.CleanPathPrefix,
+	}
+	outputPathState, buildState, prefixCreator, pathPrefix, prefixDepth, err := d.prepareBatchCreate(server.Context(), batchCreateRequest)
+	if err != nil {
+		return err
+	}
+	removedPaths, err := createBatchCreatePathPrefix(prefixCreator, pathPrefix, batchCreateRequest.CleanPathPrefix, header.Header.ReportRemovedPaths)
+	if err != nil {
+		return err
+	}
+
+	response := outputpaths.CreateStreamResponse{
+		RemovedPaths: removedPaths,
+	}
+	for {
+		request, err := server.Recv()
+		if err == io.EOF {
+			return server.SendAndClose(&response)
+		} else if err != nil {
+			return err
+		}
+
+		switch entry := request.Entry.(type) {
+		case *outputpaths.CreateStreamRequest_File:
+			if err := d.validateBatchCreateEntryPath(buildState, pathPrefix, prefixDepth, entry.File.Path); err != nil {
+				return util.StatusWrapf(err, "Invalid path for file %#v", entry.File.Path)
+			}
+			if err := d.createFile(outputPathState, buildState, prefixCreator, entry.File); err != nil {
+				return err
+			}
+			response.Files++
+		case *outputpaths.CreateStreamRequest_Directory:
+			if err := d.validateBatchCreateEntryPath(buildState, pathPrefix, prefixDepth, entry.Directory.Path); err != nil {
+				return util.StatusWrapf(err, "Invalid path for directory %#v", entry.Directory.Path)
+			}
+			if err := d.createDirectory(outputPathState, buildState, prefixCreator, entry.Directory); err != nil {
+				return err
+			}
+			response.Directories++
+		case *outputpaths.CreateStreamRequest_Symlink:
+			if err := d.validateBatchCreateEntryPath(buildState, pathPrefix, prefixDepth, entry.Symlink.Path); err != nil {
+				return util.StatusWrapf(err, "Invalid path for symbolic link %#v", entry.Symlink.Path)
+			}
+			if err := d.createSymlink(buildState, pathPrefix, prefixCreator, entry.Symlink); err != nil {
+				return err
+			}
+			response.Symlinks++
+		case *outputpaths.CreateStreamRequest_Header:
+			return status.Error(codes.InvalidArgument, "Only the first message in the stream may contain a header")
+		default:
+			return status.Error(codes.InvalidArgument, "Message does not contain a file, directory or symbolic link")
+		}
+	}
+}
+
+// lookupRetrier is used by statWalker to retry lookups of children of
+// directories that fail with an infrastructure error. Such errors are
+// typically caused by transient failures to load the contents of a
+// directory from the Content Addressable Storage. Retrying these
+// lookups prevents them from causing an entire BatchStat() request to
+// fail. Errors that aren't gRPC status errors, such as ENOENT, are
+// never retried.
+type lookupRetrier struct {
+	ctx      context.Context
+	clock    clock.Clock
+	endTime  time.Time
+	interval time.Duration
+	attempts int
+}
+
+const (
+	lookupRetryInitialInterval = 100 * time.Millisecond
+	lookupRetryMaximumInterval = 5 * time.Second
+)
+
+// newLookupRetrier creates a lookupRetrier for resolving a single path.
+// Nil is returned if retrying is disabled.
+func (d *RemoteOutputServiceDirectory) newLookupRetrier(ctx context.Context) *lookupRetrier {
+	if d.maximumLookupRetryDelay <= 0 {
+		return nil
+	}
+	return &lookupRetrier{
+		ctx:      ctx,
+		clock:    d.clock,
+		endTime:  d.clock.Now().Add(d.maximumLookupRetryDelay),
+		interval: lookupRetryInitialInterval,
+	}
+}
+
+// maybeRetry checks whether a failed lookup should be retried. If so,
+// it sleeps before returning true. Otherwise, it returns the error that
+// should be returned to the caller.
+func (r *lookupRetrier) maybeRetry(err error) (bool, error) {
+	if r == nil {
+		return false, err
+	}
+	if _, ok := status.FromError(err); !ok || !util.IsInfrastructureError(err) {
+		return false, err
+	}
+	if r.clock.Now().After(r.endTime) {
+		return false, util.StatusWrapf(err, "Lookup failed after %d attempts", r.attempts+1)
+	}
+
+	timer, ch := r.clock.NewTimer(r.interval)
+	select {
+	case <-ch:
+		r.attempts++
+		r.interval *= 2
+		if r.interval > lookupRetryMaximumInterval {
+			r.interval = lookupRetryMaximumInterval
+		}
+		return true, nil
+	case <-r.ctx.Done():
+		timer.Stop()
+		return false, util.StatusFromContext(r.ctx)
+	}
+}
+
+// statWalker is an implementation of ScopeWalker and ComponentWalker
+// that is used by BatchStat() to resolve the file or directory
+// corresponding to a requested path. It is capable of expanding
+// symbolic links, if encountered.
+//
+// Once the maximum number of symbolic link redirections is reached,
+// symbolic links are no longer expanded. The path is then reported as
+// being external, so that the client resolves the remainder of the path
+// itself. The same happens for files of types that cannot be expressed
+// as a FileStatus, such as FIFOs and device nodes.
+type statWalker struct {
+	followSymlinks  bool
+	caseInsensitive bool
+	digestFunction  *digest.Function
+	symlinksLeft    int
+	retrier         *lookupRetrier
+
+	stack               util.NonEmptyStack[virtual.PrepopulatedDirectory]
+	depthLimit          pathDepthLimit
+	fileStatus          *remoteoutputservice.FileStatus
+	leaf                virtual.NativeLeaf
+	symlinkTargets      []string
+	symlinkLimitReached bool
+	unsupportedFileType bool
+	// Whether the path provided by the client itself left the
+	// output path through ".." components, as opposed to the target
+	// of a symbolic link.
+	parentTraversalEscape bool
+}
+
+func (cw *statWalker) OnScope(absolute bool) (path.ComponentWalker, error) {
+	if absolute {
+		cw.stack.PopAll()
+		cw.depthLimit.depth = 0
+	}
+	// Currently in a known directory.
+	cw.fileStatus = &remoteoutputservice.FileStatus{
+		FileType: &remoteoutputservice.FileStatus_Directory_{},
+	}
+	return cw, nil
+}
+
+// lookupChild looks up a child of the current directory. If
+// case-insensitive resolution is enabled and the directory does not
+// contain a child with the exact name, a child whose name only differs
+// by case is returned. The lookup fails if there are multiple such
+// children, as it is ambiguous which one is meant.
+func (cw *statWalker) lookupChild(name path.Component) (virtual.PrepopulatedDirectoryChild, error) {
+	for {
+		child, err := cw.lookupChildOnce(name)
+		if err == nil {
+			return child, nil
+		}
+		retry, err := cw.retrier.maybeRetry(err)
+		if !retry {
+			return virtual.PrepopulatedDirectoryChild{}, err
+		}
+	}
+}
+
+func (cw *statWalker) lookupChildOnce(name path.Component) (virtual.PrepopulatedDirectoryChild, error) {
+	directory := cw.stack.Peek()
+	child, err := directory.LookupChild(name)
+	if err != syscall.ENOENT || !cw.caseInsensitive {
+		return child, err
+	}
+
+	directories, leaves, err := directory.LookupAllChildren()
+	if err != nil {
+		return virtual.PrepopulatedDirectoryChild{}, err
+	}
+	var matches []path.Component
+	for _, entry := range directories {
+		if strings.EqualFold(entry.Name.String(), name.String()) {
+			child = virtual.PrepopulatedDirectoryChild{}.FromDirectory(entry.Child)
+			matches = append(matches, entry.Name)
+		}
+	}
+	for _, entry := range leaves {
+		if strings.EqualFold(entry.Name.String(), name.String()) {
+			child = virtual.PrepopulatedDirectoryChild{}.FromLeaf(entry.Child)
+			matches = append(matches, entry.Name)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return virtual.PrepopulatedDirectoryChild{}, syscall.ENOENT
+	case 1:
+		return child, nil
+	default:
+		return virtual.PrepopulatedDirectoryChild{}, status.Errorf(codes.InvalidArgument, "Name %#v matches both %#v and %#v when compared case-insensitively", name.String(), matches[0].String(), matches[1].String())
+	}
+}
+
+func (cw *statWalker) OnDirectory(name path.Component) (path.GotDirectoryOrSymlink, error) {
+	child, err := cw.lookupChild(name)
+	if err != nil {
+		return nil, err
+	}
+
+	directory, leaf := child.GetPair()
+	if directory != nil {
+		// Got a directory.
+		if err := cw.depthLimit.enter(); err != nil {
+			return nil, err
+		}
+		cw.stack.Push(directory)
+		return path.GotDirectory{
+			Child:        cw,
+			IsReversible: true,
+		}, nil
+	}
+
+	target, err := leaf.Readlink()
 	if err == syscall.EINVAL {
 		return nil, syscall.ENOTDIR
 	} else if err != nil {
 		return nil, err
 	}
 
-	// Got a symbolic link in the middle of a path. Those should
-	// always be followed.
-	cw.fileStatus = &remoteoutputservice.FileStatus{
-		FileType: &remoteoutputservice.FileStatus_External_{},
+	// Got a symbolic link in the middle of a path. Those should
+	// always be followed, unless the maximum number of redirections
+	// has been reached.
+	cw.fileStatus = &remoteoutputservice.FileStatus{
+		FileType: &remoteoutputservice.FileStatus_External_{},
+	}
+	if cw.symlinksLeft == 0 {
+		cw.symlinkLimitReached = true
+		return path.GotDirectory{
+			Child:        path.VoidComponentWalker,
+			IsReversible: false,
+		}, nil
+	}
+	cw.symlinksLeft--
+	cw.symlinkTargets = append(cw.symlinkTargets, target)
+	return path.GotSymlink{
+		Parent: cw,
+		Target: target,
+	}, nil
+}
+
+func (cw *statWalker) OnTerminal(name path.Component) (*path.GotSymlink, error) {
+	child, err := cw.lookupChild(name)
+	if err != nil {
+		return nil, err
+	}
+
+	directory, leaf := child.GetPair()
+	if directory != nil {
+		// Got a directory. The existing FileStatus is sufficient.
+		if err := cw.depthLimit.enter(); err != nil {
+			return nil, err
+		}
+		cw.stack.Push(directory)
+		return nil, nil
+	}
+
+	if cw.followSymlinks {
+		target, err := leaf.Readlink()
+		if err == nil {
+			// Got a symbolic link, and we should follow it.
+			cw.fileStatus = &remoteoutputservice.FileStatus{
+				FileType: &remoteoutputservice.FileStatus_External_{},
+			}
+			if cw.symlinksLeft == 0 {
+				cw.symlinkLimitReached = true
+				return nil, nil
+			}
+			cw.symlinksLeft--
+			cw.symlinkTargets = append(cw.symlinkTargets, target)
+			return &path.GotSymlink{
+				Parent: cw,
+				Target: target,
+			}, nil
+		}
+		if err != syscall.EINVAL {
+			return nil, err
+		}
+	}
+
+	fileStatus, err := leaf.GetOutputServiceFileStatus(cw.digestFunction)
+	if err != nil {
+		return nil, err
+	}
+	if fileStatus.FileType == nil {
+		// Got a file of a type that cannot be expressed as a
+		// FileStatus, such as a FIFO or a device node. Report
+		// it as being external, so that the client obtains its
+		// status through the file system.
+		cw.fileStatus = &remoteoutputservice.FileStatus{
+			FileType: &remoteoutputservice.FileStatus_External_{},
+		}
+		cw.unsupportedFileType = true
+		return nil, nil
+	}
+	cw.fileStatus = fileStatus
+	cw.leaf = leaf
+	return nil, nil
+}
+
+func (cw *statWalker) OnUp() (path.ComponentWalker, error) {
+	if _, ok := cw.stack.PopSingle(); !ok {
+		cw.fileStatus = &remoteoutputservice.FileStatus{
+			FileType: &remoteoutputservice.FileStatus_External_{},
+		}
+		cw.parentTraversalEscape = len(cw.symlinkTargets) == 0
+		return path.VoidComponentWalker, nil
+	}
+	cw.depthLimit.leave()
+	return cw, nil
+}
+
+// getDirectoryFileStatus returns the status of a directory, as reported
+// by BatchStat(). For directories we need to provide the last
+// modification time, as the client uses that to invalidate cached
+// results.
+func getDirectoryFileStatus(ctx context.Context, directory virtual.PrepopulatedDirectory) *remoteoutputservice.FileStatus_Directory {
+	var attributes virtual.Attributes
+	directory.VirtualGetAttributes(ctx, virtual.AttributesMaskLastDataModificationTime, &attributes)
+	lastModifiedTime, ok := attributes.GetLastDataModificationTime()
+	if !ok {
+		panic("Directory did not provide a last data modification time, even though the Remote Output Service protocol requires it")
+	}
+	return &remoteoutputservice.FileStatus_Directory{
+		LastModifiedTime: timestamppb.New(lastModifiedTime),
+	}
+}
+
+// statResult is returned by statPath(), containing the status of a
+// path, and additional information that ExtendedBatchStat() may
+// return.
+type statResult struct {
+	response *remoteoutputservice.StatResponse
+	// The directory to which the path resolves, if any.
+	directory virtual.PrepopulatedDirectory
+	// The path to which the path resolves after expanding symbolic
+	// links.
+	resolvedPath string
+	// The targets of the symbolic links that were expanded, in the
+	// order in which they were encountered.
+	symlinkTargets []string
+	// Whether expansion of symbolic links stopped, because the
+	// maximum number of redirections was reached.
+	symlinkLimitReached bool
+}
+
+// statPath resolves a single path provided to BatchStat() or
+// StatStream(), returning its status.
+func (d *RemoteOutputServiceDirectory) statPath(ctx context.Context, outputPathState *outputPathState, buildState *buildState, request *remoteoutputservice.BatchStatRequest, statPath string, caseInsensitive bool) (statResult, error) {
+	statWalker := statWalker{
+		followSymlinks:  request.FollowSymlinks,
+		caseInsensitive: caseInsensitive,
+		symlinksLeft:    d.maximumSymlinkRedirections,
+		retrier:         d.newLookupRetrier(ctx),
+		stack:           util.NewNonEmptyStack[virtual.PrepopulatedDirectory](outputPathState.rootDirectory),
+		depthLimit:      newPathDepthLimit(d.maximumPathDepth),
+		fileStatus: &remoteoutputservice.FileStatus{
+			FileType: &remoteoutputservice.FileStatus_External_{},
+		},
+	}
+	if request.IncludeFileDigest {
+		statWalker.digestFunction = &buildState.digestFunction
+	}
+
+	resolvedPath, scopeWalker := path.EmptyBuilder.Join(
+		buildState.scopeWalkerFactory.New(&statWalker))
+	if err := path.Resolve(d.applyDefaultPathPrefix(buildState, statPath), scopeWalker); err == syscall.ENOENT {
+		// Path does not exist.
+		return statResult{response: &remoteoutputservice.StatResponse{}}, nil
+	} else if err != nil {
+		// Some other error occurred.
+		return statResult{}, util.StatusWrapf(d.syscallErrorMapping.convert(err), "Failed to resolve path %#v beyond %#v", statPath, resolvedPath.String())
+	}
+
+	var directory virtual.PrepopulatedDirectory
+	switch fileType := statWalker.fileStatus.FileType.(type) {
+	case *remoteoutputservice.FileStatus_Directory_:
+		directory = statWalker.stack.Peek()
+		fileType.Directory = getDirectoryFileStatus(ctx, directory)
+	case *remoteoutputservice.FileStatus_External_:
+		// Path resolves to a location outside the file
+		// system. Return the resolved path back to the
+		// client, so it can stat() it manually.
+		nextPath := resolvedPath.String()
+		if !statWalker.symlinkLimitReached && !statWalker.unsupportedFileType {
+			d.lock.Lock()
+			externalPathPolicy := buildState.externalPathPolicy
+			d.lock.Unlock()
+
+			switch externalPathPolicy {
+			case outputpaths.ExternalPathPolicy_RETURN_ERROR:
+				return statResult{}, status.Errorf(codes.InvalidArgument, "Path %#v resolves to %#v, which is outside the output path", statPath, nextPath)
+			case outputpaths.ExternalPathPolicy_RETURN_RAW_TARGET:
+				if symlinkTargets := statWalker.symlinkTargets; len(symlinkTargets) > 0 {
+					nextPath = symlinkTargets[len(symlinkTargets)-1]
+				}
+			case outputpaths.ExternalPathPolicy_RETURN_ERROR_ON_PARENT_TRAVERSAL:
+				if statWalker.parentTraversalEscape {
+					return statResult{}, status.Errorf(codes.InvalidArgument, "Path %#v traverses above the root of the output path", statPath)
+				}
+			}
+		}
+		fileType.External = &remoteoutputservice.FileStatus_External{
+			NextPath: nextPath,
+		}
+	}
+	return statResult{
+		response: &remoteoutputservice.StatResponse{
+			FileStatus: statWalker.fileStatus,
+		},
+		directory:           directory,
+		resolvedPath:        resolvedPath.String(),
+		symlinkTargets:      statWalker.symlinkTargets,
+		symlinkLimitReached: statWalker.symlinkLimitReached,
+	}, nil
+}
+
+// BatchStat can be called by a build client to obtain the status of
+// files and directories.
+//
+// Calling this method over gRPC may be far more efficient than
+// obtaining this information through the FUSE file system, as batching
+// significantly reduces the amount of context switching. It also
+// prevents the computation of digests for files for which the digest is
+// already known.
+//
+// As the response contains the status of all paths, its size may
+// exceed the maximum gRPC message size. If a maximum response size is
+// configured, the request fails with RESOURCE_EXHAUSTED instead,
+// reporting the number of paths whose status fits. Clients may either
+// split the request, or call StatStream().
+func (d *RemoteOutputServiceDirectory) BatchStat(ctx context.Context, request *remoteoutputservice.BatchStatRequest) (*remoteoutputservice.BatchStatResponse, error) {
+	outputPathState, buildState, err := d.getOutputPathAndBuildState(request.BuildId)
+	if err != nil {
+		return nil, err
+	}
+
+	response := remoteoutputservice.BatchStatResponse{
+		Responses: make([]*remoteoutputservice.StatResponse, 0, len(request.Paths)),
+	}
+	responseSizeBytes := 0
+	for i, statPath := range request.Paths {
+		result, err := d.statPath(ctx, outputPathState, buildState, request, statPath, false)
+		if err != nil {
+			return nil, err
+		}
+		if d.maximumBatchStatResponseSizeBytes > 0 {
+			responseSizeBytes += protowire.SizeTag(1) + protowire.SizeBytes(proto.Size(result.response))
+			if responseSizeBytes > d.maximumBatchStatResponseSizeBytes {
+				return nil, status.Errorf(codes.ResourceExhausted, "Response would exceed the maximum size of %d bytes, as only the status of the first %d of %d paths fits. Either split the request, or call StatStream()", d.maximumBatchStatResponseSizeBytes, i, len(request.Paths))
+			}
+		}
+		response.Responses = append(response.Responses, result.response)
+	}
+	return &response, nil
+}
+
+// StatStream is identical to BatchStat(), except that responses are
+// sent back to the client as soon as they have been computed. This
+// prevents large sets of paths from yielding responses that exceed the
+// maximum gRPC message size.
+func (d *RemoteOutputServiceDirectory) StatStream(request *remoteoutputservice.BatchStatRequest, server outputpaths.OutputPaths_StatStreamServer) error {
+	outputPathState, buildState, err := d.getOutputPathAndBuildState(request.BuildId)
+	if err != nil {
+		return err
+	}
+
+	ctx := server.Context()
+	for i, statPath := range request.Paths {
+		result, err := d.statPath(ctx, outputPathState, buildState, request, statPath, false)
+		if err != nil {
+			return err
+		}
+		if err := server.Send(&outputpaths.StatStreamResponse{
+			Index:    uint32(i),
+			Response: result.response,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ExtendedBatchStat is identical to BatchStat(), except that it can
+// return information that cannot be expressed using the Remote Output
+// Service protocol, such as the digests of directories.
+func (d *RemoteOutputServiceDirectory) ExtendedBatchStat(ctx context.Context, request *outputpaths.ExtendedBatchStatRequest) (*outputpaths.ExtendedBatchStatResponse, error) {
+	batchStatRequest := request.Request
+	if batchStatRequest == nil {
+		return nil, status.Error(codes.InvalidArgument, "No BatchStat() request provided")
+	}
+	outputPathState, buildState, err := d.getOutputPathAndBuildState(batchStatRequest.BuildId)
+	if err != nil {
+		return nil, err
+	}
+
+	var directoryDigestComputer *directoryDigestComputer
+	if request.IncludeDirectoryDigest {
+		directoryDigestComputer = newDirectoryDigestComputer(ctx, buildState.digestFunction)
+	}
+	var responsesByPath map[string]*outputpaths.ExtendedStatResponse
+	if request.DeduplicatePaths {
+		responsesByPath = map[string]*outputpaths.ExtendedStatResponse{}
+	}
+	response := outputpaths.ExtendedBatchStatResponse{
+		Responses: make([]*outputpaths.ExtendedStatResponse, 0, len(batchStatRequest.Paths)),
+	}
+	for _, statPath := range batchStatRequest.Paths {
+		extendedStatResponse, ok := responsesByPath[statPath]
+		if !ok {
+			extendedStatResponse, err = d.extendedStatPath(ctx, outputPathState, buildState, request, directoryDigestComputer, statPath)
+			if err != nil {
+				if !request.BestEffort {
+					return nil, err
+				}
+				extendedStatResponse = &outputpaths.ExtendedStatResponse{
+					Error: status.Convert(err).Proto(),
+				}
+			}
+			if responsesByPath != nil {
+				responsesByPath[statPath] = extendedStatResponse
+			}
+		}
+		response.Responses = append(response.Responses, extendedStatResponse)
+	}
+	return &response, nil
+}
+
+// extendedStatPath resolves a single path provided to
+// ExtendedBatchStat(), returning its status and any additional
+// information that was requested.
+func (d *RemoteOutputServiceDirectory) extendedStatPath(ctx context.Context, outputPathState *outputPathState, buildState *buildState, request *outputpaths.ExtendedBatchStatRequest, directoryDigestComputer *directoryDigestComputer, statPath string) (*outputpaths.ExtendedStatResponse, error) {
+	result, err := d.statPath(ctx, outputPathState, buildState, request.Request, statPath, request.CaseInsensitive)
+	if err != nil {
+		return nil, err
+	}
+	extendedStatResponse := &outputpaths.ExtendedStatResponse{
+		Response: result.response,
+	}
+	if request.IncludeResolvedPath && result.response.FileStatus != nil {
+		extendedStatResponse.ResolvedPath = result.resolvedPath
+	}
+	if request.IncludeSymlinkChain {
+		extendedStatResponse.SymlinkChain = result.symlinkTargets
+		extendedStatResponse.SymlinkChainTruncated = result.symlinkLimitReached
+	}
+	if directoryDigestComputer != nil && result.directory != nil {
+		directoryDigest, ok, err := directoryDigestComputer.getDigest(result.directory, nil)
+		if err != nil {
+			return nil, util.StatusWrapf(err, "Failed to compute digest of directory %#v", statPath)
+		}
+		if ok {
+			extendedStatResponse.DirectoryDigest = directoryDigest.GetProto()
+		}
+	}
+	if request.IncludeDirectoryEntryCount && result.directory != nil {
+		directories, leaves, err := result.directory.LookupAllChildren()
+		if err != nil {
+			return nil, util.StatusWrapf(err, "Failed to count children of directory %#v", statPath)
+		}
+		extendedStatResponse.DirectoryEntryCount = uint64(len(directories) + len(leaves))
+	}
+	return extendedStatResponse, nil
+}
+
+// StatDigest returns the type of a single path in the output path of a
+// running build and, if it is a regular file, its digest. Paths are
+// resolved in the same way as BatchStat() does.
+func (d *RemoteOutputServiceDirectory) StatDigest(ctx context.Context, request *outputpaths.StatDigestRequest) (*outputpaths.StatDigestResponse, error) {
+	outputPathState, buildState, err := d.getOutputPathAndBuildState(request.BuildId)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := d.statPath(ctx, outputPathState, buildState, &remoteoutputservice.BatchStatRequest{
+		IncludeFileDigest: true,
+		FollowSymlinks:    request.FollowSymlinks,
+	}, request.Path, false)
+	if err != nil {
+		return nil, err
+	}
+	fileStatus := result.response.FileStatus
+	if fileStatus == nil {
+		return nil, status.Errorf(codes.NotFound, "Path %#v does not exist", request.Path)
+	}
+
+	switch fileType := fileStatus.FileType.(type) {
+	case *remoteoutputservice.FileStatus_File_:
+		return &outputpaths.StatDigestResponse{
+			FileType: outputpaths.StatDigestResponse_FILE,
+			Digest:   fileType.File.GetDigest(),
+		}, nil
+	case *remoteoutputservice.FileStatus_Directory_:
+		return &outputpaths.StatDigestResponse{
+			FileType: outputpaths.StatDigestResponse_DIRECTORY,
+		}, nil
+	case *remoteoutputservice.FileStatus_Symlink_:
+		return &outputpaths.StatDigestResponse{
+			FileType: outputpaths.StatDigestResponse_SYMLINK,
+		}, nil
+	default:
+		return &outputpaths.StatDigestResponse{
+			FileType: outputpaths.StatDigestResponse_EXTERNAL,
+		}, nil
+	}
+}
+
+// authorizeBuild checks whether the caller is permitted to modify the
+// state of a running build. It returns the output path associated with
+// the build, or nil if the build ID is unknown. As the directory lock
+// is released while authorizing, callers must check that the build is
+// still associated with the returned output path.
+func (d *RemoteOutputServiceDirectory) authorizeBuild(ctx context.Context, buildID string) (*outputPathState, error) {
+	d.lock.Lock()
+	outputPathState, ok := d.buildIDs[buildID]
+	d.lock.Unlock()
+	if !ok {
+		return nil, nil
+	}
+	if err := d.authorizeOutputBase(ctx, outputPathState.outputBaseID); err != nil {
+		return nil, err
+	}
+	return outputPathState, nil
+}
+
+// FinalizeBuild can be called by a build client to indicate the current
+// build has completed. This prevents successive BatchCreate() and
+// BatchStat() calls from being processed.
+func (d *RemoteOutputServiceDirectory) FinalizeBuild(ctx context.Context, request *remoteoutputservice.FinalizeBuildRequest) (*emptypb.Empty, error) {
+	outputPathState, err := d.authorizeBuild(ctx, request.BuildId)
+	if err != nil {
+		return nil, err
+	}
+
+	// Let fetches that were triggered while the build was running
+	// complete, so that no traffic against the Content Addressable
+	// Storage occurs after the build is finalized.
+	if outputPathState != nil && d.finalizeBuildWaitsForFetches {
+		if err := outputPathState.fetchStatistics.inFlight.wait(ctx); err != nil {
+			return nil, util.StatusWrap(err, "Failed to wait for outstanding fetches to complete")
+		}
+	}
+
+	// Upload the contents of the output path before finalizing the
+	// build, if they need to be retained. This must be done without
+	// holding the directory lock, as files may need to be uploaded.
+	var build *finalizedBuild
+	if outputPathState != nil && d.retainedFinalizedBuilds > 0 {
+		build = d.snapshotOutputPath(ctx, outputPathState, request.BuildId)
+	}
+
+	var removals []directoryEntryRemoval
+	d.lock.Lock()
+	// Silently ignore requests for unknown build IDs. This ensures
+	// that FinalizeBuild() remains idempotent.
+	if outputPathState != nil && d.buildIDs[request.BuildId] == outputPathState {
+		buildState := outputPathState.buildState
+		outputPathState.rootDirectory.FinalizeBuild(ctx, buildState.digestFunction)
+		delete(d.buildIDs, buildState.id)
+		outputPathState.buildState = nil
+		outputPathState.finalizedBuildState = buildState
+		d.buildEvents.append(outputpaths.BuildEvent_BUILD_FINALIZED, outputPathState.outputBaseID, buildState.id, d.clock.Now())
+		if build != nil {
+			build.casBytesFetched = outputPathState.getBuildBytesFetched(buildState)
+			build.casFetchFailures = outputPathState.getBuildFetchFailures(buildState)
+			build.clientMetadata = buildState.clientMetadata
+			removals = d.addFinalizedBuildLocked(outputPathState, build)
+		}
+	}
+	d.lock.Unlock()
+
+	for _, removal := range removals {
+		removal.notify()
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// ReopenBuild resumes the most recently finalized build of an output
+// base. The state of the build is registered again as is, meaning that
+// filterMissingChildren() is not called.
+func (d *RemoteOutputServiceDirectory) ReopenBuild(ctx context.Context, request *outputpaths.ReopenBuildRequest) (*remoteoutputservice.StartBuildResponse, error) {
+	outputBaseID, err := newOutputBasePath(request.OutputBaseId)
+	if err != nil {
+		return nil, err
+	}
+	if err := d.authorizeOutputBase(ctx, outputBaseID); err != nil {
+		return nil, err
+	}
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	outputPathState, ok := d.outputBaseIDs[outputBaseID]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "Output base ID is not associated with any output path")
+	}
+	if buildState := outputPathState.buildState; buildState != nil {
+		if buildState.id == request.BuildId {
+			// The build was already reopened.
+			return &remoteoutputservice.StartBuildResponse{
+				OutputPathSuffix: buildState.outputPathSuffix,
+			}, nil
+		}
+		return nil, status.Errorf(codes.FailedPrecondition, "Output base ID is in use by build %#v", buildState.id)
+	}
+	buildState := outputPathState.finalizedBuildState
+	if buildState == nil || buildState.id != request.BuildId {
+		return nil, status.Error(codes.FailedPrecondition, "Build ID does not correspond to the most recently finalized build of the output base")
+	}
+	if _, ok := d.buildIDs[request.BuildId]; ok {
+		return nil, status.Error(codes.FailedPrecondition, "Build ID is in use by another output base")
+	}
+	if err := d.checkConcurrentBuildsLimitLocked(); err != nil {
+		return nil, err
+	}
+
+	outputPathState.buildState = buildState
+	outputPathState.finalizedBuildState = nil
+	d.buildIDs[buildState.id] = outputPathState
+	d.buildEvents.append(outputpaths.BuildEvent_BUILD_STARTED, outputBaseID, buildState.id, d.clock.Now())
+	return &remoteoutputservice.StartBuildResponse{
+		OutputPathSuffix: buildState.outputPathSuffix,
+	}, nil
+}
+
+// ListFinalizedBuilds returns the builds of an output base whose
+// contents have been retained after they were finalized.
+func (d *RemoteOutputServiceDirectory) ListFinalizedBuilds(ctx context.Context, request *outputpaths.ListFinalizedBuildsRequest) (*outputpaths.ListFinalizedBuildsResponse, error) {
+	outputBaseID, err := newOutputBasePath(request.OutputBaseId)
+	if err != nil {
+		return nil, err
+	}
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	builds := d.finalizedBuilds[outputBaseID]
+	response := outputpaths.ListFinalizedBuildsResponse{
+		Builds: make([]*outputpaths.FinalizedBuild, 0, len(builds)),
+	}
+	for i := len(builds) - 1; i >= 0; i-- {
+		build := builds[i]
+		response.Builds = append(response.Builds, &outputpaths.FinalizedBuild{
+			BuildId:          build.id,
+			RootTreeDigest:   build.treeDigest.GetProto(),
+			FinalizeTime:     timestamppb.New(build.finalizeTime),
+			CasBytesFetched:  build.casBytesFetched,
+			ClientMetadata:   build.clientMetadata,
+			CasFetchFailures: build.casFetchFailures,
+		})
+	}
+	return &response, nil
+}
+
+// ResolveOutputPath computes the output path suffix that StartBuild()
+// would return for a given output path prefix and output base ID,
+// without starting a build or creating an output path.
+func (d *RemoteOutputServiceDirectory) ResolveOutputPath(ctx context.Context, request *outputpaths.ResolveOutputPathRequest) (*outputpaths.ResolveOutputPathResponse, error) {
+	_, outputPath, outputPathSuffix, err := d.resolveOutputPath(ctx, request.OutputPathPrefix, request.OutputBaseId)
+	if err != nil {
+		return nil, err
+	}
+	return &outputpaths.ResolveOutputPathResponse{
+		OutputPath:       outputPath,
+		OutputPathSuffix: outputPathSuffix,
+	}, nil
+}
+
+// maximumBuildDifferences is the maximum number of differences that
+// DiffBuilds() returns.
+const maximumBuildDifferences = 10000
+
+// getFinalizedBuildTreeDigestLocked returns the digest of the Tree
+// containing the contents of a retained build of an output base.
+func (d *RemoteOutputServiceDirectory) getFinalizedBuildTreeDigestLocked(outputBaseID outputBasePath, buildID string) (digest.Digest, error) {
+	for _, build := range d.finalizedBuilds[outputBaseID] {
+		if build.id == buildID {
+			return build.treeDigest, nil
+		}
+	}
+	return digest.BadDigest, status.Errorf(codes.NotFound, "Output base does not have a retained build with ID %#v", buildID)
+}
+
+// DiffBuilds compares the contents of two retained builds of the same
+// output base, reporting the paths whose contents differ.
+func (d *RemoteOutputServiceDirectory) DiffBuilds(ctx context.Context, request *outputpaths.DiffBuildsRequest) (*outputpaths.DiffBuildsResponse, error) {
+	outputBaseID, err := newOutputBasePath(request.OutputBaseId)
+	if err != nil {
+		return nil, err
+	}
+	if err := d.authorizeOutputBase(ctx, outputBaseID); err != nil {
+		return nil, err
+	}
+	maximumDifferences := maximumBuildDifferences
+	if request.MaximumDifferences > 0 && request.MaximumDifferences < maximumBuildDifferences {
+		maximumDifferences = int(request.MaximumDifferences)
+	}
+
+	d.lock.Lock()
+	oldTreeDigest, err := d.getFinalizedBuildTreeDigestLocked(outputBaseID, request.OldBuildId)
+	if err != nil {
+		d.lock.Unlock()
+		return nil, err
+	}
+	newTreeDigest, err := d.getFinalizedBuildTreeDigestLocked(outputBaseID, request.NewBuildId)
+	d.lock.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	if oldDigestFunction, newDigestFunction := oldTreeDigest.GetDigestFunction().GetEnumValue(), newTreeDigest.GetDigestFunction().GetEnumValue(); oldDigestFunction != newDigestFunction {
+		return nil, status.Errorf(codes.InvalidArgument, "Builds use different digest functions %s and %s, meaning their contents cannot be compared", oldDigestFunction, newDigestFunction)
+	}
+
+	// Fetch the Trees without holding the directory lock, as they
+	// may need to be loaded from the Content Addressable Storage.
+	oldRootDirectory, err := d.directoryFetcher.GetTreeRootDirectory(ctx, oldTreeDigest)
+	if err != nil {
+		return nil, util.StatusWrapf(err, "Failed to fetch root directory of build %#v", request.OldBuildId)
+	}
+	newRootDirectory, err := d.directoryFetcher.GetTreeRootDirectory(ctx, newTreeDigest)
+	if err != nil {
+		return nil, util.StatusWrapf(err, "Failed to fetch root directory of build %#v", request.NewBuildId)
+	}
+	differ := newBuildDiffer(ctx, d.directoryFetcher, oldTreeDigest, newTreeDigest, maximumDifferences)
+	if err := differ.diffDirectories(oldRootDirectory, newRootDirectory, nil); err != nil {
+		return nil, err
+	}
+	return &outputpaths.DiffBuildsResponse{
+		Differences: differ.differences,
+		Truncated:   differ.truncated,
+	}, nil
+}
+
+// FinalizeSubtree can be called by a build client to indicate that the
+// contents of a directory in the output path are complete, while the
+// build continues. The contents of the directory are exported, as done
+// by ExportTree(). Successive calls to BatchCreate() that attempt to
+// modify the directory fail for the remainder of the build.
+func (d *RemoteOutputServiceDirectory) FinalizeSubtree(ctx context.Context, request *outputpaths.FinalizeSubtreeRequest) (*outputpaths.FinalizeSubtreeResponse, error) {
+	outputPathState, buildState, err := d.getOutputPathAndBuildState(request.BuildId)
+	if err != nil {
+		return nil, err
+	}
+	if err := d.authorizeOutputBase(ctx, outputPathState.outputBaseID); err != nil {
+		return nil, err
+	}
+	subtree, err := normalizeOutputPath(request.Path)
+	if err != nil {
+		return nil, util.StatusWrapf(err, "Failed to resolve path %#v", request.Path)
+	}
+
+	directoryLookup := directoryLookupComponentWalker{
+		stack: util.NewNonEmptyStack[virtual.PrepopulatedDirectory](outputPathState.rootDirectory),
+	}
+	if err := path.Resolve(request.Path, path.NewRelativeScopeWalker(&directoryLookup)); err != nil {
+		return nil, util.StatusWrapf(err, "Failed to resolve path %#v", request.Path)
+	}
+
+	// Finalize the subtree before exporting it, so that the
+	// exported contents can no longer be altered through
+	// BatchCreate().
+	d.lock.Lock()
+	_, alreadyFinalized := buildState.finalizedSubtrees[subtree]
+	if !alreadyFinalized {
+		if buildState.finalizedSubtrees == nil {
+			buildState.finalizedSubtrees = map[string]struct{}{}
+		}
+		buildState.finalizedSubtrees[subtree] = struct{}{}
+	}
+	d.lock.Unlock()
+
+	treeDigest, err := newTreeExporter(ctx, outputPathState.bareContentAddressableStorage, buildState.digestFunction, d.exportUploadConcurrency).
+		exportTree(directoryLookup.stack.Peek(), nil)
+	if err != nil {
+		if !alreadyFinalized {
+			d.lock.Lock()
+			delete(buildState.finalizedSubtrees, subtree)
+			d.lock.Unlock()
+		}
+		return nil, err
+	}
+	return &outputpaths.FinalizeSubtreeResponse{
+		TreeDigest: treeDigest.GetProto(),
+	}, nil
+}
+
+// GetActiveBuild returns information on the build that is currently
+// running against a given output base.
+func (d *RemoteOutputServiceDirectory) GetActiveBuild(ctx context.Context, request *outputpaths.GetActiveBuildRequest) (*outputpaths.GetActiveBuildResponse, error) {
+	outputBaseID, err := newOutputBasePath(request.OutputBaseId)
+	if err != nil {
+		return nil, err
+	}
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	outputPathState, ok := d.outputBaseIDs[outputBaseID]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "Output base ID is not associated with any output path")
+	}
+	buildState := outputPathState.buildState
+	if buildState == nil {
+		return &outputpaths.GetActiveBuildResponse{}, nil
+	}
+	var startBuildProgress *outputpaths.StartBuildProgress
+	if filterProgress := buildState.filterProgress; filterProgress != nil {
+		startBuildProgress = filterProgress.getProto()
+	}
+	return &outputpaths.GetActiveBuildResponse{
+		ActiveBuild: &outputpaths.ActiveBuild{
+			BuildId:            buildState.id,
+			StartTime:          timestamppb.New(buildState.startTime),
+			InstanceName:       buildState.digestFunction.GetInstanceName().String(),
+			DigestFunction:     buildState.digestFunction.GetEnumValue(),
+			CasBytesFetched:    outputPathState.getBuildBytesFetched(buildState),
+			ClientMetadata:     buildState.clientMetadata,
+			FindMissingCalls:   buildState.findMissingCalls,
+			BlobsChecked:       buildState.blobsChecked,
+			StartBuildProgress: startBuildProgress,
+		},
+	}, nil
+}
+
+// AbortBuild can be called by a build client to terminate a build
+// without finalizing it. It disassociates the build ID from the output
+// path, causing successive BatchCreate() and BatchStat() calls for the
+// build to be rejected, and cancels fetches of files and directories
+// that are in flight. Unlike FinalizeBuild(), the output path is not
+// notified that the build has completed, meaning that state it
+// persists on disk continues to reflect the last finalized build.
+//
+// Changes that were already made to the output path as part of the
+// build are not reverted.
+func (d *RemoteOutputServiceDirectory) AbortBuild(ctx context.Context, request *outputpaths.AbortBuildRequest) (*emptypb.Empty, error) {
+	outputPathState, err := d.authorizeBuild(ctx, request.BuildId)
+	if err != nil {
+		return nil, err
+	}
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	// Silently ignore requests for unknown build IDs. This ensures
+	// that AbortBuild() remains idempotent.
+	if outputPathState != nil && d.buildIDs[request.BuildId] == outputPathState {
+		delete(d.buildIDs, request.BuildId)
+		outputPathState.buildState = nil
+		outputPathState.fetchContext.cancelFetches()
+		d.buildEvents.append(outputpaths.BuildEvent_BUILD_ABORTED, outputPathState.outputBaseID, request.BuildId, d.clock.Now())
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// SetExternalPathPolicy changes how paths that resolve to a location
+// outside the output path are reported by BatchStat(), StatStream() and
+// ExtendedBatchStat() for the remainder of a build.
+func (d *RemoteOutputServiceDirectory) SetExternalPathPolicy(ctx context.Context, request *outputpaths.SetExternalPathPolicyRequest) (*emptypb.Empty, error) {
+	if _, ok := outputpaths.ExternalPathPolicy_name[int32(request.Policy)]; !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "Unknown external path policy %d", request.Policy)
+	}
+	outputPathState, err := d.authorizeBuild(ctx, request.BuildId)
+	if err != nil {
+		return nil, err
+	}
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	if outputPathState == nil || d.buildIDs[request.BuildId] != outputPathState {
+		return nil, status.Error(codes.FailedPrecondition, "Build ID is not associated with any running build")
+	}
+	outputPathState.buildState.externalPathPolicy = request.Policy
+	return &emptypb.Empty{}, nil
+}
+
+// SealBuild prevents the output path of a running build from being
+// modified through the Remote Output Service for the remainder of the
+// build. The build ID remains valid, so that BatchStat() can still be
+// called.
+func (d *RemoteOutputServiceDirectory) SealBuild(ctx context.Context, request *outputpaths.SealBuildRequest) (*emptypb.Empty, error) {
+	outputPathState, err := d.authorizeBuild(ctx, request.BuildId)
+	if err != nil {
+		return nil, err
+	}
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	if outputPathState == nil || d.buildIDs[request.BuildId] != outputPathState {
+		return nil, status.Error(codes.FailedPrecondition, "Build ID is not associated with any running build")
+	}
+	outputPathState.buildState.sealed = true
+	return &emptypb.Empty{}, nil
+}
+
+// GetOutputPath returns the absolute path of the output path of a
+// running build, as computed by StartBuild(), together with the
+// aliases that were provided.
+func (d *RemoteOutputServiceDirectory) GetOutputPath(ctx context.Context, request *outputpaths.GetOutputPathRequest) (*outputpaths.GetOutputPathResponse, error) {
+	_, buildState, err := d.getOutputPathAndBuildState(request.BuildId)
+	if err != nil {
+		return nil, err
+	}
+	return &outputpaths.GetOutputPathResponse{
+		OutputPath:        buildState.outputPath,
+		OutputPathSuffix:  buildState.outputPathSuffix,
+		OutputPathAliases: buildState.outputPathAliases,
+	}, nil
+}
+
+// VerifyTree checks whether all objects referenced by a Tree object
+// are present, without instantiating the Tree in an output path.
+func (d *RemoteOutputServiceDirectory) VerifyTree(ctx context.Context, request *outputpaths.VerifyTreeRequest) (*outputpaths.VerifyTreeResponse, error) {
+	instanceName, err := digest.NewInstanceName(request.InstanceName)
+	if err != nil {
+		return nil, util.StatusWrapf(err, "Failed to parse instance name %#v", request.InstanceName)
+	}
+	digestFunctionValue := request.DigestFunction
+	if digestFunctionValue == remoteexecution.DigestFunction_UNKNOWN {
+		digestFunctionValue = d.defaultDigestFunction
+	}
+	digestFunction, err := instanceName.GetDigestFunction(digestFunctionValue, 0)
+	if err != nil {
+		return nil, err
+	}
+	treeDigest, err := newBatchCreateDigest(digestFunction, request.TreeDigest)
+	if err != nil {
+		return nil, util.StatusWrap(err, "Invalid tree digest")
+	}
+	if sizeBytes := treeDigest.GetSizeBytes(); sizeBytes > d.maximumTreeSizeBytes {
+		return nil, status.Errorf(codes.InvalidArgument, "Tree is %d bytes in size, which exceeds the permitted maximum of %d bytes", sizeBytes, d.maximumTreeSizeBytes)
+	}
+
+	if d.startBuildConcurrency.Acquire(ctx, 1) != nil {
+		return nil, util.StatusFromContext(ctx)
+	}
+	defer d.startBuildConcurrency.Release(1)
+
+	rootDirectory, err := d.directoryFetcher.GetTreeRootDirectory(ctx, treeDigest)
+	if err != nil {
+		return nil, util.StatusWrap(err, "Failed to fetch root directory of tree")
+	}
+	verifier := newTreeVerifier(ctx, d.directoryFetcher, d.bareContentAddressableStorage, treeDigest, d.findMissingBlobsBatchSize)
+	if err := verifier.verifyDirectory(rootDirectory, nil); err != nil {
+		return nil, err
+	}
+	if err := verifier.flush(); err != nil {
+		return nil, err
+	}
+	missingDirectories, missingFiles := verifier.getMissingDigests()
+	return &outputpaths.VerifyTreeResponse{
+		MissingDirectoryDigests: missingDirectories,
+		MissingFileDigests:      missingFiles,
+	}, nil
+}
+
+// checkBuildNotSealed returns an error if the build was sealed through
+// SealBuild(), meaning that its output path may not be modified.
+func (d *RemoteOutputServiceDirectory) checkBuildNotSealed(buildState *buildState) error {
+	d.lock.Lock()
+	sealed := buildState.sealed
+	d.lock.Unlock()
+	if sealed {
+		return status.Error(codes.FailedPrecondition, "Build has been sealed, meaning its output path can no longer be modified")
+	}
+	return nil
+}
+
+// SetDefaultPathPrefix sets a path prefix that is prepended to paths
+// provided to BatchCreate() and BatchStat() for the remainder of a
+// build. This is useful for output bases that contain multiple
+// configuration directories, as it permits clients to only provide
+// paths relative to the configuration directory they use most.
+func (d *RemoteOutputServiceDirectory) SetDefaultPathPrefix(ctx context.Context, request *outputpaths.SetDefaultPathPrefixRequest) (*emptypb.Empty, error) {
+	defaultPathPrefix, err := normalizeOutputPath(request.PathPrefix)
+	if err != nil {
+		return nil, util.StatusWrapf(err, "Invalid path prefix %#v", request.PathPrefix)
+	}
+	outputPathState, err := d.authorizeBuild(ctx, request.BuildId)
+	if err != nil {
+		return nil, err
+	}
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	if outputPathState == nil || d.buildIDs[request.BuildId] != outputPathState {
+		return nil, status.Error(codes.FailedPrecondition, "Build ID is not associated with any running build")
+	}
+	outputPathState.buildState.defaultPathPrefix = defaultPathPrefix
+	return &emptypb.Empty{}, nil
+}
+
+// maximumBuildMetadataSizeBytes is the maximum combined size of the
+// keys and values that may be provided to SetBuildMetadata().
+const maximumBuildMetadataSizeBytes = 4096
+
+// SetBuildMetadata attaches key-value pairs provided by the client to
+// a running build, so that the build can be correlated with the
+// client's invocation when calling GetActiveBuild() and
+// ListFinalizedBuilds().
+func (d *RemoteOutputServiceDirectory) SetBuildMetadata(ctx context.Context, request *outputpaths.SetBuildMetadataRequest) (*emptypb.Empty, error) {
+	sizeBytes := 0
+	for key, value := range request.Metadata {
+		if key == "" {
+			return nil, status.Error(codes.InvalidArgument, "Build metadata contains an empty key")
+		}
+		sizeBytes += len(key) + len(value)
+	}
+	if sizeBytes > maximumBuildMetadataSizeBytes {
+		return nil, status.Errorf(codes.InvalidArgument, "Build metadata is %d bytes in size, which exceeds the permitted maximum of %d bytes", sizeBytes, maximumBuildMetadataSizeBytes)
+	}
+	outputPathState, err := d.authorizeBuild(ctx, request.BuildId)
+	if err != nil {
+		return nil, err
+	}
+
+	var clientMetadata map[string]string
+	if len(request.Metadata) > 0 {
+		clientMetadata = make(map[string]string, len(request.Metadata))
+		for key, value := range request.Metadata {
+			clientMetadata[key] = value
+		}
+	}
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	if outputPathState == nil || d.buildIDs[request.BuildId] != outputPathState {
+		return nil, status.Error(codes.FailedPrecondition, "Build ID is not associated with any running build")
+	}
+	outputPathState.buildState.clientMetadata = clientMetadata
+	return &emptypb.Empty{}, nil
+}
+
+// GetDirectoryResidency reports how many directories underneath a
+// directory in the output path have not been loaded from the Content
+// Addressable Storage yet. FilterChildren() is used to traverse the
+// directory hierarchy, as it does not cause any directories to be
+// loaded.
+func (d *RemoteOutputServiceDirectory) GetDirectoryResidency(ctx context.Context, request *outputpaths.GetDirectoryResidencyRequest) (*outputpaths.GetDirectoryResidencyResponse, error) {
+	outputPathState, _, err := d.getOutputPathAndBuildState(request.BuildId)
+	if err != nil {
+		return nil, err
+	}
+
+	directoryLookup := directoryLookupComponentWalker{
+		stack: util.NewNonEmptyStack[virtual.PrepopulatedDirectory](outputPathState.rootDirectory),
+	}
+	if err := path.Resolve(request.Path, path.NewRelativeScopeWalker(&directoryLookup)); err != nil {
+		return nil, util.StatusWrapf(err, "Failed to resolve path %#v", request.Path)
+	}
+
+	var response outputpaths.GetDirectoryResidencyResponse
+	if err := directoryLookup.stack.Peek().FilterChildren(func(node virtual.InitialNode, remove virtual.ChildRemover) bool {
+		if directory, _ := node.GetPair(); directory != nil {
+			response.UnloadedDirectories++
+		}
+		return true
+	}); err != nil {
+		return nil, util.StatusWrap(err, "Failed to traverse directory")
+	}
+	return &response, nil
+}
+
+// materializeProgressInterval is the number of directories that
+// Materialize() traverses between sending progress updates.
+const materializeProgressInterval = 1000
+
+// directoryMaterializer is used by Materialize() to recursively load
+// the contents of a directory and all of its subdirectories. Progress
+// is tracked, so that it can be reported to the client.
+//
+// Traversal stops early once the budget provided by the client is
+// exhausted. As directories whose contents have been loaded
+// previously don't cause any Tree objects to be fetched, a successive
+// call continues where the previous one left off.
+//
+// If a maximum depth is provided, directories located deeper than it
+// are not traversed. They are only counted, so that the client can
+// determine how much of the directory hierarchy is left to be loaded.
+type directoryMaterializer struct {
+	context             context.Context
+	budgetContext       context.Context
+	fetchStatistics     *outputPathFetchStatistics
+	bytesFetchedAtStart uint64
+	maximumBytesFetched uint64
+	maximumDepth        uint32
+	send                func(*outputpaths.MaterializeResponse) error
+
+	progress outputpaths.MaterializeResponse
+}
+
+func (dm *directoryMaterializer) reportProgress() error {
+	dm.progress.BytesFetched = dm.fetchStatistics.bytesFetched.Load() - dm.bytesFetchedAtStart
+	return dm.send(&dm.progress)
+}
+
+// isBudgetExhausted returns whether traversal needs to stop, because
+// the amount of data fetched or time spent exceeds the limits provided
+// by the client.
+func (dm *directoryMaterializer) isBudgetExhausted() bool {
+	if dm.budgetContext.Err() != nil {
+		return true
+	}
+	return dm.maximumBytesFetched > 0 && dm.fetchStatistics.bytesFetched.Load()-dm.bytesFetchedAtStart >= dm.maximumBytesFetched
+}
+
+func (dm *directoryMaterializer) materializeDirectory(d virtual.PrepopulatedDirectory, dPath *path.Trace, depth uint32) error {
+	if err := dm.context.Err(); err != nil {
+		return util.StatusFromContext(dm.context)
+	}
+	if dm.isBudgetExhausted() {
+		dm.progress.BudgetExhausted = true
+		return nil
+	}
+	directories, leaves, err := d.LookupAllChildren()
+	if err != nil {
+		return util.StatusWrapf(err, "Failed to look up children of directory %#v", dPath.String())
+	}
+	dm.progress.DirectoriesTraversed++
+	dm.progress.FilesTraversed += uint64(len(leaves))
+	if dm.progress.DirectoriesTraversed%materializeProgressInterval == 0 {
+		if err := dm.reportProgress(); err != nil {
+			return err
+		}
+	}
+	if dm.maximumDepth > 0 && depth >= dm.maximumDepth {
+		dm.progress.DirectoriesBeyondMaximumDepth += uint64(len(directories))
+		return nil
+	}
+	for _, entry := range directories {
+		if err := dm.materializeDirectory(entry.Child, dPath.Append(entry.Name), depth+1); err != nil {
+			return err
+		}
+		if dm.progress.BudgetExhausted {
+			return nil
+		}
+	}
+	return nil
+}
+
+// Materialize loads the contents of all directories underneath a
+// directory in the output path of a running build. As directories
+// backed by Tree objects are loaded when their children are looked up,
+// this is achieved by calling LookupAllChildren() recursively. If the
+// client provides a budget, traversal stops once it is exhausted.
+// Traversal may also be limited to a maximum depth.
+func (d *RemoteOutputServiceDirectory) Materialize(request *outputpaths.MaterializeRequest, server outputpaths.OutputPaths_MaterializeServer) error {
+	ctx := server.Context()
+	outputPathState, _, err := d.getOutputPathAndBuildState(request.BuildId)
+	if err != nil {
+		return err
+	}
+	if err := d.authorizeOutputBase(ctx, outputPathState.outputBaseID); err != nil {
+		return err
+	}
+
+	directoryLookup := directoryLookupComponentWalker{
+		stack: util.NewNonEmptyStack[virtual.PrepopulatedDirectory](outputPathState.rootDirectory),
+	}
+	if err := path.Resolve(request.Path, path.NewRelativeScopeWalker(&directoryLookup)); err == syscall.ENOENT {
+		return status.Errorf(codes.NotFound, "Path %#v does not exist", request.Path)
+	} else if err != nil {
+		return util.StatusWrapf(err, "Failed to resolve path %#v", request.Path)
+	}
+
+	if d.startBuildConcurrency.Acquire(ctx, 1) != nil {
+		return util.StatusFromContext(ctx)
+	}
+	defer d.startBuildConcurrency.Release(1)
+
+	budgetCtx := ctx
+	if request.MaximumDuration != nil {
+		if err := request.MaximumDuration.CheckValid(); err != nil {
+			return util.StatusWrapWithCode(err, codes.InvalidArgument, "Invalid maximum duration")
+		}
+		var cancel context.CancelFunc
+		budgetCtx, cancel = d.clock.NewContextWithTimeout(ctx, request.MaximumDuration.AsDuration())
+		defer cancel()
+	}
+	dm := directoryMaterializer{
+		context:             ctx,
+		budgetContext:       budgetCtx,
+		fetchStatistics:     outputPathState.fetchStatistics,
+		bytesFetchedAtStart: outputPathState.fetchStatistics.bytesFetched.Load(),
+		maximumBytesFetched: request.MaximumBytesFetched,
+		maximumDepth:        request.MaximumDepth,
+		send:                server.Send,
+	}
+	if err := dm.materializeDirectory(directoryLookup.stack.Peek(), nil, 0); err != nil {
+		return err
+	}
+	if dm.progress.DirectoriesTraversed%materializeProgressInterval != 0 || dm.progress.BudgetExhausted {
+		return dm.reportProgress()
+	}
+	return nil
+}
+
+// ExportTarball streams a tar archive containing the contents of a
+// directory in the output path of a running build. Like Materialize(),
+// it is subject to the same concurrency limit as filtering the output
+// path during StartBuild(), as it causes all directories to be loaded.
+//
+// The archive is generated while it is being streamed, so that the
+// client applies backpressure. Every response reports the progress of
+// the export.
+func (d *RemoteOutputServiceDirectory) ExportTarball(request *outputpaths.ExportTarballRequest, server outputpaths.OutputPaths_ExportTarballServer) error {
+	ctx := server.Context()
+	outputPathState, _, err := d.getOutputPathAndBuildState(request.BuildId)
+	if err != nil {
+		return err
+	}
+	if err := d.authorizeOutputBase(ctx, outputPathState.outputBaseID); err != nil {
+		return err
+	}
+	exporter, err := newTarballExporter(ctx, request, func(data []byte, progress *outputpaths.ExportTarballProgress) error {
+		return server.Send(&outputpaths.ExportTarballResponse{
+			Data:     data,
+			Progress: progress,
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	directoryLookup := directoryLookupComponentWalker{
+		stack: util.NewNonEmptyStack[virtual.PrepopulatedDirectory](outputPathState.rootDirectory),
+	}
+	if err := path.Resolve(request.Path, path.NewRelativeScopeWalker(&directoryLookup)); err == syscall.ENOENT {
+		return status.Errorf(codes.NotFound, "Path %#v does not exist", request.Path)
+	} else if err != nil {
+		return util.StatusWrapf(err, "Failed to resolve path %#v", request.Path)
+	}
+
+	if d.startBuildConcurrency.Acquire(ctx, 1) != nil {
+		return util.StatusFromContext(ctx)
+	}
+	defer d.startBuildConcurrency.Release(1)
+
+	if err := exporter.exportDirectory(directoryLookup.stack.Peek(), nil); err != nil {
+		return err
+	}
+	return exporter.finalize()
+}
+
+// RepairOutputPath removes files from the output path of a running
+// build whose contents are no longer present in the Content
+// Addressable Storage. Calls are subject to the same concurrency limit
+// as filtering the output path during StartBuild(), as both generate
+// a large number of FindMissingBlobs() calls.
+func (d *RemoteOutputServiceDirectory) RepairOutputPath(ctx context.Context, request *outputpaths.RepairOutputPathRequest) (*outputpaths.RepairOutputPathResponse, error) {
+	outputPathState, buildState, err := d.getOutputPathAndBuildState(request.BuildId)
+	if err != nil {
+		return nil, err
+	}
+	if err := d.authorizeOutputBase(ctx, outputPathState.outputBaseID); err != nil {
+		return nil, err
+	}
+	if err := d.checkBuildNotSealed(buildState); err != nil {
+		return nil, err
+	}
+
+	if d.startBuildConcurrency.Acquire(ctx, 1) != nil {
+		return nil, util.StatusFromContext(ctx)
 	}
-	return path.GotSymlink{
-		Parent: cw,
-		Target: target,
+	defer d.startBuildConcurrency.Release(1)
+
+	repairer := newOutputPathRepairer(ctx, outputPathState.bareContentAddressableStorage, d.findMissingBlobsBatchSize)
+	if err := repairer.repairDirectory(outputPathState.rootDirectory, nil); err != nil {
+		return nil, err
+	}
+	if err := repairer.flush(); err != nil {
+		return nil, err
+	}
+	return &outputpaths.RepairOutputPathResponse{
+		RemovedPaths: repairer.getRemovedPaths(),
+	}, nil
+}
+
+// SetOutputPath replaces the contents of the output path of a running
+// build with the contents of a Tree object. As the root directory of
+// the output path cannot be loaded lazily, the root directory of the
+// Tree is fetched up front. This ensures that the existing contents
+// are left intact if the Tree cannot be loaded.
+//
+// If overlay is set, the existing contents are retained, and the
+// contents of the Tree are only added where no entry exists yet.
+func (d *RemoteOutputServiceDirectory) SetOutputPath(ctx context.Context, request *outputpaths.SetOutputPathRequest) (*emptypb.Empty, error) {
+	outputPathState, buildState, err := d.getOutputPathAndBuildState(request.BuildId)
+	if err != nil {
+		return nil, err
+	}
+	if err := d.authorizeOutputBase(ctx, outputPathState.outputBaseID); err != nil {
+		return nil, err
+	}
+
+	if err := d.checkBuildNotSealed(buildState); err != nil {
+		return nil, err
+	}
+	if err := d.checkFinalizedSubtrees(buildState, ""); err != nil {
+		return nil, err
+	}
+	rootTreeDigest, err := newBatchCreateDigest(buildState.digestFunction, request.RootTreeDigest)
+	if err != nil {
+		return nil, util.StatusWrap(err, "Invalid root tree digest")
+	}
+	if sizeBytes := rootTreeDigest.GetSizeBytes(); sizeBytes > d.maximumTreeSizeBytes {
+		return nil, status.Errorf(codes.InvalidArgument, "Tree is %d bytes in size, which exceeds the permitted maximum of %d bytes", sizeBytes, d.maximumTreeSizeBytes)
+	}
+	if request.Overlay {
+		err = d.overlayOutputPathContents(outputPathState, buildState, rootTreeDigest)
+	} else {
+		err = d.replaceOutputPathContents(outputPathState, buildState, rootTreeDigest)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// fetchTreeRootChildren loads the root directory of a Tree object,
+// returning its children. Subdirectories are loaded lazily.
+func (d *RemoteOutputServiceDirectory) fetchTreeRootChildren(outputPathState *outputPathState, buildState *buildState, rootTreeDigest digest.Digest) (map[path.Component]virtual.InitialNode, error) {
+	// Subdirectories are loaded after this call completes, meaning
+	// they cannot use the context of the request.
+	children, err := virtual.NewCASInitialContentsFetcher(
+		context.Background(),
+		cd_cas.NewTreeDirectoryWalker(outputPathState.directoryFetcher, rootTreeDigest),
+		outputPathState.casFileFactory,
+		d.symlinkFactory,
+		buildState.digestFunction,
+	).FetchContents(func(name path.Component) virtual.FileReadMonitor { return nil })
+	if err != nil {
+		return nil, util.StatusWrap(err, "Failed to load root directory of tree")
+	}
+	return children, nil
+}
+
+// replaceOutputPathContents replaces the contents of an output path
+// with the contents of a Tree object.
+func (d *RemoteOutputServiceDirectory) replaceOutputPathContents(outputPathState *outputPathState, buildState *buildState, rootTreeDigest digest.Digest) error {
+	children, err := d.fetchTreeRootChildren(outputPathState, buildState, rootTreeDigest)
+	if err != nil {
+		return err
+	}
+
+	rootDirectory := outputPathState.rootDirectory
+	if err := rootDirectory.RemoveAllChildren(false); err != nil {
+		unlinkInitialNodes(children)
+		return util.StatusWrap(err, "Failed to remove existing contents of output path")
+	}
+	if err := rootDirectory.CreateChildren(children, false); err != nil {
+		unlinkInitialNodes(children)
+		return util.StatusWrap(err, "Failed to create contents of tree")
+	}
+	return nil
+}
+
+// overlayOutputPathContents adds the contents of a Tree object to an
+// output path, without replacing any of its existing contents.
+func (d *RemoteOutputServiceDirectory) overlayOutputPathContents(outputPathState *outputPathState, buildState *buildState, rootTreeDigest digest.Digest) error {
+	children, err := d.fetchTreeRootChildren(outputPathState, buildState, rootTreeDigest)
+	if err != nil {
+		return err
+	}
+	return overlayInitialNodes(outputPathState.rootDirectory, children)
+}
+
+// overlayInitialNodes adds children to a directory for which no entry
+// with the same name exists. Directories that exist on both sides are
+// merged recursively. Children that are not added are unlinked.
+func overlayInitialNodes(directory virtual.PrepopulatedDirectory, children map[path.Component]virtual.InitialNode) error {
+	newChildren := make(map[path.Component]virtual.InitialNode, len(children))
+	unlinkAll := func() {
+		unlinkInitialNodes(children)
+		unlinkInitialNodes(newChildren)
+	}
+	for name, child := range children {
+		delete(children, name)
+		existingChild, err := directory.LookupChild(name)
+		if err == syscall.ENOENT {
+			newChildren[name] = child
+			continue
+		} else if err != nil {
+			unlinkInitialNodes(map[path.Component]virtual.InitialNode{name: child})
+			unlinkAll()
+			return util.StatusWrapf(err, "Failed to look up %#v", name.String())
+		}
+
+		baseDirectory, baseLeaf := child.GetPair()
+		existingDirectory, _ := existingChild.GetPair()
+		if baseLeaf != nil {
+			// The existing entry takes precedence.
+			baseLeaf.Unlink()
+			continue
+		}
+		if existingDirectory == nil {
+			continue
+		}
+		grandchildren, err := baseDirectory.FetchContents(func(name path.Component) virtual.FileReadMonitor { return nil })
+		if err != nil {
+			unlinkAll()
+			return util.StatusWrapf(err, "Failed to load directory %#v", name.String())
+		}
+		if err := overlayInitialNodes(existingDirectory, grandchildren); err != nil {
+			unlinkAll()
+			return util.StatusWrapf(err, "Failed to overlay directory %#v", name.String())
+		}
+	}
+	if err := directory.CreateChildren(newChildren, false); err != nil {
+		unlinkInitialNodes(newChildren)
+		return util.StatusWrap(err, "Failed to create contents of tree")
+	}
+	return nil
+}
+
+// CloneOutputPath replaces the contents of the output path of a
+// running build with the contents of the output path of another output
+// base. This is done by exporting the source output path to a Tree,
+// and loading it in the same way as SetOutputPath(). As the build has
+// already been started, the resulting contents are filtered in the
+// same way as StartBuild() does.
+//
+// Files backed by the Content Addressable Storage are exported using
+// their existing digests. The source output path must therefore have
+// last been used with the same digest function and instance name as
+// the build. Otherwise none of its files would be usable.
+func (d *RemoteOutputServiceDirectory) CloneOutputPath(ctx context.Context, request *outputpaths.CloneOutputPathRequest) (*outputpaths.CloneOutputPathResponse, error) {
+	outputPathState, buildState, err := d.getOutputPathAndBuildState(request.BuildId)
+	if err != nil {
+		return nil, err
+	}
+	if err := d.authorizeOutputBase(ctx, outputPathState.outputBaseID); err != nil {
+		return nil, err
+	}
+	if err := d.checkBuildNotSealed(buildState); err != nil {
+		return nil, err
+	}
+	sourceOutputBaseID, err := newOutputBasePath(request.SourceOutputBaseId)
+	if err != nil {
+		return nil, util.StatusWrap(err, "Invalid source output base ID")
+	}
+	if err := d.authorizeOutputBase(ctx, sourceOutputBaseID); err != nil {
+		return nil, util.StatusWrap(err, "Source output base ID")
+	}
+
+	d.lock.Lock()
+	sourceOutputPathState, ok := d.outputBaseIDs[sourceOutputBaseID]
+	var sourceDigestFunction *digest.Function
+	if ok {
+		sourceDigestFunction = sourceOutputPathState.lastDigestFunction
+	}
+	d.lock.Unlock()
+	if !ok {
+		return nil, status.Error(codes.NotFound, "Source output base ID is not associated with any output path")
+	}
+	if sourceOutputPathState == outputPathState {
+		return nil, status.Error(codes.InvalidArgument, "Source output base ID is the output base ID of the build")
+	}
+	if sourceDigestFunction != nil {
+		if sourceInstanceName, instanceName := sourceDigestFunction.GetInstanceName(), buildState.digestFunction.GetInstanceName(); sourceInstanceName != instanceName {
+			return nil, status.Errorf(codes.FailedPrecondition, "Source output base was last used with instance name %#v, while this build uses instance name %#v", sourceInstanceName.String(), instanceName.String())
+		}
+		if sourceValue, value := sourceDigestFunction.GetEnumValue(), buildState.digestFunction.GetEnumValue(); sourceValue != value {
+			return nil, status.Errorf(codes.FailedPrecondition, "Source output base was last used with digest function %s, while this build uses digest function %s", sourceValue, value)
+		}
+	}
+	if err := d.checkFinalizedSubtrees(buildState, ""); err != nil {
+		return nil, err
+	}
+
+	// Exporting the source output path requires loading all of its
+	// directories, which is as expensive as filtering it.
+	if d.startBuildConcurrency.Acquire(ctx, 1) != nil {
+		return nil, util.StatusFromContext(ctx)
+	}
+	rootTreeDigest, err := newTreeExporter(ctx, outputPathState.bareContentAddressableStorage, buildState.digestFunction, d.exportUploadConcurrency).
+		exportTree(sourceOutputPathState.rootDirectory, nil)
+	d.startBuildConcurrency.Release(1)
+	if err != nil {
+		return nil, util.StatusWrap(err, "Failed to export contents of source output path")
+	}
+
+	if err := d.replaceOutputPathContents(outputPathState, buildState, rootTreeDigest); err != nil {
+		return nil, err
+	}
+	if err := d.filterMissingChildren(ctx, outputPathState, buildState.id, buildState.digestFunction, &startBuildProgress{}); err != nil {
+		return nil, util.StatusWrap(err, "Failed to filter contents of the output path")
+	}
+	return &outputpaths.CloneOutputPathResponse{
+		RootTreeDigest: rootTreeDigest.GetProto(),
+	}, nil
+}
+
+// unlinkInitialNodes releases the leaves contained in a set of initial
+// nodes that could not be added to a directory.
+func unlinkInitialNodes(children map[path.Component]virtual.InitialNode) {
+	for _, child := range children {
+		if _, leaf := child.GetPair(); leaf != nil {
+			leaf.Unlink()
+		}
+	}
+}
+
+// GetOutputPathErrors returns errors that were encountered while
+// accessing the contents of an output path, and clears them.
+func (d *RemoteOutputServiceDirectory) GetOutputPathErrors(ctx context.Context, request *outputpaths.GetOutputPathErrorsRequest) (*outputpaths.GetOutputPathErrorsResponse, error) {
+	outputBaseID, err := newOutputBasePath(request.OutputBaseId)
+	if err != nil {
+		return nil, err
+	}
+	if err := d.authorizeOutputBase(ctx, outputBaseID); err != nil {
+		return nil, err
+	}
+
+	d.lock.Lock()
+	outputPathState, ok := d.outputBaseIDs[outputBaseID]
+	d.lock.Unlock()
+	if !ok {
+		return nil, status.Error(codes.NotFound, "Output base ID is not associated with any output path")
+	}
+	return outputPathState.errorLogger.drain(), nil
+}
+
+// GetOutputPathStats returns statistics on the latency of fetches
+// against the Content Addressable Storage that were performed on
+// behalf of an output path, and when the contents of the output path
+// were last validated.
+func (d *RemoteOutputServiceDirectory) GetOutputPathStats(ctx context.Context, request *outputpaths.GetOutputPathStatsRequest) (*outputpaths.GetOutputPathStatsResponse, error) {
+	outputBaseID, err := newOutputBasePath(request.OutputBaseId)
+	if err != nil {
+		return nil, err
+	}
+	if err := d.authorizeOutputBase(ctx, outputBaseID); err != nil {
+		return nil, err
+	}
+
+	d.lock.Lock()
+	outputPathState, ok := d.outputBaseIDs[outputBaseID]
+	var lastValidationTime time.Time
+	if ok {
+		lastValidationTime = outputPathState.lastValidationTime
+	}
+	d.lock.Unlock()
+	if !ok {
+		return nil, status.Error(codes.NotFound, "Output base ID is not associated with any output path")
+	}
+	response := outputPathState.fetchStatistics.getProto()
+	if !lastValidationTime.IsZero() {
+		response.LastValidationTime = timestamppb.New(lastValidationTime)
+	}
+	return response, nil
+}
+
+// GetOutputPathDigestFunctions reports the distinct instance names and
+// digest functions of the objects referenced by an output path. Like
+// filterMissingChildren(), it obtains the transitive closure of digests
+// of all files and directories in the output path.
+func (d *RemoteOutputServiceDirectory) GetOutputPathDigestFunctions(ctx context.Context, request *outputpaths.GetOutputPathDigestFunctionsRequest) (*outputpaths.GetOutputPathDigestFunctionsResponse, error) {
+	outputBaseID, err := newOutputBasePath(request.OutputBaseId)
+	if err != nil {
+		return nil, err
+	}
+	if err := d.authorizeOutputBase(ctx, outputBaseID); err != nil {
+		return nil, err
+	}
+
+	d.lock.Lock()
+	outputPathState, ok := d.outputBaseIDs[outputBaseID]
+	d.lock.Unlock()
+	if !ok {
+		return nil, status.Error(codes.NotFound, "Output base ID is not associated with any output path")
+	}
+
+	type digestFunctionKey struct {
+		instanceName   string
+		digestFunction remoteexecution.DigestFunction_Value
+	}
+	objects := map[digestFunctionKey]map[digest.Digest]struct{}{}
+	var savedErr error
+	if err := outputPathState.rootDirectory.FilterChildren(func(node virtual.InitialNode, removeFunc virtual.ChildRemover) bool {
+		var digests digest.Set
+		if directory, leaf := node.GetPair(); leaf != nil {
+			digests = leaf.GetContainingDigests()
+		} else if digests, savedErr = directory.GetContainingDigests(ctx); savedErr != nil {
+			return false
+		}
+		for _, blobDigest := range digests.Items() {
+			key := digestFunctionKey{
+				instanceName:   blobDigest.GetInstanceName().String(),
+				digestFunction: blobDigest.GetDigestFunction().GetEnumValue(),
+			}
+			if objects[key] == nil {
+				objects[key] = map[digest.Digest]struct{}{}
+			}
+			objects[key][blobDigest] = struct{}{}
+		}
+		return true
+	}); err != nil {
+		return nil, util.StatusWrap(err, "Failed to traverse the output path")
+	}
+	if savedErr != nil {
+		return nil, util.StatusWrap(savedErr, "Failed to obtain the digests of a directory in the output path")
+	}
+
+	digestFunctions := make([]*outputpaths.DigestFunctionUsage, 0, len(objects))
+	for key, digests := range objects {
+		digestFunctions = append(digestFunctions, &outputpaths.DigestFunctionUsage{
+			InstanceName:   key.instanceName,
+			DigestFunction: key.digestFunction,
+			Objects:        uint64(len(digests)),
+		})
+	}
+	sort.Slice(digestFunctions, func(i, j int) bool {
+		if digestFunctions[i].InstanceName != digestFunctions[j].InstanceName {
+			return digestFunctions[i].InstanceName < digestFunctions[j].InstanceName
+		}
+		return digestFunctions[i].DigestFunction < digestFunctions[j].DigestFunction
+	})
+	return &outputpaths.GetOutputPathDigestFunctionsResponse{
+		DigestFunctions: digestFunctions,
+	}, nil
+}
+
+// GetDaemonStats returns statistics that are aggregated across all
+// output paths. These are maintained incrementally, so that calling
+// this method does not require traversing the output paths.
+func (d *RemoteOutputServiceDirectory) GetDaemonStats(ctx context.Context, request *emptypb.Empty) (*outputpaths.GetDaemonStatsResponse, error) {
+	d.lock.Lock()
+	outputPaths := len(d.outputBaseIDs)
+	activeBuilds := len(d.buildIDs)
+	d.lock.Unlock()
+
+	return &outputpaths.GetDaemonStatsResponse{
+		OutputPaths:      uint32(outputPaths),
+		ActiveBuilds:     uint32(activeBuilds),
+		CasFiles:         uint64(d.casFiles.Load()),
+		Goroutines:       uint32(runtime.NumGoroutine()),
+		BlobFetchLatency: d.aggregateFetchStatistics.blob.getProto(),
+		TreeFetchLatency: d.aggregateFetchStatistics.tree.getProto(),
+	}, nil
+}
+
+// ListOutputPaths returns the output paths that are currently exposed,
+// together with the inode numbers of their root directories.
+//
+// If a timestamp is provided, only output paths that were created or
+// had a build started against them after that time are returned,
+// sorted by the time of this change. This permits clients to poll for
+// changes, using the time of the last output path returned as the
+// timestamp of the next call.
+func (d *RemoteOutputServiceDirectory) ListOutputPaths(ctx context.Context, request *outputpaths.ListOutputPathsRequest) (*outputpaths.ListOutputPathsResponse, error) {
+	var since time.Time
+	useSince := request.Since != nil
+	if useSince {
+		if err := request.Since.CheckValid(); err != nil {
+			return nil, util.StatusWrapWithCode(err, codes.InvalidArgument, "Invalid timestamp")
+		}
+		since = request.Since.AsTime()
+	}
+
+	type listedOutputPath struct {
+		outputPath *outputpaths.ListOutputPathsResponse_OutputPath
+		changeTime time.Time
+	}
+	d.lock.Lock()
+	listedOutputPaths := make([]listedOutputPath, 0, len(d.outputBaseIDs))
+	for outputBaseID, outputPathState := range d.outputBaseIDs {
+		changeTime := outputPathState.creationTime
+		if outputPathState.lastBuildStartTime.After(changeTime) {
+			changeTime = outputPathState.lastBuildStartTime
+		}
+		if useSince && !changeTime.After(since) {
+			continue
+		}
+
+		var attributes virtual.Attributes
+		outputPathState.rootDirectory.VirtualGetAttributes(ctx, virtual.AttributesMaskInodeNumber, &attributes)
+		outputPath := &outputpaths.ListOutputPathsResponse_OutputPath{
+			OutputBaseId:             outputBaseID.String(),
+			RootDirectoryInodeNumber: attributes.GetInodeNumber(),
+			CreationTime:             timestamppb.New(outputPathState.creationTime),
+		}
+		if buildState := outputPathState.buildState; buildState != nil {
+			outputPath.ActiveBuildId = buildState.id
+		}
+		if !outputPathState.lastBuildStartTime.IsZero() {
+			outputPath.LastBuildStartTime = timestamppb.New(outputPathState.lastBuildStartTime)
+		}
+		listedOutputPaths = append(listedOutputPaths, listedOutputPath{
+			outputPath: outputPath,
+			changeTime: changeTime,
+		})
+	}
+	d.lock.Unlock()
+
+	sort.Slice(listedOutputPaths, func(i, j int) bool {
+		oi, oj := &listedOutputPaths[i], &listedOutputPaths[j]
+		if useSince && !oi.changeTime.Equal(oj.changeTime) {
+			return oi.changeTime.Before(oj.changeTime)
+		}
+		return oi.outputPath.OutputBaseId < oj.outputPath.OutputBaseId
+	})
+	outputPaths := make([]*outputpaths.ListOutputPathsResponse_OutputPath, 0, len(listedOutputPaths))
+	for _, listedOutputPath := range listedOutputPaths {
+		outputPaths = append(outputPaths, listedOutputPath.outputPath)
+	}
+	return &outputpaths.ListOutputPathsResponse{
+		OutputPaths: outputPaths,
 	}, nil
 }
 
-func (cw *statWalker) OnTerminal(name path.Component) (*path.GotSymlink, error) {
-	child, err := cw.stack.Peek().LookupChild(name)
+// ExportTree can be called by a build client to convert the contents of
+// a directory in the output path to an REv2 Tree message, which is
+// uploaded to the Content Addressable Storage.
+func (d *RemoteOutputServiceDirectory) ExportTree(ctx context.Context, request *outputpaths.ExportTreeRequest) (*outputpaths.ExportTreeResponse, error) {
+	outputPathState, buildState, err := d.getOutputPathAndBuildState(request.BuildId)
 	if err != nil {
 		return nil, err
 	}
+	if err := d.authorizeOutputBase(ctx, outputPathState.outputBaseID); err != nil {
+		return nil, err
+	}
 
-	directory, leaf := child.GetPair()
-	if directory != nil {
-		// Got a directory. The existing FileStatus is sufficient.
-		cw.stack.Push(directory)
-		return nil, nil
+	directoryLookup := directoryLookupComponentWalker{
+		stack: util.NewNonEmptyStack[virtual.PrepopulatedDirectory](outputPathState.rootDirectory),
+	}
+	if err := path.Resolve(request.Path, path.NewRelativeScopeWalker(&directoryLookup)); err != nil {
+		return nil, util.StatusWrapf(err, "Failed to resolve path %#v", request.Path)
 	}
 
-	if cw.followSymlinks {
-		target, err := leaf.Readlink()
-		if err == nil {
-			// Got a symbolic link, and we should follow it.
-			cw.fileStatus = &remoteoutputservice.FileStatus{
-				FileType: &remoteoutputservice.FileStatus_External_{},
-			}
-			return &path.GotSymlink{
-				Parent: cw,
-				Target: target,
-			}, nil
-		}
-		if err != syscall.EINVAL {
-			return nil, err
-		}
+	if d.startBuildConcurrency.Acquire(ctx, 1) != nil {
+		return nil, util.StatusFromContext(ctx)
 	}
+	defer d.startBuildConcurrency.Release(1)
 
-	fileStatus, err := leaf.GetOutputServiceFileStatus(cw.digestFunction)
+	treeDigest, err := newTreeExporter(ctx, outputPathState.bareContentAddressableStorage, buildState.digestFunction, d.exportUploadConcurrency).
+		exportTree(directoryLookup.stack.Peek(), nil)
 	if err != nil {
 		return nil, err
 	}
-	cw.fileStatus = fileStatus
-	return nil, nil
-}
-
-func (cw *statWalker) OnUp() (path.ComponentWalker, error) {
-	if _, ok := cw.stack.PopSingle(); !ok {
-		cw.fileStatus = &remoteoutputservice.FileStatus{
-			FileType: &remoteoutputservice.FileStatus_External_{},
-		}
-		return path.VoidComponentWalker, nil
-	}
-	return cw, nil
+	return &outputpaths.ExportTreeResponse{
+		TreeDigest: treeDigest.GetProto(),
+	}, nil
 }
 
-// BatchStat can be called by a build client to obtain the status of
-// files and directories.
+// ReadDirectory returns the contents of a directory in the output path
+// of a running build. This permits clients that only have access to
+// the gRPC socket to enumerate the contents of output paths.
 //
-// Calling this method over gRPC may be far more efficient than
-// obtaining this information through the FUSE file system, as batching
-// significantly reduces the amount of context switching. It also
-// prevents the computation of digests for files for which the digest is
-// already known.
-func (d *RemoteOutputServiceDirectory) BatchStat(ctx context.Context, request *remoteoutputservice.BatchStatRequest) (*remoteoutputservice.BatchStatResponse, error) {
+// Entries are returned in alphabetical order. The page token returned
+// to the client is the name of the last entry in the response. This
+// means that entries that are added or removed in between calls don't
+// cause other entries to be skipped or returned twice.
+func (d *RemoteOutputServiceDirectory) ReadDirectory(ctx context.Context, request *outputpaths.ReadDirectoryRequest) (*outputpaths.ReadDirectoryResponse, error) {
 	outputPathState, buildState, err := d.getOutputPathAndBuildState(request.BuildId)
 	if err != nil {
 		return nil, err
 	}
 
-	response := remoteoutputservice.BatchStatResponse{
-		Responses: make([]*remoteoutputservice.StatResponse, 0, len(request.Paths)),
+	directoryLookup := directoryLookupComponentWalker{
+		stack: util.NewNonEmptyStack[virtual.PrepopulatedDirectory](outputPathState.rootDirectory),
+	}
+	if err := path.Resolve(request.Path, path.NewRelativeScopeWalker(&directoryLookup)); err != nil {
+		return nil, util.StatusWrapf(err, "Failed to resolve path %#v", request.Path)
+	}
+	directories, leaves, err := directoryLookup.stack.Peek().LookupAllChildren()
+	if err != nil {
+		return nil, util.StatusWrapf(err, "Failed to look up children of directory %#v", request.Path)
 	}
-	for _, statPath := range request.Paths {
-		statWalker := statWalker{
-			followSymlinks: request.FollowSymlinks,
-			stack:          util.NewNonEmptyStack[virtual.PrepopulatedDirectory](outputPathState.rootDirectory),
-			fileStatus: &remoteoutputservice.FileStatus{
-				FileType: &remoteoutputservice.FileStatus_External_{},
-			},
-		}
-		if request.IncludeFileDigest {
-			statWalker.digestFunction = &buildState.digestFunction
-		}
 
-		resolvedPath, scopeWalker := path.EmptyBuilder.Join(
-			buildState.scopeWalkerFactory.New(path.NewLoopDetectingScopeWalker(&statWalker)))
-		if err := path.Resolve(statPath, scopeWalker); err == syscall.ENOENT {
-			// Path does not exist.
-			response.Responses = append(response.Responses, &remoteoutputservice.StatResponse{})
-		} else if err != nil {
-			// Some other error occurred.
-			return nil, util.StatusWrapf(err, "Failed to resolve path %#v beyond %#v", statPath, resolvedPath.String())
+	var digestFunction *digest.Function
+	if request.IncludeFileDigest {
+		digestFunction = &buildState.digestFunction
+	}
+
+	// Both directories and leaves are sorted alphabetically. Merge
+	// them, skipping all entries up to and including the page token.
+	var response outputpaths.ReadDirectoryResponse
+	for len(directories) > 0 || len(leaves) > 0 {
+		if request.PageSize > 0 && len(response.Entries) >= int(request.PageSize) {
+			response.NextPageToken = response.Entries[len(response.Entries)-1].Name
+			break
+		}
+		if len(leaves) == 0 || (len(directories) > 0 && directories[0].Name.String() < leaves[0].Name.String()) {
+			entry := directories[0]
+			directories = directories[1:]
+			if name := entry.Name.String(); name > request.PageToken {
+				response.Entries = append(response.Entries, &outputpaths.DirectoryEntry{
+					Name: name,
+					Status: &remoteoutputservice.FileStatus{
+						FileType: &remoteoutputservice.FileStatus_Directory_{
+							Directory: getDirectoryFileStatus(ctx, entry.Child),
+						},
+					},
+				})
+			}
 		} else {
-			switch fileType := statWalker.fileStatus.FileType.(type) {
-			case *remoteoutputservice.FileStatus_Directory_:
-				// For directories we need to provide the last
-				// modification time, as the client uses that to
-				// invalidate cached results.
-				var attributes virtual.Attributes
-				statWalker.stack.Peek().VirtualGetAttributes(ctx, virtual.AttributesMaskLastDataModificationTime, &attributes)
-				lastModifiedTime, ok := attributes.GetLastDataModificationTime()
-				if !ok {
-					panic("Directory did not provide a last data modification time, even though the Remote Output Service protocol requires it")
-				}
-				fileType.Directory = &remoteoutputservice.FileStatus_Directory{
-					LastModifiedTime: timestamppb.New(lastModifiedTime),
-				}
-			case *remoteoutputservice.FileStatus_External_:
-				// Path resolves to a location outside the file
-				// system. Return the resolved path back to the
-				// client, so it can stat() it manually.
-				fileType.External = &remoteoutputservice.FileStatus_External{
-					NextPath: resolvedPath.String(),
+			entry := leaves[0]
+			leaves = leaves[1:]
+			if name := entry.Name.String(); name > request.PageToken {
+				fileStatus, err := entry.Child.GetOutputServiceFileStatus(digestFunction)
+				if err != nil {
+					return nil, util.StatusWrapf(err, "Failed to obtain status of file %#v", name)
 				}
+				response.Entries = append(response.Entries, &outputpaths.DirectoryEntry{
+					Name:   name,
+					Status: fileStatus,
+				})
 			}
-			response.Responses = append(response.Responses, &remoteoutputservice.StatResponse{
-				FileStatus: statWalker.fileStatus,
-			})
 		}
 	}
 	return &response, nil
 }
 
-// FinalizeBuild can be called by a build client to indicate the current
-// build has completed. This prevents successive BatchCreate() and
-// BatchStat() calls from being processed.
-func (d *RemoteOutputServiceDirectory) FinalizeBuild(ctx context.Context, request *remoteoutputservice.FinalizeBuildRequest) (*emptypb.Empty, error) {
+// CreateRootSymlink can be called by a build client to create a
+// symbolic link in the root directory of the Remote Output Service
+// (e.g., "bazel-bin"). The symbolic link belongs to the output base of
+// the build, and is removed when the output base is cleaned.
+func (d *RemoteOutputServiceDirectory) CreateRootSymlink(ctx context.Context, request *outputpaths.CreateRootSymlinkRequest) (*emptypb.Empty, error) {
+	name, ok := path.NewComponent(request.Name)
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "Symbolic link name is not a valid filename: Name %s", getInvalidComponentReason(request.Name))
+	}
+	if name == d.finalizedBuildsDirectoryName {
+		return nil, status.Errorf(codes.InvalidArgument, "Symbolic link name %#v is reserved", name.String())
+	}
+	outputPathState, err := d.authorizeBuild(ctx, request.BuildId)
+	if err != nil {
+		return nil, err
+	}
+	if outputPathState == nil {
+		return nil, status.Error(codes.FailedPrecondition, "Build ID is not associated with any running build")
+	}
+
+	leaf := d.symlinkFactory.LookupSymlink([]byte(request.Target))
+
 	d.lock.Lock()
-	defer d.lock.Unlock()
+	if d.buildIDs[request.BuildId] != outputPathState {
+		d.lock.Unlock()
+		leaf.Unlink()
+		return nil, status.Error(codes.FailedPrecondition, "Build ID is not associated with any running build")
+	}
+	if _, ok := d.outputBaseIDs[outputBasePath{}.append(name)]; ok {
+		d.lock.Unlock()
+		leaf.Unlink()
+		return nil, status.Error(codes.AlreadyExists, "An output path with the same name already exists")
+	}
+	if _, ok := d.outputBaseGroups[outputBasePath{}.append(name)]; ok {
+		d.lock.Unlock()
+		leaf.Unlink()
+		return nil, status.Error(codes.AlreadyExists, "A directory containing output paths with the same name already exists")
+	}
+	oldSymlink, ok := d.rootSymlinks[name]
+	if ok && oldSymlink.outputBaseID != outputPathState.outputBaseID {
+		d.lock.Unlock()
+		leaf.Unlink()
+		return nil, status.Error(codes.AlreadyExists, "A symbolic link with the same name belongs to another output base")
+	}
+	d.rootSymlinks[name] = &rootSymlink{
+		leaf:         leaf,
+		outputBaseID: outputPathState.outputBaseID,
+		cookie:       d.changeID,
+	}
+	d.changeID++
+	d.lock.Unlock()
 
-	// Silently ignore requests for unknown build IDs. This ensures
-	// that FinalizeBuild() remains idempotent.
-	if outputPathState, ok := d.buildIDs[request.BuildId]; ok {
-		buildState := outputPathState.buildState
-		outputPathState.rootDirectory.FinalizeBuild(ctx, buildState.digestFunction)
-		delete(d.buildIDs, buildState.id)
-		outputPathState.buildState = nil
+	if ok {
+		oldSymlink.leaf.Unlink()
+		d.handle.NotifyRemoval(name)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// RemoveRootSymlink can be called by a build client to remove a
+// symbolic link that was previously created using CreateRootSymlink().
+func (d *RemoteOutputServiceDirectory) RemoveRootSymlink(ctx context.Context, request *outputpaths.RemoveRootSymlinkRequest) (*emptypb.Empty, error) {
+	name, ok := path.NewComponent(request.Name)
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "Symbolic link name is not a valid filename: Name %s", getInvalidComponentReason(request.Name))
+	}
+	outputPathState, err := d.authorizeBuild(ctx, request.BuildId)
+	if err != nil {
+		return nil, err
+	}
+	if outputPathState == nil {
+		return nil, status.Error(codes.FailedPrecondition, "Build ID is not associated with any running build")
+	}
+
+	d.lock.Lock()
+	if d.buildIDs[request.BuildId] != outputPathState {
+		d.lock.Unlock()
+		return nil, status.Error(codes.FailedPrecondition, "Build ID is not associated with any running build")
+	}
+	// Silently ignore requests for symbolic links that don't exist,
+	// or belong to another output base. This ensures that
+	// RemoveRootSymlink() remains idempotent.
+	symlink, ok := d.rootSymlinks[name]
+	if !ok || symlink.outputBaseID != outputPathState.outputBaseID {
+		d.lock.Unlock()
+		return &emptypb.Empty{}, nil
 	}
+	delete(d.rootSymlinks, name)
+	d.changeID++
+	d.lock.Unlock()
+
+	symlink.leaf.Unlink()
+	d.handle.NotifyRemoval(name)
 	return &emptypb.Empty{}, nil
 }
 
+// directoryEntry is an entry in the root directory of the Remote
+// Output Service or one of the output base group directories, as
+// returned by VirtualReadDir(). Entries are collected while holding the
+// directory lock, but are reported after the lock is released. This
+// prevents the lock from being held while obtaining the attributes of
+// every output path, which may be slow for large numbers of output
+// bases. Hence getAttributes must acquire the lock if needed.
+type directoryEntry struct {
+	cookie        uint64
+	name          path.Component
+	child         virtual.DirectoryChild
+	getAttributes func(ctx context.Context, requested virtual.AttributesMask, attributes *virtual.Attributes)
+}
+
+// lookupLocked looks up an output path or output base group directory
+// contained in the root directory or one of the output base group
+// directories.
+func (d *RemoteOutputServiceDirectory) lookupLocked(ctx context.Context, id outputBasePath, requested virtual.AttributesMask, out *virtual.Attributes) (virtual.DirectoryChild, bool) {
+	if outputPathState, ok := d.outputBaseIDs[id]; ok {
+		outputPathState.rootDirectory.VirtualGetAttributes(ctx, requested, out)
+		return virtual.DirectoryChild{}.FromDirectory(outputPathState.rootDirectory), true
+	}
+	if group, ok := d.outputBaseGroups[id]; ok {
+		group.getAttributesLocked(requested, out)
+		return virtual.DirectoryChild{}.FromDirectory(group), true
+	}
+	return virtual.DirectoryChild{}, false
+}
+
+// preloadLocked creates an output path that has not been used since
+// startup, but does have state from a previous build. This allows its
+// contents to be inspected.
+//
+// Because persistent state is stored under a flattened name, this can
+// only be done for output paths whose parent directory already
+// exists. Output paths with a multi-component output base ID are thus
+// only preloaded once a build has been run against another output base
+// in the same parent directory.
+func (d *RemoteOutputServiceDirectory) preloadLocked(ctx context.Context, id outputBasePath, requested virtual.AttributesMask, out *virtual.Attributes) (virtual.DirectoryChild, bool) {
+	if d.preloadDigestFunction == nil || !d.outputPathFactory.HasPersistentState(id.getFlattenedName()) {
+		return virtual.DirectoryChild{}, false
+	}
+	outputPathState := d.createOutputPathLocked(id, *d.preloadDigestFunction)
+	outputPathState.rootDirectory.VirtualGetAttributes(ctx, requested, out)
+	return virtual.DirectoryChild{}.FromDirectory(outputPathState.rootDirectory), true
+}
+
+// getDirectoryEntriesLocked returns the output paths and output base
+// group directories contained in the root directory or one of the
+// output base group directories whose cookie is at least firstCookie.
+func (d *RemoteOutputServiceDirectory) getDirectoryEntriesLocked(id outputBasePath, firstCookie uint64) []directoryEntry {
+	entries := make([]directoryEntry, 0, len(d.outputBaseIDs)+len(d.outputBaseGroups))
+	for childID, outputPathState := range d.outputBaseIDs {
+		if parentID, name := childID.getParent(); parentID == id && outputPathState.cookie >= firstCookie {
+			entries = append(entries, directoryEntry{
+				cookie:        outputPathState.cookie,
+				name:          name,
+				child:         virtual.DirectoryChild{}.FromDirectory(outputPathState.rootDirectory),
+				getAttributes: outputPathState.rootDirectory.VirtualGetAttributes,
+			})
+		}
+	}
+	for childID, group := range d.outputBaseGroups {
+		if parentID, name := childID.getParent(); parentID == id && group.cookie >= firstCookie {
+			entries = append(entries, directoryEntry{
+				cookie:        group.cookie,
+				name:          name,
+				child:         virtual.DirectoryChild{}.FromDirectory(group),
+				getAttributes: group.VirtualGetAttributes,
+			})
+		}
+	}
+	return entries
+}
+
+// reportDirectoryEntries reports directory entries in the order in
+// which they were created. By ensuring that cookies are monotonically
+// increasing, partial reads against directories can be performed
+// reliably.
+func reportDirectoryEntries(ctx context.Context, entries []directoryEntry, requested virtual.AttributesMask, reporter virtual.DirectoryEntryReporter) {
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].cookie < entries[j].cookie
+	})
+	for _, entry := range entries {
+		var attributes virtual.Attributes
+		entry.getAttributes(ctx, requested, &attributes)
+		if !reporter.ReportEntry(entry.cookie+1, entry.name, entry.child, &attributes) {
+			break
+		}
+	}
+}
+
 // VirtualGetAttributes returns the attributes of the root directory of
 // the Remote Output Service.
 func (d *RemoteOutputServiceDirectory) VirtualGetAttributes(ctx context.Context, requested virtual.AttributesMask, attributes *virtual.Attributes) {
+	defer d.virtualOperationTimers.getAttributes.observe(d.clock.Now())
+
 	attributes.SetFileType(filesystem.FileTypeDirectory)
-	attributes.SetPermissions(virtual.PermissionsRead | virtual.PermissionsExecute)
+	// Write permissions are needed to permit VirtualMkdir().
+	attributes.SetPermissions(virtual.PermissionsRead | virtual.PermissionsWrite | virtual.PermissionsExecute)
 	attributes.SetSizeBytes(0)
 	if requested&(virtual.AttributesMaskChangeID|virtual.AttributesMaskLinkCount) != 0 {
 		d.lock.Lock()
 		attributes.SetChangeID(d.changeID)
-		attributes.SetLinkCount(virtual.EmptyDirectoryLinkCount + uint32(len(d.outputBaseIDs)))
+		linkCount := virtual.EmptyDirectoryLinkCount + uint32(d.countChildrenLocked(outputBasePath{}))
+		if d.finalizedBuildsDirectory != nil {
+			linkCount++
+		}
+		attributes.SetLinkCount(linkCount)
 		d.lock.Unlock()
 	}
 	d.handle.GetAttributes(requested, attributes)
 }
 
 // VirtualLookup can be used to look up the root directory of an output
-// path for a given output base.
+// path for a given output base, a directory containing output paths
+// whose output base IDs consist of multiple components, a symbolic
+// link created using CreateRootSymlink(), or the directory containing
+// retained finalized builds.
+//
+// TODO: Tools tend to probe for output bases that don't exist. It
+// would be beneficial if lookups yielding ENOENT could be cached by the
+// kernel (i.e., FUSE negative dentry caching). This requires support
+// from the FUSE server in bb-remote-execution, as the Directory
+// interface provides no way to return a cache timeout. Once added,
+// StartBuild() must call NotifyRemoval() for newly created output
+// bases, so that negative entries are invalidated.
 func (d *RemoteOutputServiceDirectory) VirtualLookup(ctx context.Context, name path.Component, requested virtual.AttributesMask, out *virtual.Attributes) (virtual.DirectoryChild, virtual.Status) {
+	defer d.virtualOperationTimers.lookup.observe(d.clock.Now())
+
 	d.lock.Lock()
-	outputPathState, ok := d.outputBaseIDs[name]
-	d.lock.Unlock()
-	if !ok {
-		return virtual.DirectoryChild{}, virtual.StatusErrNoEnt
+	defer d.lock.Unlock()
+
+	if fd := d.finalizedBuildsDirectory; fd != nil && name == d.finalizedBuildsDirectoryName {
+		fd.getAttributesLocked(requested, out)
+		return virtual.DirectoryChild{}.FromDirectory(fd), virtual.StatusOK
 	}
-	outputPathState.rootDirectory.VirtualGetAttributes(ctx, requested, out)
-	return virtual.DirectoryChild{}.FromDirectory(outputPathState.rootDirectory), virtual.StatusOK
+
+	id := outputBasePath{}.append(name)
+	if child, ok := d.lookupLocked(ctx, id, requested, out); ok {
+		return child, virtual.StatusOK
+	}
+	if symlink, ok := d.rootSymlinks[name]; ok {
+		symlink.leaf.VirtualGetAttributes(ctx, requested, out)
+		return virtual.DirectoryChild{}.FromLeaf(symlink.leaf), virtual.StatusOK
+	}
+	if child, ok := d.preloadLocked(ctx, id, requested, out); ok {
+		return child, virtual.StatusOK
+	}
+	return virtual.DirectoryChild{}, virtual.StatusErrNoEnt
+}
+
+// VirtualMkdir creates an empty output path for an output base,
+// without starting a build. This allows tools to create the output
+// path before the first build against the output base is run. The
+// first call to StartBuild() for the output base adopts the output
+// path, as opposed to replacing it.
+//
+// As the instance name and digest function of the next build are not
+// known at this point, the ones that are used for preloading are used.
+// If preloading is not enabled, the empty instance name and SHA-256 are
+// used. Like any other output path, files that use a different
+// instance name or digest function than the one passed to StartBuild()
+// are removed from the output path when the build is started.
+func (d *RemoteOutputServiceDirectory) VirtualMkdir(name path.Component, requested virtual.AttributesMask, out *virtual.Attributes) (virtual.Directory, virtual.ChangeInfo, virtual.Status) {
+	id := outputBasePath{}.append(name)
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	if _, ok := d.outputBaseIDs[id]; ok {
+		return nil, virtual.ChangeInfo{}, virtual.StatusErrExist
+	}
+	if _, ok := d.outputBaseGroups[id]; ok {
+		return nil, virtual.ChangeInfo{}, virtual.StatusErrExist
+	}
+	if _, ok := d.rootSymlinks[name]; ok {
+		return nil, virtual.ChangeInfo{}, virtual.StatusErrExist
+	}
+	if name == d.finalizedBuildsDirectoryName {
+		if d.finalizedBuildsDirectory != nil {
+			return nil, virtual.ChangeInfo{}, virtual.StatusErrExist
+		}
+		return nil, virtual.ChangeInfo{}, virtual.StatusErrAccess
+	}
+
+	digestFunction := precreatedOutputPathDigestFunction
+	if d.preloadDigestFunction != nil {
+		digestFunction = *d.preloadDigestFunction
+	}
+	changeIDBefore := d.changeID
+	outputPathState := d.createOutputPathLocked(id, digestFunction)
+	outputPathState.rootDirectory.VirtualGetAttributes(context.Background(), requested, out)
+	return outputPathState.rootDirectory, virtual.ChangeInfo{
+		Before: changeIDBefore,
+		After:  d.changeID,
+	}, virtual.StatusOK
 }
 
 // VirtualOpenChild can be used to open or create a file in the root
-// directory of the Remote Output Service. Because this directory never
-// contains any files, this function is guaranteed to fail.
+// directory of the Remote Output Service. Because this directory only
+// contains directories and symbolic links, this function is guaranteed to
+// fail.
 func (d *RemoteOutputServiceDirectory) VirtualOpenChild(ctx context.Context, name path.Component, shareAccess virtual.ShareMask, createAttributes *virtual.Attributes, existingOptions *virtual.OpenExistingOptions, requested virtual.AttributesMask, openedFileAttributes *virtual.Attributes) (virtual.Leaf, virtual.AttributesMask, virtual.ChangeInfo, virtual.Status) {
+	defer d.virtualOperationTimers.openChild.observe(d.clock.Now())
+
+	id := outputBasePath{}.append(name)
 	d.lock.Lock()
-	_, ok := d.outputBaseIDs[name]
+	_, isOutputPath := d.outputBaseIDs[id]
+	_, isGroup := d.outputBaseGroups[id]
+	_, isSymlink := d.rootSymlinks[name]
 	d.lock.Unlock()
-	if ok {
+	isFinalizedBuilds := d.finalizedBuildsDirectory != nil && name == d.finalizedBuildsDirectoryName
+	if isOutputPath || isGroup || isFinalizedBuilds {
 		return virtual.ReadOnlyDirectoryOpenChildWrongFileType(existingOptions, virtual.StatusErrIsDir)
 	}
+	if isSymlink {
+		return virtual.ReadOnlyDirectoryOpenChildWrongFileType(existingOptions, virtual.StatusErrSymlink)
+	}
 	return virtual.ReadOnlyDirectoryOpenChildDoesntExist(createAttributes)
 }
 
 // VirtualReadDir returns a list of all the output paths managed by this
-// Remote Output Service.
+// Remote Output Service, the directories containing output paths whose
+// output base IDs consist of multiple components, the symbolic links
+// created using CreateRootSymlink(), and the directory containing
+// retained finalized builds.
 func (d *RemoteOutputServiceDirectory) VirtualReadDir(ctx context.Context, firstCookie uint64, requested virtual.AttributesMask, reporter virtual.DirectoryEntryReporter) virtual.Status {
+	defer d.virtualOperationTimers.readDir.observe(d.clock.Now())
+
+	d.lock.Lock()
+	entries := d.getDirectoryEntriesLocked(outputBasePath{}, firstCookie)
+	for name, symlink := range d.rootSymlinks {
+		if symlink.cookie >= firstCookie {
+			entries = append(entries, directoryEntry{
+				cookie:        symlink.cookie,
+				name:          name,
+				child:         virtual.DirectoryChild{}.FromLeaf(symlink.leaf),
+				getAttributes: symlink.leaf.VirtualGetAttributes,
+			})
+		}
+	}
+	if fd := d.finalizedBuildsDirectory; fd != nil && fd.cookie >= firstCookie {
+		entries = append(entries, directoryEntry{
+			cookie:        fd.cookie,
+			name:          d.finalizedBuildsDirectoryName,
+			child:         virtual.DirectoryChild{}.FromDirectory(fd),
+			getAttributes: fd.VirtualGetAttributes,
+		})
+	}
+	d.lock.Unlock()
+
+	reportDirectoryEntries(ctx, entries, requested, reporter)
+	return virtual.StatusOK
+}
+
+// outputBaseGroupDirectory is a directory that is created for every
+// proper prefix of output base IDs consisting of multiple components.
+// For example, if a build is started with output base ID
+// "team/project", the output path is exposed as "project" inside
+// output base group directory "team".
+//
+// Output base group directories are created when the first output path
+// inside of them is created, and are removed when the last output path
+// inside of them is cleaned.
+type outputBaseGroupDirectory struct {
+	virtual.ReadOnlyDirectory
+
+	service *RemoteOutputServiceDirectory
+	handle  virtual.StatefulDirectoryHandle
+	id      outputBasePath
+	cookie  uint64
+}
+
+func (g *outputBaseGroupDirectory) getAttributesLocked(requested virtual.AttributesMask, attributes *virtual.Attributes) {
+	attributes.SetFileType(filesystem.FileTypeDirectory)
+	attributes.SetPermissions(virtual.PermissionsRead | virtual.PermissionsExecute)
+	attributes.SetSizeBytes(0)
+	attributes.SetChangeID(g.service.changeID)
+	attributes.SetLinkCount(virtual.EmptyDirectoryLinkCount + uint32(g.service.countChildrenLocked(g.id)))
+	g.handle.GetAttributes(requested, attributes)
+}
+
+func (g *outputBaseGroupDirectory) VirtualGetAttributes(ctx context.Context, requested virtual.AttributesMask, attributes *virtual.Attributes) {
+	g.service.lock.Lock()
+	defer g.service.lock.Unlock()
+
+	g.getAttributesLocked(requested, attributes)
+}
+
+func (g *outputBaseGroupDirectory) VirtualLookup(ctx context.Context, name path.Component, requested virtual.AttributesMask, out *virtual.Attributes) (virtual.DirectoryChild, virtual.Status) {
+	d := g.service
 	d.lock.Lock()
 	defer d.lock.Unlock()
 
-	// Find the first output path past the provided cookie.
-	outputPathState := d.outputPaths.next
-	for {
-		if outputPathState == &d.outputPaths {
-			return virtual.StatusOK
-		}
-		if outputPathState.cookie >= firstCookie {
-			break
+	id := g.id.append(name)
+	if child, ok := d.lookupLocked(ctx, id, requested, out); ok {
+		return child, virtual.StatusOK
+	}
+	if d.outputBaseGroups[g.id] == g {
+		if child, ok := d.preloadLocked(ctx, id, requested, out); ok {
+			return child, virtual.StatusOK
 		}
-		outputPathState = outputPathState.next
 	}
+	return virtual.DirectoryChild{}, virtual.StatusErrNoEnt
+}
 
-	// Return information for the remaining output paths.
-	for ; outputPathState != &d.outputPaths; outputPathState = outputPathState.next {
-		child := outputPathState.rootDirectory
-		var attributes virtual.Attributes
-		child.VirtualGetAttributes(ctx, requested, &attributes)
-		if !reporter.ReportEntry(outputPathState.cookie+1, outputPathState.outputBaseID, virtual.DirectoryChild{}.FromDirectory(child), &attributes) {
-			break
-		}
+func (g *outputBaseGroupDirectory) VirtualOpenChild(ctx context.Context, name path.Component, shareAccess virtual.ShareMask, createAttributes *virtual.Attributes, existingOptions *virtual.OpenExistingOptions, requested virtual.AttributesMask, openedFileAttributes *virtual.Attributes) (virtual.Leaf, virtual.AttributesMask, virtual.ChangeInfo, virtual.Status) {
+	id := g.id.append(name)
+	d := g.service
+	d.lock.Lock()
+	_, isOutputPath := d.outputBaseIDs[id]
+	_, isGroup := d.outputBaseGroups[id]
+	d.lock.Unlock()
+	if isOutputPath || isGroup {
+		return virtual.ReadOnlyDirectoryOpenChildWrongFileType(existingOptions, virtual.StatusErrIsDir)
 	}
+	return virtual.ReadOnlyDirectoryOpenChildDoesntExist(createAttributes)
+}
+
+func (g *outputBaseGroupDirectory) VirtualReadDir(ctx context.Context, firstCookie uint64, requested virtual.AttributesMask, reporter virtual.DirectoryEntryReporter) virtual.Status {
+	d := g.service
+	d.lock.Lock()
+	entries := d.getDirectoryEntriesLocked(g.id, firstCookie)
+	d.lock.Unlock()
+
+	reportDirectoryEntries(ctx, entries, requested, reporter)
 	return virtual.StatusOK
 }
+
+// parentDirectoryLookupComponentWalker is an implementation of
+// ComponentWalker that resolves the directory containing the entry a
+// path refers to. Unlike directoryLookupComponentWalker, the final
+// component of the path is not looked up, meaning the entry itself
+// does not need to exist.
+type parentDirectoryLookupComponentWalker struct {
+	stack util.NonEmptyStack[virtual.PrepopulatedDirectory]
+	name  *path.Component
+}
+
+func (cw *parentDirectoryLookupComponentWalker) OnDirectory(name path.Component) (path.GotDirectoryOrSymlink, error) {
+	child, err := cw.stack.Peek().LookupChild(name)
+	if err != nil {
+		return nil, err
+	}
+	directory, _ := child.GetPair()
+	if directory == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Path component %#v does not resolve to a directory", name.String())
+	}
+	cw.stack.Push(directory)
+	return path.GotDirectory{
+		Child:        cw,
+		IsReversible: true,
+	}, nil
+}
+
+func (cw *parentDirectoryLookupComponentWalker) OnTerminal(name path.Component) (*path.GotSymlink, error) {
+	cw.name = &name
+	return nil, nil
+}
+
+func (cw *parentDirectoryLookupComponentWalker) OnUp() (path.ComponentWalker, error) {
+	if _, ok := cw.stack.PopSingle(); !ok {
+		return nil, status.Error(codes.InvalidArgument, "Path resolves to a location outside the output path")
+	}
+	return cw, nil
+}
+
+// InvalidateCache causes the kernel to drop any state it has cached
+// for a single entry in an output path. This is done by calling
+// NotifyRemoval() against the handle of the directory containing the
+// entry, regardless of whether the entry exists.
+func (d *RemoteOutputServiceDirectory) InvalidateCache(ctx context.Context, request *outputpaths.InvalidateCacheRequest) (*emptypb.Empty, error) {
+	if d.directoryHandleTracker == nil {
+		return nil, status.Error(codes.Unimplemented, "Cache invalidation is not enabled")
+	}
+	outputBaseID, err := newOutputBasePath(request.OutputBaseId)
+	if err != nil {
+		return nil, err
+	}
+
+	d.lock.Lock()
+	outputPathState, ok := d.outputBaseIDs[outputBaseID]
+	d.lock.Unlock()
+	if !ok {
+		return nil, status.Error(codes.NotFound, "Output base ID is not associated with any output path")
+	}
+
+	parentLookup := parentDirectoryLookupComponentWalker{
+		stack: util.NewNonEmptyStack[virtual.PrepopulatedDirectory](outputPathState.rootDirectory),
+	}
+	if err := path.Resolve(request.Path, path.NewRelativeScopeWalker(&parentLookup)); err == syscall.ENOENT {
+		return nil, status.Errorf(codes.NotFound, "Path %#v does not exist", request.Path)
+	} else if err != nil {
+		return nil, util.StatusWrapf(err, "Failed to resolve path %#v", request.Path)
+	}
+	if parentLookup.name == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Path %#v does not refer to an entry within a directory", request.Path)
+	}
+	if !d.directoryHandleTracker.NotifyRemoval(ctx, parentLookup.stack.Peek(), *parentLookup.name) {
+		return nil, status.Errorf(codes.Internal, "No handle is associated with the directory containing path %#v", request.Path)
+	}
+	return &emptypb.Empty{}, nil
+}
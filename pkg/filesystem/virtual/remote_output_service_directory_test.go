@@ -1,7 +1,14 @@
 package virtual_test
 
 import (
+	"archive/tar"
+	"bytes"
 	"context"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
 	"syscall"
 	"testing"
 	"time"
@@ -9,19 +16,66 @@ import (
 	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
 	"github.com/buildbarn/bb-clientd/internal/mock"
 	cd_vfs "github.com/buildbarn/bb-clientd/pkg/filesystem/virtual"
+	cd_testutil "github.com/buildbarn/bb-clientd/pkg/filesystem/virtual/testutil"
+	"github.com/buildbarn/bb-clientd/pkg/proto/outputpaths"
+	re_cas "github.com/buildbarn/bb-remote-execution/pkg/cas"
+	re_filesystem "github.com/buildbarn/bb-remote-execution/pkg/filesystem"
 	re_vfs "github.com/buildbarn/bb-remote-execution/pkg/filesystem/virtual"
 	"github.com/buildbarn/bb-remote-execution/pkg/proto/remoteoutputservice"
+	"github.com/buildbarn/bb-storage/pkg/auth"
+	"github.com/buildbarn/bb-storage/pkg/blobstore"
+	"github.com/buildbarn/bb-storage/pkg/blobstore/buffer"
+	"github.com/buildbarn/bb-storage/pkg/clock"
 	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/filesystem"
 	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+	"github.com/buildbarn/bb-storage/pkg/random"
 	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/buildbarn/bb-storage/pkg/util"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
 
+	"golang.org/x/sync/semaphore"
+	status_pb "google.golang.org/genproto/googleapis/rpc/status"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/emptypb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+// allowAllAuthorizer is used by tests that don't exercise
+// authorization of calls against the Remote Output Service.
+var allowAllAuthorizer = auth.NewStaticAuthorizer(func(digest.InstanceName) bool { return true })
+
+// newTestRemoteOutputServiceDirectory creates a
+// RemoteOutputServiceDirectory for use by tests. Options that are not
+// set by the caller are given values that the tests in this package
+// depend on, as opposed to the defaults used by bb_clientd.
+func newTestRemoteOutputServiceDirectory(handleAllocator re_vfs.StatefulHandleAllocator, outputPathFactory cd_vfs.OutputPathFactory, bareContentAddressableStorage, retryingContentAddressableStorage blobstore.BlobAccess, directoryFetcher re_cas.DirectoryFetcher, symlinkFactory re_vfs.SymlinkFactory, authorizer auth.Authorizer, clock clock.Clock, options cd_vfs.RemoteOutputServiceDirectoryOptions) *cd_vfs.RemoteOutputServiceDirectory {
+	if options.MaximumPathDepth == 0 {
+		options.MaximumPathDepth = 100
+	}
+	if options.FindMissingBlobsBatchSize == 0 {
+		options.FindMissingBlobsBatchSize = 1000
+	}
+	if options.StartBuildConcurrency == nil {
+		options.StartBuildConcurrency = semaphore.NewWeighted(1)
+	}
+	return cd_vfs.NewRemoteOutputServiceDirectory(
+		handleAllocator,
+		outputPathFactory,
+		bareContentAddressableStorage,
+		retryingContentAddressableStorage,
+		directoryFetcher,
+		symlinkFactory,
+		authorizer,
+		/* maximumTreeSizeBytes = */ 10000,
+		clock,
+		options)
+}
+
 func TestRemoteOutputServiceDirectoryClean(t *testing.T) {
 	ctrl, ctx := gomock.WithContext(context.Background(), t)
 
@@ -35,21 +89,25 @@ func TestRemoteOutputServiceDirectoryClean(t *testing.T) {
 	handleAllocator.EXPECT().New().Return(dHandleAllocation)
 	dHandle := mock.NewMockStatefulDirectoryHandle(ctrl)
 	dHandleAllocation.EXPECT().AsStatefulDirectory(gomock.Any()).Return(dHandle)
-	d := cd_vfs.NewRemoteOutputServiceDirectory(
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(1000, 0)).AnyTimes()
+	d := newTestRemoteOutputServiceDirectory(
 		handleAllocator,
 		outputPathFactory,
 		bareContentAddressableStorage,
 		retryingContentAddressableStorage,
 		directoryFetcher,
 		symlinkFactory,
-		/* maximumTreeSizeBytes = */ 10000)
+		allowAllAuthorizer,
+		clock,
+		cd_vfs.RemoteOutputServiceDirectoryOptions{})
 
 	t.Run("InvalidOutputBaseID", func(t *testing.T) {
 		// The output base ID must be a valid directory name.
 		_, err := d.Clean(ctx, &remoteoutputservice.CleanRequest{
 			OutputBaseId: "..",
 		})
-		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Output base ID is not a valid filename"), err)
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Output base ID must consist of one or more valid filenames separated by slashes: Component 1 may not be \"..\""), err)
 	})
 
 	t.Run("NonexistentOutputPath", func(t *testing.T) {
@@ -122,6 +180,139 @@ func TestRemoteOutputServiceDirectoryClean(t *testing.T) {
 	})
 }
 
+func TestRemoteOutputServiceDirectoryCleanStream(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	handleAllocator := mock.NewMockStatefulHandleAllocator(ctrl)
+	outputPathFactory := mock.NewMockOutputPathFactory(ctrl)
+	bareContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	retryingContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	directoryFetcher := mock.NewMockDirectoryFetcher(ctrl)
+	symlinkFactory := mock.NewMockSymlinkFactory(ctrl)
+	dHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	handleAllocator.EXPECT().New().Return(dHandleAllocation)
+	dHandle := mock.NewMockStatefulDirectoryHandle(ctrl)
+	dHandleAllocation.EXPECT().AsStatefulDirectory(gomock.Any()).Return(dHandle)
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(1000, 0)).AnyTimes()
+	d := newTestRemoteOutputServiceDirectory(
+		handleAllocator,
+		outputPathFactory,
+		bareContentAddressableStorage,
+		retryingContentAddressableStorage,
+		directoryFetcher,
+		symlinkFactory,
+		allowAllAuthorizer,
+		clock,
+		cd_vfs.RemoteOutputServiceDirectoryOptions{})
+
+	t.Run("NonexistentOutputPath", func(t *testing.T) {
+		// Persistent state should be removed, and no entries
+		// should be reported as removed.
+		outputPathFactory.EXPECT().Clean(path.MustNewComponent("a448da900e7bd4b025ab91da2aba6244"))
+		server := mock.NewMockOutputPathsCleanStreamServer(ctrl)
+		server.EXPECT().Context().Return(ctx).AnyTimes()
+		server.EXPECT().Send(testutil.EqProto(t, &outputpaths.CleanStreamResponse{}))
+
+		require.NoError(t, d.CleanStream(&remoteoutputservice.CleanRequest{
+			OutputBaseId: "a448da900e7bd4b025ab91da2aba6244",
+		}, server))
+	})
+
+	// Create an output path.
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocator := mock.NewMockStatelessHandleAllocator(ctrl)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(casFileHandleAllocator)
+	outputPath := mock.NewMockOutputPath(ctrl)
+	outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("a448da900e7bd4b025ab91da2aba6244"),
+		gomock.Any(),
+		digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256),
+		gomock.Any(),
+	).Return(outputPath)
+	outputPath.EXPECT().FilterChildren(gomock.Any())
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "a448da900e7bd4b025ab91da2aba6244",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		DigestFunction:   remoteexecution.DigestFunction_SHA256,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+
+	t.Run("Cancelled", func(t *testing.T) {
+		// Cancelling the call should stop the removal of
+		// entries, leaving the output path partially cleaned.
+		cancelledCtx, cancel := context.WithCancel(ctx)
+		cancel()
+		outputPath.EXPECT().FilterChildren(gomock.Any()).DoAndReturn(func(childFilter re_vfs.ChildFilter) error {
+			require.False(t, childFilter(re_vfs.InitialNode{}, func() error { return nil }))
+			return nil
+		})
+		server := mock.NewMockOutputPathsCleanStreamServer(ctrl)
+		server.EXPECT().Context().Return(cancelledCtx).AnyTimes()
+
+		testutil.RequireEqualStatus(t, status.Error(codes.Canceled, "context canceled"), d.CleanStream(&remoteoutputservice.CleanRequest{
+			OutputBaseId: "a448da900e7bd4b025ab91da2aba6244",
+		}, server))
+	})
+
+	t.Run("RemovalFailure", func(t *testing.T) {
+		outputPath.EXPECT().FilterChildren(gomock.Any()).DoAndReturn(func(childFilter re_vfs.ChildFilter) error {
+			require.False(t, childFilter(re_vfs.InitialNode{}, func() error {
+				return status.Error(codes.Internal, "Disk on fire")
+			}))
+			return nil
+		})
+		server := mock.NewMockOutputPathsCleanStreamServer(ctrl)
+		server.EXPECT().Context().Return(ctx).AnyTimes()
+
+		testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Failed to remove entry from output path: Disk on fire"), d.CleanStream(&remoteoutputservice.CleanRequest{
+			OutputBaseId: "a448da900e7bd4b025ab91da2aba6244",
+		}, server))
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		// Progress should be reported periodically, followed by
+		// the total number of entries removed. FilterChildren()
+		// does not report directories whose contents have been
+		// loaded, meaning these should be removed afterwards.
+		outputPath.EXPECT().FilterChildren(gomock.Any()).DoAndReturn(func(childFilter re_vfs.ChildFilter) error {
+			for i := 0; i < 1500; i++ {
+				require.True(t, childFilter(re_vfs.InitialNode{}, func() error { return nil }))
+			}
+			return nil
+		})
+		directory := mock.NewMockPrepopulatedDirectory(ctrl)
+		outputPath.EXPECT().LookupAllChildren().Return([]re_vfs.DirectoryPrepopulatedDirEntry{
+			{Name: path.MustNewComponent("directory"), Child: directory},
+		}, nil, nil)
+		subdirectory := mock.NewMockPrepopulatedDirectory(ctrl)
+		directory.EXPECT().LookupAllChildren().Return([]re_vfs.DirectoryPrepopulatedDirEntry{
+			{Name: path.MustNewComponent("subdirectory"), Child: subdirectory},
+		}, nil, nil)
+		subdirectory.EXPECT().LookupAllChildren()
+		directory.EXPECT().RemoveAll(path.MustNewComponent("subdirectory"))
+		outputPath.EXPECT().RemoveAll(path.MustNewComponent("directory"))
+		outputPath.EXPECT().RemoveAllChildren(true)
+		dHandle.EXPECT().NotifyRemoval(path.MustNewComponent("a448da900e7bd4b025ab91da2aba6244"))
+		server := mock.NewMockOutputPathsCleanStreamServer(ctrl)
+		server.EXPECT().Context().Return(ctx).AnyTimes()
+		gomock.InOrder(
+			server.EXPECT().Send(testutil.EqProto(t, &outputpaths.CleanStreamResponse{
+				EntriesRemoved: 1000,
+			})),
+			server.EXPECT().Send(testutil.EqProto(t, &outputpaths.CleanStreamResponse{
+				EntriesRemoved: 1502,
+			})))
+
+		require.NoError(t, d.CleanStream(&remoteoutputservice.CleanRequest{
+			OutputBaseId: "a448da900e7bd4b025ab91da2aba6244",
+		}, server))
+	})
+}
+
 func TestRemoteOutputServiceDirectoryStartBuild(t *testing.T) {
 	ctrl, ctx := gomock.WithContext(context.Background(), t)
 
@@ -135,14 +326,18 @@ func TestRemoteOutputServiceDirectoryStartBuild(t *testing.T) {
 	handleAllocator.EXPECT().New().Return(dHandleAllocation)
 	dHandle := mock.NewMockStatefulDirectoryHandle(ctrl)
 	dHandleAllocation.EXPECT().AsStatefulDirectory(gomock.Any()).Return(dHandle)
-	d := cd_vfs.NewRemoteOutputServiceDirectory(
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(1000, 0)).AnyTimes()
+	d := newTestRemoteOutputServiceDirectory(
 		handleAllocator,
 		outputPathFactory,
 		bareContentAddressableStorage,
 		retryingContentAddressableStorage,
 		directoryFetcher,
 		symlinkFactory,
-		/* maximumMessageSizeBytes = */ 10000)
+		allowAllAuthorizer,
+		clock,
+		cd_vfs.RemoteOutputServiceDirectoryOptions{})
 
 	t.Run("InvalidOutputBaseID", func(t *testing.T) {
 		// The output base ID must be a valid directory name.
@@ -155,21 +350,55 @@ func TestRemoteOutputServiceDirectoryStartBuild(t *testing.T) {
 				"/home/bob/.cache/bazel/_bazel_bob/a448da900e7bd4b025ab91da2aba6244/execroot/myproject/bazel-out": ".",
 			},
 		})
-		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Output base ID is not a valid filename"), err)
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Output base ID must consist of one or more valid filenames separated by slashes: Component 1 is empty"), err)
 	})
 
 	t.Run("InvalidOutputPathPrefix", func(t *testing.T) {
-		// The output path prefix must be absolute.
-		_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
-			OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
-			BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
-			DigestFunction:   remoteexecution.DigestFunction_SHA256,
-			OutputPathPrefix: "relative/path",
-			OutputPathAliases: map[string]string{
-				"/home/bob/.cache/bazel/_bazel_bob/a448da900e7bd4b025ab91da2aba6244/execroot/myproject/bazel-out": ".",
-			},
+		t.Run("Empty", func(t *testing.T) {
+			// An output path prefix must be provided.
+			_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+				OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+				BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+				DigestFunction:   remoteexecution.DigestFunction_SHA256,
+				OutputPathPrefix: "",
+			})
+			testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "No output path prefix provided"), err)
+		})
+
+		t.Run("Relative", func(t *testing.T) {
+			// The output path prefix must be absolute.
+			_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+				OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+				BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+				DigestFunction:   remoteexecution.DigestFunction_SHA256,
+				OutputPathPrefix: "relative/path",
+			})
+			testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Output path prefix \"relative/path\" is not absolute"), err)
+		})
+
+		t.Run("Windows", func(t *testing.T) {
+			// Windows paths should be reported explicitly, as clients
+			// may have forwarded a path from the host platform.
+			_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+				OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+				BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+				DigestFunction:   remoteexecution.DigestFunction_SHA256,
+				OutputPathPrefix: "C:\\Users\\bob\\outputs",
+			})
+			testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Output path prefix \"C:\\\\Users\\\\bob\\\\outputs\" appears to be a Windows path, while an absolute UNIX-style path was expected"), err)
+		})
+
+		t.Run("InvalidComponent", func(t *testing.T) {
+			// Components of the output path prefix must be valid
+			// filenames.
+			_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+				OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+				BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+				DigestFunction:   remoteexecution.DigestFunction_SHA256,
+				OutputPathPrefix: "/home/bob\x00/outputs",
+			})
+			testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Output path prefix \"/home/bob\\x00/outputs\" is invalid: Component 2 contains a null byte"), err)
 		})
-		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Failed to resolve output path prefix: Path is relative, while an absolute path was expected"), err)
 	})
 
 	t.Run("InvalidOutputPathAliases", func(t *testing.T) {
@@ -186,6 +415,51 @@ func TestRemoteOutputServiceDirectoryStartBuild(t *testing.T) {
 		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Failed to resolve alias path \"relative/path\": Path is relative, while an absolute path was expected"), err)
 	})
 
+	t.Run("OverlappingOutputPathAliases", func(t *testing.T) {
+		// Output path aliases may not be placed at, above or
+		// below the output path, or any other aliases.
+		for _, tc := range []struct {
+			aliases map[string]string
+			err     error
+		}{
+			{
+				aliases: map[string]string{"/home/bob/bb_clientd/outputs/9da951b8cb759233037166e28f7ea186": "."},
+				err:     status.Error(codes.InvalidArgument, "Alias path \"/home/bob/bb_clientd/outputs/9da951b8cb759233037166e28f7ea186\" overlaps with output path \"/home/bob/bb_clientd/outputs/9da951b8cb759233037166e28f7ea186\""),
+			},
+			{
+				aliases: map[string]string{"/home/bob/bb_clientd/outputs/9da951b8cb759233037166e28f7ea186/bazel-out": "."},
+				err:     status.Error(codes.InvalidArgument, "Alias path \"/home/bob/bb_clientd/outputs/9da951b8cb759233037166e28f7ea186/bazel-out\" overlaps with output path \"/home/bob/bb_clientd/outputs/9da951b8cb759233037166e28f7ea186\""),
+			},
+			{
+				aliases: map[string]string{"/home/bob/bb_clientd": "."},
+				err:     status.Error(codes.InvalidArgument, "Alias path \"/home/bob/bb_clientd\" overlaps with output path \"/home/bob/bb_clientd/outputs/9da951b8cb759233037166e28f7ea186\""),
+			},
+			{
+				aliases: map[string]string{
+					"/home/bob/execroot/bazel-out":     "bazel-out",
+					"/home/bob/execroot/bazel-out/bin": "bazel-out/k8-fastbuild/bin",
+				},
+				err: status.Error(codes.InvalidArgument, "Alias paths \"/home/bob/execroot/bazel-out\" and \"/home/bob/execroot/bazel-out/bin\" overlap"),
+			},
+			{
+				aliases: map[string]string{
+					"/home/bob/execroot/bazel-out":  "bazel-out",
+					"/home/bob/execroot/bazel-out/": "bazel-out",
+				},
+				err: status.Error(codes.InvalidArgument, "Alias paths \"/home/bob/execroot/bazel-out\" and \"/home/bob/execroot/bazel-out/\" overlap"),
+			},
+		} {
+			_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+				OutputBaseId:      "9da951b8cb759233037166e28f7ea186",
+				BuildId:           "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+				DigestFunction:    remoteexecution.DigestFunction_SHA256,
+				OutputPathPrefix:  "/home/bob/bb_clientd/outputs",
+				OutputPathAliases: tc.aliases,
+			})
+			testutil.RequireEqualStatus(t, tc.err, err)
+		}
+	})
+
 	t.Run("InvalidDigestFunction", func(t *testing.T) {
 		// Digest function is not supported by this implementation.
 		_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
@@ -350,7 +624,7 @@ func TestRemoteOutputServiceDirectoryStartBuild(t *testing.T) {
 
 			_, err = d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
 				OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
-				BuildId:          "2e3fd15a-f2ae-4855-ac69-bdd4a0ef7339",
+				BuildId:          "b4314f7b-4f3a-4b8e-a5a1-7b0fd0c1c5e6",
 				InstanceName:     "my-cluster",
 				DigestFunction:   remoteexecution.DigestFunction_MD5,
 				OutputPathPrefix: "/home/bob/bb_clientd/outputs",
@@ -378,7 +652,7 @@ func TestRemoteOutputServiceDirectoryStartBuild(t *testing.T) {
 
 			_, err = d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
 				OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
-				BuildId:          "2e3fd15a-f2ae-4855-ac69-bdd4a0ef7339",
+				BuildId:          "b4314f7b-4f3a-4b8e-a5a1-7b0fd0c1c5e6",
 				InstanceName:     "my-cluster",
 				DigestFunction:   remoteexecution.DigestFunction_MD5,
 				OutputPathPrefix: "/home/bob/bb_clientd/outputs",
@@ -483,7 +757,7 @@ func TestRemoteOutputServiceDirectoryStartBuild(t *testing.T) {
 
 			_, err = d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
 				OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
-				BuildId:          "2e3fd15a-f2ae-4855-ac69-bdd4a0ef7339",
+				BuildId:          "b4314f7b-4f3a-4b8e-a5a1-7b0fd0c1c5e6",
 				InstanceName:     "my-cluster",
 				DigestFunction:   remoteexecution.DigestFunction_MD5,
 				OutputPathPrefix: "/home/bob/bb_clientd/outputs",
@@ -493,10 +767,42 @@ func TestRemoteOutputServiceDirectoryStartBuild(t *testing.T) {
 			})
 			require.NoError(t, err)
 		})
+
+		t.Run("Reattach", func(t *testing.T) {
+			// Calling StartBuild() with the ID of the build
+			// that is running should yield the same
+			// response. As the output path was already
+			// filtered successfully, it should not be
+			// traversed again.
+			response, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+				OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+				BuildId:          "b4314f7b-4f3a-4b8e-a5a1-7b0fd0c1c5e6",
+				InstanceName:     "my-cluster",
+				DigestFunction:   remoteexecution.DigestFunction_MD5,
+				OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+			})
+			require.NoError(t, err)
+			testutil.RequireEqualProto(t, &remoteoutputservice.StartBuildResponse{
+				OutputPathSuffix: "9da951b8cb759233037166e28f7ea186",
+			}, response)
+		})
+
+		t.Run("ReattachDifferentOutputBaseID", func(t *testing.T) {
+			// Build IDs may not be reused across output
+			// bases while the build is running.
+			_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+				OutputBaseId:     "c7d9e3a0b8a1e1e4f6d0a5f8b2c3d4e5",
+				BuildId:          "b4314f7b-4f3a-4b8e-a5a1-7b0fd0c1c5e6",
+				InstanceName:     "my-cluster",
+				DigestFunction:   remoteexecution.DigestFunction_MD5,
+				OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+			})
+			testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Build ID is already in use by output base ID \"9da951b8cb759233037166e28f7ea186\""), err)
+		})
 	})
 }
 
-func TestRemoteOutputServiceDirectoryBatchCreate(t *testing.T) {
+func TestRemoteOutputServiceDirectoryFindMissingBlobsBatchSize(t *testing.T) {
 	ctrl, ctx := gomock.WithContext(context.Background(), t)
 
 	handleAllocator := mock.NewMockStatefulHandleAllocator(ctrl)
@@ -509,232 +815,286 @@ func TestRemoteOutputServiceDirectoryBatchCreate(t *testing.T) {
 	handleAllocator.EXPECT().New().Return(dHandleAllocation)
 	dHandle := mock.NewMockStatefulDirectoryHandle(ctrl)
 	dHandleAllocation.EXPECT().AsStatefulDirectory(gomock.Any()).Return(dHandle)
-	d := cd_vfs.NewRemoteOutputServiceDirectory(
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(1000, 0)).AnyTimes()
+	d := newTestRemoteOutputServiceDirectory(
 		handleAllocator,
 		outputPathFactory,
 		bareContentAddressableStorage,
 		retryingContentAddressableStorage,
 		directoryFetcher,
 		symlinkFactory,
-		/* maximumMessageSizeBytes = */ 10000)
-
-	t.Run("InvalidBuildID", func(t *testing.T) {
-		// StartBuild() should be called first.
-		_, err := d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
-			BuildId: "ad778a53-48e6-4ae1-b1f5-01b84a508f5f",
-			Files: []*remoteexecution.OutputFile{
-				{
-					Path: "foo.o",
-					Digest: &remoteexecution.Digest{
-						Hash:      "d0ab620af7f3e77f3adfa190d41a25ce",
-						SizeBytes: 123,
-					},
-				},
-			},
+		allowAllAuthorizer,
+		clock,
+		cd_vfs.RemoteOutputServiceDirectoryOptions{
+			FindMissingBlobsBatchSize: 2,
 		})
-		testutil.RequireEqualStatus(t, status.Error(codes.FailedPrecondition, "Build ID is not associated with any running build"), err)
-	})
 
-	// Let the remainder of the tests assume that a build is running.
+	// Starting a build in an output path containing three files
+	// should cause FindMissingBlobs() to be called twice.
 	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
 	handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
 	casFileHandleAllocator := mock.NewMockStatelessHandleAllocator(ctrl)
 	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(casFileHandleAllocator)
 	outputPath := mock.NewMockOutputPath(ctrl)
 	outputPathFactory.EXPECT().StartInitialBuild(
-		path.MustNewComponent("c6adef0d5ca1888a4aa847fb51229a8c"),
+		path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
 		gomock.Any(),
 		digest.MustNewFunction("my-cluster", remoteexecution.DigestFunction_MD5),
 		gomock.Any(),
 	).Return(outputPath)
-	outputPath.EXPECT().FilterChildren(gomock.Any())
 
-	response, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
-		OutputBaseId:     "c6adef0d5ca1888a4aa847fb51229a8c",
-		BuildId:          "ad778a53-48e6-4ae1-b1f5-01b84a508f5f",
+	digest1 := digest.MustNewDigest("my-cluster", remoteexecution.DigestFunction_MD5, "338db227a0de09b4309e928cdbb7d40a", 42)
+	digest2 := digest.MustNewDigest("my-cluster", remoteexecution.DigestFunction_MD5, "1d1f71aecd9b2d8127e5a91fc871833a", 43)
+	digest3 := digest.MustNewDigest("my-cluster", remoteexecution.DigestFunction_MD5, "238a131a3e8eb98d1fc5b27d882ca40b", 44)
+	outputPath.EXPECT().FilterChildren(gomock.Any()).DoAndReturn(func(childFilter re_vfs.ChildFilter) error {
+		for _, blobDigest := range []digest.Digest{digest1, digest2, digest3} {
+			child := mock.NewMockNativeLeaf(ctrl)
+			child.EXPECT().GetContainingDigests().Return(blobDigest.ToSingletonSet())
+			remover := mock.NewMockChildRemover(ctrl)
+			require.True(t, childFilter(re_vfs.InitialNode{}.FromLeaf(child), remover.Call))
+		}
+		return nil
+	})
+	bareContentAddressableStorage.EXPECT().FindMissing(ctx, digest.NewSetBuilder().Add(digest1).Add(digest2).Build()).
+		Return(digest.EmptySet, nil)
+	bareContentAddressableStorage.EXPECT().FindMissing(ctx, digest3.ToSingletonSet()).
+		Return(digest.EmptySet, nil)
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
 		InstanceName:     "my-cluster",
 		DigestFunction:   remoteexecution.DigestFunction_MD5,
 		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
-		OutputPathAliases: map[string]string{
-			"/home/bob/.cache/bazel/_bazel_bob/c6adef0d5ca1888a4aa847fb51229a8c/execroot/myproject/bazel-out": ".",
-		},
 	})
 	require.NoError(t, err)
-	testutil.RequireEqualProto(t, &remoteoutputservice.StartBuildResponse{
-		OutputPathSuffix: "c6adef0d5ca1888a4aa847fb51229a8c",
-	}, response)
+}
 
-	// Tests for the path_prefix and clean_path_prefix options.
+func TestRemoteOutputServiceDirectoryStartBuildConcurrency(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
 
-	t.Run("InvalidPathPrefix", func(t *testing.T) {
-		_, err := d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
-			BuildId:    "ad778a53-48e6-4ae1-b1f5-01b84a508f5f",
-			PathPrefix: "/etc",
-			Files: []*remoteexecution.OutputFile{
-				{
-					Path: "foo.o",
-					Digest: &remoteexecution.Digest{
-						Hash:      "d0ab620af7f3e77f3adfa190d41a25ce",
-						SizeBytes: 123,
-					},
-				},
-			},
+	handleAllocator := mock.NewMockStatefulHandleAllocator(ctrl)
+	outputPathFactory := mock.NewMockOutputPathFactory(ctrl)
+	bareContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	retryingContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	directoryFetcher := mock.NewMockDirectoryFetcher(ctrl)
+	symlinkFactory := mock.NewMockSymlinkFactory(ctrl)
+	dHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	handleAllocator.EXPECT().New().Return(dHandleAllocation)
+	dHandle := mock.NewMockStatefulDirectoryHandle(ctrl)
+	dHandleAllocation.EXPECT().AsStatefulDirectory(gomock.Any()).Return(dHandle)
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(1000, 0)).AnyTimes()
+	startBuildConcurrency := semaphore.NewWeighted(1)
+	d := newTestRemoteOutputServiceDirectory(
+		handleAllocator,
+		outputPathFactory,
+		bareContentAddressableStorage,
+		retryingContentAddressableStorage,
+		directoryFetcher,
+		symlinkFactory,
+		allowAllAuthorizer,
+		clock,
+		cd_vfs.RemoteOutputServiceDirectoryOptions{
+			StartBuildConcurrency: startBuildConcurrency,
 		})
-		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Failed to create path prefix directory: Path is absolute, while a relative path was expected"), err)
-	})
 
-	t.Run("PathPrefixCreationFailure", func(t *testing.T) {
-		child1 := mock.NewMockPrepopulatedDirectory(ctrl)
-		outputPath.EXPECT().CreateAndEnterPrepopulatedDirectory(path.MustNewComponent("some")).
-			Return(child1, nil)
-		child1.EXPECT().CreateAndEnterPrepopulatedDirectory(path.MustNewComponent("sub")).
-			Return(nil, status.Error(codes.Internal, "Disk failure"))
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocator := mock.NewMockStatelessHandleAllocator(ctrl)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(casFileHandleAllocator)
+	outputPath := mock.NewMockOutputPath(ctrl)
+	outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+		gomock.Any(),
+		digest.MustNewFunction("my-cluster", remoteexecution.DigestFunction_MD5),
+		gomock.Any(),
+	).Return(outputPath)
 
-		_, err := d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
-			BuildId:    "ad778a53-48e6-4ae1-b1f5-01b84a508f5f",
-			PathPrefix: "some/sub/directory",
-			Files: []*remoteexecution.OutputFile{
-				{
-					Path: "foo.o",
-					Digest: &remoteexecution.Digest{
-						Hash:      "d0ab620af7f3e77f3adfa190d41a25ce",
-						SizeBytes: 123,
-					},
-				},
-			},
+	t.Run("Exhausted", func(t *testing.T) {
+		// If all capacity is in use, StartBuild() should block
+		// until the context is canceled.
+		require.NoError(t, startBuildConcurrency.Acquire(ctx, 1))
+		ctxWithCancel, cancel := context.WithCancel(ctx)
+		cancel()
+
+		_, err := d.StartBuild(ctxWithCancel, &remoteoutputservice.StartBuildRequest{
+			OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+			BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			InstanceName:     "my-cluster",
+			DigestFunction:   remoteexecution.DigestFunction_MD5,
+			OutputPathPrefix: "/home/bob/bb_clientd/outputs",
 		})
-		testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Failed to create path prefix directory: Disk failure"), err)
+		testutil.RequireEqualStatus(t, status.Error(codes.Canceled, "Failed to filter contents of the output path: context canceled"), err)
+		startBuildConcurrency.Release(1)
 	})
 
-	t.Run("PathPrefixCleanFailure", func(t *testing.T) {
-		child1 := mock.NewMockPrepopulatedDirectory(ctrl)
-		outputPath.EXPECT().CreateAndEnterPrepopulatedDirectory(path.MustNewComponent("directory")).
-			Return(child1, nil)
-		child1.EXPECT().RemoveAllChildren(false).Return(status.Error(codes.Internal, "Disk failure"))
+	t.Run("Available", func(t *testing.T) {
+		// Once capacity is available, the output path should be
+		// traversed.
+		outputPath.EXPECT().FilterChildren(gomock.Any())
 
-		_, err := d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
-			BuildId:         "ad778a53-48e6-4ae1-b1f5-01b84a508f5f",
-			PathPrefix:      "directory",
-			CleanPathPrefix: true,
-			Files: []*remoteexecution.OutputFile{
-				{
-					Path: "foo.o",
-					Digest: &remoteexecution.Digest{
-						Hash:      "d0ab620af7f3e77f3adfa190d41a25ce",
-						SizeBytes: 123,
-					},
-				},
-			},
+		_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+			OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+			BuildId:          "e20a2f7b-d5ac-4ea4-9a9c-3d0ba7c0a3e4",
+			InstanceName:     "my-cluster",
+			DigestFunction:   remoteexecution.DigestFunction_MD5,
+			OutputPathPrefix: "/home/bob/bb_clientd/outputs",
 		})
-		testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Failed to clean path prefix directory: Disk failure"), err)
+		require.NoError(t, err)
 	})
+}
 
-	t.Run("SymlinkCreationFailure", func(t *testing.T) {
-		symlink := mock.NewMockNativeLeaf(ctrl)
-		symlinkFactory.EXPECT().LookupSymlink([]byte("target")).Return(symlink)
-		outputPath.EXPECT().CreateChildren(map[path.Component]re_vfs.InitialNode{
-			path.MustNewComponent("foo"): re_vfs.InitialNode{}.FromLeaf(symlink),
-		}, true).Return(status.Error(codes.Internal, "I/O error"))
-		symlink.EXPECT().Unlink()
+func TestRemoteOutputServiceDirectoryBatchStartBuild(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
 
-		_, err := d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
-			BuildId: "ad778a53-48e6-4ae1-b1f5-01b84a508f5f",
-			Symlinks: []*remoteexecution.OutputSymlink{
+	handleAllocator := mock.NewMockStatefulHandleAllocator(ctrl)
+	outputPathFactory := mock.NewMockOutputPathFactory(ctrl)
+	bareContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	retryingContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	directoryFetcher := mock.NewMockDirectoryFetcher(ctrl)
+	symlinkFactory := mock.NewMockSymlinkFactory(ctrl)
+	dHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	handleAllocator.EXPECT().New().Return(dHandleAllocation)
+	dHandle := mock.NewMockStatefulDirectoryHandle(ctrl)
+	dHandleAllocation.EXPECT().AsStatefulDirectory(gomock.Any()).Return(dHandle)
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(1000, 0)).AnyTimes()
+	d := newTestRemoteOutputServiceDirectory(
+		handleAllocator,
+		outputPathFactory,
+		bareContentAddressableStorage,
+		retryingContentAddressableStorage,
+		directoryFetcher,
+		symlinkFactory,
+		allowAllAuthorizer,
+		clock,
+		cd_vfs.RemoteOutputServiceDirectoryOptions{})
+
+	t.Run("InvalidRequest", func(t *testing.T) {
+		// Errors should report which request was invalid.
+		_, err := d.BatchStartBuild(ctx, &outputpaths.BatchStartBuildRequest{
+			Requests: []*remoteoutputservice.StartBuildRequest{
 				{
-					Path:   "foo",
-					Target: "target",
+					OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+					BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+					DigestFunction:   remoteexecution.DigestFunction_SHA256,
+					OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+				},
+				{
+					OutputBaseId:     "..",
+					BuildId:          "9ff3a9c1-0c63-4a30-9a8e-9c8b0c5b6f2a",
+					DigestFunction:   remoteexecution.DigestFunction_SHA256,
+					OutputPathPrefix: "/home/bob/bb_clientd/outputs",
 				},
 			},
 		})
-		testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Failed to create symbolic link \"foo\": I/O error"), err)
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Request at index 1: Output base ID must consist of one or more valid filenames separated by slashes: Component 1 may not be \"..\""), err)
 	})
 
-	t.Run("DirectoryTooBig", func(t *testing.T) {
-		// We should forbid the creation of directories in the
-		// output directory that are too big, as attempting to
-		// access those would fail anyway.
-		_, err := d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
-			BuildId: "ad778a53-48e6-4ae1-b1f5-01b84a508f5f",
-			Directories: []*remoteexecution.OutputDirectory{
+	t.Run("DuplicateOutputBaseID", func(t *testing.T) {
+		// Starting multiple builds in the same output base
+		// would cause all but the last one to be finalized.
+		_, err := d.BatchStartBuild(ctx, &outputpaths.BatchStartBuildRequest{
+			Requests: []*remoteoutputservice.StartBuildRequest{
 				{
-					Path: "large_directory",
-					TreeDigest: &remoteexecution.Digest{
-						Hash:      "b2bc8901bd2dfc25e0e43f0a1eaf8758",
-						SizeBytes: 9999999,
-					},
+					OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+					BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+					DigestFunction:   remoteexecution.DigestFunction_SHA256,
+					OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+				},
+				{
+					OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+					BuildId:          "9ff3a9c1-0c63-4a30-9a8e-9c8b0c5b6f2a",
+					DigestFunction:   remoteexecution.DigestFunction_SHA256,
+					OutputPathPrefix: "/home/bob/bb_clientd/outputs",
 				},
 			},
 		})
-		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Directory \"large_directory\" is 9999999 bytes in size, which exceeds the permitted maximum of 10000 bytes"), err)
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Request at index 1: Output base ID \"9da951b8cb759233037166e28f7ea186\" is used by multiple requests"), err)
 	})
 
-	// The creation of actual files and directories is hard to test,
-	// as the InitialNode arguments provided to CreateChildren()
-	// contain objects that are hard to compare. At least provide a
-	// test for the success case.
+	requests := []*remoteoutputservice.StartBuildRequest{
+		{
+			OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+			BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			InstanceName:     "my-cluster",
+			DigestFunction:   remoteexecution.DigestFunction_SHA256,
+			OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+		},
+		{
+			OutputBaseId:     "b4ca7ef48a2c2ef2c8b5d4d2a5b0bb38",
+			BuildId:          "9ff3a9c1-0c63-4a30-9a8e-9c8b0c5b6f2a",
+			InstanceName:     "my-cluster",
+			DigestFunction:   remoteexecution.DigestFunction_SHA256,
+			OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+		},
+	}
 
-	t.Run("Success", func(t *testing.T) {
-		child1 := mock.NewMockPrepopulatedDirectory(ctrl)
-		outputPath.EXPECT().CreateAndEnterPrepopulatedDirectory(path.MustNewComponent("a")).
-			Return(child1, nil)
+	outputPaths := make([]*mock.MockOutputPath, 0, len(requests))
+	for _, request := range requests {
+		casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+		handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+		casFileHandleAllocator := mock.NewMockStatelessHandleAllocator(ctrl)
+		casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(casFileHandleAllocator)
+		outputPath := mock.NewMockOutputPath(ctrl)
+		outputPathFactory.EXPECT().StartInitialBuild(
+			path.MustNewComponent(request.OutputBaseId),
+			gomock.Any(),
+			digest.MustNewFunction("my-cluster", remoteexecution.DigestFunction_SHA256),
+			gomock.Any(),
+		).Return(outputPath)
+		outputPaths = append(outputPaths, outputPath)
+	}
 
-		child2 := mock.NewMockPrepopulatedDirectory(ctrl)
-		child1.EXPECT().CreateAndEnterPrepopulatedDirectory(path.MustNewComponent("b")).
-			Return(child2, nil).Times(3)
+	t.Run("FilterChildrenFailure", func(t *testing.T) {
+		// If filtering the contents of one of the output paths
+		// fails, none of the builds should be started.
+		outputPaths[0].EXPECT().FilterChildren(gomock.Any())
+		outputPaths[1].EXPECT().FilterChildren(gomock.Any()).Return(status.Error(codes.Internal, "Failed to read directory contents"))
 
-		// Creation of "file".
-		casFileHandleAllocation := mock.NewMockStatelessHandleAllocation(ctrl)
-		casFileHandleAllocator.EXPECT().New(gomock.Any()).Return(casFileHandleAllocation)
-		file := mock.NewMockNativeLeaf(ctrl)
-		casFileHandleAllocation.EXPECT().AsNativeLeaf(gomock.Any()).Return(file)
-		child2.EXPECT().CreateChildren(map[path.Component]re_vfs.InitialNode{
-			path.MustNewComponent("file"): re_vfs.InitialNode{}.FromLeaf(file),
-		}, true)
+		_, err := d.BatchStartBuild(ctx, &outputpaths.BatchStartBuildRequest{
+			Requests: requests,
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Request at index 1: Failed to filter contents of the output path: Failed to read directory contents"), err)
 
-		// Creation of "directory".
-		child2.EXPECT().CreateChildren(gomock.Any(), true)
+		for _, request := range requests {
+			_, err := d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
+				BuildId: request.BuildId,
+			})
+			testutil.RequireEqualStatus(t, status.Error(codes.FailedPrecondition, "Build ID is not associated with any running build"), err)
+		}
+	})
 
-		// Creation of "symlink".
-		symlink := mock.NewMockNativeLeaf(ctrl)
-		symlinkFactory.EXPECT().LookupSymlink([]byte("file")).Return(symlink)
-		child2.EXPECT().CreateChildren(map[path.Component]re_vfs.InitialNode{
-			path.MustNewComponent("symlink"): re_vfs.InitialNode{}.FromLeaf(symlink),
-		}, true)
+	t.Run("Success", func(t *testing.T) {
+		// Retrying the request should reuse the output paths
+		// that were created previously.
+		outputPaths[0].EXPECT().FilterChildren(gomock.Any())
+		outputPaths[1].EXPECT().FilterChildren(gomock.Any())
 
-		_, err := d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
-			BuildId:    "ad778a53-48e6-4ae1-b1f5-01b84a508f5f",
-			PathPrefix: "a",
-			Files: []*remoteexecution.OutputFile{
-				{
-					Path:         "b/file",
-					IsExecutable: true,
-					Digest: &remoteexecution.Digest{
-						Hash:      "d0ab620af7f3e77f3adfa190d41a25ce",
-						SizeBytes: 123,
-					},
-				},
-			},
-			Directories: []*remoteexecution.OutputDirectory{
-				{
-					Path: "b/directory",
-					TreeDigest: &remoteexecution.Digest{
-						Hash:      "8e1554fc1ad824a6e9180c7b145790d2",
-						SizeBytes: 123,
-					},
-				},
-			},
-			Symlinks: []*remoteexecution.OutputSymlink{
-				{
-					Path:   "b/symlink",
-					Target: "file",
-				},
-			},
+		response, err := d.BatchStartBuild(ctx, &outputpaths.BatchStartBuildRequest{
+			Requests: requests,
 		})
 		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &outputpaths.BatchStartBuildResponse{
+			Responses: []*remoteoutputservice.StartBuildResponse{
+				{OutputPathSuffix: "9da951b8cb759233037166e28f7ea186"},
+				{OutputPathSuffix: "b4ca7ef48a2c2ef2c8b5d4d2a5b0bb38"},
+			},
+		}, response)
+
+		for _, request := range requests {
+			response, err := d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
+				BuildId: request.BuildId,
+			})
+			require.NoError(t, err)
+			testutil.RequireEqualProto(t, &remoteoutputservice.BatchStatResponse{}, response)
+		}
 	})
 }
 
-func TestRemoteOutputServiceDirectoryBatchStat(t *testing.T) {
+func TestRemoteOutputServiceDirectoryCopyFilesAcrossInstanceNames(t *testing.T) {
 	ctrl, ctx := gomock.WithContext(context.Background(), t)
 
 	handleAllocator := mock.NewMockStatefulHandleAllocator(ctrl)
@@ -747,25 +1107,24 @@ func TestRemoteOutputServiceDirectoryBatchStat(t *testing.T) {
 	handleAllocator.EXPECT().New().Return(dHandleAllocation)
 	dHandle := mock.NewMockStatefulDirectoryHandle(ctrl)
 	dHandleAllocation.EXPECT().AsStatefulDirectory(gomock.Any()).Return(dHandle)
-	d := cd_vfs.NewRemoteOutputServiceDirectory(
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(1000, 0)).AnyTimes()
+	d := newTestRemoteOutputServiceDirectory(
 		handleAllocator,
 		outputPathFactory,
 		bareContentAddressableStorage,
 		retryingContentAddressableStorage,
 		directoryFetcher,
 		symlinkFactory,
-		/* maximumMessageSizeBytes = */ 10000)
-
-	t.Run("InvalidBuildID", func(t *testing.T) {
-		// StartBuild() should be called first.
-		_, err := d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
-			BuildId: "140dbef8-1b24-4966-bb9e-8edc7fa61df8",
-			Paths:   []string{"foo.o"},
+		allowAllAuthorizer,
+		clock,
+		cd_vfs.RemoteOutputServiceDirectoryOptions{
+			CopyFilesAcrossInstanceNames: true,
 		})
-		testutil.RequireEqualStatus(t, status.Error(codes.FailedPrecondition, "Build ID is not associated with any running build"), err)
-	})
 
-	// Let the remainder of the tests assume that a build is running.
+	// Start a build against "new-cluster", while the output path
+	// contains files that were created by a build against
+	// "old-cluster".
 	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
 	handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
 	casFileHandleAllocator := mock.NewMockStatelessHandleAllocator(ctrl)
@@ -774,15 +1133,78 @@ func TestRemoteOutputServiceDirectoryBatchStat(t *testing.T) {
 	outputPathFactory.EXPECT().StartInitialBuild(
 		path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
 		gomock.Any(),
-		digest.MustNewFunction("my-cluster", remoteexecution.DigestFunction_MD5),
+		digest.MustNewFunction("new-cluster", remoteexecution.DigestFunction_MD5),
 		gomock.Any(),
 	).Return(outputPath)
-	outputPath.EXPECT().FilterChildren(gomock.Any())
+
+	// File that is present in the new instance name. It should be
+	// retained without copying it.
+	remover1 := mock.NewMockChildRemover(ctrl)
+	leaf1 := mock.NewMockNativeLeaf(ctrl)
+	leaf1.EXPECT().GetContainingDigests().
+		Return(digest.MustNewDigest("old-cluster", remoteexecution.DigestFunction_MD5, "ed076287532e86365e841e92bfc50d8c", 12).ToSingletonSet())
+
+	// File that is absent in the new instance name. It should be
+	// copied from the old instance name.
+	remover2 := mock.NewMockChildRemover(ctrl)
+	leaf2 := mock.NewMockNativeLeaf(ctrl)
+	leaf2.EXPECT().GetContainingDigests().
+		Return(digest.MustNewDigest("old-cluster", remoteexecution.DigestFunction_MD5, "8b1a9953c4611296a827abf8c47804d7", 5).ToSingletonSet())
+	bareContentAddressableStorage.EXPECT().Get(gomock.Any(), digest.MustNewDigest("old-cluster", remoteexecution.DigestFunction_MD5, "8b1a9953c4611296a827abf8c47804d7", 5)).
+		Return(buffer.NewValidatedBufferFromByteSlice([]byte("Hello")))
+	bareContentAddressableStorage.EXPECT().Put(gomock.Any(), digest.MustNewDigest("new-cluster", remoteexecution.DigestFunction_MD5, "8b1a9953c4611296a827abf8c47804d7", 5), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, blobDigest digest.Digest, b buffer.Buffer) error {
+			data, err := b.ToByteSlice(100)
+			require.NoError(t, err)
+			require.Equal(t, []byte("Hello"), data)
+			return nil
+		})
+
+	// File that is absent in the new instance name, and also
+	// cannot be copied. It should be removed.
+	remover3 := mock.NewMockChildRemover(ctrl)
+	remover3.EXPECT().Call()
+	leaf3 := mock.NewMockNativeLeaf(ctrl)
+	leaf3.EXPECT().GetContainingDigests().
+		Return(digest.MustNewDigest("old-cluster", remoteexecution.DigestFunction_MD5, "3e25960a79dbc69b674cd4ec67a72c62", 11).ToSingletonSet())
+	bareContentAddressableStorage.EXPECT().Get(gomock.Any(), digest.MustNewDigest("old-cluster", remoteexecution.DigestFunction_MD5, "3e25960a79dbc69b674cd4ec67a72c62", 11)).
+		Return(buffer.NewBufferFromError(status.Error(codes.NotFound, "Object not found")))
+	bareContentAddressableStorage.EXPECT().Put(gomock.Any(), digest.MustNewDigest("new-cluster", remoteexecution.DigestFunction_MD5, "3e25960a79dbc69b674cd4ec67a72c62", 11), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, blobDigest digest.Digest, b buffer.Buffer) error {
+			_, err := b.ToByteSlice(100)
+			testutil.RequireEqualStatus(t, status.Error(codes.NotFound, "Object not found"), err)
+			return err
+		})
+
+	// File that uses a different digest function. It cannot be
+	// copied, so it should be removed immediately.
+	remover4 := mock.NewMockChildRemover(ctrl)
+	remover4.EXPECT().Call()
+	leaf4 := mock.NewMockNativeLeaf(ctrl)
+	leaf4.EXPECT().GetContainingDigests().
+		Return(digest.MustNewDigest("old-cluster", remoteexecution.DigestFunction_SHA256, "8b1a9953c4611296a827abf8c47804d7e6c49c6b1f3c5f5e5a7e2d3c4b5a6978", 5).ToSingletonSet())
+
+	outputPath.EXPECT().FilterChildren(gomock.Any()).DoAndReturn(func(childFilter re_vfs.ChildFilter) error {
+		require.True(t, childFilter(re_vfs.InitialNode{}.FromLeaf(leaf1), remover1.Call))
+		require.True(t, childFilter(re_vfs.InitialNode{}.FromLeaf(leaf2), remover2.Call))
+		require.True(t, childFilter(re_vfs.InitialNode{}.FromLeaf(leaf3), remover3.Call))
+		require.True(t, childFilter(re_vfs.InitialNode{}.FromLeaf(leaf4), remover4.Call))
+		return nil
+	})
+	bareContentAddressableStorage.EXPECT().FindMissing(gomock.Any(), digest.NewSetBuilder().
+		Add(digest.MustNewDigest("new-cluster", remoteexecution.DigestFunction_MD5, "ed076287532e86365e841e92bfc50d8c", 12)).
+		Add(digest.MustNewDigest("new-cluster", remoteexecution.DigestFunction_MD5, "8b1a9953c4611296a827abf8c47804d7", 5)).
+		Add(digest.MustNewDigest("new-cluster", remoteexecution.DigestFunction_MD5, "3e25960a79dbc69b674cd4ec67a72c62", 11)).
+		Build()).
+		Return(digest.NewSetBuilder().
+			Add(digest.MustNewDigest("new-cluster", remoteexecution.DigestFunction_MD5, "8b1a9953c4611296a827abf8c47804d7", 5)).
+			Add(digest.MustNewDigest("new-cluster", remoteexecution.DigestFunction_MD5, "3e25960a79dbc69b674cd4ec67a72c62", 11)).
+			Build(), nil)
 
 	response, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
 		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
 		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
-		InstanceName:     "my-cluster",
+		InstanceName:     "new-cluster",
 		DigestFunction:   remoteexecution.DigestFunction_MD5,
 		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
 		OutputPathAliases: map[string]string{
@@ -793,313 +1215,8825 @@ func TestRemoteOutputServiceDirectoryBatchStat(t *testing.T) {
 	testutil.RequireEqualProto(t, &remoteoutputservice.StartBuildResponse{
 		OutputPathSuffix: "9da951b8cb759233037166e28f7ea186",
 	}, response)
+}
 
-	t.Run("Noop", func(t *testing.T) {
-		// Requests that don't contain any paths shouldn't cause
-		// any I/O against the output path.
-		_, err := d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
-			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
-		})
-		require.NoError(t, err)
-	})
+func TestRemoteOutputServiceDirectoryBatchCreate(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
 
-	t.Run("OnDirectoryLookupFailure", func(t *testing.T) {
-		outputPath.EXPECT().LookupChild(path.MustNewComponent("stdio")).Return(re_vfs.PrepopulatedDirectoryChild{}, status.Error(codes.Internal, "Disk failure"))
+	handleAllocator := mock.NewMockStatefulHandleAllocator(ctrl)
+	outputPathFactory := mock.NewMockOutputPathFactory(ctrl)
+	bareContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	retryingContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	directoryFetcher := mock.NewMockDirectoryFetcher(ctrl)
+	symlinkFactory := mock.NewMockSymlinkFactory(ctrl)
+	dHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	handleAllocator.EXPECT().New().Return(dHandleAllocation)
+	dHandle := mock.NewMockStatefulDirectoryHandle(ctrl)
+	dHandleAllocation.EXPECT().AsStatefulDirectory(gomock.Any()).Return(dHandle)
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(1000, 0)).AnyTimes()
+	d := newTestRemoteOutputServiceDirectory(
+		handleAllocator,
+		outputPathFactory,
+		bareContentAddressableStorage,
+		retryingContentAddressableStorage,
+		directoryFetcher,
+		symlinkFactory,
+		allowAllAuthorizer,
+		clock,
+		cd_vfs.RemoteOutputServiceDirectoryOptions{})
 
-		_, err := d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
-			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
-			Paths:   []string{"stdio/printf.o"},
+	t.Run("InvalidBuildID", func(t *testing.T) {
+		// StartBuild() should be called first.
+		_, err := d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+			BuildId: "ad778a53-48e6-4ae1-b1f5-01b84a508f5f",
+			Files: []*remoteexecution.OutputFile{
+				{
+					Path: "foo.o",
+					Digest: &remoteexecution.Digest{
+						Hash:      "d0ab620af7f3e77f3adfa190d41a25ce",
+						SizeBytes: 123,
+					},
+				},
+			},
 		})
-		testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Failed to resolve path \"stdio/printf.o\" beyond \".\": Disk failure"), err)
+		testutil.RequireEqualStatus(t, status.Error(codes.FailedPrecondition, "Build ID is not associated with any running build"), err)
 	})
 
-	t.Run("OnDirectoryReadlinkFailure", func(t *testing.T) {
-		leaf := mock.NewMockNativeLeaf(ctrl)
-		outputPath.EXPECT().LookupChild(path.MustNewComponent("stdio")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(leaf), nil)
-		leaf.EXPECT().Readlink().Return("", status.Error(codes.Internal, "Disk failure"))
+	// Let the remainder of the tests assume that a build is running.
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocator := mock.NewMockStatelessHandleAllocator(ctrl)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(casFileHandleAllocator)
+	outputPath := mock.NewMockOutputPath(ctrl)
+	outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("c6adef0d5ca1888a4aa847fb51229a8c"),
+		gomock.Any(),
+		digest.MustNewFunction("my-cluster", remoteexecution.DigestFunction_MD5),
+		gomock.Any(),
+	).Return(outputPath)
+	outputPath.EXPECT().FilterChildren(gomock.Any())
 
-		_, err := d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
-			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
-			Paths:   []string{"stdio/printf.o"},
-		})
-		testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Failed to resolve path \"stdio/printf.o\" beyond \".\": Disk failure"), err)
+	response, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "c6adef0d5ca1888a4aa847fb51229a8c",
+		BuildId:          "ad778a53-48e6-4ae1-b1f5-01b84a508f5f",
+		InstanceName:     "my-cluster",
+		DigestFunction:   remoteexecution.DigestFunction_MD5,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+		OutputPathAliases: map[string]string{
+			"/home/bob/.cache/bazel/_bazel_bob/c6adef0d5ca1888a4aa847fb51229a8c/execroot/myproject/bazel-out": ".",
+		},
 	})
+	require.NoError(t, err)
+	testutil.RequireEqualProto(t, &remoteoutputservice.StartBuildResponse{
+		OutputPathSuffix: "c6adef0d5ca1888a4aa847fb51229a8c",
+	}, response)
 
-	t.Run("OnTerminalLookupFailure", func(t *testing.T) {
-		outputPath.EXPECT().LookupChild(path.MustNewComponent("printf.o")).Return(re_vfs.PrepopulatedDirectoryChild{}, status.Error(codes.Internal, "Disk failure"))
+	// Tests for the path_prefix and clean_path_prefix options.
 
-		_, err := d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
-			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
-			Paths:   []string{"printf.o"},
+	t.Run("InvalidPathPrefix", func(t *testing.T) {
+		_, err := d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+			BuildId:    "ad778a53-48e6-4ae1-b1f5-01b84a508f5f",
+			PathPrefix: "/etc",
+			Files: []*remoteexecution.OutputFile{
+				{
+					Path: "foo.o",
+					Digest: &remoteexecution.Digest{
+						Hash:      "d0ab620af7f3e77f3adfa190d41a25ce",
+						SizeBytes: 123,
+					},
+				},
+			},
 		})
-		testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Failed to resolve path \"printf.o\" beyond \".\": Disk failure"), err)
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Failed to create path prefix directory: Path is absolute, while a relative path was expected"), err)
 	})
 
-	t.Run("OnTerminalReadlinkFailure", func(t *testing.T) {
-		leaf := mock.NewMockNativeLeaf(ctrl)
-		outputPath.EXPECT().LookupChild(path.MustNewComponent("printf.o")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(leaf), nil)
-		leaf.EXPECT().Readlink().Return("", status.Error(codes.Internal, "Disk failure"))
-
-		_, err := d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
-			BuildId:        "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
-			FollowSymlinks: true,
-			Paths:          []string{"printf.o"},
-		})
-		testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Failed to resolve path \"printf.o\" beyond \".\": Disk failure"), err)
-	})
-
-	t.Run("OnTerminalGetOutputServiceFileStatusFailure", func(t *testing.T) {
-		leaf := mock.NewMockNativeLeaf(ctrl)
-		outputPath.EXPECT().LookupChild(path.MustNewComponent("printf.o")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(leaf), nil)
-		leaf.EXPECT().GetOutputServiceFileStatus(gomock.Any()).Return(nil, status.Error(codes.Internal, "Disk failure"))
-
-		_, err := d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
-			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
-			Paths:   []string{"printf.o"},
-		})
-		testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Failed to resolve path \"printf.o\" beyond \".\": Disk failure"), err)
-	})
+	t.Run("PathPrefixCreationFailure", func(t *testing.T) {
+		child1 := mock.NewMockPrepopulatedDirectory(ctrl)
+		outputPath.EXPECT().CreateAndEnterPrepopulatedDirectory(path.MustNewComponent("some")).
+			Return(child1, nil)
+		child1.EXPECT().CreateAndEnterPrepopulatedDirectory(path.MustNewComponent("sub")).
+			Return(nil, status.Error(codes.Internal, "Disk failure"))
 
-	t.Run("Success", func(t *testing.T) {
-		// Lookup of "file", pointing to directly to a file.
-		leaf1 := mock.NewMockNativeLeaf(ctrl)
-		outputPath.EXPECT().LookupChild(path.MustNewComponent("file")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(leaf1), nil)
-		leaf1.EXPECT().Readlink().Return("", syscall.EINVAL)
-		leaf1.EXPECT().GetOutputServiceFileStatus(gomock.Any()).Return(&remoteoutputservice.FileStatus{
-			FileType: &remoteoutputservice.FileStatus_File_{
-				File: &remoteoutputservice.FileStatus_File{
+		_, err := d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+			BuildId:    "ad778a53-48e6-4ae1-b1f5-01b84a508f5f",
+			PathPrefix: "some/sub/directory",
+			Files: []*remoteexecution.OutputFile{
+				{
+					Path: "foo.o",
 					Digest: &remoteexecution.Digest{
-						Hash:      "ad17450bb18953f249532a478d2150ba",
-						SizeBytes: 72,
+						Hash:      "d0ab620af7f3e77f3adfa190d41a25ce",
+						SizeBytes: 123,
 					},
 				},
 			},
-		}, nil)
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Failed to create path prefix directory: Disk failure"), err)
+	})
 
-		// Lookup of "directory". pointing directly to a directory.
-		directory1 := mock.NewMockPrepopulatedDirectory(ctrl)
-		outputPath.EXPECT().LookupChild(path.MustNewComponent("directory")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(directory1), nil)
-		directory1.EXPECT().VirtualGetAttributes(ctx, re_vfs.AttributesMaskLastDataModificationTime, gomock.Any()).
-			Do(func(ctx context.Context, requested re_vfs.AttributesMask, attributes *re_vfs.Attributes) {
-				attributes.SetLastDataModificationTime(time.Unix(1000, 0))
-			})
+	t.Run("PathPrefixCleanFailure", func(t *testing.T) {
+		child1 := mock.NewMockPrepopulatedDirectory(ctrl)
+		outputPath.EXPECT().CreateAndEnterPrepopulatedDirectory(path.MustNewComponent("directory")).
+			Return(child1, nil)
+		child1.EXPECT().RemoveAllChildren(false).Return(status.Error(codes.Internal, "Disk failure"))
 
-		// Lookup of "nested/symlink_internal_relative_file",
-		// being a symlink that points to a file.
-		directory2 := mock.NewMockPrepopulatedDirectory(ctrl)
-		outputPath.EXPECT().LookupChild(path.MustNewComponent("nested")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(directory2), nil)
-		leaf2 := mock.NewMockNativeLeaf(ctrl)
-		directory2.EXPECT().LookupChild(path.MustNewComponent("symlink_internal_relative_file")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(leaf2), nil)
-		leaf2.EXPECT().Readlink().Return("../target", nil)
-		leaf3 := mock.NewMockNativeLeaf(ctrl)
-		outputPath.EXPECT().LookupChild(path.MustNewComponent("target")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(leaf3), nil)
-		leaf3.EXPECT().Readlink().Return("", syscall.EINVAL)
-		leaf3.EXPECT().GetOutputServiceFileStatus(gomock.Any()).Return(&remoteoutputservice.FileStatus{
-			FileType: &remoteoutputservice.FileStatus_File_{
-				File: &remoteoutputservice.FileStatus_File{
+		_, err := d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+			BuildId:         "ad778a53-48e6-4ae1-b1f5-01b84a508f5f",
+			PathPrefix:      "directory",
+			CleanPathPrefix: true,
+			Files: []*remoteexecution.OutputFile{
+				{
+					Path: "foo.o",
 					Digest: &remoteexecution.Digest{
-						Hash:      "166d6efee3489f73be1c3c2304e50bca",
-						SizeBytes: 85,
+						Hash:      "d0ab620af7f3e77f3adfa190d41a25ce",
+						SizeBytes: 123,
 					},
 				},
 			},
-		}, nil)
-
-		// Lookup of "nested/symlink_internal_relative_directory",
-		// being a symlink that points to a directory.
-		directory3 := mock.NewMockPrepopulatedDirectory(ctrl)
-		outputPath.EXPECT().LookupChild(path.MustNewComponent("nested")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(directory3), nil)
-		leaf4 := mock.NewMockNativeLeaf(ctrl)
-		directory3.EXPECT().LookupChild(path.MustNewComponent("symlink_internal_relative_directory")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(leaf4), nil)
-		leaf4.EXPECT().Readlink().Return("..", nil)
-		outputPath.EXPECT().VirtualGetAttributes(ctx, re_vfs.AttributesMaskLastDataModificationTime, gomock.Any()).
-			Do(func(ctx context.Context, requested re_vfs.AttributesMask, attributes *re_vfs.Attributes) {
-				attributes.SetLastDataModificationTime(time.Unix(1001, 0))
-			})
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Failed to clean path prefix directory: Disk failure"), err)
+	})
 
-		// Lookup of "nested/symlink_internal_absolute_path",
-		// being a symlink containing an absolute path starting
-		// with the output path.
-		directory4 := mock.NewMockPrepopulatedDirectory(ctrl)
-		outputPath.EXPECT().LookupChild(path.MustNewComponent("nested")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(directory4), nil)
-		leaf5 := mock.NewMockNativeLeaf(ctrl)
-		directory4.EXPECT().LookupChild(path.MustNewComponent("symlink_internal_absolute_path")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(leaf5), nil)
-		leaf5.EXPECT().Readlink().Return("/home/bob/bb_clientd/outputs/9da951b8cb759233037166e28f7ea186/hello", nil)
-		directory5 := mock.NewMockPrepopulatedDirectory(ctrl)
-		outputPath.EXPECT().LookupChild(path.MustNewComponent("hello")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(directory5), nil)
-		directory5.EXPECT().VirtualGetAttributes(ctx, re_vfs.AttributesMaskLastDataModificationTime, gomock.Any()).
-			Do(func(ctx context.Context, requested re_vfs.AttributesMask, attributes *re_vfs.Attributes) {
-				attributes.SetLastDataModificationTime(time.Unix(1002, 0))
-			})
+	// Requests that don't contain any entries should only have an
+	// effect if the path prefix needs to be cleaned.
 
-		// Lookup of "nested/symlink_internal_absolute_alias",
-		// being a symlink containing an absolute path starting
-		// with one of the output path aliases.
-		directory6 := mock.NewMockPrepopulatedDirectory(ctrl)
-		outputPath.EXPECT().LookupChild(path.MustNewComponent("nested")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(directory6), nil)
-		leaf6 := mock.NewMockNativeLeaf(ctrl)
-		directory6.EXPECT().LookupChild(path.MustNewComponent("symlink_internal_absolute_alias")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(leaf6), nil)
-		leaf6.EXPECT().Readlink().Return("/home/bob/.cache/bazel/_bazel_bob/9da951b8cb759233037166e28f7ea186/execroot/myproject/bazel-out/hello", nil)
-		directory7 := mock.NewMockPrepopulatedDirectory(ctrl)
-		outputPath.EXPECT().LookupChild(path.MustNewComponent("hello")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(directory7), nil)
-		directory7.EXPECT().VirtualGetAttributes(ctx, re_vfs.AttributesMaskLastDataModificationTime, gomock.Any()).
-			Do(func(ctx context.Context, requested re_vfs.AttributesMask, attributes *re_vfs.Attributes) {
-				attributes.SetLastDataModificationTime(time.Unix(1003, 0))
-			})
+	t.Run("EmptyRequestInvalidBuildID", func(t *testing.T) {
+		_, err := d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+			BuildId: "3f2c5e8e-0c1b-4d0d-8d3a-8b6f1f6d2c91",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.FailedPrecondition, "Build ID is not associated with any running build"), err)
+	})
 
-		// Lookup of "nested/symlink_external", being a symlink
-		// containing an absolute path that doesn't start with
-		// any known prefix.
-		directory8 := mock.NewMockPrepopulatedDirectory(ctrl)
-		outputPath.EXPECT().LookupChild(path.MustNewComponent("nested")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(directory8), nil)
-		leaf7 := mock.NewMockNativeLeaf(ctrl)
-		directory8.EXPECT().LookupChild(path.MustNewComponent("symlink_external")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(leaf7), nil)
-		leaf7.EXPECT().Readlink().Return("/etc/passwd", nil)
+	t.Run("EmptyRequestInvalidPathPrefix", func(t *testing.T) {
+		_, err := d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+			BuildId:    "ad778a53-48e6-4ae1-b1f5-01b84a508f5f",
+			PathPrefix: "/etc",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Failed to create path prefix directory: Path is absolute, while a relative path was expected"), err)
+	})
 
-		// Lookup of "nonexistent".
-		outputPath.EXPECT().LookupChild(path.MustNewComponent("nonexistent")).Return(re_vfs.PrepopulatedDirectoryChild{}, syscall.ENOENT)
+	t.Run("EmptyRequest", func(t *testing.T) {
+		// The path prefix directory should not be created.
+		_, err := d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+			BuildId:    "ad778a53-48e6-4ae1-b1f5-01b84a508f5f",
+			PathPrefix: "some/sub/directory",
+		})
+		require.NoError(t, err)
+	})
 
-		// Lookup of ".".
-		outputPath.EXPECT().VirtualGetAttributes(ctx, re_vfs.AttributesMaskLastDataModificationTime, gomock.Any()).
-			Do(func(ctx context.Context, requested re_vfs.AttributesMask, attributes *re_vfs.Attributes) {
-				attributes.SetLastDataModificationTime(time.Unix(1004, 0))
-			})
+	t.Run("EmptyRequestCleanPathPrefix", func(t *testing.T) {
+		child1 := mock.NewMockPrepopulatedDirectory(ctrl)
+		outputPath.EXPECT().CreateAndEnterPrepopulatedDirectory(path.MustNewComponent("directory")).
+			Return(child1, nil)
+		child1.EXPECT().RemoveAllChildren(false)
 
-		response, err := d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
-			BuildId:           "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
-			IncludeFileDigest: true,
-			FollowSymlinks:    true,
-			Paths: []string{
-				"file",
-				"directory",
-				"nested/symlink_internal_relative_file",
-				"nested/symlink_internal_relative_directory",
-				"nested/symlink_internal_absolute_path",
-				"nested/symlink_internal_absolute_alias",
-				"nested/symlink_external",
-				"../foo",
-				"/etc/passwd",
-				"nonexistent",
-				".",
-			},
+		_, err := d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+			BuildId:         "ad778a53-48e6-4ae1-b1f5-01b84a508f5f",
+			PathPrefix:      "directory",
+			CleanPathPrefix: true,
 		})
 		require.NoError(t, err)
-		testutil.RequireEqualProto(t, &remoteoutputservice.BatchStatResponse{
-			Responses: []*remoteoutputservice.StatResponse{
-				// "file".
-				{
-					FileStatus: &remoteoutputservice.FileStatus{
-						FileType: &remoteoutputservice.FileStatus_File_{
-							File: &remoteoutputservice.FileStatus_File{
-								Digest: &remoteexecution.Digest{
-									Hash:      "ad17450bb18953f249532a478d2150ba",
-									SizeBytes: 72,
-								},
-							},
-						},
-					},
-				},
-				// "directory".
+	})
+
+	// Paths that resolve to locations outside the output path should
+	// be rejected before any directories are created. Calls to
+	// CreateAndEnterPrepopulatedDirectory() would cause the mocks to
+	// fail.
+
+	t.Run("PathPrefixOutsideOutputPath", func(t *testing.T) {
+		_, err := d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+			BuildId:    "ad778a53-48e6-4ae1-b1f5-01b84a508f5f",
+			PathPrefix: "a/b/../../..",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Failed to create path prefix directory: Path resolves to a location outside the output path"), err)
+	})
+
+	t.Run("FilePathOutsideOutputPath", func(t *testing.T) {
+		_, err := d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+			BuildId:    "ad778a53-48e6-4ae1-b1f5-01b84a508f5f",
+			PathPrefix: "some/sub",
+			Files: []*remoteexecution.OutputFile{
 				{
-					FileStatus: &remoteoutputservice.FileStatus{
-						FileType: &remoteoutputservice.FileStatus_Directory_{
-							Directory: &remoteoutputservice.FileStatus_Directory{
-								LastModifiedTime: &timestamppb.Timestamp{Seconds: 1000},
-							},
-						},
+					Path: "a/../../../../etc/passwd",
+					Digest: &remoteexecution.Digest{
+						Hash:      "d0ab620af7f3e77f3adfa190d41a25ce",
+						SizeBytes: 123,
 					},
 				},
-				// "nested/symlink_internal_relative_file".
+			},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Invalid path for file \"a/../../../../etc/passwd\": Path resolves to a location outside the output path"), err)
+	})
+
+	t.Run("DirectoryPathWithNullByte", func(t *testing.T) {
+		_, err := d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+			BuildId:    "ad778a53-48e6-4ae1-b1f5-01b84a508f5f",
+			PathPrefix: "some/sub",
+			Directories: []*remoteexecution.OutputDirectory{
 				{
-					FileStatus: &remoteoutputservice.FileStatus{
-						FileType: &remoteoutputservice.FileStatus_File_{
-							File: &remoteoutputservice.FileStatus_File{
-								Digest: &remoteexecution.Digest{
-									Hash:      "166d6efee3489f73be1c3c2304e50bca",
-									SizeBytes: 85,
-								},
-							},
-						},
+					Path: "a/b\x00c",
+					TreeDigest: &remoteexecution.Digest{
+						Hash:      "d0ab620af7f3e77f3adfa190d41a25ce",
+						SizeBytes: 123,
 					},
 				},
-				// "nested/symlink_internal_relative_directory".
+			},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Invalid path for directory \"a/b\\x00c\": Path contains a null byte"), err)
+	})
+
+	t.Run("AbsoluteSymlinkPath", func(t *testing.T) {
+		_, err := d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+			BuildId:    "ad778a53-48e6-4ae1-b1f5-01b84a508f5f",
+			PathPrefix: "some/sub",
+			Symlinks: []*remoteexecution.OutputSymlink{
 				{
-					FileStatus: &remoteoutputservice.FileStatus{
-						FileType: &remoteoutputservice.FileStatus_Directory_{
-							Directory: &remoteoutputservice.FileStatus_Directory{
-								LastModifiedTime: &timestamppb.Timestamp{Seconds: 1001},
-							},
-						},
-					},
+					Path:   "/tmp/foo",
+					Target: "target",
 				},
-				// "nested/symlink_internal_absolute_path".
+			},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Invalid path for symbolic link \"/tmp/foo\": Path is absolute, while a relative path was expected"), err)
+	})
+
+	t.Run("SymlinkCreationFailure", func(t *testing.T) {
+		symlink := mock.NewMockNativeLeaf(ctrl)
+		symlinkFactory.EXPECT().LookupSymlink([]byte("target")).Return(symlink)
+		outputPath.EXPECT().CreateChildren(map[path.Component]re_vfs.InitialNode{
+			path.MustNewComponent("foo"): re_vfs.InitialNode{}.FromLeaf(symlink),
+		}, true).Return(status.Error(codes.Internal, "I/O error"))
+		symlink.EXPECT().Unlink()
+
+		_, err := d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+			BuildId: "ad778a53-48e6-4ae1-b1f5-01b84a508f5f",
+			Symlinks: []*remoteexecution.OutputSymlink{
 				{
-					FileStatus: &remoteoutputservice.FileStatus{
-						FileType: &remoteoutputservice.FileStatus_Directory_{
-							Directory: &remoteoutputservice.FileStatus_Directory{
-								LastModifiedTime: &timestamppb.Timestamp{Seconds: 1002},
-							},
-						},
+					Path:   "foo",
+					Target: "target",
+				},
+			},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Failed to create symbolic link \"foo\": I/O error"), err)
+	})
+
+	t.Run("MaximumPathDepth", func(t *testing.T) {
+		// The number of directories traversed by the path
+		// prefix and the path of the entry combined should not
+		// exceed the configured limit.
+		directory := mock.NewMockPrepopulatedDirectory(ctrl)
+		outputPath.EXPECT().CreateAndEnterPrepopulatedDirectory(path.MustNewComponent("d")).Return(directory, nil)
+		directory.EXPECT().CreateAndEnterPrepopulatedDirectory(path.MustNewComponent("d")).Return(directory, nil).Times(99)
+		symlink := mock.NewMockNativeLeaf(ctrl)
+		symlinkFactory.EXPECT().LookupSymlink([]byte("target")).Return(symlink)
+		symlink.EXPECT().Unlink()
+
+		_, err := d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+			BuildId:    "ad778a53-48e6-4ae1-b1f5-01b84a508f5f",
+			PathPrefix: strings.Repeat("d/", 50),
+			Symlinks: []*remoteexecution.OutputSymlink{
+				{
+					Path:   strings.Repeat("d/", 51) + "foo",
+					Target: "target",
+				},
+			},
+		})
+		testutil.RequireEqualStatus(t, status.Errorf(codes.InvalidArgument, "Failed to create symbolic link %#v: Failed to resolve path: Path exceeds the maximum depth of 100 directories", strings.Repeat("d/", 51)+"foo"), err)
+	})
+
+	t.Run("InvalidModificationTime", func(t *testing.T) {
+		_, err := d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+			BuildId: "ad778a53-48e6-4ae1-b1f5-01b84a508f5f",
+			Files: []*remoteexecution.OutputFile{
+				{
+					Path: "file",
+					Digest: &remoteexecution.Digest{
+						Hash:      "d0ab620af7f3e77f3adfa190d41a25ce",
+						SizeBytes: 123,
+					},
+					NodeProperties: &remoteexecution.NodeProperties{
+						Mtime: &timestamppb.Timestamp{Seconds: -1},
 					},
 				},
-				// "nested/symlink_internal_absolute_alias".
+			},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Invalid node properties for file \"file\": Modification time precedes the Unix epoch"), err)
+	})
+
+	t.Run("ModificationTime", func(t *testing.T) {
+		// If a modification time is provided, it should be
+		// reported by the file that is created.
+		casFileHandleAllocation := mock.NewMockStatelessHandleAllocation(ctrl)
+		casFileHandleAllocator.EXPECT().New(gomock.Any()).Return(casFileHandleAllocation)
+		file := mock.NewMockNativeLeaf(ctrl)
+		casFileHandleAllocation.EXPECT().AsNativeLeaf(gomock.Any()).Return(file)
+		var createdLeaf re_vfs.NativeLeaf
+		outputPath.EXPECT().CreateChildren(gomock.Any(), true).
+			DoAndReturn(func(children map[path.Component]re_vfs.InitialNode, overwrite bool) error {
+				_, createdLeaf = children[path.MustNewComponent("file")].GetPair()
+				return nil
+			})
+
+		_, err := d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+			BuildId: "ad778a53-48e6-4ae1-b1f5-01b84a508f5f",
+			Files: []*remoteexecution.OutputFile{
 				{
-					FileStatus: &remoteoutputservice.FileStatus{
-						FileType: &remoteoutputservice.FileStatus_Directory_{
-							Directory: &remoteoutputservice.FileStatus_Directory{
-								LastModifiedTime: &timestamppb.Timestamp{Seconds: 1003},
-							},
-						},
+					Path: "file",
+					Digest: &remoteexecution.Digest{
+						Hash:      "d0ab620af7f3e77f3adfa190d41a25ce",
+						SizeBytes: 123,
+					},
+					NodeProperties: &remoteexecution.NodeProperties{
+						Mtime: &timestamppb.Timestamp{Seconds: 315532800},
 					},
 				},
-				// "nested/symlink_external".
+			},
+		})
+		require.NoError(t, err)
+
+		file.EXPECT().VirtualGetAttributes(ctx, re_vfs.AttributesMaskLastDataModificationTime, gomock.Any()).
+			Do(func(ctx context.Context, requested re_vfs.AttributesMask, attributes *re_vfs.Attributes) {
+				attributes.SetLastDataModificationTime(time.Unix(1000, 0))
+			})
+
+		var attributes re_vfs.Attributes
+		createdLeaf.VirtualGetAttributes(ctx, re_vfs.AttributesMaskLastDataModificationTime, &attributes)
+		require.Equal(t, (&re_vfs.Attributes{}).SetLastDataModificationTime(time.Unix(315532800, 0).UTC()), &attributes)
+	})
+
+	t.Run("DirectoryTooBig", func(t *testing.T) {
+		// We should forbid the creation of directories in the
+		// output directory that are too big, as attempting to
+		// access those would fail anyway.
+		_, err := d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+			BuildId: "ad778a53-48e6-4ae1-b1f5-01b84a508f5f",
+			Directories: []*remoteexecution.OutputDirectory{
 				{
-					FileStatus: &remoteoutputservice.FileStatus{
-						FileType: &remoteoutputservice.FileStatus_External_{
-							External: &remoteoutputservice.FileStatus_External{
-								NextPath: "/etc/passwd",
-							},
-						},
+					Path: "large_directory",
+					TreeDigest: &remoteexecution.Digest{
+						Hash:      "b2bc8901bd2dfc25e0e43f0a1eaf8758",
+						SizeBytes: 9999999,
 					},
 				},
-				// "../foo".
+			},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Directory \"large_directory\" is 9999999 bytes in size, which exceeds the permitted maximum of 10000 bytes"), err)
+	})
+
+	// The creation of actual files and directories is hard to test,
+	// as the InitialNode arguments provided to CreateChildren()
+	// contain objects that are hard to compare. At least provide a
+	// test for the success case.
+
+	t.Run("Success", func(t *testing.T) {
+		child1 := mock.NewMockPrepopulatedDirectory(ctrl)
+		outputPath.EXPECT().CreateAndEnterPrepopulatedDirectory(path.MustNewComponent("a")).
+			Return(child1, nil)
+
+		child2 := mock.NewMockPrepopulatedDirectory(ctrl)
+		child1.EXPECT().CreateAndEnterPrepopulatedDirectory(path.MustNewComponent("b")).
+			Return(child2, nil).Times(3)
+
+		// Creation of "file".
+		casFileHandleAllocation := mock.NewMockStatelessHandleAllocation(ctrl)
+		casFileHandleAllocator.EXPECT().New(gomock.Any()).Return(casFileHandleAllocation)
+		file := mock.NewMockNativeLeaf(ctrl)
+		casFileHandleAllocation.EXPECT().AsNativeLeaf(gomock.Any()).Return(file)
+		var createdFile re_vfs.NativeLeaf
+		child2.EXPECT().CreateChildren(gomock.Any(), true).
+			DoAndReturn(func(children map[path.Component]re_vfs.InitialNode, overwrite bool) error {
+				_, createdFile = children[path.MustNewComponent("file")].GetPair()
+				return nil
+			})
+
+		// Creation of "directory".
+		child2.EXPECT().CreateChildren(gomock.Any(), true)
+
+		// Creation of "symlink".
+		symlink := mock.NewMockNativeLeaf(ctrl)
+		symlinkFactory.EXPECT().LookupSymlink([]byte("file")).Return(symlink)
+		child2.EXPECT().CreateChildren(map[path.Component]re_vfs.InitialNode{
+			path.MustNewComponent("symlink"): re_vfs.InitialNode{}.FromLeaf(symlink),
+		}, true)
+
+		_, err := d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+			BuildId:    "ad778a53-48e6-4ae1-b1f5-01b84a508f5f",
+			PathPrefix: "a",
+			Files: []*remoteexecution.OutputFile{
 				{
-					FileStatus: &remoteoutputservice.FileStatus{
-						FileType: &remoteoutputservice.FileStatus_External_{
-							External: &remoteoutputservice.FileStatus_External{
-								NextPath: "../foo",
-							},
-						},
+					Path:         "b/file",
+					IsExecutable: true,
+					Digest: &remoteexecution.Digest{
+						Hash:      "d0ab620af7f3e77f3adfa190d41a25ce",
+						SizeBytes: 123,
 					},
 				},
-				// "/etc/passwd".
+			},
+			Directories: []*remoteexecution.OutputDirectory{
 				{
-					FileStatus: &remoteoutputservice.FileStatus{
-						FileType: &remoteoutputservice.FileStatus_External_{
-							External: &remoteoutputservice.FileStatus_External{
-								NextPath: "/etc/passwd",
-							},
-						},
+					Path: "b/directory",
+					TreeDigest: &remoteexecution.Digest{
+						Hash:      "8e1554fc1ad824a6e9180c7b145790d2",
+						SizeBytes: 123,
 					},
 				},
-				// "nonexistent".
-				{},
-				// ".".
+			},
+			Symlinks: []*remoteexecution.OutputSymlink{
+				{
+					Path:   "b/symlink",
+					Target: "file",
+				},
+			},
+		})
+		require.NoError(t, err)
+
+		// The file that was created should be backed by the
+		// file returned by the handle allocator.
+		file.EXPECT().Unlink()
+		createdFile.Unlink()
+	})
+}
+
+func TestRemoteOutputServiceDirectoryBestEffortBatchCreate(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	handleAllocator := mock.NewMockStatefulHandleAllocator(ctrl)
+	outputPathFactory := mock.NewMockOutputPathFactory(ctrl)
+	bareContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	retryingContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	directoryFetcher := mock.NewMockDirectoryFetcher(ctrl)
+	symlinkFactory := mock.NewMockSymlinkFactory(ctrl)
+	dHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	handleAllocator.EXPECT().New().Return(dHandleAllocation)
+	dHandle := mock.NewMockStatefulDirectoryHandle(ctrl)
+	dHandleAllocation.EXPECT().AsStatefulDirectory(gomock.Any()).Return(dHandle)
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(1000, 0)).AnyTimes()
+	d := newTestRemoteOutputServiceDirectory(
+		handleAllocator,
+		outputPathFactory,
+		bareContentAddressableStorage,
+		retryingContentAddressableStorage,
+		directoryFetcher,
+		symlinkFactory,
+		allowAllAuthorizer,
+		clock,
+		cd_vfs.RemoteOutputServiceDirectoryOptions{})
+
+	t.Run("InvalidBuildID", func(t *testing.T) {
+		// StartBuild() should be called first.
+		_, err := d.BestEffortBatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+			BuildId: "ad778a53-48e6-4ae1-b1f5-01b84a508f5f",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.FailedPrecondition, "Build ID is not associated with any running build"), err)
+	})
+
+	// Let the remainder of the tests assume that a build is running.
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocator := mock.NewMockStatelessHandleAllocator(ctrl)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(casFileHandleAllocator)
+	outputPath := mock.NewMockOutputPath(ctrl)
+	outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("c6adef0d5ca1888a4aa847fb51229a8c"),
+		gomock.Any(),
+		digest.MustNewFunction("my-cluster", remoteexecution.DigestFunction_MD5),
+		gomock.Any(),
+	).Return(outputPath)
+	outputPath.EXPECT().FilterChildren(gomock.Any())
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "c6adef0d5ca1888a4aa847fb51229a8c",
+		BuildId:          "ad778a53-48e6-4ae1-b1f5-01b84a508f5f",
+		InstanceName:     "my-cluster",
+		DigestFunction:   remoteexecution.DigestFunction_MD5,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+
+	t.Run("PathPrefixCreationFailure", func(t *testing.T) {
+		// Failures to create the path prefix directory should
+		// cause the request to fail as a whole.
+		outputPath.EXPECT().CreateAndEnterPrepopulatedDirectory(path.MustNewComponent("a")).
+			Return(nil, status.Error(codes.Internal, "I/O error"))
+
+		_, err := d.BestEffortBatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+			BuildId:    "ad778a53-48e6-4ae1-b1f5-01b84a508f5f",
+			PathPrefix: "a",
+			Symlinks: []*remoteexecution.OutputSymlink{
+				{
+					Path:   "symlink",
+					Target: "target",
+				},
+			},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Failed to create path prefix directory: I/O error"), err)
+	})
+
+	t.Run("EmptyRequest", func(t *testing.T) {
+		// Requests without any entries should not cause the
+		// path prefix directory to be created.
+		response, err := d.BestEffortBatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+			BuildId:    "ad778a53-48e6-4ae1-b1f5-01b84a508f5f",
+			PathPrefix: "a",
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &outputpaths.BestEffortBatchCreateResponse{}, response)
+	})
+
+	t.Run("PartialFailure", func(t *testing.T) {
+		// Failures to create individual entries should be
+		// reported, but not prevent other entries from being
+		// created.
+		casFileHandleAllocation := mock.NewMockStatelessHandleAllocation(ctrl)
+		casFileHandleAllocator.EXPECT().New(gomock.Any()).Return(casFileHandleAllocation)
+		file := mock.NewMockNativeLeaf(ctrl)
+		casFileHandleAllocation.EXPECT().AsNativeLeaf(gomock.Any()).Return(file)
+		var createdFile re_vfs.NativeLeaf
+		outputPath.EXPECT().CreateChildren(gomock.Any(), true).
+			DoAndReturn(func(children map[path.Component]re_vfs.InitialNode, overwrite bool) error {
+				_, createdFile = children[path.MustNewComponent("file")].GetPair()
+				return nil
+			})
+
+		symlink := mock.NewMockNativeLeaf(ctrl)
+		symlinkFactory.EXPECT().LookupSymlink([]byte("file")).Return(symlink)
+		outputPath.EXPECT().CreateChildren(map[path.Component]re_vfs.InitialNode{
+			path.MustNewComponent("symlink"): re_vfs.InitialNode{}.FromLeaf(symlink),
+		}, true).Return(status.Error(codes.Internal, "I/O error"))
+		symlink.EXPECT().Unlink()
+
+		response, err := d.BestEffortBatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+			BuildId: "ad778a53-48e6-4ae1-b1f5-01b84a508f5f",
+			Files: []*remoteexecution.OutputFile{
+				{
+					Path: "../etc/passwd",
+					Digest: &remoteexecution.Digest{
+						Hash:      "d0ab620af7f3e77f3adfa190d41a25ce",
+						SizeBytes: 123,
+					},
+				},
+				{
+					Path: "file",
+					Digest: &remoteexecution.Digest{
+						Hash:      "d0ab620af7f3e77f3adfa190d41a25ce",
+						SizeBytes: 123,
+					},
+				},
+			},
+			Directories: []*remoteexecution.OutputDirectory{
+				{
+					Path: "large_directory",
+					TreeDigest: &remoteexecution.Digest{
+						Hash:      "b2bc8901bd2dfc25e0e43f0a1eaf8758",
+						SizeBytes: 9999999,
+					},
+				},
+			},
+			Symlinks: []*remoteexecution.OutputSymlink{
+				{
+					Path:   "symlink",
+					Target: "file",
+				},
+			},
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &outputpaths.BestEffortBatchCreateResponse{
+			Files: []*status_pb.Status{
+				status.New(codes.InvalidArgument, "Invalid path for file \"../etc/passwd\": Path resolves to a location outside the output path").Proto(),
+				{},
+			},
+			Directories: []*status_pb.Status{
+				status.New(codes.InvalidArgument, "Directory \"large_directory\" is 9999999 bytes in size, which exceeds the permitted maximum of 10000 bytes").Proto(),
+			},
+			Symlinks: []*status_pb.Status{
+				status.New(codes.Internal, "Failed to create symbolic link \"symlink\": I/O error").Proto(),
+			},
+		}, response)
+
+		file.EXPECT().Unlink()
+		createdFile.Unlink()
+	})
+
+	t.Run("DigestFunctionMismatch", func(t *testing.T) {
+		// Digests whose hash length corresponds to a different
+		// digest function than the one used by the build
+		// should be rejected, mentioning both digest
+		// functions.
+		response, err := d.BestEffortBatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+			BuildId: "ad778a53-48e6-4ae1-b1f5-01b84a508f5f",
+			Files: []*remoteexecution.OutputFile{
+				{
+					Path: "sha256_file",
+					Digest: &remoteexecution.Digest{
+						Hash:      "8ca5bb1eb3b5ad0c6a2a1c6c4c6a4c0f3c7e5b2a0fc0a5b4c1b0d9e8f7a6b5c4",
+						SizeBytes: 123,
+					},
+				},
+				{
+					Path: "truncated_file",
+					Digest: &remoteexecution.Digest{
+						Hash:      "d0ab620af7f3e77f3adfa190d41a25c",
+						SizeBytes: 123,
+					},
+				},
+			},
+			Directories: []*remoteexecution.OutputDirectory{
+				{
+					Path: "sha1_directory",
+					TreeDigest: &remoteexecution.Digest{
+						Hash:      "a9993e364706816aba3e25717850c26c9cd0d89d",
+						SizeBytes: 456,
+					},
+				},
+			},
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &outputpaths.BestEffortBatchCreateResponse{
+			Files: []*status_pb.Status{
+				status.New(codes.InvalidArgument, "Invalid digest for file \"sha256_file\": Hash has length 64, which corresponds to digest function SHA256, while the build uses digest function MD5").Proto(),
+				status.New(codes.InvalidArgument, "Invalid digest for file \"truncated_file\": Hash has length 31, while 32 characters were expected").Proto(),
+			},
+			Directories: []*status_pb.Status{
+				status.New(codes.InvalidArgument, "Invalid digest for directory \"sha1_directory\": Hash has length 40, which corresponds to digest function SHA1, while the build uses digest function MD5").Proto(),
+			},
+			Symlinks: []*status_pb.Status{},
+		}, response)
+	})
+}
+
+func TestRemoteOutputServiceDirectoryBatchStat(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	handleAllocator := mock.NewMockStatefulHandleAllocator(ctrl)
+	outputPathFactory := mock.NewMockOutputPathFactory(ctrl)
+	bareContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	retryingContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	directoryFetcher := mock.NewMockDirectoryFetcher(ctrl)
+	symlinkFactory := mock.NewMockSymlinkFactory(ctrl)
+	dHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	handleAllocator.EXPECT().New().Return(dHandleAllocation)
+	dHandle := mock.NewMockStatefulDirectoryHandle(ctrl)
+	dHandleAllocation.EXPECT().AsStatefulDirectory(gomock.Any()).Return(dHandle)
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(1000, 0)).AnyTimes()
+	d := newTestRemoteOutputServiceDirectory(
+		handleAllocator,
+		outputPathFactory,
+		bareContentAddressableStorage,
+		retryingContentAddressableStorage,
+		directoryFetcher,
+		symlinkFactory,
+		allowAllAuthorizer,
+		clock,
+		cd_vfs.RemoteOutputServiceDirectoryOptions{})
+
+	t.Run("InvalidBuildID", func(t *testing.T) {
+		// StartBuild() should be called first.
+		_, err := d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
+			BuildId: "140dbef8-1b24-4966-bb9e-8edc7fa61df8",
+			Paths:   []string{"foo.o"},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.FailedPrecondition, "Build ID is not associated with any running build"), err)
+	})
+
+	// Let the remainder of the tests assume that a build is running.
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocator := mock.NewMockStatelessHandleAllocator(ctrl)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(casFileHandleAllocator)
+	outputPath := mock.NewMockOutputPath(ctrl)
+	outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+		gomock.Any(),
+		digest.MustNewFunction("my-cluster", remoteexecution.DigestFunction_MD5),
+		gomock.Any(),
+	).Return(outputPath)
+	outputPath.EXPECT().FilterChildren(gomock.Any())
+
+	response, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		InstanceName:     "my-cluster",
+		DigestFunction:   remoteexecution.DigestFunction_MD5,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+		OutputPathAliases: map[string]string{
+			"/home/bob/.cache/bazel/_bazel_bob/9da951b8cb759233037166e28f7ea186/execroot/myproject/bazel-out": ".",
+		},
+	})
+	require.NoError(t, err)
+	testutil.RequireEqualProto(t, &remoteoutputservice.StartBuildResponse{
+		OutputPathSuffix: "9da951b8cb759233037166e28f7ea186",
+	}, response)
+
+	t.Run("Noop", func(t *testing.T) {
+		// Requests that don't contain any paths shouldn't cause
+		// any I/O against the output path.
+		_, err := d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("OnDirectoryLookupFailure", func(t *testing.T) {
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("stdio")).Return(re_vfs.PrepopulatedDirectoryChild{}, status.Error(codes.Internal, "Disk failure"))
+
+		_, err := d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Paths:   []string{"stdio/printf.o"},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Failed to resolve path \"stdio/printf.o\" beyond \".\": Disk failure"), err)
+	})
+
+	t.Run("OnDirectoryReadlinkFailure", func(t *testing.T) {
+		leaf := mock.NewMockNativeLeaf(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("stdio")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(leaf), nil)
+		leaf.EXPECT().Readlink().Return("", status.Error(codes.Internal, "Disk failure"))
+
+		_, err := d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Paths:   []string{"stdio/printf.o"},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Failed to resolve path \"stdio/printf.o\" beyond \".\": Disk failure"), err)
+	})
+
+	t.Run("OnTerminalLookupFailure", func(t *testing.T) {
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("printf.o")).Return(re_vfs.PrepopulatedDirectoryChild{}, status.Error(codes.Internal, "Disk failure"))
+
+		_, err := d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Paths:   []string{"printf.o"},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Failed to resolve path \"printf.o\" beyond \".\": Disk failure"), err)
+	})
+
+	t.Run("OnTerminalReadlinkFailure", func(t *testing.T) {
+		leaf := mock.NewMockNativeLeaf(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("printf.o")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(leaf), nil)
+		leaf.EXPECT().Readlink().Return("", status.Error(codes.Internal, "Disk failure"))
+
+		_, err := d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
+			BuildId:        "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			FollowSymlinks: true,
+			Paths:          []string{"printf.o"},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Failed to resolve path \"printf.o\" beyond \".\": Disk failure"), err)
+	})
+
+	t.Run("OnTerminalGetOutputServiceFileStatusFailure", func(t *testing.T) {
+		leaf := mock.NewMockNativeLeaf(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("printf.o")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(leaf), nil)
+		leaf.EXPECT().GetOutputServiceFileStatus(gomock.Any()).Return(nil, status.Error(codes.Internal, "Disk failure"))
+
+		_, err := d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Paths:   []string{"printf.o"},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Failed to resolve path \"printf.o\" beyond \".\": Disk failure"), err)
+	})
+
+	t.Run("SymlinkLoop", func(t *testing.T) {
+		// Symbolic links pointing to themselves should only be
+		// expanded up to the configured limit. Beyond that, the
+		// path should be returned to the client unexpanded, so
+		// that stat() on the client side fails with ELOOP.
+		leaf := mock.NewMockNativeLeaf(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("loop")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(leaf), nil).Times(82)
+		leaf.EXPECT().Readlink().Return("loop", nil).Times(82)
+
+		response, err := d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
+			BuildId:        "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			FollowSymlinks: true,
+			Paths: []string{
+				"loop",
+				"loop/foo",
+			},
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &remoteoutputservice.BatchStatResponse{
+			Responses: []*remoteoutputservice.StatResponse{
+				{
+					FileStatus: &remoteoutputservice.FileStatus{
+						FileType: &remoteoutputservice.FileStatus_External_{
+							External: &remoteoutputservice.FileStatus_External{
+								NextPath: "loop",
+							},
+						},
+					},
+				},
+				{
+					FileStatus: &remoteoutputservice.FileStatus{
+						FileType: &remoteoutputservice.FileStatus_External_{
+							External: &remoteoutputservice.FileStatus_External{
+								NextPath: "loop/foo",
+							},
+						},
+					},
+				},
+			},
+		}, response)
+	})
+
+	t.Run("MaximumPathDepth", func(t *testing.T) {
+		// Paths traversing more than the configured number of
+		// directories should be rejected.
+		directory := mock.NewMockPrepopulatedDirectory(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("d")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(directory), nil)
+		directory.EXPECT().LookupChild(path.MustNewComponent("d")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(directory), nil).Times(100)
+
+		deepPath := strings.Repeat("d/", 101) + "file"
+		_, err := d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Paths:   []string{deepPath},
+		})
+		testutil.RequireEqualStatus(t, status.Errorf(codes.InvalidArgument, "Failed to resolve path %#v beyond %#v: Path exceeds the maximum depth of 100 directories", deepPath, strings.Repeat("d/", 100)), err)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		// Lookup of "file", pointing to directly to a file.
+		leaf1 := mock.NewMockNativeLeaf(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("file")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(leaf1), nil)
+		leaf1.EXPECT().Readlink().Return("", syscall.EINVAL)
+		leaf1.EXPECT().GetOutputServiceFileStatus(gomock.Any()).Return(&remoteoutputservice.FileStatus{
+			FileType: &remoteoutputservice.FileStatus_File_{
+				File: &remoteoutputservice.FileStatus_File{
+					Digest: &remoteexecution.Digest{
+						Hash:      "ad17450bb18953f249532a478d2150ba",
+						SizeBytes: 72,
+					},
+				},
+			},
+		}, nil)
+
+		// Lookup of "directory". pointing directly to a directory.
+		directory1 := mock.NewMockPrepopulatedDirectory(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("directory")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(directory1), nil)
+		directory1.EXPECT().VirtualGetAttributes(ctx, re_vfs.AttributesMaskLastDataModificationTime, gomock.Any()).
+			Do(func(ctx context.Context, requested re_vfs.AttributesMask, attributes *re_vfs.Attributes) {
+				attributes.SetLastDataModificationTime(time.Unix(1000, 0))
+			})
+
+		// Lookup of "nested/symlink_internal_relative_file",
+		// being a symlink that points to a file.
+		directory2 := mock.NewMockPrepopulatedDirectory(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("nested")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(directory2), nil)
+		leaf2 := mock.NewMockNativeLeaf(ctrl)
+		directory2.EXPECT().LookupChild(path.MustNewComponent("symlink_internal_relative_file")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(leaf2), nil)
+		leaf2.EXPECT().Readlink().Return("../target", nil)
+		leaf3 := mock.NewMockNativeLeaf(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("target")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(leaf3), nil)
+		leaf3.EXPECT().Readlink().Return("", syscall.EINVAL)
+		leaf3.EXPECT().GetOutputServiceFileStatus(gomock.Any()).Return(&remoteoutputservice.FileStatus{
+			FileType: &remoteoutputservice.FileStatus_File_{
+				File: &remoteoutputservice.FileStatus_File{
+					Digest: &remoteexecution.Digest{
+						Hash:      "166d6efee3489f73be1c3c2304e50bca",
+						SizeBytes: 85,
+					},
+				},
+			},
+		}, nil)
+
+		// Lookup of "nested/symlink_internal_relative_directory",
+		// being a symlink that points to a directory.
+		directory3 := mock.NewMockPrepopulatedDirectory(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("nested")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(directory3), nil)
+		leaf4 := mock.NewMockNativeLeaf(ctrl)
+		directory3.EXPECT().LookupChild(path.MustNewComponent("symlink_internal_relative_directory")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(leaf4), nil)
+		leaf4.EXPECT().Readlink().Return("..", nil)
+		outputPath.EXPECT().VirtualGetAttributes(ctx, re_vfs.AttributesMaskLastDataModificationTime, gomock.Any()).
+			Do(func(ctx context.Context, requested re_vfs.AttributesMask, attributes *re_vfs.Attributes) {
+				attributes.SetLastDataModificationTime(time.Unix(1001, 0))
+			})
+
+		// Lookup of "nested/symlink_internal_absolute_path",
+		// being a symlink containing an absolute path starting
+		// with the output path.
+		directory4 := mock.NewMockPrepopulatedDirectory(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("nested")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(directory4), nil)
+		leaf5 := mock.NewMockNativeLeaf(ctrl)
+		directory4.EXPECT().LookupChild(path.MustNewComponent("symlink_internal_absolute_path")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(leaf5), nil)
+		leaf5.EXPECT().Readlink().Return("/home/bob/bb_clientd/outputs/9da951b8cb759233037166e28f7ea186/hello", nil)
+		directory5 := mock.NewMockPrepopulatedDirectory(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("hello")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(directory5), nil)
+		directory5.EXPECT().VirtualGetAttributes(ctx, re_vfs.AttributesMaskLastDataModificationTime, gomock.Any()).
+			Do(func(ctx context.Context, requested re_vfs.AttributesMask, attributes *re_vfs.Attributes) {
+				attributes.SetLastDataModificationTime(time.Unix(1002, 0))
+			})
+
+		// Lookup of "nested/symlink_internal_absolute_alias",
+		// being a symlink containing an absolute path starting
+		// with one of the output path aliases.
+		directory6 := mock.NewMockPrepopulatedDirectory(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("nested")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(directory6), nil)
+		leaf6 := mock.NewMockNativeLeaf(ctrl)
+		directory6.EXPECT().LookupChild(path.MustNewComponent("symlink_internal_absolute_alias")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(leaf6), nil)
+		leaf6.EXPECT().Readlink().Return("/home/bob/.cache/bazel/_bazel_bob/9da951b8cb759233037166e28f7ea186/execroot/myproject/bazel-out/hello", nil)
+		directory7 := mock.NewMockPrepopulatedDirectory(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("hello")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(directory7), nil)
+		directory7.EXPECT().VirtualGetAttributes(ctx, re_vfs.AttributesMaskLastDataModificationTime, gomock.Any()).
+			Do(func(ctx context.Context, requested re_vfs.AttributesMask, attributes *re_vfs.Attributes) {
+				attributes.SetLastDataModificationTime(time.Unix(1003, 0))
+			})
+
+		// Lookup of "nested/symlink_external", being a symlink
+		// containing an absolute path that doesn't start with
+		// any known prefix.
+		directory8 := mock.NewMockPrepopulatedDirectory(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("nested")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(directory8), nil)
+		leaf7 := mock.NewMockNativeLeaf(ctrl)
+		directory8.EXPECT().LookupChild(path.MustNewComponent("symlink_external")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(leaf7), nil)
+		leaf7.EXPECT().Readlink().Return("/etc/passwd", nil)
+
+		// Lookup of "nonexistent".
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("nonexistent")).Return(re_vfs.PrepopulatedDirectoryChild{}, syscall.ENOENT)
+
+		// Lookup of ".".
+		outputPath.EXPECT().VirtualGetAttributes(ctx, re_vfs.AttributesMaskLastDataModificationTime, gomock.Any()).
+			Do(func(ctx context.Context, requested re_vfs.AttributesMask, attributes *re_vfs.Attributes) {
+				attributes.SetLastDataModificationTime(time.Unix(1004, 0))
+			})
+
+		response, err := d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
+			BuildId:           "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			IncludeFileDigest: true,
+			FollowSymlinks:    true,
+			Paths: []string{
+				"file",
+				"directory",
+				"nested/symlink_internal_relative_file",
+				"nested/symlink_internal_relative_directory",
+				"nested/symlink_internal_absolute_path",
+				"nested/symlink_internal_absolute_alias",
+				"nested/symlink_external",
+				"../foo",
+				"/etc/passwd",
+				"nonexistent",
+				".",
+			},
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &remoteoutputservice.BatchStatResponse{
+			Responses: []*remoteoutputservice.StatResponse{
+				// "file".
+				{
+					FileStatus: &remoteoutputservice.FileStatus{
+						FileType: &remoteoutputservice.FileStatus_File_{
+							File: &remoteoutputservice.FileStatus_File{
+								Digest: &remoteexecution.Digest{
+									Hash:      "ad17450bb18953f249532a478d2150ba",
+									SizeBytes: 72,
+								},
+							},
+						},
+					},
+				},
+				// "directory".
+				{
+					FileStatus: &remoteoutputservice.FileStatus{
+						FileType: &remoteoutputservice.FileStatus_Directory_{
+							Directory: &remoteoutputservice.FileStatus_Directory{
+								LastModifiedTime: &timestamppb.Timestamp{Seconds: 1000},
+							},
+						},
+					},
+				},
+				// "nested/symlink_internal_relative_file".
+				{
+					FileStatus: &remoteoutputservice.FileStatus{
+						FileType: &remoteoutputservice.FileStatus_File_{
+							File: &remoteoutputservice.FileStatus_File{
+								Digest: &remoteexecution.Digest{
+									Hash:      "166d6efee3489f73be1c3c2304e50bca",
+									SizeBytes: 85,
+								},
+							},
+						},
+					},
+				},
+				// "nested/symlink_internal_relative_directory".
+				{
+					FileStatus: &remoteoutputservice.FileStatus{
+						FileType: &remoteoutputservice.FileStatus_Directory_{
+							Directory: &remoteoutputservice.FileStatus_Directory{
+								LastModifiedTime: &timestamppb.Timestamp{Seconds: 1001},
+							},
+						},
+					},
+				},
+				// "nested/symlink_internal_absolute_path".
+				{
+					FileStatus: &remoteoutputservice.FileStatus{
+						FileType: &remoteoutputservice.FileStatus_Directory_{
+							Directory: &remoteoutputservice.FileStatus_Directory{
+								LastModifiedTime: &timestamppb.Timestamp{Seconds: 1002},
+							},
+						},
+					},
+				},
+				// "nested/symlink_internal_absolute_alias".
+				{
+					FileStatus: &remoteoutputservice.FileStatus{
+						FileType: &remoteoutputservice.FileStatus_Directory_{
+							Directory: &remoteoutputservice.FileStatus_Directory{
+								LastModifiedTime: &timestamppb.Timestamp{Seconds: 1003},
+							},
+						},
+					},
+				},
+				// "nested/symlink_external".
+				{
+					FileStatus: &remoteoutputservice.FileStatus{
+						FileType: &remoteoutputservice.FileStatus_External_{
+							External: &remoteoutputservice.FileStatus_External{
+								NextPath: "/etc/passwd",
+							},
+						},
+					},
+				},
+				// "../foo".
+				{
+					FileStatus: &remoteoutputservice.FileStatus{
+						FileType: &remoteoutputservice.FileStatus_External_{
+							External: &remoteoutputservice.FileStatus_External{
+								NextPath: "../foo",
+							},
+						},
+					},
+				},
+				// "/etc/passwd".
+				{
+					FileStatus: &remoteoutputservice.FileStatus{
+						FileType: &remoteoutputservice.FileStatus_External_{
+							External: &remoteoutputservice.FileStatus_External{
+								NextPath: "/etc/passwd",
+							},
+						},
+					},
+				},
+				// "nonexistent".
+				{},
+				// ".".
+				{
+					FileStatus: &remoteoutputservice.FileStatus{
+						FileType: &remoteoutputservice.FileStatus_Directory_{
+							Directory: &remoteoutputservice.FileStatus_Directory{
+								LastModifiedTime: &timestamppb.Timestamp{Seconds: 1004},
+							},
+						},
+					},
+				},
+			},
+		}, response)
+	})
+}
+
+func TestRemoteOutputServiceDirectoryExtendedBatchStat(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	handleAllocator := mock.NewMockStatefulHandleAllocator(ctrl)
+	outputPathFactory := mock.NewMockOutputPathFactory(ctrl)
+	bareContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	retryingContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	directoryFetcher := mock.NewMockDirectoryFetcher(ctrl)
+	symlinkFactory := mock.NewMockSymlinkFactory(ctrl)
+	dHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	handleAllocator.EXPECT().New().Return(dHandleAllocation)
+	dHandle := mock.NewMockStatefulDirectoryHandle(ctrl)
+	dHandleAllocation.EXPECT().AsStatefulDirectory(gomock.Any()).Return(dHandle)
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(1000, 0)).AnyTimes()
+	d := newTestRemoteOutputServiceDirectory(
+		handleAllocator,
+		outputPathFactory,
+		bareContentAddressableStorage,
+		retryingContentAddressableStorage,
+		directoryFetcher,
+		symlinkFactory,
+		allowAllAuthorizer,
+		clock,
+		cd_vfs.RemoteOutputServiceDirectoryOptions{})
+
+	t.Run("MissingRequest", func(t *testing.T) {
+		_, err := d.ExtendedBatchStat(ctx, &outputpaths.ExtendedBatchStatRequest{})
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "No BatchStat() request provided"), err)
+	})
+
+	// Let the remainder of the tests assume that a build is running.
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocator := mock.NewMockStatelessHandleAllocator(ctrl)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(casFileHandleAllocator)
+	outputPath := mock.NewMockOutputPath(ctrl)
+	outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+		gomock.Any(),
+		digest.MustNewFunction("my-cluster", remoteexecution.DigestFunction_MD5),
+		gomock.Any(),
+	).Return(outputPath)
+	outputPath.EXPECT().FilterChildren(gomock.Any())
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		InstanceName:     "my-cluster",
+		DigestFunction:   remoteexecution.DigestFunction_MD5,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+
+	directoryStatus := &remoteoutputservice.FileStatus{
+		FileType: &remoteoutputservice.FileStatus_Directory_{
+			Directory: &remoteoutputservice.FileStatus_Directory{
+				LastModifiedTime: &timestamppb.Timestamp{Seconds: 1000},
+			},
+		},
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		// Request the status of the same directory twice. The
+		// digest of its contents should only be computed once.
+		directory := mock.NewMockPrepopulatedDirectory(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("directory")).
+			Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(directory), nil).
+			Times(2)
+		directory.EXPECT().VirtualGetAttributes(ctx, re_vfs.AttributesMaskLastDataModificationTime, gomock.Any()).
+			Do(func(ctx context.Context, requested re_vfs.AttributesMask, attributes *re_vfs.Attributes) {
+				attributes.SetLastDataModificationTime(time.Unix(1000, 0))
+			}).
+			Times(2)
+
+		subdirectory := mock.NewMockPrepopulatedDirectory(ctrl)
+		file := mock.NewMockNativeLeaf(ctrl)
+		symlink := mock.NewMockNativeLeaf(ctrl)
+		directory.EXPECT().LookupAllChildren().Return(
+			[]re_vfs.DirectoryPrepopulatedDirEntry{
+				{Name: path.MustNewComponent("subdirectory"), Child: subdirectory},
+			},
+			[]re_vfs.LeafPrepopulatedDirEntry{
+				{Name: path.MustNewComponent("file"), Child: file},
+				{Name: path.MustNewComponent("symlink"), Child: symlink},
+			},
+			nil)
+		subdirectory.EXPECT().LookupAllChildren()
+		file.EXPECT().Readlink().Return("", syscall.EINVAL)
+		file.EXPECT().GetOutputServiceFileStatus(gomock.Any()).Return(&remoteoutputservice.FileStatus{
+			FileType: &remoteoutputservice.FileStatus_File_{
+				File: &remoteoutputservice.FileStatus_File{
+					Digest: &remoteexecution.Digest{
+						Hash:      "ad17450bb18953f249532a478d2150ba",
+						SizeBytes: 72,
+					},
+				},
+			},
+		}, nil)
+		file.EXPECT().GetContainingDigests().
+			Return(digest.MustNewDigest("my-cluster", remoteexecution.DigestFunction_MD5, "ad17450bb18953f249532a478d2150ba", 72).ToSingletonSet())
+		file.EXPECT().VirtualGetAttributes(ctx, re_vfs.AttributesMaskPermissions, gomock.Any()).
+			Do(func(ctx context.Context, requested re_vfs.AttributesMask, attributes *re_vfs.Attributes) {
+				attributes.SetPermissions(re_vfs.PermissionsRead | re_vfs.PermissionsExecute)
+			})
+		symlink.EXPECT().Readlink().Return("file", nil)
+
+		response, err := d.ExtendedBatchStat(ctx, &outputpaths.ExtendedBatchStatRequest{
+			Request: &remoteoutputservice.BatchStatRequest{
+				BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+				Paths:   []string{"directory", "directory"},
+			},
+			IncludeDirectoryDigest: true,
+		})
+		require.NoError(t, err)
+
+		// The digest should correspond to that of an REv2
+		// Directory message containing a file, an empty
+		// subdirectory and a symbolic link.
+		directoryDigest := &remoteexecution.Digest{
+			Hash:      "16a37ee76faa29376ecfb17d38a5a197",
+			SizeBytes: 117,
+		}
+		testutil.RequireEqualProto(t, &outputpaths.ExtendedBatchStatResponse{
+			Responses: []*outputpaths.ExtendedStatResponse{
+				{
+					Response:        &remoteoutputservice.StatResponse{FileStatus: directoryStatus},
+					DirectoryDigest: directoryDigest,
+				},
+				{
+					Response:        &remoteoutputservice.StatResponse{FileStatus: directoryStatus},
+					DirectoryDigest: directoryDigest,
+				},
+			},
+		}, response)
+	})
+
+	t.Run("FileWithoutDigest", func(t *testing.T) {
+		// If the digest of a file in the directory cannot be
+		// computed, the digest of the directory should be
+		// omitted.
+		directory := mock.NewMockPrepopulatedDirectory(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("directory")).
+			Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(directory), nil)
+		directory.EXPECT().VirtualGetAttributes(ctx, re_vfs.AttributesMaskLastDataModificationTime, gomock.Any()).
+			Do(func(ctx context.Context, requested re_vfs.AttributesMask, attributes *re_vfs.Attributes) {
+				attributes.SetLastDataModificationTime(time.Unix(1000, 0))
+			})
+		file := mock.NewMockNativeLeaf(ctrl)
+		directory.EXPECT().LookupAllChildren().Return(
+			nil,
+			[]re_vfs.LeafPrepopulatedDirEntry{
+				{Name: path.MustNewComponent("file"), Child: file},
+			},
+			nil)
+		file.EXPECT().Readlink().Return("", syscall.EINVAL)
+		file.EXPECT().GetOutputServiceFileStatus(gomock.Any()).Return(&remoteoutputservice.FileStatus{
+			FileType: &remoteoutputservice.FileStatus_File_{
+				File: &remoteoutputservice.FileStatus_File{},
+			},
+		}, nil)
+
+		response, err := d.ExtendedBatchStat(ctx, &outputpaths.ExtendedBatchStatRequest{
+			Request: &remoteoutputservice.BatchStatRequest{
+				BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+				Paths:   []string{"directory"},
+			},
+			IncludeDirectoryDigest: true,
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &outputpaths.ExtendedBatchStatResponse{
+			Responses: []*outputpaths.ExtendedStatResponse{
+				{
+					Response: &remoteoutputservice.StatResponse{FileStatus: directoryStatus},
+				},
+			},
+		}, response)
+	})
+
+	t.Run("FileWithIncompatibleDigestFunction", func(t *testing.T) {
+		// Files backed by the Content Addressable Storage
+		// report their digest as is. If such a file uses a
+		// different digest function than the build, the digest
+		// of the directory would mix digest functions. This
+		// should be reported as an error.
+		directory := mock.NewMockPrepopulatedDirectory(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("directory")).
+			Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(directory), nil)
+		directory.EXPECT().VirtualGetAttributes(ctx, re_vfs.AttributesMaskLastDataModificationTime, gomock.Any()).
+			Do(func(ctx context.Context, requested re_vfs.AttributesMask, attributes *re_vfs.Attributes) {
+				attributes.SetLastDataModificationTime(time.Unix(1000, 0))
+			})
+		subdirectory := mock.NewMockPrepopulatedDirectory(ctrl)
+		directory.EXPECT().LookupAllChildren().Return(
+			[]re_vfs.DirectoryPrepopulatedDirEntry{
+				{Name: path.MustNewComponent("subdirectory"), Child: subdirectory},
+			},
+			nil,
+			nil)
+		file := mock.NewMockNativeLeaf(ctrl)
+		subdirectory.EXPECT().LookupAllChildren().Return(
+			nil,
+			[]re_vfs.LeafPrepopulatedDirEntry{
+				{Name: path.MustNewComponent("file"), Child: file},
+			},
+			nil)
+		file.EXPECT().Readlink().Return("", syscall.EINVAL)
+		file.EXPECT().GetOutputServiceFileStatus(gomock.Any()).Return(&remoteoutputservice.FileStatus{
+			FileType: &remoteoutputservice.FileStatus_File_{
+				File: &remoteoutputservice.FileStatus_File{
+					Digest: &remoteexecution.Digest{
+						Hash:      "2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae",
+						SizeBytes: 3,
+					},
+				},
+			},
+		}, nil)
+		file.EXPECT().GetContainingDigests().
+			Return(digest.MustNewDigest("my-cluster", remoteexecution.DigestFunction_SHA256, "2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae", 3).ToSingletonSet())
+
+		_, err := d.ExtendedBatchStat(ctx, &outputpaths.ExtendedBatchStatRequest{
+			Request: &remoteoutputservice.BatchStatRequest{
+				BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+				Paths:   []string{"directory"},
+			},
+			IncludeDirectoryDigest: true,
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.FailedPrecondition, "Failed to compute digest of directory \"directory\": Invalid digest for file \"subdirectory/file\": Digest \"1-2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae-3-my-cluster\" uses digest function SHA256, while the build uses digest function MD5"), err)
+	})
+
+	t.Run("FileWithDifferentInstanceName", func(t *testing.T) {
+		// Instance names are not part of the Directory message.
+		// Files whose digest only differs from the build in
+		// terms of instance name should thus be accepted.
+		directory := mock.NewMockPrepopulatedDirectory(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("directory")).
+			Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(directory), nil)
+		directory.EXPECT().VirtualGetAttributes(ctx, re_vfs.AttributesMaskLastDataModificationTime, gomock.Any()).
+			Do(func(ctx context.Context, requested re_vfs.AttributesMask, attributes *re_vfs.Attributes) {
+				attributes.SetLastDataModificationTime(time.Unix(1000, 0))
+			})
+		file := mock.NewMockNativeLeaf(ctrl)
+		directory.EXPECT().LookupAllChildren().Return(
+			nil,
+			[]re_vfs.LeafPrepopulatedDirEntry{
+				{Name: path.MustNewComponent("file"), Child: file},
+			},
+			nil)
+		file.EXPECT().Readlink().Return("", syscall.EINVAL)
+		file.EXPECT().GetOutputServiceFileStatus(gomock.Any()).Return(&remoteoutputservice.FileStatus{
+			FileType: &remoteoutputservice.FileStatus_File_{
+				File: &remoteoutputservice.FileStatus_File{
+					Digest: &remoteexecution.Digest{
+						Hash:      "ad17450bb18953f249532a478d2150ba",
+						SizeBytes: 72,
+					},
+				},
+			},
+		}, nil)
+		file.EXPECT().GetContainingDigests().
+			Return(digest.MustNewDigest("other-cluster", remoteexecution.DigestFunction_MD5, "ad17450bb18953f249532a478d2150ba", 72).ToSingletonSet())
+		file.EXPECT().VirtualGetAttributes(ctx, re_vfs.AttributesMaskPermissions, gomock.Any()).
+			Do(func(ctx context.Context, requested re_vfs.AttributesMask, attributes *re_vfs.Attributes) {
+				attributes.SetPermissions(re_vfs.PermissionsRead)
+			})
+
+		response, err := d.ExtendedBatchStat(ctx, &outputpaths.ExtendedBatchStatRequest{
+			Request: &remoteoutputservice.BatchStatRequest{
+				BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+				Paths:   []string{"directory"},
+			},
+			IncludeDirectoryDigest: true,
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &outputpaths.ExtendedBatchStatResponse{
+			Responses: []*outputpaths.ExtendedStatResponse{
+				{
+					Response: &remoteoutputservice.StatResponse{FileStatus: directoryStatus},
+					DirectoryDigest: &remoteexecution.Digest{
+						Hash:      "2d642cf0efeefd95dbbdfc64b07911f3",
+						SizeBytes: 46,
+					},
+				},
+			},
+		}, response)
+	})
+
+	t.Run("DirectoryEntryCount", func(t *testing.T) {
+		// The number of children of a directory should be
+		// reported. Failures to load the directory should only
+		// affect the path in question in best-effort mode.
+		directory := mock.NewMockPrepopulatedDirectory(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("directory")).
+			Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(directory), nil)
+		directory.EXPECT().VirtualGetAttributes(ctx, re_vfs.AttributesMaskLastDataModificationTime, gomock.Any()).
+			Do(func(ctx context.Context, requested re_vfs.AttributesMask, attributes *re_vfs.Attributes) {
+				attributes.SetLastDataModificationTime(time.Unix(1000, 0))
+			})
+		directory.EXPECT().LookupAllChildren().Return(
+			[]re_vfs.DirectoryPrepopulatedDirEntry{
+				{Name: path.MustNewComponent("subdirectory"), Child: mock.NewMockPrepopulatedDirectory(ctrl)},
+			},
+			[]re_vfs.LeafPrepopulatedDirEntry{
+				{Name: path.MustNewComponent("file"), Child: mock.NewMockNativeLeaf(ctrl)},
+				{Name: path.MustNewComponent("symlink"), Child: mock.NewMockNativeLeaf(ctrl)},
+			},
+			nil)
+		brokenDirectory := mock.NewMockPrepopulatedDirectory(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("broken")).
+			Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(brokenDirectory), nil)
+		brokenDirectory.EXPECT().VirtualGetAttributes(ctx, re_vfs.AttributesMaskLastDataModificationTime, gomock.Any()).
+			Do(func(ctx context.Context, requested re_vfs.AttributesMask, attributes *re_vfs.Attributes) {
+				attributes.SetLastDataModificationTime(time.Unix(1000, 0))
+			})
+		brokenDirectory.EXPECT().LookupAllChildren().Return(nil, nil, status.Error(codes.Internal, "Failed to load directory"))
+
+		response, err := d.ExtendedBatchStat(ctx, &outputpaths.ExtendedBatchStatRequest{
+			Request: &remoteoutputservice.BatchStatRequest{
+				BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+				Paths:   []string{"directory", "broken"},
+			},
+			IncludeDirectoryEntryCount: true,
+			BestEffort:                 true,
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &outputpaths.ExtendedBatchStatResponse{
+			Responses: []*outputpaths.ExtendedStatResponse{
+				{
+					Response:            &remoteoutputservice.StatResponse{FileStatus: directoryStatus},
+					DirectoryEntryCount: 3,
+				},
+				{
+					Error: status.New(codes.Internal, "Failed to count children of directory \"broken\": Failed to load directory").Proto(),
+				},
+			},
+		}, response)
+	})
+
+	t.Run("DeduplicatePaths", func(t *testing.T) {
+		// Paths that are requested multiple times should only
+		// be resolved once, while still yielding one response
+		// per requested path.
+		directory := mock.NewMockPrepopulatedDirectory(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("directory")).
+			Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(directory), nil)
+		directory.EXPECT().VirtualGetAttributes(ctx, re_vfs.AttributesMaskLastDataModificationTime, gomock.Any()).
+			Do(func(ctx context.Context, requested re_vfs.AttributesMask, attributes *re_vfs.Attributes) {
+				attributes.SetLastDataModificationTime(time.Unix(1000, 0))
+			})
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("missing")).
+			Return(re_vfs.PrepopulatedDirectoryChild{}, syscall.ENOENT)
+
+		response, err := d.ExtendedBatchStat(ctx, &outputpaths.ExtendedBatchStatRequest{
+			Request: &remoteoutputservice.BatchStatRequest{
+				BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+				Paths:   []string{"directory", "missing", "directory", "missing", "directory"},
+			},
+			DeduplicatePaths: true,
+		})
+		require.NoError(t, err)
+		directoryResponse := &outputpaths.ExtendedStatResponse{
+			Response: &remoteoutputservice.StatResponse{FileStatus: directoryStatus},
+		}
+		missingResponse := &outputpaths.ExtendedStatResponse{
+			Response: &remoteoutputservice.StatResponse{},
+		}
+		testutil.RequireEqualProto(t, &outputpaths.ExtendedBatchStatResponse{
+			Responses: []*outputpaths.ExtendedStatResponse{
+				directoryResponse,
+				missingResponse,
+				directoryResponse,
+				missingResponse,
+				directoryResponse,
+			},
+		}, response)
+	})
+
+	t.Run("ResolvedPath", func(t *testing.T) {
+		// Symbolic links should be expanded when computing the
+		// resolved path of a file. Paths that don't exist
+		// should not have a resolved path.
+		directory := mock.NewMockPrepopulatedDirectory(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("directory")).
+			Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(directory), nil).
+			Times(2)
+		symlink := mock.NewMockNativeLeaf(ctrl)
+		directory.EXPECT().LookupChild(path.MustNewComponent("symlink")).
+			Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(symlink), nil)
+		symlink.EXPECT().Readlink().Return("../file", nil)
+		file := mock.NewMockNativeLeaf(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("file")).
+			Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(file), nil)
+		file.EXPECT().Readlink().Return("", syscall.EINVAL)
+		fileStatus := &remoteoutputservice.FileStatus{
+			FileType: &remoteoutputservice.FileStatus_File_{
+				File: &remoteoutputservice.FileStatus_File{},
+			},
+		}
+		file.EXPECT().GetOutputServiceFileStatus(nil).Return(fileStatus, nil)
+		directory.EXPECT().LookupChild(path.MustNewComponent("nonexistent")).
+			Return(re_vfs.PrepopulatedDirectoryChild{}, syscall.ENOENT)
+
+		response, err := d.ExtendedBatchStat(ctx, &outputpaths.ExtendedBatchStatRequest{
+			Request: &remoteoutputservice.BatchStatRequest{
+				BuildId:        "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+				Paths:          []string{"directory/symlink", "directory/nonexistent"},
+				FollowSymlinks: true,
+			},
+			IncludeResolvedPath: true,
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &outputpaths.ExtendedBatchStatResponse{
+			Responses: []*outputpaths.ExtendedStatResponse{
+				{
+					Response:     &remoteoutputservice.StatResponse{FileStatus: fileStatus},
+					ResolvedPath: "file",
+				},
+				{
+					Response: &remoteoutputservice.StatResponse{},
+				},
+			},
+		}, response)
+	})
+
+	t.Run("SymlinkChain", func(t *testing.T) {
+		// The targets of all symbolic links that are expanded
+		// should be returned in order.
+		symlinkA := mock.NewMockNativeLeaf(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("a")).
+			Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(symlinkA), nil)
+		symlinkA.EXPECT().Readlink().Return("b", nil)
+		symlinkB := mock.NewMockNativeLeaf(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("b")).
+			Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(symlinkB), nil)
+		symlinkB.EXPECT().Readlink().Return("c", nil)
+		file := mock.NewMockNativeLeaf(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("c")).
+			Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(file), nil)
+		file.EXPECT().Readlink().Return("", syscall.EINVAL)
+		fileStatus := &remoteoutputservice.FileStatus{
+			FileType: &remoteoutputservice.FileStatus_File_{
+				File: &remoteoutputservice.FileStatus_File{},
+			},
+		}
+		file.EXPECT().GetOutputServiceFileStatus(nil).Return(fileStatus, nil)
+
+		// Chains that exceed the maximum number of redirections
+		// should be reported as being truncated.
+		loop := mock.NewMockNativeLeaf(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("loop")).
+			Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(loop), nil).
+			Times(41)
+		loop.EXPECT().Readlink().Return("loop", nil).Times(41)
+
+		response, err := d.ExtendedBatchStat(ctx, &outputpaths.ExtendedBatchStatRequest{
+			Request: &remoteoutputservice.BatchStatRequest{
+				BuildId:        "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+				Paths:          []string{"a", "loop"},
+				FollowSymlinks: true,
+			},
+			IncludeSymlinkChain: true,
+		})
+		require.NoError(t, err)
+		loopChain := make([]string, 40)
+		for i := range loopChain {
+			loopChain[i] = "loop"
+		}
+		testutil.RequireEqualProto(t, &outputpaths.ExtendedBatchStatResponse{
+			Responses: []*outputpaths.ExtendedStatResponse{
+				{
+					Response:     &remoteoutputservice.StatResponse{FileStatus: fileStatus},
+					SymlinkChain: []string{"b", "c"},
+				},
+				{
+					Response: &remoteoutputservice.StatResponse{
+						FileStatus: &remoteoutputservice.FileStatus{
+							FileType: &remoteoutputservice.FileStatus_External_{
+								External: &remoteoutputservice.FileStatus_External{
+									NextPath: "loop",
+								},
+							},
+						},
+					},
+					SymlinkChain:          loopChain,
+					SymlinkChainTruncated: true,
+				},
+			},
+		}, response)
+	})
+
+	t.Run("CaseInsensitive", func(t *testing.T) {
+		// If no child with the exact name exists, children
+		// whose names only differ by case should be considered.
+		directory := mock.NewMockPrepopulatedDirectory(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("Directory")).
+			Return(re_vfs.PrepopulatedDirectoryChild{}, syscall.ENOENT).
+			Times(2)
+		outputPath.EXPECT().LookupAllChildren().Return(
+			[]re_vfs.DirectoryPrepopulatedDirEntry{
+				{Name: path.MustNewComponent("directory"), Child: directory},
+			},
+			nil,
+			nil).
+			Times(2)
+
+		// Exact matches should take precedence.
+		file := mock.NewMockNativeLeaf(ctrl)
+		directory.EXPECT().LookupChild(path.MustNewComponent("File")).
+			Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(file), nil)
+		fileStatus := &remoteoutputservice.FileStatus{
+			FileType: &remoteoutputservice.FileStatus_File_{
+				File: &remoteoutputservice.FileStatus_File{},
+			},
+		}
+		file.EXPECT().GetOutputServiceFileStatus(nil).Return(fileStatus, nil)
+
+		// Names that match multiple children are ambiguous.
+		directory.EXPECT().LookupChild(path.MustNewComponent("AMBIGUOUS")).
+			Return(re_vfs.PrepopulatedDirectoryChild{}, syscall.ENOENT)
+		directory.EXPECT().LookupAllChildren().Return(
+			nil,
+			[]re_vfs.LeafPrepopulatedDirEntry{
+				{Name: path.MustNewComponent("File"), Child: file},
+				{Name: path.MustNewComponent("ambiguous"), Child: mock.NewMockNativeLeaf(ctrl)},
+				{Name: path.MustNewComponent("Ambiguous"), Child: mock.NewMockNativeLeaf(ctrl)},
+			},
+			nil)
+
+		response, err := d.ExtendedBatchStat(ctx, &outputpaths.ExtendedBatchStatRequest{
+			Request: &remoteoutputservice.BatchStatRequest{
+				BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+				Paths:   []string{"Directory/File"},
+			},
+			IncludeResolvedPath: true,
+			CaseInsensitive:     true,
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &outputpaths.ExtendedBatchStatResponse{
+			Responses: []*outputpaths.ExtendedStatResponse{
+				{
+					Response:     &remoteoutputservice.StatResponse{FileStatus: fileStatus},
+					ResolvedPath: "Directory/File",
+				},
+			},
+		}, response)
+
+		_, err = d.ExtendedBatchStat(ctx, &outputpaths.ExtendedBatchStatRequest{
+			Request: &remoteoutputservice.BatchStatRequest{
+				BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+				Paths:   []string{"Directory/AMBIGUOUS"},
+			},
+			CaseInsensitive: true,
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Failed to resolve path \"Directory/AMBIGUOUS\" beyond \"Directory/\": Name \"AMBIGUOUS\" matches both \"ambiguous\" and \"Ambiguous\" when compared case-insensitively"), err)
+	})
+
+	t.Run("LookupAllChildrenFailure", func(t *testing.T) {
+		directory := mock.NewMockPrepopulatedDirectory(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("directory")).
+			Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(directory), nil)
+		directory.EXPECT().VirtualGetAttributes(ctx, re_vfs.AttributesMaskLastDataModificationTime, gomock.Any()).
+			Do(func(ctx context.Context, requested re_vfs.AttributesMask, attributes *re_vfs.Attributes) {
+				attributes.SetLastDataModificationTime(time.Unix(1000, 0))
+			})
+		directory.EXPECT().LookupAllChildren().Return(nil, nil, status.Error(codes.Internal, "Disk failure"))
+
+		_, err := d.ExtendedBatchStat(ctx, &outputpaths.ExtendedBatchStatRequest{
+			Request: &remoteoutputservice.BatchStatRequest{
+				BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+				Paths:   []string{"directory"},
+			},
+			IncludeDirectoryDigest: true,
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Failed to compute digest of directory \"directory\": Failed to look up children of directory \".\": Disk failure"), err)
+	})
+
+	t.Run("BestEffort", func(t *testing.T) {
+		// Failures to resolve individual paths should be
+		// reported as part of the response, without preventing
+		// the remaining paths from being processed.
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("broken")).
+			Return(re_vfs.PrepopulatedDirectoryChild{}, status.Error(codes.Internal, "Disk failure"))
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("nonexistent")).
+			Return(re_vfs.PrepopulatedDirectoryChild{}, syscall.ENOENT)
+
+		response, err := d.ExtendedBatchStat(ctx, &outputpaths.ExtendedBatchStatRequest{
+			Request: &remoteoutputservice.BatchStatRequest{
+				BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+				Paths:   []string{"broken", "nonexistent"},
+			},
+			BestEffort: true,
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &outputpaths.ExtendedBatchStatResponse{
+			Responses: []*outputpaths.ExtendedStatResponse{
+				{
+					Error: status.New(codes.Internal, "Failed to resolve path \"broken\" beyond \".\": Disk failure").Proto(),
+				},
+				{
+					Response: &remoteoutputservice.StatResponse{},
+				},
+			},
+		}, response)
+	})
+
+	t.Run("BestEffortSymlinkLoop", func(t *testing.T) {
+		// Symbolic link cycles are not treated as failures.
+		// Once the maximum number of redirections is reached,
+		// the path is reported as being external, so that
+		// stat() on the client side fails with ELOOP. The
+		// remaining paths should be unaffected.
+		loop := mock.NewMockNativeLeaf(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("loop")).
+			Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(loop), nil).
+			Times(41)
+		loop.EXPECT().Readlink().Return("loop", nil).Times(41)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("nonexistent")).
+			Return(re_vfs.PrepopulatedDirectoryChild{}, syscall.ENOENT)
+
+		response, err := d.ExtendedBatchStat(ctx, &outputpaths.ExtendedBatchStatRequest{
+			Request: &remoteoutputservice.BatchStatRequest{
+				BuildId:        "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+				Paths:          []string{"loop", "nonexistent"},
+				FollowSymlinks: true,
+			},
+			BestEffort: true,
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &outputpaths.ExtendedBatchStatResponse{
+			Responses: []*outputpaths.ExtendedStatResponse{
+				{
+					Response: &remoteoutputservice.StatResponse{
+						FileStatus: &remoteoutputservice.FileStatus{
+							FileType: &remoteoutputservice.FileStatus_External_{
+								External: &remoteoutputservice.FileStatus_External{
+									NextPath: "loop",
+								},
+							},
+						},
+					},
+				},
+				{
+					Response: &remoteoutputservice.StatResponse{},
+				},
+			},
+		}, response)
+	})
+}
+
+func TestRemoteOutputServiceDirectorySetExternalPathPolicy(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	handleAllocator := mock.NewMockStatefulHandleAllocator(ctrl)
+	outputPathFactory := mock.NewMockOutputPathFactory(ctrl)
+	bareContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	retryingContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	directoryFetcher := mock.NewMockDirectoryFetcher(ctrl)
+	symlinkFactory := mock.NewMockSymlinkFactory(ctrl)
+	dHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	handleAllocator.EXPECT().New().Return(dHandleAllocation)
+	dHandle := mock.NewMockStatefulDirectoryHandle(ctrl)
+	dHandleAllocation.EXPECT().AsStatefulDirectory(gomock.Any()).Return(dHandle)
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(1000, 0)).AnyTimes()
+	d := newTestRemoteOutputServiceDirectory(
+		handleAllocator,
+		outputPathFactory,
+		bareContentAddressableStorage,
+		retryingContentAddressableStorage,
+		directoryFetcher,
+		symlinkFactory,
+		allowAllAuthorizer,
+		clock,
+		cd_vfs.RemoteOutputServiceDirectoryOptions{})
+
+	t.Run("InvalidPolicy", func(t *testing.T) {
+		_, err := d.SetExternalPathPolicy(ctx, &outputpaths.SetExternalPathPolicyRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Policy:  42,
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Unknown external path policy 42"), err)
+	})
+
+	t.Run("InvalidBuildID", func(t *testing.T) {
+		_, err := d.SetExternalPathPolicy(ctx, &outputpaths.SetExternalPathPolicyRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Policy:  outputpaths.ExternalPathPolicy_RETURN_ERROR,
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.FailedPrecondition, "Build ID is not associated with any running build"), err)
+	})
+
+	// Let the remainder of the tests assume that a build is running.
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocator := mock.NewMockStatelessHandleAllocator(ctrl)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(casFileHandleAllocator)
+	outputPath := mock.NewMockOutputPath(ctrl)
+	outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+		gomock.Any(),
+		digest.MustNewFunction("", remoteexecution.DigestFunction_MD5),
+		gomock.Any(),
+	).Return(outputPath)
+	outputPath.EXPECT().FilterChildren(gomock.Any())
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		DigestFunction:   remoteexecution.DigestFunction_MD5,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+
+	// All tests stat a symbolic link that leaves the output path
+	// through a relative target.
+	expectSymlink := func() {
+		nested := mock.NewMockPrepopulatedDirectory(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("nested")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(nested), nil)
+		leaf := mock.NewMockNativeLeaf(ctrl)
+		nested.EXPECT().LookupChild(path.MustNewComponent("symlink")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(leaf), nil)
+		leaf.EXPECT().Readlink().Return("../../outside", nil)
+	}
+	batchStat := func() (*remoteoutputservice.BatchStatResponse, error) {
+		return d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
+			BuildId:        "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			FollowSymlinks: true,
+			Paths:          []string{"nested/symlink"},
+		})
+	}
+	externalResponse := func(nextPath string) *remoteoutputservice.BatchStatResponse {
+		return &remoteoutputservice.BatchStatResponse{
+			Responses: []*remoteoutputservice.StatResponse{
+				{
+					FileStatus: &remoteoutputservice.FileStatus{
+						FileType: &remoteoutputservice.FileStatus_External_{
+							External: &remoteoutputservice.FileStatus_External{
+								NextPath: nextPath,
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	t.Run("ReturnExternal", func(t *testing.T) {
+		// By default, the path resolved so far should be
+		// returned.
+		expectSymlink()
+
+		response, err := batchStat()
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, externalResponse("../outside"), response)
+	})
+
+	t.Run("ReturnError", func(t *testing.T) {
+		_, err := d.SetExternalPathPolicy(ctx, &outputpaths.SetExternalPathPolicyRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Policy:  outputpaths.ExternalPathPolicy_RETURN_ERROR,
+		})
+		require.NoError(t, err)
+		expectSymlink()
+
+		_, err = batchStat()
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Path \"nested/symlink\" resolves to \"../outside\", which is outside the output path"), err)
+	})
+
+	t.Run("ReturnRawTarget", func(t *testing.T) {
+		_, err := d.SetExternalPathPolicy(ctx, &outputpaths.SetExternalPathPolicyRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Policy:  outputpaths.ExternalPathPolicy_RETURN_RAW_TARGET,
+		})
+		require.NoError(t, err)
+		expectSymlink()
+
+		response, err := batchStat()
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, externalResponse("../../outside"), response)
+	})
+
+	t.Run("ReturnErrorOnParentTraversal", func(t *testing.T) {
+		_, err := d.SetExternalPathPolicy(ctx, &outputpaths.SetExternalPathPolicyRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Policy:  outputpaths.ExternalPathPolicy_RETURN_ERROR_ON_PARENT_TRAVERSAL,
+		})
+		require.NoError(t, err)
+
+		// Leaving the output path through a symbolic link
+		// should still be permitted.
+		expectSymlink()
+
+		response, err := batchStat()
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, externalResponse("../outside"), response)
+
+		// Leaving the output path through ".." components in
+		// the path provided by the client should fail.
+		nested := mock.NewMockPrepopulatedDirectory(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("nested")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(nested), nil)
+
+		_, err = d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Paths:   []string{"nested/../../outside"},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Path \"nested/../../outside\" traverses above the root of the output path"), err)
+	})
+}
+
+func TestRemoteOutputServiceDirectorySetDefaultPathPrefix(t *testing.T) {
+	ctx := context.Background()
+
+	contentAddressableStorage := cd_testutil.NewFakeContentAddressableStorage()
+	d := cd_testutil.NewInMemoryRemoteOutputServiceDirectory(contentAddressableStorage)
+
+	t.Run("InvalidPathPrefix", func(t *testing.T) {
+		_, err := d.SetDefaultPathPrefix(ctx, &outputpaths.SetDefaultPathPrefixRequest{
+			BuildId:    "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			PathPrefix: "bazel-out/../..",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Invalid path prefix \"bazel-out/../..\": Path resolves to a location outside the output path"), err)
+	})
+
+	t.Run("InvalidBuildID", func(t *testing.T) {
+		_, err := d.SetDefaultPathPrefix(ctx, &outputpaths.SetDefaultPathPrefixRequest{
+			BuildId:    "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			PathPrefix: "bazel-out/k8-fastbuild",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.FailedPrecondition, "Build ID is not associated with any running build"), err)
+	})
+
+	// Let the remainder of the tests assume that a build is running.
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		DigestFunction:   remoteexecution.DigestFunction_SHA256,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+
+	batchStat := func(paths ...string) *remoteoutputservice.BatchStatResponse {
+		response, err := d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Paths:   paths,
+		})
+		require.NoError(t, err)
+		return response
+	}
+	fileResponse := &remoteoutputservice.StatResponse{
+		FileStatus: &remoteoutputservice.FileStatus{
+			FileType: &remoteoutputservice.FileStatus_File_{
+				File: &remoteoutputservice.FileStatus_File{},
+			},
+		},
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		_, err := d.SetDefaultPathPrefix(ctx, &outputpaths.SetDefaultPathPrefixRequest{
+			BuildId:    "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			PathPrefix: "bazel-out/./k8-fastbuild/",
+		})
+		require.NoError(t, err)
+
+		// Paths provided to BatchCreate() should be relative to
+		// the default path prefix. The request should not be
+		// modified, as callers may log or replay it.
+		batchCreateRequest := &remoteoutputservice.BatchCreateRequest{
+			BuildId:    "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			PathPrefix: "bin",
+			Files: []*remoteexecution.OutputFile{
+				{
+					Path: "hello.txt",
+					Digest: contentAddressableStorage.PutBlob(
+						digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256),
+						[]byte("Hello")).GetProto(),
+				},
+			},
+		}
+		_, err = d.BatchCreate(ctx, batchCreateRequest)
+		require.NoError(t, err)
+		require.Equal(t, "bin", batchCreateRequest.PathPrefix)
+
+		// Paths provided to BatchStat() should also be relative
+		// to the default path prefix, unless they already
+		// include it.
+		testutil.RequireEqualProto(t, &remoteoutputservice.BatchStatResponse{
+			Responses: []*remoteoutputservice.StatResponse{
+				fileResponse,
+				fileResponse,
+				{},
+			},
+		}, batchStat("bin/hello.txt", "bazel-out/k8-fastbuild/bin/hello.txt", "hello.txt"))
+	})
+
+	t.Run("Reset", func(t *testing.T) {
+		// Setting the default path prefix to the empty string
+		// should cause paths to be relative to the root of the
+		// output path again.
+		_, err := d.SetDefaultPathPrefix(ctx, &outputpaths.SetDefaultPathPrefixRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		})
+		require.NoError(t, err)
+
+		testutil.RequireEqualProto(t, &remoteoutputservice.BatchStatResponse{
+			Responses: []*remoteoutputservice.StatResponse{
+				{},
+				fileResponse,
+			},
+		}, batchStat("bin/hello.txt", "bazel-out/k8-fastbuild/bin/hello.txt"))
+	})
+}
+
+func TestRemoteOutputServiceDirectoryRepairOutputPath(t *testing.T) {
+	ctx := context.Background()
+
+	contentAddressableStorage := cd_testutil.NewFakeContentAddressableStorage()
+	d := cd_testutil.NewInMemoryRemoteOutputServiceDirectory(contentAddressableStorage)
+
+	t.Run("InvalidBuildID", func(t *testing.T) {
+		_, err := d.RepairOutputPath(ctx, &outputpaths.RepairOutputPathRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.FailedPrecondition, "Build ID is not associated with any running build"), err)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+			OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+			BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			DigestFunction:   remoteexecution.DigestFunction_SHA256,
+			OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+		})
+		require.NoError(t, err)
+
+		// Create two files, of which only one is present in the
+		// Content Addressable Storage.
+		digestFunction := digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256)
+		_, err = d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+			BuildId:    "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			PathPrefix: "bazel-out",
+			Files: []*remoteexecution.OutputFile{
+				{
+					Path:   "present.txt",
+					Digest: contentAddressableStorage.PutBlob(digestFunction, []byte("Hello")).GetProto(),
+				},
+				{
+					Path: "nested/missing.txt",
+					Digest: &remoteexecution.Digest{
+						Hash:      "0dff85db4ec8cb2bb25e4bdf8e0bfb20f4ea3e1a7cbab0dd6c1c4c1e45b9bd5e",
+						SizeBytes: 7,
+					},
+				},
+			},
+			Symlinks: []*remoteexecution.OutputSymlink{
+				{
+					Path:   "symlink",
+					Target: "nested/missing.txt",
+				},
+			},
+		})
+		require.NoError(t, err)
+
+		// Only the file that is missing should be removed.
+		response, err := d.RepairOutputPath(ctx, &outputpaths.RepairOutputPathRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &outputpaths.RepairOutputPathResponse{
+			RemovedPaths: []string{"bazel-out/nested/missing.txt"},
+		}, response)
+
+		statResponse, err := d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Paths:   []string{"bazel-out/present.txt", "bazel-out/nested/missing.txt", "bazel-out/symlink"},
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &remoteoutputservice.BatchStatResponse{
+			Responses: []*remoteoutputservice.StatResponse{
+				{
+					FileStatus: &remoteoutputservice.FileStatus{
+						FileType: &remoteoutputservice.FileStatus_File_{
+							File: &remoteoutputservice.FileStatus_File{},
+						},
+					},
+				},
+				{},
+				{
+					FileStatus: &remoteoutputservice.FileStatus{
+						FileType: &remoteoutputservice.FileStatus_Symlink_{
+							Symlink: &remoteoutputservice.FileStatus_Symlink{
+								Target: "nested/missing.txt",
+							},
+						},
+					},
+				},
+			},
+		}, statResponse)
+
+		// Repairing the output path again should be a no-op.
+		response, err = d.RepairOutputPath(ctx, &outputpaths.RepairOutputPathRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &outputpaths.RepairOutputPathResponse{}, response)
+	})
+}
+
+func TestRemoteOutputServiceDirectoryStatStream(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	handleAllocator := mock.NewMockStatefulHandleAllocator(ctrl)
+	outputPathFactory := mock.NewMockOutputPathFactory(ctrl)
+	bareContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	retryingContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	directoryFetcher := mock.NewMockDirectoryFetcher(ctrl)
+	symlinkFactory := mock.NewMockSymlinkFactory(ctrl)
+	dHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	handleAllocator.EXPECT().New().Return(dHandleAllocation)
+	dHandle := mock.NewMockStatefulDirectoryHandle(ctrl)
+	dHandleAllocation.EXPECT().AsStatefulDirectory(gomock.Any()).Return(dHandle)
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(1000, 0)).AnyTimes()
+	d := newTestRemoteOutputServiceDirectory(
+		handleAllocator,
+		outputPathFactory,
+		bareContentAddressableStorage,
+		retryingContentAddressableStorage,
+		directoryFetcher,
+		symlinkFactory,
+		allowAllAuthorizer,
+		clock,
+		cd_vfs.RemoteOutputServiceDirectoryOptions{})
+
+	t.Run("InvalidBuildID", func(t *testing.T) {
+		// StartBuild() should be called first.
+		server := mock.NewMockOutputPathsStatStreamServer(ctrl)
+
+		testutil.RequireEqualStatus(
+			t,
+			status.Error(codes.FailedPrecondition, "Build ID is not associated with any running build"),
+			d.StatStream(&remoteoutputservice.BatchStatRequest{
+				BuildId: "7f9b6d35-5b4b-4b6b-9b7e-a2f0e1e1b6ef",
+				Paths:   []string{"foo.o"},
+			}, server))
+	})
+
+	// Let the remainder of the tests assume that a build is running.
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocator := mock.NewMockStatelessHandleAllocator(ctrl)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(casFileHandleAllocator)
+	outputPath := mock.NewMockOutputPath(ctrl)
+	outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+		gomock.Any(),
+		digest.MustNewFunction("my-cluster", remoteexecution.DigestFunction_MD5),
+		gomock.Any(),
+	).Return(outputPath)
+	outputPath.EXPECT().FilterChildren(gomock.Any())
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		InstanceName:     "my-cluster",
+		DigestFunction:   remoteexecution.DigestFunction_MD5,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+
+	t.Run("ResolutionFailure", func(t *testing.T) {
+		// Responses for paths preceding the failing one should
+		// already have been sent.
+		server := mock.NewMockOutputPathsStatStreamServer(ctrl)
+		server.EXPECT().Context().Return(ctx)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("nonexistent")).Return(re_vfs.PrepopulatedDirectoryChild{}, syscall.ENOENT)
+		server.EXPECT().Send(testutil.EqProto(t, &outputpaths.StatStreamResponse{
+			Index:    0,
+			Response: &remoteoutputservice.StatResponse{},
+		}))
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("printf.o")).Return(re_vfs.PrepopulatedDirectoryChild{}, status.Error(codes.Internal, "Disk failure"))
+
+		testutil.RequireEqualStatus(
+			t,
+			status.Error(codes.Internal, "Failed to resolve path \"printf.o\" beyond \".\": Disk failure"),
+			d.StatStream(&remoteoutputservice.BatchStatRequest{
+				BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+				Paths:   []string{"nonexistent", "printf.o"},
+			}, server))
+	})
+
+	t.Run("SendFailure", func(t *testing.T) {
+		server := mock.NewMockOutputPathsStatStreamServer(ctrl)
+		server.EXPECT().Context().Return(ctx)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("nonexistent")).Return(re_vfs.PrepopulatedDirectoryChild{}, syscall.ENOENT)
+		server.EXPECT().Send(gomock.Any()).Return(status.Error(codes.Canceled, "Client went away"))
+
+		testutil.RequireEqualStatus(
+			t,
+			status.Error(codes.Canceled, "Client went away"),
+			d.StatStream(&remoteoutputservice.BatchStatRequest{
+				BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+				Paths:   []string{"nonexistent", "printf.o"},
+			}, server))
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		server := mock.NewMockOutputPathsStatStreamServer(ctrl)
+		server.EXPECT().Context().Return(ctx)
+
+		// Lookup of "file", pointing to directly to a file.
+		leaf := mock.NewMockNativeLeaf(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("file")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(leaf), nil)
+		leaf.EXPECT().GetOutputServiceFileStatus(nil).Return(&remoteoutputservice.FileStatus{
+			FileType: &remoteoutputservice.FileStatus_File_{
+				File: &remoteoutputservice.FileStatus_File{},
+			},
+		}, nil)
+		server.EXPECT().Send(testutil.EqProto(t, &outputpaths.StatStreamResponse{
+			Index: 0,
+			Response: &remoteoutputservice.StatResponse{
+				FileStatus: &remoteoutputservice.FileStatus{
+					FileType: &remoteoutputservice.FileStatus_File_{
+						File: &remoteoutputservice.FileStatus_File{},
+					},
+				},
+			},
+		}))
+
+		// Lookup of "nonexistent".
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("nonexistent")).Return(re_vfs.PrepopulatedDirectoryChild{}, syscall.ENOENT)
+		server.EXPECT().Send(testutil.EqProto(t, &outputpaths.StatStreamResponse{
+			Index:    1,
+			Response: &remoteoutputservice.StatResponse{},
+		}))
+
+		require.NoError(t, d.StatStream(&remoteoutputservice.BatchStatRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Paths:   []string{"file", "nonexistent"},
+		}, server))
+	})
+}
+
+func TestRemoteOutputServiceDirectoryCreateStream(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	handleAllocator := mock.NewMockStatefulHandleAllocator(ctrl)
+	outputPathFactory := mock.NewMockOutputPathFactory(ctrl)
+	bareContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	retryingContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	directoryFetcher := mock.NewMockDirectoryFetcher(ctrl)
+	symlinkFactory := mock.NewMockSymlinkFactory(ctrl)
+	dHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	handleAllocator.EXPECT().New().Return(dHandleAllocation)
+	dHandle := mock.NewMockStatefulDirectoryHandle(ctrl)
+	dHandleAllocation.EXPECT().AsStatefulDirectory(gomock.Any()).Return(dHandle)
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(1000, 0)).AnyTimes()
+	d := newTestRemoteOutputServiceDirectory(
+		handleAllocator,
+		outputPathFactory,
+		bareContentAddressableStorage,
+		retryingContentAddressableStorage,
+		directoryFetcher,
+		symlinkFactory,
+		allowAllAuthorizer,
+		clock,
+		cd_vfs.RemoteOutputServiceDirectoryOptions{})
+
+	t.Run("EmptyStream", func(t *testing.T) {
+		server := mock.NewMockOutputPathsCreateStreamServer(ctrl)
+		server.EXPECT().Recv().Return(nil, io.EOF)
+
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Stream does not contain a header"), d.CreateStream(server))
+	})
+
+	t.Run("MissingHeader", func(t *testing.T) {
+		server := mock.NewMockOutputPathsCreateStreamServer(ctrl)
+		server.EXPECT().Recv().Return(&outputpaths.CreateStreamRequest{
+			Entry: &outputpaths.CreateStreamRequest_Symlink{
+				Symlink: &remoteexecution.OutputSymlink{
+					Path:   "foo",
+					Target: "target",
+				},
+			},
+		}, nil)
+
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "First message in the stream does not contain a header"), d.CreateStream(server))
+	})
+
+	t.Run("InvalidBuildID", func(t *testing.T) {
+		// StartBuild() should be called first.
+		server := mock.NewMockOutputPathsCreateStreamServer(ctrl)
+		server.EXPECT().Recv().Return(&outputpaths.CreateStreamRequest{
+			Entry: &outputpaths.CreateStreamRequest_Header{
+				Header: &outputpaths.CreateStreamHeader{
+					BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+				},
+			},
+		}, nil)
+		server.EXPECT().Context().Return(ctx)
+
+		testutil.RequireEqualStatus(t, status.Error(codes.FailedPrecondition, "Build ID is not associated with any running build"), d.CreateStream(server))
+	})
+
+	// Let the remainder of the tests assume that a build is running.
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocator := mock.NewMockStatelessHandleAllocator(ctrl)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(casFileHandleAllocator)
+	outputPath := mock.NewMockOutputPath(ctrl)
+	outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+		gomock.Any(),
+		digest.MustNewFunction("", remoteexecution.DigestFunction_MD5),
+		gomock.Any(),
+	).Return(outputPath)
+	outputPath.EXPECT().FilterChildren(gomock.Any())
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		DigestFunction:   remoteexecution.DigestFunction_MD5,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+
+	header := &outputpaths.CreateStreamRequest{
+		Entry: &outputpaths.CreateStreamRequest_Header{
+			Header: &outputpaths.CreateStreamHeader{
+				BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			},
+		},
+	}
+
+	t.Run("InvalidPath", func(t *testing.T) {
+		// Entries preceding the invalid one should already
+		// have been created.
+		server := mock.NewMockOutputPathsCreateStreamServer(ctrl)
+		server.EXPECT().Context().Return(ctx)
+		symlink := mock.NewMockNativeLeaf(ctrl)
+		symlinkFactory.EXPECT().LookupSymlink([]byte("target")).Return(symlink)
+		outputPath.EXPECT().CreateChildren(map[path.Component]re_vfs.InitialNode{
+			path.MustNewComponent("foo"): re_vfs.InitialNode{}.FromLeaf(symlink),
+		}, true)
+		gomock.InOrder(
+			server.EXPECT().Recv().Return(header, nil),
+			server.EXPECT().Recv().Return(&outputpaths.CreateStreamRequest{
+				Entry: &outputpaths.CreateStreamRequest_Symlink{
+					Symlink: &remoteexecution.OutputSymlink{
+						Path:   "foo",
+						Target: "target",
+					},
+				},
+			}, nil),
+			server.EXPECT().Recv().Return(&outputpaths.CreateStreamRequest{
+				Entry: &outputpaths.CreateStreamRequest_File{
+					File: &remoteexecution.OutputFile{
+						Path: "../etc/passwd",
+						Digest: &remoteexecution.Digest{
+							Hash:      "d0ab620af7f3e77f3adfa190d41a25ce",
+							SizeBytes: 123,
+						},
+					},
+				},
+			}, nil))
+
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Invalid path for file \"../etc/passwd\": Path resolves to a location outside the output path"), d.CreateStream(server))
+	})
+
+	t.Run("DuplicateHeader", func(t *testing.T) {
+		server := mock.NewMockOutputPathsCreateStreamServer(ctrl)
+		server.EXPECT().Context().Return(ctx)
+		server.EXPECT().Recv().Return(header, nil).Times(2)
+
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Only the first message in the stream may contain a header"), d.CreateStream(server))
+	})
+
+	t.Run("ReceiveFailure", func(t *testing.T) {
+		server := mock.NewMockOutputPathsCreateStreamServer(ctrl)
+		server.EXPECT().Context().Return(ctx)
+		gomock.InOrder(
+			server.EXPECT().Recv().Return(header, nil),
+			server.EXPECT().Recv().Return(nil, status.Error(codes.Canceled, "Client went away")))
+
+		testutil.RequireEqualStatus(t, status.Error(codes.Canceled, "Client went away"), d.CreateStream(server))
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		// Once the client closes the stream, the number of
+		// entries that were created should be returned.
+		server := mock.NewMockOutputPathsCreateStreamServer(ctrl)
+		server.EXPECT().Context().Return(ctx)
+		symlink1 := mock.NewMockNativeLeaf(ctrl)
+		symlinkFactory.EXPECT().LookupSymlink([]byte("target1")).Return(symlink1)
+		outputPath.EXPECT().CreateChildren(map[path.Component]re_vfs.InitialNode{
+			path.MustNewComponent("symlink1"): re_vfs.InitialNode{}.FromLeaf(symlink1),
+		}, true)
+		symlink2 := mock.NewMockNativeLeaf(ctrl)
+		symlinkFactory.EXPECT().LookupSymlink([]byte("target2")).Return(symlink2)
+		outputPath.EXPECT().CreateChildren(map[path.Component]re_vfs.InitialNode{
+			path.MustNewComponent("symlink2"): re_vfs.InitialNode{}.FromLeaf(symlink2),
+		}, true)
+		gomock.InOrder(
+			server.EXPECT().Recv().Return(header, nil),
+			server.EXPECT().Recv().Return(&outputpaths.CreateStreamRequest{
+				Entry: &outputpaths.CreateStreamRequest_Symlink{
+					Symlink: &remoteexecution.OutputSymlink{
+						Path:   "symlink1",
+						Target: "target1",
+					},
+				},
+			}, nil),
+			server.EXPECT().Recv().Return(&outputpaths.CreateStreamRequest{
+				Entry: &outputpaths.CreateStreamRequest_Symlink{
+					Symlink: &remoteexecution.OutputSymlink{
+						Path:   "symlink2",
+						Target: "target2",
+					},
+				},
+			}, nil),
+			server.EXPECT().Recv().Return(nil, io.EOF),
+			server.EXPECT().SendAndClose(testutil.EqProto(t, &outputpaths.CreateStreamResponse{
+				Symlinks: 2,
+			})))
+
+		require.NoError(t, d.CreateStream(server))
+	})
+
+	t.Run("ReportRemovedPaths", func(t *testing.T) {
+		// If requested, the names of the entries that were
+		// removed when cleaning the path prefix directory
+		// should be returned.
+		server := mock.NewMockOutputPathsCreateStreamServer(ctrl)
+		server.EXPECT().Context().Return(ctx)
+		outputPath.EXPECT().LookupAllChildren().Return(
+			[]re_vfs.DirectoryPrepopulatedDirEntry{
+				{Name: path.MustNewComponent("directory"), Child: mock.NewMockPrepopulatedDirectory(ctrl)},
+			},
+			[]re_vfs.LeafPrepopulatedDirEntry{
+				{Name: path.MustNewComponent("symlink2"), Child: mock.NewMockNativeLeaf(ctrl)},
+				{Name: path.MustNewComponent("symlink1"), Child: mock.NewMockNativeLeaf(ctrl)},
+			},
+			nil)
+		outputPath.EXPECT().RemoveAllChildren(false)
+		gomock.InOrder(
+			server.EXPECT().Recv().Return(&outputpaths.CreateStreamRequest{
+				Entry: &outputpaths.CreateStreamRequest_Header{
+					Header: &outputpaths.CreateStreamHeader{
+						BuildId:            "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+						CleanPathPrefix:    true,
+						ReportRemovedPaths: true,
+					},
+				},
+			}, nil),
+			server.EXPECT().Recv().Return(nil, io.EOF),
+			server.EXPECT().SendAndClose(testutil.EqProto(t, &outputpaths.CreateStreamResponse{
+				RemovedPaths: []string{"directory", "symlink1", "symlink2"},
+			})))
+
+		require.NoError(t, d.CreateStream(server))
+	})
+}
+
+func TestRemoteOutputServiceDirectoryGetActiveBuild(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	handleAllocator := mock.NewMockStatefulHandleAllocator(ctrl)
+	outputPathFactory := mock.NewMockOutputPathFactory(ctrl)
+	bareContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	retryingContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	directoryFetcher := mock.NewMockDirectoryFetcher(ctrl)
+	symlinkFactory := mock.NewMockSymlinkFactory(ctrl)
+	dHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	handleAllocator.EXPECT().New().Return(dHandleAllocation)
+	dHandle := mock.NewMockStatefulDirectoryHandle(ctrl)
+	dHandleAllocation.EXPECT().AsStatefulDirectory(gomock.Any()).Return(dHandle)
+	clock := mock.NewMockClock(ctrl)
+	d := newTestRemoteOutputServiceDirectory(
+		handleAllocator,
+		outputPathFactory,
+		bareContentAddressableStorage,
+		retryingContentAddressableStorage,
+		directoryFetcher,
+		symlinkFactory,
+		allowAllAuthorizer,
+		clock,
+		cd_vfs.RemoteOutputServiceDirectoryOptions{})
+
+	t.Run("InvalidOutputBaseID", func(t *testing.T) {
+		_, err := d.GetActiveBuild(ctx, &outputpaths.GetActiveBuildRequest{
+			OutputBaseId: "..",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Output base ID must consist of one or more valid filenames separated by slashes: Component 1 may not be \"..\""), err)
+	})
+
+	t.Run("UnknownOutputBaseID", func(t *testing.T) {
+		_, err := d.GetActiveBuild(ctx, &outputpaths.GetActiveBuildRequest{
+			OutputBaseId: "9da951b8cb759233037166e28f7ea186",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.NotFound, "Output base ID is not associated with any output path"), err)
+	})
+
+	// Start a build.
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocator := mock.NewMockStatelessHandleAllocator(ctrl)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(casFileHandleAllocator)
+	outputPath := mock.NewMockOutputPath(ctrl)
+	outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+		gomock.Any(),
+		digest.MustNewFunction("my-cluster", remoteexecution.DigestFunction_SHA256),
+		gomock.Any(),
+	).Return(outputPath)
+	clock.EXPECT().Now().Return(time.Unix(1700000000, 0)).Times(2)
+	outputPath.EXPECT().FilterChildren(gomock.Any())
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		InstanceName:     "my-cluster",
+		DigestFunction:   remoteexecution.DigestFunction_SHA256,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+
+	t.Run("BuildRunning", func(t *testing.T) {
+		response, err := d.GetActiveBuild(ctx, &outputpaths.GetActiveBuildRequest{
+			OutputBaseId: "9da951b8cb759233037166e28f7ea186",
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &outputpaths.GetActiveBuildResponse{
+			ActiveBuild: &outputpaths.ActiveBuild{
+				BuildId:        "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+				StartTime:      &timestamppb.Timestamp{Seconds: 1700000000},
+				InstanceName:   "my-cluster",
+				DigestFunction: remoteexecution.DigestFunction_SHA256,
+			},
+		}, response)
+	})
+
+	t.Run("BuildFinalized", func(t *testing.T) {
+		// Once finalized, the output base should still be
+		// known, but no longer be associated with a build.
+		outputPath.EXPECT().FinalizeBuild(gomock.Any(), digest.MustNewFunction("my-cluster", remoteexecution.DigestFunction_SHA256))
+		clock.EXPECT().Now().Return(time.Unix(1700000100, 0))
+
+		_, err := d.FinalizeBuild(ctx, &remoteoutputservice.FinalizeBuildRequest{
+			BuildId:         "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			BuildSuccessful: true,
+		})
+		require.NoError(t, err)
+
+		response, err := d.GetActiveBuild(ctx, &outputpaths.GetActiveBuildRequest{
+			OutputBaseId: "9da951b8cb759233037166e28f7ea186",
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &outputpaths.GetActiveBuildResponse{}, response)
+	})
+}
+
+func TestRemoteOutputServiceDirectoryAbortBuild(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	handleAllocator := mock.NewMockStatefulHandleAllocator(ctrl)
+	outputPathFactory := mock.NewMockOutputPathFactory(ctrl)
+	bareContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	retryingContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	directoryFetcher := mock.NewMockDirectoryFetcher(ctrl)
+	symlinkFactory := mock.NewMockSymlinkFactory(ctrl)
+	dHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	handleAllocator.EXPECT().New().Return(dHandleAllocation)
+	dHandle := mock.NewMockStatefulDirectoryHandle(ctrl)
+	dHandleAllocation.EXPECT().AsStatefulDirectory(gomock.Any()).Return(dHandle)
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(1000, 0)).AnyTimes()
+	d := newTestRemoteOutputServiceDirectory(
+		handleAllocator,
+		outputPathFactory,
+		bareContentAddressableStorage,
+		retryingContentAddressableStorage,
+		directoryFetcher,
+		symlinkFactory,
+		allowAllAuthorizer,
+		clock,
+		cd_vfs.RemoteOutputServiceDirectoryOptions{})
+
+	t.Run("UnknownBuildID", func(t *testing.T) {
+		// Requests for unknown build IDs should be ignored.
+		_, err := d.AbortBuild(ctx, &outputpaths.AbortBuildRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+		handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+		casFileHandleAllocator := mock.NewMockStatelessHandleAllocator(ctrl)
+		casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(casFileHandleAllocator)
+		outputPath := mock.NewMockOutputPath(ctrl)
+		var casFileFactory re_vfs.CASFileFactory
+		outputPathFactory.EXPECT().StartInitialBuild(
+			path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+			gomock.Any(),
+			digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256),
+			gomock.Any(),
+		).DoAndReturn(func(outputBaseID path.Component, cff re_vfs.CASFileFactory, digestFunction digest.Function, errorLogger util.ErrorLogger) cd_vfs.OutputPath {
+			casFileFactory = cff
+			return outputPath
+		})
+		outputPath.EXPECT().FilterChildren(gomock.Any())
+
+		_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+			OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+			BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			DigestFunction:   remoteexecution.DigestFunction_SHA256,
+			OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+		})
+		require.NoError(t, err)
+
+		// Start reading a file, for which fetching its contents
+		// blocks until cancelled.
+		fileDigest := digest.MustNewDigest("", remoteexecution.DigestFunction_SHA256, "185f8db32271fe25f561a6fc938b2e264306ec304eda518007d1764826381969", 5)
+		fileHandleAllocation := mock.NewMockStatelessHandleAllocation(ctrl)
+		casFileHandleAllocator.EXPECT().New(gomock.Any()).Return(fileHandleAllocation)
+		fileHandleAllocation.EXPECT().AsNativeLeaf(gomock.Any()).
+			DoAndReturn(func(leaf re_vfs.NativeLeaf) re_vfs.NativeLeaf { return leaf })
+		file := casFileFactory.LookupFile(fileDigest, false, nil)
+
+		fetchStarted := make(chan struct{})
+		retryingContentAddressableStorage.EXPECT().Get(gomock.Any(), fileDigest).
+			DoAndReturn(func(ctx context.Context, blobDigest digest.Digest) buffer.Buffer {
+				close(fetchStarted)
+				<-ctx.Done()
+				return buffer.NewBufferFromError(util.StatusFromContext(ctx))
+			})
+		readStatus := make(chan re_vfs.Status, 1)
+		go func() {
+			var buf [5]byte
+			_, _, s := file.VirtualRead(buf[:], 0)
+			readStatus <- s
+		}()
+		<-fetchStarted
+
+		// Aborting the build should not cause the output path
+		// to be finalized. It should cancel the fetch.
+		_, err = d.AbortBuild(ctx, &outputpaths.AbortBuildRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		})
+		require.NoError(t, err)
+		require.Equal(t, re_vfs.StatusErrIO, <-readStatus)
+
+		// Successive reads should not be affected.
+		retryingContentAddressableStorage.EXPECT().Get(gomock.Any(), fileDigest).
+			DoAndReturn(func(ctx context.Context, blobDigest digest.Digest) buffer.Buffer {
+				require.NoError(t, ctx.Err())
+				return buffer.NewValidatedBufferFromByteSlice([]byte("Hello"))
+			})
+		var buf [5]byte
+		n, eof, s := file.VirtualRead(buf[:], 0)
+		require.Equal(t, re_vfs.StatusOK, s)
+		require.True(t, eof)
+		require.Equal(t, []byte("Hello"), buf[:n])
+
+		// The build ID should no longer be usable.
+		_, err = d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Paths:   []string{"foo.o"},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.FailedPrecondition, "Build ID is not associated with any running build"), err)
+
+		_, err = d.FinalizeBuild(ctx, &remoteoutputservice.FinalizeBuildRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		})
+		require.NoError(t, err)
+
+		// The output path should remain accessible.
+		outputPath.EXPECT().VirtualGetAttributes(ctx, re_vfs.AttributesMask(0), gomock.Any())
+
+		var out re_vfs.Attributes
+		child, s := d.VirtualLookup(ctx, path.MustNewComponent("9da951b8cb759233037166e28f7ea186"), 0, &out)
+		require.Equal(t, re_vfs.StatusOK, s)
+		require.Equal(t, re_vfs.DirectoryChild{}.FromDirectory(outputPath), child)
+	})
+}
+
+func TestRemoteOutputServiceDirectoryWatchBuilds(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	handleAllocator := mock.NewMockStatefulHandleAllocator(ctrl)
+	outputPathFactory := mock.NewMockOutputPathFactory(ctrl)
+	bareContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	retryingContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	directoryFetcher := mock.NewMockDirectoryFetcher(ctrl)
+	symlinkFactory := mock.NewMockSymlinkFactory(ctrl)
+	dHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	handleAllocator.EXPECT().New().Return(dHandleAllocation)
+	dHandle := mock.NewMockStatefulDirectoryHandle(ctrl)
+	dHandleAllocation.EXPECT().AsStatefulDirectory(gomock.Any()).Return(dHandle)
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(1000, 0)).AnyTimes()
+	d := newTestRemoteOutputServiceDirectory(
+		handleAllocator,
+		outputPathFactory,
+		bareContentAddressableStorage,
+		retryingContentAddressableStorage,
+		directoryFetcher,
+		symlinkFactory,
+		allowAllAuthorizer,
+		clock,
+		cd_vfs.RemoteOutputServiceDirectoryOptions{})
+
+	t.Run("CursorNotIssued", func(t *testing.T) {
+		server := mock.NewMockOutputPathsWatchBuildsServer(ctrl)
+		server.EXPECT().Context().Return(ctx).AnyTimes()
+
+		testutil.RequireEqualStatus(
+			t,
+			status.Error(codes.OutOfRange, "Cursor 1 is greater than the cursor of the most recent event, 0"),
+			d.WatchBuilds(&outputpaths.WatchBuildsRequest{
+				Start: &outputpaths.WatchBuildsRequest_AfterCursor{AfterCursor: 1},
+			}, server))
+	})
+
+	// Start, finalize and abort builds, and clean the output base
+	// afterwards.
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocator := mock.NewMockStatelessHandleAllocator(ctrl)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(casFileHandleAllocator)
+	outputPath := mock.NewMockOutputPath(ctrl)
+	outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+		gomock.Any(),
+		digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256),
+		gomock.Any(),
+	).Return(outputPath)
+	outputPath.EXPECT().FilterChildren(gomock.Any()).Times(2)
+	outputPath.EXPECT().FinalizeBuild(gomock.Any(), digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256))
+	outputPath.EXPECT().RemoveAllChildren(true)
+	dHandle.EXPECT().NotifyRemoval(path.MustNewComponent("9da951b8cb759233037166e28f7ea186"))
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		DigestFunction:   remoteexecution.DigestFunction_SHA256,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+	_, err = d.FinalizeBuild(ctx, &remoteoutputservice.FinalizeBuildRequest{
+		BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+	})
+	require.NoError(t, err)
+	_, err = d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "c4a1f8e2-5a3e-4b0e-9f6d-0f6a3c2b1d7e",
+		DigestFunction:   remoteexecution.DigestFunction_SHA256,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+	_, err = d.Clean(ctx, &remoteoutputservice.CleanRequest{
+		OutputBaseId: "9da951b8cb759233037166e28f7ea186",
+	})
+	require.NoError(t, err)
+
+	newEvent := func(cursor uint64, eventType outputpaths.BuildEvent_Type, buildID string) *outputpaths.BuildEvent {
+		return &outputpaths.BuildEvent{
+			Cursor:       cursor,
+			Type:         eventType,
+			OutputBaseId: "9da951b8cb759233037166e28f7ea186",
+			BuildId:      buildID,
+			Timestamp:    &timestamppb.Timestamp{Seconds: 1000},
+		}
+	}
+
+	t.Run("ResumeFromCursor", func(t *testing.T) {
+		// Events following the provided cursor should be
+		// returned, after which the call blocks for new events.
+		watchCtx, cancel := context.WithCancel(ctx)
+		server := mock.NewMockOutputPathsWatchBuildsServer(ctrl)
+		server.EXPECT().Context().Return(watchCtx).AnyTimes()
+		server.EXPECT().Send(testutil.EqProto(t, &outputpaths.WatchBuildsResponse{
+			Events: []*outputpaths.BuildEvent{
+				newEvent(2, outputpaths.BuildEvent_BUILD_FINALIZED, "37f5dbef-b117-4fb6-bce8-5c147cb603b4"),
+				newEvent(3, outputpaths.BuildEvent_BUILD_STARTED, "c4a1f8e2-5a3e-4b0e-9f6d-0f6a3c2b1d7e"),
+				newEvent(4, outputpaths.BuildEvent_BUILD_ABORTED, "c4a1f8e2-5a3e-4b0e-9f6d-0f6a3c2b1d7e"),
+				newEvent(5, outputpaths.BuildEvent_OUTPUT_BASE_CLEANED, ""),
+			},
+		})).DoAndReturn(func(response *outputpaths.WatchBuildsResponse) error {
+			cancel()
+			return nil
+		})
+
+		testutil.RequireEqualStatus(
+			t,
+			status.Error(codes.Canceled, "context canceled"),
+			d.WatchBuilds(&outputpaths.WatchBuildsRequest{
+				Start: &outputpaths.WatchBuildsRequest_AfterCursor{AfterCursor: 1},
+			}, server))
+	})
+
+	t.Run("SendFailure", func(t *testing.T) {
+		server := mock.NewMockOutputPathsWatchBuildsServer(ctrl)
+		server.EXPECT().Context().Return(ctx).AnyTimes()
+		server.EXPECT().Send(gomock.Any()).Return(status.Error(codes.Unavailable, "Connection closed"))
+
+		testutil.RequireEqualStatus(
+			t,
+			status.Error(codes.Unavailable, "Connection closed"),
+			d.WatchBuilds(&outputpaths.WatchBuildsRequest{
+				Start: &outputpaths.WatchBuildsRequest_AfterCursor{AfterCursor: 0},
+			}, server))
+	})
+
+	t.Run("Now", func(t *testing.T) {
+		// By default, only events emitted after the call is
+		// made should be returned. Clean the output base once
+		// the call has captured the current cursor.
+		watchCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		started := make(chan struct{})
+		server := mock.NewMockOutputPathsWatchBuildsServer(ctrl)
+		server.EXPECT().Context().DoAndReturn(func() context.Context {
+			close(started)
+			return watchCtx
+		})
+		server.EXPECT().Send(testutil.EqProto(t, &outputpaths.WatchBuildsResponse{
+			Events: []*outputpaths.BuildEvent{
+				newEvent(6, outputpaths.BuildEvent_OUTPUT_BASE_CLEANED, ""),
+			},
+		})).DoAndReturn(func(response *outputpaths.WatchBuildsResponse) error {
+			cancel()
+			return nil
+		})
+
+		errCh := make(chan error, 1)
+		go func() {
+			errCh <- d.WatchBuilds(&outputpaths.WatchBuildsRequest{}, server)
+		}()
+		<-started
+
+		outputPathFactory.EXPECT().Clean(path.MustNewComponent("9da951b8cb759233037166e28f7ea186"))
+		_, err := d.Clean(ctx, &remoteoutputservice.CleanRequest{
+			OutputBaseId: "9da951b8cb759233037166e28f7ea186",
+		})
+		require.NoError(t, err)
+
+		testutil.RequireEqualStatus(t, status.Error(codes.Canceled, "context canceled"), <-errCh)
+	})
+}
+
+func TestRemoteOutputServiceDirectoryEntryRemovalEvents(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	handleAllocator := mock.NewMockStatefulHandleAllocator(ctrl)
+	outputPathFactory := mock.NewMockOutputPathFactory(ctrl)
+	bareContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	retryingContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	directoryFetcher := mock.NewMockDirectoryFetcher(ctrl)
+	symlinkFactory := mock.NewMockSymlinkFactory(ctrl)
+	dHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	handleAllocator.EXPECT().New().Return(dHandleAllocation)
+	dHandle := mock.NewMockStatefulDirectoryHandle(ctrl)
+	dHandleAllocation.EXPECT().AsStatefulDirectory(gomock.Any()).Return(dHandle)
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(1000, 0)).AnyTimes()
+	d := newTestRemoteOutputServiceDirectory(
+		handleAllocator,
+		outputPathFactory,
+		bareContentAddressableStorage,
+		retryingContentAddressableStorage,
+		directoryFetcher,
+		symlinkFactory,
+		allowAllAuthorizer,
+		clock,
+		cd_vfs.RemoteOutputServiceDirectoryOptions{
+			MaximumEntryRemovalEvents: 1,
+		})
+
+	// Start a build in an output path containing a file that is
+	// absent, and a file that uses a different digest function.
+	// Both files should be removed, but only a single event should
+	// be emitted.
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocator := mock.NewMockStatelessHandleAllocator(ctrl)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(casFileHandleAllocator)
+	outputPath := mock.NewMockOutputPath(ctrl)
+	outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+		gomock.Any(),
+		digest.MustNewFunction("my-cluster", remoteexecution.DigestFunction_MD5),
+		gomock.Any(),
+	).Return(outputPath)
+
+	digest1 := digest.MustNewDigest("my-cluster", remoteexecution.DigestFunction_SHA256, "a948904f2f0f479b8f8197694b30184b0d2ed1c1cd2a1ec0fb85d299a192a447", 12)
+	digest2 := digest.MustNewDigest("my-cluster", remoteexecution.DigestFunction_MD5, "338db227a0de09b4309e928cdbb7d40a", 42)
+	outputPath.EXPECT().FilterChildren(gomock.Any()).DoAndReturn(func(childFilter re_vfs.ChildFilter) error {
+		for _, blobDigest := range []digest.Digest{digest1, digest2} {
+			child := mock.NewMockNativeLeaf(ctrl)
+			child.EXPECT().GetContainingDigests().Return(blobDigest.ToSingletonSet())
+			remover := mock.NewMockChildRemover(ctrl)
+			remover.EXPECT().Call()
+			require.True(t, childFilter(re_vfs.InitialNode{}.FromLeaf(child), remover.Call))
+		}
+		return nil
+	})
+	bareContentAddressableStorage.EXPECT().FindMissing(ctx, digest2.ToSingletonSet()).
+		Return(digest2.ToSingletonSet(), nil)
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		InstanceName:     "my-cluster",
+		DigestFunction:   remoteexecution.DigestFunction_MD5,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+
+	watchCtx, cancel := context.WithCancel(ctx)
+	server := mock.NewMockOutputPathsWatchBuildsServer(ctrl)
+	server.EXPECT().Context().Return(watchCtx).AnyTimes()
+	server.EXPECT().Send(testutil.EqProto(t, &outputpaths.WatchBuildsResponse{
+		Events: []*outputpaths.BuildEvent{
+			{
+				Cursor:       1,
+				Type:         outputpaths.BuildEvent_BUILD_STARTED,
+				OutputBaseId: "9da951b8cb759233037166e28f7ea186",
+				BuildId:      "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+				Timestamp:    &timestamppb.Timestamp{Seconds: 1000},
+			},
+			{
+				Cursor:       2,
+				Type:         outputpaths.BuildEvent_ENTRY_REMOVED,
+				OutputBaseId: "9da951b8cb759233037166e28f7ea186",
+				BuildId:      "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+				Timestamp:    &timestamppb.Timestamp{Seconds: 1000},
+				EntryRemoval: &outputpaths.BuildEvent_EntryRemoval{
+					Reason:         outputpaths.BuildEvent_EntryRemoval_INCOMPATIBLE_DIGEST,
+					Digest:         digest1.GetProto(),
+					DigestFunction: remoteexecution.DigestFunction_SHA256,
+				},
+			},
+		},
+	})).DoAndReturn(func(response *outputpaths.WatchBuildsResponse) error {
+		cancel()
+		return nil
+	})
+
+	testutil.RequireEqualStatus(
+		t,
+		status.Error(codes.Canceled, "context canceled"),
+		d.WatchBuilds(&outputpaths.WatchBuildsRequest{
+			Start: &outputpaths.WatchBuildsRequest_AfterCursor{AfterCursor: 0},
+		}, server))
+}
+
+func TestRemoteOutputServiceDirectoryEntryRemovalEventsMissingDirectoryContents(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	handleAllocator := mock.NewMockStatefulHandleAllocator(ctrl)
+	outputPathFactory := mock.NewMockOutputPathFactory(ctrl)
+	bareContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	retryingContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	directoryFetcher := mock.NewMockDirectoryFetcher(ctrl)
+	symlinkFactory := mock.NewMockSymlinkFactory(ctrl)
+	dHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	handleAllocator.EXPECT().New().Return(dHandleAllocation)
+	dHandle := mock.NewMockStatefulDirectoryHandle(ctrl)
+	dHandleAllocation.EXPECT().AsStatefulDirectory(gomock.Any()).Return(dHandle)
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(1000, 0)).AnyTimes()
+	d := newTestRemoteOutputServiceDirectory(
+		handleAllocator,
+		outputPathFactory,
+		bareContentAddressableStorage,
+		retryingContentAddressableStorage,
+		directoryFetcher,
+		symlinkFactory,
+		allowAllAuthorizer,
+		clock,
+		cd_vfs.RemoteOutputServiceDirectoryOptions{
+			MaximumEntryRemovalEvents: 10,
+		})
+
+	// Start a build in an output path containing a directory of
+	// which multiple files are absent. The directory should only
+	// be removed once, and a single ENTRY_REMOVED event should be
+	// emitted. It should report the first digest that was found to
+	// be missing, as opposed to the last digest contained in the
+	// directory.
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocator := mock.NewMockStatelessHandleAllocator(ctrl)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(casFileHandleAllocator)
+	outputPath := mock.NewMockOutputPath(ctrl)
+	outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+		gomock.Any(),
+		digest.MustNewFunction("my-cluster", remoteexecution.DigestFunction_MD5),
+		gomock.Any(),
+	).Return(outputPath)
+
+	digest1 := digest.MustNewDigest("my-cluster", remoteexecution.DigestFunction_MD5, "2c0f843d40e00603f0d71e0d11a6e045", 7)
+	digest2 := digest.MustNewDigest("my-cluster", remoteexecution.DigestFunction_MD5, "6b9105a7125cb9f190a3e44ab5f22663", 8)
+	digests := digest.NewSetBuilder().Add(digest1).Add(digest2).Build()
+	outputPath.EXPECT().FilterChildren(gomock.Any()).DoAndReturn(func(childFilter re_vfs.ChildFilter) error {
+		child := mock.NewMockInitialContentsFetcher(ctrl)
+		child.EXPECT().GetContainingDigests(ctx).Return(digests, nil)
+		remover := mock.NewMockChildRemover(ctrl)
+		remover.EXPECT().Call()
+		require.True(t, childFilter(re_vfs.InitialNode{}.FromDirectory(child), remover.Call))
+		return nil
+	})
+	bareContentAddressableStorage.EXPECT().FindMissing(ctx, digests).Return(digests, nil)
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		InstanceName:     "my-cluster",
+		DigestFunction:   remoteexecution.DigestFunction_MD5,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+
+	watchCtx, cancel := context.WithCancel(ctx)
+	server := mock.NewMockOutputPathsWatchBuildsServer(ctrl)
+	server.EXPECT().Context().Return(watchCtx).AnyTimes()
+	server.EXPECT().Send(testutil.EqProto(t, &outputpaths.WatchBuildsResponse{
+		Events: []*outputpaths.BuildEvent{
+			{
+				Cursor:       1,
+				Type:         outputpaths.BuildEvent_BUILD_STARTED,
+				OutputBaseId: "9da951b8cb759233037166e28f7ea186",
+				BuildId:      "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+				Timestamp:    &timestamppb.Timestamp{Seconds: 1000},
+			},
+			{
+				Cursor:       2,
+				Type:         outputpaths.BuildEvent_ENTRY_REMOVED,
+				OutputBaseId: "9da951b8cb759233037166e28f7ea186",
+				BuildId:      "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+				Timestamp:    &timestamppb.Timestamp{Seconds: 1000},
+				EntryRemoval: &outputpaths.BuildEvent_EntryRemoval{
+					Reason:         outputpaths.BuildEvent_EntryRemoval_MISSING,
+					Digest:         digest1.GetProto(),
+					DigestFunction: remoteexecution.DigestFunction_MD5,
+				},
+			},
+		},
+	})).DoAndReturn(func(response *outputpaths.WatchBuildsResponse) error {
+		cancel()
+		return nil
+	})
+
+	testutil.RequireEqualStatus(
+		t,
+		status.Error(codes.Canceled, "context canceled"),
+		d.WatchBuilds(&outputpaths.WatchBuildsRequest{
+			Start: &outputpaths.WatchBuildsRequest_AfterCursor{AfterCursor: 0},
+		}, server))
+}
+
+func TestRemoteOutputServiceDirectoryEntryRemovalEventsRetention(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	handleAllocator := mock.NewMockStatefulHandleAllocator(ctrl)
+	outputPathFactory := mock.NewMockOutputPathFactory(ctrl)
+	bareContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	retryingContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	directoryFetcher := mock.NewMockDirectoryFetcher(ctrl)
+	symlinkFactory := mock.NewMockSymlinkFactory(ctrl)
+	dHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	handleAllocator.EXPECT().New().Return(dHandleAllocation)
+	dHandle := mock.NewMockStatefulDirectoryHandle(ctrl)
+	dHandleAllocation.EXPECT().AsStatefulDirectory(gomock.Any()).Return(dHandle)
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(1000, 0)).AnyTimes()
+	d := newTestRemoteOutputServiceDirectory(
+		handleAllocator,
+		outputPathFactory,
+		bareContentAddressableStorage,
+		retryingContentAddressableStorage,
+		directoryFetcher,
+		symlinkFactory,
+		allowAllAuthorizer,
+		clock,
+		cd_vfs.RemoteOutputServiceDirectoryOptions{
+			MaximumEntryRemovalEvents: math.MaxInt,
+		})
+
+	// Start a build in an output path containing more files with
+	// incompatible digests than the number of ENTRY_REMOVED events
+	// that is retained.
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocator := mock.NewMockStatelessHandleAllocator(ctrl)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(casFileHandleAllocator)
+	outputPath := mock.NewMockOutputPath(ctrl)
+	outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+		gomock.Any(),
+		digest.MustNewFunction("", remoteexecution.DigestFunction_MD5),
+		gomock.Any(),
+	).Return(outputPath)
+	outputPath.EXPECT().FilterChildren(gomock.Any()).DoAndReturn(func(childFilter re_vfs.ChildFilter) error {
+		for i := 0; i < 1500; i++ {
+			child := mock.NewMockNativeLeaf(ctrl)
+			child.EXPECT().GetContainingDigests().Return(
+				digest.MustNewDigest("", remoteexecution.DigestFunction_SHA256, "a948904f2f0f479b8f8197694b30184b0d2ed1c1cd2a1ec0fb85d299a192a447", int64(i)).ToSingletonSet())
+			remover := mock.NewMockChildRemover(ctrl)
+			remover.EXPECT().Call()
+			require.True(t, childFilter(re_vfs.InitialNode{}.FromLeaf(child), remover.Call))
+		}
+		return nil
+	})
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		DigestFunction:   remoteexecution.DigestFunction_MD5,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+
+	// Resuming from the start should still be possible, returning
+	// the BUILD_STARTED event, followed by the ENTRY_REMOVED events
+	// that are still retained.
+	watchCtx, cancel := context.WithCancel(ctx)
+	server := mock.NewMockOutputPathsWatchBuildsServer(ctrl)
+	server.EXPECT().Context().Return(watchCtx).AnyTimes()
+	server.EXPECT().Send(gomock.Any()).DoAndReturn(func(response *outputpaths.WatchBuildsResponse) error {
+		require.Len(t, response.Events, 1001)
+		require.Equal(t, uint64(1), response.Events[0].Cursor)
+		require.Equal(t, outputpaths.BuildEvent_BUILD_STARTED, response.Events[0].Type)
+		require.Equal(t, uint64(502), response.Events[1].Cursor)
+		require.Equal(t, outputpaths.BuildEvent_ENTRY_REMOVED, response.Events[1].Type)
+		require.Equal(t, uint64(1501), response.Events[1000].Cursor)
+		cancel()
+		return nil
+	})
+
+	testutil.RequireEqualStatus(
+		t,
+		status.Error(codes.Canceled, "context canceled"),
+		d.WatchBuilds(&outputpaths.WatchBuildsRequest{
+			Start: &outputpaths.WatchBuildsRequest_AfterCursor{AfterCursor: 0},
+		}, server))
+}
+
+func TestRemoteOutputServiceDirectoryRootSymlinks(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	handleAllocator := mock.NewMockStatefulHandleAllocator(ctrl)
+	outputPathFactory := mock.NewMockOutputPathFactory(ctrl)
+	bareContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	retryingContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	directoryFetcher := mock.NewMockDirectoryFetcher(ctrl)
+	symlinkFactory := mock.NewMockSymlinkFactory(ctrl)
+	dHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	handleAllocator.EXPECT().New().Return(dHandleAllocation)
+	dHandle := mock.NewMockStatefulDirectoryHandle(ctrl)
+	dHandleAllocation.EXPECT().AsStatefulDirectory(gomock.Any()).Return(dHandle)
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(1000, 0)).AnyTimes()
+	d := newTestRemoteOutputServiceDirectory(
+		handleAllocator,
+		outputPathFactory,
+		bareContentAddressableStorage,
+		retryingContentAddressableStorage,
+		directoryFetcher,
+		symlinkFactory,
+		allowAllAuthorizer,
+		clock,
+		cd_vfs.RemoteOutputServiceDirectoryOptions{})
+
+	t.Run("InvalidName", func(t *testing.T) {
+		_, err := d.CreateRootSymlink(ctx, &outputpaths.CreateRootSymlinkRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Name:    "..",
+			Target:  "9da951b8cb759233037166e28f7ea186/bin",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Symbolic link name is not a valid filename: Name may not be \"..\""), err)
+	})
+
+	t.Run("NameContainingSlash", func(t *testing.T) {
+		_, err := d.CreateRootSymlink(ctx, &outputpaths.CreateRootSymlinkRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Name:    "bazel/bin",
+			Target:  "9da951b8cb759233037166e28f7ea186/bin",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Symbolic link name is not a valid filename: Name contains a slash"), err)
+	})
+
+	t.Run("UnknownBuildID", func(t *testing.T) {
+		_, err := d.CreateRootSymlink(ctx, &outputpaths.CreateRootSymlinkRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Name:    "bazel-bin",
+			Target:  "9da951b8cb759233037166e28f7ea186/bin",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.FailedPrecondition, "Build ID is not associated with any running build"), err)
+	})
+
+	// Start a build, so that symbolic links can be created.
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocator := mock.NewMockStatelessHandleAllocator(ctrl)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(casFileHandleAllocator)
+	outputPath := mock.NewMockOutputPath(ctrl)
+	outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+		gomock.Any(),
+		digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256),
+		gomock.Any(),
+	).Return(outputPath)
+	outputPath.EXPECT().FilterChildren(gomock.Any())
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		DigestFunction:   remoteexecution.DigestFunction_SHA256,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+
+	t.Run("NameOfOutputPath", func(t *testing.T) {
+		// Symbolic links may not shadow output paths.
+		leaf := mock.NewMockNativeLeaf(ctrl)
+		symlinkFactory.EXPECT().LookupSymlink([]byte("foo")).Return(leaf)
+		leaf.EXPECT().Unlink()
+
+		_, err := d.CreateRootSymlink(ctx, &outputpaths.CreateRootSymlinkRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Name:    "9da951b8cb759233037166e28f7ea186",
+			Target:  "foo",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.AlreadyExists, "An output path with the same name already exists"), err)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		// Create a symbolic link. It should be visible through
+		// VirtualLookup() and VirtualReadDir().
+		leaf1 := mock.NewMockNativeLeaf(ctrl)
+		symlinkFactory.EXPECT().LookupSymlink([]byte("9da951b8cb759233037166e28f7ea186/k8-fastbuild/bin")).Return(leaf1)
+
+		_, err := d.CreateRootSymlink(ctx, &outputpaths.CreateRootSymlinkRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Name:    "bazel-bin",
+			Target:  "9da951b8cb759233037166e28f7ea186/k8-fastbuild/bin",
+		})
+		require.NoError(t, err)
+
+		leaf1.EXPECT().VirtualGetAttributes(ctx, re_vfs.AttributesMask(0), gomock.Any())
+		var out re_vfs.Attributes
+		child, s := d.VirtualLookup(ctx, path.MustNewComponent("bazel-bin"), 0, &out)
+		require.Equal(t, re_vfs.StatusOK, s)
+		require.Equal(t, re_vfs.DirectoryChild{}.FromLeaf(leaf1), child)
+
+		reporter := mock.NewMockDirectoryEntryReporter(ctrl)
+		outputPath.EXPECT().VirtualGetAttributes(ctx, re_vfs.AttributesMaskInodeNumber, gomock.Any()).
+			Do(func(ctx context.Context, requested re_vfs.AttributesMask, out *re_vfs.Attributes) {
+				out.SetInodeNumber(101)
+			})
+		reporter.EXPECT().ReportEntry(
+			uint64(1),
+			path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+			re_vfs.DirectoryChild{}.FromDirectory(outputPath),
+			(&re_vfs.Attributes{}).SetInodeNumber(101),
+		).Return(true)
+		leaf1.EXPECT().VirtualGetAttributes(ctx, re_vfs.AttributesMaskInodeNumber, gomock.Any()).
+			Do(func(ctx context.Context, requested re_vfs.AttributesMask, out *re_vfs.Attributes) {
+				out.SetInodeNumber(102)
+			})
+		reporter.EXPECT().ReportEntry(
+			uint64(2),
+			path.MustNewComponent("bazel-bin"),
+			re_vfs.DirectoryChild{}.FromLeaf(leaf1),
+			(&re_vfs.Attributes{}).SetInodeNumber(102),
+		).Return(true)
+		require.Equal(t, re_vfs.StatusOK, d.VirtualReadDir(ctx, 0, re_vfs.AttributesMaskInodeNumber, reporter))
+
+		// Replacing the symbolic link should release the
+		// previous one.
+		leaf2 := mock.NewMockNativeLeaf(ctrl)
+		symlinkFactory.EXPECT().LookupSymlink([]byte("9da951b8cb759233037166e28f7ea186/k8-opt/bin")).Return(leaf2)
+		leaf1.EXPECT().Unlink()
+		dHandle.EXPECT().NotifyRemoval(path.MustNewComponent("bazel-bin"))
+
+		_, err = d.CreateRootSymlink(ctx, &outputpaths.CreateRootSymlinkRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Name:    "bazel-bin",
+			Target:  "9da951b8cb759233037166e28f7ea186/k8-opt/bin",
+		})
+		require.NoError(t, err)
+
+		// Removing the symbolic link should make it disappear.
+		// Successive removals should be ignored.
+		leaf2.EXPECT().Unlink()
+		dHandle.EXPECT().NotifyRemoval(path.MustNewComponent("bazel-bin"))
+
+		for i := 0; i < 2; i++ {
+			_, err = d.RemoveRootSymlink(ctx, &outputpaths.RemoveRootSymlinkRequest{
+				BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+				Name:    "bazel-bin",
+			})
+			require.NoError(t, err)
+		}
+
+		_, s = d.VirtualLookup(ctx, path.MustNewComponent("bazel-bin"), 0, &out)
+		require.Equal(t, re_vfs.StatusErrNoEnt, s)
+	})
+
+	t.Run("Clean", func(t *testing.T) {
+		// Cleaning the output base should also remove the
+		// symbolic links belonging to it.
+		leaf := mock.NewMockNativeLeaf(ctrl)
+		symlinkFactory.EXPECT().LookupSymlink([]byte("9da951b8cb759233037166e28f7ea186/k8-fastbuild/bin")).Return(leaf)
+
+		_, err := d.CreateRootSymlink(ctx, &outputpaths.CreateRootSymlinkRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Name:    "bazel-bin",
+			Target:  "9da951b8cb759233037166e28f7ea186/k8-fastbuild/bin",
+		})
+		require.NoError(t, err)
+
+		outputPath.EXPECT().RemoveAllChildren(true)
+		leaf.EXPECT().Unlink()
+		dHandle.EXPECT().NotifyRemoval(path.MustNewComponent("9da951b8cb759233037166e28f7ea186"))
+		dHandle.EXPECT().NotifyRemoval(path.MustNewComponent("bazel-bin"))
+
+		_, err = d.Clean(ctx, &remoteoutputservice.CleanRequest{
+			OutputBaseId: "9da951b8cb759233037166e28f7ea186",
+		})
+		require.NoError(t, err)
+
+		var out re_vfs.Attributes
+		_, s := d.VirtualLookup(ctx, path.MustNewComponent("bazel-bin"), 0, &out)
+		require.Equal(t, re_vfs.StatusErrNoEnt, s)
+	})
+}
+
+func TestRemoteOutputServiceDirectoryGetOutputPathErrors(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	handleAllocator := mock.NewMockStatefulHandleAllocator(ctrl)
+	outputPathFactory := mock.NewMockOutputPathFactory(ctrl)
+	bareContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	retryingContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	directoryFetcher := mock.NewMockDirectoryFetcher(ctrl)
+	symlinkFactory := mock.NewMockSymlinkFactory(ctrl)
+	dHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	handleAllocator.EXPECT().New().Return(dHandleAllocation)
+	dHandle := mock.NewMockStatefulDirectoryHandle(ctrl)
+	dHandleAllocation.EXPECT().AsStatefulDirectory(gomock.Any()).Return(dHandle)
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(1000, 0)).AnyTimes()
+	d := newTestRemoteOutputServiceDirectory(
+		handleAllocator,
+		outputPathFactory,
+		bareContentAddressableStorage,
+		retryingContentAddressableStorage,
+		directoryFetcher,
+		symlinkFactory,
+		allowAllAuthorizer,
+		clock,
+		cd_vfs.RemoteOutputServiceDirectoryOptions{})
+
+	t.Run("InvalidOutputBaseID", func(t *testing.T) {
+		_, err := d.GetOutputPathErrors(ctx, &outputpaths.GetOutputPathErrorsRequest{
+			OutputBaseId: "..",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Output base ID must consist of one or more valid filenames separated by slashes: Component 1 may not be \"..\""), err)
+	})
+
+	t.Run("UnknownOutputBaseID", func(t *testing.T) {
+		_, err := d.GetOutputPathErrors(ctx, &outputpaths.GetOutputPathErrorsRequest{
+			OutputBaseId: "9da951b8cb759233037166e28f7ea186",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.NotFound, "Output base ID is not associated with any output path"), err)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		// Capture the error logger that is provided to the
+		// output path, so that we can report errors.
+		casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+		handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+		casFileHandleAllocator := mock.NewMockStatelessHandleAllocator(ctrl)
+		casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(casFileHandleAllocator)
+		outputPath := mock.NewMockOutputPath(ctrl)
+		var errorLogger util.ErrorLogger
+		outputPathFactory.EXPECT().StartInitialBuild(
+			path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+			gomock.Any(),
+			digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256),
+			gomock.Any(),
+		).DoAndReturn(func(outputBaseID path.Component, casFileFactory re_vfs.CASFileFactory, digestFunction digest.Function, el util.ErrorLogger) cd_vfs.OutputPath {
+			errorLogger = el
+			return outputPath
+		})
+		outputPath.EXPECT().FilterChildren(gomock.Any())
+
+		_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+			OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+			BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			DigestFunction:   remoteexecution.DigestFunction_SHA256,
+			OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+		})
+		require.NoError(t, err)
+
+		// Initially, no errors should be reported.
+		response, err := d.GetOutputPathErrors(ctx, &outputpaths.GetOutputPathErrorsRequest{
+			OutputBaseId: "9da951b8cb759233037166e28f7ea186",
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &outputpaths.GetOutputPathErrorsResponse{}, response)
+
+		// Errors should be retained, even if they are reported
+		// outside the context of a build.
+		outputPath.EXPECT().FinalizeBuild(gomock.Any(), digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256))
+
+		_, err = d.FinalizeBuild(ctx, &remoteoutputservice.FinalizeBuildRequest{
+			BuildId:         "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			BuildSuccessful: true,
+		})
+		require.NoError(t, err)
+		errorLogger.Log(status.Error(codes.NotFound, "Failed to read from 3-7b8d7b3915a2bad3ba7ff4bf0a489b64-123-hello at offset 0: Object not found"))
+
+		response, err = d.GetOutputPathErrors(ctx, &outputpaths.GetOutputPathErrorsRequest{
+			OutputBaseId: "9da951b8cb759233037166e28f7ea186",
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &outputpaths.GetOutputPathErrorsResponse{
+			Errors: []*outputpaths.OutputPathError{
+				{
+					Time:   &timestamppb.Timestamp{Seconds: 1000},
+					Status: status.New(codes.NotFound, "Failed to read from 3-7b8d7b3915a2bad3ba7ff4bf0a489b64-123-hello at offset 0: Object not found").Proto(),
+				},
+			},
+		}, response)
+
+		// Errors caused by failing fetches should report the type
+		// of object that could not be fetched.
+		treeFetchFailure, err := status.New(codes.NotFound, "Failed to initialize directory: Object not found").
+			WithDetails(&outputpaths.FetchFailure{ObjectType: outputpaths.FetchedObjectType_TREE})
+		require.NoError(t, err)
+		errorLogger.Log(treeFetchFailure.Err())
+
+		response, err = d.GetOutputPathErrors(ctx, &outputpaths.GetOutputPathErrorsRequest{
+			OutputBaseId: "9da951b8cb759233037166e28f7ea186",
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &outputpaths.GetOutputPathErrorsResponse{
+			Errors: []*outputpaths.OutputPathError{
+				{
+					Time:              &timestamppb.Timestamp{Seconds: 1000},
+					Status:            treeFetchFailure.Proto(),
+					FetchedObjectType: outputpaths.FetchedObjectType_TREE,
+				},
+			},
+		}, response)
+
+		// Errors should be cleared after they have been
+		// returned. If more errors are reported than can be
+		// retained, the oldest should be discarded.
+		for i := 0; i < 102; i++ {
+			errorLogger.Log(status.Errorf(codes.Internal, "Error %d", i))
+		}
+
+		response, err = d.GetOutputPathErrors(ctx, &outputpaths.GetOutputPathErrorsRequest{
+			OutputBaseId: "9da951b8cb759233037166e28f7ea186",
+		})
+		require.NoError(t, err)
+		require.Len(t, response.Errors, 100)
+		require.Equal(t, "Error 2", response.Errors[0].Status.Message)
+		require.Equal(t, uint64(2), response.DiscardedErrorsCount)
+	})
+}
+
+func TestRemoteOutputServiceDirectoryOutputPathErrorLoggerFactory(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	handleAllocator := mock.NewMockStatefulHandleAllocator(ctrl)
+	outputPathFactory := mock.NewMockOutputPathFactory(ctrl)
+	bareContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	retryingContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	directoryFetcher := mock.NewMockDirectoryFetcher(ctrl)
+	symlinkFactory := mock.NewMockSymlinkFactory(ctrl)
+	dHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	handleAllocator.EXPECT().New().Return(dHandleAllocation)
+	dHandle := mock.NewMockStatefulDirectoryHandle(ctrl)
+	dHandleAllocation.EXPECT().AsStatefulDirectory(gomock.Any()).Return(dHandle)
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(1000, 0)).AnyTimes()
+	errorLoggerFactory := mock.NewMockOutputPathErrorLoggerFactory(ctrl)
+	d := newTestRemoteOutputServiceDirectory(
+		handleAllocator,
+		outputPathFactory,
+		bareContentAddressableStorage,
+		retryingContentAddressableStorage,
+		directoryFetcher,
+		symlinkFactory,
+		allowAllAuthorizer,
+		clock,
+		cd_vfs.RemoteOutputServiceDirectoryOptions{
+			OutputPathErrorLoggerFactory: errorLoggerFactory,
+		})
+
+	// When an output path is created, the factory should be called
+	// to obtain the error logger to which errors of the output path
+	// are forwarded.
+	baseErrorLogger := mock.NewMockErrorLogger(ctrl)
+	errorLoggerFactory.EXPECT().NewErrorLogger("9da951b8cb759233037166e28f7ea186").Return(baseErrorLogger)
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocator := mock.NewMockStatelessHandleAllocator(ctrl)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(casFileHandleAllocator)
+	outputPath := mock.NewMockOutputPath(ctrl)
+	var errorLogger util.ErrorLogger
+	outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+		gomock.Any(),
+		digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256),
+		gomock.Any(),
+	).DoAndReturn(func(outputBaseID path.Component, casFileFactory re_vfs.CASFileFactory, digestFunction digest.Function, el util.ErrorLogger) cd_vfs.OutputPath {
+		errorLogger = el
+		return outputPath
+	})
+	outputPath.EXPECT().FilterChildren(gomock.Any())
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		DigestFunction:   remoteexecution.DigestFunction_SHA256,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+
+	// Errors should be forwarded to the error logger returned by
+	// the factory, while still being retained for
+	// GetOutputPathErrors().
+	treeFetchFailure, err := status.New(codes.NotFound, "Failed to initialize directory: Object not found").
+		WithDetails(&outputpaths.FetchFailure{ObjectType: outputpaths.FetchedObjectType_TREE})
+	require.NoError(t, err)
+	wrappedTreeFetchFailure, err := status.New(codes.NotFound, "Failed to fetch tree: Failed to initialize directory: Object not found").
+		WithDetails(&outputpaths.FetchFailure{ObjectType: outputpaths.FetchedObjectType_TREE})
+	require.NoError(t, err)
+	baseErrorLogger.EXPECT().Log(testutil.EqStatus(t, wrappedTreeFetchFailure.Err()))
+	errorLogger.Log(treeFetchFailure.Err())
+
+	response, err := d.GetOutputPathErrors(ctx, &outputpaths.GetOutputPathErrorsRequest{
+		OutputBaseId: "9da951b8cb759233037166e28f7ea186",
+	})
+	require.NoError(t, err)
+	testutil.RequireEqualProto(t, &outputpaths.GetOutputPathErrorsResponse{
+		Errors: []*outputpaths.OutputPathError{
+			{
+				Time:              &timestamppb.Timestamp{Seconds: 1000},
+				Status:            treeFetchFailure.Proto(),
+				FetchedObjectType: outputpaths.FetchedObjectType_TREE,
+			},
+		},
+	}, response)
+}
+
+func TestRemoteOutputServiceDirectoryGetOutputPathStats(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	handleAllocator := mock.NewMockStatefulHandleAllocator(ctrl)
+	outputPathFactory := mock.NewMockOutputPathFactory(ctrl)
+	bareContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	retryingContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	directoryFetcher := mock.NewMockDirectoryFetcher(ctrl)
+	symlinkFactory := mock.NewMockSymlinkFactory(ctrl)
+	dHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	handleAllocator.EXPECT().New().Return(dHandleAllocation)
+	dHandle := mock.NewMockStatefulDirectoryHandle(ctrl)
+	dHandleAllocation.EXPECT().AsStatefulDirectory(gomock.Any()).Return(dHandle)
+	clock := mock.NewMockClock(ctrl)
+	now := time.Unix(1000, 0)
+	clock.EXPECT().Now().DoAndReturn(func() time.Time { return now }).AnyTimes()
+	d := newTestRemoteOutputServiceDirectory(
+		handleAllocator,
+		outputPathFactory,
+		bareContentAddressableStorage,
+		retryingContentAddressableStorage,
+		directoryFetcher,
+		symlinkFactory,
+		allowAllAuthorizer,
+		clock,
+		cd_vfs.RemoteOutputServiceDirectoryOptions{
+			MetricsOutputBaseIDs: []string{"9da951b8cb759233037166e28f7ea186"},
+		})
+
+	t.Run("InvalidOutputBaseID", func(t *testing.T) {
+		_, err := d.GetOutputPathStats(ctx, &outputpaths.GetOutputPathStatsRequest{
+			OutputBaseId: "..",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Output base ID must consist of one or more valid filenames separated by slashes: Component 1 may not be \"..\""), err)
+	})
+
+	t.Run("UnknownOutputBaseID", func(t *testing.T) {
+		_, err := d.GetOutputPathStats(ctx, &outputpaths.GetOutputPathStatsRequest{
+			OutputBaseId: "9da951b8cb759233037166e28f7ea186",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.NotFound, "Output base ID is not associated with any output path"), err)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		// Capture the CAS file factory that is provided to the
+		// output path, so that we can read files.
+		casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+		handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+		casFileHandleAllocator := mock.NewMockStatelessHandleAllocator(ctrl)
+		casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(casFileHandleAllocator)
+		outputPath := mock.NewMockOutputPath(ctrl)
+		var casFileFactory re_vfs.CASFileFactory
+		outputPathFactory.EXPECT().StartInitialBuild(
+			path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+			gomock.Any(),
+			digest.MustNewFunction("", remoteexecution.DigestFunction_MD5),
+			gomock.Any(),
+		).DoAndReturn(func(outputBaseID path.Component, cff re_vfs.CASFileFactory, digestFunction digest.Function, errorLogger util.ErrorLogger) cd_vfs.OutputPath {
+			casFileFactory = cff
+			return outputPath
+		})
+		outputPath.EXPECT().FilterChildren(gomock.Any())
+
+		_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+			OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+			BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			DigestFunction:   remoteexecution.DigestFunction_MD5,
+			OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+		})
+		require.NoError(t, err)
+
+		// Initially, no fetches should be reported. The output
+		// path should have been validated by StartBuild().
+		response, err := d.GetOutputPathStats(ctx, &outputpaths.GetOutputPathStatsRequest{
+			OutputBaseId: "9da951b8cb759233037166e28f7ea186",
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &outputpaths.GetOutputPathStatsResponse{
+			BlobFetchLatency:   &outputpaths.FetchLatencyDistribution{},
+			TreeFetchLatency:   &outputpaths.FetchLatencyDistribution{},
+			LastValidationTime: &timestamppb.Timestamp{Seconds: 1000},
+		}, response)
+
+		// Read a file twice. The first read takes 1 millisecond,
+		// while the second read takes 1 second.
+		fileDigest := digest.MustNewDigest("", remoteexecution.DigestFunction_MD5, "8b1a9953c4611296a827abf8c47804d7", 5)
+		fileHandleAllocation := mock.NewMockStatelessHandleAllocation(ctrl)
+		casFileHandleAllocator.EXPECT().New(gomock.Any()).Return(fileHandleAllocation)
+		fileHandleAllocation.EXPECT().AsNativeLeaf(gomock.Any()).
+			DoAndReturn(func(leaf re_vfs.NativeLeaf) re_vfs.NativeLeaf { return leaf })
+		file := casFileFactory.LookupFile(fileDigest, false, nil)
+
+		for _, duration := range []time.Duration{time.Millisecond, time.Second} {
+			retryingContentAddressableStorage.EXPECT().Get(gomock.Any(), fileDigest).
+				DoAndReturn(func(ctx context.Context, blobDigest digest.Digest) buffer.Buffer {
+					now = now.Add(duration)
+					return buffer.NewValidatedBufferFromByteSlice([]byte("Hello"))
+				})
+			var buf [5]byte
+			n, eof, s := file.VirtualRead(buf[:], 0)
+			require.Equal(t, re_vfs.StatusOK, s)
+			require.True(t, eof)
+			require.Equal(t, []byte("Hello"), buf[:n])
+		}
+
+		// Percentiles are estimated by interpolating between
+		// bucket boundaries.
+		response, err = d.GetOutputPathStats(ctx, &outputpaths.GetOutputPathStatsRequest{
+			OutputBaseId: "9da951b8cb759233037166e28f7ea186",
+		})
+		require.NoError(t, err)
+		require.Equal(t, uint64(2), response.BlobFetchLatency.Count)
+		require.Equal(t, time.Millisecond, response.BlobFetchLatency.P50.AsDuration())
+		require.Less(t, 464*time.Millisecond, response.BlobFetchLatency.P95.AsDuration())
+		require.LessOrEqual(t, response.BlobFetchLatency.P95.AsDuration(), response.BlobFetchLatency.P99.AsDuration())
+		require.LessOrEqual(t, response.BlobFetchLatency.P99.AsDuration(), time.Second)
+		testutil.RequireEqualProto(t, &outputpaths.FetchLatencyDistribution{}, response.TreeFetchLatency)
+		testutil.RequireEqualProto(t, &timestamppb.Timestamp{Seconds: 1000}, response.LastValidationTime)
+
+		// Both reads should be accounted for in the number of
+		// bytes fetched by the build.
+		activeBuildResponse, err := d.GetActiveBuild(ctx, &outputpaths.GetActiveBuildRequest{
+			OutputBaseId: "9da951b8cb759233037166e28f7ea186",
+		})
+		require.NoError(t, err)
+		require.Equal(t, uint64(10), activeBuildResponse.ActiveBuild.CasBytesFetched)
+
+		// Starting another build should validate the output
+		// path again.
+		now = time.Unix(2000, 0)
+		outputPath.EXPECT().FilterChildren(gomock.Any())
+
+		_, err = d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+			OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+			BuildId:          "0a3c4a01-f2b1-4f4d-9d5f-8e1e1f2b3c4d",
+			DigestFunction:   remoteexecution.DigestFunction_MD5,
+			OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+		})
+		require.NoError(t, err)
+
+		response, err = d.GetOutputPathStats(ctx, &outputpaths.GetOutputPathStatsRequest{
+			OutputBaseId: "9da951b8cb759233037166e28f7ea186",
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &timestamppb.Timestamp{Seconds: 2000}, response.LastValidationTime)
+
+		// Bytes fetched by the previous build should not be
+		// attributed to the new build.
+		activeBuildResponse, err = d.GetActiveBuild(ctx, &outputpaths.GetActiveBuildRequest{
+			OutputBaseId: "9da951b8cb759233037166e28f7ea186",
+		})
+		require.NoError(t, err)
+		require.Equal(t, uint64(0), activeBuildResponse.ActiveBuild.CasBytesFetched)
+	})
+}
+
+func TestRemoteOutputServiceDirectoryGetDaemonStats(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	handleAllocator := mock.NewMockStatefulHandleAllocator(ctrl)
+	outputPathFactory := mock.NewMockOutputPathFactory(ctrl)
+	bareContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	retryingContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	directoryFetcher := mock.NewMockDirectoryFetcher(ctrl)
+	symlinkFactory := mock.NewMockSymlinkFactory(ctrl)
+	dHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	handleAllocator.EXPECT().New().Return(dHandleAllocation)
+	dHandle := mock.NewMockStatefulDirectoryHandle(ctrl)
+	dHandleAllocation.EXPECT().AsStatefulDirectory(gomock.Any()).Return(dHandle)
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(1000, 0)).AnyTimes()
+	d := newTestRemoteOutputServiceDirectory(
+		handleAllocator,
+		outputPathFactory,
+		bareContentAddressableStorage,
+		retryingContentAddressableStorage,
+		directoryFetcher,
+		symlinkFactory,
+		allowAllAuthorizer,
+		clock,
+		cd_vfs.RemoteOutputServiceDirectoryOptions{})
+
+	t.Run("Initial", func(t *testing.T) {
+		response, err := d.GetDaemonStats(ctx, &emptypb.Empty{})
+		require.NoError(t, err)
+		require.Equal(t, uint32(0), response.OutputPaths)
+		require.Equal(t, uint32(0), response.ActiveBuilds)
+		require.Equal(t, uint64(0), response.CasFiles)
+		require.Less(t, uint32(0), response.Goroutines)
+		testutil.RequireEqualProto(t, &outputpaths.FetchLatencyDistribution{}, response.BlobFetchLatency)
+		testutil.RequireEqualProto(t, &outputpaths.FetchLatencyDistribution{}, response.TreeFetchLatency)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+		handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+		casFileHandleAllocator := mock.NewMockStatelessHandleAllocator(ctrl)
+		casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(casFileHandleAllocator)
+		outputPath := mock.NewMockOutputPath(ctrl)
+		var casFileFactory re_vfs.CASFileFactory
+		outputPathFactory.EXPECT().StartInitialBuild(
+			path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+			gomock.Any(),
+			digest.MustNewFunction("", remoteexecution.DigestFunction_MD5),
+			gomock.Any(),
+		).DoAndReturn(func(outputBaseID path.Component, cff re_vfs.CASFileFactory, digestFunction digest.Function, errorLogger util.ErrorLogger) cd_vfs.OutputPath {
+			casFileFactory = cff
+			return outputPath
+		})
+		outputPath.EXPECT().FilterChildren(gomock.Any())
+
+		_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+			OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+			BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			DigestFunction:   remoteexecution.DigestFunction_MD5,
+			OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+		})
+		require.NoError(t, err)
+
+		// Create a file and read it, so that both the number of
+		// files and the number of fetches are incremented.
+		fileDigest := digest.MustNewDigest("", remoteexecution.DigestFunction_MD5, "8b1a9953c4611296a827abf8c47804d7", 5)
+		fileHandleAllocation := mock.NewMockStatelessHandleAllocation(ctrl)
+		casFileHandleAllocator.EXPECT().New(gomock.Any()).Return(fileHandleAllocation)
+		fileHandleAllocation.EXPECT().AsNativeLeaf(gomock.Any()).
+			DoAndReturn(func(leaf re_vfs.NativeLeaf) re_vfs.NativeLeaf { return leaf })
+		file := casFileFactory.LookupFile(fileDigest, false, nil)
+
+		retryingContentAddressableStorage.EXPECT().Get(gomock.Any(), fileDigest).
+			Return(buffer.NewValidatedBufferFromByteSlice([]byte("Hello")))
+		var buf [5]byte
+		n, eof, s := file.VirtualRead(buf[:], 0)
+		require.Equal(t, re_vfs.StatusOK, s)
+		require.True(t, eof)
+		require.Equal(t, []byte("Hello"), buf[:n])
+
+		response, err := d.GetDaemonStats(ctx, &emptypb.Empty{})
+		require.NoError(t, err)
+		require.Equal(t, uint32(1), response.OutputPaths)
+		require.Equal(t, uint32(1), response.ActiveBuilds)
+		require.Equal(t, uint64(1), response.CasFiles)
+		require.Equal(t, uint64(1), response.BlobFetchLatency.Count)
+		testutil.RequireEqualProto(t, &outputpaths.FetchLatencyDistribution{}, response.TreeFetchLatency)
+
+		// Removing the file should decrement the number of
+		// files. The fetch should remain accounted for.
+		file.Unlink()
+
+		response, err = d.GetDaemonStats(ctx, &emptypb.Empty{})
+		require.NoError(t, err)
+		require.Equal(t, uint64(0), response.CasFiles)
+		require.Equal(t, uint64(1), response.BlobFetchLatency.Count)
+	})
+}
+
+func TestRemoteOutputServiceDirectoryListOutputPaths(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	handleAllocator := mock.NewMockStatefulHandleAllocator(ctrl)
+	outputPathFactory := mock.NewMockOutputPathFactory(ctrl)
+	bareContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	retryingContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	directoryFetcher := mock.NewMockDirectoryFetcher(ctrl)
+	symlinkFactory := mock.NewMockSymlinkFactory(ctrl)
+	dHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	handleAllocator.EXPECT().New().Return(dHandleAllocation)
+	dHandle := mock.NewMockStatefulDirectoryHandle(ctrl)
+	dHandleAllocation.EXPECT().AsStatefulDirectory(gomock.Any()).Return(dHandle)
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(1000, 0)).AnyTimes()
+	d := newTestRemoteOutputServiceDirectory(
+		handleAllocator,
+		outputPathFactory,
+		bareContentAddressableStorage,
+		retryingContentAddressableStorage,
+		directoryFetcher,
+		symlinkFactory,
+		allowAllAuthorizer,
+		clock,
+		cd_vfs.RemoteOutputServiceDirectoryOptions{})
+
+	t.Run("Initial", func(t *testing.T) {
+		response, err := d.ListOutputPaths(ctx, &outputpaths.ListOutputPathsRequest{})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &outputpaths.ListOutputPathsResponse{}, response)
+	})
+
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocator := mock.NewMockStatelessHandleAllocator(ctrl)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(casFileHandleAllocator)
+	outputPath := mock.NewMockOutputPath(ctrl)
+	outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+		gomock.Any(),
+		digest.MustNewFunction("", remoteexecution.DigestFunction_MD5),
+		gomock.Any(),
+	).Return(outputPath)
+	outputPath.EXPECT().FilterChildren(gomock.Any())
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		DigestFunction:   remoteexecution.DigestFunction_MD5,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+
+	t.Run("ActiveBuild", func(t *testing.T) {
+		// The inode number of the root directory should be
+		// obtained from the output path.
+		outputPath.EXPECT().VirtualGetAttributes(gomock.Any(), re_vfs.AttributesMaskInodeNumber, gomock.Any()).
+			Do(func(ctx context.Context, requested re_vfs.AttributesMask, attributes *re_vfs.Attributes) {
+				attributes.SetInodeNumber(1234)
+			})
+
+		response, err := d.ListOutputPaths(ctx, &outputpaths.ListOutputPathsRequest{})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &outputpaths.ListOutputPathsResponse{
+			OutputPaths: []*outputpaths.ListOutputPathsResponse_OutputPath{
+				{
+					OutputBaseId:             "9da951b8cb759233037166e28f7ea186",
+					RootDirectoryInodeNumber: 1234,
+					ActiveBuildId:            "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+					CreationTime:             &timestamppb.Timestamp{Seconds: 1000},
+					LastBuildStartTime:       &timestamppb.Timestamp{Seconds: 1000},
+				},
+			},
+		}, response)
+	})
+
+	t.Run("FinalizedBuild", func(t *testing.T) {
+		// Output paths should continue to be reported after
+		// the build is finalized.
+		outputPath.EXPECT().FinalizeBuild(gomock.Any(), gomock.Any())
+		_, err := d.FinalizeBuild(ctx, &remoteoutputservice.FinalizeBuildRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		})
+		require.NoError(t, err)
+
+		outputPath.EXPECT().VirtualGetAttributes(gomock.Any(), re_vfs.AttributesMaskInodeNumber, gomock.Any()).
+			Do(func(ctx context.Context, requested re_vfs.AttributesMask, attributes *re_vfs.Attributes) {
+				attributes.SetInodeNumber(1234)
+			})
+
+		response, err := d.ListOutputPaths(ctx, &outputpaths.ListOutputPathsRequest{})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &outputpaths.ListOutputPathsResponse{
+			OutputPaths: []*outputpaths.ListOutputPathsResponse_OutputPath{
+				{
+					OutputBaseId:             "9da951b8cb759233037166e28f7ea186",
+					RootDirectoryInodeNumber: 1234,
+					CreationTime:             &timestamppb.Timestamp{Seconds: 1000},
+					LastBuildStartTime:       &timestamppb.Timestamp{Seconds: 1000},
+				},
+			},
+		}, response)
+	})
+}
+
+func TestRemoteOutputServiceDirectoryListOutputPathsSince(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	handleAllocator := mock.NewMockStatefulHandleAllocator(ctrl)
+	outputPathFactory := mock.NewMockOutputPathFactory(ctrl)
+	bareContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	retryingContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	directoryFetcher := mock.NewMockDirectoryFetcher(ctrl)
+	symlinkFactory := mock.NewMockSymlinkFactory(ctrl)
+	dHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	handleAllocator.EXPECT().New().Return(dHandleAllocation)
+	dHandle := mock.NewMockStatefulDirectoryHandle(ctrl)
+	dHandleAllocation.EXPECT().AsStatefulDirectory(gomock.Any()).Return(dHandle)
+	clock := mock.NewMockClock(ctrl)
+	now := time.Unix(1000, 0)
+	clock.EXPECT().Now().DoAndReturn(func() time.Time { return now }).AnyTimes()
+	d := newTestRemoteOutputServiceDirectory(
+		handleAllocator,
+		outputPathFactory,
+		bareContentAddressableStorage,
+		retryingContentAddressableStorage,
+		directoryFetcher,
+		symlinkFactory,
+		allowAllAuthorizer,
+		clock,
+		cd_vfs.RemoteOutputServiceDirectoryOptions{})
+
+	t.Run("InvalidTimestamp", func(t *testing.T) {
+		_, err := d.ListOutputPaths(ctx, &outputpaths.ListOutputPathsRequest{
+			Since: &timestamppb.Timestamp{Nanos: -1},
+		})
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	// Start builds against two output bases at different points in
+	// time.
+	outputPaths := map[string]*mock.MockOutputPath{}
+	startBuild := func(outputBaseID, buildID string, startTime time.Time) {
+		now = startTime
+		outputPath, ok := outputPaths[outputBaseID]
+		if !ok {
+			casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+			handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+			casFileHandleAllocator := mock.NewMockStatelessHandleAllocator(ctrl)
+			casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(casFileHandleAllocator)
+			outputPath = mock.NewMockOutputPath(ctrl)
+			outputPathFactory.EXPECT().StartInitialBuild(
+				path.MustNewComponent(outputBaseID),
+				gomock.Any(),
+				digest.MustNewFunction("", remoteexecution.DigestFunction_MD5),
+				gomock.Any(),
+			).Return(outputPath)
+			inodeNumber := uint64(1000 + len(outputPaths))
+			outputPath.EXPECT().VirtualGetAttributes(gomock.Any(), re_vfs.AttributesMaskInodeNumber, gomock.Any()).
+				Do(func(ctx context.Context, requested re_vfs.AttributesMask, attributes *re_vfs.Attributes) {
+					attributes.SetInodeNumber(inodeNumber)
+				}).
+				AnyTimes()
+			outputPaths[outputBaseID] = outputPath
+		}
+		outputPath.EXPECT().FilterChildren(gomock.Any())
+
+		_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+			OutputBaseId:     outputBaseID,
+			BuildId:          buildID,
+			DigestFunction:   remoteexecution.DigestFunction_MD5,
+			OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+		})
+		require.NoError(t, err)
+	}
+	startBuild("b", "6cc4b5e2-bd48-4cc4-8a1a-aaba7ae170ad", time.Unix(1100, 0))
+	startBuild("a", "1b4d9e52-4ec0-4e8e-a6dc-1befe7e1e3a6", time.Unix(1200, 0))
+
+	t.Run("AllOutputPaths", func(t *testing.T) {
+		// Without a timestamp, all output paths should be
+		// returned, sorted by output base ID.
+		response, err := d.ListOutputPaths(ctx, &outputpaths.ListOutputPathsRequest{})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &outputpaths.ListOutputPathsResponse{
+			OutputPaths: []*outputpaths.ListOutputPathsResponse_OutputPath{
+				{
+					OutputBaseId:             "a",
+					RootDirectoryInodeNumber: 1001,
+					ActiveBuildId:            "1b4d9e52-4ec0-4e8e-a6dc-1befe7e1e3a6",
+					CreationTime:             &timestamppb.Timestamp{Seconds: 1200},
+					LastBuildStartTime:       &timestamppb.Timestamp{Seconds: 1200},
+				},
+				{
+					OutputBaseId:             "b",
+					RootDirectoryInodeNumber: 1000,
+					ActiveBuildId:            "6cc4b5e2-bd48-4cc4-8a1a-aaba7ae170ad",
+					CreationTime:             &timestamppb.Timestamp{Seconds: 1100},
+					LastBuildStartTime:       &timestamppb.Timestamp{Seconds: 1100},
+				},
+			},
+		}, response)
+	})
+
+	t.Run("SortedByTime", func(t *testing.T) {
+		// With a timestamp, output paths should be sorted by
+		// the time at which they last changed.
+		response, err := d.ListOutputPaths(ctx, &outputpaths.ListOutputPathsRequest{
+			Since: &timestamppb.Timestamp{Seconds: 1000},
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &outputpaths.ListOutputPathsResponse{
+			OutputPaths: []*outputpaths.ListOutputPathsResponse_OutputPath{
+				{
+					OutputBaseId:             "b",
+					RootDirectoryInodeNumber: 1000,
+					ActiveBuildId:            "6cc4b5e2-bd48-4cc4-8a1a-aaba7ae170ad",
+					CreationTime:             &timestamppb.Timestamp{Seconds: 1100},
+					LastBuildStartTime:       &timestamppb.Timestamp{Seconds: 1100},
+				},
+				{
+					OutputBaseId:             "a",
+					RootDirectoryInodeNumber: 1001,
+					ActiveBuildId:            "1b4d9e52-4ec0-4e8e-a6dc-1befe7e1e3a6",
+					CreationTime:             &timestamppb.Timestamp{Seconds: 1200},
+					LastBuildStartTime:       &timestamppb.Timestamp{Seconds: 1200},
+				},
+			},
+		}, response)
+	})
+
+	t.Run("Filtered", func(t *testing.T) {
+		// Only output paths that changed after the timestamp
+		// should be returned. Starting another build against
+		// an existing output path should cause it to be
+		// returned again.
+		response, err := d.ListOutputPaths(ctx, &outputpaths.ListOutputPathsRequest{
+			Since: &timestamppb.Timestamp{Seconds: 1100},
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &outputpaths.ListOutputPathsResponse{
+			OutputPaths: []*outputpaths.ListOutputPathsResponse_OutputPath{
+				{
+					OutputBaseId:             "a",
+					RootDirectoryInodeNumber: 1001,
+					ActiveBuildId:            "1b4d9e52-4ec0-4e8e-a6dc-1befe7e1e3a6",
+					CreationTime:             &timestamppb.Timestamp{Seconds: 1200},
+					LastBuildStartTime:       &timestamppb.Timestamp{Seconds: 1200},
+				},
+			},
+		}, response)
+
+		startBuild("b", "4a5b3c8f-0e45-4d2b-9d4c-5f8e8b9a0c1d", time.Unix(1300, 0))
+
+		response, err = d.ListOutputPaths(ctx, &outputpaths.ListOutputPathsRequest{
+			Since: &timestamppb.Timestamp{Seconds: 1200},
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &outputpaths.ListOutputPathsResponse{
+			OutputPaths: []*outputpaths.ListOutputPathsResponse_OutputPath{
+				{
+					OutputBaseId:             "b",
+					RootDirectoryInodeNumber: 1000,
+					ActiveBuildId:            "4a5b3c8f-0e45-4d2b-9d4c-5f8e8b9a0c1d",
+					CreationTime:             &timestamppb.Timestamp{Seconds: 1100},
+					LastBuildStartTime:       &timestamppb.Timestamp{Seconds: 1300},
+				},
+			},
+		}, response)
+	})
+}
+
+func TestRemoteOutputServiceDirectoryAuthorization(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	handleAllocator := mock.NewMockStatefulHandleAllocator(ctrl)
+	outputPathFactory := mock.NewMockOutputPathFactory(ctrl)
+	bareContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	retryingContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	directoryFetcher := mock.NewMockDirectoryFetcher(ctrl)
+	symlinkFactory := mock.NewMockSymlinkFactory(ctrl)
+	authorizer := mock.NewMockAuthorizer(ctrl)
+	dHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	handleAllocator.EXPECT().New().Return(dHandleAllocation)
+	dHandle := mock.NewMockStatefulDirectoryHandle(ctrl)
+	dHandleAllocation.EXPECT().AsStatefulDirectory(gomock.Any()).Return(dHandle)
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(1000, 0)).AnyTimes()
+	d := newTestRemoteOutputServiceDirectory(
+		handleAllocator,
+		outputPathFactory,
+		bareContentAddressableStorage,
+		retryingContentAddressableStorage,
+		directoryFetcher,
+		symlinkFactory,
+		authorizer,
+		clock,
+		cd_vfs.RemoteOutputServiceDirectoryOptions{})
+
+	outputBaseInstanceName := digest.MustNewInstanceName("9da951b8cb759233037166e28f7ea186")
+
+	t.Run("CleanDenied", func(t *testing.T) {
+		authorizer.EXPECT().Authorize(ctx, []digest.InstanceName{outputBaseInstanceName}).
+			Return([]error{status.Error(codes.PermissionDenied, "Permission denied")})
+
+		_, err := d.Clean(ctx, &remoteoutputservice.CleanRequest{
+			OutputBaseId: "9da951b8cb759233037166e28f7ea186",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.PermissionDenied, "Authorization: Permission denied"), err)
+	})
+
+	t.Run("StartBuildDenied", func(t *testing.T) {
+		authorizer.EXPECT().Authorize(ctx, []digest.InstanceName{outputBaseInstanceName}).
+			Return([]error{status.Error(codes.PermissionDenied, "Permission denied")})
+
+		_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+			OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+			BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			DigestFunction:   remoteexecution.DigestFunction_SHA256,
+			OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.PermissionDenied, "Authorization: Permission denied"), err)
+	})
+
+	// Let the remainder of the tests assume that a build is running.
+	authorizer.EXPECT().Authorize(ctx, []digest.InstanceName{outputBaseInstanceName}).Return([]error{nil})
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocator := mock.NewMockStatelessHandleAllocator(ctrl)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(casFileHandleAllocator)
+	outputPath := mock.NewMockOutputPath(ctrl)
+	outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+		gomock.Any(),
+		digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256),
+		gomock.Any(),
+	).Return(outputPath)
+	outputPath.EXPECT().FilterChildren(gomock.Any())
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		DigestFunction:   remoteexecution.DigestFunction_SHA256,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+
+	t.Run("BatchCreateDenied", func(t *testing.T) {
+		authorizer.EXPECT().Authorize(ctx, []digest.InstanceName{outputBaseInstanceName}).
+			Return([]error{status.Error(codes.PermissionDenied, "Permission denied")})
+
+		_, err := d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+			BuildId:         "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			CleanPathPrefix: true,
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.PermissionDenied, "Authorization: Permission denied"), err)
+	})
+
+	t.Run("AbortBuildDenied", func(t *testing.T) {
+		authorizer.EXPECT().Authorize(ctx, []digest.InstanceName{outputBaseInstanceName}).
+			Return([]error{status.Error(codes.PermissionDenied, "Permission denied")})
+
+		_, err := d.AbortBuild(ctx, &outputpaths.AbortBuildRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.PermissionDenied, "Authorization: Permission denied"), err)
+	})
+
+	t.Run("ExportTreeDenied", func(t *testing.T) {
+		authorizer.EXPECT().Authorize(ctx, []digest.InstanceName{outputBaseInstanceName}).
+			Return([]error{status.Error(codes.PermissionDenied, "Permission denied")})
+
+		_, err := d.ExportTree(ctx, &outputpaths.ExportTreeRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.PermissionDenied, "Authorization: Permission denied"), err)
+	})
+
+	t.Run("GetOutputPathErrorsDenied", func(t *testing.T) {
+		authorizer.EXPECT().Authorize(ctx, []digest.InstanceName{outputBaseInstanceName}).
+			Return([]error{status.Error(codes.PermissionDenied, "Permission denied")})
+
+		_, err := d.GetOutputPathErrors(ctx, &outputpaths.GetOutputPathErrorsRequest{
+			OutputBaseId: "9da951b8cb759233037166e28f7ea186",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.PermissionDenied, "Authorization: Permission denied"), err)
+	})
+
+	t.Run("GetOutputPathStatsDenied", func(t *testing.T) {
+		authorizer.EXPECT().Authorize(ctx, []digest.InstanceName{outputBaseInstanceName}).
+			Return([]error{status.Error(codes.PermissionDenied, "Permission denied")})
+
+		_, err := d.GetOutputPathStats(ctx, &outputpaths.GetOutputPathStatsRequest{
+			OutputBaseId: "9da951b8cb759233037166e28f7ea186",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.PermissionDenied, "Authorization: Permission denied"), err)
+	})
+
+	t.Run("CloneOutputPathSourceDenied", func(t *testing.T) {
+		// Being permitted to access the output base of the build
+		// should not be sufficient to clone another output base.
+		authorizer.EXPECT().Authorize(ctx, []digest.InstanceName{outputBaseInstanceName}).Return([]error{nil})
+		authorizer.EXPECT().Authorize(ctx, []digest.InstanceName{digest.MustNewInstanceName("7c3e5ea1a68b4e9f8c8e3a2b1d0f9e8d")}).
+			Return([]error{status.Error(codes.PermissionDenied, "Permission denied")})
+
+		_, err := d.CloneOutputPath(ctx, &outputpaths.CloneOutputPathRequest{
+			BuildId:            "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			SourceOutputBaseId: "7c3e5ea1a68b4e9f8c8e3a2b1d0f9e8d",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.PermissionDenied, "Source output base ID: Authorization: Permission denied"), err)
+	})
+
+	t.Run("FinalizeBuildDenied", func(t *testing.T) {
+		authorizer.EXPECT().Authorize(ctx, []digest.InstanceName{outputBaseInstanceName}).
+			Return([]error{status.Error(codes.PermissionDenied, "Permission denied")})
+
+		_, err := d.FinalizeBuild(ctx, &remoteoutputservice.FinalizeBuildRequest{
+			BuildId:         "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			BuildSuccessful: true,
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.PermissionDenied, "Authorization: Permission denied"), err)
+	})
+
+	t.Run("FinalizeBuildUnknownBuildID", func(t *testing.T) {
+		// Requests for unknown build IDs should be ignored
+		// without consulting the authorizer, as there is no
+		// output base to authorize against.
+		_, err := d.FinalizeBuild(ctx, &remoteoutputservice.FinalizeBuildRequest{
+			BuildId:         "c11e7bf6-6ab5-4d85-998b-3a7b0bba01d9",
+			BuildSuccessful: true,
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("FinalizeBuildSuccess", func(t *testing.T) {
+		authorizer.EXPECT().Authorize(ctx, []digest.InstanceName{outputBaseInstanceName}).Return([]error{nil})
+		outputPath.EXPECT().FinalizeBuild(ctx, digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256))
+
+		_, err := d.FinalizeBuild(ctx, &remoteoutputservice.FinalizeBuildRequest{
+			BuildId:         "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			BuildSuccessful: true,
+		})
+		require.NoError(t, err)
+	})
+}
+
+func TestRemoteOutputServiceDirectoryExportTree(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	handleAllocator := mock.NewMockStatefulHandleAllocator(ctrl)
+	outputPathFactory := mock.NewMockOutputPathFactory(ctrl)
+	bareContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	retryingContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	directoryFetcher := mock.NewMockDirectoryFetcher(ctrl)
+	symlinkFactory := mock.NewMockSymlinkFactory(ctrl)
+	dHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	handleAllocator.EXPECT().New().Return(dHandleAllocation)
+	dHandle := mock.NewMockStatefulDirectoryHandle(ctrl)
+	dHandleAllocation.EXPECT().AsStatefulDirectory(gomock.Any()).Return(dHandle)
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(1000, 0)).AnyTimes()
+	d := newTestRemoteOutputServiceDirectory(
+		handleAllocator,
+		outputPathFactory,
+		bareContentAddressableStorage,
+		retryingContentAddressableStorage,
+		directoryFetcher,
+		symlinkFactory,
+		allowAllAuthorizer,
+		clock,
+		cd_vfs.RemoteOutputServiceDirectoryOptions{})
+
+	t.Run("InvalidBuildID", func(t *testing.T) {
+		// StartBuild() should be called first.
+		_, err := d.ExportTree(ctx, &outputpaths.ExportTreeRequest{
+			BuildId: "140dbef8-1b24-4966-bb9e-8edc7fa61df8",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.FailedPrecondition, "Build ID is not associated with any running build"), err)
+	})
+
+	// Let the remainder of the tests assume that a build is running.
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocator := mock.NewMockStatelessHandleAllocator(ctrl)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(casFileHandleAllocator)
+	outputPath := mock.NewMockOutputPath(ctrl)
+	outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+		gomock.Any(),
+		digest.MustNewFunction("my-cluster", remoteexecution.DigestFunction_MD5),
+		gomock.Any(),
+	).Return(outputPath)
+	outputPath.EXPECT().FilterChildren(gomock.Any())
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		InstanceName:     "my-cluster",
+		DigestFunction:   remoteexecution.DigestFunction_MD5,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+
+	t.Run("PathOutsideOutputPath", func(t *testing.T) {
+		_, err := d.ExportTree(ctx, &outputpaths.ExportTreeRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Path:    "..",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Failed to resolve path \"..\": Path resolves to a location outside the output path"), err)
+	})
+
+	t.Run("PathNotADirectory", func(t *testing.T) {
+		leaf := mock.NewMockNativeLeaf(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("file")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(leaf), nil)
+
+		_, err := d.ExportTree(ctx, &outputpaths.ExportTreeRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Path:    "file",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Failed to resolve path \"file\": Path component \"file\" does not resolve to a directory"), err)
+	})
+
+	t.Run("AbsoluteSymlink", func(t *testing.T) {
+		symlink := mock.NewMockNativeLeaf(ctrl)
+		outputPath.EXPECT().LookupAllChildren().Return(nil, []re_vfs.LeafPrepopulatedDirEntry{
+			{Child: symlink, Name: path.MustNewComponent("symlink")},
+		}, nil)
+		symlink.EXPECT().Readlink().Return("/etc/passwd", nil)
+
+		_, err := d.ExportTree(ctx, &outputpaths.ExportTreeRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Symbolic link \"symlink\" has absolute target \"/etc/passwd\", which cannot be part of a Tree"), err)
+	})
+
+	t.Run("UploadFailure", func(t *testing.T) {
+		// Uploads happen in the background. Failures should
+		// still be propagated, and prevent the Tree from being
+		// stored.
+		file := mock.NewMockNativeLeaf(ctrl)
+		outputPath.EXPECT().LookupAllChildren().Return(nil, []re_vfs.LeafPrepopulatedDirEntry{
+			{Child: file, Name: path.MustNewComponent("file")},
+		}, nil)
+		file.EXPECT().Readlink().Return("", syscall.EINVAL)
+		file.EXPECT().VirtualGetAttributes(ctx, re_vfs.AttributesMaskPermissions, gomock.Any()).
+			Do(func(ctx context.Context, requested re_vfs.AttributesMask, attributes *re_vfs.Attributes) {
+				attributes.SetPermissions(re_vfs.PermissionsRead)
+			})
+		file.EXPECT().UploadFile(ctx, bareContentAddressableStorage, digest.MustNewFunction("my-cluster", remoteexecution.DigestFunction_MD5)).
+			Return(digest.BadDigest, status.Error(codes.Internal, "Disk on fire"))
+
+		_, err := d.ExportTree(ctx, &outputpaths.ExportTreeRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Failed to upload file \"file\": Disk on fire"), err)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		// Export the directory "a", containing a file, a
+		// symbolic link and two identical subdirectories.
+		directoryA := mock.NewMockPrepopulatedDirectory(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("a")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(directoryA), nil)
+		directoryB := mock.NewMockPrepopulatedDirectory(ctrl)
+		directoryC := mock.NewMockPrepopulatedDirectory(ctrl)
+		file := mock.NewMockNativeLeaf(ctrl)
+		symlink := mock.NewMockNativeLeaf(ctrl)
+		directoryA.EXPECT().LookupAllChildren().Return(
+			[]re_vfs.DirectoryPrepopulatedDirEntry{
+				{Child: directoryB, Name: path.MustNewComponent("b")},
+				{Child: directoryC, Name: path.MustNewComponent("c")},
+			},
+			[]re_vfs.LeafPrepopulatedDirEntry{
+				{Child: file, Name: path.MustNewComponent("file")},
+				{Child: symlink, Name: path.MustNewComponent("symlink")},
+			},
+			nil)
+		directoryB.EXPECT().LookupAllChildren()
+		directoryC.EXPECT().LookupAllChildren()
+		file.EXPECT().Readlink().Return("", syscall.EINVAL)
+		file.EXPECT().UploadFile(ctx, bareContentAddressableStorage, digest.MustNewFunction("my-cluster", remoteexecution.DigestFunction_MD5)).
+			Return(digest.MustNewDigest("my-cluster", remoteexecution.DigestFunction_MD5, "5eb63bbbe01eeed093cb22bb8f5acdc3", 11), nil)
+		file.EXPECT().VirtualGetAttributes(ctx, re_vfs.AttributesMaskPermissions, gomock.Any()).
+			Do(func(ctx context.Context, requested re_vfs.AttributesMask, attributes *re_vfs.Attributes) {
+				attributes.SetPermissions(re_vfs.PermissionsRead | re_vfs.PermissionsExecute)
+			})
+		symlink.EXPECT().Readlink().Return("../file", nil)
+
+		// The empty directory should only be stored once.
+		treeDigest := digest.MustNewDigest("my-cluster", remoteexecution.DigestFunction_MD5, "597d8447e0c48535f099232feee683bb", 155)
+		bareContentAddressableStorage.EXPECT().Put(ctx, treeDigest, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, blobDigest digest.Digest, b buffer.Buffer) error {
+				tree, err := b.ToProto(&remoteexecution.Tree{}, 10000)
+				require.NoError(t, err)
+				testutil.RequireEqualProto(t, &remoteexecution.Tree{
+					Root: &remoteexecution.Directory{
+						Files: []*remoteexecution.FileNode{
+							{
+								Name: "file",
+								Digest: &remoteexecution.Digest{
+									Hash:      "5eb63bbbe01eeed093cb22bb8f5acdc3",
+									SizeBytes: 11,
+								},
+								IsExecutable: true,
+							},
+						},
+						Directories: []*remoteexecution.DirectoryNode{
+							{
+								Name: "b",
+								Digest: &remoteexecution.Digest{
+									Hash:      "d41d8cd98f00b204e9800998ecf8427e",
+									SizeBytes: 0,
+								},
+							},
+							{
+								Name: "c",
+								Digest: &remoteexecution.Digest{
+									Hash:      "d41d8cd98f00b204e9800998ecf8427e",
+									SizeBytes: 0,
+								},
+							},
+						},
+						Symlinks: []*remoteexecution.SymlinkNode{
+							{
+								Name:   "symlink",
+								Target: "../file",
+							},
+						},
+					},
+					Children: []*remoteexecution.Directory{{}},
+				}, tree)
+				return nil
+			})
+
+		response, err := d.ExportTree(ctx, &outputpaths.ExportTreeRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Path:    "a",
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &outputpaths.ExportTreeResponse{
+			TreeDigest: treeDigest.GetProto(),
+		}, response)
+	})
+}
+
+func TestRemoteOutputServiceDirectoryGetDirectoryResidency(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	handleAllocator := mock.NewMockStatefulHandleAllocator(ctrl)
+	outputPathFactory := mock.NewMockOutputPathFactory(ctrl)
+	bareContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	retryingContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	directoryFetcher := mock.NewMockDirectoryFetcher(ctrl)
+	symlinkFactory := mock.NewMockSymlinkFactory(ctrl)
+	dHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	handleAllocator.EXPECT().New().Return(dHandleAllocation)
+	dHandle := mock.NewMockStatefulDirectoryHandle(ctrl)
+	dHandleAllocation.EXPECT().AsStatefulDirectory(gomock.Any()).Return(dHandle)
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(1000, 0)).AnyTimes()
+	d := newTestRemoteOutputServiceDirectory(
+		handleAllocator,
+		outputPathFactory,
+		bareContentAddressableStorage,
+		retryingContentAddressableStorage,
+		directoryFetcher,
+		symlinkFactory,
+		allowAllAuthorizer,
+		clock,
+		cd_vfs.RemoteOutputServiceDirectoryOptions{})
+
+	t.Run("InvalidBuildID", func(t *testing.T) {
+		// StartBuild() should be called first.
+		_, err := d.GetDirectoryResidency(ctx, &outputpaths.GetDirectoryResidencyRequest{
+			BuildId: "140dbef8-1b24-4966-bb9e-8edc7fa61df8",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.FailedPrecondition, "Build ID is not associated with any running build"), err)
+	})
+
+	// Let the remainder of the tests assume that a build is running.
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocator := mock.NewMockStatelessHandleAllocator(ctrl)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(casFileHandleAllocator)
+	outputPath := mock.NewMockOutputPath(ctrl)
+	outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+		gomock.Any(),
+		digest.MustNewFunction("", remoteexecution.DigestFunction_MD5),
+		gomock.Any(),
+	).Return(outputPath)
+	outputPath.EXPECT().FilterChildren(gomock.Any())
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		DigestFunction:   remoteexecution.DigestFunction_MD5,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+
+	t.Run("PathNotADirectory", func(t *testing.T) {
+		leaf := mock.NewMockNativeLeaf(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("file")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(leaf), nil)
+
+		_, err := d.GetDirectoryResidency(ctx, &outputpaths.GetDirectoryResidencyRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Path:    "file",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Failed to resolve path \"file\": Path component \"file\" does not resolve to a directory"), err)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		// Only directories that have not been loaded should be
+		// counted. Files should be ignored.
+		directory := mock.NewMockPrepopulatedDirectory(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("directory")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(directory), nil)
+		directory.EXPECT().FilterChildren(gomock.Any()).
+			DoAndReturn(func(childFilter re_vfs.ChildFilter) error {
+				removeFunc := func() error {
+					t.Fatal("Children should not be removed")
+					return nil
+				}
+				require.True(t, childFilter(re_vfs.InitialNode{}.FromLeaf(mock.NewMockNativeLeaf(ctrl)), removeFunc))
+				require.True(t, childFilter(re_vfs.InitialNode{}.FromDirectory(mock.NewMockInitialContentsFetcher(ctrl)), removeFunc))
+				require.True(t, childFilter(re_vfs.InitialNode{}.FromDirectory(mock.NewMockInitialContentsFetcher(ctrl)), removeFunc))
+				return nil
+			})
+
+		response, err := d.GetDirectoryResidency(ctx, &outputpaths.GetDirectoryResidencyRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Path:    "directory",
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &outputpaths.GetDirectoryResidencyResponse{
+			UnloadedDirectories: 2,
+		}, response)
+	})
+}
+
+func TestRemoteOutputServiceDirectorySetOutputPath(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	handleAllocator := mock.NewMockStatefulHandleAllocator(ctrl)
+	outputPathFactory := mock.NewMockOutputPathFactory(ctrl)
+	bareContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	retryingContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	directoryFetcher := mock.NewMockDirectoryFetcher(ctrl)
+	symlinkFactory := mock.NewMockSymlinkFactory(ctrl)
+	dHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	handleAllocator.EXPECT().New().Return(dHandleAllocation)
+	dHandle := mock.NewMockStatefulDirectoryHandle(ctrl)
+	dHandleAllocation.EXPECT().AsStatefulDirectory(gomock.Any()).Return(dHandle)
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(1000, 0)).AnyTimes()
+	d := newTestRemoteOutputServiceDirectory(
+		handleAllocator,
+		outputPathFactory,
+		bareContentAddressableStorage,
+		retryingContentAddressableStorage,
+		directoryFetcher,
+		symlinkFactory,
+		allowAllAuthorizer,
+		clock,
+		cd_vfs.RemoteOutputServiceDirectoryOptions{})
+
+	t.Run("InvalidBuildID", func(t *testing.T) {
+		// StartBuild() should be called first.
+		_, err := d.SetOutputPath(ctx, &outputpaths.SetOutputPathRequest{
+			BuildId: "140dbef8-1b24-4966-bb9e-8edc7fa61df8",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.FailedPrecondition, "Build ID is not associated with any running build"), err)
+	})
+
+	// Let the remainder of the tests assume that a build is running.
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocator := mock.NewMockStatelessHandleAllocator(ctrl)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(casFileHandleAllocator)
+	outputPath := mock.NewMockOutputPath(ctrl)
+	outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+		gomock.Any(),
+		digest.MustNewFunction("", remoteexecution.DigestFunction_MD5),
+		gomock.Any(),
+	).Return(outputPath)
+	outputPath.EXPECT().FilterChildren(gomock.Any())
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		DigestFunction:   remoteexecution.DigestFunction_MD5,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+
+	t.Run("InvalidDigest", func(t *testing.T) {
+		_, err := d.SetOutputPath(ctx, &outputpaths.SetOutputPathRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			RootTreeDigest: &remoteexecution.Digest{
+				Hash:      "f2f9b523b20747ab9da53b4ed63f48f3",
+				SizeBytes: -1,
+			},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Invalid root tree digest: Invalid digest size: -1 bytes"), err)
+	})
+
+	t.Run("TooBig", func(t *testing.T) {
+		_, err := d.SetOutputPath(ctx, &outputpaths.SetOutputPathRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			RootTreeDigest: &remoteexecution.Digest{
+				Hash:      "f2f9b523b20747ab9da53b4ed63f48f3",
+				SizeBytes: 10001,
+			},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Tree is 10001 bytes in size, which exceeds the permitted maximum of 10000 bytes"), err)
+	})
+
+	t.Run("TreeFetchFailure", func(t *testing.T) {
+		// If the root directory of the Tree cannot be loaded,
+		// the existing contents of the output path should be
+		// left intact.
+		directoryFetcher.EXPECT().GetTreeRootDirectory(
+			gomock.Any(),
+			digest.MustNewDigest("", remoteexecution.DigestFunction_MD5, "f2f9b523b20747ab9da53b4ed63f48f3", 123),
+		).Return(nil, status.Error(codes.NotFound, "Object not found"))
+
+		_, err := d.SetOutputPath(ctx, &outputpaths.SetOutputPathRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			RootTreeDigest: &remoteexecution.Digest{
+				Hash:      "f2f9b523b20747ab9da53b4ed63f48f3",
+				SizeBytes: 123,
+			},
+		})
+		expectedStatus, errDetails := status.New(codes.NotFound, "Failed to load root directory of tree: Tree \"3-f2f9b523b20747ab9da53b4ed63f48f3-123-\" root directory: Tree is absent from the Content Addressable Storage, meaning this directory needs to be rebuilt: Object not found").
+			WithDetails(&outputpaths.FetchFailure{ObjectType: outputpaths.FetchedObjectType_TREE})
+		require.NoError(t, errDetails)
+		testutil.RequireEqualStatus(t, expectedStatus.Err(), err)
+	})
+
+	t.Run("RemovalFailure", func(t *testing.T) {
+		directoryFetcher.EXPECT().GetTreeRootDirectory(
+			gomock.Any(),
+			digest.MustNewDigest("", remoteexecution.DigestFunction_MD5, "f2f9b523b20747ab9da53b4ed63f48f3", 123),
+		).Return(&remoteexecution.Directory{
+			Symlinks: []*remoteexecution.SymlinkNode{
+				{Name: "symlink", Target: "target"},
+			},
+		}, nil)
+		leaf := mock.NewMockNativeLeaf(ctrl)
+		symlinkFactory.EXPECT().LookupSymlink([]byte("target")).Return(leaf)
+		outputPath.EXPECT().RemoveAllChildren(false).Return(status.Error(codes.Internal, "Disk on fire"))
+		leaf.EXPECT().Unlink()
+
+		_, err := d.SetOutputPath(ctx, &outputpaths.SetOutputPathRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			RootTreeDigest: &remoteexecution.Digest{
+				Hash:      "f2f9b523b20747ab9da53b4ed63f48f3",
+				SizeBytes: 123,
+			},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Failed to remove existing contents of output path: Disk on fire"), err)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		// The existing contents of the output path should be
+		// replaced by the contents of the root directory of the
+		// Tree. Subdirectories should be loaded lazily.
+		directoryFetcher.EXPECT().GetTreeRootDirectory(
+			gomock.Any(),
+			digest.MustNewDigest("", remoteexecution.DigestFunction_MD5, "f2f9b523b20747ab9da53b4ed63f48f3", 123),
+		).Return(&remoteexecution.Directory{
+			Directories: []*remoteexecution.DirectoryNode{
+				{
+					Name: "directory",
+					Digest: &remoteexecution.Digest{
+						Hash:      "8c2e88d0d7b4b7c5b3e0c5ae6a8e2d1f",
+						SizeBytes: 42,
+					},
+				},
+			},
+			Symlinks: []*remoteexecution.SymlinkNode{
+				{Name: "symlink", Target: "target"},
+			},
+		}, nil)
+		leaf := mock.NewMockNativeLeaf(ctrl)
+		symlinkFactory.EXPECT().LookupSymlink([]byte("target")).Return(leaf)
+		outputPath.EXPECT().RemoveAllChildren(false)
+		outputPath.EXPECT().CreateChildren(gomock.Any(), false).
+			DoAndReturn(func(children map[path.Component]re_vfs.InitialNode, overwrite bool) error {
+				require.Len(t, children, 2)
+				directory, _ := children[path.MustNewComponent("directory")].GetPair()
+				require.NotNil(t, directory)
+				_, symlink := children[path.MustNewComponent("symlink")].GetPair()
+				require.Equal(t, leaf, symlink)
+				return nil
+			})
+
+		_, err := d.SetOutputPath(ctx, &outputpaths.SetOutputPathRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			RootTreeDigest: &remoteexecution.Digest{
+				Hash:      "f2f9b523b20747ab9da53b4ed63f48f3",
+				SizeBytes: 123,
+			},
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("Overlay", func(t *testing.T) {
+		// Existing entries should take precedence over the
+		// contents of the Tree. Directories that exist on both
+		// sides should be merged.
+		directoryFetcher.EXPECT().GetTreeRootDirectory(
+			gomock.Any(),
+			digest.MustNewDigest("", remoteexecution.DigestFunction_MD5, "f2f9b523b20747ab9da53b4ed63f48f3", 123),
+		).Return(&remoteexecution.Directory{
+			Directories: []*remoteexecution.DirectoryNode{
+				{
+					Name: "directory",
+					Digest: &remoteexecution.Digest{
+						Hash:      "8c2e88d0d7b4b7c5b3e0c5ae6a8e2d1f",
+						SizeBytes: 42,
+					},
+				},
+			},
+			Symlinks: []*remoteexecution.SymlinkNode{
+				{Name: "existing_symlink", Target: "base_target"},
+				{Name: "new_symlink", Target: "target"},
+			},
+		}, nil)
+		directoryFetcher.EXPECT().GetTreeChildDirectory(
+			gomock.Any(),
+			digest.MustNewDigest("", remoteexecution.DigestFunction_MD5, "f2f9b523b20747ab9da53b4ed63f48f3", 123),
+			digest.MustNewDigest("", remoteexecution.DigestFunction_MD5, "8c2e88d0d7b4b7c5b3e0c5ae6a8e2d1f", 42),
+		).Return(&remoteexecution.Directory{
+			Symlinks: []*remoteexecution.SymlinkNode{
+				{Name: "nested_symlink", Target: "nested_target"},
+			},
+		}, nil)
+		existingSymlinkLeaf := mock.NewMockNativeLeaf(ctrl)
+		symlinkFactory.EXPECT().LookupSymlink([]byte("base_target")).Return(existingSymlinkLeaf)
+		existingSymlinkLeaf.EXPECT().Unlink()
+		newSymlinkLeaf := mock.NewMockNativeLeaf(ctrl)
+		symlinkFactory.EXPECT().LookupSymlink([]byte("target")).Return(newSymlinkLeaf)
+		nestedSymlinkLeaf := mock.NewMockNativeLeaf(ctrl)
+		symlinkFactory.EXPECT().LookupSymlink([]byte("nested_target")).Return(nestedSymlinkLeaf)
+
+		existingDirectory := mock.NewMockPrepopulatedDirectory(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("directory")).
+			Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(existingDirectory), nil)
+		existingDirectory.EXPECT().LookupChild(path.MustNewComponent("nested_symlink")).
+			Return(re_vfs.PrepopulatedDirectoryChild{}, syscall.ENOENT)
+		existingDirectory.EXPECT().CreateChildren(map[path.Component]re_vfs.InitialNode{
+			path.MustNewComponent("nested_symlink"): re_vfs.InitialNode{}.FromLeaf(nestedSymlinkLeaf),
+		}, false)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("existing_symlink")).
+			Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(mock.NewMockNativeLeaf(ctrl)), nil)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("new_symlink")).
+			Return(re_vfs.PrepopulatedDirectoryChild{}, syscall.ENOENT)
+		outputPath.EXPECT().CreateChildren(map[path.Component]re_vfs.InitialNode{
+			path.MustNewComponent("new_symlink"): re_vfs.InitialNode{}.FromLeaf(newSymlinkLeaf),
+		}, false)
+
+		_, err := d.SetOutputPath(ctx, &outputpaths.SetOutputPathRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			RootTreeDigest: &remoteexecution.Digest{
+				Hash:      "f2f9b523b20747ab9da53b4ed63f48f3",
+				SizeBytes: 123,
+			},
+			Overlay: true,
+		})
+		require.NoError(t, err)
+	})
+}
+
+func TestRemoteOutputServiceDirectoryFinalizeSubtree(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	handleAllocator := mock.NewMockStatefulHandleAllocator(ctrl)
+	outputPathFactory := mock.NewMockOutputPathFactory(ctrl)
+	bareContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	retryingContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	directoryFetcher := mock.NewMockDirectoryFetcher(ctrl)
+	symlinkFactory := mock.NewMockSymlinkFactory(ctrl)
+	dHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	handleAllocator.EXPECT().New().Return(dHandleAllocation)
+	dHandle := mock.NewMockStatefulDirectoryHandle(ctrl)
+	dHandleAllocation.EXPECT().AsStatefulDirectory(gomock.Any()).Return(dHandle)
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(1000, 0)).AnyTimes()
+	d := newTestRemoteOutputServiceDirectory(
+		handleAllocator,
+		outputPathFactory,
+		bareContentAddressableStorage,
+		retryingContentAddressableStorage,
+		directoryFetcher,
+		symlinkFactory,
+		allowAllAuthorizer,
+		clock,
+		cd_vfs.RemoteOutputServiceDirectoryOptions{})
+
+	t.Run("InvalidBuildID", func(t *testing.T) {
+		// StartBuild() should be called first.
+		_, err := d.FinalizeSubtree(ctx, &outputpaths.FinalizeSubtreeRequest{
+			BuildId: "140dbef8-1b24-4966-bb9e-8edc7fa61df8",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.FailedPrecondition, "Build ID is not associated with any running build"), err)
+	})
+
+	// Let the remainder of the tests assume that a build is running.
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocator := mock.NewMockStatelessHandleAllocator(ctrl)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(casFileHandleAllocator)
+	outputPath := mock.NewMockOutputPath(ctrl)
+	outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+		gomock.Any(),
+		digest.MustNewFunction("", remoteexecution.DigestFunction_MD5),
+		gomock.Any(),
+	).Return(outputPath)
+	outputPath.EXPECT().FilterChildren(gomock.Any())
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		DigestFunction:   remoteexecution.DigestFunction_MD5,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+
+	t.Run("PathOutsideOutputPath", func(t *testing.T) {
+		_, err := d.FinalizeSubtree(ctx, &outputpaths.FinalizeSubtreeRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Path:    "..",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Failed to resolve path \"..\": Path resolves to a location outside the output path"), err)
+	})
+
+	directoryA := mock.NewMockPrepopulatedDirectory(ctrl)
+	directoryB := mock.NewMockPrepopulatedDirectory(ctrl)
+	treeDigest := digest.MustNewDigest("", remoteexecution.DigestFunction_MD5, "9dd94c5a4b02914af42e8e6372e0b709", 2)
+
+	t.Run("ExportFailure", func(t *testing.T) {
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("a")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(directoryA), nil)
+		directoryA.EXPECT().LookupChild(path.MustNewComponent("b")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(directoryB), nil)
+		directoryB.EXPECT().LookupAllChildren()
+		bareContentAddressableStorage.EXPECT().Put(ctx, treeDigest, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, blobDigest digest.Digest, b buffer.Buffer) error {
+				b.Discard()
+				return status.Error(codes.Unavailable, "Server not reachable")
+			})
+
+		_, err := d.FinalizeSubtree(ctx, &outputpaths.FinalizeSubtreeRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Path:    "a/b",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.Unavailable, "Failed to store tree: Server not reachable"), err)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("a")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(directoryA), nil)
+		directoryA.EXPECT().LookupChild(path.MustNewComponent("b")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(directoryB), nil)
+		directoryB.EXPECT().LookupAllChildren()
+		bareContentAddressableStorage.EXPECT().Put(ctx, treeDigest, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, blobDigest digest.Digest, b buffer.Buffer) error {
+				tree, err := b.ToProto(&remoteexecution.Tree{}, 10000)
+				require.NoError(t, err)
+				testutil.RequireEqualProto(t, &remoteexecution.Tree{
+					Root: &remoteexecution.Directory{},
+				}, tree)
+				return nil
+			})
+
+		response, err := d.FinalizeSubtree(ctx, &outputpaths.FinalizeSubtreeRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Path:    "a/b",
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &outputpaths.FinalizeSubtreeResponse{
+			TreeDigest: treeDigest.GetProto(),
+		}, response)
+	})
+
+	t.Run("CreateInsideFinalizedSubtree", func(t *testing.T) {
+		// Entries may not be created inside the finalized
+		// subtree, regardless of how the path is spelled.
+		_, err := d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+			BuildId:    "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			PathPrefix: "a/c/..",
+			Symlinks: []*remoteexecution.OutputSymlink{
+				{Path: "b/symlink", Target: "target"},
+			},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.FailedPrecondition, "Invalid path for symbolic link \"b/symlink\": Path \"a/b/symlink\" is part of finalized subtree \"a/b\""), err)
+	})
+
+	t.Run("ReplaceFinalizedSubtree", func(t *testing.T) {
+		// Entries may also not replace a directory containing
+		// the finalized subtree.
+		_, err := d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Symlinks: []*remoteexecution.OutputSymlink{
+				{Path: "a", Target: "target"},
+			},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.FailedPrecondition, "Invalid path for symbolic link \"a\": Path \"a\" contains finalized subtree \"a/b\""), err)
+	})
+
+	t.Run("CleanParentOfFinalizedSubtree", func(t *testing.T) {
+		_, err := d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+			BuildId:         "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			PathPrefix:      "a",
+			CleanPathPrefix: true,
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.FailedPrecondition, "Failed to clean path prefix directory: Path \"a\" contains finalized subtree \"a/b\""), err)
+	})
+
+	t.Run("SetOutputPath", func(t *testing.T) {
+		_, err := d.SetOutputPath(ctx, &outputpaths.SetOutputPathRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			RootTreeDigest: &remoteexecution.Digest{
+				Hash:      "f2f9b523b20747ab9da53b4ed63f48f3",
+				SizeBytes: 123,
+			},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.FailedPrecondition, "Path \"\" contains finalized subtree \"a/b\""), err)
+	})
+
+	t.Run("CreateOutsideFinalizedSubtree", func(t *testing.T) {
+		// The remainder of the output path remains mutable.
+		directoryC := mock.NewMockPrepopulatedDirectory(ctrl)
+		outputPath.EXPECT().CreateAndEnterPrepopulatedDirectory(path.MustNewComponent("a")).Return(directoryA, nil)
+		directoryA.EXPECT().CreateAndEnterPrepopulatedDirectory(path.MustNewComponent("c")).Return(directoryC, nil)
+		leaf := mock.NewMockNativeLeaf(ctrl)
+		symlinkFactory.EXPECT().LookupSymlink([]byte("target")).Return(leaf)
+		directoryC.EXPECT().CreateChildren(map[path.Component]re_vfs.InitialNode{
+			path.MustNewComponent("symlink"): re_vfs.InitialNode{}.FromLeaf(leaf),
+		}, true)
+
+		_, err := d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+			BuildId:    "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			PathPrefix: "a",
+			Symlinks: []*remoteexecution.OutputSymlink{
+				{Path: "c/symlink", Target: "target"},
+			},
+		})
+		require.NoError(t, err)
+	})
+}
+
+func TestRemoteOutputServiceDirectoryReadDirectory(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	handleAllocator := mock.NewMockStatefulHandleAllocator(ctrl)
+	outputPathFactory := mock.NewMockOutputPathFactory(ctrl)
+	bareContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	retryingContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	directoryFetcher := mock.NewMockDirectoryFetcher(ctrl)
+	symlinkFactory := mock.NewMockSymlinkFactory(ctrl)
+	dHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	handleAllocator.EXPECT().New().Return(dHandleAllocation)
+	dHandle := mock.NewMockStatefulDirectoryHandle(ctrl)
+	dHandleAllocation.EXPECT().AsStatefulDirectory(gomock.Any()).Return(dHandle)
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(1000, 0)).AnyTimes()
+	d := newTestRemoteOutputServiceDirectory(
+		handleAllocator,
+		outputPathFactory,
+		bareContentAddressableStorage,
+		retryingContentAddressableStorage,
+		directoryFetcher,
+		symlinkFactory,
+		allowAllAuthorizer,
+		clock,
+		cd_vfs.RemoteOutputServiceDirectoryOptions{})
+
+	t.Run("InvalidBuildID", func(t *testing.T) {
+		// StartBuild() should be called first.
+		_, err := d.ReadDirectory(ctx, &outputpaths.ReadDirectoryRequest{
+			BuildId: "140dbef8-1b24-4966-bb9e-8edc7fa61df8",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.FailedPrecondition, "Build ID is not associated with any running build"), err)
+	})
+
+	// Let the remainder of the tests assume that a build is running.
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocator := mock.NewMockStatelessHandleAllocator(ctrl)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(casFileHandleAllocator)
+	outputPath := mock.NewMockOutputPath(ctrl)
+	outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+		gomock.Any(),
+		digest.MustNewFunction("my-cluster", remoteexecution.DigestFunction_MD5),
+		gomock.Any(),
+	).Return(outputPath)
+	outputPath.EXPECT().FilterChildren(gomock.Any())
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		InstanceName:     "my-cluster",
+		DigestFunction:   remoteexecution.DigestFunction_MD5,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+
+	t.Run("LookupAllChildrenFailure", func(t *testing.T) {
+		outputPath.EXPECT().LookupAllChildren().Return(nil, nil, status.Error(codes.Internal, "Disk failure"))
+
+		_, err := d.ReadDirectory(ctx, &outputpaths.ReadDirectoryRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Failed to look up children of directory \"\": Disk failure"), err)
+	})
+
+	t.Run("Pagination", func(t *testing.T) {
+		// Read a directory containing five entries using a
+		// page size of three. Directories and leaves should be
+		// merged, so that entries are returned in alphabetical
+		// order.
+		directory := mock.NewMockPrepopulatedDirectory(ctrl)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("directory")).
+			Return(re_vfs.PrepopulatedDirectoryChild{}.FromDirectory(directory), nil).
+			Times(2)
+		subdirectoryB := mock.NewMockPrepopulatedDirectory(ctrl)
+		subdirectoryD := mock.NewMockPrepopulatedDirectory(ctrl)
+		fileA := mock.NewMockNativeLeaf(ctrl)
+		fileC := mock.NewMockNativeLeaf(ctrl)
+		symlinkE := mock.NewMockNativeLeaf(ctrl)
+		directory.EXPECT().LookupAllChildren().Return(
+			[]re_vfs.DirectoryPrepopulatedDirEntry{
+				{Name: path.MustNewComponent("b"), Child: subdirectoryB},
+				{Name: path.MustNewComponent("d"), Child: subdirectoryD},
+			},
+			[]re_vfs.LeafPrepopulatedDirEntry{
+				{Name: path.MustNewComponent("a"), Child: fileA},
+				{Name: path.MustNewComponent("c"), Child: fileC},
+				{Name: path.MustNewComponent("e"), Child: symlinkE},
+			},
+			nil).
+			Times(2)
+
+		fileStatusA := &remoteoutputservice.FileStatus{
+			FileType: &remoteoutputservice.FileStatus_File_{
+				File: &remoteoutputservice.FileStatus_File{
+					Digest: &remoteexecution.Digest{
+						Hash:      "ad17450bb18953f249532a478d2150ba",
+						SizeBytes: 72,
+					},
+				},
+			},
+		}
+		digestFunction := digest.MustNewFunction("my-cluster", remoteexecution.DigestFunction_MD5)
+		fileA.EXPECT().GetOutputServiceFileStatus(&digestFunction).Return(fileStatusA, nil)
+		subdirectoryB.EXPECT().VirtualGetAttributes(ctx, re_vfs.AttributesMaskLastDataModificationTime, gomock.Any()).
+			Do(func(ctx context.Context, requested re_vfs.AttributesMask, attributes *re_vfs.Attributes) {
+				attributes.SetLastDataModificationTime(time.Unix(1001, 0))
+			})
+		fileStatusC := &remoteoutputservice.FileStatus{
+			FileType: &remoteoutputservice.FileStatus_File_{
+				File: &remoteoutputservice.FileStatus_File{
+					Digest: &remoteexecution.Digest{
+						Hash:      "7b8d7b3915a2bad3ba7ff4bf0a489b64",
+						SizeBytes: 123,
+					},
+				},
+			},
+		}
+		fileC.EXPECT().GetOutputServiceFileStatus(&digestFunction).Return(fileStatusC, nil)
+
+		response, err := d.ReadDirectory(ctx, &outputpaths.ReadDirectoryRequest{
+			BuildId:           "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Path:              "directory",
+			IncludeFileDigest: true,
+			PageSize:          3,
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &outputpaths.ReadDirectoryResponse{
+			Entries: []*outputpaths.DirectoryEntry{
+				{Name: "a", Status: fileStatusA},
+				{
+					Name: "b",
+					Status: &remoteoutputservice.FileStatus{
+						FileType: &remoteoutputservice.FileStatus_Directory_{
+							Directory: &remoteoutputservice.FileStatus_Directory{
+								LastModifiedTime: &timestamppb.Timestamp{Seconds: 1001},
+							},
+						},
+					},
+				},
+				{Name: "c", Status: fileStatusC},
+			},
+			NextPageToken: "c",
+		}, response)
+
+		// Reading the next page should return the remaining
+		// entries, without providing another page token.
+		subdirectoryD.EXPECT().VirtualGetAttributes(ctx, re_vfs.AttributesMaskLastDataModificationTime, gomock.Any()).
+			Do(func(ctx context.Context, requested re_vfs.AttributesMask, attributes *re_vfs.Attributes) {
+				attributes.SetLastDataModificationTime(time.Unix(1002, 0))
+			})
+		symlinkStatusE := &remoteoutputservice.FileStatus{
+			FileType: &remoteoutputservice.FileStatus_Symlink_{
+				Symlink: &remoteoutputservice.FileStatus_Symlink{
+					Target: "a",
+				},
+			},
+		}
+		symlinkE.EXPECT().GetOutputServiceFileStatus(&digestFunction).Return(symlinkStatusE, nil)
+
+		response, err = d.ReadDirectory(ctx, &outputpaths.ReadDirectoryRequest{
+			BuildId:           "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Path:              "directory",
+			IncludeFileDigest: true,
+			PageSize:          3,
+			PageToken:         "c",
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &outputpaths.ReadDirectoryResponse{
+			Entries: []*outputpaths.DirectoryEntry{
+				{
+					Name: "d",
+					Status: &remoteoutputservice.FileStatus{
+						FileType: &remoteoutputservice.FileStatus_Directory_{
+							Directory: &remoteoutputservice.FileStatus_Directory{
+								LastModifiedTime: &timestamppb.Timestamp{Seconds: 1002},
+							},
+						},
+					},
+				},
+				{Name: "e", Status: symlinkStatusE},
+			},
+		}, response)
+	})
+}
+
+func TestRemoteOutputServiceDirectoryVirtualLookup(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	handleAllocator := mock.NewMockStatefulHandleAllocator(ctrl)
+	outputPathFactory := mock.NewMockOutputPathFactory(ctrl)
+	bareContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	retryingContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	directoryFetcher := mock.NewMockDirectoryFetcher(ctrl)
+	symlinkFactory := mock.NewMockSymlinkFactory(ctrl)
+	dHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	handleAllocator.EXPECT().New().Return(dHandleAllocation)
+	dHandle := mock.NewMockStatefulDirectoryHandle(ctrl)
+	dHandleAllocation.EXPECT().AsStatefulDirectory(gomock.Any()).Return(dHandle)
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(1000, 0)).AnyTimes()
+	d := newTestRemoteOutputServiceDirectory(
+		handleAllocator,
+		outputPathFactory,
+		bareContentAddressableStorage,
+		retryingContentAddressableStorage,
+		directoryFetcher,
+		symlinkFactory,
+		allowAllAuthorizer,
+		clock,
+		cd_vfs.RemoteOutputServiceDirectoryOptions{})
+
+	// No output paths exist, so VirtualLookup() should always fail.
+	var out1 re_vfs.Attributes
+	_, s := d.VirtualLookup(ctx, path.MustNewComponent("eda09135e50ff6e877fe5f8136ddc759"), 0, &out1)
+	require.Equal(t, re_vfs.StatusErrNoEnt, s)
+
+	// Create an output path.
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocator := mock.NewMockStatelessHandleAllocator(ctrl)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(casFileHandleAllocator)
+	outputPath := mock.NewMockOutputPath(ctrl)
+	outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("eaf1d65b7ab802934e6b57d0e14b3f30"),
+		gomock.Any(),
+		digest.MustNewFunction("", remoteexecution.DigestFunction_MD5),
+		gomock.Any(),
+	).Return(outputPath)
+	outputPath.EXPECT().FilterChildren(gomock.Any())
+
+	response, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "eaf1d65b7ab802934e6b57d0e14b3f30",
+		BuildId:          "2840d789-16ff-4fe4-9639-3245f9bb9106",
+		DigestFunction:   remoteexecution.DigestFunction_MD5,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+		OutputPathAliases: map[string]string{
+			"/home/bob/.cache/bazel/_bazel_bob/eaf1d65b7ab802934e6b57d0e14b3f30/execroot/myproject/bazel-out": ".",
+		},
+	})
+	require.NoError(t, err)
+	testutil.RequireEqualProto(t, &remoteoutputservice.StartBuildResponse{
+		OutputPathSuffix: "eaf1d65b7ab802934e6b57d0e14b3f30",
+	}, response)
+
+	outputPath.EXPECT().VirtualGetAttributes(
+		ctx,
+		re_vfs.AttributesMaskInodeNumber,
+		gomock.Any(),
+	).Do(func(ctx context.Context, requested re_vfs.AttributesMask, out *re_vfs.Attributes) {
+		out.SetInodeNumber(101)
+	})
+
+	// Call VirtualLookup() again. It should now succeed.
+	var out2 re_vfs.Attributes
+	child, s := d.VirtualLookup(ctx, path.MustNewComponent("eaf1d65b7ab802934e6b57d0e14b3f30"), re_vfs.AttributesMaskInodeNumber, &out2)
+	require.Equal(t, re_vfs.StatusOK, s)
+	require.Equal(t, re_vfs.DirectoryChild{}.FromDirectory(outputPath), child)
+	require.Equal(t, *(&re_vfs.Attributes{}).SetInodeNumber(101), out2)
+
+	// Remove the output path.
+	outputPath.EXPECT().RemoveAllChildren(true)
+	dHandle.EXPECT().NotifyRemoval(path.MustNewComponent("eaf1d65b7ab802934e6b57d0e14b3f30"))
+
+	_, err = d.Clean(ctx, &remoteoutputservice.CleanRequest{
+		OutputBaseId: "eaf1d65b7ab802934e6b57d0e14b3f30",
+	})
+	require.NoError(t, err)
+
+	// VirtualLookup() should fail once again.
+	var out3 re_vfs.Attributes
+	_, s = d.VirtualLookup(ctx, path.MustNewComponent("eda09135e50ff6e877fe5f8136ddc759"), 0, &out3)
+	require.Equal(t, re_vfs.StatusErrNoEnt, s)
+}
+
+func TestRemoteOutputServiceDirectoryVirtualLookupPreload(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	handleAllocator := mock.NewMockStatefulHandleAllocator(ctrl)
+	outputPathFactory := mock.NewMockOutputPathFactory(ctrl)
+	bareContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	retryingContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	directoryFetcher := mock.NewMockDirectoryFetcher(ctrl)
+	symlinkFactory := mock.NewMockSymlinkFactory(ctrl)
+	dHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	handleAllocator.EXPECT().New().Return(dHandleAllocation)
+	dHandle := mock.NewMockStatefulDirectoryHandle(ctrl)
+	dHandleAllocation.EXPECT().AsStatefulDirectory(gomock.Any()).Return(dHandle)
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(1000, 0)).AnyTimes()
+	preloadDigestFunction := digest.MustNewFunction("my-cluster", remoteexecution.DigestFunction_SHA256)
+	d := newTestRemoteOutputServiceDirectory(
+		handleAllocator,
+		outputPathFactory,
+		bareContentAddressableStorage,
+		retryingContentAddressableStorage,
+		directoryFetcher,
+		symlinkFactory,
+		allowAllAuthorizer,
+		clock,
+		cd_vfs.RemoteOutputServiceDirectoryOptions{
+			PreloadDigestFunction: &preloadDigestFunction,
+		})
+
+	t.Run("NoPersistentState", func(t *testing.T) {
+		// Output bases without any persistent state should
+		// not be created.
+		outputPathFactory.EXPECT().HasPersistentState(path.MustNewComponent("eda09135e50ff6e877fe5f8136ddc759")).Return(false)
+
+		var out re_vfs.Attributes
+		_, s := d.VirtualLookup(ctx, path.MustNewComponent("eda09135e50ff6e877fe5f8136ddc759"), 0, &out)
+		require.Equal(t, re_vfs.StatusErrNoEnt, s)
+	})
+
+	t.Run("PersistentState", func(t *testing.T) {
+		// Output bases with persistent state should be loaded
+		// using the provided digest function.
+		outputPathFactory.EXPECT().HasPersistentState(path.MustNewComponent("eaf1d65b7ab802934e6b57d0e14b3f30")).Return(true)
+		casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+		handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+		casFileHandleAllocator := mock.NewMockStatelessHandleAllocator(ctrl)
+		casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(casFileHandleAllocator)
+		outputPath := mock.NewMockOutputPath(ctrl)
+		outputPathFactory.EXPECT().StartInitialBuild(
+			path.MustNewComponent("eaf1d65b7ab802934e6b57d0e14b3f30"),
+			gomock.Any(),
+			preloadDigestFunction,
+			gomock.Any(),
+		).Return(outputPath)
+		outputPath.EXPECT().VirtualGetAttributes(ctx, re_vfs.AttributesMask(0), gomock.Any()).Times(2)
+
+		var out1 re_vfs.Attributes
+		child, s := d.VirtualLookup(ctx, path.MustNewComponent("eaf1d65b7ab802934e6b57d0e14b3f30"), 0, &out1)
+		require.Equal(t, re_vfs.StatusOK, s)
+		require.Equal(t, re_vfs.DirectoryChild{}.FromDirectory(outputPath), child)
+
+		// Successive lookups should return the same output
+		// path, without consulting the OutputPathFactory.
+		var out2 re_vfs.Attributes
+		child, s = d.VirtualLookup(ctx, path.MustNewComponent("eaf1d65b7ab802934e6b57d0e14b3f30"), 0, &out2)
+		require.Equal(t, re_vfs.StatusOK, s)
+		require.Equal(t, re_vfs.DirectoryChild{}.FromDirectory(outputPath), child)
+
+		// Starting a build should reuse the output path.
+		outputPath.EXPECT().FilterChildren(gomock.Any())
+
+		_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+			OutputBaseId:     "eaf1d65b7ab802934e6b57d0e14b3f30",
+			BuildId:          "2840d789-16ff-4fe4-9639-3245f9bb9106",
+			InstanceName:     "my-cluster",
+			DigestFunction:   remoteexecution.DigestFunction_SHA256,
+			OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+		})
+		require.NoError(t, err)
+	})
+}
+
+func TestRemoteOutputServiceDirectoryVirtualMkdir(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	handleAllocator := mock.NewMockStatefulHandleAllocator(ctrl)
+	outputPathFactory := mock.NewMockOutputPathFactory(ctrl)
+	bareContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	retryingContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	directoryFetcher := mock.NewMockDirectoryFetcher(ctrl)
+	symlinkFactory := mock.NewMockSymlinkFactory(ctrl)
+	dHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	handleAllocator.EXPECT().New().Return(dHandleAllocation)
+	dHandle := mock.NewMockStatefulDirectoryHandle(ctrl)
+	dHandleAllocation.EXPECT().AsStatefulDirectory(gomock.Any()).Return(dHandle)
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(1000, 0)).AnyTimes()
+	d := newTestRemoteOutputServiceDirectory(
+		handleAllocator,
+		outputPathFactory,
+		bareContentAddressableStorage,
+		retryingContentAddressableStorage,
+		directoryFetcher,
+		symlinkFactory,
+		allowAllAuthorizer,
+		clock,
+		cd_vfs.RemoteOutputServiceDirectoryOptions{})
+
+	// Create an output path without starting a build. As
+	// preloading is not enabled, SHA-256 should be used.
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocator := mock.NewMockStatelessHandleAllocator(ctrl)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(casFileHandleAllocator)
+	outputPath := mock.NewMockOutputPath(ctrl)
+	outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("2468b2d6c4ec2d6a4a3ce4ac2e3fac02"),
+		gomock.Any(),
+		digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256),
+		gomock.Any(),
+	).Return(outputPath)
+	outputPath.EXPECT().VirtualGetAttributes(
+		gomock.Any(),
+		re_vfs.AttributesMaskInodeNumber,
+		gomock.Any(),
+	).Do(func(ctx context.Context, requested re_vfs.AttributesMask, out *re_vfs.Attributes) {
+		out.SetInodeNumber(101)
+	})
+
+	var out1 re_vfs.Attributes
+	directory, changeInfo, s := d.VirtualMkdir(path.MustNewComponent("2468b2d6c4ec2d6a4a3ce4ac2e3fac02"), re_vfs.AttributesMaskInodeNumber, &out1)
+	require.Equal(t, re_vfs.StatusOK, s)
+	require.Equal(t, outputPath, directory)
+	require.Equal(t, re_vfs.ChangeInfo{Before: 0, After: 1}, changeInfo)
+	require.Equal(t, *(&re_vfs.Attributes{}).SetInodeNumber(101), out1)
+
+	t.Run("AlreadyExists", func(t *testing.T) {
+		var out re_vfs.Attributes
+		_, _, s := d.VirtualMkdir(path.MustNewComponent("2468b2d6c4ec2d6a4a3ce4ac2e3fac02"), re_vfs.AttributesMaskInodeNumber, &out)
+		require.Equal(t, re_vfs.StatusErrExist, s)
+	})
+
+	t.Run("NoActiveBuild", func(t *testing.T) {
+		// The output path should exist, but no build should
+		// be running against it.
+		response, err := d.GetActiveBuild(ctx, &outputpaths.GetActiveBuildRequest{
+			OutputBaseId: "2468b2d6c4ec2d6a4a3ce4ac2e3fac02",
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &outputpaths.GetActiveBuildResponse{}, response)
+	})
+
+	t.Run("StartBuild", func(t *testing.T) {
+		// Starting a build should adopt the existing output
+		// path, even if a different digest function is used.
+		// Files using a different digest function are removed
+		// by FilterChildren().
+		outputPath.EXPECT().FilterChildren(gomock.Any())
+
+		response, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+			OutputBaseId:     "2468b2d6c4ec2d6a4a3ce4ac2e3fac02",
+			BuildId:          "a2b4ff1e-0e25-4e9a-9b4a-08f3d0f7a1a3",
+			InstanceName:     "my-cluster",
+			DigestFunction:   remoteexecution.DigestFunction_MD5,
+			OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &remoteoutputservice.StartBuildResponse{
+			OutputPathSuffix: "2468b2d6c4ec2d6a4a3ce4ac2e3fac02",
+		}, response)
+	})
+}
+
+func TestRemoteOutputServiceDirectoryVirtualReadDir(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	handleAllocator := mock.NewMockStatefulHandleAllocator(ctrl)
+	outputPathFactory := mock.NewMockOutputPathFactory(ctrl)
+	bareContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	retryingContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	directoryFetcher := mock.NewMockDirectoryFetcher(ctrl)
+	symlinkFactory := mock.NewMockSymlinkFactory(ctrl)
+	dHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	handleAllocator.EXPECT().New().Return(dHandleAllocation)
+	dHandle := mock.NewMockStatefulDirectoryHandle(ctrl)
+	dHandleAllocation.EXPECT().AsStatefulDirectory(gomock.Any()).Return(dHandle)
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(1000, 0)).AnyTimes()
+	d := newTestRemoteOutputServiceDirectory(
+		handleAllocator,
+		outputPathFactory,
+		bareContentAddressableStorage,
+		retryingContentAddressableStorage,
+		directoryFetcher,
+		symlinkFactory,
+		allowAllAuthorizer,
+		clock,
+		cd_vfs.RemoteOutputServiceDirectoryOptions{})
+
+	t.Run("InitialState", func(t *testing.T) {
+		// The directory should initially be empty.
+		reporter := mock.NewMockDirectoryEntryReporter(ctrl)
+
+		require.Equal(
+			t,
+			re_vfs.StatusOK,
+			d.VirtualReadDir(ctx, 0, re_vfs.AttributesMaskInodeNumber, reporter))
+	})
+
+	// Create two output paths.
+	casFileHandleAllocation1 := mock.NewMockStatefulHandleAllocation(ctrl)
+	handleAllocator.EXPECT().New().Return(casFileHandleAllocation1)
+	casFileHandleAllocator1 := mock.NewMockStatelessHandleAllocator(ctrl)
+	casFileHandleAllocation1.EXPECT().AsStatelessAllocator().Return(casFileHandleAllocator1)
+	outputPath1 := mock.NewMockOutputPath(ctrl)
+	outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("83f3e6ff93a5403cbfb14682d8165968"),
+		gomock.Any(),
+		digest.MustNewFunction("", remoteexecution.DigestFunction_MD5),
+		gomock.Any(),
+	).Return(outputPath1)
+	outputPath1.EXPECT().FilterChildren(gomock.Any())
+
+	response, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "83f3e6ff93a5403cbfb14682d8165968",
+		BuildId:          "2b2b974f-ed53-40f0-a75a-422c09ba8be8",
+		DigestFunction:   remoteexecution.DigestFunction_MD5,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+		OutputPathAliases: map[string]string{
+			"/home/bob/.cache/bazel/_bazel_bob/83f3e6ff93a5403cbfb14682d8165968/execroot/project1/bazel-out": ".",
+		},
+	})
+	require.NoError(t, err)
+	testutil.RequireEqualProto(t, &remoteoutputservice.StartBuildResponse{
+		OutputPathSuffix: "83f3e6ff93a5403cbfb14682d8165968",
+	}, response)
+
+	casFileHandleAllocation2 := mock.NewMockStatefulHandleAllocation(ctrl)
+	handleAllocator.EXPECT().New().Return(casFileHandleAllocation2)
+	casFileHandleAllocator2 := mock.NewMockStatelessHandleAllocator(ctrl)
+	casFileHandleAllocation2.EXPECT().AsStatelessAllocator().Return(casFileHandleAllocator2)
+	outputPath2 := mock.NewMockOutputPath(ctrl)
+	outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("d4b145a6191c6d8d037d13986274d08d"),
+		gomock.Any(),
+		digest.MustNewFunction("", remoteexecution.DigestFunction_MD5),
+		gomock.Any(),
+	).Return(outputPath2)
+	outputPath2.EXPECT().FilterChildren(gomock.Any())
+
+	response, err = d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "d4b145a6191c6d8d037d13986274d08d",
+		BuildId:          "2f941206-fb17-460a-a779-10c621bc0d19",
+		DigestFunction:   remoteexecution.DigestFunction_MD5,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+		OutputPathAliases: map[string]string{
+			"/home/bob/.cache/bazel/_bazel_bob/d4b145a6191c6d8d037d13986274d08d/execroot/project2/bazel-out": ".",
+		},
+	})
+	require.NoError(t, err)
+	testutil.RequireEqualProto(t, &remoteoutputservice.StartBuildResponse{
+		OutputPathSuffix: "d4b145a6191c6d8d037d13986274d08d",
+	}, response)
+
+	t.Run("FromStart", func(t *testing.T) {
+		// The directory listing should contain both output paths.
+		reporter := mock.NewMockDirectoryEntryReporter(ctrl)
+		outputPath1.EXPECT().VirtualGetAttributes(
+			ctx,
+			re_vfs.AttributesMaskInodeNumber,
+			gomock.Any(),
+		).Do(func(ctx context.Context, requested re_vfs.AttributesMask, out *re_vfs.Attributes) {
+			out.SetInodeNumber(101)
+		})
+		reporter.EXPECT().ReportEntry(
+			uint64(1),
+			path.MustNewComponent("83f3e6ff93a5403cbfb14682d8165968"),
+			re_vfs.DirectoryChild{}.FromDirectory(outputPath1),
+			(&re_vfs.Attributes{}).SetInodeNumber(101),
+		).Return(true)
+		outputPath2.EXPECT().VirtualGetAttributes(
+			ctx,
+			re_vfs.AttributesMaskInodeNumber,
+			gomock.Any(),
+		).Do(func(ctx context.Context, requested re_vfs.AttributesMask, out *re_vfs.Attributes) {
+			out.SetInodeNumber(102)
+		})
+		reporter.EXPECT().ReportEntry(
+			uint64(2),
+			path.MustNewComponent("d4b145a6191c6d8d037d13986274d08d"),
+			re_vfs.DirectoryChild{}.FromDirectory(outputPath1),
+			(&re_vfs.Attributes{}).SetInodeNumber(102),
+		).Return(true)
+
+		require.Equal(
+			t,
+			re_vfs.StatusOK,
+			d.VirtualReadDir(ctx, 0, re_vfs.AttributesMaskInodeNumber, reporter))
+	})
+
+	t.Run("Partial", func(t *testing.T) {
+		reporter := mock.NewMockDirectoryEntryReporter(ctrl)
+		outputPath2.EXPECT().VirtualGetAttributes(
+			ctx,
+			re_vfs.AttributesMaskInodeNumber,
+			gomock.Any(),
+		).Do(func(ctx context.Context, requested re_vfs.AttributesMask, out *re_vfs.Attributes) {
+			out.SetInodeNumber(102)
+		})
+		reporter.EXPECT().ReportEntry(
+			uint64(2),
+			path.MustNewComponent("d4b145a6191c6d8d037d13986274d08d"),
+			re_vfs.DirectoryChild{}.FromDirectory(outputPath1),
+			(&re_vfs.Attributes{}).SetInodeNumber(102),
+		).Return(true)
+
+		require.Equal(
+			t,
+			re_vfs.StatusOK,
+			d.VirtualReadDir(ctx, 1, re_vfs.AttributesMaskInodeNumber, reporter))
+	})
+
+	t.Run("AtEOF", func(t *testing.T) {
+		reporter := mock.NewMockDirectoryEntryReporter(ctrl)
+		require.Equal(
+			t,
+			re_vfs.StatusOK,
+			d.VirtualReadDir(ctx, 2, re_vfs.AttributesMaskInodeNumber, reporter))
+	})
+
+	t.Run("BeyondEOF", func(t *testing.T) {
+		reporter := mock.NewMockDirectoryEntryReporter(ctrl)
+		require.Equal(
+			t,
+			re_vfs.StatusOK,
+			d.VirtualReadDir(ctx, 3, re_vfs.AttributesMaskInodeNumber, reporter))
+	})
+
+	// Remove all output paths.
+	outputPath1.EXPECT().RemoveAllChildren(true)
+	dHandle.EXPECT().NotifyRemoval(path.MustNewComponent("83f3e6ff93a5403cbfb14682d8165968"))
+	_, err = d.Clean(ctx, &remoteoutputservice.CleanRequest{
+		OutputBaseId: "83f3e6ff93a5403cbfb14682d8165968",
+	})
+	require.NoError(t, err)
+
+	outputPath2.EXPECT().RemoveAllChildren(true)
+	dHandle.EXPECT().NotifyRemoval(path.MustNewComponent("d4b145a6191c6d8d037d13986274d08d"))
+	_, err = d.Clean(ctx, &remoteoutputservice.CleanRequest{
+		OutputBaseId: "d4b145a6191c6d8d037d13986274d08d",
+	})
+	require.NoError(t, err)
+
+	t.Run("AfterClean", func(t *testing.T) {
+		reporter := mock.NewMockDirectoryEntryReporter(ctrl)
+
+		require.Equal(
+			t,
+			re_vfs.StatusOK,
+			d.VirtualReadDir(ctx, 0, re_vfs.AttributesMaskInodeNumber, reporter))
+	})
+}
+
+func TestRemoteOutputServiceDirectoryNestedOutputBaseID(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	handleAllocator := mock.NewMockStatefulHandleAllocator(ctrl)
+	outputPathFactory := mock.NewMockOutputPathFactory(ctrl)
+	bareContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	retryingContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	directoryFetcher := mock.NewMockDirectoryFetcher(ctrl)
+	symlinkFactory := mock.NewMockSymlinkFactory(ctrl)
+	dHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	handleAllocator.EXPECT().New().Return(dHandleAllocation)
+	dHandle := mock.NewMockStatefulDirectoryHandle(ctrl)
+	dHandleAllocation.EXPECT().AsStatefulDirectory(gomock.Any()).Return(dHandle)
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(1000, 0)).AnyTimes()
+	d := newTestRemoteOutputServiceDirectory(
+		handleAllocator,
+		outputPathFactory,
+		bareContentAddressableStorage,
+		retryingContentAddressableStorage,
+		directoryFetcher,
+		symlinkFactory,
+		allowAllAuthorizer,
+		clock,
+		cd_vfs.RemoteOutputServiceDirectoryOptions{})
+
+	t.Run("InvalidOutputBaseID", func(t *testing.T) {
+		// Every component of the output base ID must be a
+		// valid filename. The error message should explain
+		// which component is invalid, and why.
+		for outputBaseID, reason := range map[string]string{
+			"team/":            "Component 2 is empty",
+			"/project":         "Component 1 is empty",
+			"team//project":    "Component 2 is empty",
+			"team/./project":   "Component 2 may not be \".\"",
+			"team/../project":  "Component 2 may not be \"..\"",
+			"team/pro\x00ject": "Component 2 contains a null byte",
+		} {
+			_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+				OutputBaseId:     outputBaseID,
+				BuildId:          "0b6a9a09-4b96-4a5c-8d3c-2b0a2f1fce43",
+				DigestFunction:   remoteexecution.DigestFunction_SHA256,
+				OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+			})
+			testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Output base ID must consist of one or more valid filenames separated by slashes: "+reason), err)
+		}
+	})
+
+	// Create an output path with an output base ID consisting of
+	// two components. This should cause a directory to be created
+	// for the first component. The output path factory should be
+	// called with a name that is a single component.
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocator := mock.NewMockStatelessHandleAllocator(ctrl)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(casFileHandleAllocator)
+	groupHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	handleAllocator.EXPECT().New().Return(groupHandleAllocation)
+	groupHandle := mock.NewMockStatefulDirectoryHandle(ctrl)
+	groupHandleAllocation.EXPECT().AsStatefulDirectory(gomock.Any()).Return(groupHandle)
+	outputPath := mock.NewMockOutputPath(ctrl)
+	outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("team%2Fproject"),
+		gomock.Any(),
+		digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256),
+		gomock.Any(),
+	).Return(outputPath)
+	outputPath.EXPECT().FilterChildren(gomock.Any())
+
+	response, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "team/project",
+		BuildId:          "7e2b0b6d-6f7c-4d0e-9f43-0f6a0b0b8a51",
+		DigestFunction:   remoteexecution.DigestFunction_SHA256,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+		OutputPathAliases: map[string]string{
+			"/home/bob/.cache/bazel/_bazel_bob/team/project/execroot/myproject/bazel-out": ".",
+		},
+	})
+	require.NoError(t, err)
+	testutil.RequireEqualProto(t, &remoteoutputservice.StartBuildResponse{
+		OutputPathSuffix: "team/project",
+	}, response)
+
+	t.Run("PrefixOfExistingOutputBaseID", func(t *testing.T) {
+		// "team" is already used as a directory, so it cannot
+		// be used as an output path.
+		_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+			OutputBaseId:     "team",
+			BuildId:          "f0f7e0f2-885c-4b85-9f58-5c2b8f4c1c4e",
+			DigestFunction:   remoteexecution.DigestFunction_SHA256,
+			OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.FailedPrecondition, "Output base ID \"team\" is a prefix of the output base IDs of other output paths"), err)
+	})
+
+	t.Run("ExistingOutputBaseIDIsPrefix", func(t *testing.T) {
+		// Output paths cannot be created inside other output
+		// paths.
+		_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+			OutputBaseId:     "team/project/subproject",
+			BuildId:          "f0f7e0f2-885c-4b85-9f58-5c2b8f4c1c4e",
+			DigestFunction:   remoteexecution.DigestFunction_SHA256,
+			OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.FailedPrecondition, "Output base ID \"team/project\" is a prefix of output base ID \"team/project/subproject\""), err)
+	})
+
+	t.Run("Lookup", func(t *testing.T) {
+		// The output path should be accessible through the
+		// directory that was created for the first component.
+		groupHandle.EXPECT().GetAttributes(re_vfs.AttributesMaskInodeNumber, gomock.Any()).
+			Do(func(requested re_vfs.AttributesMask, out *re_vfs.Attributes) {
+				out.SetInodeNumber(100)
+			})
+		var out1 re_vfs.Attributes
+		child, s := d.VirtualLookup(ctx, path.MustNewComponent("team"), re_vfs.AttributesMaskInodeNumber, &out1)
+		require.Equal(t, re_vfs.StatusOK, s)
+		require.Equal(t, uint64(100), out1.GetInodeNumber())
+		group, _ := child.GetPair()
+		require.NotNil(t, group)
+
+		outputPath.EXPECT().VirtualGetAttributes(
+			ctx,
+			re_vfs.AttributesMaskInodeNumber,
+			gomock.Any(),
+		).Do(func(ctx context.Context, requested re_vfs.AttributesMask, out *re_vfs.Attributes) {
+			out.SetInodeNumber(101)
+		})
+		var out2 re_vfs.Attributes
+		child, s = group.VirtualLookup(ctx, path.MustNewComponent("project"), re_vfs.AttributesMaskInodeNumber, &out2)
+		require.Equal(t, re_vfs.StatusOK, s)
+		require.Equal(t, re_vfs.DirectoryChild{}.FromDirectory(outputPath), child)
+		require.Equal(t, *(&re_vfs.Attributes{}).SetInodeNumber(101), out2)
+
+		var out3 re_vfs.Attributes
+		_, s = group.VirtualLookup(ctx, path.MustNewComponent("nonexistent"), re_vfs.AttributesMaskInodeNumber, &out3)
+		require.Equal(t, re_vfs.StatusErrNoEnt, s)
+
+		// The output path should only be listed in the
+		// directory that was created for the first component.
+		reporter := mock.NewMockDirectoryEntryReporter(ctrl)
+		outputPath.EXPECT().VirtualGetAttributes(
+			ctx,
+			re_vfs.AttributesMaskInodeNumber,
+			gomock.Any(),
+		).Do(func(ctx context.Context, requested re_vfs.AttributesMask, out *re_vfs.Attributes) {
+			out.SetInodeNumber(101)
+		})
+		reporter.EXPECT().ReportEntry(
+			gomock.Any(),
+			path.MustNewComponent("project"),
+			re_vfs.DirectoryChild{}.FromDirectory(outputPath),
+			(&re_vfs.Attributes{}).SetInodeNumber(101),
+		).Return(true)
+		require.Equal(
+			t,
+			re_vfs.StatusOK,
+			group.VirtualReadDir(ctx, 0, re_vfs.AttributesMaskInodeNumber, reporter))
+	})
+
+	t.Run("Clean", func(t *testing.T) {
+		// Cleaning the output path should also cause the
+		// directory that was created for the first component
+		// to be removed, as it has become empty.
+		outputPath.EXPECT().RemoveAllChildren(true)
+		groupHandle.EXPECT().NotifyRemoval(path.MustNewComponent("project"))
+		dHandle.EXPECT().NotifyRemoval(path.MustNewComponent("team"))
+		groupHandle.EXPECT().Release()
+
+		_, err := d.Clean(ctx, &remoteoutputservice.CleanRequest{
+			OutputBaseId: "team/project",
+		})
+		require.NoError(t, err)
+
+		var out re_vfs.Attributes
+		_, s := d.VirtualLookup(ctx, path.MustNewComponent("team"), re_vfs.AttributesMaskInodeNumber, &out)
+		require.Equal(t, re_vfs.StatusErrNoEnt, s)
+
+		// Successive attempts should request the removal of
+		// persistent state.
+		outputPathFactory.EXPECT().Clean(path.MustNewComponent("team%2Fproject"))
+		_, err = d.Clean(ctx, &remoteoutputservice.CleanRequest{
+			OutputBaseId: "team/project",
+		})
+		require.NoError(t, err)
+	})
+}
+
+func TestRemoteOutputServiceDirectoryMaterialize(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	contentAddressableStorage := cd_testutil.NewFakeContentAddressableStorage()
+	d := cd_testutil.NewInMemoryRemoteOutputServiceDirectory(contentAddressableStorage)
+
+	t.Run("InvalidBuildID", func(t *testing.T) {
+		server := mock.NewMockOutputPathsMaterializeServer(ctrl)
+		server.EXPECT().Context().Return(ctx).AnyTimes()
+
+		testutil.RequireEqualStatus(
+			t,
+			status.Error(codes.FailedPrecondition, "Build ID is not associated with any running build"),
+			d.Materialize(&outputpaths.MaterializeRequest{
+				BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			}, server))
+	})
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		DigestFunction:   remoteexecution.DigestFunction_SHA256,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+	digestFunction := digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256)
+	_, err = d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+		BuildId:    "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		PathPrefix: "bazel-out",
+		Files: []*remoteexecution.OutputFile{
+			{
+				Path:   "a/b/hello.txt",
+				Digest: contentAddressableStorage.PutBlob(digestFunction, []byte("Hello")).GetProto(),
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	t.Run("NonexistentPath", func(t *testing.T) {
+		server := mock.NewMockOutputPathsMaterializeServer(ctrl)
+		server.EXPECT().Context().Return(ctx).AnyTimes()
+
+		testutil.RequireEqualStatus(
+			t,
+			status.Error(codes.NotFound, "Path \"nonexistent\" does not exist"),
+			d.Materialize(&outputpaths.MaterializeRequest{
+				BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+				Path:    "nonexistent",
+			}, server))
+	})
+
+	t.Run("FullOutputPath", func(t *testing.T) {
+		// The root directory, "bazel-out", "a" and "b" should
+		// all be traversed.
+		server := mock.NewMockOutputPathsMaterializeServer(ctrl)
+		server.EXPECT().Context().Return(ctx).AnyTimes()
+		server.EXPECT().Send(testutil.EqProto(t, &outputpaths.MaterializeResponse{
+			DirectoriesTraversed: 4,
+			FilesTraversed:       1,
+		}))
+
+		require.NoError(t, d.Materialize(&outputpaths.MaterializeRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		}, server))
+	})
+
+	t.Run("Subdirectory", func(t *testing.T) {
+		server := mock.NewMockOutputPathsMaterializeServer(ctrl)
+		server.EXPECT().Context().Return(ctx).AnyTimes()
+		server.EXPECT().Send(testutil.EqProto(t, &outputpaths.MaterializeResponse{
+			DirectoriesTraversed: 2,
+			FilesTraversed:       1,
+		}))
+
+		require.NoError(t, d.Materialize(&outputpaths.MaterializeRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Path:    "bazel-out/a",
+		}, server))
+	})
+
+	t.Run("MaximumDepth", func(t *testing.T) {
+		// Only the root directory, "bazel-out" and "a" should
+		// be traversed. "b" is located too deep.
+		server := mock.NewMockOutputPathsMaterializeServer(ctrl)
+		server.EXPECT().Context().Return(ctx).AnyTimes()
+		server.EXPECT().Send(testutil.EqProto(t, &outputpaths.MaterializeResponse{
+			DirectoriesTraversed:          3,
+			DirectoriesBeyondMaximumDepth: 1,
+		}))
+
+		require.NoError(t, d.Materialize(&outputpaths.MaterializeRequest{
+			BuildId:      "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			MaximumDepth: 2,
+		}, server))
+	})
+
+	t.Run("MaximumDepthSubdirectory", func(t *testing.T) {
+		// The maximum depth should be relative to the
+		// requested directory.
+		server := mock.NewMockOutputPathsMaterializeServer(ctrl)
+		server.EXPECT().Context().Return(ctx).AnyTimes()
+		server.EXPECT().Send(testutil.EqProto(t, &outputpaths.MaterializeResponse{
+			DirectoriesTraversed:          2,
+			DirectoriesBeyondMaximumDepth: 1,
+		}))
+
+		require.NoError(t, d.Materialize(&outputpaths.MaterializeRequest{
+			BuildId:      "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Path:         "bazel-out",
+			MaximumDepth: 1,
+		}, server))
+	})
+
+	t.Run("BudgetExhausted", func(t *testing.T) {
+		// A duration of zero causes traversal to stop before
+		// any directory is loaded.
+		server := mock.NewMockOutputPathsMaterializeServer(ctrl)
+		server.EXPECT().Context().Return(ctx).AnyTimes()
+		server.EXPECT().Send(testutil.EqProto(t, &outputpaths.MaterializeResponse{
+			BudgetExhausted: true,
+		}))
+
+		require.NoError(t, d.Materialize(&outputpaths.MaterializeRequest{
+			BuildId:         "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			MaximumDuration: &durationpb.Duration{},
+		}, server))
+	})
+}
+
+func TestRemoteOutputServiceDirectoryReopenBuild(t *testing.T) {
+	ctx := context.Background()
+
+	contentAddressableStorage := cd_testutil.NewFakeContentAddressableStorage()
+	d := cd_testutil.NewInMemoryRemoteOutputServiceDirectory(contentAddressableStorage)
+	digestFunction := digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256)
+
+	startBuild := func(buildID string) {
+		_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+			OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+			BuildId:          buildID,
+			DigestFunction:   remoteexecution.DigestFunction_SHA256,
+			OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+		})
+		require.NoError(t, err)
+	}
+	createFile := func(buildID, filePath string) error {
+		_, err := d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+			BuildId:    buildID,
+			PathPrefix: "bazel-out",
+			Files: []*remoteexecution.OutputFile{
+				{
+					Path:   filePath,
+					Digest: contentAddressableStorage.PutBlob(digestFunction, []byte("Hello")).GetProto(),
+				},
+			},
+		})
+		return err
+	}
+	finalizeBuild := func(buildID string) {
+		_, err := d.FinalizeBuild(ctx, &remoteoutputservice.FinalizeBuildRequest{
+			BuildId: buildID,
+		})
+		require.NoError(t, err)
+	}
+
+	t.Run("UnknownOutputBaseID", func(t *testing.T) {
+		_, err := d.ReopenBuild(ctx, &outputpaths.ReopenBuildRequest{
+			OutputBaseId: "9da951b8cb759233037166e28f7ea186",
+			BuildId:      "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.NotFound, "Output base ID is not associated with any output path"), err)
+	})
+
+	startBuild("37f5dbef-b117-4fb6-bce8-5c147cb603b4")
+	require.NoError(t, createFile("37f5dbef-b117-4fb6-bce8-5c147cb603b4", "phase1.txt"))
+
+	t.Run("BuildRunning", func(t *testing.T) {
+		_, err := d.ReopenBuild(ctx, &outputpaths.ReopenBuildRequest{
+			OutputBaseId: "9da951b8cb759233037166e28f7ea186",
+			BuildId:      "0a3c4a01-f2b1-4f4d-9d5f-8e1e1f2b3c4d",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.FailedPrecondition, "Output base ID is in use by build \"37f5dbef-b117-4fb6-bce8-5c147cb603b4\""), err)
+	})
+
+	finalizeBuild("37f5dbef-b117-4fb6-bce8-5c147cb603b4")
+
+	t.Run("WrongBuildID", func(t *testing.T) {
+		_, err := d.ReopenBuild(ctx, &outputpaths.ReopenBuildRequest{
+			OutputBaseId: "9da951b8cb759233037166e28f7ea186",
+			BuildId:      "0a3c4a01-f2b1-4f4d-9d5f-8e1e1f2b3c4d",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.FailedPrecondition, "Build ID does not correspond to the most recently finalized build of the output base"), err)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		// Once finalized, the build can no longer be modified.
+		testutil.RequireEqualStatus(
+			t,
+			status.Error(codes.FailedPrecondition, "Build ID is not associated with any running build"),
+			createFile("37f5dbef-b117-4fb6-bce8-5c147cb603b4", "phase2.txt"))
+
+		// Reopening the build should permit additional files to
+		// be created. Reopening is idempotent.
+		for i := 0; i < 2; i++ {
+			response, err := d.ReopenBuild(ctx, &outputpaths.ReopenBuildRequest{
+				OutputBaseId: "9da951b8cb759233037166e28f7ea186",
+				BuildId:      "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			})
+			require.NoError(t, err)
+			testutil.RequireEqualProto(t, &remoteoutputservice.StartBuildResponse{
+				OutputPathSuffix: "9da951b8cb759233037166e28f7ea186",
+			}, response)
+		}
+		require.NoError(t, createFile("37f5dbef-b117-4fb6-bce8-5c147cb603b4", "phase2.txt"))
+
+		response, err := d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Paths:   []string{"bazel-out/phase1.txt", "bazel-out/phase2.txt"},
+		})
+		require.NoError(t, err)
+		require.Len(t, response.Responses, 2)
+		require.NotNil(t, response.Responses[0].FileStatus)
+		require.NotNil(t, response.Responses[1].FileStatus)
+
+		finalizeBuild("37f5dbef-b117-4fb6-bce8-5c147cb603b4")
+	})
+
+	t.Run("OtherBuildStarted", func(t *testing.T) {
+		// Starting another build against the output base should
+		// prevent the previous build from being reopened.
+		startBuild("0a3c4a01-f2b1-4f4d-9d5f-8e1e1f2b3c4d")
+		finalizeBuild("0a3c4a01-f2b1-4f4d-9d5f-8e1e1f2b3c4d")
+
+		_, err := d.ReopenBuild(ctx, &outputpaths.ReopenBuildRequest{
+			OutputBaseId: "9da951b8cb759233037166e28f7ea186",
+			BuildId:      "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.FailedPrecondition, "Build ID does not correspond to the most recently finalized build of the output base"), err)
+	})
+
+	t.Run("Cleaned", func(t *testing.T) {
+		_, err := d.Clean(ctx, &remoteoutputservice.CleanRequest{
+			OutputBaseId: "9da951b8cb759233037166e28f7ea186",
+		})
+		require.NoError(t, err)
+
+		_, err = d.ReopenBuild(ctx, &outputpaths.ReopenBuildRequest{
+			OutputBaseId: "9da951b8cb759233037166e28f7ea186",
+			BuildId:      "0a3c4a01-f2b1-4f4d-9d5f-8e1e1f2b3c4d",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.NotFound, "Output base ID is not associated with any output path"), err)
+	})
+}
+
+func TestRemoteOutputServiceDirectoryRewriteAbsoluteSymlinkTargets(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	contentAddressableStorage := cd_testutil.NewFakeContentAddressableStorage()
+	handleAllocator := re_vfs.NewFUSEHandleAllocator(random.FastThreadSafeGenerator)
+	symlinkFactory := re_vfs.NewHandleAllocatingSymlinkFactory(
+		re_vfs.BaseSymlinkFactory,
+		handleAllocator.New())
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(1000, 0)).AnyTimes()
+	d := newTestRemoteOutputServiceDirectory(
+		handleAllocator,
+		cd_vfs.NewInMemoryOutputPathFactory(re_filesystem.InMemoryFilePool, symlinkFactory, handleAllocator, sort.Sort, clock),
+		contentAddressableStorage,
+		contentAddressableStorage,
+		re_cas.NewBlobAccessDirectoryFetcher(
+			contentAddressableStorage,
+			/* maximumDirectorySizeBytes = */ 10000,
+			/* maximumTreeSizeBytes = */ 10000),
+		symlinkFactory,
+		allowAllAuthorizer,
+		clock,
+		cd_vfs.RemoteOutputServiceDirectoryOptions{
+			RewriteAbsoluteSymlinkTargets: true,
+		})
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		DigestFunction:   remoteexecution.DigestFunction_SHA256,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+		OutputPathAliases: map[string]string{
+			"/home/bob/.cache/bazel/_bazel_bob/9da951b8cb759233037166e28f7ea186/execroot/myproject/bazel-out": "bazel-out",
+		},
+	})
+	require.NoError(t, err)
+
+	// Targets that refer to locations inside the output path,
+	// either directly or through an alias, should be converted to
+	// relative paths. Other targets should be stored verbatim.
+	symlinks := []struct {
+		path           string
+		target         string
+		expectedTarget string
+	}{
+		{"k8-fastbuild/bin/alias", "/home/bob/.cache/bazel/_bazel_bob/9da951b8cb759233037166e28f7ea186/execroot/myproject/bazel-out/k8-fastbuild/bin/hello", "hello"},
+		{"k8-fastbuild/bin/direct", "/home/bob/bb_clientd/outputs/9da951b8cb759233037166e28f7ea186/bazel-out/k8-fastbuild/genfiles/hello", "../genfiles/hello"},
+		{"k8-fastbuild/bin/external", "/usr/bin/python3", "/usr/bin/python3"},
+		{"k8-fastbuild/bin/relative", "../genfiles/hello", "../genfiles/hello"},
+		{"k8-fastbuild/bin/outside", "/home/bob/bb_clientd/outputs/9da951b8cb759233037166e28f7ea186/..", "/home/bob/bb_clientd/outputs/9da951b8cb759233037166e28f7ea186/.."},
+		{"directory", "/home/bob/bb_clientd/outputs/9da951b8cb759233037166e28f7ea186/bazel-out", "."},
+		{"root", "/home/bob/bb_clientd/outputs/9da951b8cb759233037166e28f7ea186", ".."},
+	}
+	request := &remoteoutputservice.BatchCreateRequest{
+		BuildId:    "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		PathPrefix: "bazel-out",
+	}
+	var paths []string
+	for _, symlink := range symlinks {
+		request.Symlinks = append(request.Symlinks, &remoteexecution.OutputSymlink{
+			Path:   symlink.path,
+			Target: symlink.target,
+		})
+		paths = append(paths, "bazel-out/"+symlink.path)
+	}
+	_, err = d.BatchCreate(ctx, request)
+	require.NoError(t, err)
+
+	response, err := d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
+		BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		Paths:   paths,
+	})
+	require.NoError(t, err)
+	require.Len(t, response.Responses, len(symlinks))
+	for i, symlink := range symlinks {
+		testutil.RequireEqualProto(t, &remoteoutputservice.StatResponse{
+			FileStatus: &remoteoutputservice.FileStatus{
+				FileType: &remoteoutputservice.FileStatus_Symlink_{
+					Symlink: &remoteoutputservice.FileStatus_Symlink{
+						Target: symlink.expectedTarget,
+					},
+				},
+			},
+		}, response.Responses[i])
+	}
+}
+
+func TestRemoteOutputServiceDirectoryMaximumConcurrentBuilds(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	contentAddressableStorage := cd_testutil.NewFakeContentAddressableStorage()
+	handleAllocator := re_vfs.NewFUSEHandleAllocator(random.FastThreadSafeGenerator)
+	symlinkFactory := re_vfs.NewHandleAllocatingSymlinkFactory(
+		re_vfs.BaseSymlinkFactory,
+		handleAllocator.New())
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(1000, 0)).AnyTimes()
+	d := newTestRemoteOutputServiceDirectory(
+		handleAllocator,
+		cd_vfs.NewInMemoryOutputPathFactory(re_filesystem.InMemoryFilePool, symlinkFactory, handleAllocator, sort.Sort, clock),
+		contentAddressableStorage,
+		contentAddressableStorage,
+		re_cas.NewBlobAccessDirectoryFetcher(
+			contentAddressableStorage,
+			/* maximumDirectorySizeBytes = */ 10000,
+			/* maximumTreeSizeBytes = */ 10000),
+		symlinkFactory,
+		allowAllAuthorizer,
+		clock,
+		cd_vfs.RemoteOutputServiceDirectoryOptions{
+			MaximumConcurrentBuilds: 1,
+		})
+
+	startBuild := func(outputBaseID, buildID string) error {
+		_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+			OutputBaseId:     outputBaseID,
+			BuildId:          buildID,
+			DigestFunction:   remoteexecution.DigestFunction_SHA256,
+			OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+		})
+		return err
+	}
+
+	require.NoError(t, startBuild("9da951b8cb759233037166e28f7ea186", "37f5dbef-b117-4fb6-bce8-5c147cb603b4"))
+
+	t.Run("LimitReached", func(t *testing.T) {
+		// Starting a build against another output base would
+		// cause the limit to be exceeded.
+		testutil.RequireEqualStatus(
+			t,
+			status.Error(codes.ResourceExhausted, "The maximum number of 1 concurrent builds has been reached"),
+			startBuild("7c3e5ea1a68b4e9f8c8e3a2b1d0f9e8d", "0a3c4a01-f2b1-4f4d-9d5f-8e1e1f2b3c4d"))
+	})
+
+	t.Run("SameOutputBase", func(t *testing.T) {
+		// Starting a new build against the same output base
+		// implicitly finalizes the running build, meaning the
+		// number of running builds remains the same.
+		require.NoError(t, startBuild("9da951b8cb759233037166e28f7ea186", "b4e6ec5c-8d9a-4f8d-a3f4-2f3c1e5d6a7b"))
+	})
+
+	t.Run("AfterFinalizeBuild", func(t *testing.T) {
+		// Once the running build is finalized, builds against
+		// other output bases may be started.
+		_, err := d.FinalizeBuild(ctx, &remoteoutputservice.FinalizeBuildRequest{
+			BuildId: "b4e6ec5c-8d9a-4f8d-a3f4-2f3c1e5d6a7b",
+		})
+		require.NoError(t, err)
+		require.NoError(t, startBuild("7c3e5ea1a68b4e9f8c8e3a2b1d0f9e8d", "0a3c4a01-f2b1-4f4d-9d5f-8e1e1f2b3c4d"))
+	})
+}
+
+func TestRemoteOutputServiceDirectoryDefaultDigestFunction(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	contentAddressableStorage := cd_testutil.NewFakeContentAddressableStorage()
+	handleAllocator := re_vfs.NewFUSEHandleAllocator(random.FastThreadSafeGenerator)
+	symlinkFactory := re_vfs.NewHandleAllocatingSymlinkFactory(
+		re_vfs.BaseSymlinkFactory,
+		handleAllocator.New())
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(1000, 0)).AnyTimes()
+	d := newTestRemoteOutputServiceDirectory(
+		handleAllocator,
+		cd_vfs.NewInMemoryOutputPathFactory(re_filesystem.InMemoryFilePool, symlinkFactory, handleAllocator, sort.Sort, clock),
+		contentAddressableStorage,
+		contentAddressableStorage,
+		re_cas.NewBlobAccessDirectoryFetcher(
+			contentAddressableStorage,
+			/* maximumDirectorySizeBytes = */ 10000,
+			/* maximumTreeSizeBytes = */ 10000),
+		symlinkFactory,
+		allowAllAuthorizer,
+		clock,
+		cd_vfs.RemoteOutputServiceDirectoryOptions{
+			DefaultDigestFunction: remoteexecution.DigestFunction_SHA256,
+		})
+
+	// StartBuild() requests that don't specify a digest function
+	// should use the default.
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+
+	t.Run("MatchingDigest", func(t *testing.T) {
+		_, err := d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Files: []*remoteexecution.OutputFile{
+				{
+					Path: "hello.txt",
+					Digest: contentAddressableStorage.PutBlob(
+						digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256),
+						[]byte("Hello")).GetProto(),
+				},
+			},
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("MismatchingDigest", func(t *testing.T) {
+		_, err := d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Files: []*remoteexecution.OutputFile{
+				{
+					Path: "hello.txt",
+					Digest: &remoteexecution.Digest{
+						Hash:      "8b1a9953c4611296a827abf8c47804d7",
+						SizeBytes: 5,
+					},
+				},
+			},
+		})
+		require.Error(t, err)
+	})
+}
+
+func TestRemoteOutputServiceDirectoryInvalidateCache(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	contentAddressableStorage := cd_testutil.NewFakeContentAddressableStorage()
+	fuseHandleAllocator := re_vfs.NewFUSEHandleAllocator(random.FastThreadSafeGenerator)
+	directoryHandleTracker := cd_vfs.NewDirectoryHandleTracker()
+	handleAllocator := cd_vfs.NewDirectoryHandleTrackingHandleAllocator(fuseHandleAllocator, directoryHandleTracker)
+	symlinkFactory := re_vfs.NewHandleAllocatingSymlinkFactory(
+		re_vfs.BaseSymlinkFactory,
+		handleAllocator.New())
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(1000, 0)).AnyTimes()
+	d := newTestRemoteOutputServiceDirectory(
+		handleAllocator,
+		cd_vfs.NewInMemoryOutputPathFactory(re_filesystem.InMemoryFilePool, symlinkFactory, handleAllocator, sort.Sort, clock),
+		contentAddressableStorage,
+		contentAddressableStorage,
+		re_cas.NewBlobAccessDirectoryFetcher(
+			contentAddressableStorage,
+			/* maximumDirectorySizeBytes = */ 10000,
+			/* maximumTreeSizeBytes = */ 10000),
+		symlinkFactory,
+		allowAllAuthorizer,
+		clock,
+		cd_vfs.RemoteOutputServiceDirectoryOptions{
+			DirectoryHandleTracker: directoryHandleTracker,
+		})
+
+	var removals []path.Component
+	fuseHandleAllocator.RegisterRemovalNotifier(func(parent uint64, name path.Component) {
+		removals = append(removals, name)
+	})
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		DigestFunction:   remoteexecution.DigestFunction_SHA256,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+	_, err = d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+		BuildId:    "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		PathPrefix: "bazel-out",
+		Symlinks: []*remoteexecution.OutputSymlink{
+			{
+				Path:   "link",
+				Target: "target",
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	t.Run("UnknownOutputBase", func(t *testing.T) {
+		_, err := d.InvalidateCache(ctx, &outputpaths.InvalidateCacheRequest{
+			OutputBaseId: "7c3e5ea1a68b4e9f8c8e3a2b1d0f9e8d",
+			Path:         "bazel-out/link",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.NotFound, "Output base ID is not associated with any output path"), err)
+	})
+
+	t.Run("NonexistentDirectory", func(t *testing.T) {
+		_, err := d.InvalidateCache(ctx, &outputpaths.InvalidateCacheRequest{
+			OutputBaseId: "9da951b8cb759233037166e28f7ea186",
+			Path:         "nonexistent/link",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.NotFound, "Path \"nonexistent/link\" does not exist"), err)
+	})
+
+	t.Run("RootDirectory", func(t *testing.T) {
+		_, err := d.InvalidateCache(ctx, &outputpaths.InvalidateCacheRequest{
+			OutputBaseId: "9da951b8cb759233037166e28f7ea186",
+			Path:         "",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Path \"\" does not refer to an entry within a directory"), err)
+	})
+
+	t.Run("ExistingEntry", func(t *testing.T) {
+		removals = nil
+		_, err := d.InvalidateCache(ctx, &outputpaths.InvalidateCacheRequest{
+			OutputBaseId: "9da951b8cb759233037166e28f7ea186",
+			Path:         "bazel-out/link",
+		})
+		require.NoError(t, err)
+		require.Equal(t, []path.Component{path.MustNewComponent("link")}, removals)
+	})
+
+	t.Run("NonexistentEntry", func(t *testing.T) {
+		// The kernel may still have a stale directory entry
+		// cached for entries that no longer exist, meaning
+		// these should be invalidated as well.
+		removals = nil
+		_, err := d.InvalidateCache(ctx, &outputpaths.InvalidateCacheRequest{
+			OutputBaseId: "9da951b8cb759233037166e28f7ea186",
+			Path:         "bazel-out/nonexistent",
+		})
+		require.NoError(t, err)
+		require.Equal(t, []path.Component{path.MustNewComponent("nonexistent")}, removals)
+	})
+}
+
+func TestRemoteOutputServiceDirectoryRejectInstanceNameChanges(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	contentAddressableStorage := cd_testutil.NewFakeContentAddressableStorage()
+	handleAllocator := re_vfs.NewFUSEHandleAllocator(random.FastThreadSafeGenerator)
+	symlinkFactory := re_vfs.NewHandleAllocatingSymlinkFactory(
+		re_vfs.BaseSymlinkFactory,
+		handleAllocator.New())
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(1000, 0)).AnyTimes()
+	d := newTestRemoteOutputServiceDirectory(
+		handleAllocator,
+		cd_vfs.NewInMemoryOutputPathFactory(re_filesystem.InMemoryFilePool, symlinkFactory, handleAllocator, sort.Sort, clock),
+		contentAddressableStorage,
+		contentAddressableStorage,
+		re_cas.NewBlobAccessDirectoryFetcher(
+			contentAddressableStorage,
+			/* maximumDirectorySizeBytes = */ 10000,
+			/* maximumTreeSizeBytes = */ 10000),
+		symlinkFactory,
+		allowAllAuthorizer,
+		clock,
+		cd_vfs.RemoteOutputServiceDirectoryOptions{
+			RejectInstanceNameChanges: true,
+		})
+
+	startBuild := func(buildID, instanceName string) error {
+		_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+			OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+			BuildId:          buildID,
+			InstanceName:     instanceName,
+			DigestFunction:   remoteexecution.DigestFunction_SHA256,
+			OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+		})
+		return err
+	}
+
+	require.NoError(t, startBuild("37f5dbef-b117-4fb6-bce8-5c147cb603b4", "cluster-a"))
+
+	t.Run("SameInstanceName", func(t *testing.T) {
+		require.NoError(t, startBuild("0a3c4a01-f2b1-4f4d-9d5f-8e1e1f2b3c4d", "cluster-a"))
+	})
+
+	t.Run("DifferentInstanceName", func(t *testing.T) {
+		testutil.RequireEqualStatus(
+			t,
+			status.Error(codes.FailedPrecondition, "Output base was last used with instance name \"cluster-a\", while this build uses instance name \"cluster-b\""),
+			startBuild("b4e6ec5c-8d9a-4f8d-a3f4-2f3c1e5d6a7b", "cluster-b"))
+	})
+
+	t.Run("AfterClean", func(t *testing.T) {
+		// Once the output base is cleaned, it may be used with
+		// another instance name.
+		_, err := d.Clean(ctx, &remoteoutputservice.CleanRequest{
+			OutputBaseId: "9da951b8cb759233037166e28f7ea186",
+		})
+		require.NoError(t, err)
+		require.NoError(t, startBuild("b4e6ec5c-8d9a-4f8d-a3f4-2f3c1e5d6a7b", "cluster-b"))
+	})
+}
+
+func TestRemoteOutputServiceDirectoryOutputPathStorageSelector(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	contentAddressableStorage := cd_testutil.NewFakeContentAddressableStorage()
+	handleAllocator := re_vfs.NewFUSEHandleAllocator(random.FastThreadSafeGenerator)
+	symlinkFactory := re_vfs.NewHandleAllocatingSymlinkFactory(
+		re_vfs.BaseSymlinkFactory,
+		handleAllocator.New())
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(1000, 0)).AnyTimes()
+
+	// Let builds against instance name "tenant-a" use a separate
+	// Content Addressable Storage.
+	tenantContentAddressableStorage := cd_testutil.NewFakeContentAddressableStorage()
+	selector := cd_vfs.NewPrefixOutputPathStorageSelector()
+	selector.AddInstanceNamePrefix(digest.MustNewInstanceName("tenant-a"), cd_vfs.OutputPathStorage{
+		BareContentAddressableStorage:     tenantContentAddressableStorage,
+		RetryingContentAddressableStorage: tenantContentAddressableStorage,
+		DirectoryFetcher: re_cas.NewBlobAccessDirectoryFetcher(
+			tenantContentAddressableStorage,
+			/* maximumDirectorySizeBytes = */ 10000,
+			/* maximumTreeSizeBytes = */ 10000),
+	})
+	d := newTestRemoteOutputServiceDirectory(
+		handleAllocator,
+		cd_vfs.NewInMemoryOutputPathFactory(re_filesystem.InMemoryFilePool, symlinkFactory, handleAllocator, sort.Sort, clock),
+		contentAddressableStorage,
+		contentAddressableStorage,
+		re_cas.NewBlobAccessDirectoryFetcher(
+			contentAddressableStorage,
+			/* maximumDirectorySizeBytes = */ 10000,
+			/* maximumTreeSizeBytes = */ 10000),
+		symlinkFactory,
+		allowAllAuthorizer,
+		clock,
+		cd_vfs.RemoteOutputServiceDirectoryOptions{
+			OutputPathStorageSelector: selector,
+		})
+
+	startBuild := func(buildID, instanceName string) error {
+		_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+			OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+			BuildId:          buildID,
+			InstanceName:     instanceName,
+			DigestFunction:   remoteexecution.DigestFunction_SHA256,
+			OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+		})
+		return err
+	}
+
+	require.NoError(t, startBuild("37f5dbef-b117-4fb6-bce8-5c147cb603b4", "tenant-a"))
+
+	t.Run("SameStorage", func(t *testing.T) {
+		// Switching to an instance name that selects the same
+		// storage is permitted.
+		require.NoError(t, startBuild("0a3c4a01-f2b1-4f4d-9d5f-8e1e1f2b3c4d", "tenant-a/project"))
+	})
+
+	t.Run("DifferentStorage", func(t *testing.T) {
+		// The output path can't switch to the default storage,
+		// as its files are backed by that of the tenant.
+		testutil.RequireEqualStatus(
+			t,
+			status.Error(codes.FailedPrecondition, "Output base was last used with instance name \"tenant-a/project\", which uses different storage than instance name \"tenant-b\" used by this build. Clean the output base to use this instance name"),
+			startBuild("b4e6ec5c-8d9a-4f8d-a3f4-2f3c1e5d6a7b", "tenant-b"))
+	})
+
+	t.Run("AfterClean", func(t *testing.T) {
+		_, err := d.Clean(ctx, &remoteoutputservice.CleanRequest{
+			OutputBaseId: "9da951b8cb759233037166e28f7ea186",
+		})
+		require.NoError(t, err)
+		require.NoError(t, startBuild("b4e6ec5c-8d9a-4f8d-a3f4-2f3c1e5d6a7b", "tenant-b"))
+	})
+}
+
+func TestRemoteOutputServiceDirectoryStartBuildStream(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	contentAddressableStorage := cd_testutil.NewFakeContentAddressableStorage()
+	d := cd_testutil.NewInMemoryRemoteOutputServiceDirectory(contentAddressableStorage)
+
+	// Run a build that creates one file that is present in the
+	// Content Addressable Storage, and one that is absent.
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		DigestFunction:   remoteexecution.DigestFunction_SHA256,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+	_, err = d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+		BuildId:    "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		PathPrefix: "bazel-out",
+		Files: []*remoteexecution.OutputFile{
+			{
+				Path: "present.txt",
+				Digest: contentAddressableStorage.PutBlob(
+					digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256),
+					[]byte("Hello")).GetProto(),
+			},
+			{
+				Path: "absent.txt",
+				Digest: &remoteexecution.Digest{
+					Hash:      "a948904f2f0f479b8f8197694b30184b0d2ed1c1cd2a1ec0fb85d299a192a447",
+					SizeBytes: 12,
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	_, err = d.FinalizeBuild(ctx, &remoteoutputservice.FinalizeBuildRequest{
+		BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+	})
+	require.NoError(t, err)
+
+	t.Run("InvalidRequest", func(t *testing.T) {
+		server := mock.NewMockOutputPathsStartBuildStreamServer(ctrl)
+		server.EXPECT().Context().Return(ctx).AnyTimes()
+
+		testutil.RequireEqualStatus(
+			t,
+			status.Error(codes.InvalidArgument, "Output base ID must consist of one or more valid filenames separated by slashes: Component 1 is empty"),
+			d.StartBuildStream(&remoteoutputservice.StartBuildRequest{
+				OutputBaseId:     "",
+				BuildId:          "0a3c4a01-f2b1-4f4d-9d5f-8e1e1f2b3c4d",
+				DigestFunction:   remoteexecution.DigestFunction_SHA256,
+				OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+			}, server))
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		// Starting the next build should report that the absent
+		// file was removed, followed by the response.
+		server := mock.NewMockOutputPathsStartBuildStreamServer(ctrl)
+		server.EXPECT().Context().Return(ctx).AnyTimes()
+		progress := &outputpaths.StartBuildProgress{
+			EntriesScanned:   2,
+			BlobsChecked:     2,
+			EntriesRemoved:   1,
+			FindMissingCalls: 1,
+		}
+		gomock.InOrder(
+			server.EXPECT().Send(testutil.EqProto(t, &outputpaths.StartBuildStreamResponse{
+				Event: &outputpaths.StartBuildStreamResponse_Progress{
+					Progress: progress,
+				},
+			})).DoAndReturn(func(*outputpaths.StartBuildStreamResponse) error {
+				// While the contents of the output path
+				// are being checked, the progress should
+				// also be reported by GetActiveBuild().
+				response, err := d.GetActiveBuild(ctx, &outputpaths.GetActiveBuildRequest{
+					OutputBaseId: "9da951b8cb759233037166e28f7ea186",
+				})
+				require.NoError(t, err)
+				testutil.RequireEqualProto(t, progress, response.ActiveBuild.StartBuildProgress)
+				return nil
+			}),
+			server.EXPECT().Send(testutil.EqProto(t, &outputpaths.StartBuildStreamResponse{
+				Event: &outputpaths.StartBuildStreamResponse_Response{
+					Response: &remoteoutputservice.StartBuildResponse{
+						OutputPathSuffix: "9da951b8cb759233037166e28f7ea186",
+					},
+				},
+			})))
+
+		require.NoError(t, d.StartBuildStream(&remoteoutputservice.StartBuildRequest{
+			OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+			BuildId:          "0a3c4a01-f2b1-4f4d-9d5f-8e1e1f2b3c4d",
+			DigestFunction:   remoteexecution.DigestFunction_SHA256,
+			OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+		}, server))
+
+		// The number of FindMissingBlobs() calls should remain
+		// available after StartBuild() completes.
+		response, err := d.GetActiveBuild(ctx, &outputpaths.GetActiveBuildRequest{
+			OutputBaseId: "9da951b8cb759233037166e28f7ea186",
+		})
+		require.NoError(t, err)
+		require.Equal(t, uint64(1), response.ActiveBuild.FindMissingCalls)
+		require.Equal(t, uint64(2), response.ActiveBuild.BlobsChecked)
+		require.Nil(t, response.ActiveBuild.StartBuildProgress)
+	})
+}
+
+func TestRemoteOutputServiceDirectoryCloneOutputPath(t *testing.T) {
+	ctx := context.Background()
+
+	contentAddressableStorage := cd_testutil.NewFakeContentAddressableStorage()
+	d := cd_testutil.NewInMemoryRemoteOutputServiceDirectory(contentAddressableStorage)
+
+	// Let the source output base contain a file and a symbolic link.
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		DigestFunction:   remoteexecution.DigestFunction_SHA256,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+	fileDigest := contentAddressableStorage.PutBlob(
+		digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256),
+		[]byte("Hello"))
+	_, err = d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+		BuildId:    "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		PathPrefix: "bazel-out",
+		Files: []*remoteexecution.OutputFile{
+			{
+				Path:   "hello.txt",
+				Digest: fileDigest.GetProto(),
+			},
+		},
+		Symlinks: []*remoteexecution.OutputSymlink{
+			{
+				Path:   "link.txt",
+				Target: "hello.txt",
+			},
+		},
+	})
+	require.NoError(t, err)
+	_, err = d.FinalizeBuild(ctx, &remoteoutputservice.FinalizeBuildRequest{
+		BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+	})
+	require.NoError(t, err)
+
+	// Let another output base use a different digest function and
+	// instance name.
+	for i, startBuildRequest := range []*remoteoutputservice.StartBuildRequest{
+		{
+			OutputBaseId:     "e8d5c1b0a4f34e2d9c7b6a5f4e3d2c1b",
+			BuildId:          "5b1e0c2d-3f4a-4b5c-8d6e-7f8091a2b3c4",
+			DigestFunction:   remoteexecution.DigestFunction_SHA1,
+			OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+		},
+		{
+			OutputBaseId:     "f1a2b3c4d5e64f708192a3b4c5d6e7f8",
+			BuildId:          "8c7d6e5f-4a3b-4c2d-9e1f-0a1b2c3d4e5f",
+			InstanceName:     "other-cluster",
+			DigestFunction:   remoteexecution.DigestFunction_SHA256,
+			OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+		},
+	} {
+		_, err = d.StartBuild(ctx, startBuildRequest)
+		require.NoError(t, err, i)
+		_, err = d.FinalizeBuild(ctx, &remoteoutputservice.FinalizeBuildRequest{
+			BuildId: startBuildRequest.BuildId,
+		})
+		require.NoError(t, err, i)
+	}
+
+	_, err = d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "7c3e5ea1a68b4e9f8c8e3a2b1d0f9e8d",
+		BuildId:          "0a3c4a01-f2b1-4f4d-9d5f-8e1e1f2b3c4d",
+		DigestFunction:   remoteexecution.DigestFunction_SHA256,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+
+	t.Run("DifferentDigestFunction", func(t *testing.T) {
+		_, err := d.CloneOutputPath(ctx, &outputpaths.CloneOutputPathRequest{
+			BuildId:            "0a3c4a01-f2b1-4f4d-9d5f-8e1e1f2b3c4d",
+			SourceOutputBaseId: "e8d5c1b0a4f34e2d9c7b6a5f4e3d2c1b",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.FailedPrecondition, "Source output base was last used with digest function SHA1, while this build uses digest function SHA256"), err)
+	})
+
+	t.Run("DifferentInstanceName", func(t *testing.T) {
+		_, err := d.CloneOutputPath(ctx, &outputpaths.CloneOutputPathRequest{
+			BuildId:            "0a3c4a01-f2b1-4f4d-9d5f-8e1e1f2b3c4d",
+			SourceOutputBaseId: "f1a2b3c4d5e64f708192a3b4c5d6e7f8",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.FailedPrecondition, "Source output base was last used with instance name \"other-cluster\", while this build uses instance name \"\""), err)
+	})
+
+	t.Run("UnknownSourceOutputBase", func(t *testing.T) {
+		_, err := d.CloneOutputPath(ctx, &outputpaths.CloneOutputPathRequest{
+			BuildId:            "0a3c4a01-f2b1-4f4d-9d5f-8e1e1f2b3c4d",
+			SourceOutputBaseId: "b4e6ec5c8d9a4f8da3f42f3c1e5d6a7b",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.NotFound, "Source output base ID is not associated with any output path"), err)
+	})
+
+	t.Run("SameOutputBase", func(t *testing.T) {
+		_, err := d.CloneOutputPath(ctx, &outputpaths.CloneOutputPathRequest{
+			BuildId:            "0a3c4a01-f2b1-4f4d-9d5f-8e1e1f2b3c4d",
+			SourceOutputBaseId: "7c3e5ea1a68b4e9f8c8e3a2b1d0f9e8d",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Source output base ID is the output base ID of the build"), err)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		response, err := d.CloneOutputPath(ctx, &outputpaths.CloneOutputPathRequest{
+			BuildId:            "0a3c4a01-f2b1-4f4d-9d5f-8e1e1f2b3c4d",
+			SourceOutputBaseId: "9da951b8cb759233037166e28f7ea186",
+		})
+		require.NoError(t, err)
+		require.NotNil(t, response.RootTreeDigest)
+
+		// The cloned output path should reference the same
+		// file in the Content Addressable Storage.
+		directory, err := d.ReadDirectory(ctx, &outputpaths.ReadDirectoryRequest{
+			BuildId:           "0a3c4a01-f2b1-4f4d-9d5f-8e1e1f2b3c4d",
+			Path:              "bazel-out",
+			IncludeFileDigest: true,
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &outputpaths.ReadDirectoryResponse{
+			Entries: []*outputpaths.DirectoryEntry{
+				{
+					Name: "hello.txt",
+					Status: &remoteoutputservice.FileStatus{
+						FileType: &remoteoutputservice.FileStatus_File_{
+							File: &remoteoutputservice.FileStatus_File{
+								Digest: fileDigest.GetProto(),
+							},
+						},
+					},
+				},
+				{
+					Name: "link.txt",
+					Status: &remoteoutputservice.FileStatus{
+						FileType: &remoteoutputservice.FileStatus_Symlink_{
+							Symlink: &remoteoutputservice.FileStatus_Symlink{
+								Target: "hello.txt",
+							},
+						},
+					},
+				},
+			},
+		}, directory)
+	})
+}
+
+func TestRemoteOutputServiceDirectoryStatDigest(t *testing.T) {
+	ctx := context.Background()
+
+	contentAddressableStorage := cd_testutil.NewFakeContentAddressableStorage()
+	d := cd_testutil.NewInMemoryRemoteOutputServiceDirectory(contentAddressableStorage)
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		DigestFunction:   remoteexecution.DigestFunction_SHA256,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+	fileDigest := contentAddressableStorage.PutBlob(
+		digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256),
+		[]byte("Hello"))
+	_, err = d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+		BuildId:    "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		PathPrefix: "bazel-out",
+		Files: []*remoteexecution.OutputFile{
+			{
+				Path:   "hello.txt",
+				Digest: fileDigest.GetProto(),
+			},
+		},
+		Symlinks: []*remoteexecution.OutputSymlink{
+			{
+				Path:   "link.txt",
+				Target: "hello.txt",
+			},
+			{
+				Path:   "external.txt",
+				Target: "/etc/passwd",
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	t.Run("UnknownBuildID", func(t *testing.T) {
+		_, err := d.StatDigest(ctx, &outputpaths.StatDigestRequest{
+			BuildId: "0a3c4a01-f2b1-4f4d-9d5f-8e1e1f2b3c4d",
+			Path:    "bazel-out/hello.txt",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.FailedPrecondition, "Build ID is not associated with any running build"), err)
+	})
+
+	t.Run("NonexistentPath", func(t *testing.T) {
+		_, err := d.StatDigest(ctx, &outputpaths.StatDigestRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Path:    "bazel-out/nonexistent.txt",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.NotFound, "Path \"bazel-out/nonexistent.txt\" does not exist"), err)
+	})
+
+	t.Run("File", func(t *testing.T) {
+		response, err := d.StatDigest(ctx, &outputpaths.StatDigestRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Path:    "bazel-out/hello.txt",
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &outputpaths.StatDigestResponse{
+			FileType: outputpaths.StatDigestResponse_FILE,
+			Digest:   fileDigest.GetProto(),
+		}, response)
+	})
+
+	t.Run("Directory", func(t *testing.T) {
+		response, err := d.StatDigest(ctx, &outputpaths.StatDigestRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Path:    "bazel-out",
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &outputpaths.StatDigestResponse{
+			FileType: outputpaths.StatDigestResponse_DIRECTORY,
+		}, response)
+	})
+
+	t.Run("Symlink", func(t *testing.T) {
+		// Without following symbolic links, no digest can be
+		// returned.
+		response, err := d.StatDigest(ctx, &outputpaths.StatDigestRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Path:    "bazel-out/link.txt",
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &outputpaths.StatDigestResponse{
+			FileType: outputpaths.StatDigestResponse_SYMLINK,
+		}, response)
+
+		// When following symbolic links, the digest of the
+		// target should be returned.
+		response, err = d.StatDigest(ctx, &outputpaths.StatDigestRequest{
+			BuildId:        "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Path:           "bazel-out/link.txt",
+			FollowSymlinks: true,
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &outputpaths.StatDigestResponse{
+			FileType: outputpaths.StatDigestResponse_FILE,
+			Digest:   fileDigest.GetProto(),
+		}, response)
+	})
+
+	t.Run("External", func(t *testing.T) {
+		response, err := d.StatDigest(ctx, &outputpaths.StatDigestRequest{
+			BuildId:        "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Path:           "bazel-out/external.txt",
+			FollowSymlinks: true,
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &outputpaths.StatDigestResponse{
+			FileType: outputpaths.StatDigestResponse_EXTERNAL,
+		}, response)
+	})
+
+	t.Run("ExternalPathPolicy", func(t *testing.T) {
+		// Paths are resolved in the same way as BatchStat()
+		// does, meaning that the external path policy of the
+		// build is respected.
+		_, err := d.SetExternalPathPolicy(ctx, &outputpaths.SetExternalPathPolicyRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Policy:  outputpaths.ExternalPathPolicy_RETURN_ERROR,
+		})
+		require.NoError(t, err)
+
+		_, err = d.StatDigest(ctx, &outputpaths.StatDigestRequest{
+			BuildId:        "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Path:           "bazel-out/external.txt",
+			FollowSymlinks: true,
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Path \"bazel-out/external.txt\" resolves to \"/etc/passwd\", which is outside the output path"), err)
+	})
+}
+
+func TestRemoteOutputServiceDirectoryMaximumStartBuildDuration(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	bareContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	retryingContentAddressableStorage := cd_testutil.NewFakeContentAddressableStorage()
+	handleAllocator := re_vfs.NewFUSEHandleAllocator(random.FastThreadSafeGenerator)
+	symlinkFactory := re_vfs.NewHandleAllocatingSymlinkFactory(
+		re_vfs.BaseSymlinkFactory,
+		handleAllocator.New())
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(1000, 0)).AnyTimes()
+	d := newTestRemoteOutputServiceDirectory(
+		handleAllocator,
+		cd_vfs.NewInMemoryOutputPathFactory(re_filesystem.InMemoryFilePool, symlinkFactory, handleAllocator, sort.Sort, clock),
+		bareContentAddressableStorage,
+		retryingContentAddressableStorage,
+		re_cas.NewBlobAccessDirectoryFetcher(
+			retryingContentAddressableStorage,
+			/* maximumDirectorySizeBytes = */ 10000,
+			/* maximumTreeSizeBytes = */ 10000),
+		symlinkFactory,
+		allowAllAuthorizer,
+		clock,
+		cd_vfs.RemoteOutputServiceDirectoryOptions{
+			MaximumStartBuildDuration: 10 * time.Millisecond,
+		})
+
+	// Let the output path contain a single file, so that the next
+	// call to StartBuild() needs to call FindMissingBlobs().
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		DigestFunction:   remoteexecution.DigestFunction_SHA256,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+	_, err = d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+		BuildId:    "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		PathPrefix: "bazel-out",
+		Files: []*remoteexecution.OutputFile{
+			{
+				Path: "hello.txt",
+				Digest: &remoteexecution.Digest{
+					Hash:      "185f8db32271fe25f561a6fc938b2e264306ec304eda518007d1764826381969",
+					SizeBytes: 5,
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	_, err = d.FinalizeBuild(ctx, &remoteoutputservice.FinalizeBuildRequest{
+		BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+	})
+	require.NoError(t, err)
+
+	// If the Content Addressable Storage does not respond,
+	// StartBuild() should fail once the maximum duration is
+	// reached, even though the client did not provide a deadline.
+	bareContentAddressableStorage.EXPECT().FindMissing(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, digests digest.Set) (digest.Set, error) {
+			<-ctx.Done()
+			return digest.EmptySet, util.StatusFromContext(ctx)
+		})
+
+	_, err = d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "0a3c4a01-f2b1-4f4d-9d5f-8e1e1f2b3c4d",
+		DigestFunction:   remoteexecution.DigestFunction_SHA256,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	testutil.RequireEqualStatus(t, status.Error(codes.DeadlineExceeded, "Failed to filter contents of the output path: Checking the contents of the output path did not complete within 10ms"), err)
+}
+
+func TestRemoteOutputServiceDirectoryRenameOutputBase(t *testing.T) {
+	ctx := context.Background()
+
+	contentAddressableStorage := cd_testutil.NewFakeContentAddressableStorage()
+	d := cd_testutil.NewInMemoryRemoteOutputServiceDirectory(contentAddressableStorage)
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		DigestFunction:   remoteexecution.DigestFunction_SHA256,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+	fileDigest := contentAddressableStorage.PutBlob(
+		digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256),
+		[]byte("Hello"))
+	_, err = d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+		BuildId:    "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		PathPrefix: "bazel-out",
+		Files: []*remoteexecution.OutputFile{
+			{
+				Path:   "hello.txt",
+				Digest: fileDigest.GetProto(),
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	t.Run("UnknownOutputBaseID", func(t *testing.T) {
+		_, err := d.RenameOutputBase(ctx, &outputpaths.RenameOutputBaseRequest{
+			OutputBaseId:    "c8e0e5a0cdd4bde3a2ea2d5a0e1fc2ba",
+			NewOutputBaseId: "8a2d3d7e1c9a5b6f4e0d2c1b3a5f7e9d",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.NotFound, "Output base ID is not associated with any output path"), err)
+	})
+
+	t.Run("InvalidNewOutputBaseID", func(t *testing.T) {
+		_, err := d.RenameOutputBase(ctx, &outputpaths.RenameOutputBaseRequest{
+			OutputBaseId:    "9da951b8cb759233037166e28f7ea186",
+			NewOutputBaseId: ".history",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "New output base ID may not start with reserved name \".history\""), err)
+	})
+
+	t.Run("RunningBuild", func(t *testing.T) {
+		// Output paths can only be renamed while idle, as the
+		// output path prefix announced to the client would
+		// otherwise no longer be valid.
+		_, err := d.RenameOutputBase(ctx, &outputpaths.RenameOutputBaseRequest{
+			OutputBaseId:    "9da951b8cb759233037166e28f7ea186",
+			NewOutputBaseId: "8a2d3d7e1c9a5b6f4e0d2c1b3a5f7e9d",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.FailedPrecondition, "Output base has a running build"), err)
+	})
+
+	_, err = d.FinalizeBuild(ctx, &remoteoutputservice.FinalizeBuildRequest{
+		BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+	})
+	require.NoError(t, err)
+
+	t.Run("NewOutputBaseIDInUse", func(t *testing.T) {
+		_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+			OutputBaseId:     "e4f7a1b2c3d5e6f708192a3b4c5d6e7f",
+			BuildId:          "0a3c4a01-f2b1-4f4d-9d5f-8e1e1f2b3c4d",
+			DigestFunction:   remoteexecution.DigestFunction_SHA256,
+			OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+		})
+		require.NoError(t, err)
+		_, err = d.FinalizeBuild(ctx, &remoteoutputservice.FinalizeBuildRequest{
+			BuildId: "0a3c4a01-f2b1-4f4d-9d5f-8e1e1f2b3c4d",
+		})
+		require.NoError(t, err)
+
+		_, err = d.RenameOutputBase(ctx, &outputpaths.RenameOutputBaseRequest{
+			OutputBaseId:    "9da951b8cb759233037166e28f7ea186",
+			NewOutputBaseId: "e4f7a1b2c3d5e6f708192a3b4c5d6e7f",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.AlreadyExists, "New output base ID is already associated with an output path"), err)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		_, err := d.RenameOutputBase(ctx, &outputpaths.RenameOutputBaseRequest{
+			OutputBaseId:    "9da951b8cb759233037166e28f7ea186",
+			NewOutputBaseId: "8a2d3d7e1c9a5b6f4e0d2c1b3a5f7e9d",
+		})
+		require.NoError(t, err)
+
+		// The old output base ID should no longer be present,
+		// while the contents of the output path should be
+		// accessible under the new output base ID.
+		_, s := d.VirtualLookup(ctx, path.MustNewComponent("9da951b8cb759233037166e28f7ea186"), 0, &re_vfs.Attributes{})
+		require.Equal(t, re_vfs.StatusErrNoEnt, s)
+
+		_, err = d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+			OutputBaseId:     "8a2d3d7e1c9a5b6f4e0d2c1b3a5f7e9d",
+			BuildId:          "b4e6ec5c-8d9a-4f8d-a3f4-2f3c1e5d6a7b",
+			DigestFunction:   remoteexecution.DigestFunction_SHA256,
+			OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+		})
+		require.NoError(t, err)
+		response, err := d.StatDigest(ctx, &outputpaths.StatDigestRequest{
+			BuildId: "b4e6ec5c-8d9a-4f8d-a3f4-2f3c1e5d6a7b",
+			Path:    "bazel-out/hello.txt",
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &outputpaths.StatDigestResponse{
+			FileType: outputpaths.StatDigestResponse_FILE,
+			Digest:   fileDigest.GetProto(),
+		}, response)
+	})
+}
+
+func TestRemoteOutputServiceDirectoryRenameOutputBaseFailure(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	handleAllocator := mock.NewMockStatefulHandleAllocator(ctrl)
+	outputPathFactory := mock.NewMockOutputPathFactory(ctrl)
+	bareContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	retryingContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	directoryFetcher := mock.NewMockDirectoryFetcher(ctrl)
+	symlinkFactory := mock.NewMockSymlinkFactory(ctrl)
+	dHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	handleAllocator.EXPECT().New().Return(dHandleAllocation)
+	dHandle := mock.NewMockStatefulDirectoryHandle(ctrl)
+	dHandleAllocation.EXPECT().AsStatefulDirectory(gomock.Any()).Return(dHandle)
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(1000, 0)).AnyTimes()
+	d := newTestRemoteOutputServiceDirectory(
+		handleAllocator,
+		outputPathFactory,
+		bareContentAddressableStorage,
+		retryingContentAddressableStorage,
+		directoryFetcher,
+		symlinkFactory,
+		allowAllAuthorizer,
+		clock,
+		cd_vfs.RemoteOutputServiceDirectoryOptions{})
+
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocator := mock.NewMockStatelessHandleAllocator(ctrl)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(casFileHandleAllocator)
+	outputPath := mock.NewMockOutputPath(ctrl)
+	outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+		gomock.Any(),
+		digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256),
+		gomock.Any(),
+	).Return(outputPath)
+	outputPath.EXPECT().FilterChildren(gomock.Any())
+	outputPath.EXPECT().FinalizeBuild(gomock.Any(), digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256))
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		DigestFunction:   remoteexecution.DigestFunction_SHA256,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+	_, err = d.FinalizeBuild(ctx, &remoteoutputservice.FinalizeBuildRequest{
+		BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+	})
+	require.NoError(t, err)
+
+	// If the persistent state of the output path cannot be
+	// renamed, the output path should remain accessible under its
+	// current output base ID.
+	outputPath.EXPECT().Rename(path.MustNewComponent("8a2d3d7e1c9a5b6f4e0d2c1b3a5f7e9d")).
+		Return(status.Error(codes.Internal, "Disk on fire"))
+
+	_, err = d.RenameOutputBase(ctx, &outputpaths.RenameOutputBaseRequest{
+		OutputBaseId:    "9da951b8cb759233037166e28f7ea186",
+		NewOutputBaseId: "8a2d3d7e1c9a5b6f4e0d2c1b3a5f7e9d",
+	})
+	testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Failed to rename output path: Disk on fire"), err)
+
+	_, err = d.RenameOutputBase(ctx, &outputpaths.RenameOutputBaseRequest{
+		OutputBaseId:    "8a2d3d7e1c9a5b6f4e0d2c1b3a5f7e9d",
+		NewOutputBaseId: "9da951b8cb759233037166e28f7ea186",
+	})
+	testutil.RequireEqualStatus(t, status.Error(codes.NotFound, "Output base ID is not associated with any output path"), err)
+
+	outputPath.EXPECT().FilterChildren(gomock.Any())
+
+	_, err = d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "0a3c4a01-f2b1-4f4d-9d5f-8e1e1f2b3c4d",
+		DigestFunction:   remoteexecution.DigestFunction_SHA256,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+}
+
+func TestRemoteOutputServiceDirectoryGetOutputPathDigestFunctions(t *testing.T) {
+	ctx := context.Background()
+
+	contentAddressableStorage := cd_testutil.NewFakeContentAddressableStorage()
+	d := cd_testutil.NewInMemoryRemoteOutputServiceDirectory(contentAddressableStorage)
+
+	t.Run("UnknownOutputBaseID", func(t *testing.T) {
+		_, err := d.GetOutputPathDigestFunctions(ctx, &outputpaths.GetOutputPathDigestFunctionsRequest{
+			OutputBaseId: "9da951b8cb759233037166e28f7ea186",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.NotFound, "Output base ID is not associated with any output path"), err)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+			OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+			BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			InstanceName:     "hello",
+			DigestFunction:   remoteexecution.DigestFunction_SHA256,
+			OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+		})
+		require.NoError(t, err)
+		digestFunction := digest.MustNewFunction("hello", remoteexecution.DigestFunction_SHA256)
+		_, err = d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+			BuildId:    "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			PathPrefix: "bazel-out",
+			Files: []*remoteexecution.OutputFile{
+				{
+					Path:   "a.txt",
+					Digest: contentAddressableStorage.PutBlob(digestFunction, []byte("Hello")).GetProto(),
+				},
+				{
+					Path:   "b.txt",
+					Digest: contentAddressableStorage.PutBlob(digestFunction, []byte("World")).GetProto(),
+				},
+				{
+					// Objects referenced by multiple files
+					// should only be counted once.
+					Path:   "c.txt",
+					Digest: contentAddressableStorage.PutBlob(digestFunction, []byte("Hello")).GetProto(),
+				},
+			},
+		})
+		require.NoError(t, err)
+
+		response, err := d.GetOutputPathDigestFunctions(ctx, &outputpaths.GetOutputPathDigestFunctionsRequest{
+			OutputBaseId: "9da951b8cb759233037166e28f7ea186",
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &outputpaths.GetOutputPathDigestFunctionsResponse{
+			DigestFunctions: []*outputpaths.DigestFunctionUsage{
+				{
+					InstanceName:   "hello",
+					DigestFunction: remoteexecution.DigestFunction_SHA256,
+					Objects:        2,
+				},
+			},
+		}, response)
+	})
+}
+
+func TestRemoteOutputServiceDirectorySealBuild(t *testing.T) {
+	ctx := context.Background()
+
+	contentAddressableStorage := cd_testutil.NewFakeContentAddressableStorage()
+	d := cd_testutil.NewInMemoryRemoteOutputServiceDirectory(contentAddressableStorage)
+
+	t.Run("UnknownBuildID", func(t *testing.T) {
+		_, err := d.SealBuild(ctx, &outputpaths.SealBuildRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.FailedPrecondition, "Build ID is not associated with any running build"), err)
+	})
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		DigestFunction:   remoteexecution.DigestFunction_SHA256,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+	fileDigest := contentAddressableStorage.PutBlob(
+		digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256),
+		[]byte("Hello"))
+	_, err = d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+		BuildId:    "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		PathPrefix: "bazel-out",
+		Files: []*remoteexecution.OutputFile{
+			{
+				Path:   "hello.txt",
+				Digest: fileDigest.GetProto(),
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	t.Run("Success", func(t *testing.T) {
+		// Sealing a build multiple times should be permitted.
+		for i := 0; i < 2; i++ {
+			_, err := d.SealBuild(ctx, &outputpaths.SealBuildRequest{
+				BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			})
+			require.NoError(t, err)
+		}
+	})
+
+	t.Run("BatchCreate", func(t *testing.T) {
+		_, err := d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+			BuildId:    "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			PathPrefix: "bazel-out",
+			Files: []*remoteexecution.OutputFile{
+				{
+					Path:   "goodbye.txt",
+					Digest: fileDigest.GetProto(),
+				},
+			},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.FailedPrecondition, "Build has been sealed, meaning its output path can no longer be modified"), err)
+	})
+
+	t.Run("SetOutputPath", func(t *testing.T) {
+		_, err := d.SetOutputPath(ctx, &outputpaths.SetOutputPathRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			RootTreeDigest: &remoteexecution.Digest{
+				Hash:      "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+				SizeBytes: 0,
+			},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.FailedPrecondition, "Build has been sealed, meaning its output path can no longer be modified"), err)
+	})
+
+	t.Run("StatDigest", func(t *testing.T) {
+		// Querying the output path should continue to work
+		// until the build is finalized.
+		response, err := d.StatDigest(ctx, &outputpaths.StatDigestRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Path:    "bazel-out/hello.txt",
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &outputpaths.StatDigestResponse{
+			FileType: outputpaths.StatDigestResponse_FILE,
+			Digest:   fileDigest.GetProto(),
+		}, response)
+	})
+
+	t.Run("NextBuild", func(t *testing.T) {
+		// Successive builds should not be sealed.
+		_, err := d.FinalizeBuild(ctx, &remoteoutputservice.FinalizeBuildRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		})
+		require.NoError(t, err)
+		_, err = d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+			OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+			BuildId:          "0a3c4a01-f2b1-4f4d-9d5f-8e1e1f2b3c4d",
+			DigestFunction:   remoteexecution.DigestFunction_SHA256,
+			OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+		})
+		require.NoError(t, err)
+		_, err = d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+			BuildId:    "0a3c4a01-f2b1-4f4d-9d5f-8e1e1f2b3c4d",
+			PathPrefix: "bazel-out",
+			Files: []*remoteexecution.OutputFile{
+				{
+					Path:   "goodbye.txt",
+					Digest: fileDigest.GetProto(),
+				},
+			},
+		})
+		require.NoError(t, err)
+	})
+}
+
+func TestRemoteOutputServiceDirectoryBatchStatUnsupportedFileType(t *testing.T) {
+	ctx := context.Background()
+
+	d := cd_testutil.NewInMemoryRemoteOutputServiceDirectory(cd_testutil.NewFakeContentAddressableStorage())
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		DigestFunction:   remoteexecution.DigestFunction_SHA256,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+
+	// Create a FIFO in the output path through the virtual file
+	// system. FIFOs cannot be expressed as a FileStatus.
+	child, s := d.VirtualLookup(ctx, path.MustNewComponent("9da951b8cb759233037166e28f7ea186"), 0, &re_vfs.Attributes{})
+	require.Equal(t, re_vfs.StatusOK, s)
+	outputPath, _ := child.GetPair()
+	_, _, s = outputPath.VirtualMknod(ctx, path.MustNewComponent("fifo"), filesystem.FileTypeFIFO, 0, &re_vfs.Attributes{})
+	require.Equal(t, re_vfs.StatusOK, s)
+
+	t.Run("BatchStat", func(t *testing.T) {
+		// The FIFO should be reported as being external, so
+		// that the client can obtain its status manually. This
+		// should not cause other paths to fail.
+		response, err := d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
+			BuildId:           "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			IncludeFileDigest: true,
+			Paths:             []string{"fifo", "nonexistent"},
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &remoteoutputservice.BatchStatResponse{
+			Responses: []*remoteoutputservice.StatResponse{
+				{
+					FileStatus: &remoteoutputservice.FileStatus{
+						FileType: &remoteoutputservice.FileStatus_External_{
+							External: &remoteoutputservice.FileStatus_External{
+								NextPath: "fifo",
+							},
+						},
+					},
+				},
+				{},
+			},
+		}, response)
+	})
+
+	t.Run("ExternalPathPolicy", func(t *testing.T) {
+		// The external path policy only applies to paths that
+		// resolve to a location outside the output path.
+		_, err := d.SetExternalPathPolicy(ctx, &outputpaths.SetExternalPathPolicyRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Policy:  outputpaths.ExternalPathPolicy_RETURN_ERROR,
+		})
+		require.NoError(t, err)
+
+		response, err := d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Paths:   []string{"fifo"},
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &remoteoutputservice.BatchStatResponse{
+			Responses: []*remoteoutputservice.StatResponse{
+				{
+					FileStatus: &remoteoutputservice.FileStatus{
+						FileType: &remoteoutputservice.FileStatus_External_{
+							External: &remoteoutputservice.FileStatus_External{
+								NextPath: "fifo",
+							},
+						},
+					},
+				},
+			},
+		}, response)
+	})
+}
+
+func TestRemoteOutputServiceDirectoryStaleBuildGracePeriod(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	contentAddressableStorage := cd_testutil.NewFakeContentAddressableStorage()
+	handleAllocator := re_vfs.NewFUSEHandleAllocator(random.FastThreadSafeGenerator)
+	symlinkFactory := re_vfs.NewHandleAllocatingSymlinkFactory(
+		re_vfs.BaseSymlinkFactory,
+		handleAllocator.New())
+	now := time.Unix(1000, 0)
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().DoAndReturn(func() time.Time { return now }).AnyTimes()
+	d := newTestRemoteOutputServiceDirectory(
+		handleAllocator,
+		cd_vfs.NewInMemoryOutputPathFactory(re_filesystem.InMemoryFilePool, symlinkFactory, handleAllocator, sort.Sort, clock),
+		contentAddressableStorage,
+		contentAddressableStorage,
+		re_cas.NewBlobAccessDirectoryFetcher(
+			contentAddressableStorage,
+			/* maximumDirectorySizeBytes = */ 10000,
+			/* maximumTreeSizeBytes = */ 10000),
+		symlinkFactory,
+		allowAllAuthorizer,
+		clock,
+		cd_vfs.RemoteOutputServiceDirectoryOptions{
+			StaleBuildGracePeriod: time.Minute,
+		})
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		DigestFunction:   remoteexecution.DigestFunction_SHA256,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+
+	t.Run("RecentlyActive", func(t *testing.T) {
+		// Requests against the existing build should extend the
+		// grace period. Starting another build should fail, as
+		// the existing build is likely still in use.
+		now = time.Unix(1050, 0)
+		_, err := d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Paths:   []string{"hello.txt"},
+		})
+		require.NoError(t, err)
+
+		now = time.Unix(1100, 0)
+		_, err = d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+			OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+			BuildId:          "0a3c4a01-f2b1-4f4d-9d5f-8e1e1f2b3c4d",
+			DigestFunction:   remoteexecution.DigestFunction_SHA256,
+			OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.FailedPrecondition, "Output base ID is in use by build \"37f5dbef-b117-4fb6-bce8-5c147cb603b4\", which was active less than 1m0s ago"), err)
+
+		// The existing build should remain usable.
+		_, err = d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Paths:   []string{"hello.txt"},
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("Stale", func(t *testing.T) {
+		// Once the grace period has passed, the existing build
+		// should be finalized forcefully.
+		now = time.Unix(1200, 0)
+		_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+			OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+			BuildId:          "0a3c4a01-f2b1-4f4d-9d5f-8e1e1f2b3c4d",
+			DigestFunction:   remoteexecution.DigestFunction_SHA256,
+			OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+		})
+		require.NoError(t, err)
+
+		_, err = d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Paths:   []string{"hello.txt"},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.FailedPrecondition, "Build ID is not associated with any running build"), err)
+	})
+}
+
+func TestRemoteOutputServiceDirectoryExportTarball(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	contentAddressableStorage := cd_testutil.NewFakeContentAddressableStorage()
+	d := cd_testutil.NewInMemoryRemoteOutputServiceDirectory(contentAddressableStorage)
+
+	// Export a tarball, returning the entries it contains in the
+	// form "${type} ${name} ${contents or symlink target}".
+	exportTarball := func(request *outputpaths.ExportTarballRequest) ([]string, error) {
+		var archive bytes.Buffer
+		server := mock.NewMockOutputPathsExportTarballServer(ctrl)
+		server.EXPECT().Context().Return(ctx).AnyTimes()
+		server.EXPECT().Send(gomock.Any()).DoAndReturn(func(response *outputpaths.ExportTarballResponse) error {
+			archive.Write(response.Data)
+			return nil
+		}).AnyTimes()
+		if err := d.ExportTarball(request, server); err != nil {
+			return nil, err
+		}
+
+		var entries []string
+		reader := tar.NewReader(&archive)
+		for {
+			header, err := reader.Next()
+			if err == io.EOF {
+				return entries, nil
+			}
+			require.NoError(t, err)
+			switch header.Typeflag {
+			case tar.TypeDir:
+				entries = append(entries, "dir "+header.Name)
+			case tar.TypeSymlink:
+				entries = append(entries, "symlink "+header.Name+" "+header.Linkname)
+			case tar.TypeReg:
+				contents, err := io.ReadAll(reader)
+				require.NoError(t, err)
+				entries = append(entries, fmt.Sprintf("file %s %s %o", header.Name, contents, header.Mode))
+			}
+		}
+	}
+
+	t.Run("InvalidBuildID", func(t *testing.T) {
+		_, err := exportTarball(&outputpaths.ExportTarballRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.FailedPrecondition, "Build ID is not associated with any running build"), err)
+	})
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		DigestFunction:   remoteexecution.DigestFunction_SHA256,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+	digestFunction := digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256)
+	_, err = d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+		BuildId:    "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		PathPrefix: "bazel-out",
+		Files: []*remoteexecution.OutputFile{
+			{
+				Path:   "a/hello.txt",
+				Digest: contentAddressableStorage.PutBlob(digestFunction, []byte("Hello")).GetProto(),
+			},
+			{
+				Path:         "b/world.sh",
+				Digest:       contentAddressableStorage.PutBlob(digestFunction, []byte("World")).GetProto(),
+				IsExecutable: true,
+			},
+		},
+		Symlinks: []*remoteexecution.OutputSymlink{
+			{
+				Path:   "link",
+				Target: "a/hello.txt",
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	t.Run("InvalidIncludePattern", func(t *testing.T) {
+		_, err := exportTarball(&outputpaths.ExportTarballRequest{
+			BuildId:         "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			IncludePatterns: []string{"["},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Invalid include pattern \"[\""), err)
+	})
+
+	t.Run("NonexistentPath", func(t *testing.T) {
+		_, err := exportTarball(&outputpaths.ExportTarballRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Path:    "nonexistent",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.NotFound, "Path \"nonexistent\" does not exist"), err)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		entries, err := exportTarball(&outputpaths.ExportTarballRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Path:    "bazel-out",
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{
+			"dir a/",
+			"file a/hello.txt Hello 644",
+			"dir b/",
+			"file b/world.sh World 755",
+			"symlink link a/hello.txt",
+		}, entries)
+	})
+
+	t.Run("IncludePatterns", func(t *testing.T) {
+		// Directories that don't contain any included entries
+		// should be omitted.
+		entries, err := exportTarball(&outputpaths.ExportTarballRequest{
+			BuildId:         "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			IncludePatterns: []string{"bazel-out/b/*.sh"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{
+			"dir bazel-out/",
+			"dir bazel-out/b/",
+			"file bazel-out/b/world.sh World 755",
+		}, entries)
+	})
+
+	t.Run("InvalidChunkSize", func(t *testing.T) {
+		_, err := exportTarball(&outputpaths.ExportTarballRequest{
+			BuildId:        "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			ChunkSizeBytes: 2 * 1024 * 1024,
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Chunk size of 2097152 bytes exceeds the maximum of 1048576 bytes"), err)
+	})
+
+	t.Run("InvalidReadConcurrency", func(t *testing.T) {
+		_, err := exportTarball(&outputpaths.ExportTarballRequest{
+			BuildId:         "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			ReadConcurrency: 65,
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Read concurrency of 65 exceeds the maximum of 64"), err)
+	})
+
+	t.Run("ChunkedWithProgress", func(t *testing.T) {
+		// Export a file that is larger than the chunk size,
+		// while reading multiple chunks in parallel. Chunks
+		// should be sent in order, should not exceed the chunk
+		// size, and should report monotonically increasing
+		// progress.
+		largeContents := bytes.Repeat([]byte("0123456789abcdef"), 1000)
+		_, err := d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+			BuildId:    "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			PathPrefix: "large",
+			Files: []*remoteexecution.OutputFile{
+				{
+					Path:   "file.bin",
+					Digest: contentAddressableStorage.PutBlob(digestFunction, largeContents).GetProto(),
+				},
+			},
+		})
+		require.NoError(t, err)
+
+		var archive bytes.Buffer
+		var lastProgress outputpaths.ExportTarballProgress
+		server := mock.NewMockOutputPathsExportTarballServer(ctrl)
+		server.EXPECT().Context().Return(ctx).AnyTimes()
+		server.EXPECT().Send(gomock.Any()).DoAndReturn(func(response *outputpaths.ExportTarballResponse) error {
+			require.LessOrEqual(t, len(response.Data), 1024)
+			require.GreaterOrEqual(t, response.Progress.EntriesExported, lastProgress.EntriesExported)
+			require.GreaterOrEqual(t, response.Progress.FileBytesExported, lastProgress.FileBytesExported)
+			lastProgress.EntriesExported = response.Progress.EntriesExported
+			lastProgress.FileBytesExported = response.Progress.FileBytesExported
+			archive.Write(response.Data)
+			return nil
+		}).MinTimes(16)
+		require.NoError(t, d.ExportTarball(&outputpaths.ExportTarballRequest{
+			BuildId:         "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Path:            "large",
+			ChunkSizeBytes:  1024,
+			ReadConcurrency: 4,
+		}, server))
+
+		require.Equal(t, uint64(1), lastProgress.EntriesExported)
+		require.Equal(t, uint64(len(largeContents)), lastProgress.FileBytesExported)
+		reader := tar.NewReader(&archive)
+		header, err := reader.Next()
+		require.NoError(t, err)
+		require.Equal(t, "file.bin", header.Name)
+		contents, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		require.Equal(t, largeContents, contents)
+		_, err = reader.Next()
+		require.Equal(t, io.EOF, err)
+	})
+
+	t.Run("Cancellation", func(t *testing.T) {
+		cancelledCtx, cancel := context.WithCancel(ctx)
+		cancel()
+		server := mock.NewMockOutputPathsExportTarballServer(ctrl)
+		server.EXPECT().Context().Return(cancelledCtx).AnyTimes()
+
+		testutil.RequireEqualStatus(
+			t,
+			status.Error(codes.Canceled, "context canceled"),
+			d.ExportTarball(&outputpaths.ExportTarballRequest{
+				BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			}, server))
+	})
+}
+
+func BenchmarkRemoteOutputServiceDirectoryExportTarball(b *testing.B) {
+	ctrl, ctx := gomock.WithContext(context.Background(), b)
+
+	contentAddressableStorage := cd_testutil.NewFakeContentAddressableStorage()
+	d := cd_testutil.NewInMemoryRemoteOutputServiceDirectory(contentAddressableStorage)
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		DigestFunction:   remoteexecution.DigestFunction_SHA256,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(b, err)
+
+	// Create a synthetic tree of 100 directories, each containing
+	// 100 files of 16 KiB.
+	digestFunction := digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256)
+	var files []*remoteexecution.OutputFile
+	for i := 0; i < 100; i++ {
+		for j := 0; j < 100; j++ {
+			contents := bytes.Repeat([]byte(fmt.Sprintf("%08d", i*100+j)), 2048)
+			files = append(files, &remoteexecution.OutputFile{
+				Path:   fmt.Sprintf("%d/%d.bin", i, j),
+				Digest: contentAddressableStorage.PutBlob(digestFunction, contents).GetProto(),
+			})
+		}
+	}
+	_, err = d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+		BuildId:    "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		PathPrefix: "bazel-out",
+		Files:      files,
+	})
+	require.NoError(b, err)
+
+	for _, readConcurrency := range []uint32{0, 4} {
+		b.Run(fmt.Sprintf("ReadConcurrency%d", readConcurrency), func(b *testing.B) {
+			server := mock.NewMockOutputPathsExportTarballServer(ctrl)
+			server.EXPECT().Context().Return(ctx).AnyTimes()
+			var bytesSent int64
+			server.EXPECT().Send(gomock.Any()).DoAndReturn(func(response *outputpaths.ExportTarballResponse) error {
+				bytesSent += int64(len(response.Data))
+				return nil
+			}).AnyTimes()
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				require.NoError(b, d.ExportTarball(&outputpaths.ExportTarballRequest{
+					BuildId:         "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+					Path:            "bazel-out",
+					ReadConcurrency: readConcurrency,
+				}, server))
+			}
+			b.SetBytes(bytesSent / int64(b.N))
+		})
+	}
+}
+
+func TestRemoteOutputServiceDirectoryVirtualReadDirConcurrency(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	d := cd_testutil.NewInMemoryRemoteOutputServiceDirectory(cd_testutil.NewFakeContentAddressableStorage())
+	startBuild := func(outputBaseID, buildID string) {
+		_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+			OutputBaseId:     outputBaseID,
+			BuildId:          buildID,
+			DigestFunction:   remoteexecution.DigestFunction_SHA256,
+			OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+		})
+		require.NoError(t, err)
+	}
+	startBuild("9da951b8cb759233037166e28f7ea186", "37f5dbef-b117-4fb6-bce8-5c147cb603b4")
+	startBuild("b3775ae0a1f5feb7f1ff2b2a3a0e8d4c", "a0e4ac65-e5c1-4b23-8b0c-5f1d3d4b6a7e")
+
+	t.Run("ReentrantReporter", func(t *testing.T) {
+		// Entries should be reported without holding the
+		// directory lock, so that reporters may call back into
+		// the directory without causing deadlocks.
+		reporter := mock.NewMockDirectoryEntryReporter(ctrl)
+		var names []string
+		reporter.EXPECT().ReportEntry(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(nextCookie uint64, name path.Component, child re_vfs.DirectoryChild, attributes *re_vfs.Attributes) bool {
+				var rootAttributes re_vfs.Attributes
+				d.VirtualGetAttributes(ctx, re_vfs.AttributesMaskChangeID, &rootAttributes)
+				names = append(names, name.String())
+				return true
+			}).
+			Times(2)
+
+		require.Equal(
+			t,
+			re_vfs.StatusOK,
+			d.VirtualReadDir(ctx, 0, re_vfs.AttributesMaskChangeID, reporter))
+		require.Equal(t, []string{
+			"9da951b8cb759233037166e28f7ea186",
+			"b3775ae0a1f5feb7f1ff2b2a3a0e8d4c",
+		}, names)
+	})
+
+	t.Run("ConcurrentClean", func(t *testing.T) {
+		// Output bases may be created and cleaned while the
+		// directory is being listed. Entries that are removed
+		// while being reported should still be usable.
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for i := 0; i < 100; i++ {
+				outputBaseID := fmt.Sprintf("c%031d", i)
+				if _, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+					OutputBaseId:     outputBaseID,
+					BuildId:          fmt.Sprintf("build-%d", i),
+					DigestFunction:   remoteexecution.DigestFunction_SHA256,
+					OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+				}); err != nil {
+					t.Error(err)
+					return
+				}
+				if _, err := d.Clean(ctx, &remoteoutputservice.CleanRequest{
+					OutputBaseId: outputBaseID,
+				}); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}()
+
+		reporter := mock.NewMockDirectoryEntryReporter(ctrl)
+		reporter.EXPECT().ReportEntry(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(nextCookie uint64, name path.Component, child re_vfs.DirectoryChild, attributes *re_vfs.Attributes) bool {
+				directory, _ := child.GetPair()
+				require.NotNil(t, directory)
+				return true
+			}).
+			AnyTimes()
+	loop:
+		for {
+			select {
+			case <-done:
+				break loop
+			default:
+				require.Equal(
+					t,
+					re_vfs.StatusOK,
+					d.VirtualReadDir(ctx, 0, re_vfs.AttributesMaskChangeID|re_vfs.AttributesMaskLinkCount, reporter))
+			}
+		}
+	})
+}
+
+func TestRemoteOutputServiceDirectoryBatchStatLookupRetry(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	handleAllocator := mock.NewMockStatefulHandleAllocator(ctrl)
+	outputPathFactory := mock.NewMockOutputPathFactory(ctrl)
+	bareContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	retryingContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	directoryFetcher := mock.NewMockDirectoryFetcher(ctrl)
+	symlinkFactory := mock.NewMockSymlinkFactory(ctrl)
+	dHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	handleAllocator.EXPECT().New().Return(dHandleAllocation)
+	dHandle := mock.NewMockStatefulDirectoryHandle(ctrl)
+	dHandleAllocation.EXPECT().AsStatefulDirectory(gomock.Any()).Return(dHandle)
+	now := time.Unix(1000, 0)
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().DoAndReturn(func() time.Time { return now }).AnyTimes()
+	d := newTestRemoteOutputServiceDirectory(
+		handleAllocator,
+		outputPathFactory,
+		bareContentAddressableStorage,
+		retryingContentAddressableStorage,
+		directoryFetcher,
+		symlinkFactory,
+		allowAllAuthorizer,
+		clock,
+		cd_vfs.RemoteOutputServiceDirectoryOptions{
+			MaximumLookupRetryDelay: time.Minute,
+		})
+
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocator := mock.NewMockStatelessHandleAllocator(ctrl)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(casFileHandleAllocator)
+	outputPath := mock.NewMockOutputPath(ctrl)
+	outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+		gomock.Any(),
+		digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256),
+		gomock.Any(),
+	).Return(outputPath)
+	outputPath.EXPECT().FilterChildren(gomock.Any())
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		DigestFunction:   remoteexecution.DigestFunction_SHA256,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+
+	expectSleep := func(interval, advance time.Duration) {
+		timer := mock.NewMockTimer(ctrl)
+		timerChannel := make(chan time.Time, 1)
+		timerChannel <- now
+		clock.EXPECT().NewTimer(interval).DoAndReturn(func(d time.Duration) (*mock.MockTimer, <-chan time.Time) {
+			now = now.Add(advance)
+			return timer, timerChannel
+		})
+	}
+
+	t.Run("NotFound", func(t *testing.T) {
+		// Genuine absence of a file should not be retried.
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("printf.o")).Return(re_vfs.PrepopulatedDirectoryChild{}, syscall.ENOENT)
+
+		response, err := d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Paths:   []string{"printf.o"},
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &remoteoutputservice.BatchStatResponse{
+			Responses: []*remoteoutputservice.StatResponse{{}},
+		}, response)
+	})
+
+	t.Run("NonInfrastructureError", func(t *testing.T) {
+		// Errors that are not transient should not be retried.
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("printf.o")).Return(re_vfs.PrepopulatedDirectoryChild{}, status.Error(codes.InvalidArgument, "Malformed directory"))
+
+		_, err := d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Paths:   []string{"printf.o"},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Failed to resolve path \"printf.o\" beyond \".\": Malformed directory"), err)
+	})
+
+	t.Run("TransientError", func(t *testing.T) {
+		// Lookups that fail transiently should be retried with
+		// exponential backoff until they succeed.
+		leaf := mock.NewMockNativeLeaf(ctrl)
+		gomock.InOrder(
+			outputPath.EXPECT().LookupChild(path.MustNewComponent("printf.o")).Return(re_vfs.PrepopulatedDirectoryChild{}, status.Error(codes.Unavailable, "Server not reachable")),
+			outputPath.EXPECT().LookupChild(path.MustNewComponent("printf.o")).Return(re_vfs.PrepopulatedDirectoryChild{}, status.Error(codes.Unavailable, "Server not reachable")),
+			outputPath.EXPECT().LookupChild(path.MustNewComponent("printf.o")).Return(re_vfs.PrepopulatedDirectoryChild{}.FromLeaf(leaf), nil))
+		expectSleep(100*time.Millisecond, 100*time.Millisecond)
+		expectSleep(200*time.Millisecond, 200*time.Millisecond)
+		leaf.EXPECT().GetOutputServiceFileStatus(nil).Return(&remoteoutputservice.FileStatus{
+			FileType: &remoteoutputservice.FileStatus_File_{
+				File: &remoteoutputservice.FileStatus_File{},
+			},
+		}, nil)
+
+		response, err := d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Paths:   []string{"printf.o"},
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &remoteoutputservice.BatchStatResponse{
+			Responses: []*remoteoutputservice.StatResponse{{
+				FileStatus: &remoteoutputservice.FileStatus{
+					FileType: &remoteoutputservice.FileStatus_File_{
+						File: &remoteoutputservice.FileStatus_File{},
+					},
+				},
+			}},
+		}, response)
+	})
+
+	t.Run("PersistentError", func(t *testing.T) {
+		// Lookups that keep on failing should be reported as
+		// errors once the maximum delay has been exceeded.
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("printf.o")).Return(re_vfs.PrepopulatedDirectoryChild{}, status.Error(codes.Internal, "Disk failure")).Times(2)
+		expectSleep(100*time.Millisecond, 2*time.Minute)
+
+		_, err := d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Paths:   []string{"printf.o"},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Failed to resolve path \"printf.o\" beyond \".\": Lookup failed after 2 attempts: Disk failure"), err)
+	})
+
+	t.Run("PersistentErrorBestEffort", func(t *testing.T) {
+		// In best-effort mode, persistent failures should only
+		// affect the paths in question.
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("printf.o")).Return(re_vfs.PrepopulatedDirectoryChild{}, status.Error(codes.Internal, "Disk failure")).Times(2)
+		expectSleep(100*time.Millisecond, 2*time.Minute)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("scanf.o")).Return(re_vfs.PrepopulatedDirectoryChild{}, syscall.ENOENT)
+
+		response, err := d.ExtendedBatchStat(ctx, &outputpaths.ExtendedBatchStatRequest{
+			Request: &remoteoutputservice.BatchStatRequest{
+				BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+				Paths:   []string{"printf.o", "scanf.o"},
+			},
+			BestEffort: true,
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &outputpaths.ExtendedBatchStatResponse{
+			Responses: []*outputpaths.ExtendedStatResponse{
+				{
+					Error: status.New(codes.Internal, "Failed to resolve path \"printf.o\" beyond \".\": Lookup failed after 2 attempts: Disk failure").Proto(),
+				},
+				{
+					Response: &remoteoutputservice.StatResponse{},
+				},
+			},
+		}, response)
+	})
+
+	t.Run("Cancellation", func(t *testing.T) {
+		// Retries should stop once the client cancels the
+		// request.
+		cancelledCtx, cancel := context.WithCancel(ctx)
+		outputPath.EXPECT().LookupChild(path.MustNewComponent("printf.o")).Return(re_vfs.PrepopulatedDirectoryChild{}, status.Error(codes.Unavailable, "Server not reachable"))
+		timer := mock.NewMockTimer(ctrl)
+		clock.EXPECT().NewTimer(100 * time.Millisecond).DoAndReturn(func(d time.Duration) (*mock.MockTimer, <-chan time.Time) {
+			cancel()
+			return timer, nil
+		})
+		timer.EXPECT().Stop()
+
+		_, err := d.BatchStat(cancelledCtx, &remoteoutputservice.BatchStatRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Paths:   []string{"printf.o"},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.Canceled, "Failed to resolve path \"printf.o\" beyond \".\": context canceled"), err)
+	})
+}
+
+func TestRemoteOutputServiceDirectoryGetOutputPath(t *testing.T) {
+	ctx := context.Background()
+	d := cd_testutil.NewInMemoryRemoteOutputServiceDirectory(cd_testutil.NewFakeContentAddressableStorage())
+
+	t.Run("UnknownBuild", func(t *testing.T) {
+		_, err := d.GetOutputPath(ctx, &outputpaths.GetOutputPathRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.FailedPrecondition, "Build ID is not associated with any running build"), err)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		// The output path should be normalized, and include
+		// the output base ID.
+		_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+			OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+			BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			DigestFunction:   remoteexecution.DigestFunction_SHA256,
+			OutputPathPrefix: "/home/bob//bb_clientd/./outputs/",
+			OutputPathAliases: map[string]string{
+				"/home/bob/.cache/bazel/_bazel_bob/9da951b8cb759233037166e28f7ea186/execroot/myproject/bazel-out": ".",
+			},
+		})
+		require.NoError(t, err)
+
+		response, err := d.GetOutputPath(ctx, &outputpaths.GetOutputPathRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &outputpaths.GetOutputPathResponse{
+			OutputPath:       "/home/bob/bb_clientd/outputs/9da951b8cb759233037166e28f7ea186",
+			OutputPathSuffix: "9da951b8cb759233037166e28f7ea186",
+			OutputPathAliases: map[string]string{
+				"/home/bob/.cache/bazel/_bazel_bob/9da951b8cb759233037166e28f7ea186/execroot/myproject/bazel-out": ".",
+			},
+		}, response)
+	})
+}
+
+func TestRemoteOutputServiceDirectoryBatchCreateSymlinkTargetValidation(t *testing.T) {
+	ctx := context.Background()
+	d := cd_testutil.NewInMemoryRemoteOutputServiceDirectory(cd_testutil.NewFakeContentAddressableStorage())
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		DigestFunction:   remoteexecution.DigestFunction_SHA256,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+
+	t.Run("EmptyTarget", func(t *testing.T) {
+		_, err := d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+			BuildId:    "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			PathPrefix: "bazel-out",
+			Symlinks: []*remoteexecution.OutputSymlink{
+				{Path: "link", Target: ""},
+			},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Symbolic link \"link\" has an empty target"), err)
+
+		// Invalid requests should not leave the path prefix
+		// directory behind.
+		response, err := d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Paths:   []string{"bazel-out"},
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &remoteoutputservice.BatchStatResponse{
+			Responses: []*remoteoutputservice.StatResponse{{}},
+		}, response)
+	})
+
+	t.Run("NullByte", func(t *testing.T) {
+		_, err := d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+			BuildId:    "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			PathPrefix: "bazel-out",
+			Symlinks: []*remoteexecution.OutputSymlink{
+				{Path: "link", Target: "foo\x00bar"},
+			},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Target of symbolic link \"link\" contains a null byte"), err)
+	})
+
+	t.Run("OversizedTarget", func(t *testing.T) {
+		_, err := d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+			BuildId:    "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			PathPrefix: "bazel-out",
+			Symlinks: []*remoteexecution.OutputSymlink{
+				{Path: "link", Target: strings.Repeat("a/", 2049)},
+			},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Target of symbolic link \"link\" is 4098 bytes in size, which exceeds the permitted maximum of 4096 bytes"), err)
+	})
+
+	t.Run("BestEffort", func(t *testing.T) {
+		// Invalid targets should only cause the entries in
+		// question to fail.
+		response, err := d.BestEffortBatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+			BuildId:    "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			PathPrefix: "bazel-out",
+			Symlinks: []*remoteexecution.OutputSymlink{
+				{Path: "empty", Target: ""},
+				{Path: "valid", Target: strings.Repeat("a", 4096)},
+			},
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &outputpaths.BestEffortBatchCreateResponse{
+			Files:       []*status_pb.Status{},
+			Directories: []*status_pb.Status{},
+			Symlinks: []*status_pb.Status{
+				status.New(codes.InvalidArgument, "Symbolic link \"empty\" has an empty target").Proto(),
+				status.New(codes.OK, "").Proto(),
+			},
+		}, response)
+	})
+}
+
+func TestRemoteOutputServiceDirectoryVerifyTree(t *testing.T) {
+	ctx := context.Background()
+	contentAddressableStorage := cd_testutil.NewFakeContentAddressableStorage()
+	d := cd_testutil.NewInMemoryRemoteOutputServiceDirectory(contentAddressableStorage)
+	digestFunction := digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256)
+
+	presentFileDigest := contentAddressableStorage.PutBlob(digestFunction, []byte("Hello"))
+	missingFileDigest := digest.MustNewDigest("", remoteexecution.DigestFunction_SHA256, "8b1a9953c4611296a827abf8c47804d7e6c49c6b1f3c5f5e5a7e2d3c4b5a6978", 5)
+	missingDirectoryDigest := digest.MustNewDigest("", remoteexecution.DigestFunction_SHA256, "f8df3ae5fea2c0d2a5d4e6a8a1f5c5d1a5e4b8f2e1b6c7a8d9e0f1a2b3c4d5e6", 42)
+
+	childDirectory := &remoteexecution.Directory{
+		Files: []*remoteexecution.FileNode{
+			{Name: "missing", Digest: missingFileDigest.GetProto()},
+		},
+	}
+	childDirectoryData, err := proto.Marshal(childDirectory)
+	require.NoError(t, err)
+	childDirectoryDigest := contentAddressableStorage.PutBlob(digestFunction, childDirectoryData)
+
+	treeData, err := proto.Marshal(&remoteexecution.Tree{
+		Root: &remoteexecution.Directory{
+			Files: []*remoteexecution.FileNode{
+				{Name: "missing", Digest: missingFileDigest.GetProto()},
+				{Name: "present", Digest: presentFileDigest.GetProto()},
+			},
+			Directories: []*remoteexecution.DirectoryNode{
+				{Name: "child", Digest: childDirectoryDigest.GetProto()},
+				{Name: "missing", Digest: missingDirectoryDigest.GetProto()},
+			},
+		},
+		Children: []*remoteexecution.Directory{childDirectory},
+	})
+	require.NoError(t, err)
+	treeDigest := contentAddressableStorage.PutBlob(digestFunction, treeData)
+
+	t.Run("InvalidTreeDigest", func(t *testing.T) {
+		_, err := d.VerifyTree(ctx, &outputpaths.VerifyTreeRequest{
+			DigestFunction: remoteexecution.DigestFunction_SHA256,
+			TreeDigest: &remoteexecution.Digest{
+				Hash:      "0123",
+				SizeBytes: 123,
+			},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Invalid tree digest: Hash has length 4, while 64 characters were expected"), err)
+	})
+
+	t.Run("TreeNotFound", func(t *testing.T) {
+		_, err := d.VerifyTree(ctx, &outputpaths.VerifyTreeRequest{
+			DigestFunction: remoteexecution.DigestFunction_SHA256,
+			TreeDigest:     missingDirectoryDigest.GetProto(),
+		})
+		require.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		// Files referenced multiple times should only be
+		// reported once. Directories not contained in the Tree
+		// should be reported as missing.
+		response, err := d.VerifyTree(ctx, &outputpaths.VerifyTreeRequest{
+			DigestFunction: remoteexecution.DigestFunction_SHA256,
+			TreeDigest:     treeDigest.GetProto(),
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &outputpaths.VerifyTreeResponse{
+			MissingDirectoryDigests: []*remoteexecution.Digest{missingDirectoryDigest.GetProto()},
+			MissingFileDigests:      []*remoteexecution.Digest{missingFileDigest.GetProto()},
+		}, response)
+	})
+}
+
+func TestRemoteOutputServiceDirectorySetBuildMetadata(t *testing.T) {
+	ctx := context.Background()
+	d := cd_testutil.NewInMemoryRemoteOutputServiceDirectory(cd_testutil.NewFakeContentAddressableStorage())
+
+	t.Run("EmptyKey", func(t *testing.T) {
+		_, err := d.SetBuildMetadata(ctx, &outputpaths.SetBuildMetadataRequest{
+			BuildId:  "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Metadata: map[string]string{"": "value"},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Build metadata contains an empty key"), err)
+	})
+
+	t.Run("TooLarge", func(t *testing.T) {
+		_, err := d.SetBuildMetadata(ctx, &outputpaths.SetBuildMetadataRequest{
+			BuildId:  "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Metadata: map[string]string{"key": strings.Repeat("x", 4094)},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Build metadata is 4097 bytes in size, which exceeds the permitted maximum of 4096 bytes"), err)
+	})
+
+	t.Run("InvalidBuildID", func(t *testing.T) {
+		_, err := d.SetBuildMetadata(ctx, &outputpaths.SetBuildMetadataRequest{
+			BuildId:  "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Metadata: map[string]string{"invocation_id": "1234"},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.FailedPrecondition, "Build ID is not associated with any running build"), err)
+	})
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		DigestFunction:   remoteexecution.DigestFunction_SHA256,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+
+	t.Run("Success", func(t *testing.T) {
+		// Metadata should be returned by GetActiveBuild(). Each
+		// call replaces the metadata set previously.
+		getClientMetadata := func() map[string]string {
+			response, err := d.GetActiveBuild(ctx, &outputpaths.GetActiveBuildRequest{
+				OutputBaseId: "9da951b8cb759233037166e28f7ea186",
+			})
+			require.NoError(t, err)
+			return response.ActiveBuild.ClientMetadata
+		}
+		require.Empty(t, getClientMetadata())
+
+		_, err := d.SetBuildMetadata(ctx, &outputpaths.SetBuildMetadataRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Metadata: map[string]string{
+				"ci_job_id":     "5678",
+				"invocation_id": "1234",
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{
+			"ci_job_id":     "5678",
+			"invocation_id": "1234",
+		}, getClientMetadata())
+
+		_, err = d.SetBuildMetadata(ctx, &outputpaths.SetBuildMetadataRequest{
+			BuildId:  "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Metadata: map[string]string{"invocation_id": "4321"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"invocation_id": "4321"}, getClientMetadata())
+	})
+}
+
+func TestRemoteOutputServiceDirectorySyscallErrorMapping(t *testing.T) {
+	ctx := context.Background()
+	contentAddressableStorage := cd_testutil.NewFakeContentAddressableStorage()
+	d := cd_testutil.NewInMemoryRemoteOutputServiceDirectory(contentAddressableStorage)
+	digestFunction := digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256)
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		DigestFunction:   remoteexecution.DigestFunction_SHA256,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+	_, err = d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+		BuildId:    "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		PathPrefix: "bazel-out",
+		Files: []*remoteexecution.OutputFile{
+			{
+				Path:   "file",
+				Digest: contentAddressableStorage.PutBlob(digestFunction, []byte("Hello")).GetProto(),
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	t.Run("BatchStat", func(t *testing.T) {
+		// Traversing into a file should yield ENOTDIR, which
+		// should be reported as FAILED_PRECONDITION, as opposed
+		// to UNKNOWN.
+		_, err := d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Paths:   []string{"bazel-out/file/child"},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.FailedPrecondition, "Failed to resolve path \"bazel-out/file/child\" beyond \"bazel-out/\": not a directory"), err)
+	})
+}
+
+func TestRemoteOutputServiceDirectoryResolveOutputPath(t *testing.T) {
+	ctx := context.Background()
+	d := cd_testutil.NewInMemoryRemoteOutputServiceDirectory(cd_testutil.NewFakeContentAddressableStorage())
+
+	t.Run("InvalidOutputPathPrefix", func(t *testing.T) {
+		_, err := d.ResolveOutputPath(ctx, &outputpaths.ResolveOutputPathRequest{
+			OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+			OutputPathPrefix: "relative/path",
+		})
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("ReservedOutputBaseID", func(t *testing.T) {
+		_, err := d.ResolveOutputPath(ctx, &outputpaths.ResolveOutputPathRequest{
+			OutputBaseId:     ".history",
+			OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Output base ID may not start with reserved name \".history\""), err)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		response, err := d.ResolveOutputPath(ctx, &outputpaths.ResolveOutputPathRequest{
+			OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+			OutputPathPrefix: "/home/bob/bb_clientd/outputs/",
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &outputpaths.ResolveOutputPathResponse{
+			OutputPathSuffix: "9da951b8cb759233037166e28f7ea186",
+			OutputPath:       "/home/bob/bb_clientd/outputs/9da951b8cb759233037166e28f7ea186",
+		}, response)
+
+		// No output path should have been created.
+		_, err = d.GetActiveBuild(ctx, &outputpaths.GetActiveBuildRequest{
+			OutputBaseId: "9da951b8cb759233037166e28f7ea186",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.NotFound, "Output base ID is not associated with any output path"), err)
+	})
+}
+
+func TestRemoteOutputServiceDirectoryBoundDirectories(t *testing.T) {
+	ctx := context.Background()
+
+	contentAddressableStorage := cd_testutil.NewFakeContentAddressableStorage()
+	digestFunction := digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256)
+	fileDigest := contentAddressableStorage.PutBlob(digestFunction, []byte("#!/bin/sh\n"))
+	treeData, err := proto.Marshal(&remoteexecution.Tree{
+		Root: &remoteexecution.Directory{
+			Files: []*remoteexecution.FileNode{
+				{Name: "cc", Digest: fileDigest.GetProto(), IsExecutable: true},
+			},
+		},
+	})
+	require.NoError(t, err)
+	treeDigest := contentAddressableStorage.PutBlob(digestFunction, treeData)
+
+	handleAllocator := re_vfs.NewFUSEHandleAllocator(random.FastThreadSafeGenerator)
+	symlinkFactory := re_vfs.NewHandleAllocatingSymlinkFactory(
+		re_vfs.BaseSymlinkFactory,
+		handleAllocator.New())
+	d := newTestRemoteOutputServiceDirectory(
+		handleAllocator,
+		cd_vfs.NewInMemoryOutputPathFactory(re_filesystem.InMemoryFilePool, symlinkFactory, handleAllocator, sort.Sort, clock.SystemClock),
+		contentAddressableStorage,
+		contentAddressableStorage,
+		re_cas.NewBlobAccessDirectoryFetcher(
+			contentAddressableStorage,
+			/* maximumDirectorySizeBytes = */ 10000,
+			/* maximumTreeSizeBytes = */ 10000),
+		symlinkFactory,
+		allowAllAuthorizer,
+		clock.SystemClock,
+		cd_vfs.RemoteOutputServiceDirectoryOptions{
+			BoundDirectories: map[path.Component]*remoteexecution.Digest{
+				path.MustNewComponent("toolchain"): treeDigest.GetProto(),
+			},
+		})
+
+	startBuild := func(buildID string) {
+		_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+			OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+			BuildId:          buildID,
+			DigestFunction:   remoteexecution.DigestFunction_SHA256,
+			OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+		})
+		require.NoError(t, err)
+	}
+	startBuild("37f5dbef-b117-4fb6-bce8-5c147cb603b4")
+
+	t.Run("BatchStat", func(t *testing.T) {
+		// Paths inside the bound directory should be reported
+		// as being part of the output path.
+		response, err := d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
+			BuildId:           "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			IncludeFileDigest: true,
+			Paths:             []string{"toolchain/cc"},
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &remoteoutputservice.BatchStatResponse{
+			Responses: []*remoteoutputservice.StatResponse{
+				{
+					FileStatus: &remoteoutputservice.FileStatus{
+						FileType: &remoteoutputservice.FileStatus_File_{
+							File: &remoteoutputservice.FileStatus_File{
+								Digest: fileDigest.GetProto(),
+							},
+						},
+					},
+				},
+			},
+		}, response)
+	})
+
+	t.Run("BatchCreate", func(t *testing.T) {
+		_, err := d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+			BuildId:    "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			PathPrefix: "toolchain",
+			Files: []*remoteexecution.OutputFile{
+				{Path: "ld", Digest: fileDigest.GetProto()},
+			},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.FailedPrecondition, "Invalid path for file \"ld\": Path \"toolchain/ld\" is part of read-only bound directory \"toolchain\""), err)
+	})
+
+	t.Run("RecreatedOnStartBuild", func(t *testing.T) {
+		// Modifications made through the virtual file system
+		// should be discarded when the next build starts.
+		_, err := d.FinalizeBuild(ctx, &remoteoutputservice.FinalizeBuildRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		})
+		require.NoError(t, err)
+		child, s := d.VirtualLookup(ctx, path.MustNewComponent("9da951b8cb759233037166e28f7ea186"), 0, &re_vfs.Attributes{})
+		require.Equal(t, re_vfs.StatusOK, s)
+		outputPath, _ := child.GetPair()
+		child, s = outputPath.VirtualLookup(ctx, path.MustNewComponent("toolchain"), 0, &re_vfs.Attributes{})
+		require.Equal(t, re_vfs.StatusOK, s)
+		toolchain, _ := child.GetPair()
+		_, s = toolchain.VirtualRemove(path.MustNewComponent("cc"), false, true)
+		require.Equal(t, re_vfs.StatusOK, s)
+
+		startBuild("b4e6ec5c-8d9a-4f8d-a3f4-2f3c1e5d6a7b")
+		response, err := d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
+			BuildId: "b4e6ec5c-8d9a-4f8d-a3f4-2f3c1e5d6a7b",
+			Paths:   []string{"toolchain/cc"},
+		})
+		require.NoError(t, err)
+		require.NotNil(t, response.Responses[0].FileStatus.GetFile())
+	})
+}
+
+func TestRemoteOutputServiceDirectoryExtendedBatchCreate(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	handleAllocator := mock.NewMockStatefulHandleAllocator(ctrl)
+	outputPathFactory := mock.NewMockOutputPathFactory(ctrl)
+	bareContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	retryingContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	directoryFetcher := mock.NewMockDirectoryFetcher(ctrl)
+	symlinkFactory := mock.NewMockSymlinkFactory(ctrl)
+	dHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	handleAllocator.EXPECT().New().Return(dHandleAllocation)
+	dHandle := mock.NewMockStatefulDirectoryHandle(ctrl)
+	dHandleAllocation.EXPECT().AsStatefulDirectory(gomock.Any()).Return(dHandle)
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(1000, 0)).AnyTimes()
+	d := newTestRemoteOutputServiceDirectory(
+		handleAllocator,
+		outputPathFactory,
+		bareContentAddressableStorage,
+		retryingContentAddressableStorage,
+		directoryFetcher,
+		symlinkFactory,
+		allowAllAuthorizer,
+		clock,
+		cd_vfs.RemoteOutputServiceDirectoryOptions{
+			FindMissingBlobsBatchSize: 1,
+		})
+
+	t.Run("NoRequest", func(t *testing.T) {
+		_, err := d.ExtendedBatchCreate(ctx, &outputpaths.ExtendedBatchCreateRequest{})
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "No BatchCreate() request provided"), err)
+	})
+
+	// Let the remainder of the tests assume that a build is running.
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocator := mock.NewMockStatelessHandleAllocator(ctrl)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(casFileHandleAllocator)
+	outputPath := mock.NewMockOutputPath(ctrl)
+	outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("c6adef0d5ca1888a4aa847fb51229a8c"),
+		gomock.Any(),
+		digest.MustNewFunction("my-cluster", remoteexecution.DigestFunction_MD5),
+		gomock.Any(),
+	).Return(outputPath)
+	outputPath.EXPECT().FilterChildren(gomock.Any())
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "c6adef0d5ca1888a4aa847fb51229a8c",
+		BuildId:          "ad778a53-48e6-4ae1-b1f5-01b84a508f5f",
+		InstanceName:     "my-cluster",
+		DigestFunction:   remoteexecution.DigestFunction_MD5,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+
+	treeDigest1 := digest.MustNewDigest("my-cluster", remoteexecution.DigestFunction_MD5, "b2bc8901bd2dfc25e0e43f0a1eaf8758", 123)
+	treeDigest2 := digest.MustNewDigest("my-cluster", remoteexecution.DigestFunction_MD5, "72e93c68cd8c3fac1bbc3fbb4e4e3a2c", 456)
+	request := &outputpaths.ExtendedBatchCreateRequest{
+		Request: &remoteoutputservice.BatchCreateRequest{
+			BuildId: "ad778a53-48e6-4ae1-b1f5-01b84a508f5f",
+			Directories: []*remoteexecution.OutputDirectory{
+				{
+					Path:       "directory1",
+					TreeDigest: treeDigest1.GetProto(),
+				},
+				{
+					Path:       "directory2",
+					TreeDigest: treeDigest2.GetProto(),
+				},
+			},
+		},
+		ValidateTreeDigests: true,
+	}
+
+	t.Run("FindMissingFailure", func(t *testing.T) {
+		bareContentAddressableStorage.EXPECT().FindMissing(ctx, treeDigest1.ToSingletonSet()).
+			Return(digest.EmptySet, status.Error(codes.Unavailable, "Server offline"))
+
+		_, err := d.ExtendedBatchCreate(ctx, request)
+		testutil.RequireEqualStatus(t, status.Error(codes.Unavailable, "Failed to find missing blobs: Server offline"), err)
+	})
+
+	t.Run("MissingTree", func(t *testing.T) {
+		// If one of the Tree objects is absent, none of the
+		// entries should be created. The status of every
+		// directory should be reported instead. Digests should
+		// be checked in batches.
+		bareContentAddressableStorage.EXPECT().FindMissing(ctx, treeDigest1.ToSingletonSet()).
+			Return(digest.EmptySet, nil)
+		bareContentAddressableStorage.EXPECT().FindMissing(ctx, treeDigest2.ToSingletonSet()).
+			Return(treeDigest2.ToSingletonSet(), nil)
+
+		response, err := d.ExtendedBatchCreate(ctx, request)
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &outputpaths.ExtendedBatchCreateResponse{
+			Directories: []*status_pb.Status{
+				{},
+				status.New(codes.NotFound, "Tree for directory \"directory2\" with digest \"3-72e93c68cd8c3fac1bbc3fbb4e4e3a2c-456-my-cluster\" is not present in the Content Addressable Storage").Proto(),
+			},
+		}, response)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		// If all Tree objects are present, the directories
+		// should be created as usual.
+		bareContentAddressableStorage.EXPECT().FindMissing(ctx, treeDigest1.ToSingletonSet()).
+			Return(digest.EmptySet, nil)
+		bareContentAddressableStorage.EXPECT().FindMissing(ctx, treeDigest2.ToSingletonSet()).
+			Return(digest.EmptySet, nil)
+		outputPath.EXPECT().CreateChildren(gomock.Any(), true).Times(2)
+
+		response, err := d.ExtendedBatchCreate(ctx, request)
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &outputpaths.ExtendedBatchCreateResponse{}, response)
+	})
+
+	t.Run("ValidationDisabled", func(t *testing.T) {
+		// Without validation, no calls to FindMissing() should
+		// be made.
+		outputPath.EXPECT().CreateChildren(gomock.Any(), true).Times(2)
+
+		response, err := d.ExtendedBatchCreate(ctx, &outputpaths.ExtendedBatchCreateRequest{
+			Request: request.Request,
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &outputpaths.ExtendedBatchCreateResponse{}, response)
+	})
+
+	t.Run("InvalidAllowedScope", func(t *testing.T) {
+		_, err := d.ExtendedBatchCreate(ctx, &outputpaths.ExtendedBatchCreateRequest{
+			Request:      request.Request,
+			AllowedScope: "../directory1",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Invalid allowed scope \"../directory1\": Path resolves to a location outside the output path"), err)
+	})
+
+	t.Run("PathPrefixOutsideAllowedScope", func(t *testing.T) {
+		// The path prefix must either be located inside the
+		// allowed scope, or contain it.
+		_, err := d.ExtendedBatchCreate(ctx, &outputpaths.ExtendedBatchCreateRequest{
+			Request: &remoteoutputservice.BatchCreateRequest{
+				BuildId:    "ad778a53-48e6-4ae1-b1f5-01b84a508f5f",
+				PathPrefix: "foo",
+			},
+			AllowedScope: "bar",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Path prefix \"foo\" neither is located inside allowed scope \"bar\", nor contains it"), err)
+	})
+
+	t.Run("CleanPathPrefixOutsideAllowedScope", func(t *testing.T) {
+		// Cleaning a path prefix that contains the allowed
+		// scope would remove entries outside of it.
+		_, err := d.ExtendedBatchCreate(ctx, &outputpaths.ExtendedBatchCreateRequest{
+			Request: &remoteoutputservice.BatchCreateRequest{
+				BuildId:         "ad778a53-48e6-4ae1-b1f5-01b84a508f5f",
+				PathPrefix:      "foo",
+				CleanPathPrefix: true,
+			},
+			AllowedScope: "foo/bar",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.PermissionDenied, "Cleaning path prefix \"foo\" would remove entries outside allowed scope \"foo/bar\""), err)
+	})
+
+	t.Run("EntryOutsideAllowedScope", func(t *testing.T) {
+		// Entries are checked after resolving ".." components,
+		// and fail without creating any other entries.
+		_, err := d.ExtendedBatchCreate(ctx, &outputpaths.ExtendedBatchCreateRequest{
+			Request: &remoteoutputservice.BatchCreateRequest{
+				BuildId: "ad778a53-48e6-4ae1-b1f5-01b84a508f5f",
+				Directories: []*remoteexecution.OutputDirectory{
+					{
+						Path:       "directory1",
+						TreeDigest: treeDigest1.GetProto(),
+					},
+					{
+						Path:       "directory1/../directory2",
+						TreeDigest: treeDigest2.GetProto(),
+					},
+				},
+			},
+			AllowedScope: "directory1",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.PermissionDenied, "Invalid path for directory \"directory1/../directory2\": Path resolves to \"directory2\", which is outside allowed scope \"directory1\""), err)
+	})
+
+	t.Run("EntryInsideAllowedScope", func(t *testing.T) {
+		outputPath.EXPECT().CreateChildren(gomock.Any(), true)
+
+		response, err := d.ExtendedBatchCreate(ctx, &outputpaths.ExtendedBatchCreateRequest{
+			Request: &remoteoutputservice.BatchCreateRequest{
+				BuildId: "ad778a53-48e6-4ae1-b1f5-01b84a508f5f",
+				Directories: []*remoteexecution.OutputDirectory{
+					{
+						Path:       "directory1",
+						TreeDigest: treeDigest1.GetProto(),
+					},
+				},
+			},
+			AllowedScope: "directory1",
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &outputpaths.ExtendedBatchCreateResponse{}, response)
+	})
+}
+
+func TestRemoteOutputServiceDirectoryBatchStatMaximumResponseSize(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	handleAllocator := mock.NewMockStatefulHandleAllocator(ctrl)
+	outputPathFactory := mock.NewMockOutputPathFactory(ctrl)
+	bareContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	retryingContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	directoryFetcher := mock.NewMockDirectoryFetcher(ctrl)
+	symlinkFactory := mock.NewMockSymlinkFactory(ctrl)
+	dHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	handleAllocator.EXPECT().New().Return(dHandleAllocation)
+	dHandle := mock.NewMockStatefulDirectoryHandle(ctrl)
+	dHandleAllocation.EXPECT().AsStatefulDirectory(gomock.Any()).Return(dHandle)
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(1000, 0)).AnyTimes()
+	d := newTestRemoteOutputServiceDirectory(
+		handleAllocator,
+		outputPathFactory,
+		bareContentAddressableStorage,
+		retryingContentAddressableStorage,
+		directoryFetcher,
+		symlinkFactory,
+		allowAllAuthorizer,
+		clock,
+		cd_vfs.RemoteOutputServiceDirectoryOptions{
+			MaximumBatchStatResponseSizeBytes: 5,
+		})
+
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocator := mock.NewMockStatelessHandleAllocator(ctrl)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(casFileHandleAllocator)
+	outputPath := mock.NewMockOutputPath(ctrl)
+	outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("9da951b8cb759233037166e28f7ea186"),
+		gomock.Any(),
+		digest.MustNewFunction("my-cluster", remoteexecution.DigestFunction_MD5),
+		gomock.Any(),
+	).Return(outputPath)
+	outputPath.EXPECT().FilterChildren(gomock.Any())
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		InstanceName:     "my-cluster",
+		DigestFunction:   remoteexecution.DigestFunction_MD5,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+
+	// The status of a non-existent path is encoded as an empty
+	// message, taking up two bytes in the response.
+	outputPath.EXPECT().LookupChild(path.MustNewComponent("nonexistent")).
+		Return(re_vfs.PrepopulatedDirectoryChild{}, syscall.ENOENT).
+		AnyTimes()
+
+	t.Run("WithinLimit", func(t *testing.T) {
+		response, err := d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Paths:   []string{"nonexistent", "nonexistent"},
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &remoteoutputservice.BatchStatResponse{
+			Responses: []*remoteoutputservice.StatResponse{{}, {}},
+		}, response)
+	})
+
+	t.Run("LimitExceeded", func(t *testing.T) {
+		// The error should report the number of paths that
+		// fit, so that the client can split the request.
+		_, err := d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Paths:   []string{"nonexistent", "nonexistent", "nonexistent"},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.ResourceExhausted, "Response would exceed the maximum size of 5 bytes, as only the status of the first 2 of 3 paths fits. Either split the request, or call StatStream()"), err)
+	})
+}
+
+func TestRemoteOutputServiceDirectoryBatchCreateFailureLogging(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	handleAllocator := mock.NewMockStatefulHandleAllocator(ctrl)
+	outputPathFactory := mock.NewMockOutputPathFactory(ctrl)
+	bareContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	retryingContentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	directoryFetcher := mock.NewMockDirectoryFetcher(ctrl)
+	symlinkFactory := mock.NewMockSymlinkFactory(ctrl)
+	dHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	handleAllocator.EXPECT().New().Return(dHandleAllocation)
+	dHandle := mock.NewMockStatefulDirectoryHandle(ctrl)
+	dHandleAllocation.EXPECT().AsStatefulDirectory(gomock.Any()).Return(dHandle)
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(1000, 0)).AnyTimes()
+	errorLogger := mock.NewMockErrorLogger(ctrl)
+	d := newTestRemoteOutputServiceDirectory(
+		handleAllocator,
+		outputPathFactory,
+		bareContentAddressableStorage,
+		retryingContentAddressableStorage,
+		directoryFetcher,
+		symlinkFactory,
+		allowAllAuthorizer,
+		clock,
+		cd_vfs.RemoteOutputServiceDirectoryOptions{
+			BatchCreateFailureLogger:   errorLogger,
+			LogBatchCreateFailurePaths: true,
+		})
+
+	// Let the remainder of the tests assume that a build is running.
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocator := mock.NewMockStatelessHandleAllocator(ctrl)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(casFileHandleAllocator)
+	outputPath := mock.NewMockOutputPath(ctrl)
+	outputPathFactory.EXPECT().StartInitialBuild(
+		path.MustNewComponent("c6adef0d5ca1888a4aa847fb51229a8c"),
+		gomock.Any(),
+		digest.MustNewFunction("my-cluster", remoteexecution.DigestFunction_MD5),
+		gomock.Any(),
+	).Return(outputPath)
+	outputPath.EXPECT().FilterChildren(gomock.Any())
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "c6adef0d5ca1888a4aa847fb51229a8c",
+		BuildId:          "ad778a53-48e6-4ae1-b1f5-01b84a508f5f",
+		InstanceName:     "my-cluster",
+		DigestFunction:   remoteexecution.DigestFunction_MD5,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+
+	t.Run("Success", func(t *testing.T) {
+		// Successful requests should not be logged.
+		_, err := d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+			BuildId:    "ad778a53-48e6-4ae1-b1f5-01b84a508f5f",
+			PathPrefix: "a",
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		// Failures should be logged, together with the paths
+		// of all entries in the request.
+		errorLogger.EXPECT().Log(testutil.EqStatus(t, status.Error(codes.InvalidArgument, "BatchCreate() for build \"ad778a53-48e6-4ae1-b1f5-01b84a508f5f\" with path prefix \"a\", 1 files, 1 directories and 0 symbolic links (files [\"../../etc/passwd\"], directories [\"directory\"], symbolic links []) failed: Invalid path for file \"../../etc/passwd\": Path resolves to a location outside the output path")))
+
+		_, err := d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+			BuildId:    "ad778a53-48e6-4ae1-b1f5-01b84a508f5f",
+			PathPrefix: "a",
+			Files: []*remoteexecution.OutputFile{
+				{
+					Path: "../../etc/passwd",
+					Digest: &remoteexecution.Digest{
+						Hash:      "d0ab620af7f3e77f3adfa190d41a25ce",
+						SizeBytes: 123,
+					},
+				},
+			},
+			Directories: []*remoteexecution.OutputDirectory{
 				{
-					FileStatus: &remoteoutputservice.FileStatus{
-						FileType: &remoteoutputservice.FileStatus_Directory_{
-							Directory: &remoteoutputservice.FileStatus_Directory{
-								LastModifiedTime: &timestamppb.Timestamp{Seconds: 1004},
-							},
-						},
+					Path: "directory",
+					TreeDigest: &remoteexecution.Digest{
+						Hash:      "b2bc8901bd2dfc25e0e43f0a1eaf8758",
+						SizeBytes: 123,
 					},
 				},
 			},
-		}, response)
+		})
+		require.Error(t, err)
 	})
 }
 
-func TestRemoteOutputServiceDirectoryVirtualLookup(t *testing.T) {
+func TestRemoteOutputServiceDirectoryCleanAndStartBuild(t *testing.T) {
 	ctrl, ctx := gomock.WithContext(context.Background(), t)
 
 	handleAllocator := mock.NewMockStatefulHandleAllocator(ctrl)
@@ -1112,79 +10046,100 @@ func TestRemoteOutputServiceDirectoryVirtualLookup(t *testing.T) {
 	handleAllocator.EXPECT().New().Return(dHandleAllocation)
 	dHandle := mock.NewMockStatefulDirectoryHandle(ctrl)
 	dHandleAllocation.EXPECT().AsStatefulDirectory(gomock.Any()).Return(dHandle)
-	d := cd_vfs.NewRemoteOutputServiceDirectory(
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(1000, 0)).AnyTimes()
+	d := newTestRemoteOutputServiceDirectory(
 		handleAllocator,
 		outputPathFactory,
 		bareContentAddressableStorage,
 		retryingContentAddressableStorage,
 		directoryFetcher,
 		symlinkFactory,
-		/* maximumMessageSizeBytes = */ 10000)
+		allowAllAuthorizer,
+		clock,
+		cd_vfs.RemoteOutputServiceDirectoryOptions{})
 
-	// No output paths exist, so VirtualLookup() should always fail.
-	var out1 re_vfs.Attributes
-	_, s := d.VirtualLookup(ctx, path.MustNewComponent("eda09135e50ff6e877fe5f8136ddc759"), 0, &out1)
-	require.Equal(t, re_vfs.StatusErrNoEnt, s)
+	t.Run("InitialBuild", func(t *testing.T) {
+		// Calling CleanAndStartBuild() against an output base
+		// that doesn't exist yet should create an empty output
+		// path.
+		casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+		handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+		casFileHandleAllocator := mock.NewMockStatelessHandleAllocator(ctrl)
+		casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(casFileHandleAllocator)
+		outputPath := mock.NewMockOutputPath(ctrl)
+		outputPathFactory.EXPECT().StartInitialBuild(
+			path.MustNewComponent("c6adef0d5ca1888a4aa847fb51229a8c"),
+			gomock.Any(),
+			digest.MustNewFunction("my-cluster", remoteexecution.DigestFunction_MD5),
+			gomock.Any(),
+		).Return(outputPath)
+		outputPath.EXPECT().RemoveAllChildren(true)
+		outputPath.EXPECT().FilterChildren(gomock.Any())
 
-	// Create an output path.
-	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
-	handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
-	casFileHandleAllocator := mock.NewMockStatelessHandleAllocator(ctrl)
-	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(casFileHandleAllocator)
-	outputPath := mock.NewMockOutputPath(ctrl)
-	outputPathFactory.EXPECT().StartInitialBuild(
-		path.MustNewComponent("eaf1d65b7ab802934e6b57d0e14b3f30"),
-		gomock.Any(),
-		digest.MustNewFunction("", remoteexecution.DigestFunction_MD5),
-		gomock.Any(),
-	).Return(outputPath)
-	outputPath.EXPECT().FilterChildren(gomock.Any())
+		response, err := d.CleanAndStartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+			OutputBaseId:     "c6adef0d5ca1888a4aa847fb51229a8c",
+			BuildId:          "ad778a53-48e6-4ae1-b1f5-01b84a508f5f",
+			InstanceName:     "my-cluster",
+			DigestFunction:   remoteexecution.DigestFunction_MD5,
+			OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &remoteoutputservice.StartBuildResponse{
+			OutputPathSuffix: "c6adef0d5ca1888a4aa847fb51229a8c",
+		}, response)
 
-	response, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
-		OutputBaseId:     "eaf1d65b7ab802934e6b57d0e14b3f30",
-		BuildId:          "2840d789-16ff-4fe4-9639-3245f9bb9106",
-		DigestFunction:   remoteexecution.DigestFunction_MD5,
-		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
-		OutputPathAliases: map[string]string{
-			"/home/bob/.cache/bazel/_bazel_bob/eaf1d65b7ab802934e6b57d0e14b3f30/execroot/myproject/bazel-out": ".",
-		},
-	})
-	require.NoError(t, err)
-	testutil.RequireEqualProto(t, &remoteoutputservice.StartBuildResponse{
-		OutputPathSuffix: "eaf1d65b7ab802934e6b57d0e14b3f30",
-	}, response)
+		t.Run("Reattach", func(t *testing.T) {
+			// Reattaching to a running build would cause
+			// its outputs to be removed.
+			_, err := d.CleanAndStartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+				OutputBaseId:     "c6adef0d5ca1888a4aa847fb51229a8c",
+				BuildId:          "ad778a53-48e6-4ae1-b1f5-01b84a508f5f",
+				InstanceName:     "my-cluster",
+				DigestFunction:   remoteexecution.DigestFunction_MD5,
+				OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+			})
+			testutil.RequireEqualStatus(t, status.Error(codes.FailedPrecondition, "Build ID is already in use, and the outputs of running builds cannot be cleaned"), err)
+		})
 
-	outputPath.EXPECT().VirtualGetAttributes(
-		ctx,
-		re_vfs.AttributesMaskInodeNumber,
-		gomock.Any(),
-	).Do(func(ctx context.Context, requested re_vfs.AttributesMask, out *re_vfs.Attributes) {
-		out.SetInodeNumber(101)
-	})
+		t.Run("SuccessiveBuild", func(t *testing.T) {
+			// Successive builds should reuse the existing
+			// output path, removing its contents.
+			outputPath.EXPECT().RemoveAllChildren(true)
+			outputPath.EXPECT().FilterChildren(gomock.Any())
 
-	// Call VirtualLookup() again. It should now succeed.
-	var out2 re_vfs.Attributes
-	child, s := d.VirtualLookup(ctx, path.MustNewComponent("eaf1d65b7ab802934e6b57d0e14b3f30"), re_vfs.AttributesMaskInodeNumber, &out2)
-	require.Equal(t, re_vfs.StatusOK, s)
-	require.Equal(t, re_vfs.DirectoryChild{}.FromDirectory(outputPath), child)
-	require.Equal(t, *(&re_vfs.Attributes{}).SetInodeNumber(101), out2)
+			_, err := d.CleanAndStartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+				OutputBaseId:     "c6adef0d5ca1888a4aa847fb51229a8c",
+				BuildId:          "0a1c3c8e-0a33-4a46-9d6b-8c6bd6a4c5f0",
+				InstanceName:     "my-cluster",
+				DigestFunction:   remoteexecution.DigestFunction_MD5,
+				OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+			})
+			require.NoError(t, err)
 
-	// Remove the output path.
-	outputPath.EXPECT().RemoveAllChildren(true)
-	dHandle.EXPECT().NotifyRemoval(path.MustNewComponent("eaf1d65b7ab802934e6b57d0e14b3f30"))
+			// The previous build should have been displaced.
+			_, err = d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
+				BuildId: "ad778a53-48e6-4ae1-b1f5-01b84a508f5f",
+			})
+			testutil.RequireEqualStatus(t, status.Error(codes.FailedPrecondition, "Build ID is not associated with any running build"), err)
+		})
 
-	_, err = d.Clean(ctx, &remoteoutputservice.CleanRequest{
-		OutputBaseId: "eaf1d65b7ab802934e6b57d0e14b3f30",
-	})
-	require.NoError(t, err)
+		t.Run("RemovalFailure", func(t *testing.T) {
+			outputPath.EXPECT().RemoveAllChildren(true).Return(status.Error(codes.Internal, "Disk on fire"))
 
-	// VirtualLookup() should fail once again.
-	var out3 re_vfs.Attributes
-	_, s = d.VirtualLookup(ctx, path.MustNewComponent("eda09135e50ff6e877fe5f8136ddc759"), 0, &out3)
-	require.Equal(t, re_vfs.StatusErrNoEnt, s)
+			_, err := d.CleanAndStartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+				OutputBaseId:     "c6adef0d5ca1888a4aa847fb51229a8c",
+				BuildId:          "5e4f1f0f-9e43-4be2-97c2-2f5d3c7a8d0e",
+				InstanceName:     "my-cluster",
+				DigestFunction:   remoteexecution.DigestFunction_MD5,
+				OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+			})
+			testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Failed to clean output path: Disk on fire"), err)
+		})
+	})
 }
 
-func TestRemoteOutputServiceDirectoryVirtualReadDir(t *testing.T) {
+func TestRemoteOutputServiceDirectoryFinalizeBuildWaitsForFetches(t *testing.T) {
 	ctrl, ctx := gomock.WithContext(context.Background(), t)
 
 	handleAllocator := mock.NewMockStatefulHandleAllocator(ctrl)
@@ -1197,175 +10152,98 @@ func TestRemoteOutputServiceDirectoryVirtualReadDir(t *testing.T) {
 	handleAllocator.EXPECT().New().Return(dHandleAllocation)
 	dHandle := mock.NewMockStatefulDirectoryHandle(ctrl)
 	dHandleAllocation.EXPECT().AsStatefulDirectory(gomock.Any()).Return(dHandle)
-	d := cd_vfs.NewRemoteOutputServiceDirectory(
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(1000, 0)).AnyTimes()
+	d := newTestRemoteOutputServiceDirectory(
 		handleAllocator,
 		outputPathFactory,
 		bareContentAddressableStorage,
 		retryingContentAddressableStorage,
 		directoryFetcher,
 		symlinkFactory,
-		/* maximumMessageSizeBytes = */ 10000)
-
-	t.Run("InitialState", func(t *testing.T) {
-		// The directory should initially be empty.
-		reporter := mock.NewMockDirectoryEntryReporter(ctrl)
-
-		require.Equal(
-			t,
-			re_vfs.StatusOK,
-			d.VirtualReadDir(ctx, 0, re_vfs.AttributesMaskInodeNumber, reporter))
-	})
+		allowAllAuthorizer,
+		clock,
+		cd_vfs.RemoteOutputServiceDirectoryOptions{
+			FinalizeBuildWaitsForFetches: true,
+		})
 
-	// Create two output paths.
-	casFileHandleAllocation1 := mock.NewMockStatefulHandleAllocation(ctrl)
-	handleAllocator.EXPECT().New().Return(casFileHandleAllocation1)
-	casFileHandleAllocator1 := mock.NewMockStatelessHandleAllocator(ctrl)
-	casFileHandleAllocation1.EXPECT().AsStatelessAllocator().Return(casFileHandleAllocator1)
-	outputPath1 := mock.NewMockOutputPath(ctrl)
+	casFileHandleAllocation := mock.NewMockStatefulHandleAllocation(ctrl)
+	handleAllocator.EXPECT().New().Return(casFileHandleAllocation)
+	casFileHandleAllocator := mock.NewMockStatelessHandleAllocator(ctrl)
+	casFileHandleAllocation.EXPECT().AsStatelessAllocator().Return(casFileHandleAllocator)
+	outputPath := mock.NewMockOutputPath(ctrl)
 	outputPathFactory.EXPECT().StartInitialBuild(
-		path.MustNewComponent("83f3e6ff93a5403cbfb14682d8165968"),
+		path.MustNewComponent("4b4e2e6e2bbc0d6aed8d5d3b7e0f6a2c"),
 		gomock.Any(),
 		digest.MustNewFunction("", remoteexecution.DigestFunction_MD5),
 		gomock.Any(),
-	).Return(outputPath1)
-	outputPath1.EXPECT().FilterChildren(gomock.Any())
+	).Return(outputPath)
+	outputPath.EXPECT().FilterChildren(gomock.Any())
 
-	response, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
-		OutputBaseId:     "83f3e6ff93a5403cbfb14682d8165968",
-		BuildId:          "2b2b974f-ed53-40f0-a75a-422c09ba8be8",
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "4b4e2e6e2bbc0d6aed8d5d3b7e0f6a2c",
+		BuildId:          "1b2e3ee4-5c10-4c3b-8bd1-0b9e6b0d8e3f",
 		DigestFunction:   remoteexecution.DigestFunction_MD5,
 		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
-		OutputPathAliases: map[string]string{
-			"/home/bob/.cache/bazel/_bazel_bob/83f3e6ff93a5403cbfb14682d8165968/execroot/project1/bazel-out": ".",
-		},
 	})
 	require.NoError(t, err)
-	testutil.RequireEqualProto(t, &remoteoutputservice.StartBuildResponse{
-		OutputPathSuffix: "83f3e6ff93a5403cbfb14682d8165968",
-	}, response)
 
-	casFileHandleAllocation2 := mock.NewMockStatefulHandleAllocation(ctrl)
-	handleAllocator.EXPECT().New().Return(casFileHandleAllocation2)
-	casFileHandleAllocator2 := mock.NewMockStatelessHandleAllocator(ctrl)
-	casFileHandleAllocation2.EXPECT().AsStatelessAllocator().Return(casFileHandleAllocator2)
-	outputPath2 := mock.NewMockOutputPath(ctrl)
-	outputPathFactory.EXPECT().StartInitialBuild(
-		path.MustNewComponent("d4b145a6191c6d8d037d13986274d08d"),
-		gomock.Any(),
-		digest.MustNewFunction("", remoteexecution.DigestFunction_MD5),
+	// Let SetOutputPath() trigger a fetch of a Tree that doesn't
+	// complete until we permit it to.
+	fetchStarted := make(chan struct{})
+	fetchBlocked := make(chan struct{})
+	directoryFetcher.EXPECT().GetTreeRootDirectory(
 		gomock.Any(),
-	).Return(outputPath2)
-	outputPath2.EXPECT().FilterChildren(gomock.Any())
-
-	response, err = d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
-		OutputBaseId:     "d4b145a6191c6d8d037d13986274d08d",
-		BuildId:          "2f941206-fb17-460a-a779-10c621bc0d19",
-		DigestFunction:   remoteexecution.DigestFunction_MD5,
-		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
-		OutputPathAliases: map[string]string{
-			"/home/bob/.cache/bazel/_bazel_bob/d4b145a6191c6d8d037d13986274d08d/execroot/project2/bazel-out": ".",
-		},
+		digest.MustNewDigest("", remoteexecution.DigestFunction_MD5, "c2a1e4c7b33bba16f6a0b3e1e0d3b1a4", 123),
+	).DoAndReturn(func(ctx context.Context, treeDigest digest.Digest) (*remoteexecution.Directory, error) {
+		close(fetchStarted)
+		<-fetchBlocked
+		return nil, status.Error(codes.Unavailable, "Server offline")
 	})
-	require.NoError(t, err)
-	testutil.RequireEqualProto(t, &remoteoutputservice.StartBuildResponse{
-		OutputPathSuffix: "d4b145a6191c6d8d037d13986274d08d",
-	}, response)
-
-	t.Run("FromStart", func(t *testing.T) {
-		// The directory listing should contain both output paths.
-		reporter := mock.NewMockDirectoryEntryReporter(ctrl)
-		outputPath1.EXPECT().VirtualGetAttributes(
-			ctx,
-			re_vfs.AttributesMaskInodeNumber,
-			gomock.Any(),
-		).Do(func(ctx context.Context, requested re_vfs.AttributesMask, out *re_vfs.Attributes) {
-			out.SetInodeNumber(101)
-		})
-		reporter.EXPECT().ReportEntry(
-			uint64(1),
-			path.MustNewComponent("83f3e6ff93a5403cbfb14682d8165968"),
-			re_vfs.DirectoryChild{}.FromDirectory(outputPath1),
-			(&re_vfs.Attributes{}).SetInodeNumber(101),
-		).Return(true)
-		outputPath2.EXPECT().VirtualGetAttributes(
-			ctx,
-			re_vfs.AttributesMaskInodeNumber,
-			gomock.Any(),
-		).Do(func(ctx context.Context, requested re_vfs.AttributesMask, out *re_vfs.Attributes) {
-			out.SetInodeNumber(102)
+	setOutputPathErr := make(chan error, 1)
+	go func() {
+		_, err := d.SetOutputPath(ctx, &outputpaths.SetOutputPathRequest{
+			BuildId: "1b2e3ee4-5c10-4c3b-8bd1-0b9e6b0d8e3f",
+			RootTreeDigest: &remoteexecution.Digest{
+				Hash:      "c2a1e4c7b33bba16f6a0b3e1e0d3b1a4",
+				SizeBytes: 123,
+			},
 		})
-		reporter.EXPECT().ReportEntry(
-			uint64(2),
-			path.MustNewComponent("d4b145a6191c6d8d037d13986274d08d"),
-			re_vfs.DirectoryChild{}.FromDirectory(outputPath1),
-			(&re_vfs.Attributes{}).SetInodeNumber(102),
-		).Return(true)
+		setOutputPathErr <- err
+	}()
+	<-fetchStarted
 
-		require.Equal(
-			t,
-			re_vfs.StatusOK,
-			d.VirtualReadDir(ctx, 0, re_vfs.AttributesMaskInodeNumber, reporter))
-	})
-
-	t.Run("Partial", func(t *testing.T) {
-		reporter := mock.NewMockDirectoryEntryReporter(ctrl)
-		outputPath2.EXPECT().VirtualGetAttributes(
-			ctx,
-			re_vfs.AttributesMaskInodeNumber,
-			gomock.Any(),
-		).Do(func(ctx context.Context, requested re_vfs.AttributesMask, out *re_vfs.Attributes) {
-			out.SetInodeNumber(102)
+	t.Run("Cancelled", func(t *testing.T) {
+		// FinalizeBuild() should respect context cancellation
+		// while waiting for the fetch to complete. The build
+		// should remain running.
+		cancelledCtx, cancel := context.WithCancel(ctx)
+		cancel()
+		_, err := d.FinalizeBuild(cancelledCtx, &remoteoutputservice.FinalizeBuildRequest{
+			BuildId: "1b2e3ee4-5c10-4c3b-8bd1-0b9e6b0d8e3f",
 		})
-		reporter.EXPECT().ReportEntry(
-			uint64(2),
-			path.MustNewComponent("d4b145a6191c6d8d037d13986274d08d"),
-			re_vfs.DirectoryChild{}.FromDirectory(outputPath1),
-			(&re_vfs.Attributes{}).SetInodeNumber(102),
-		).Return(true)
-
-		require.Equal(
-			t,
-			re_vfs.StatusOK,
-			d.VirtualReadDir(ctx, 1, re_vfs.AttributesMaskInodeNumber, reporter))
-	})
-
-	t.Run("AtEOF", func(t *testing.T) {
-		reporter := mock.NewMockDirectoryEntryReporter(ctrl)
-		require.Equal(
-			t,
-			re_vfs.StatusOK,
-			d.VirtualReadDir(ctx, 2, re_vfs.AttributesMaskInodeNumber, reporter))
-	})
-
-	t.Run("BeyondEOF", func(t *testing.T) {
-		reporter := mock.NewMockDirectoryEntryReporter(ctrl)
-		require.Equal(
-			t,
-			re_vfs.StatusOK,
-			d.VirtualReadDir(ctx, 3, re_vfs.AttributesMaskInodeNumber, reporter))
-	})
-
-	// Remove all output paths.
-	outputPath1.EXPECT().RemoveAllChildren(true)
-	dHandle.EXPECT().NotifyRemoval(path.MustNewComponent("83f3e6ff93a5403cbfb14682d8165968"))
-	_, err = d.Clean(ctx, &remoteoutputservice.CleanRequest{
-		OutputBaseId: "83f3e6ff93a5403cbfb14682d8165968",
+		testutil.RequireEqualStatus(t, status.Error(codes.Canceled, "Failed to wait for outstanding fetches to complete: context canceled"), err)
 	})
-	require.NoError(t, err)
-
-	outputPath2.EXPECT().RemoveAllChildren(true)
-	dHandle.EXPECT().NotifyRemoval(path.MustNewComponent("d4b145a6191c6d8d037d13986274d08d"))
-	_, err = d.Clean(ctx, &remoteoutputservice.CleanRequest{
-		OutputBaseId: "d4b145a6191c6d8d037d13986274d08d",
-	})
-	require.NoError(t, err)
 
-	t.Run("AfterClean", func(t *testing.T) {
-		reporter := mock.NewMockDirectoryEntryReporter(ctrl)
+	t.Run("Success", func(t *testing.T) {
+		// Once the fetch completes, FinalizeBuild() should
+		// return.
+		outputPath.EXPECT().FinalizeBuild(gomock.Any(), gomock.Any())
+		finalizeBuildErr := make(chan error, 1)
+		go func() {
+			_, err := d.FinalizeBuild(ctx, &remoteoutputservice.FinalizeBuildRequest{
+				BuildId: "1b2e3ee4-5c10-4c3b-8bd1-0b9e6b0d8e3f",
+			})
+			finalizeBuildErr <- err
+		}()
+		select {
+		case <-finalizeBuildErr:
+			t.Fatal("FinalizeBuild() returned before the fetch completed")
+		case <-time.After(100 * time.Millisecond):
+		}
 
-		require.Equal(
-			t,
-			re_vfs.StatusOK,
-			d.VirtualReadDir(ctx, 0, re_vfs.AttributesMaskInodeNumber, reporter))
+		close(fetchBlocked)
+		require.Error(t, <-setOutputPathErr)
+		require.NoError(t, <-finalizeBuildErr)
 	})
 }
@@ -0,0 +1,82 @@
+package virtual
+
+import (
+	"strconv"
+	"syscall"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// SyscallErrorMapping converts syscall errors to gRPC status codes.
+// Resolving paths in output paths yields plain syscall errors (e.g.,
+// ENOTDIR when a pathname component refers to a file). Without any
+// conversion, these are reported through gRPC with code UNKNOWN.
+// BatchStat() and BatchCreate() use this mapping to ensure that the
+// same condition is always reported with the same code.
+type SyscallErrorMapping map[syscall.Errno]codes.Code
+
+// syscallErrorNames contains the names of syscall errors whose
+// mapping may be overridden through configuration.
+var syscallErrorNames = map[string]syscall.Errno{
+	"EACCES":       syscall.EACCES,
+	"EEXIST":       syscall.EEXIST,
+	"EINVAL":       syscall.EINVAL,
+	"EISDIR":       syscall.EISDIR,
+	"ELOOP":        syscall.ELOOP,
+	"ENAMETOOLONG": syscall.ENAMETOOLONG,
+	"ENOENT":       syscall.ENOENT,
+	"ENOTDIR":      syscall.ENOTDIR,
+	"ENOTEMPTY":    syscall.ENOTEMPTY,
+	"EPERM":        syscall.EPERM,
+}
+
+// DefaultSyscallErrorMapping is the mapping of syscall errors to gRPC
+// status codes that bb_clientd uses, unless overridden.
+var DefaultSyscallErrorMapping = SyscallErrorMapping{
+	syscall.EACCES:       codes.PermissionDenied,
+	syscall.EEXIST:       codes.AlreadyExists,
+	syscall.EINVAL:       codes.InvalidArgument,
+	syscall.EISDIR:       codes.FailedPrecondition,
+	syscall.ELOOP:        codes.FailedPrecondition,
+	syscall.ENAMETOOLONG: codes.InvalidArgument,
+	syscall.ENOENT:       codes.NotFound,
+	syscall.ENOTDIR:      codes.FailedPrecondition,
+	syscall.ENOTEMPTY:    codes.FailedPrecondition,
+	syscall.EPERM:        codes.PermissionDenied,
+}
+
+// NewSyscallErrorMappingFromConfiguration creates a SyscallErrorMapping
+// based on DefaultSyscallErrorMapping, where the codes of some of the
+// syscall errors are replaced. Both syscall errors and codes are
+// provided by name (e.g., "ENOTDIR" and "FAILED_PRECONDITION").
+func NewSyscallErrorMappingFromConfiguration(overrides map[string]string) (SyscallErrorMapping, error) {
+	m := make(SyscallErrorMapping, len(DefaultSyscallErrorMapping))
+	for errno, code := range DefaultSyscallErrorMapping {
+		m[errno] = code
+	}
+	for errnoName, codeName := range overrides {
+		errno, ok := syscallErrorNames[errnoName]
+		if !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "Unknown syscall error %#v", errnoName)
+		}
+		var code codes.Code
+		if err := code.UnmarshalJSON([]byte(strconv.Quote(codeName))); err != nil || code == codes.OK {
+			return nil, status.Errorf(codes.InvalidArgument, "Invalid status code %#v for syscall error %#v", codeName, errnoName)
+		}
+		m[errno] = code
+	}
+	return m, nil
+}
+
+// convert a syscall error to a gRPC status error. Errors that are not
+// syscall errors, or syscall errors for which no mapping exists, are
+// returned unmodified.
+func (m SyscallErrorMapping) convert(err error) error {
+	if errno, ok := err.(syscall.Errno); ok {
+		if code, ok := m[errno]; ok {
+			return status.Error(code, errno.Error())
+		}
+	}
+	return err
+}
@@ -0,0 +1,63 @@
+package virtual_test
+
+import (
+	"syscall"
+	"testing"
+
+	cd_vfs "github.com/buildbarn/bb-clientd/pkg/filesystem/virtual"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestNewSyscallErrorMappingFromConfiguration(t *testing.T) {
+	t.Run("Default", func(t *testing.T) {
+		m, err := cd_vfs.NewSyscallErrorMappingFromConfiguration(nil)
+		require.NoError(t, err)
+		require.Equal(t, cd_vfs.SyscallErrorMapping{
+			syscall.EACCES:       codes.PermissionDenied,
+			syscall.EEXIST:       codes.AlreadyExists,
+			syscall.EINVAL:       codes.InvalidArgument,
+			syscall.EISDIR:       codes.FailedPrecondition,
+			syscall.ELOOP:        codes.FailedPrecondition,
+			syscall.ENAMETOOLONG: codes.InvalidArgument,
+			syscall.ENOENT:       codes.NotFound,
+			syscall.ENOTDIR:      codes.FailedPrecondition,
+			syscall.ENOTEMPTY:    codes.FailedPrecondition,
+			syscall.EPERM:        codes.PermissionDenied,
+		}, m)
+	})
+
+	t.Run("Override", func(t *testing.T) {
+		// Overrides should not affect the default mapping.
+		m, err := cd_vfs.NewSyscallErrorMappingFromConfiguration(map[string]string{
+			"ENOTDIR": "INVALID_ARGUMENT",
+		})
+		require.NoError(t, err)
+		require.Equal(t, codes.InvalidArgument, m[syscall.ENOTDIR])
+		require.Equal(t, codes.NotFound, m[syscall.ENOENT])
+		require.Equal(t, codes.FailedPrecondition, cd_vfs.DefaultSyscallErrorMapping[syscall.ENOTDIR])
+	})
+
+	t.Run("UnknownSyscallError", func(t *testing.T) {
+		_, err := cd_vfs.NewSyscallErrorMappingFromConfiguration(map[string]string{
+			"EAGAIN": "UNAVAILABLE",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Unknown syscall error \"EAGAIN\""), err)
+	})
+
+	t.Run("InvalidCode", func(t *testing.T) {
+		_, err := cd_vfs.NewSyscallErrorMappingFromConfiguration(map[string]string{
+			"ENOENT": "NOPE",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Invalid status code \"NOPE\" for syscall error \"ENOENT\""), err)
+
+		// Mapping errors to OK would cause them to be ignored.
+		_, err = cd_vfs.NewSyscallErrorMappingFromConfiguration(map[string]string{
+			"ENOENT": "OK",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Invalid status code \"OK\" for syscall error \"ENOENT\""), err)
+	})
+}
@@ -0,0 +1,258 @@
+package virtual
+
+import (
+	"archive/tar"
+	"bufio"
+	"context"
+	"path/filepath"
+	"time"
+
+	"github.com/buildbarn/bb-clientd/pkg/proto/outputpaths"
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/virtual"
+	"github.com/buildbarn/bb-storage/pkg/filesystem"
+	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// defaultTarballExportChunkSizeBytes is the maximum number of
+	// bytes of the tar archive that ExportTarball() sends as part of
+	// a single response if the client does not provide a chunk
+	// size. The chunk size is also used as the size of reads against
+	// files.
+	defaultTarballExportChunkSizeBytes = 64 * 1024
+
+	// maximumTarballExportChunkSizeBytes is the maximum chunk size
+	// that clients may provide. It ensures responses remain below
+	// gRPC's default maximum message size.
+	maximumTarballExportChunkSizeBytes = 1024 * 1024
+
+	// maximumTarballExportReadConcurrency is the maximum number of
+	// chunks of a single file that clients may request to be read
+	// in parallel.
+	maximumTarballExportReadConcurrency = 64
+)
+
+// tarballChunkSender is an io.Writer that forwards all data written to
+// it as a single response message.
+type tarballChunkSender func(data []byte) error
+
+func (s tarballChunkSender) Write(p []byte) (int, error) {
+	if err := s(p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// tarballExporter is used by ExportTarball() to convert the contents of
+// a directory in an output path to a tar archive. Directories are only
+// written once the first entry that is included is encountered, so
+// that include patterns don't cause empty directories to be emitted.
+//
+// Data is only read as fast as send() returns. As gRPC blocks sending
+// responses while the client has not consumed previous ones, this
+// ensures that the tar archive is never buffered in its entirety.
+type tarballExporter struct {
+	context         context.Context
+	includePatterns []string
+	chunkSizeBytes  int
+	readConcurrency int
+	send            func(data []byte, progress *outputpaths.ExportTarballProgress) error
+	buffer          *bufio.Writer
+	writer          *tar.Writer
+
+	pendingDirectories []*tar.Header
+	entriesExported    uint64
+	fileBytesExported  uint64
+}
+
+func newTarballExporter(ctx context.Context, request *outputpaths.ExportTarballRequest, send func(data []byte, progress *outputpaths.ExportTarballProgress) error) (*tarballExporter, error) {
+	for _, pattern := range request.IncludePatterns {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "Invalid include pattern %#v", pattern)
+		}
+	}
+	chunkSizeBytes := defaultTarballExportChunkSizeBytes
+	if request.ChunkSizeBytes > maximumTarballExportChunkSizeBytes {
+		return nil, status.Errorf(codes.InvalidArgument, "Chunk size of %d bytes exceeds the maximum of %d bytes", request.ChunkSizeBytes, maximumTarballExportChunkSizeBytes)
+	} else if request.ChunkSizeBytes > 0 {
+		chunkSizeBytes = int(request.ChunkSizeBytes)
+	}
+	if request.ReadConcurrency > maximumTarballExportReadConcurrency {
+		return nil, status.Errorf(codes.InvalidArgument, "Read concurrency of %d exceeds the maximum of %d", request.ReadConcurrency, maximumTarballExportReadConcurrency)
+	}
+
+	te := &tarballExporter{
+		context:         ctx,
+		includePatterns: request.IncludePatterns,
+		chunkSizeBytes:  chunkSizeBytes,
+		readConcurrency: int(request.ReadConcurrency),
+		send:            send,
+	}
+	te.buffer = bufio.NewWriterSize(tarballChunkSender(te.sendChunk), chunkSizeBytes)
+	te.writer = tar.NewWriter(te.buffer)
+	return te, nil
+}
+
+// sendChunk sends a chunk of the tar archive, annotated with the
+// progress of the export.
+func (te *tarballExporter) sendChunk(data []byte) error {
+	return te.send(data, &outputpaths.ExportTarballProgress{
+		EntriesExported:   te.entriesExported,
+		FileBytesExported: te.fileBytesExported,
+	})
+}
+
+// isIncluded returns whether a file or symbolic link matches any of the
+// include patterns.
+func (te *tarballExporter) isIncluded(p string) bool {
+	if len(te.includePatterns) == 0 {
+		return true
+	}
+	for _, pattern := range te.includePatterns {
+		if matched, _ := filepath.Match(pattern, p); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// writeHeader writes a header to the tar archive, preceded by the
+// headers of any parent directories that have not been written yet.
+func (te *tarballExporter) writeHeader(header *tar.Header) error {
+	for _, directoryHeader := range te.pendingDirectories {
+		if err := te.writer.WriteHeader(directoryHeader); err != nil {
+			return util.StatusWrapfWithCode(err, codes.Internal, "Failed to write header of directory %#v", directoryHeader.Name)
+		}
+	}
+	te.entriesExported += uint64(len(te.pendingDirectories))
+	te.pendingDirectories = te.pendingDirectories[:0]
+	if err := te.writer.WriteHeader(header); err != nil {
+		return util.StatusWrapfWithCode(err, codes.Internal, "Failed to write header of %#v", header.Name)
+	}
+	te.entriesExported++
+	return nil
+}
+
+func (te *tarballExporter) getModificationTime(attributes *virtual.Attributes) time.Time {
+	if modificationTime, ok := attributes.GetLastDataModificationTime(); ok {
+		return modificationTime
+	}
+	return time.Unix(0, 0)
+}
+
+// exportDirectory writes all children of a directory to the tar
+// archive. Child directories are exported recursively.
+func (te *tarballExporter) exportDirectory(d virtual.PrepopulatedDirectory, dPath *path.Trace) error {
+	if err := te.context.Err(); err != nil {
+		return util.StatusFromContext(te.context)
+	}
+	directories, leaves, err := d.LookupAllChildren()
+	if err != nil {
+		return util.StatusWrapf(err, "Failed to look up children of directory %#v", dPath.String())
+	}
+
+	for _, entry := range directories {
+		childPath := dPath.Append(entry.Name)
+		var attributes virtual.Attributes
+		entry.Child.VirtualGetAttributes(te.context, virtual.AttributesMaskLastDataModificationTime, &attributes)
+		pendingDirectories := len(te.pendingDirectories)
+		te.pendingDirectories = append(te.pendingDirectories, &tar.Header{
+			Typeflag: tar.TypeDir,
+			Name:     childPath.String() + "/",
+			Mode:     0o755,
+			ModTime:  te.getModificationTime(&attributes),
+		})
+		if err := te.exportDirectory(entry.Child, childPath); err != nil {
+			return err
+		}
+		if len(te.pendingDirectories) > pendingDirectories {
+			// Directory did not contain any entries that
+			// were included.
+			te.pendingDirectories = te.pendingDirectories[:pendingDirectories]
+		}
+	}
+
+	for _, entry := range leaves {
+		childPath := dPath.Append(entry.Name)
+		if !te.isIncluded(childPath.String()) {
+			continue
+		}
+		if err := te.exportLeaf(entry.Child, childPath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// exportLeaf writes a single file or symbolic link to the tar archive.
+// Files of other types are skipped, as they don't have any contents
+// that can be exported.
+func (te *tarballExporter) exportLeaf(leaf virtual.NativeLeaf, leafPath *path.Trace) error {
+	if err := te.context.Err(); err != nil {
+		return util.StatusFromContext(te.context)
+	}
+
+	var attributes virtual.Attributes
+	leaf.VirtualGetAttributes(te.context, virtual.AttributesMaskFileType|virtual.AttributesMaskLastDataModificationTime|virtual.AttributesMaskPermissions|virtual.AttributesMaskSizeBytes, &attributes)
+	switch attributes.GetFileType() {
+	case filesystem.FileTypeSymlink:
+		target, err := leaf.Readlink()
+		if err != nil {
+			return util.StatusWrapf(err, "Failed to read symbolic link %#v", leafPath.String())
+		}
+		return te.writeHeader(&tar.Header{
+			Typeflag: tar.TypeSymlink,
+			Name:     leafPath.String(),
+			Linkname: target,
+			Mode:     0o777,
+			ModTime:  te.getModificationTime(&attributes),
+		})
+	case filesystem.FileTypeRegularFile:
+		permissions, ok := attributes.GetPermissions()
+		if !ok {
+			panic("Leaf did not provide permissions, even though they were requested")
+		}
+		sizeBytes, ok := attributes.GetSizeBytes()
+		if !ok {
+			panic("Leaf did not provide a size, even though it was requested")
+		}
+		mode := int64(0o644)
+		if permissions&virtual.PermissionsExecute != 0 {
+			mode = 0o755
+		}
+		if err := te.writeHeader(&tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     leafPath.String(),
+			Size:     int64(sizeBytes),
+			Mode:     mode,
+			ModTime:  te.getModificationTime(&attributes),
+		}); err != nil {
+			return err
+		}
+		if err := readLeaf(te.context, leaf, 0, sizeBytes, te.chunkSizeBytes, te.readConcurrency, func(data []byte) error {
+			if _, err := te.writer.Write(data); err != nil {
+				return err
+			}
+			te.fileBytesExported += uint64(len(data))
+			return nil
+		}); err != nil {
+			return util.StatusWrapf(err, "Failed to read file %#v", leafPath.String())
+		}
+		return nil
+	default:
+		return nil
+	}
+}
+
+// finalize writes the trailer of the tar archive, and sends any data
+// that is still buffered.
+func (te *tarballExporter) finalize() error {
+	if err := te.writer.Close(); err != nil {
+		return util.StatusWrapWithCode(err, codes.Internal, "Failed to close tar archive")
+	}
+	return te.buffer.Flush()
+}
@@ -0,0 +1,108 @@
+package testutil
+
+import (
+	"context"
+	"sync"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-storage/pkg/blobstore"
+	"github.com/buildbarn/bb-storage/pkg/blobstore/buffer"
+	"github.com/buildbarn/bb-storage/pkg/blobstore/slicing"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// FakeContentAddressableStorage is an implementation of BlobAccess
+// that stores all blobs in memory. It can be used to exercise
+// RemoteOutputServiceDirectory in tests, without requiring access to
+// an actual Content Addressable Storage.
+//
+// Blobs are keyed by their full digest, meaning that blobs are not
+// shared between instance names or digest functions.
+type FakeContentAddressableStorage struct {
+	lock  sync.Mutex
+	blobs map[digest.Digest][]byte
+}
+
+var _ blobstore.BlobAccess = (*FakeContentAddressableStorage)(nil)
+
+// NewFakeContentAddressableStorage creates a
+// FakeContentAddressableStorage that does not contain any blobs.
+func NewFakeContentAddressableStorage() *FakeContentAddressableStorage {
+	return &FakeContentAddressableStorage{
+		blobs: map[digest.Digest][]byte{},
+	}
+}
+
+// PutBlob stores a blob, computing its digest using the provided
+// digest function. The digest is returned, so that it can be provided
+// to BatchCreate().
+func (ba *FakeContentAddressableStorage) PutBlob(digestFunction digest.Function, data []byte) digest.Digest {
+	generator := digestFunction.NewGenerator(int64(len(data)))
+	if _, err := generator.Write(data); err != nil {
+		panic(err)
+	}
+	blobDigest := generator.Sum()
+
+	ba.lock.Lock()
+	ba.blobs[blobDigest] = append([]byte(nil), data...)
+	ba.lock.Unlock()
+	return blobDigest
+}
+
+// GetCapabilities reports that all digest functions are supported.
+func (ba *FakeContentAddressableStorage) GetCapabilities(ctx context.Context, instanceName digest.InstanceName) (*remoteexecution.ServerCapabilities, error) {
+	return &remoteexecution.ServerCapabilities{
+		CacheCapabilities: &remoteexecution.CacheCapabilities{
+			DigestFunctions: digest.SupportedDigestFunctions,
+		},
+	}, nil
+}
+
+// Get the contents of a blob.
+func (ba *FakeContentAddressableStorage) Get(ctx context.Context, blobDigest digest.Digest) buffer.Buffer {
+	ba.lock.Lock()
+	data, ok := ba.blobs[blobDigest]
+	ba.lock.Unlock()
+	if !ok {
+		return buffer.NewBufferFromError(status.Errorf(codes.NotFound, "Blob %#v not found", blobDigest.String()))
+	}
+	return buffer.NewValidatedBufferFromByteSlice(data)
+}
+
+// GetFromComposite extracts an object contained in another object,
+// such as a Directory contained in a Tree. The slices of the parent
+// object are not cached.
+func (ba *FakeContentAddressableStorage) GetFromComposite(ctx context.Context, parentDigest, childDigest digest.Digest, slicer slicing.BlobSlicer) buffer.Buffer {
+	b, _ := slicer.Slice(ba.Get(ctx, parentDigest), childDigest)
+	return b
+}
+
+// Put stores a blob.
+func (ba *FakeContentAddressableStorage) Put(ctx context.Context, blobDigest digest.Digest, b buffer.Buffer) error {
+	data, err := b.ToByteSlice(int(blobDigest.GetSizeBytes()))
+	if err != nil {
+		return err
+	}
+
+	ba.lock.Lock()
+	ba.blobs[blobDigest] = data
+	ba.lock.Unlock()
+	return nil
+}
+
+// FindMissing returns the digests of blobs that have not been stored.
+func (ba *FakeContentAddressableStorage) FindMissing(ctx context.Context, digests digest.Set) (digest.Set, error) {
+	ba.lock.Lock()
+	defer ba.lock.Unlock()
+
+	missing := digest.NewSetBuilder()
+	for _, blobDigest := range digests.Items() {
+		if _, ok := ba.blobs[blobDigest]; !ok {
+			missing.Add(blobDigest)
+		}
+	}
+	return missing.Build(), nil
+}
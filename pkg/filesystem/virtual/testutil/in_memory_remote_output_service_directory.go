@@ -0,0 +1,46 @@
+package testutil
+
+import (
+	"sort"
+
+	cd_vfs "github.com/buildbarn/bb-clientd/pkg/filesystem/virtual"
+	re_cas "github.com/buildbarn/bb-remote-execution/pkg/cas"
+	re_filesystem "github.com/buildbarn/bb-remote-execution/pkg/filesystem"
+	re_vfs "github.com/buildbarn/bb-remote-execution/pkg/filesystem/virtual"
+	"github.com/buildbarn/bb-storage/pkg/auth"
+	"github.com/buildbarn/bb-storage/pkg/blobstore"
+	"github.com/buildbarn/bb-storage/pkg/clock"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/random"
+)
+
+// NewInMemoryRemoteOutputServiceDirectory creates a
+// RemoteOutputServiceDirectory that stores the contents of output
+// paths in memory, and reads files and directories from the provided
+// Content Addressable Storage. It uses the same defaults as bb_clientd,
+// and permits all clients to modify output paths.
+//
+// This function is intended to be used by tests that call into the
+// Remote Output Service, such as StartBuild(), BatchCreate() and
+// BatchStat(). It may be combined with FakeContentAddressableStorage,
+// so that no actual Content Addressable Storage is needed.
+func NewInMemoryRemoteOutputServiceDirectory(contentAddressableStorage blobstore.BlobAccess) *cd_vfs.RemoteOutputServiceDirectory {
+	handleAllocator := re_vfs.NewFUSEHandleAllocator(random.FastThreadSafeGenerator)
+	symlinkFactory := re_vfs.NewHandleAllocatingSymlinkFactory(
+		re_vfs.BaseSymlinkFactory,
+		handleAllocator.New())
+	return cd_vfs.NewRemoteOutputServiceDirectory(
+		handleAllocator,
+		cd_vfs.NewInMemoryOutputPathFactory(re_filesystem.InMemoryFilePool, symlinkFactory, handleAllocator, sort.Sort, clock.SystemClock),
+		contentAddressableStorage,
+		contentAddressableStorage,
+		re_cas.NewBlobAccessDirectoryFetcher(
+			contentAddressableStorage,
+			/* maximumDirectorySizeBytes = */ 16*1024*1024,
+			/* maximumTreeSizeBytes = */ 64*1024*1024),
+		symlinkFactory,
+		auth.NewStaticAuthorizer(func(digest.InstanceName) bool { return true }),
+		/* maximumTreeSizeBytes = */ 64*1024*1024,
+		clock.SystemClock,
+		cd_vfs.RemoteOutputServiceDirectoryOptions{})
+}
@@ -0,0 +1,92 @@
+package testutil_test
+
+import (
+	"context"
+	"testing"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	cd_testutil "github.com/buildbarn/bb-clientd/pkg/filesystem/virtual/testutil"
+	"github.com/buildbarn/bb-remote-execution/pkg/proto/remoteoutputservice"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInMemoryRemoteOutputServiceDirectory(t *testing.T) {
+	ctx := context.Background()
+
+	contentAddressableStorage := cd_testutil.NewFakeContentAddressableStorage()
+	d := cd_testutil.NewInMemoryRemoteOutputServiceDirectory(contentAddressableStorage)
+
+	_, err := d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		DigestFunction:   remoteexecution.DigestFunction_SHA256,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+
+	// Files created through BatchCreate() should be backed by the
+	// fake Content Addressable Storage.
+	fileDigest := contentAddressableStorage.PutBlob(
+		digest.MustNewFunction("", remoteexecution.DigestFunction_SHA256),
+		[]byte("Hello"))
+	_, err = d.BatchCreate(ctx, &remoteoutputservice.BatchCreateRequest{
+		BuildId:    "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		PathPrefix: "bazel-out",
+		Files: []*remoteexecution.OutputFile{
+			{
+				Path:   "hello.txt",
+				Digest: fileDigest.GetProto(),
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	response, err := d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
+		BuildId:           "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		IncludeFileDigest: true,
+		Paths:             []string{"bazel-out/hello.txt"},
+	})
+	require.NoError(t, err)
+	testutil.RequireEqualProto(t, &remoteoutputservice.BatchStatResponse{
+		Responses: []*remoteoutputservice.StatResponse{
+			{
+				FileStatus: &remoteoutputservice.FileStatus{
+					FileType: &remoteoutputservice.FileStatus_File_{
+						File: &remoteoutputservice.FileStatus_File{
+							Digest: fileDigest.GetProto(),
+						},
+					},
+				},
+			},
+		},
+	}, response)
+
+	// As the file is present in the Content Addressable Storage,
+	// restarting the build should leave it in place.
+	_, err = d.StartBuild(ctx, &remoteoutputservice.StartBuildRequest{
+		OutputBaseId:     "9da951b8cb759233037166e28f7ea186",
+		BuildId:          "0a3c4a01-f2b1-4f4d-9d5f-8e1e1f2b3c4d",
+		DigestFunction:   remoteexecution.DigestFunction_SHA256,
+		OutputPathPrefix: "/home/bob/bb_clientd/outputs",
+	})
+	require.NoError(t, err)
+
+	response, err = d.BatchStat(ctx, &remoteoutputservice.BatchStatRequest{
+		BuildId: "0a3c4a01-f2b1-4f4d-9d5f-8e1e1f2b3c4d",
+		Paths:   []string{"bazel-out/hello.txt"},
+	})
+	require.NoError(t, err)
+	testutil.RequireEqualProto(t, &remoteoutputservice.BatchStatResponse{
+		Responses: []*remoteoutputservice.StatResponse{
+			{
+				FileStatus: &remoteoutputservice.FileStatus{
+					FileType: &remoteoutputservice.FileStatus_File_{
+						File: &remoteoutputservice.FileStatus_File{},
+					},
+				},
+			},
+		},
+	}, response)
+}
@@ -15,22 +15,24 @@ import (
 type treeCASDirectoryFactory struct {
 	re_vfs.CASFileFactory
 
-	context          context.Context
-	directoryFetcher re_cas.DirectoryFetcher
-	handleAllocator  *re_vfs.ResolvableDigestHandleAllocator
-	errorLogger      util.ErrorLogger
+	context           context.Context
+	directoryFetcher  re_cas.DirectoryFetcher
+	handleAllocator   *re_vfs.ResolvableDigestHandleAllocator
+	computeLinkCounts bool
+	errorLogger       util.ErrorLogger
 }
 
 // NewTreeCASDirectoryFactory creates a CASDirectoryFactory that is
 // capable of creating directories that correspond to the root directory
 // of REv2 Tree messages messages that are stored in a Content
 // Addressable Storage (CAS).
-func NewTreeCASDirectoryFactory(ctx context.Context, casFileFactory re_vfs.CASFileFactory, directoryFetcher re_cas.DirectoryFetcher, handleAllocation re_vfs.StatelessHandleAllocation, errorLogger util.ErrorLogger) CASDirectoryFactory {
+func NewTreeCASDirectoryFactory(ctx context.Context, casFileFactory re_vfs.CASFileFactory, directoryFetcher re_cas.DirectoryFetcher, handleAllocation re_vfs.StatelessHandleAllocation, computeLinkCounts bool, errorLogger util.ErrorLogger) CASDirectoryFactory {
 	cdf := &treeCASDirectoryFactory{
-		CASFileFactory:   casFileFactory,
-		context:          ctx,
-		directoryFetcher: directoryFetcher,
-		errorLogger:      errorLogger,
+		CASFileFactory:    casFileFactory,
+		context:           ctx,
+		directoryFetcher:  directoryFetcher,
+		computeLinkCounts: computeLinkCounts,
+		errorLogger:       errorLogger,
 	}
 	cdf.handleAllocator = re_vfs.NewResolvableDigestHandleAllocator(handleAllocation, cdf.resolve)
 	return cdf
@@ -77,7 +79,8 @@ func (cdc *treeCASDirectoryContext) createRootDirectory() (re_vfs.Directory, re_
 		},
 		cdc.treeDigest.GetDigestFunction(),
 		cdc.handleAllocator.New(bytes.NewBuffer([]byte{0})),
-		uint64(cdc.treeDigest.GetSizeBytes()))
+		uint64(cdc.treeDigest.GetSizeBytes()),
+		cdc.computeLinkCounts)
 }
 
 func (cdc *treeCASDirectoryContext) createChildDirectory(childDigest digest.Digest) (re_vfs.Directory, re_vfs.HandleResolver) {
@@ -88,7 +91,8 @@ func (cdc *treeCASDirectoryContext) createChildDirectory(childDigest digest.Dige
 		},
 		cdc.treeDigest.GetDigestFunction(),
 		cdc.handleAllocator.New(bytes.NewBuffer(append([]byte{1}, childDigest.GetCompactBinary()...))),
-		uint64(cdc.treeDigest.GetSizeBytes()))
+		uint64(cdc.treeDigest.GetSizeBytes()),
+		cdc.computeLinkCounts)
 }
 
 func (cdc *treeCASDirectoryContext) resolve(r io.ByteReader) (re_vfs.DirectoryChild, re_vfs.Status) {
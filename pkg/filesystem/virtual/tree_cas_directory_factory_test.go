@@ -92,6 +92,7 @@ func TestTreeCASDirectoryFactoryLookupDirectory(t *testing.T) {
 		casFileFactory,
 		directoryFetcher,
 		rootHandleAllocation,
+		/* computeLinkCounts = */ false,
 		errorLogger)
 
 	treeHandleAllocator := treeCASDirectoryFactoryExpectLookupRootDirectory(
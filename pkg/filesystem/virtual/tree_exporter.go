@@ -0,0 +1,271 @@
+package virtual
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"syscall"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/virtual"
+	"github.com/buildbarn/bb-storage/pkg/blobstore"
+	"github.com/buildbarn/bb-storage/pkg/blobstore/buffer"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"golang.org/x/sync/semaphore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// treeExporter is used by ExportTree() to convert the contents of a
+// directory in an output path to an REv2 Tree message. Files that are
+// not backed by the Content Addressable Storage are uploaded.
+//
+// Conversion happens in two passes. The first pass traverses the
+// directory hierarchy, uploading files concurrently. The second pass
+// computes the digests of directories, which can only be done once the
+// digests of all files contained in them are known.
+type treeExporter struct {
+	context                   context.Context
+	contentAddressableStorage blobstore.BlobAccess
+	digestFunction            digest.Function
+	uploadSemaphore           *semaphore.Weighted
+
+	children     []*remoteexecution.Directory
+	childrenSeen map[digest.Digest]struct{}
+
+	uploadsWait  sync.WaitGroup
+	uploadsLock  sync.Mutex
+	uploadsError error
+}
+
+func newTreeExporter(ctx context.Context, contentAddressableStorage blobstore.BlobAccess, digestFunction digest.Function, uploadConcurrency int) *treeExporter {
+	return &treeExporter{
+		context:                   ctx,
+		contentAddressableStorage: contentAddressableStorage,
+		digestFunction:            digestFunction,
+		uploadSemaphore:           semaphore.NewWeighted(int64(uploadConcurrency)),
+		childrenSeen:              map[digest.Digest]struct{}{},
+	}
+}
+
+// exportedDirectory is a directory that has been converted to an REv2
+// Directory message during the first pass, for which the digests of
+// child directories have not been computed yet.
+type exportedDirectory struct {
+	directory *remoteexecution.Directory
+	path      *path.Trace
+	// Directories that are contained in this directory, using the
+	// same order as directory.Directories.
+	children []*exportedDirectory
+}
+
+func (te *treeExporter) computeDigest(data []byte) digest.Digest {
+	digestGenerator := te.digestFunction.NewGenerator(int64(len(data)))
+	if _, err := digestGenerator.Write(data); err != nil {
+		panic(err)
+	}
+	return digestGenerator.Sum()
+}
+
+// startUpload uploads a file to the Content Addressable Storage in
+// the background, storing its digest in the provided FileNode once
+// completed. If an upload of another file failed previously, no more
+// uploads are started.
+func (te *treeExporter) startUpload(leaf virtual.NativeLeaf, fileNode *remoteexecution.FileNode, filePath *path.Trace) error {
+	if te.uploadSemaphore.Acquire(te.context, 1) != nil {
+		return util.StatusFromContext(te.context)
+	}
+	te.uploadsLock.Lock()
+	err := te.uploadsError
+	te.uploadsLock.Unlock()
+	if err != nil {
+		te.uploadSemaphore.Release(1)
+		return err
+	}
+
+	te.uploadsWait.Add(1)
+	go func() {
+		defer te.uploadsWait.Done()
+		defer te.uploadSemaphore.Release(1)
+
+		fileDigest, err := leaf.UploadFile(te.context, te.contentAddressableStorage, te.digestFunction)
+		if err != nil {
+			te.uploadsLock.Lock()
+			if te.uploadsError == nil {
+				te.uploadsError = util.StatusWrapf(err, "Failed to upload file %#v", filePath.String())
+			}
+			te.uploadsLock.Unlock()
+			return
+		}
+		fileNode.Digest = fileDigest.GetProto()
+	}()
+	return nil
+}
+
+// waitForUploads waits for all uploads started by startUpload() to
+// complete, returning the error of the first upload that failed.
+func (te *treeExporter) waitForUploads() error {
+	te.uploadsWait.Wait()
+	return te.uploadsError
+}
+
+// exportDirectory converts a single directory to an REv2 Directory
+// message. Child directories are converted recursively. The digests of
+// files are filled in once their uploads complete, while the digests of
+// directories are filled in by computeDirectoryDigests().
+func (te *treeExporter) exportDirectory(d virtual.PrepopulatedDirectory, dPath *path.Trace) (*exportedDirectory, error) {
+	directories, leaves, err := d.LookupAllChildren()
+	if err != nil {
+		return nil, util.StatusWrapf(err, "Failed to look up children of directory %#v", dPath.String())
+	}
+
+	var directory remoteexecution.Directory
+	exported := &exportedDirectory{
+		directory: &directory,
+		path:      dPath,
+		children:  make([]*exportedDirectory, 0, len(directories)),
+	}
+	for _, entry := range directories {
+		childDirectory, err := te.exportDirectory(entry.Child, dPath.Append(entry.Name))
+		if err != nil {
+			return nil, err
+		}
+		exported.children = append(exported.children, childDirectory)
+		directory.Directories = append(directory.Directories, &remoteexecution.DirectoryNode{
+			Name: entry.Name.String(),
+		})
+	}
+
+	for _, entry := range leaves {
+		childPath := dPath.Append(entry.Name)
+		target, err := entry.Child.Readlink()
+		if err == nil {
+			// REv2 only permits absolute symlink targets if
+			// the server announces support for them. As we
+			// don't know where the Tree ends up being used,
+			// forbid them entirely.
+			if strings.HasPrefix(target, "/") {
+				return nil, status.Errorf(codes.InvalidArgument, "Symbolic link %#v has absolute target %#v, which cannot be part of a Tree", childPath.String(), target)
+			}
+			directory.Symlinks = append(directory.Symlinks, &remoteexecution.SymlinkNode{
+				Name:   entry.Name.String(),
+				Target: target,
+			})
+			continue
+		} else if err != syscall.EINVAL {
+			return nil, util.StatusWrapf(err, "Failed to read symbolic link %#v", childPath.String())
+		}
+
+		var attributes virtual.Attributes
+		entry.Child.VirtualGetAttributes(te.context, virtual.AttributesMaskPermissions, &attributes)
+		permissions, ok := attributes.GetPermissions()
+		if !ok {
+			panic("Leaf did not provide permissions, even though they were requested")
+		}
+		fileNode := &remoteexecution.FileNode{
+			Name:         entry.Name.String(),
+			IsExecutable: permissions&virtual.PermissionsExecute != 0,
+		}
+		if err := te.startUpload(entry.Child, fileNode, childPath); err != nil {
+			return nil, err
+		}
+		directory.Files = append(directory.Files, fileNode)
+	}
+	return exported, nil
+}
+
+// computeDirectoryDigests computes the digests of all directories
+// contained in a directory that was converted by exportDirectory().
+// Child directories are retained, so that they may become part of the
+// Tree.
+func (te *treeExporter) computeDirectoryDigests(exported *exportedDirectory) error {
+	for i, child := range exported.children {
+		if err := te.computeDirectoryDigests(child); err != nil {
+			return err
+		}
+		childData, err := proto.Marshal(child.directory)
+		if err != nil {
+			return util.StatusWrapf(err, "Failed to marshal directory %#v", child.path.String())
+		}
+
+		// There is no need to make the directory part of the
+		// Tree if we have seen an identical directory
+		// previously.
+		childDigest := te.computeDigest(childData)
+		if _, ok := te.childrenSeen[childDigest]; !ok {
+			te.children = append(te.children, child.directory)
+			te.childrenSeen[childDigest] = struct{}{}
+		}
+		exported.directory.Directories[i].Digest = childDigest.GetProto()
+	}
+	return nil
+}
+
+// exportTree converts a directory to an REv2 Tree message, and uploads
+// it to the Content Addressable Storage.
+func (te *treeExporter) exportTree(d virtual.PrepopulatedDirectory, dPath *path.Trace) (digest.Digest, error) {
+	// Wait for uploads that were started to complete, even if
+	// traversal fails, so that no uploads outlive the call.
+	rootDirectory, err := te.exportDirectory(d, dPath)
+	if uploadsErr := te.waitForUploads(); err == nil {
+		err = uploadsErr
+	}
+	if err != nil {
+		return digest.BadDigest, err
+	}
+	if err := te.computeDirectoryDigests(rootDirectory); err != nil {
+		return digest.BadDigest, err
+	}
+	treeData, err := proto.Marshal(&remoteexecution.Tree{
+		Root:     rootDirectory.directory,
+		Children: te.children,
+	})
+	if err != nil {
+		return digest.BadDigest, util.StatusWrap(err, "Failed to marshal tree")
+	}
+	treeDigest := te.computeDigest(treeData)
+	if err := te.contentAddressableStorage.Put(te.context, treeDigest, buffer.NewValidatedBufferFromByteSlice(treeData)); err != nil {
+		return digest.BadDigest, util.StatusWrap(err, "Failed to store tree")
+	}
+	return treeDigest, nil
+}
+
+// directoryLookupComponentWalker is an implementation of
+// ComponentWalker that is used by ExportTree() to resolve the
+// directory whose contents need to be exported. Symbolic links are not
+// followed.
+type directoryLookupComponentWalker struct {
+	stack util.NonEmptyStack[virtual.PrepopulatedDirectory]
+}
+
+func (cw *directoryLookupComponentWalker) OnDirectory(name path.Component) (path.GotDirectoryOrSymlink, error) {
+	child, err := cw.stack.Peek().LookupChild(name)
+	if err != nil {
+		return nil, err
+	}
+	directory, _ := child.GetPair()
+	if directory == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Path component %#v does not resolve to a directory", name.String())
+	}
+	cw.stack.Push(directory)
+	return path.GotDirectory{
+		Child:        cw,
+		IsReversible: true,
+	}, nil
+}
+
+func (cw *directoryLookupComponentWalker) OnTerminal(name path.Component) (*path.GotSymlink, error) {
+	_, err := cw.OnDirectory(name)
+	return nil, err
+}
+
+func (cw *directoryLookupComponentWalker) OnUp() (path.ComponentWalker, error) {
+	if _, ok := cw.stack.PopSingle(); !ok {
+		return nil, status.Error(codes.InvalidArgument, "Path resolves to a location outside the output path")
+	}
+	return cw, nil
+}
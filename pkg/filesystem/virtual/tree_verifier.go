@@ -0,0 +1,139 @@
+package virtual
+
+import (
+	"context"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	re_cas "github.com/buildbarn/bb-remote-execution/pkg/cas"
+	"github.com/buildbarn/bb-storage/pkg/blobstore"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// treeVerifier is used by VerifyTree() to check whether all objects
+// referenced by a Tree object are present. Child directories are
+// obtained from the Tree through the DirectoryFetcher, while the
+// existence of files is checked by calling FindMissingBlobs() in
+// batches.
+type treeVerifier struct {
+	context                   context.Context
+	directoryFetcher          re_cas.DirectoryFetcher
+	contentAddressableStorage blobstore.BlobAccess
+	treeDigest                digest.Digest
+	batchSize                 int
+
+	directoriesSeen    map[digest.Digest]struct{}
+	filesSeen          map[digest.Digest]struct{}
+	queue              digest.SetBuilder
+	missingDirectories digest.SetBuilder
+	missingFiles       digest.SetBuilder
+}
+
+func newTreeVerifier(ctx context.Context, directoryFetcher re_cas.DirectoryFetcher, contentAddressableStorage blobstore.BlobAccess, treeDigest digest.Digest, batchSize int) *treeVerifier {
+	return &treeVerifier{
+		context:                   ctx,
+		directoryFetcher:          directoryFetcher,
+		contentAddressableStorage: contentAddressableStorage,
+		treeDigest:                treeDigest,
+		batchSize:                 batchSize,
+
+		directoriesSeen:    map[digest.Digest]struct{}{},
+		filesSeen:          map[digest.Digest]struct{}{},
+		queue:              digest.NewSetBuilder(),
+		missingDirectories: digest.NewSetBuilder(),
+		missingFiles:       digest.NewSetBuilder(),
+	}
+}
+
+// verifyDirectory queues all files contained in a directory and its
+// subdirectories for existence checking. Subdirectories that are not
+// contained in the Tree are reported as missing.
+func (tv *treeVerifier) verifyDirectory(directory *remoteexecution.Directory, dPath *path.Trace) error {
+	if err := tv.context.Err(); err != nil {
+		return util.StatusFromContext(tv.context)
+	}
+
+	digestFunction := tv.treeDigest.GetDigestFunction()
+	for _, entry := range directory.Files {
+		fileDigest, err := digestFunction.NewDigestFromProto(entry.Digest)
+		if err != nil {
+			return util.StatusWrapf(err, "Invalid digest for file %#v in directory %#v", entry.Name, dPath.String())
+		}
+		if _, ok := tv.filesSeen[fileDigest]; ok {
+			continue
+		}
+		tv.filesSeen[fileDigest] = struct{}{}
+		if tv.queue.Length() >= tv.batchSize {
+			if err := tv.flush(); err != nil {
+				return err
+			}
+		}
+		tv.queue.Add(fileDigest)
+	}
+
+	for _, entry := range directory.Directories {
+		name, ok := path.NewComponent(entry.Name)
+		if !ok {
+			return status.Errorf(codes.InvalidArgument, "Directory %#v contains a subdirectory with invalid name %#v", dPath.String(), entry.Name)
+		}
+		childPath := dPath.Append(name)
+		childDigest, err := digestFunction.NewDigestFromProto(entry.Digest)
+		if err != nil {
+			return util.StatusWrapf(err, "Invalid digest for directory %#v", childPath.String())
+		}
+		if _, ok := tv.directoriesSeen[childDigest]; ok {
+			continue
+		}
+		tv.directoriesSeen[childDigest] = struct{}{}
+
+		child, err := tv.directoryFetcher.GetTreeChildDirectory(tv.context, tv.treeDigest, childDigest)
+		if status.Code(err) == codes.InvalidArgument {
+			// The Tree does not contain the directory.
+			tv.missingDirectories.Add(childDigest)
+			continue
+		} else if err != nil {
+			return util.StatusWrapf(err, "Failed to fetch directory %#v", childPath.String())
+		}
+		if err := tv.verifyDirectory(child, childPath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// flush calls FindMissingBlobs() on the files that are currently
+// queued.
+func (tv *treeVerifier) flush() error {
+	if tv.queue.Length() == 0 {
+		return nil
+	}
+	missing, err := tv.contentAddressableStorage.FindMissing(tv.context, tv.queue.Build())
+	if err != nil {
+		return util.StatusWrap(err, "Failed to find missing blobs")
+	}
+	for _, blobDigest := range missing.Items() {
+		tv.missingFiles.Add(blobDigest)
+	}
+	tv.queue = digest.NewSetBuilder()
+	return nil
+}
+
+// getMissingDigests returns the digests of the directories that are not
+// contained in the Tree and the digests of the files that are absent
+// from the Content Addressable Storage, both in sorted order.
+func (tv *treeVerifier) getMissingDigests() ([]*remoteexecution.Digest, []*remoteexecution.Digest) {
+	return getDigestProtos(tv.missingDirectories.Build()), getDigestProtos(tv.missingFiles.Build())
+}
+
+func getDigestProtos(digests digest.Set) []*remoteexecution.Digest {
+	items := digests.Items()
+	protos := make([]*remoteexecution.Digest, 0, len(items))
+	for _, blobDigest := range items {
+		protos = append(protos, blobDigest.GetProto())
+	}
+	return protos
+}
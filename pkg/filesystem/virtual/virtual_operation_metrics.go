@@ -0,0 +1,114 @@
+package virtual
+
+import (
+	"sync"
+	"time"
+
+	"github.com/buildbarn/bb-remote-execution/pkg/filesystem/virtual"
+	"github.com/buildbarn/bb-storage/pkg/clock"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/util"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	virtualOperationPrometheusMetrics sync.Once
+
+	virtualOperationDurationSeconds = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "buildbarn",
+			Subsystem: "clientd",
+			Name:      "virtual_operation_duration_seconds",
+			Help:      "Amount of time spent processing operations against the virtual file system, in seconds.",
+			Buckets:   util.DecimalExponentialBuckets(-6, 7, 2),
+		},
+		[]string{"operation"})
+
+	virtualReadBytesTotal = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "buildbarn",
+			Subsystem: "clientd",
+			Name:      "virtual_read_bytes_total",
+			Help:      "Number of bytes read from files in output paths that are backed by the Content Addressable Storage.",
+		})
+)
+
+// virtualOperationTimer records the duration of a single type of
+// operation against the virtual file system in a Prometheus histogram.
+// The number of operations can be derived from the histogram's count.
+type virtualOperationTimer struct {
+	clock    clock.Clock
+	observer prometheus.Observer
+}
+
+func newVirtualOperationTimer(clock clock.Clock, operation string) virtualOperationTimer {
+	virtualOperationPrometheusMetrics.Do(func() {
+		prometheus.MustRegister(virtualOperationDurationSeconds)
+		prometheus.MustRegister(virtualReadBytesTotal)
+	})
+
+	return virtualOperationTimer{
+		clock:    clock,
+		observer: virtualOperationDurationSeconds.WithLabelValues(operation),
+	}
+}
+
+// observe records the duration of an operation that started at a
+// given point in time. It is intended to be called using defer.
+func (t virtualOperationTimer) observe(timeStart time.Time) {
+	t.observer.Observe(t.clock.Now().Sub(timeStart).Seconds())
+}
+
+// virtualOperationTimers contains the timers for operations against
+// the root directory of the Remote Output Service.
+type virtualOperationTimers struct {
+	getAttributes virtualOperationTimer
+	lookup        virtualOperationTimer
+	openChild     virtualOperationTimer
+	readDir       virtualOperationTimer
+}
+
+func newVirtualOperationTimers(clock clock.Clock) virtualOperationTimers {
+	return virtualOperationTimers{
+		getAttributes: newVirtualOperationTimer(clock, "GetAttributes"),
+		lookup:        newVirtualOperationTimer(clock, "Lookup"),
+		openChild:     newVirtualOperationTimer(clock, "OpenChild"),
+		readDir:       newVirtualOperationTimer(clock, "ReadDir"),
+	}
+}
+
+// readMeasuringCASFileFactory is a decorator for CASFileFactory that
+// records the duration and size of reads against the files that it
+// creates. This allows operators to distinguish workloads that are
+// dominated by reading file contents from ones that are dominated by
+// metadata operations.
+type readMeasuringCASFileFactory struct {
+	base  virtual.CASFileFactory
+	timer virtualOperationTimer
+}
+
+func newReadMeasuringCASFileFactory(base virtual.CASFileFactory, clock clock.Clock) virtual.CASFileFactory {
+	return &readMeasuringCASFileFactory{
+		base:  base,
+		timer: newVirtualOperationTimer(clock, "Read"),
+	}
+}
+
+func (cff *readMeasuringCASFileFactory) LookupFile(blobDigest digest.Digest, isExecutable bool, readMonitor virtual.FileReadMonitor) virtual.NativeLeaf {
+	return &readMeasuringNativeLeaf{
+		NativeLeaf: cff.base.LookupFile(blobDigest, isExecutable, readMonitor),
+		timer:      cff.timer,
+	}
+}
+
+type readMeasuringNativeLeaf struct {
+	virtual.NativeLeaf
+	timer virtualOperationTimer
+}
+
+func (l *readMeasuringNativeLeaf) VirtualRead(buf []byte, offset uint64) (int, bool, virtual.Status) {
+	defer l.timer.observe(l.timer.clock.Now())
+	n, eof, s := l.NativeLeaf.VirtualRead(buf, offset)
+	virtualReadBytesTotal.Add(float64(n))
+	return n, eof, s
+}
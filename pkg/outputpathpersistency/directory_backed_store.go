@@ -29,7 +29,9 @@ func NewDirectoryBackedStore(directory filesystem.Directory, maximumStateFileSiz
 
 func (s *directoryBackedStore) Read(outputBaseID path.Component) (ReadCloser, *outputpathpersistency.RootDirectory, error) {
 	f, err := s.directory.OpenRead(outputBaseID)
-	if err != nil {
+	if err == syscall.ENOENT {
+		return nil, nil, util.StatusWrapWithCode(err, codes.NotFound, "Failed to open state file")
+	} else if err != nil {
 		return nil, nil, util.StatusWrap(err, "Failed to open state file")
 	}
 	reader, rootDirectory, err := NewFileReader(f, s.maximumStateFileSizeBytes)
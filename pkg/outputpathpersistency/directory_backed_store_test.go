@@ -32,6 +32,16 @@ func TestDirectoryBackedStoreRead(t *testing.T) {
 		testutil.RequireEqualStatus(t, status.Error(codes.NotFound, "Failed to open state file: File not found"), err)
 	})
 
+	t.Run("ENOENT", func(t *testing.T) {
+		// Absence of the state file should be reported as
+		// NOT_FOUND, so that callers can distinguish it from
+		// other failures.
+		directory.EXPECT().OpenRead(outputBaseID).Return(nil, syscall.ENOENT)
+
+		_, _, err := store.Read(outputBaseID)
+		testutil.RequireEqualStatus(t, status.Error(codes.NotFound, "Failed to open state file: no such file or directory"), err)
+	})
+
 	t.Run("ReadError", func(t *testing.T) {
 		fileReader := mock.NewMockFileReader(ctrl)
 		directory.EXPECT().OpenRead(outputBaseID).Return(fileReader, nil)
@@ -0,0 +1,9310 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.31.0
+// 	protoc        v4.23.4
+// source: pkg/proto/outputpaths/outputpaths.proto
+
+package outputpaths
+
+import (
+	context "context"
+	v2 "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	remoteoutputservice "github.com/buildbarn/bb-remote-execution/pkg/proto/remoteoutputservice"
+	status "google.golang.org/genproto/googleapis/rpc/status"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status1 "google.golang.org/grpc/status"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type FetchedObjectType int32
+
+const (
+	FetchedObjectType_UNKNOWN FetchedObjectType = 0
+	FetchedObjectType_BLOB    FetchedObjectType = 1
+	FetchedObjectType_TREE    FetchedObjectType = 2
+)
+
+// Enum value maps for FetchedObjectType.
+var (
+	FetchedObjectType_name = map[int32]string{
+		0: "UNKNOWN",
+		1: "BLOB",
+		2: "TREE",
+	}
+	FetchedObjectType_value = map[string]int32{
+		"UNKNOWN": 0,
+		"BLOB":    1,
+		"TREE":    2,
+	}
+)
+
+func (x FetchedObjectType) Enum() *FetchedObjectType {
+	p := new(FetchedObjectType)
+	*p = x
+	return p
+}
+
+func (x FetchedObjectType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (FetchedObjectType) Descriptor() protoreflect.EnumDescriptor {
+	return file_pkg_proto_outputpaths_outputpaths_proto_enumTypes[0].Descriptor()
+}
+
+func (FetchedObjectType) Type() protoreflect.EnumType {
+	return &file_pkg_proto_outputpaths_outputpaths_proto_enumTypes[0]
+}
+
+func (x FetchedObjectType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use FetchedObjectType.Descriptor instead.
+func (FetchedObjectType) EnumDescriptor() ([]byte, []int) {
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP(), []int{0}
+}
+
+type ExternalPathPolicy int32
+
+const (
+	ExternalPathPolicy_RETURN_EXTERNAL                  ExternalPathPolicy = 0
+	ExternalPathPolicy_RETURN_ERROR                     ExternalPathPolicy = 1
+	ExternalPathPolicy_RETURN_RAW_TARGET                ExternalPathPolicy = 2
+	ExternalPathPolicy_RETURN_ERROR_ON_PARENT_TRAVERSAL ExternalPathPolicy = 3
+)
+
+// Enum value maps for ExternalPathPolicy.
+var (
+	ExternalPathPolicy_name = map[int32]string{
+		0: "RETURN_EXTERNAL",
+		1: "RETURN_ERROR",
+		2: "RETURN_RAW_TARGET",
+		3: "RETURN_ERROR_ON_PARENT_TRAVERSAL",
+	}
+	ExternalPathPolicy_value = map[string]int32{
+		"RETURN_EXTERNAL":                  0,
+		"RETURN_ERROR":                     1,
+		"RETURN_RAW_TARGET":                2,
+		"RETURN_ERROR_ON_PARENT_TRAVERSAL": 3,
+	}
+)
+
+func (x ExternalPathPolicy) Enum() *ExternalPathPolicy {
+	p := new(ExternalPathPolicy)
+	*p = x
+	return p
+}
+
+func (x ExternalPathPolicy) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ExternalPathPolicy) Descriptor() protoreflect.EnumDescriptor {
+	return file_pkg_proto_outputpaths_outputpaths_proto_enumTypes[1].Descriptor()
+}
+
+func (ExternalPathPolicy) Type() protoreflect.EnumType {
+	return &file_pkg_proto_outputpaths_outputpaths_proto_enumTypes[1]
+}
+
+func (x ExternalPathPolicy) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ExternalPathPolicy.Descriptor instead.
+func (ExternalPathPolicy) EnumDescriptor() ([]byte, []int) {
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP(), []int{1}
+}
+
+type BuildEvent_Type int32
+
+const (
+	BuildEvent_UNKNOWN             BuildEvent_Type = 0
+	BuildEvent_BUILD_STARTED       BuildEvent_Type = 1
+	BuildEvent_BUILD_FINALIZED     BuildEvent_Type = 2
+	BuildEvent_BUILD_ABORTED       BuildEvent_Type = 3
+	BuildEvent_OUTPUT_BASE_CLEANED BuildEvent_Type = 4
+	BuildEvent_ENTRY_REMOVED       BuildEvent_Type = 5
+)
+
+// Enum value maps for BuildEvent_Type.
+var (
+	BuildEvent_Type_name = map[int32]string{
+		0: "UNKNOWN",
+		1: "BUILD_STARTED",
+		2: "BUILD_FINALIZED",
+		3: "BUILD_ABORTED",
+		4: "OUTPUT_BASE_CLEANED",
+		5: "ENTRY_REMOVED",
+	}
+	BuildEvent_Type_value = map[string]int32{
+		"UNKNOWN":             0,
+		"BUILD_STARTED":       1,
+		"BUILD_FINALIZED":     2,
+		"BUILD_ABORTED":       3,
+		"OUTPUT_BASE_CLEANED": 4,
+		"ENTRY_REMOVED":       5,
+	}
+)
+
+func (x BuildEvent_Type) Enum() *BuildEvent_Type {
+	p := new(BuildEvent_Type)
+	*p = x
+	return p
+}
+
+func (x BuildEvent_Type) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (BuildEvent_Type) Descriptor() protoreflect.EnumDescriptor {
+	return file_pkg_proto_outputpaths_outputpaths_proto_enumTypes[2].Descriptor()
+}
+
+func (BuildEvent_Type) Type() protoreflect.EnumType {
+	return &file_pkg_proto_outputpaths_outputpaths_proto_enumTypes[2]
+}
+
+func (x BuildEvent_Type) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use BuildEvent_Type.Descriptor instead.
+func (BuildEvent_Type) EnumDescriptor() ([]byte, []int) {
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP(), []int{2, 0}
+}
+
+type BuildEvent_EntryRemoval_Reason int32
+
+const (
+	BuildEvent_EntryRemoval_UNKNOWN               BuildEvent_EntryRemoval_Reason = 0
+	BuildEvent_EntryRemoval_MISSING               BuildEvent_EntryRemoval_Reason = 1
+	BuildEvent_EntryRemoval_INCOMPATIBLE_DIGEST   BuildEvent_EntryRemoval_Reason = 2
+	BuildEvent_EntryRemoval_COPY_FAILED           BuildEvent_EntryRemoval_Reason = 3
+	BuildEvent_EntryRemoval_DIRECTORY_UNAVAILABLE BuildEvent_EntryRemoval_Reason = 4
+)
+
+// Enum value maps for BuildEvent_EntryRemoval_Reason.
+var (
+	BuildEvent_EntryRemoval_Reason_name = map[int32]string{
+		0: "UNKNOWN",
+		1: "MISSING",
+		2: "INCOMPATIBLE_DIGEST",
+		3: "COPY_FAILED",
+		4: "DIRECTORY_UNAVAILABLE",
+	}
+	BuildEvent_EntryRemoval_Reason_value = map[string]int32{
+		"UNKNOWN":               0,
+		"MISSING":               1,
+		"INCOMPATIBLE_DIGEST":   2,
+		"COPY_FAILED":           3,
+		"DIRECTORY_UNAVAILABLE": 4,
+	}
+)
+
+func (x BuildEvent_EntryRemoval_Reason) Enum() *BuildEvent_EntryRemoval_Reason {
+	p := new(BuildEvent_EntryRemoval_Reason)
+	*p = x
+	return p
+}
+
+func (x BuildEvent_EntryRemoval_Reason) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (BuildEvent_EntryRemoval_Reason) Descriptor() protoreflect.EnumDescriptor {
+	return file_pkg_proto_outputpaths_outputpaths_proto_enumTypes[3].Descriptor()
+}
+
+func (BuildEvent_EntryRemoval_Reason) Type() protoreflect.EnumType {
+	return &file_pkg_proto_outputpaths_outputpaths_proto_enumTypes[3]
+}
+
+func (x BuildEvent_EntryRemoval_Reason) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use BuildEvent_EntryRemoval_Reason.Descriptor instead.
+func (BuildEvent_EntryRemoval_Reason) EnumDescriptor() ([]byte, []int) {
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP(), []int{2, 0, 0}
+}
+
+type StatDigestResponse_FileType int32
+
+const (
+	StatDigestResponse_FILE      StatDigestResponse_FileType = 0
+	StatDigestResponse_DIRECTORY StatDigestResponse_FileType = 1
+	StatDigestResponse_SYMLINK   StatDigestResponse_FileType = 2
+	StatDigestResponse_EXTERNAL  StatDigestResponse_FileType = 3
+)
+
+// Enum value maps for StatDigestResponse_FileType.
+var (
+	StatDigestResponse_FileType_name = map[int32]string{
+		0: "FILE",
+		1: "DIRECTORY",
+		2: "SYMLINK",
+		3: "EXTERNAL",
+	}
+	StatDigestResponse_FileType_value = map[string]int32{
+		"FILE":      0,
+		"DIRECTORY": 1,
+		"SYMLINK":   2,
+		"EXTERNAL":  3,
+	}
+)
+
+func (x StatDigestResponse_FileType) Enum() *StatDigestResponse_FileType {
+	p := new(StatDigestResponse_FileType)
+	*p = x
+	return p
+}
+
+func (x StatDigestResponse_FileType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (StatDigestResponse_FileType) Descriptor() protoreflect.EnumDescriptor {
+	return file_pkg_proto_outputpaths_outputpaths_proto_enumTypes[4].Descriptor()
+}
+
+func (StatDigestResponse_FileType) Type() protoreflect.EnumType {
+	return &file_pkg_proto_outputpaths_outputpaths_proto_enumTypes[4]
+}
+
+func (x StatDigestResponse_FileType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use StatDigestResponse_FileType.Descriptor instead.
+func (StatDigestResponse_FileType) EnumDescriptor() ([]byte, []int) {
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP(), []int{56, 0}
+}
+
+type WatchBuildsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Start:
+	//	*WatchBuildsRequest_Now
+	//	*WatchBuildsRequest_AfterCursor
+	Start isWatchBuildsRequest_Start `protobuf_oneof:"start"`
+}
+
+func (x *WatchBuildsRequest) Reset() {
+	*x = WatchBuildsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WatchBuildsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchBuildsRequest) ProtoMessage() {}
+
+func (x *WatchBuildsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchBuildsRequest.ProtoReflect.Descriptor instead.
+func (*WatchBuildsRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP(), []int{0}
+}
+
+func (m *WatchBuildsRequest) GetStart() isWatchBuildsRequest_Start {
+	if m != nil {
+		return m.Start
+	}
+	return nil
+}
+
+func (x *WatchBuildsRequest) GetNow() *emptypb.Empty {
+	if x, ok := x.GetStart().(*WatchBuildsRequest_Now); ok {
+		return x.Now
+	}
+	return nil
+}
+
+func (x *WatchBuildsRequest) GetAfterCursor() uint64 {
+	if x, ok := x.GetStart().(*WatchBuildsRequest_AfterCursor); ok {
+		return x.AfterCursor
+	}
+	return 0
+}
+
+type isWatchBuildsRequest_Start interface {
+	isWatchBuildsRequest_Start()
+}
+
+type WatchBuildsRequest_Now struct {
+	Now *emptypb.Empty `protobuf:"bytes,1,opt,name=now,proto3,oneof"`
+}
+
+type WatchBuildsRequest_AfterCursor struct {
+	AfterCursor uint64 `protobuf:"varint,2,opt,name=after_cursor,json=afterCursor,proto3,oneof"`
+}
+
+func (*WatchBuildsRequest_Now) isWatchBuildsRequest_Start() {}
+
+func (*WatchBuildsRequest_AfterCursor) isWatchBuildsRequest_Start() {}
+
+type WatchBuildsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Events []*BuildEvent `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+}
+
+func (x *WatchBuildsResponse) Reset() {
+	*x = WatchBuildsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WatchBuildsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchBuildsResponse) ProtoMessage() {}
+
+func (x *WatchBuildsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchBuildsResponse.ProtoReflect.Descriptor instead.
+func (*WatchBuildsResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *WatchBuildsResponse) GetEvents() []*BuildEvent {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+type BuildEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Cursor       uint64                   `protobuf:"varint,1,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	Type         BuildEvent_Type          `protobuf:"varint,2,opt,name=type,proto3,enum=buildbarn.outputpaths.BuildEvent_Type" json:"type,omitempty"`
+	OutputBaseId string                   `protobuf:"bytes,3,opt,name=output_base_id,json=outputBaseId,proto3" json:"output_base_id,omitempty"`
+	BuildId      string                   `protobuf:"bytes,4,opt,name=build_id,json=buildId,proto3" json:"build_id,omitempty"`
+	Timestamp    *timestamppb.Timestamp   `protobuf:"bytes,5,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	EntryRemoval *BuildEvent_EntryRemoval `protobuf:"bytes,6,opt,name=entry_removal,json=entryRemoval,proto3" json:"entry_removal,omitempty"`
+}
+
+func (x *BuildEvent) Reset() {
+	*x = BuildEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BuildEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BuildEvent) ProtoMessage() {}
+
+func (x *BuildEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BuildEvent.ProtoReflect.Descriptor instead.
+func (*BuildEvent) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *BuildEvent) GetCursor() uint64 {
+	if x != nil {
+		return x.Cursor
+	}
+	return 0
+}
+
+func (x *BuildEvent) GetType() BuildEvent_Type {
+	if x != nil {
+		return x.Type
+	}
+	return BuildEvent_UNKNOWN
+}
+
+func (x *BuildEvent) GetOutputBaseId() string {
+	if x != nil {
+		return x.OutputBaseId
+	}
+	return ""
+}
+
+func (x *BuildEvent) GetBuildId() string {
+	if x != nil {
+		return x.BuildId
+	}
+	return ""
+}
+
+func (x *BuildEvent) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *BuildEvent) GetEntryRemoval() *BuildEvent_EntryRemoval {
+	if x != nil {
+		return x.EntryRemoval
+	}
+	return nil
+}
+
+type ListOutputPathsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Since *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=since,proto3" json:"since,omitempty"`
+}
+
+func (x *ListOutputPathsRequest) Reset() {
+	*x = ListOutputPathsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListOutputPathsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListOutputPathsRequest) ProtoMessage() {}
+
+func (x *ListOutputPathsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListOutputPathsRequest.ProtoReflect.Descriptor instead.
+func (*ListOutputPathsRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListOutputPathsRequest) GetSince() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Since
+	}
+	return nil
+}
+
+type ListOutputPathsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	OutputPaths []*ListOutputPathsResponse_OutputPath `protobuf:"bytes,1,rep,name=output_paths,json=outputPaths,proto3" json:"output_paths,omitempty"`
+}
+
+func (x *ListOutputPathsResponse) Reset() {
+	*x = ListOutputPathsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListOutputPathsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListOutputPathsResponse) ProtoMessage() {}
+
+func (x *ListOutputPathsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListOutputPathsResponse.ProtoReflect.Descriptor instead.
+func (*ListOutputPathsResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListOutputPathsResponse) GetOutputPaths() []*ListOutputPathsResponse_OutputPath {
+	if x != nil {
+		return x.OutputPaths
+	}
+	return nil
+}
+
+type StatStreamResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Index    uint32                            `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Response *remoteoutputservice.StatResponse `protobuf:"bytes,2,opt,name=response,proto3" json:"response,omitempty"`
+}
+
+func (x *StatStreamResponse) Reset() {
+	*x = StatStreamResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StatStreamResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatStreamResponse) ProtoMessage() {}
+
+func (x *StatStreamResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatStreamResponse.ProtoReflect.Descriptor instead.
+func (*StatStreamResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *StatStreamResponse) GetIndex() uint32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *StatStreamResponse) GetResponse() *remoteoutputservice.StatResponse {
+	if x != nil {
+		return x.Response
+	}
+	return nil
+}
+
+type GetActiveBuildRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	OutputBaseId string `protobuf:"bytes,1,opt,name=output_base_id,json=outputBaseId,proto3" json:"output_base_id,omitempty"`
+}
+
+func (x *GetActiveBuildRequest) Reset() {
+	*x = GetActiveBuildRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetActiveBuildRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetActiveBuildRequest) ProtoMessage() {}
+
+func (x *GetActiveBuildRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetActiveBuildRequest.ProtoReflect.Descriptor instead.
+func (*GetActiveBuildRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetActiveBuildRequest) GetOutputBaseId() string {
+	if x != nil {
+		return x.OutputBaseId
+	}
+	return ""
+}
+
+type GetActiveBuildResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ActiveBuild *ActiveBuild `protobuf:"bytes,1,opt,name=active_build,json=activeBuild,proto3" json:"active_build,omitempty"`
+}
+
+func (x *GetActiveBuildResponse) Reset() {
+	*x = GetActiveBuildResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetActiveBuildResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetActiveBuildResponse) ProtoMessage() {}
+
+func (x *GetActiveBuildResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetActiveBuildResponse.ProtoReflect.Descriptor instead.
+func (*GetActiveBuildResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetActiveBuildResponse) GetActiveBuild() *ActiveBuild {
+	if x != nil {
+		return x.ActiveBuild
+	}
+	return nil
+}
+
+type ActiveBuild struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BuildId            string                  `protobuf:"bytes,1,opt,name=build_id,json=buildId,proto3" json:"build_id,omitempty"`
+	StartTime          *timestamppb.Timestamp  `protobuf:"bytes,2,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	InstanceName       string                  `protobuf:"bytes,3,opt,name=instance_name,json=instanceName,proto3" json:"instance_name,omitempty"`
+	DigestFunction     v2.DigestFunction_Value `protobuf:"varint,4,opt,name=digest_function,json=digestFunction,proto3,enum=build.bazel.remote.execution.v2.DigestFunction_Value" json:"digest_function,omitempty"`
+	CasBytesFetched    uint64                  `protobuf:"varint,5,opt,name=cas_bytes_fetched,json=casBytesFetched,proto3" json:"cas_bytes_fetched,omitempty"`
+	ClientMetadata     map[string]string       `protobuf:"bytes,6,rep,name=client_metadata,json=clientMetadata,proto3" json:"client_metadata,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	FindMissingCalls   uint64                  `protobuf:"varint,7,opt,name=find_missing_calls,json=findMissingCalls,proto3" json:"find_missing_calls,omitempty"`
+	BlobsChecked       uint64                  `protobuf:"varint,8,opt,name=blobs_checked,json=blobsChecked,proto3" json:"blobs_checked,omitempty"`
+	StartBuildProgress *StartBuildProgress     `protobuf:"bytes,9,opt,name=start_build_progress,json=startBuildProgress,proto3" json:"start_build_progress,omitempty"`
+}
+
+func (x *ActiveBuild) Reset() {
+	*x = ActiveBuild{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ActiveBuild) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ActiveBuild) ProtoMessage() {}
+
+func (x *ActiveBuild) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ActiveBuild.ProtoReflect.Descriptor instead.
+func (*ActiveBuild) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ActiveBuild) GetBuildId() string {
+	if x != nil {
+		return x.BuildId
+	}
+	return ""
+}
+
+func (x *ActiveBuild) GetStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *ActiveBuild) GetInstanceName() string {
+	if x != nil {
+		return x.InstanceName
+	}
+	return ""
+}
+
+func (x *ActiveBuild) GetDigestFunction() v2.DigestFunction_Value {
+	if x != nil {
+		return x.DigestFunction
+	}
+	return v2.DigestFunction_Value(0)
+}
+
+func (x *ActiveBuild) GetCasBytesFetched() uint64 {
+	if x != nil {
+		return x.CasBytesFetched
+	}
+	return 0
+}
+
+func (x *ActiveBuild) GetClientMetadata() map[string]string {
+	if x != nil {
+		return x.ClientMetadata
+	}
+	return nil
+}
+
+func (x *ActiveBuild) GetFindMissingCalls() uint64 {
+	if x != nil {
+		return x.FindMissingCalls
+	}
+	return 0
+}
+
+func (x *ActiveBuild) GetBlobsChecked() uint64 {
+	if x != nil {
+		return x.BlobsChecked
+	}
+	return 0
+}
+
+func (x *ActiveBuild) GetStartBuildProgress() *StartBuildProgress {
+	if x != nil {
+		return x.StartBuildProgress
+	}
+	return nil
+}
+
+type AbortBuildRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BuildId string `protobuf:"bytes,1,opt,name=build_id,json=buildId,proto3" json:"build_id,omitempty"`
+}
+
+func (x *AbortBuildRequest) Reset() {
+	*x = AbortBuildRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AbortBuildRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AbortBuildRequest) ProtoMessage() {}
+
+func (x *AbortBuildRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AbortBuildRequest.ProtoReflect.Descriptor instead.
+func (*AbortBuildRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *AbortBuildRequest) GetBuildId() string {
+	if x != nil {
+		return x.BuildId
+	}
+	return ""
+}
+
+type GetOutputPathErrorsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	OutputBaseId string `protobuf:"bytes,1,opt,name=output_base_id,json=outputBaseId,proto3" json:"output_base_id,omitempty"`
+}
+
+func (x *GetOutputPathErrorsRequest) Reset() {
+	*x = GetOutputPathErrorsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetOutputPathErrorsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOutputPathErrorsRequest) ProtoMessage() {}
+
+func (x *GetOutputPathErrorsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOutputPathErrorsRequest.ProtoReflect.Descriptor instead.
+func (*GetOutputPathErrorsRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GetOutputPathErrorsRequest) GetOutputBaseId() string {
+	if x != nil {
+		return x.OutputBaseId
+	}
+	return ""
+}
+
+type GetOutputPathErrorsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Errors               []*OutputPathError `protobuf:"bytes,1,rep,name=errors,proto3" json:"errors,omitempty"`
+	DiscardedErrorsCount uint64             `protobuf:"varint,2,opt,name=discarded_errors_count,json=discardedErrorsCount,proto3" json:"discarded_errors_count,omitempty"`
+}
+
+func (x *GetOutputPathErrorsResponse) Reset() {
+	*x = GetOutputPathErrorsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetOutputPathErrorsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOutputPathErrorsResponse) ProtoMessage() {}
+
+func (x *GetOutputPathErrorsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOutputPathErrorsResponse.ProtoReflect.Descriptor instead.
+func (*GetOutputPathErrorsResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GetOutputPathErrorsResponse) GetErrors() []*OutputPathError {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+func (x *GetOutputPathErrorsResponse) GetDiscardedErrorsCount() uint64 {
+	if x != nil {
+		return x.DiscardedErrorsCount
+	}
+	return 0
+}
+
+type OutputPathError struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Time              *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=time,proto3" json:"time,omitempty"`
+	Status            *status.Status         `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	FetchedObjectType FetchedObjectType      `protobuf:"varint,3,opt,name=fetched_object_type,json=fetchedObjectType,proto3,enum=buildbarn.outputpaths.FetchedObjectType" json:"fetched_object_type,omitempty"`
+}
+
+func (x *OutputPathError) Reset() {
+	*x = OutputPathError{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *OutputPathError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OutputPathError) ProtoMessage() {}
+
+func (x *OutputPathError) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OutputPathError.ProtoReflect.Descriptor instead.
+func (*OutputPathError) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *OutputPathError) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *OutputPathError) GetStatus() *status.Status {
+	if x != nil {
+		return x.Status
+	}
+	return nil
+}
+
+func (x *OutputPathError) GetFetchedObjectType() FetchedObjectType {
+	if x != nil {
+		return x.FetchedObjectType
+	}
+	return FetchedObjectType_UNKNOWN
+}
+
+type FetchFailure struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ObjectType FetchedObjectType `protobuf:"varint,1,opt,name=object_type,json=objectType,proto3,enum=buildbarn.outputpaths.FetchedObjectType" json:"object_type,omitempty"`
+}
+
+func (x *FetchFailure) Reset() {
+	*x = FetchFailure{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FetchFailure) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FetchFailure) ProtoMessage() {}
+
+func (x *FetchFailure) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FetchFailure.ProtoReflect.Descriptor instead.
+func (*FetchFailure) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *FetchFailure) GetObjectType() FetchedObjectType {
+	if x != nil {
+		return x.ObjectType
+	}
+	return FetchedObjectType_UNKNOWN
+}
+
+type ExportTreeRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BuildId string `protobuf:"bytes,1,opt,name=build_id,json=buildId,proto3" json:"build_id,omitempty"`
+	Path    string `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+}
+
+func (x *ExportTreeRequest) Reset() {
+	*x = ExportTreeRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExportTreeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportTreeRequest) ProtoMessage() {}
+
+func (x *ExportTreeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportTreeRequest.ProtoReflect.Descriptor instead.
+func (*ExportTreeRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ExportTreeRequest) GetBuildId() string {
+	if x != nil {
+		return x.BuildId
+	}
+	return ""
+}
+
+func (x *ExportTreeRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+type ExportTreeResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	TreeDigest *v2.Digest `protobuf:"bytes,1,opt,name=tree_digest,json=treeDigest,proto3" json:"tree_digest,omitempty"`
+}
+
+func (x *ExportTreeResponse) Reset() {
+	*x = ExportTreeResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExportTreeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportTreeResponse) ProtoMessage() {}
+
+func (x *ExportTreeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportTreeResponse.ProtoReflect.Descriptor instead.
+func (*ExportTreeResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ExportTreeResponse) GetTreeDigest() *v2.Digest {
+	if x != nil {
+		return x.TreeDigest
+	}
+	return nil
+}
+
+type CreateRootSymlinkRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BuildId string `protobuf:"bytes,1,opt,name=build_id,json=buildId,proto3" json:"build_id,omitempty"`
+	Name    string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Target  string `protobuf:"bytes,3,opt,name=target,proto3" json:"target,omitempty"`
+}
+
+func (x *CreateRootSymlinkRequest) Reset() {
+	*x = CreateRootSymlinkRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreateRootSymlinkRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateRootSymlinkRequest) ProtoMessage() {}
+
+func (x *CreateRootSymlinkRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateRootSymlinkRequest.ProtoReflect.Descriptor instead.
+func (*CreateRootSymlinkRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *CreateRootSymlinkRequest) GetBuildId() string {
+	if x != nil {
+		return x.BuildId
+	}
+	return ""
+}
+
+func (x *CreateRootSymlinkRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateRootSymlinkRequest) GetTarget() string {
+	if x != nil {
+		return x.Target
+	}
+	return ""
+}
+
+type RemoveRootSymlinkRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BuildId string `protobuf:"bytes,1,opt,name=build_id,json=buildId,proto3" json:"build_id,omitempty"`
+	Name    string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+}
+
+func (x *RemoveRootSymlinkRequest) Reset() {
+	*x = RemoveRootSymlinkRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RemoveRootSymlinkRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveRootSymlinkRequest) ProtoMessage() {}
+
+func (x *RemoveRootSymlinkRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveRootSymlinkRequest.ProtoReflect.Descriptor instead.
+func (*RemoveRootSymlinkRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *RemoveRootSymlinkRequest) GetBuildId() string {
+	if x != nil {
+		return x.BuildId
+	}
+	return ""
+}
+
+func (x *RemoveRootSymlinkRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type BestEffortBatchCreateResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Files       []*status.Status `protobuf:"bytes,1,rep,name=files,proto3" json:"files,omitempty"`
+	Directories []*status.Status `protobuf:"bytes,2,rep,name=directories,proto3" json:"directories,omitempty"`
+	Symlinks    []*status.Status `protobuf:"bytes,3,rep,name=symlinks,proto3" json:"symlinks,omitempty"`
+}
+
+func (x *BestEffortBatchCreateResponse) Reset() {
+	*x = BestEffortBatchCreateResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BestEffortBatchCreateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BestEffortBatchCreateResponse) ProtoMessage() {}
+
+func (x *BestEffortBatchCreateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BestEffortBatchCreateResponse.ProtoReflect.Descriptor instead.
+func (*BestEffortBatchCreateResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *BestEffortBatchCreateResponse) GetFiles() []*status.Status {
+	if x != nil {
+		return x.Files
+	}
+	return nil
+}
+
+func (x *BestEffortBatchCreateResponse) GetDirectories() []*status.Status {
+	if x != nil {
+		return x.Directories
+	}
+	return nil
+}
+
+func (x *BestEffortBatchCreateResponse) GetSymlinks() []*status.Status {
+	if x != nil {
+		return x.Symlinks
+	}
+	return nil
+}
+
+type CreateStreamRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Entry:
+	//	*CreateStreamRequest_Header
+	//	*CreateStreamRequest_File
+	//	*CreateStreamRequest_Directory
+	//	*CreateStreamRequest_Symlink
+	Entry isCreateStreamRequest_Entry `protobuf_oneof:"entry"`
+}
+
+func (x *CreateStreamRequest) Reset() {
+	*x = CreateStreamRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreateStreamRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateStreamRequest) ProtoMessage() {}
+
+func (x *CreateStreamRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateStreamRequest.ProtoReflect.Descriptor instead.
+func (*CreateStreamRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP(), []int{19}
+}
+
+func (m *CreateStreamRequest) GetEntry() isCreateStreamRequest_Entry {
+	if m != nil {
+		return m.Entry
+	}
+	return nil
+}
+
+func (x *CreateStreamRequest) GetHeader() *CreateStreamHeader {
+	if x, ok := x.GetEntry().(*CreateStreamRequest_Header); ok {
+		return x.Header
+	}
+	return nil
+}
+
+func (x *CreateStreamRequest) GetFile() *v2.OutputFile {
+	if x, ok := x.GetEntry().(*CreateStreamRequest_File); ok {
+		return x.File
+	}
+	return nil
+}
+
+func (x *CreateStreamRequest) GetDirectory() *v2.OutputDirectory {
+	if x, ok := x.GetEntry().(*CreateStreamRequest_Directory); ok {
+		return x.Directory
+	}
+	return nil
+}
+
+func (x *CreateStreamRequest) GetSymlink() *v2.OutputSymlink {
+	if x, ok := x.GetEntry().(*CreateStreamRequest_Symlink); ok {
+		return x.Symlink
+	}
+	return nil
+}
+
+type isCreateStreamRequest_Entry interface {
+	isCreateStreamRequest_Entry()
+}
+
+type CreateStreamRequest_Header struct {
+	Header *CreateStreamHeader `protobuf:"bytes,1,opt,name=header,proto3,oneof"`
+}
+
+type CreateStreamRequest_File struct {
+	File *v2.OutputFile `protobuf:"bytes,2,opt,name=file,proto3,oneof"`
+}
+
+type CreateStreamRequest_Directory struct {
+	Directory *v2.OutputDirectory `protobuf:"bytes,3,opt,name=directory,proto3,oneof"`
+}
+
+type CreateStreamRequest_Symlink struct {
+	Symlink *v2.OutputSymlink `protobuf:"bytes,4,opt,name=symlink,proto3,oneof"`
+}
+
+func (*CreateStreamRequest_Header) isCreateStreamRequest_Entry() {}
+
+func (*CreateStreamRequest_File) isCreateStreamRequest_Entry() {}
+
+func (*CreateStreamRequest_Directory) isCreateStreamRequest_Entry() {}
+
+func (*CreateStreamRequest_Symlink) isCreateStreamRequest_Entry() {}
+
+type CreateStreamHeader struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BuildId            string `protobuf:"bytes,1,opt,name=build_id,json=buildId,proto3" json:"build_id,omitempty"`
+	PathPrefix         string `protobuf:"bytes,2,opt,name=path_prefix,json=pathPrefix,proto3" json:"path_prefix,omitempty"`
+	CleanPathPrefix    bool   `protobuf:"varint,3,opt,name=clean_path_prefix,json=cleanPathPrefix,proto3" json:"clean_path_prefix,omitempty"`
+	ReportRemovedPaths bool   `protobuf:"varint,4,opt,name=report_removed_paths,json=reportRemovedPaths,proto3" json:"report_removed_paths,omitempty"`
+}
+
+func (x *CreateStreamHeader) Reset() {
+	*x = CreateStreamHeader{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreateStreamHeader) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateStreamHeader) ProtoMessage() {}
+
+func (x *CreateStreamHeader) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateStreamHeader.ProtoReflect.Descriptor instead.
+func (*CreateStreamHeader) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *CreateStreamHeader) GetBuildId() string {
+	if x != nil {
+		return x.BuildId
+	}
+	return ""
+}
+
+func (x *CreateStreamHeader) GetPathPrefix() string {
+	if x != nil {
+		return x.PathPrefix
+	}
+	return ""
+}
+
+func (x *CreateStreamHeader) GetCleanPathPrefix() bool {
+	if x != nil {
+		return x.CleanPathPrefix
+	}
+	return false
+}
+
+func (x *CreateStreamHeader) GetReportRemovedPaths() bool {
+	if x != nil {
+		return x.ReportRemovedPaths
+	}
+	return false
+}
+
+type CreateStreamResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Files        uint64   `protobuf:"varint,1,opt,name=files,proto3" json:"files,omitempty"`
+	Directories  uint64   `protobuf:"varint,2,opt,name=directories,proto3" json:"directories,omitempty"`
+	Symlinks     uint64   `protobuf:"varint,3,opt,name=symlinks,proto3" json:"symlinks,omitempty"`
+	RemovedPaths []string `protobuf:"bytes,4,rep,name=removed_paths,json=removedPaths,proto3" json:"removed_paths,omitempty"`
+}
+
+func (x *CreateStreamResponse) Reset() {
+	*x = CreateStreamResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreateStreamResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateStreamResponse) ProtoMessage() {}
+
+func (x *CreateStreamResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateStreamResponse.ProtoReflect.Descriptor instead.
+func (*CreateStreamResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *CreateStreamResponse) GetFiles() uint64 {
+	if x != nil {
+		return x.Files
+	}
+	return 0
+}
+
+func (x *CreateStreamResponse) GetDirectories() uint64 {
+	if x != nil {
+		return x.Directories
+	}
+	return 0
+}
+
+func (x *CreateStreamResponse) GetSymlinks() uint64 {
+	if x != nil {
+		return x.Symlinks
+	}
+	return 0
+}
+
+func (x *CreateStreamResponse) GetRemovedPaths() []string {
+	if x != nil {
+		return x.RemovedPaths
+	}
+	return nil
+}
+
+type ExtendedBatchCreateRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Request             *remoteoutputservice.BatchCreateRequest `protobuf:"bytes,1,opt,name=request,proto3" json:"request,omitempty"`
+	ValidateTreeDigests bool                                    `protobuf:"varint,2,opt,name=validate_tree_digests,json=validateTreeDigests,proto3" json:"validate_tree_digests,omitempty"`
+	AllowedScope        string                                  `protobuf:"bytes,3,opt,name=allowed_scope,json=allowedScope,proto3" json:"allowed_scope,omitempty"`
+}
+
+func (x *ExtendedBatchCreateRequest) Reset() {
+	*x = ExtendedBatchCreateRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExtendedBatchCreateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExtendedBatchCreateRequest) ProtoMessage() {}
+
+func (x *ExtendedBatchCreateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExtendedBatchCreateRequest.ProtoReflect.Descriptor instead.
+func (*ExtendedBatchCreateRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *ExtendedBatchCreateRequest) GetRequest() *remoteoutputservice.BatchCreateRequest {
+	if x != nil {
+		return x.Request
+	}
+	return nil
+}
+
+func (x *ExtendedBatchCreateRequest) GetValidateTreeDigests() bool {
+	if x != nil {
+		return x.ValidateTreeDigests
+	}
+	return false
+}
+
+func (x *ExtendedBatchCreateRequest) GetAllowedScope() string {
+	if x != nil {
+		return x.AllowedScope
+	}
+	return ""
+}
+
+type ExtendedBatchCreateResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Directories []*status.Status `protobuf:"bytes,1,rep,name=directories,proto3" json:"directories,omitempty"`
+}
+
+func (x *ExtendedBatchCreateResponse) Reset() {
+	*x = ExtendedBatchCreateResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExtendedBatchCreateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExtendedBatchCreateResponse) ProtoMessage() {}
+
+func (x *ExtendedBatchCreateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExtendedBatchCreateResponse.ProtoReflect.Descriptor instead.
+func (*ExtendedBatchCreateResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *ExtendedBatchCreateResponse) GetDirectories() []*status.Status {
+	if x != nil {
+		return x.Directories
+	}
+	return nil
+}
+
+type GetOutputPathStatsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	OutputBaseId string `protobuf:"bytes,1,opt,name=output_base_id,json=outputBaseId,proto3" json:"output_base_id,omitempty"`
+}
+
+func (x *GetOutputPathStatsRequest) Reset() {
+	*x = GetOutputPathStatsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetOutputPathStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOutputPathStatsRequest) ProtoMessage() {}
+
+func (x *GetOutputPathStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOutputPathStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetOutputPathStatsRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *GetOutputPathStatsRequest) GetOutputBaseId() string {
+	if x != nil {
+		return x.OutputBaseId
+	}
+	return ""
+}
+
+type GetOutputPathStatsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BlobFetchLatency   *FetchLatencyDistribution `protobuf:"bytes,1,opt,name=blob_fetch_latency,json=blobFetchLatency,proto3" json:"blob_fetch_latency,omitempty"`
+	TreeFetchLatency   *FetchLatencyDistribution `protobuf:"bytes,2,opt,name=tree_fetch_latency,json=treeFetchLatency,proto3" json:"tree_fetch_latency,omitempty"`
+	LastValidationTime *timestamppb.Timestamp    `protobuf:"bytes,3,opt,name=last_validation_time,json=lastValidationTime,proto3" json:"last_validation_time,omitempty"`
+}
+
+func (x *GetOutputPathStatsResponse) Reset() {
+	*x = GetOutputPathStatsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetOutputPathStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOutputPathStatsResponse) ProtoMessage() {}
+
+func (x *GetOutputPathStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOutputPathStatsResponse.ProtoReflect.Descriptor instead.
+func (*GetOutputPathStatsResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *GetOutputPathStatsResponse) GetBlobFetchLatency() *FetchLatencyDistribution {
+	if x != nil {
+		return x.BlobFetchLatency
+	}
+	return nil
+}
+
+func (x *GetOutputPathStatsResponse) GetTreeFetchLatency() *FetchLatencyDistribution {
+	if x != nil {
+		return x.TreeFetchLatency
+	}
+	return nil
+}
+
+func (x *GetOutputPathStatsResponse) GetLastValidationTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastValidationTime
+	}
+	return nil
+}
+
+type FetchLatencyDistribution struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Count uint64               `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+	P50   *durationpb.Duration `protobuf:"bytes,2,opt,name=p50,proto3" json:"p50,omitempty"`
+	P95   *durationpb.Duration `protobuf:"bytes,3,opt,name=p95,proto3" json:"p95,omitempty"`
+	P99   *durationpb.Duration `protobuf:"bytes,4,opt,name=p99,proto3" json:"p99,omitempty"`
+}
+
+func (x *FetchLatencyDistribution) Reset() {
+	*x = FetchLatencyDistribution{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FetchLatencyDistribution) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FetchLatencyDistribution) ProtoMessage() {}
+
+func (x *FetchLatencyDistribution) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FetchLatencyDistribution.ProtoReflect.Descriptor instead.
+func (*FetchLatencyDistribution) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *FetchLatencyDistribution) GetCount() uint64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *FetchLatencyDistribution) GetP50() *durationpb.Duration {
+	if x != nil {
+		return x.P50
+	}
+	return nil
+}
+
+func (x *FetchLatencyDistribution) GetP95() *durationpb.Duration {
+	if x != nil {
+		return x.P95
+	}
+	return nil
+}
+
+func (x *FetchLatencyDistribution) GetP99() *durationpb.Duration {
+	if x != nil {
+		return x.P99
+	}
+	return nil
+}
+
+type ExtendedBatchStatRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Request                    *remoteoutputservice.BatchStatRequest `protobuf:"bytes,1,opt,name=request,proto3" json:"request,omitempty"`
+	IncludeDirectoryDigest     bool                                  `protobuf:"varint,2,opt,name=include_directory_digest,json=includeDirectoryDigest,proto3" json:"include_directory_digest,omitempty"`
+	IncludeResolvedPath        bool                                  `protobuf:"varint,3,opt,name=include_resolved_path,json=includeResolvedPath,proto3" json:"include_resolved_path,omitempty"`
+	IncludeSymlinkChain        bool                                  `protobuf:"varint,4,opt,name=include_symlink_chain,json=includeSymlinkChain,proto3" json:"include_symlink_chain,omitempty"`
+	CaseInsensitive            bool                                  `protobuf:"varint,5,opt,name=case_insensitive,json=caseInsensitive,proto3" json:"case_insensitive,omitempty"`
+	BestEffort                 bool                                  `protobuf:"varint,6,opt,name=best_effort,json=bestEffort,proto3" json:"best_effort,omitempty"`
+	IncludeDirectoryEntryCount bool                                  `protobuf:"varint,7,opt,name=include_directory_entry_count,json=includeDirectoryEntryCount,proto3" json:"include_directory_entry_count,omitempty"`
+	DeduplicatePaths           bool                                  `protobuf:"varint,8,opt,name=deduplicate_paths,json=deduplicatePaths,proto3" json:"deduplicate_paths,omitempty"`
+}
+
+func (x *ExtendedBatchStatRequest) Reset() {
+	*x = ExtendedBatchStatRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[27]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExtendedBatchStatRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExtendedBatchStatRequest) ProtoMessage() {}
+
+func (x *ExtendedBatchStatRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[27]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExtendedBatchStatRequest.ProtoReflect.Descriptor instead.
+func (*ExtendedBatchStatRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *ExtendedBatchStatRequest) GetRequest() *remoteoutputservice.BatchStatRequest {
+	if x != nil {
+		return x.Request
+	}
+	return nil
+}
+
+func (x *ExtendedBatchStatRequest) GetIncludeDirectoryDigest() bool {
+	if x != nil {
+		return x.IncludeDirectoryDigest
+	}
+	return false
+}
+
+func (x *ExtendedBatchStatRequest) GetIncludeResolvedPath() bool {
+	if x != nil {
+		return x.IncludeResolvedPath
+	}
+	return false
+}
+
+func (x *ExtendedBatchStatRequest) GetIncludeSymlinkChain() bool {
+	if x != nil {
+		return x.IncludeSymlinkChain
+	}
+	return false
+}
+
+func (x *ExtendedBatchStatRequest) GetCaseInsensitive() bool {
+	if x != nil {
+		return x.CaseInsensitive
+	}
+	return false
+}
+
+func (x *ExtendedBatchStatRequest) GetBestEffort() bool {
+	if x != nil {
+		return x.BestEffort
+	}
+	return false
+}
+
+func (x *ExtendedBatchStatRequest) GetIncludeDirectoryEntryCount() bool {
+	if x != nil {
+		return x.IncludeDirectoryEntryCount
+	}
+	return false
+}
+
+func (x *ExtendedBatchStatRequest) GetDeduplicatePaths() bool {
+	if x != nil {
+		return x.DeduplicatePaths
+	}
+	return false
+}
+
+type ExtendedBatchStatResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Responses []*ExtendedStatResponse `protobuf:"bytes,1,rep,name=responses,proto3" json:"responses,omitempty"`
+}
+
+func (x *ExtendedBatchStatResponse) Reset() {
+	*x = ExtendedBatchStatResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[28]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExtendedBatchStatResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExtendedBatchStatResponse) ProtoMessage() {}
+
+func (x *ExtendedBatchStatResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[28]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExtendedBatchStatResponse.ProtoReflect.Descriptor instead.
+func (*ExtendedBatchStatResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *ExtendedBatchStatResponse) GetResponses() []*ExtendedStatResponse {
+	if x != nil {
+		return x.Responses
+	}
+	return nil
+}
+
+type ExtendedStatResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Response              *remoteoutputservice.StatResponse `protobuf:"bytes,1,opt,name=response,proto3" json:"response,omitempty"`
+	DirectoryDigest       *v2.Digest                        `protobuf:"bytes,2,opt,name=directory_digest,json=directoryDigest,proto3" json:"directory_digest,omitempty"`
+	ResolvedPath          string                            `protobuf:"bytes,3,opt,name=resolved_path,json=resolvedPath,proto3" json:"resolved_path,omitempty"`
+	SymlinkChain          []string                          `protobuf:"bytes,4,rep,name=symlink_chain,json=symlinkChain,proto3" json:"symlink_chain,omitempty"`
+	SymlinkChainTruncated bool                              `protobuf:"varint,5,opt,name=symlink_chain_truncated,json=symlinkChainTruncated,proto3" json:"symlink_chain_truncated,omitempty"`
+	Error                 *status.Status                    `protobuf:"bytes,6,opt,name=error,proto3" json:"error,omitempty"`
+	DirectoryEntryCount   uint64                            `protobuf:"varint,7,opt,name=directory_entry_count,json=directoryEntryCount,proto3" json:"directory_entry_count,omitempty"`
+}
+
+func (x *ExtendedStatResponse) Reset() {
+	*x = ExtendedStatResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[29]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExtendedStatResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExtendedStatResponse) ProtoMessage() {}
+
+func (x *ExtendedStatResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[29]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExtendedStatResponse.ProtoReflect.Descriptor instead.
+func (*ExtendedStatResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *ExtendedStatResponse) GetResponse() *remoteoutputservice.StatResponse {
+	if x != nil {
+		return x.Response
+	}
+	return nil
+}
+
+func (x *ExtendedStatResponse) GetDirectoryDigest() *v2.Digest {
+	if x != nil {
+		return x.DirectoryDigest
+	}
+	return nil
+}
+
+func (x *ExtendedStatResponse) GetResolvedPath() string {
+	if x != nil {
+		return x.ResolvedPath
+	}
+	return ""
+}
+
+func (x *ExtendedStatResponse) GetSymlinkChain() []string {
+	if x != nil {
+		return x.SymlinkChain
+	}
+	return nil
+}
+
+func (x *ExtendedStatResponse) GetSymlinkChainTruncated() bool {
+	if x != nil {
+		return x.SymlinkChainTruncated
+	}
+	return false
+}
+
+func (x *ExtendedStatResponse) GetError() *status.Status {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+func (x *ExtendedStatResponse) GetDirectoryEntryCount() uint64 {
+	if x != nil {
+		return x.DirectoryEntryCount
+	}
+	return 0
+}
+
+type BatchStartBuildRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Requests []*remoteoutputservice.StartBuildRequest `protobuf:"bytes,1,rep,name=requests,proto3" json:"requests,omitempty"`
+}
+
+func (x *BatchStartBuildRequest) Reset() {
+	*x = BatchStartBuildRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[30]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BatchStartBuildRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchStartBuildRequest) ProtoMessage() {}
+
+func (x *BatchStartBuildRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[30]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchStartBuildRequest.ProtoReflect.Descriptor instead.
+func (*BatchStartBuildRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *BatchStartBuildRequest) GetRequests() []*remoteoutputservice.StartBuildRequest {
+	if x != nil {
+		return x.Requests
+	}
+	return nil
+}
+
+type BatchStartBuildResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Responses []*remoteoutputservice.StartBuildResponse `protobuf:"bytes,1,rep,name=responses,proto3" json:"responses,omitempty"`
+}
+
+func (x *BatchStartBuildResponse) Reset() {
+	*x = BatchStartBuildResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[31]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BatchStartBuildResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchStartBuildResponse) ProtoMessage() {}
+
+func (x *BatchStartBuildResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[31]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchStartBuildResponse.ProtoReflect.Descriptor instead.
+func (*BatchStartBuildResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *BatchStartBuildResponse) GetResponses() []*remoteoutputservice.StartBuildResponse {
+	if x != nil {
+		return x.Responses
+	}
+	return nil
+}
+
+type ReadDirectoryRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BuildId           string `protobuf:"bytes,1,opt,name=build_id,json=buildId,proto3" json:"build_id,omitempty"`
+	Path              string `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	IncludeFileDigest bool   `protobuf:"varint,3,opt,name=include_file_digest,json=includeFileDigest,proto3" json:"include_file_digest,omitempty"`
+	PageSize          uint32 `protobuf:"varint,4,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken         string `protobuf:"bytes,5,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+}
+
+func (x *ReadDirectoryRequest) Reset() {
+	*x = ReadDirectoryRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[32]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReadDirectoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReadDirectoryRequest) ProtoMessage() {}
+
+func (x *ReadDirectoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[32]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReadDirectoryRequest.ProtoReflect.Descriptor instead.
+func (*ReadDirectoryRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *ReadDirectoryRequest) GetBuildId() string {
+	if x != nil {
+		return x.BuildId
+	}
+	return ""
+}
+
+func (x *ReadDirectoryRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *ReadDirectoryRequest) GetIncludeFileDigest() bool {
+	if x != nil {
+		return x.IncludeFileDigest
+	}
+	return false
+}
+
+func (x *ReadDirectoryRequest) GetPageSize() uint32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ReadDirectoryRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type ReadDirectoryResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Entries       []*DirectoryEntry `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	NextPageToken string            `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+}
+
+func (x *ReadDirectoryResponse) Reset() {
+	*x = ReadDirectoryResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[33]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReadDirectoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReadDirectoryResponse) ProtoMessage() {}
+
+func (x *ReadDirectoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[33]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReadDirectoryResponse.ProtoReflect.Descriptor instead.
+func (*ReadDirectoryResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *ReadDirectoryResponse) GetEntries() []*DirectoryEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+func (x *ReadDirectoryResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type DirectoryEntry struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name   string                          `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Status *remoteoutputservice.FileStatus `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+}
+
+func (x *DirectoryEntry) Reset() {
+	*x = DirectoryEntry{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[34]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DirectoryEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DirectoryEntry) ProtoMessage() {}
+
+func (x *DirectoryEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[34]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DirectoryEntry.ProtoReflect.Descriptor instead.
+func (*DirectoryEntry) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *DirectoryEntry) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *DirectoryEntry) GetStatus() *remoteoutputservice.FileStatus {
+	if x != nil {
+		return x.Status
+	}
+	return nil
+}
+
+type GetDaemonStatsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	OutputPaths      uint32                    `protobuf:"varint,1,opt,name=output_paths,json=outputPaths,proto3" json:"output_paths,omitempty"`
+	ActiveBuilds     uint32                    `protobuf:"varint,2,opt,name=active_builds,json=activeBuilds,proto3" json:"active_builds,omitempty"`
+	CasFiles         uint64                    `protobuf:"varint,3,opt,name=cas_files,json=casFiles,proto3" json:"cas_files,omitempty"`
+	Goroutines       uint32                    `protobuf:"varint,4,opt,name=goroutines,proto3" json:"goroutines,omitempty"`
+	BlobFetchLatency *FetchLatencyDistribution `protobuf:"bytes,5,opt,name=blob_fetch_latency,json=blobFetchLatency,proto3" json:"blob_fetch_latency,omitempty"`
+	TreeFetchLatency *FetchLatencyDistribution `protobuf:"bytes,6,opt,name=tree_fetch_latency,json=treeFetchLatency,proto3" json:"tree_fetch_latency,omitempty"`
+}
+
+func (x *GetDaemonStatsResponse) Reset() {
+	*x = GetDaemonStatsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[35]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetDaemonStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDaemonStatsResponse) ProtoMessage() {}
+
+func (x *GetDaemonStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[35]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDaemonStatsResponse.ProtoReflect.Descriptor instead.
+func (*GetDaemonStatsResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *GetDaemonStatsResponse) GetOutputPaths() uint32 {
+	if x != nil {
+		return x.OutputPaths
+	}
+	return 0
+}
+
+func (x *GetDaemonStatsResponse) GetActiveBuilds() uint32 {
+	if x != nil {
+		return x.ActiveBuilds
+	}
+	return 0
+}
+
+func (x *GetDaemonStatsResponse) GetCasFiles() uint64 {
+	if x != nil {
+		return x.CasFiles
+	}
+	return 0
+}
+
+func (x *GetDaemonStatsResponse) GetGoroutines() uint32 {
+	if x != nil {
+		return x.Goroutines
+	}
+	return 0
+}
+
+func (x *GetDaemonStatsResponse) GetBlobFetchLatency() *FetchLatencyDistribution {
+	if x != nil {
+		return x.BlobFetchLatency
+	}
+	return nil
+}
+
+func (x *GetDaemonStatsResponse) GetTreeFetchLatency() *FetchLatencyDistribution {
+	if x != nil {
+		return x.TreeFetchLatency
+	}
+	return nil
+}
+
+type SetExternalPathPolicyRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BuildId string             `protobuf:"bytes,1,opt,name=build_id,json=buildId,proto3" json:"build_id,omitempty"`
+	Policy  ExternalPathPolicy `protobuf:"varint,2,opt,name=policy,proto3,enum=buildbarn.outputpaths.ExternalPathPolicy" json:"policy,omitempty"`
+}
+
+func (x *SetExternalPathPolicyRequest) Reset() {
+	*x = SetExternalPathPolicyRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[36]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SetExternalPathPolicyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetExternalPathPolicyRequest) ProtoMessage() {}
+
+func (x *SetExternalPathPolicyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[36]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetExternalPathPolicyRequest.ProtoReflect.Descriptor instead.
+func (*SetExternalPathPolicyRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *SetExternalPathPolicyRequest) GetBuildId() string {
+	if x != nil {
+		return x.BuildId
+	}
+	return ""
+}
+
+func (x *SetExternalPathPolicyRequest) GetPolicy() ExternalPathPolicy {
+	if x != nil {
+		return x.Policy
+	}
+	return ExternalPathPolicy_RETURN_EXTERNAL
+}
+
+type GetDirectoryResidencyRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BuildId string `protobuf:"bytes,1,opt,name=build_id,json=buildId,proto3" json:"build_id,omitempty"`
+	Path    string `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+}
+
+func (x *GetDirectoryResidencyRequest) Reset() {
+	*x = GetDirectoryResidencyRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[37]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetDirectoryResidencyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDirectoryResidencyRequest) ProtoMessage() {}
+
+func (x *GetDirectoryResidencyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[37]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDirectoryResidencyRequest.ProtoReflect.Descriptor instead.
+func (*GetDirectoryResidencyRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *GetDirectoryResidencyRequest) GetBuildId() string {
+	if x != nil {
+		return x.BuildId
+	}
+	return ""
+}
+
+func (x *GetDirectoryResidencyRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+type GetDirectoryResidencyResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	UnloadedDirectories uint64 `protobuf:"varint,1,opt,name=unloaded_directories,json=unloadedDirectories,proto3" json:"unloaded_directories,omitempty"`
+}
+
+func (x *GetDirectoryResidencyResponse) Reset() {
+	*x = GetDirectoryResidencyResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[38]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetDirectoryResidencyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDirectoryResidencyResponse) ProtoMessage() {}
+
+func (x *GetDirectoryResidencyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[38]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDirectoryResidencyResponse.ProtoReflect.Descriptor instead.
+func (*GetDirectoryResidencyResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *GetDirectoryResidencyResponse) GetUnloadedDirectories() uint64 {
+	if x != nil {
+		return x.UnloadedDirectories
+	}
+	return 0
+}
+
+type FinalizeSubtreeRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BuildId string `protobuf:"bytes,1,opt,name=build_id,json=buildId,proto3" json:"build_id,omitempty"`
+	Path    string `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+}
+
+func (x *FinalizeSubtreeRequest) Reset() {
+	*x = FinalizeSubtreeRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[39]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FinalizeSubtreeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FinalizeSubtreeRequest) ProtoMessage() {}
+
+func (x *FinalizeSubtreeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[39]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FinalizeSubtreeRequest.ProtoReflect.Descriptor instead.
+func (*FinalizeSubtreeRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *FinalizeSubtreeRequest) GetBuildId() string {
+	if x != nil {
+		return x.BuildId
+	}
+	return ""
+}
+
+func (x *FinalizeSubtreeRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+type FinalizeSubtreeResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	TreeDigest *v2.Digest `protobuf:"bytes,1,opt,name=tree_digest,json=treeDigest,proto3" json:"tree_digest,omitempty"`
+}
+
+func (x *FinalizeSubtreeResponse) Reset() {
+	*x = FinalizeSubtreeResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[40]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FinalizeSubtreeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FinalizeSubtreeResponse) ProtoMessage() {}
+
+func (x *FinalizeSubtreeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[40]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FinalizeSubtreeResponse.ProtoReflect.Descriptor instead.
+func (*FinalizeSubtreeResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *FinalizeSubtreeResponse) GetTreeDigest() *v2.Digest {
+	if x != nil {
+		return x.TreeDigest
+	}
+	return nil
+}
+
+type SetOutputPathRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BuildId        string     `protobuf:"bytes,1,opt,name=build_id,json=buildId,proto3" json:"build_id,omitempty"`
+	RootTreeDigest *v2.Digest `protobuf:"bytes,2,opt,name=root_tree_digest,json=rootTreeDigest,proto3" json:"root_tree_digest,omitempty"`
+	Overlay        bool       `protobuf:"varint,3,opt,name=overlay,proto3" json:"overlay,omitempty"`
+}
+
+func (x *SetOutputPathRequest) Reset() {
+	*x = SetOutputPathRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[41]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SetOutputPathRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetOutputPathRequest) ProtoMessage() {}
+
+func (x *SetOutputPathRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[41]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetOutputPathRequest.ProtoReflect.Descriptor instead.
+func (*SetOutputPathRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *SetOutputPathRequest) GetBuildId() string {
+	if x != nil {
+		return x.BuildId
+	}
+	return ""
+}
+
+func (x *SetOutputPathRequest) GetRootTreeDigest() *v2.Digest {
+	if x != nil {
+		return x.RootTreeDigest
+	}
+	return nil
+}
+
+func (x *SetOutputPathRequest) GetOverlay() bool {
+	if x != nil {
+		return x.Overlay
+	}
+	return false
+}
+
+type ListFinalizedBuildsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	OutputBaseId string `protobuf:"bytes,1,opt,name=output_base_id,json=outputBaseId,proto3" json:"output_base_id,omitempty"`
+}
+
+func (x *ListFinalizedBuildsRequest) Reset() {
+	*x = ListFinalizedBuildsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[42]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListFinalizedBuildsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListFinalizedBuildsRequest) ProtoMessage() {}
+
+func (x *ListFinalizedBuildsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[42]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListFinalizedBuildsRequest.ProtoReflect.Descriptor instead.
+func (*ListFinalizedBuildsRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *ListFinalizedBuildsRequest) GetOutputBaseId() string {
+	if x != nil {
+		return x.OutputBaseId
+	}
+	return ""
+}
+
+type FinalizedBuild struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BuildId          string                 `protobuf:"bytes,1,opt,name=build_id,json=buildId,proto3" json:"build_id,omitempty"`
+	RootTreeDigest   *v2.Digest             `protobuf:"bytes,2,opt,name=root_tree_digest,json=rootTreeDigest,proto3" json:"root_tree_digest,omitempty"`
+	FinalizeTime     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=finalize_time,json=finalizeTime,proto3" json:"finalize_time,omitempty"`
+	CasBytesFetched  uint64                 `protobuf:"varint,4,opt,name=cas_bytes_fetched,json=casBytesFetched,proto3" json:"cas_bytes_fetched,omitempty"`
+	ClientMetadata   map[string]string      `protobuf:"bytes,5,rep,name=client_metadata,json=clientMetadata,proto3" json:"client_metadata,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	CasFetchFailures uint64                 `protobuf:"varint,6,opt,name=cas_fetch_failures,json=casFetchFailures,proto3" json:"cas_fetch_failures,omitempty"`
+}
+
+func (x *FinalizedBuild) Reset() {
+	*x = FinalizedBuild{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[43]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FinalizedBuild) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FinalizedBuild) ProtoMessage() {}
+
+func (x *FinalizedBuild) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[43]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FinalizedBuild.ProtoReflect.Descriptor instead.
+func (*FinalizedBuild) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *FinalizedBuild) GetBuildId() string {
+	if x != nil {
+		return x.BuildId
+	}
+	return ""
+}
+
+func (x *FinalizedBuild) GetRootTreeDigest() *v2.Digest {
+	if x != nil {
+		return x.RootTreeDigest
+	}
+	return nil
+}
+
+func (x *FinalizedBuild) GetFinalizeTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.FinalizeTime
+	}
+	return nil
+}
+
+func (x *FinalizedBuild) GetCasBytesFetched() uint64 {
+	if x != nil {
+		return x.CasBytesFetched
+	}
+	return 0
+}
+
+func (x *FinalizedBuild) GetClientMetadata() map[string]string {
+	if x != nil {
+		return x.ClientMetadata
+	}
+	return nil
+}
+
+func (x *FinalizedBuild) GetCasFetchFailures() uint64 {
+	if x != nil {
+		return x.CasFetchFailures
+	}
+	return 0
+}
+
+type ListFinalizedBuildsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Builds []*FinalizedBuild `protobuf:"bytes,1,rep,name=builds,proto3" json:"builds,omitempty"`
+}
+
+func (x *ListFinalizedBuildsResponse) Reset() {
+	*x = ListFinalizedBuildsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[44]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListFinalizedBuildsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListFinalizedBuildsResponse) ProtoMessage() {}
+
+func (x *ListFinalizedBuildsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[44]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListFinalizedBuildsResponse.ProtoReflect.Descriptor instead.
+func (*ListFinalizedBuildsResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *ListFinalizedBuildsResponse) GetBuilds() []*FinalizedBuild {
+	if x != nil {
+		return x.Builds
+	}
+	return nil
+}
+
+type SetDefaultPathPrefixRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BuildId    string `protobuf:"bytes,1,opt,name=build_id,json=buildId,proto3" json:"build_id,omitempty"`
+	PathPrefix string `protobuf:"bytes,2,opt,name=path_prefix,json=pathPrefix,proto3" json:"path_prefix,omitempty"`
+}
+
+func (x *SetDefaultPathPrefixRequest) Reset() {
+	*x = SetDefaultPathPrefixRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[45]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SetDefaultPathPrefixRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetDefaultPathPrefixRequest) ProtoMessage() {}
+
+func (x *SetDefaultPathPrefixRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[45]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetDefaultPathPrefixRequest.ProtoReflect.Descriptor instead.
+func (*SetDefaultPathPrefixRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *SetDefaultPathPrefixRequest) GetBuildId() string {
+	if x != nil {
+		return x.BuildId
+	}
+	return ""
+}
+
+func (x *SetDefaultPathPrefixRequest) GetPathPrefix() string {
+	if x != nil {
+		return x.PathPrefix
+	}
+	return ""
+}
+
+type MaterializeRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BuildId             string               `protobuf:"bytes,1,opt,name=build_id,json=buildId,proto3" json:"build_id,omitempty"`
+	Path                string               `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	MaximumBytesFetched uint64               `protobuf:"varint,3,opt,name=maximum_bytes_fetched,json=maximumBytesFetched,proto3" json:"maximum_bytes_fetched,omitempty"`
+	MaximumDuration     *durationpb.Duration `protobuf:"bytes,4,opt,name=maximum_duration,json=maximumDuration,proto3" json:"maximum_duration,omitempty"`
+	MaximumDepth        uint32               `protobuf:"varint,5,opt,name=maximum_depth,json=maximumDepth,proto3" json:"maximum_depth,omitempty"`
+}
+
+func (x *MaterializeRequest) Reset() {
+	*x = MaterializeRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[46]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MaterializeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MaterializeRequest) ProtoMessage() {}
+
+func (x *MaterializeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[46]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MaterializeRequest.ProtoReflect.Descriptor instead.
+func (*MaterializeRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *MaterializeRequest) GetBuildId() string {
+	if x != nil {
+		return x.BuildId
+	}
+	return ""
+}
+
+func (x *MaterializeRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *MaterializeRequest) GetMaximumBytesFetched() uint64 {
+	if x != nil {
+		return x.MaximumBytesFetched
+	}
+	return 0
+}
+
+func (x *MaterializeRequest) GetMaximumDuration() *durationpb.Duration {
+	if x != nil {
+		return x.MaximumDuration
+	}
+	return nil
+}
+
+func (x *MaterializeRequest) GetMaximumDepth() uint32 {
+	if x != nil {
+		return x.MaximumDepth
+	}
+	return 0
+}
+
+type MaterializeResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DirectoriesTraversed          uint64 `protobuf:"varint,1,opt,name=directories_traversed,json=directoriesTraversed,proto3" json:"directories_traversed,omitempty"`
+	FilesTraversed                uint64 `protobuf:"varint,2,opt,name=files_traversed,json=filesTraversed,proto3" json:"files_traversed,omitempty"`
+	BytesFetched                  uint64 `protobuf:"varint,3,opt,name=bytes_fetched,json=bytesFetched,proto3" json:"bytes_fetched,omitempty"`
+	BudgetExhausted               bool   `protobuf:"varint,4,opt,name=budget_exhausted,json=budgetExhausted,proto3" json:"budget_exhausted,omitempty"`
+	DirectoriesBeyondMaximumDepth uint64 `protobuf:"varint,5,opt,name=directories_beyond_maximum_depth,json=directoriesBeyondMaximumDepth,proto3" json:"directories_beyond_maximum_depth,omitempty"`
+}
+
+func (x *MaterializeResponse) Reset() {
+	*x = MaterializeResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[47]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MaterializeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MaterializeResponse) ProtoMessage() {}
+
+func (x *MaterializeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[47]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MaterializeResponse.ProtoReflect.Descriptor instead.
+func (*MaterializeResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *MaterializeResponse) GetDirectoriesTraversed() uint64 {
+	if x != nil {
+		return x.DirectoriesTraversed
+	}
+	return 0
+}
+
+func (x *MaterializeResponse) GetFilesTraversed() uint64 {
+	if x != nil {
+		return x.FilesTraversed
+	}
+	return 0
+}
+
+func (x *MaterializeResponse) GetBytesFetched() uint64 {
+	if x != nil {
+		return x.BytesFetched
+	}
+	return 0
+}
+
+func (x *MaterializeResponse) GetBudgetExhausted() bool {
+	if x != nil {
+		return x.BudgetExhausted
+	}
+	return false
+}
+
+func (x *MaterializeResponse) GetDirectoriesBeyondMaximumDepth() uint64 {
+	if x != nil {
+		return x.DirectoriesBeyondMaximumDepth
+	}
+	return 0
+}
+
+type ReopenBuildRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	OutputBaseId string `protobuf:"bytes,1,opt,name=output_base_id,json=outputBaseId,proto3" json:"output_base_id,omitempty"`
+	BuildId      string `protobuf:"bytes,2,opt,name=build_id,json=buildId,proto3" json:"build_id,omitempty"`
+}
+
+func (x *ReopenBuildRequest) Reset() {
+	*x = ReopenBuildRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[48]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReopenBuildRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReopenBuildRequest) ProtoMessage() {}
+
+func (x *ReopenBuildRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[48]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReopenBuildRequest.ProtoReflect.Descriptor instead.
+func (*ReopenBuildRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *ReopenBuildRequest) GetOutputBaseId() string {
+	if x != nil {
+		return x.OutputBaseId
+	}
+	return ""
+}
+
+func (x *ReopenBuildRequest) GetBuildId() string {
+	if x != nil {
+		return x.BuildId
+	}
+	return ""
+}
+
+type InvalidateCacheRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	OutputBaseId string `protobuf:"bytes,1,opt,name=output_base_id,json=outputBaseId,proto3" json:"output_base_id,omitempty"`
+	Path         string `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+}
+
+func (x *InvalidateCacheRequest) Reset() {
+	*x = InvalidateCacheRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[49]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *InvalidateCacheRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InvalidateCacheRequest) ProtoMessage() {}
+
+func (x *InvalidateCacheRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[49]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InvalidateCacheRequest.ProtoReflect.Descriptor instead.
+func (*InvalidateCacheRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *InvalidateCacheRequest) GetOutputBaseId() string {
+	if x != nil {
+		return x.OutputBaseId
+	}
+	return ""
+}
+
+func (x *InvalidateCacheRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+type StartBuildStreamResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Event:
+	//	*StartBuildStreamResponse_Progress
+	//	*StartBuildStreamResponse_Response
+	Event isStartBuildStreamResponse_Event `protobuf_oneof:"event"`
+}
+
+func (x *StartBuildStreamResponse) Reset() {
+	*x = StartBuildStreamResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[50]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StartBuildStreamResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartBuildStreamResponse) ProtoMessage() {}
+
+func (x *StartBuildStreamResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[50]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartBuildStreamResponse.ProtoReflect.Descriptor instead.
+func (*StartBuildStreamResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP(), []int{50}
+}
+
+func (m *StartBuildStreamResponse) GetEvent() isStartBuildStreamResponse_Event {
+	if m != nil {
+		return m.Event
+	}
+	return nil
+}
+
+func (x *StartBuildStreamResponse) GetProgress() *StartBuildProgress {
+	if x, ok := x.GetEvent().(*StartBuildStreamResponse_Progress); ok {
+		return x.Progress
+	}
+	return nil
+}
+
+func (x *StartBuildStreamResponse) GetResponse() *remoteoutputservice.StartBuildResponse {
+	if x, ok := x.GetEvent().(*StartBuildStreamResponse_Response); ok {
+		return x.Response
+	}
+	return nil
+}
+
+type isStartBuildStreamResponse_Event interface {
+	isStartBuildStreamResponse_Event()
+}
+
+type StartBuildStreamResponse_Progress struct {
+	Progress *StartBuildProgress `protobuf:"bytes,1,opt,name=progress,proto3,oneof"`
+}
+
+type StartBuildStreamResponse_Response struct {
+	Response *remoteoutputservice.StartBuildResponse `protobuf:"bytes,2,opt,name=response,proto3,oneof"`
+}
+
+func (*StartBuildStreamResponse_Progress) isStartBuildStreamResponse_Event() {}
+
+func (*StartBuildStreamResponse_Response) isStartBuildStreamResponse_Event() {}
+
+type StartBuildProgress struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	EntriesScanned   uint64 `protobuf:"varint,1,opt,name=entries_scanned,json=entriesScanned,proto3" json:"entries_scanned,omitempty"`
+	BlobsChecked     uint64 `protobuf:"varint,2,opt,name=blobs_checked,json=blobsChecked,proto3" json:"blobs_checked,omitempty"`
+	EntriesRemoved   uint64 `protobuf:"varint,3,opt,name=entries_removed,json=entriesRemoved,proto3" json:"entries_removed,omitempty"`
+	FindMissingCalls uint64 `protobuf:"varint,4,opt,name=find_missing_calls,json=findMissingCalls,proto3" json:"find_missing_calls,omitempty"`
+	QueuedDigests    uint64 `protobuf:"varint,5,opt,name=queued_digests,json=queuedDigests,proto3" json:"queued_digests,omitempty"`
+}
+
+func (x *StartBuildProgress) Reset() {
+	*x = StartBuildProgress{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[51]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StartBuildProgress) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartBuildProgress) ProtoMessage() {}
+
+func (x *StartBuildProgress) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[51]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartBuildProgress.ProtoReflect.Descriptor instead.
+func (*StartBuildProgress) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *StartBuildProgress) GetEntriesScanned() uint64 {
+	if x != nil {
+		return x.EntriesScanned
+	}
+	return 0
+}
+
+func (x *StartBuildProgress) GetBlobsChecked() uint64 {
+	if x != nil {
+		return x.BlobsChecked
+	}
+	return 0
+}
+
+func (x *StartBuildProgress) GetEntriesRemoved() uint64 {
+	if x != nil {
+		return x.EntriesRemoved
+	}
+	return 0
+}
+
+func (x *StartBuildProgress) GetFindMissingCalls() uint64 {
+	if x != nil {
+		return x.FindMissingCalls
+	}
+	return 0
+}
+
+func (x *StartBuildProgress) GetQueuedDigests() uint64 {
+	if x != nil {
+		return x.QueuedDigests
+	}
+	return 0
+}
+
+type CleanStreamResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	EntriesRemoved uint64 `protobuf:"varint,1,opt,name=entries_removed,json=entriesRemoved,proto3" json:"entries_removed,omitempty"`
+}
+
+func (x *CleanStreamResponse) Reset() {
+	*x = CleanStreamResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[52]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CleanStreamResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CleanStreamResponse) ProtoMessage() {}
+
+func (x *CleanStreamResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[52]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CleanStreamResponse.ProtoReflect.Descriptor instead.
+func (*CleanStreamResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *CleanStreamResponse) GetEntriesRemoved() uint64 {
+	if x != nil {
+		return x.EntriesRemoved
+	}
+	return 0
+}
+
+type CloneOutputPathRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BuildId            string `protobuf:"bytes,1,opt,name=build_id,json=buildId,proto3" json:"build_id,omitempty"`
+	SourceOutputBaseId string `protobuf:"bytes,2,opt,name=source_output_base_id,json=sourceOutputBaseId,proto3" json:"source_output_base_id,omitempty"`
+}
+
+func (x *CloneOutputPathRequest) Reset() {
+	*x = CloneOutputPathRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[53]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CloneOutputPathRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CloneOutputPathRequest) ProtoMessage() {}
+
+func (x *CloneOutputPathRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[53]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CloneOutputPathRequest.ProtoReflect.Descriptor instead.
+func (*CloneOutputPathRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *CloneOutputPathRequest) GetBuildId() string {
+	if x != nil {
+		return x.BuildId
+	}
+	return ""
+}
+
+func (x *CloneOutputPathRequest) GetSourceOutputBaseId() string {
+	if x != nil {
+		return x.SourceOutputBaseId
+	}
+	return ""
+}
+
+type CloneOutputPathResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RootTreeDigest *v2.Digest `protobuf:"bytes,1,opt,name=root_tree_digest,json=rootTreeDigest,proto3" json:"root_tree_digest,omitempty"`
+}
+
+func (x *CloneOutputPathResponse) Reset() {
+	*x = CloneOutputPathResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[54]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CloneOutputPathResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CloneOutputPathResponse) ProtoMessage() {}
+
+func (x *CloneOutputPathResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[54]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CloneOutputPathResponse.ProtoReflect.Descriptor instead.
+func (*CloneOutputPathResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *CloneOutputPathResponse) GetRootTreeDigest() *v2.Digest {
+	if x != nil {
+		return x.RootTreeDigest
+	}
+	return nil
+}
+
+type StatDigestRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BuildId        string `protobuf:"bytes,1,opt,name=build_id,json=buildId,proto3" json:"build_id,omitempty"`
+	Path           string `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	FollowSymlinks bool   `protobuf:"varint,3,opt,name=follow_symlinks,json=followSymlinks,proto3" json:"follow_symlinks,omitempty"`
+}
+
+func (x *StatDigestRequest) Reset() {
+	*x = StatDigestRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[55]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StatDigestRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatDigestRequest) ProtoMessage() {}
+
+func (x *StatDigestRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[55]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatDigestRequest.ProtoReflect.Descriptor instead.
+func (*StatDigestRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *StatDigestRequest) GetBuildId() string {
+	if x != nil {
+		return x.BuildId
+	}
+	return ""
+}
+
+func (x *StatDigestRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *StatDigestRequest) GetFollowSymlinks() bool {
+	if x != nil {
+		return x.FollowSymlinks
+	}
+	return false
+}
+
+type StatDigestResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	FileType StatDigestResponse_FileType `protobuf:"varint,1,opt,name=file_type,json=fileType,proto3,enum=buildbarn.outputpaths.StatDigestResponse_FileType" json:"file_type,omitempty"`
+	Digest   *v2.Digest                  `protobuf:"bytes,2,opt,name=digest,proto3" json:"digest,omitempty"`
+}
+
+func (x *StatDigestResponse) Reset() {
+	*x = StatDigestResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[56]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StatDigestResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatDigestResponse) ProtoMessage() {}
+
+func (x *StatDigestResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[56]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatDigestResponse.ProtoReflect.Descriptor instead.
+func (*StatDigestResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *StatDigestResponse) GetFileType() StatDigestResponse_FileType {
+	if x != nil {
+		return x.FileType
+	}
+	return StatDigestResponse_FILE
+}
+
+func (x *StatDigestResponse) GetDigest() *v2.Digest {
+	if x != nil {
+		return x.Digest
+	}
+	return nil
+}
+
+type RenameOutputBaseRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	OutputBaseId    string `protobuf:"bytes,1,opt,name=output_base_id,json=outputBaseId,proto3" json:"output_base_id,omitempty"`
+	NewOutputBaseId string `protobuf:"bytes,2,opt,name=new_output_base_id,json=newOutputBaseId,proto3" json:"new_output_base_id,omitempty"`
+}
+
+func (x *RenameOutputBaseRequest) Reset() {
+	*x = RenameOutputBaseRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[57]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RenameOutputBaseRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RenameOutputBaseRequest) ProtoMessage() {}
+
+func (x *RenameOutputBaseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[57]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RenameOutputBaseRequest.ProtoReflect.Descriptor instead.
+func (*RenameOutputBaseRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP(), []int{57}
+}
+
+func (x *RenameOutputBaseRequest) GetOutputBaseId() string {
+	if x != nil {
+		return x.OutputBaseId
+	}
+	return ""
+}
+
+func (x *RenameOutputBaseRequest) GetNewOutputBaseId() string {
+	if x != nil {
+		return x.NewOutputBaseId
+	}
+	return ""
+}
+
+type GetOutputPathDigestFunctionsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	OutputBaseId string `protobuf:"bytes,1,opt,name=output_base_id,json=outputBaseId,proto3" json:"output_base_id,omitempty"`
+}
+
+func (x *GetOutputPathDigestFunctionsRequest) Reset() {
+	*x = GetOutputPathDigestFunctionsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[58]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetOutputPathDigestFunctionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOutputPathDigestFunctionsRequest) ProtoMessage() {}
+
+func (x *GetOutputPathDigestFunctionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[58]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOutputPathDigestFunctionsRequest.ProtoReflect.Descriptor instead.
+func (*GetOutputPathDigestFunctionsRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP(), []int{58}
+}
+
+func (x *GetOutputPathDigestFunctionsRequest) GetOutputBaseId() string {
+	if x != nil {
+		return x.OutputBaseId
+	}
+	return ""
+}
+
+type GetOutputPathDigestFunctionsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DigestFunctions []*DigestFunctionUsage `protobuf:"bytes,1,rep,name=digest_functions,json=digestFunctions,proto3" json:"digest_functions,omitempty"`
+}
+
+func (x *GetOutputPathDigestFunctionsResponse) Reset() {
+	*x = GetOutputPathDigestFunctionsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[59]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetOutputPathDigestFunctionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOutputPathDigestFunctionsResponse) ProtoMessage() {}
+
+func (x *GetOutputPathDigestFunctionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[59]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOutputPathDigestFunctionsResponse.ProtoReflect.Descriptor instead.
+func (*GetOutputPathDigestFunctionsResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP(), []int{59}
+}
+
+func (x *GetOutputPathDigestFunctionsResponse) GetDigestFunctions() []*DigestFunctionUsage {
+	if x != nil {
+		return x.DigestFunctions
+	}
+	return nil
+}
+
+type DigestFunctionUsage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	InstanceName   string                  `protobuf:"bytes,1,opt,name=instance_name,json=instanceName,proto3" json:"instance_name,omitempty"`
+	DigestFunction v2.DigestFunction_Value `protobuf:"varint,2,opt,name=digest_function,json=digestFunction,proto3,enum=build.bazel.remote.execution.v2.DigestFunction_Value" json:"digest_function,omitempty"`
+	Objects        uint64                  `protobuf:"varint,3,opt,name=objects,proto3" json:"objects,omitempty"`
+}
+
+func (x *DigestFunctionUsage) Reset() {
+	*x = DigestFunctionUsage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[60]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DigestFunctionUsage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DigestFunctionUsage) ProtoMessage() {}
+
+func (x *DigestFunctionUsage) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[60]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DigestFunctionUsage.ProtoReflect.Descriptor instead.
+func (*DigestFunctionUsage) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP(), []int{60}
+}
+
+func (x *DigestFunctionUsage) GetInstanceName() string {
+	if x != nil {
+		return x.InstanceName
+	}
+	return ""
+}
+
+func (x *DigestFunctionUsage) GetDigestFunction() v2.DigestFunction_Value {
+	if x != nil {
+		return x.DigestFunction
+	}
+	return v2.DigestFunction_Value(0)
+}
+
+func (x *DigestFunctionUsage) GetObjects() uint64 {
+	if x != nil {
+		return x.Objects
+	}
+	return 0
+}
+
+type ExportTarballRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BuildId         string   `protobuf:"bytes,1,opt,name=build_id,json=buildId,proto3" json:"build_id,omitempty"`
+	Path            string   `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	IncludePatterns []string `protobuf:"bytes,3,rep,name=include_patterns,json=includePatterns,proto3" json:"include_patterns,omitempty"`
+	ChunkSizeBytes  uint32   `protobuf:"varint,4,opt,name=chunk_size_bytes,json=chunkSizeBytes,proto3" json:"chunk_size_bytes,omitempty"`
+	ReadConcurrency uint32   `protobuf:"varint,5,opt,name=read_concurrency,json=readConcurrency,proto3" json:"read_concurrency,omitempty"`
+}
+
+func (x *ExportTarballRequest) Reset() {
+	*x = ExportTarballRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[61]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExportTarballRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportTarballRequest) ProtoMessage() {}
+
+func (x *ExportTarballRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[61]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportTarballRequest.ProtoReflect.Descriptor instead.
+func (*ExportTarballRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP(), []int{61}
+}
+
+func (x *ExportTarballRequest) GetBuildId() string {
+	if x != nil {
+		return x.BuildId
+	}
+	return ""
+}
+
+func (x *ExportTarballRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *ExportTarballRequest) GetIncludePatterns() []string {
+	if x != nil {
+		return x.IncludePatterns
+	}
+	return nil
+}
+
+func (x *ExportTarballRequest) GetChunkSizeBytes() uint32 {
+	if x != nil {
+		return x.ChunkSizeBytes
+	}
+	return 0
+}
+
+func (x *ExportTarballRequest) GetReadConcurrency() uint32 {
+	if x != nil {
+		return x.ReadConcurrency
+	}
+	return 0
+}
+
+type ExportTarballResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Data     []byte                 `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	Progress *ExportTarballProgress `protobuf:"bytes,2,opt,name=progress,proto3" json:"progress,omitempty"`
+}
+
+func (x *ExportTarballResponse) Reset() {
+	*x = ExportTarballResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[62]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExportTarballResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportTarballResponse) ProtoMessage() {}
+
+func (x *ExportTarballResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[62]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportTarballResponse.ProtoReflect.Descriptor instead.
+func (*ExportTarballResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP(), []int{62}
+}
+
+func (x *ExportTarballResponse) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *ExportTarballResponse) GetProgress() *ExportTarballProgress {
+	if x != nil {
+		return x.Progress
+	}
+	return nil
+}
+
+type ExportTarballProgress struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	EntriesExported   uint64 `protobuf:"varint,1,opt,name=entries_exported,json=entriesExported,proto3" json:"entries_exported,omitempty"`
+	FileBytesExported uint64 `protobuf:"varint,2,opt,name=file_bytes_exported,json=fileBytesExported,proto3" json:"file_bytes_exported,omitempty"`
+}
+
+func (x *ExportTarballProgress) Reset() {
+	*x = ExportTarballProgress{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[63]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExportTarballProgress) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportTarballProgress) ProtoMessage() {}
+
+func (x *ExportTarballProgress) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[63]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportTarballProgress.ProtoReflect.Descriptor instead.
+func (*ExportTarballProgress) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP(), []int{63}
+}
+
+func (x *ExportTarballProgress) GetEntriesExported() uint64 {
+	if x != nil {
+		return x.EntriesExported
+	}
+	return 0
+}
+
+func (x *ExportTarballProgress) GetFileBytesExported() uint64 {
+	if x != nil {
+		return x.FileBytesExported
+	}
+	return 0
+}
+
+type SealBuildRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BuildId string `protobuf:"bytes,1,opt,name=build_id,json=buildId,proto3" json:"build_id,omitempty"`
+}
+
+func (x *SealBuildRequest) Reset() {
+	*x = SealBuildRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[64]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SealBuildRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SealBuildRequest) ProtoMessage() {}
+
+func (x *SealBuildRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[64]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SealBuildRequest.ProtoReflect.Descriptor instead.
+func (*SealBuildRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP(), []int{64}
+}
+
+func (x *SealBuildRequest) GetBuildId() string {
+	if x != nil {
+		return x.BuildId
+	}
+	return ""
+}
+
+type RepairOutputPathRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BuildId string `protobuf:"bytes,1,opt,name=build_id,json=buildId,proto3" json:"build_id,omitempty"`
+}
+
+func (x *RepairOutputPathRequest) Reset() {
+	*x = RepairOutputPathRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[65]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RepairOutputPathRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RepairOutputPathRequest) ProtoMessage() {}
+
+func (x *RepairOutputPathRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[65]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RepairOutputPathRequest.ProtoReflect.Descriptor instead.
+func (*RepairOutputPathRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP(), []int{65}
+}
+
+func (x *RepairOutputPathRequest) GetBuildId() string {
+	if x != nil {
+		return x.BuildId
+	}
+	return ""
+}
+
+type RepairOutputPathResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RemovedPaths []string `protobuf:"bytes,1,rep,name=removed_paths,json=removedPaths,proto3" json:"removed_paths,omitempty"`
+}
+
+func (x *RepairOutputPathResponse) Reset() {
+	*x = RepairOutputPathResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[66]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RepairOutputPathResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RepairOutputPathResponse) ProtoMessage() {}
+
+func (x *RepairOutputPathResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[66]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RepairOutputPathResponse.ProtoReflect.Descriptor instead.
+func (*RepairOutputPathResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP(), []int{66}
+}
+
+func (x *RepairOutputPathResponse) GetRemovedPaths() []string {
+	if x != nil {
+		return x.RemovedPaths
+	}
+	return nil
+}
+
+type GetOutputPathRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BuildId string `protobuf:"bytes,1,opt,name=build_id,json=buildId,proto3" json:"build_id,omitempty"`
+}
+
+func (x *GetOutputPathRequest) Reset() {
+	*x = GetOutputPathRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[67]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetOutputPathRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOutputPathRequest) ProtoMessage() {}
+
+func (x *GetOutputPathRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[67]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOutputPathRequest.ProtoReflect.Descriptor instead.
+func (*GetOutputPathRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP(), []int{67}
+}
+
+func (x *GetOutputPathRequest) GetBuildId() string {
+	if x != nil {
+		return x.BuildId
+	}
+	return ""
+}
+
+type GetOutputPathResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	OutputPath        string            `protobuf:"bytes,1,opt,name=output_path,json=outputPath,proto3" json:"output_path,omitempty"`
+	OutputPathSuffix  string            `protobuf:"bytes,2,opt,name=output_path_suffix,json=outputPathSuffix,proto3" json:"output_path_suffix,omitempty"`
+	OutputPathAliases map[string]string `protobuf:"bytes,3,rep,name=output_path_aliases,json=outputPathAliases,proto3" json:"output_path_aliases,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *GetOutputPathResponse) Reset() {
+	*x = GetOutputPathResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[68]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetOutputPathResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOutputPathResponse) ProtoMessage() {}
+
+func (x *GetOutputPathResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[68]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOutputPathResponse.ProtoReflect.Descriptor instead.
+func (*GetOutputPathResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP(), []int{68}
+}
+
+func (x *GetOutputPathResponse) GetOutputPath() string {
+	if x != nil {
+		return x.OutputPath
+	}
+	return ""
+}
+
+func (x *GetOutputPathResponse) GetOutputPathSuffix() string {
+	if x != nil {
+		return x.OutputPathSuffix
+	}
+	return ""
+}
+
+func (x *GetOutputPathResponse) GetOutputPathAliases() map[string]string {
+	if x != nil {
+		return x.OutputPathAliases
+	}
+	return nil
+}
+
+type VerifyTreeRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	InstanceName   string                  `protobuf:"bytes,1,opt,name=instance_name,json=instanceName,proto3" json:"instance_name,omitempty"`
+	DigestFunction v2.DigestFunction_Value `protobuf:"varint,2,opt,name=digest_function,json=digestFunction,proto3,enum=build.bazel.remote.execution.v2.DigestFunction_Value" json:"digest_function,omitempty"`
+	TreeDigest     *v2.Digest              `protobuf:"bytes,3,opt,name=tree_digest,json=treeDigest,proto3" json:"tree_digest,omitempty"`
+}
+
+func (x *VerifyTreeRequest) Reset() {
+	*x = VerifyTreeRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[69]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *VerifyTreeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyTreeRequest) ProtoMessage() {}
+
+func (x *VerifyTreeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[69]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyTreeRequest.ProtoReflect.Descriptor instead.
+func (*VerifyTreeRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP(), []int{69}
+}
+
+func (x *VerifyTreeRequest) GetInstanceName() string {
+	if x != nil {
+		return x.InstanceName
+	}
+	return ""
+}
+
+func (x *VerifyTreeRequest) GetDigestFunction() v2.DigestFunction_Value {
+	if x != nil {
+		return x.DigestFunction
+	}
+	return v2.DigestFunction_Value(0)
+}
+
+func (x *VerifyTreeRequest) GetTreeDigest() *v2.Digest {
+	if x != nil {
+		return x.TreeDigest
+	}
+	return nil
+}
+
+type VerifyTreeResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	MissingDirectoryDigests []*v2.Digest `protobuf:"bytes,1,rep,name=missing_directory_digests,json=missingDirectoryDigests,proto3" json:"missing_directory_digests,omitempty"`
+	MissingFileDigests      []*v2.Digest `protobuf:"bytes,2,rep,name=missing_file_digests,json=missingFileDigests,proto3" json:"missing_file_digests,omitempty"`
+}
+
+func (x *VerifyTreeResponse) Reset() {
+	*x = VerifyTreeResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[70]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *VerifyTreeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyTreeResponse) ProtoMessage() {}
+
+func (x *VerifyTreeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[70]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyTreeResponse.ProtoReflect.Descriptor instead.
+func (*VerifyTreeResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP(), []int{70}
+}
+
+func (x *VerifyTreeResponse) GetMissingDirectoryDigests() []*v2.Digest {
+	if x != nil {
+		return x.MissingDirectoryDigests
+	}
+	return nil
+}
+
+func (x *VerifyTreeResponse) GetMissingFileDigests() []*v2.Digest {
+	if x != nil {
+		return x.MissingFileDigests
+	}
+	return nil
+}
+
+type SetBuildMetadataRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BuildId  string            `protobuf:"bytes,1,opt,name=build_id,json=buildId,proto3" json:"build_id,omitempty"`
+	Metadata map[string]string `protobuf:"bytes,2,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *SetBuildMetadataRequest) Reset() {
+	*x = SetBuildMetadataRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[71]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SetBuildMetadataRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetBuildMetadataRequest) ProtoMessage() {}
+
+func (x *SetBuildMetadataRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[71]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetBuildMetadataRequest.ProtoReflect.Descriptor instead.
+func (*SetBuildMetadataRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP(), []int{71}
+}
+
+func (x *SetBuildMetadataRequest) GetBuildId() string {
+	if x != nil {
+		return x.BuildId
+	}
+	return ""
+}
+
+func (x *SetBuildMetadataRequest) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+type DiffBuildsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	OutputBaseId       string `protobuf:"bytes,1,opt,name=output_base_id,json=outputBaseId,proto3" json:"output_base_id,omitempty"`
+	OldBuildId         string `protobuf:"bytes,2,opt,name=old_build_id,json=oldBuildId,proto3" json:"old_build_id,omitempty"`
+	NewBuildId         string `protobuf:"bytes,3,opt,name=new_build_id,json=newBuildId,proto3" json:"new_build_id,omitempty"`
+	MaximumDifferences uint32 `protobuf:"varint,4,opt,name=maximum_differences,json=maximumDifferences,proto3" json:"maximum_differences,omitempty"`
+}
+
+func (x *DiffBuildsRequest) Reset() {
+	*x = DiffBuildsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[72]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DiffBuildsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiffBuildsRequest) ProtoMessage() {}
+
+func (x *DiffBuildsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[72]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiffBuildsRequest.ProtoReflect.Descriptor instead.
+func (*DiffBuildsRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP(), []int{72}
+}
+
+func (x *DiffBuildsRequest) GetOutputBaseId() string {
+	if x != nil {
+		return x.OutputBaseId
+	}
+	return ""
+}
+
+func (x *DiffBuildsRequest) GetOldBuildId() string {
+	if x != nil {
+		return x.OldBuildId
+	}
+	return ""
+}
+
+func (x *DiffBuildsRequest) GetNewBuildId() string {
+	if x != nil {
+		return x.NewBuildId
+	}
+	return ""
+}
+
+func (x *DiffBuildsRequest) GetMaximumDifferences() uint32 {
+	if x != nil {
+		return x.MaximumDifferences
+	}
+	return 0
+}
+
+type DiffBuildsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Differences []*PathDifference `protobuf:"bytes,1,rep,name=differences,proto3" json:"differences,omitempty"`
+	Truncated   bool              `protobuf:"varint,2,opt,name=truncated,proto3" json:"truncated,omitempty"`
+}
+
+func (x *DiffBuildsResponse) Reset() {
+	*x = DiffBuildsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[73]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DiffBuildsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiffBuildsResponse) ProtoMessage() {}
+
+func (x *DiffBuildsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[73]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiffBuildsResponse.ProtoReflect.Descriptor instead.
+func (*DiffBuildsResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP(), []int{73}
+}
+
+func (x *DiffBuildsResponse) GetDifferences() []*PathDifference {
+	if x != nil {
+		return x.Differences
+	}
+	return nil
+}
+
+func (x *DiffBuildsResponse) GetTruncated() bool {
+	if x != nil {
+		return x.Truncated
+	}
+	return false
+}
+
+type PathDifference struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Path    string      `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	OldNode *DiffedNode `protobuf:"bytes,2,opt,name=old_node,json=oldNode,proto3" json:"old_node,omitempty"`
+	NewNode *DiffedNode `protobuf:"bytes,3,opt,name=new_node,json=newNode,proto3" json:"new_node,omitempty"`
+}
+
+func (x *PathDifference) Reset() {
+	*x = PathDifference{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[74]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PathDifference) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PathDifference) ProtoMessage() {}
+
+func (x *PathDifference) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[74]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PathDifference.ProtoReflect.Descriptor instead.
+func (*PathDifference) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP(), []int{74}
+}
+
+func (x *PathDifference) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *PathDifference) GetOldNode() *DiffedNode {
+	if x != nil {
+		return x.OldNode
+	}
+	return nil
+}
+
+func (x *PathDifference) GetNewNode() *DiffedNode {
+	if x != nil {
+		return x.NewNode
+	}
+	return nil
+}
+
+type DiffedNode struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to NodeType:
+	//	*DiffedNode_File
+	//	*DiffedNode_Directory
+	//	*DiffedNode_Symlink
+	NodeType isDiffedNode_NodeType `protobuf_oneof:"node_type"`
+}
+
+func (x *DiffedNode) Reset() {
+	*x = DiffedNode{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[75]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DiffedNode) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiffedNode) ProtoMessage() {}
+
+func (x *DiffedNode) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[75]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiffedNode.ProtoReflect.Descriptor instead.
+func (*DiffedNode) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP(), []int{75}
+}
+
+func (m *DiffedNode) GetNodeType() isDiffedNode_NodeType {
+	if m != nil {
+		return m.NodeType
+	}
+	return nil
+}
+
+func (x *DiffedNode) GetFile() *v2.FileNode {
+	if x, ok := x.GetNodeType().(*DiffedNode_File); ok {
+		return x.File
+	}
+	return nil
+}
+
+func (x *DiffedNode) GetDirectory() *v2.DirectoryNode {
+	if x, ok := x.GetNodeType().(*DiffedNode_Directory); ok {
+		return x.Directory
+	}
+	return nil
+}
+
+func (x *DiffedNode) GetSymlink() *v2.SymlinkNode {
+	if x, ok := x.GetNodeType().(*DiffedNode_Symlink); ok {
+		return x.Symlink
+	}
+	return nil
+}
+
+type isDiffedNode_NodeType interface {
+	isDiffedNode_NodeType()
+}
+
+type DiffedNode_File struct {
+	File *v2.FileNode `protobuf:"bytes,1,opt,name=file,proto3,oneof"`
+}
+
+type DiffedNode_Directory struct {
+	Directory *v2.DirectoryNode `protobuf:"bytes,2,opt,name=directory,proto3,oneof"`
+}
+
+type DiffedNode_Symlink struct {
+	Symlink *v2.SymlinkNode `protobuf:"bytes,3,opt,name=symlink,proto3,oneof"`
+}
+
+func (*DiffedNode_File) isDiffedNode_NodeType() {}
+
+func (*DiffedNode_Directory) isDiffedNode_NodeType() {}
+
+func (*DiffedNode_Symlink) isDiffedNode_NodeType() {}
+
+type ResolveOutputPathRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	OutputBaseId     string `protobuf:"bytes,1,opt,name=output_base_id,json=outputBaseId,proto3" json:"output_base_id,omitempty"`
+	OutputPathPrefix string `protobuf:"bytes,2,opt,name=output_path_prefix,json=outputPathPrefix,proto3" json:"output_path_prefix,omitempty"`
+}
+
+func (x *ResolveOutputPathRequest) Reset() {
+	*x = ResolveOutputPathRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[76]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ResolveOutputPathRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResolveOutputPathRequest) ProtoMessage() {}
+
+func (x *ResolveOutputPathRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[76]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResolveOutputPathRequest.ProtoReflect.Descriptor instead.
+func (*ResolveOutputPathRequest) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP(), []int{76}
+}
+
+func (x *ResolveOutputPathRequest) GetOutputBaseId() string {
+	if x != nil {
+		return x.OutputBaseId
+	}
+	return ""
+}
+
+func (x *ResolveOutputPathRequest) GetOutputPathPrefix() string {
+	if x != nil {
+		return x.OutputPathPrefix
+	}
+	return ""
+}
+
+type ResolveOutputPathResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	OutputPathSuffix string `protobuf:"bytes,1,opt,name=output_path_suffix,json=outputPathSuffix,proto3" json:"output_path_suffix,omitempty"`
+	OutputPath       string `protobuf:"bytes,2,opt,name=output_path,json=outputPath,proto3" json:"output_path,omitempty"`
+}
+
+func (x *ResolveOutputPathResponse) Reset() {
+	*x = ResolveOutputPathResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[77]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ResolveOutputPathResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResolveOutputPathResponse) ProtoMessage() {}
+
+func (x *ResolveOutputPathResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[77]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResolveOutputPathResponse.ProtoReflect.Descriptor instead.
+func (*ResolveOutputPathResponse) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP(), []int{77}
+}
+
+func (x *ResolveOutputPathResponse) GetOutputPathSuffix() string {
+	if x != nil {
+		return x.OutputPathSuffix
+	}
+	return ""
+}
+
+func (x *ResolveOutputPathResponse) GetOutputPath() string {
+	if x != nil {
+		return x.OutputPath
+	}
+	return ""
+}
+
+type BuildEvent_EntryRemoval struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Reason         BuildEvent_EntryRemoval_Reason `protobuf:"varint,1,opt,name=reason,proto3,enum=buildbarn.outputpaths.BuildEvent_EntryRemoval_Reason" json:"reason,omitempty"`
+	Digest         *v2.Digest                     `protobuf:"bytes,2,opt,name=digest,proto3" json:"digest,omitempty"`
+	DigestFunction v2.DigestFunction_Value        `protobuf:"varint,3,opt,name=digest_function,json=digestFunction,proto3,enum=build.bazel.remote.execution.v2.DigestFunction_Value" json:"digest_function,omitempty"`
+}
+
+func (x *BuildEvent_EntryRemoval) Reset() {
+	*x = BuildEvent_EntryRemoval{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[78]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BuildEvent_EntryRemoval) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BuildEvent_EntryRemoval) ProtoMessage() {}
+
+func (x *BuildEvent_EntryRemoval) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[78]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BuildEvent_EntryRemoval.ProtoReflect.Descriptor instead.
+func (*BuildEvent_EntryRemoval) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP(), []int{2, 0}
+}
+
+func (x *BuildEvent_EntryRemoval) GetReason() BuildEvent_EntryRemoval_Reason {
+	if x != nil {
+		return x.Reason
+	}
+	return BuildEvent_EntryRemoval_UNKNOWN
+}
+
+func (x *BuildEvent_EntryRemoval) GetDigest() *v2.Digest {
+	if x != nil {
+		return x.Digest
+	}
+	return nil
+}
+
+func (x *BuildEvent_EntryRemoval) GetDigestFunction() v2.DigestFunction_Value {
+	if x != nil {
+		return x.DigestFunction
+	}
+	return v2.DigestFunction_Value(0)
+}
+
+type ListOutputPathsResponse_OutputPath struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	OutputBaseId             string                 `protobuf:"bytes,1,opt,name=output_base_id,json=outputBaseId,proto3" json:"output_base_id,omitempty"`
+	RootDirectoryInodeNumber uint64                 `protobuf:"varint,2,opt,name=root_directory_inode_number,json=rootDirectoryInodeNumber,proto3" json:"root_directory_inode_number,omitempty"`
+	ActiveBuildId            string                 `protobuf:"bytes,3,opt,name=active_build_id,json=activeBuildId,proto3" json:"active_build_id,omitempty"`
+	CreationTime             *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=creation_time,json=creationTime,proto3" json:"creation_time,omitempty"`
+	LastBuildStartTime       *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=last_build_start_time,json=lastBuildStartTime,proto3" json:"last_build_start_time,omitempty"`
+}
+
+func (x *ListOutputPathsResponse_OutputPath) Reset() {
+	*x = ListOutputPathsResponse_OutputPath{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[79]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListOutputPathsResponse_OutputPath) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListOutputPathsResponse_OutputPath) ProtoMessage() {}
+
+func (x *ListOutputPathsResponse_OutputPath) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[79]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListOutputPathsResponse_OutputPath.ProtoReflect.Descriptor instead.
+func (*ListOutputPathsResponse_OutputPath) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP(), []int{4, 0}
+}
+
+func (x *ListOutputPathsResponse_OutputPath) GetOutputBaseId() string {
+	if x != nil {
+		return x.OutputBaseId
+	}
+	return ""
+}
+
+func (x *ListOutputPathsResponse_OutputPath) GetRootDirectoryInodeNumber() uint64 {
+	if x != nil {
+		return x.RootDirectoryInodeNumber
+	}
+	return 0
+}
+
+func (x *ListOutputPathsResponse_OutputPath) GetActiveBuildId() string {
+	if x != nil {
+		return x.ActiveBuildId
+	}
+	return ""
+}
+
+func (x *ListOutputPathsResponse_OutputPath) GetCreationTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreationTime
+	}
+	return nil
+}
+
+func (x *ListOutputPathsResponse_OutputPath) GetLastBuildStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastBuildStartTime
+	}
+	return nil
+}
+
+var File_pkg_proto_outputpaths_outputpaths_proto protoreflect.FileDescriptor
+
+var file_pkg_proto_outputpaths_outputpaths_proto_rawDesc = []byte{
+	0x0a, 0x27, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x6f, 0x75, 0x74, 0x70,
+	0x75, 0x74, 0x70, 0x61, 0x74, 0x68, 0x73, 0x2f, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x70, 0x61,
+	0x74, 0x68, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x15, 0x62, 0x75, 0x69, 0x6c, 0x64,
+	0x62, 0x61, 0x72, 0x6e, 0x2e, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x70, 0x61, 0x74, 0x68, 0x73,
+	0x1a, 0x36, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x2f, 0x62, 0x61, 0x7a, 0x65, 0x6c, 0x2f, 0x72, 0x65,
+	0x6d, 0x6f, 0x74, 0x65, 0x2f, 0x65, 0x78, 0x65, 0x63, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x2f, 0x76,
+	0x32, 0x2f, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x5f, 0x65, 0x78, 0x65, 0x63, 0x75, 0x74, 0x69,
+	0x6f, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1b, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x65, 0x6d, 0x70, 0x74, 0x79, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x17, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x72,
+	0x70, 0x63, 0x2f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a,
+	0x39, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x72, 0x65, 0x6d, 0x6f, 0x74,
+	0x65, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2f, 0x72,
+	0x65, 0x6d, 0x6f, 0x74, 0x65, 0x5f, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x5f, 0x73, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x6e, 0x0a, 0x12, 0x57, 0x61,
+	0x74, 0x63, 0x68, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x2a, 0x0a, 0x03, 0x6e, 0x6f, 0x77, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x45, 0x6d, 0x70, 0x74, 0x79, 0x48, 0x00, 0x52, 0x03, 0x6e, 0x6f, 0x77, 0x12, 0x23, 0x0a, 0x0c,
+	0x61, 0x66, 0x74, 0x65, 0x72, 0x5f, 0x63, 0x75, 0x72, 0x73, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x04, 0x48, 0x00, 0x52, 0x0b, 0x61, 0x66, 0x74, 0x65, 0x72, 0x43, 0x75, 0x72, 0x73, 0x6f,
+	0x72, 0x42, 0x07, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x22, 0x50, 0x0a, 0x13, 0x57, 0x61,
+	0x74, 0x63, 0x68, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x39, 0x0a, 0x06, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x21, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x6f, 0x75,
+	0x74, 0x70, 0x75, 0x74, 0x70, 0x61, 0x74, 0x68, 0x73, 0x2e, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x45,
+	0x76, 0x65, 0x6e, 0x74, 0x52, 0x06, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x22, 0x96, 0x06, 0x0a,
+	0x0a, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x63,
+	0x75, 0x72, 0x73, 0x6f, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x63, 0x75, 0x72,
+	0x73, 0x6f, 0x72, 0x12, 0x3a, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0e, 0x32, 0x26, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x6f, 0x75,
+	0x74, 0x70, 0x75, 0x74, 0x70, 0x61, 0x74, 0x68, 0x73, 0x2e, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x45,
+	0x76, 0x65, 0x6e, 0x74, 0x2e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12,
+	0x24, 0x0a, 0x0e, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x5f, 0x62, 0x61, 0x73, 0x65, 0x5f, 0x69,
+	0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x42,
+	0x61, 0x73, 0x65, 0x49, 0x64, 0x12, 0x19, 0x0a, 0x08, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x5f, 0x69,
+	0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x49, 0x64,
+	0x12, 0x38, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52,
+	0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x53, 0x0a, 0x0d, 0x65, 0x6e,
+	0x74, 0x72, 0x79, 0x5f, 0x72, 0x65, 0x6d, 0x6f, 0x76, 0x61, 0x6c, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x2e, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x6f, 0x75,
+	0x74, 0x70, 0x75, 0x74, 0x70, 0x61, 0x74, 0x68, 0x73, 0x2e, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x45,
+	0x76, 0x65, 0x6e, 0x74, 0x2e, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x61,
+	0x6c, 0x52, 0x0c, 0x65, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x61, 0x6c, 0x1a,
+	0xe7, 0x02, 0x0a, 0x0c, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x61, 0x6c,
+	0x12, 0x4d, 0x0a, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x35, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x6f, 0x75, 0x74,
+	0x70, 0x75, 0x74, 0x70, 0x61, 0x74, 0x68, 0x73, 0x2e, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x45, 0x76,
+	0x65, 0x6e, 0x74, 0x2e, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x61, 0x6c,
+	0x2e, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x52, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x12,
+	0x3f, 0x0a, 0x06, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x27, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x2e, 0x62, 0x61, 0x7a, 0x65, 0x6c, 0x2e, 0x72, 0x65,
+	0x6d, 0x6f, 0x74, 0x65, 0x2e, 0x65, 0x78, 0x65, 0x63, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x76,
+	0x32, 0x2e, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x52, 0x06, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74,
+	0x12, 0x5e, 0x0a, 0x0f, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x5f, 0x66, 0x75, 0x6e, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x35, 0x2e, 0x62, 0x75, 0x69, 0x6c,
+	0x64, 0x2e, 0x62, 0x61, 0x7a, 0x65, 0x6c, 0x2e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x2e, 0x65,
+	0x78, 0x65, 0x63, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x76, 0x32, 0x2e, 0x44, 0x69, 0x67, 0x65,
+	0x73, 0x74, 0x46, 0x75, 0x6e, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65,
+	0x52, 0x0e, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x46, 0x75, 0x6e, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x22, 0x67, 0x0a, 0x06, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x12, 0x0b, 0x0a, 0x07, 0x55, 0x4e,
+	0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x00, 0x12, 0x0b, 0x0a, 0x07, 0x4d, 0x49, 0x53, 0x53, 0x49,
+	0x4e, 0x47, 0x10, 0x01, 0x12, 0x17, 0x0a, 0x13, 0x49, 0x4e, 0x43, 0x4f, 0x4d, 0x50, 0x41, 0x54,
+	0x49, 0x42, 0x4c, 0x45, 0x5f, 0x44, 0x49, 0x47, 0x45, 0x53, 0x54, 0x10, 0x02, 0x12, 0x0f, 0x0a,
+	0x0b, 0x43, 0x4f, 0x50, 0x59, 0x5f, 0x46, 0x41, 0x49, 0x4c, 0x45, 0x44, 0x10, 0x03, 0x12, 0x19,
+	0x0a, 0x15, 0x44, 0x49, 0x52, 0x45, 0x43, 0x54, 0x4f, 0x52, 0x59, 0x5f, 0x55, 0x4e, 0x41, 0x56,
+	0x41, 0x49, 0x4c, 0x41, 0x42, 0x4c, 0x45, 0x10, 0x04, 0x22, 0x7a, 0x0a, 0x04, 0x54, 0x79, 0x70,
+	0x65, 0x12, 0x0b, 0x0a, 0x07, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x00, 0x12, 0x11,
+	0x0a, 0x0d, 0x42, 0x55, 0x49, 0x4c, 0x44, 0x5f, 0x53, 0x54, 0x41, 0x52, 0x54, 0x45, 0x44, 0x10,
+	0x01, 0x12, 0x13, 0x0a, 0x0f, 0x42, 0x55, 0x49, 0x4c, 0x44, 0x5f, 0x46, 0x49, 0x4e, 0x41, 0x4c,
+	0x49, 0x5a, 0x45, 0x44, 0x10, 0x02, 0x12, 0x11, 0x0a, 0x0d, 0x42, 0x55, 0x49, 0x4c, 0x44, 0x5f,
+	0x41, 0x42, 0x4f, 0x52, 0x54, 0x45, 0x44, 0x10, 0x03, 0x12, 0x17, 0x0a, 0x13, 0x4f, 0x55, 0x54,
+	0x50, 0x55, 0x54, 0x5f, 0x42, 0x41, 0x53, 0x45, 0x5f, 0x43, 0x4c, 0x45, 0x41, 0x4e, 0x45, 0x44,
+	0x10, 0x04, 0x12, 0x11, 0x0a, 0x0d, 0x45, 0x4e, 0x54, 0x52, 0x59, 0x5f, 0x52, 0x45, 0x4d, 0x4f,
+	0x56, 0x45, 0x44, 0x10, 0x05, 0x22, 0x4a, 0x0a, 0x16, 0x4c, 0x69, 0x73, 0x74, 0x4f, 0x75, 0x74,
+	0x70, 0x75, 0x74, 0x50, 0x61, 0x74, 0x68, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x30, 0x0a, 0x05, 0x73, 0x69, 0x6e, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x05, 0x73, 0x69, 0x6e, 0x63,
+	0x65, 0x22, 0xa3, 0x03, 0x0a, 0x17, 0x4c, 0x69, 0x73, 0x74, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74,
+	0x50, 0x61, 0x74, 0x68, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x5c, 0x0a,
+	0x0c, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x73, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x39, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e,
+	0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x70, 0x61, 0x74, 0x68, 0x73, 0x2e, 0x4c, 0x69, 0x73, 0x74,
+	0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x50, 0x61, 0x74, 0x68, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x2e, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x50, 0x61, 0x74, 0x68, 0x52, 0x0b,
+	0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x50, 0x61, 0x74, 0x68, 0x73, 0x1a, 0xa9, 0x02, 0x0a, 0x0a,
+	0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x50, 0x61, 0x74, 0x68, 0x12, 0x24, 0x0a, 0x0e, 0x6f, 0x75,
+	0x74, 0x70, 0x75, 0x74, 0x5f, 0x62, 0x61, 0x73, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0c, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x42, 0x61, 0x73, 0x65, 0x49, 0x64,
+	0x12, 0x3d, 0x0a, 0x1b, 0x72, 0x6f, 0x6f, 0x74, 0x5f, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f,
+	0x72, 0x79, 0x5f, 0x69, 0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x18, 0x72, 0x6f, 0x6f, 0x74, 0x44, 0x69, 0x72, 0x65, 0x63,
+	0x74, 0x6f, 0x72, 0x79, 0x49, 0x6e, 0x6f, 0x64, 0x65, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x12,
+	0x26, 0x0a, 0x0f, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x5f, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x5f,
+	0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65,
+	0x42, 0x75, 0x69, 0x6c, 0x64, 0x49, 0x64, 0x12, 0x3f, 0x0a, 0x0d, 0x63, 0x72, 0x65, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0c, 0x63, 0x72, 0x65, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x4d, 0x0a, 0x15, 0x6c, 0x61, 0x73, 0x74,
+	0x5f, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x5f, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x74, 0x69, 0x6d,
+	0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x52, 0x12, 0x6c, 0x61, 0x73, 0x74, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x53, 0x74,
+	0x61, 0x72, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x22, 0x6b, 0x0a, 0x12, 0x53, 0x74, 0x61, 0x74, 0x53,
+	0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a,
+	0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x69, 0x6e,
+	0x64, 0x65, 0x78, 0x12, 0x3f, 0x0a, 0x08, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x23, 0x2e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x5f, 0x6f,
+	0x75, 0x74, 0x70, 0x75, 0x74, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x53, 0x74,
+	0x61, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x52, 0x08, 0x72, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x22, 0x3d, 0x0a, 0x15, 0x47, 0x65, 0x74, 0x41, 0x63, 0x74, 0x69, 0x76,
+	0x65, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x24, 0x0a,
+	0x0e, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x5f, 0x62, 0x61, 0x73, 0x65, 0x5f, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x42, 0x61, 0x73,
+	0x65, 0x49, 0x64, 0x22, 0x5f, 0x0a, 0x16, 0x47, 0x65, 0x74, 0x41, 0x63, 0x74, 0x69, 0x76, 0x65,
+	0x42, 0x75, 0x69, 0x6c, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x45, 0x0a,
+	0x0c, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x5f, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e,
+	0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x70, 0x61, 0x74, 0x68, 0x73, 0x2e, 0x41, 0x63, 0x74, 0x69,
+	0x76, 0x65, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x52, 0x0b, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x42,
+	0x75, 0x69, 0x6c, 0x64, 0x22, 0xe8, 0x04, 0x0a, 0x0b, 0x41, 0x63, 0x74, 0x69, 0x76, 0x65, 0x42,
+	0x75, 0x69, 0x6c, 0x64, 0x12, 0x19, 0x0a, 0x08, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x5f, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x49, 0x64, 0x12,
+	0x39, 0x0a, 0x0a, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52,
+	0x09, 0x73, 0x74, 0x61, 0x72, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x23, 0x0a, 0x0d, 0x69, 0x6e,
+	0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0c, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x12,
+	0x5e, 0x0a, 0x0f, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x5f, 0x66, 0x75, 0x6e, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x35, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64,
+	0x2e, 0x62, 0x61, 0x7a, 0x65, 0x6c, 0x2e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x2e, 0x65, 0x78,
+	0x65, 0x63, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x76, 0x32, 0x2e, 0x44, 0x69, 0x67, 0x65, 0x73,
+	0x74, 0x46, 0x75, 0x6e, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52,
+	0x0e, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x46, 0x75, 0x6e, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12,
+	0x2a, 0x0a, 0x11, 0x63, 0x61, 0x73, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x5f, 0x66, 0x65, 0x74,
+	0x63, 0x68, 0x65, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0f, 0x63, 0x61, 0x73, 0x42,
+	0x79, 0x74, 0x65, 0x73, 0x46, 0x65, 0x74, 0x63, 0x68, 0x65, 0x64, 0x12, 0x5f, 0x0a, 0x0f, 0x63,
+	0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x18, 0x06,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x36, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e,
+	0x2e, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x70, 0x61, 0x74, 0x68, 0x73, 0x2e, 0x41, 0x63, 0x74,
+	0x69, 0x76, 0x65, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x2e, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x4d,
+	0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0e, 0x63, 0x6c,
+	0x69, 0x65, 0x6e, 0x74, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x12, 0x2c, 0x0a, 0x12,
+	0x66, 0x69, 0x6e, 0x64, 0x5f, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6e, 0x67, 0x5f, 0x63, 0x61, 0x6c,
+	0x6c, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x04, 0x52, 0x10, 0x66, 0x69, 0x6e, 0x64, 0x4d, 0x69,
+	0x73, 0x73, 0x69, 0x6e, 0x67, 0x43, 0x61, 0x6c, 0x6c, 0x73, 0x12, 0x23, 0x0a, 0x0d, 0x62, 0x6c,
+	0x6f, 0x62, 0x73, 0x5f, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x65, 0x64, 0x18, 0x08, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x0c, 0x62, 0x6c, 0x6f, 0x62, 0x73, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x65, 0x64, 0x12,
+	0x5b, 0x0a, 0x14, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x5f, 0x70,
+	0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x29, 0x2e,
+	0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74,
+	0x70, 0x61, 0x74, 0x68, 0x73, 0x2e, 0x53, 0x74, 0x61, 0x72, 0x74, 0x42, 0x75, 0x69, 0x6c, 0x64,
+	0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x52, 0x12, 0x73, 0x74, 0x61, 0x72, 0x74, 0x42,
+	0x75, 0x69, 0x6c, 0x64, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x1a, 0x41, 0x0a, 0x13,
+	0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x45, 0x6e,
+	0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22,
+	0x2e, 0x0a, 0x11, 0x41, 0x62, 0x6f, 0x72, 0x74, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x5f, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x49, 0x64, 0x22,
+	0x42, 0x0a, 0x1a, 0x47, 0x65, 0x74, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x50, 0x61, 0x74, 0x68,
+	0x45, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x24, 0x0a,
+	0x0e, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x5f, 0x62, 0x61, 0x73, 0x65, 0x5f, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x42, 0x61, 0x73,
+	0x65, 0x49, 0x64, 0x22, 0x93, 0x01, 0x0a, 0x1b, 0x47, 0x65, 0x74, 0x4f, 0x75, 0x74, 0x70, 0x75,
+	0x74, 0x50, 0x61, 0x74, 0x68, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x3e, 0x0a, 0x06, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x26, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e,
+	0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x70, 0x61, 0x74, 0x68, 0x73, 0x2e, 0x4f, 0x75, 0x74, 0x70,
+	0x75, 0x74, 0x50, 0x61, 0x74, 0x68, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x52, 0x06, 0x65, 0x72, 0x72,
+	0x6f, 0x72, 0x73, 0x12, 0x34, 0x0a, 0x16, 0x64, 0x69, 0x73, 0x63, 0x61, 0x72, 0x64, 0x65, 0x64,
+	0x5f, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x14, 0x64, 0x69, 0x73, 0x63, 0x61, 0x72, 0x64, 0x65, 0x64, 0x45, 0x72,
+	0x72, 0x6f, 0x72, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0xc7, 0x01, 0x0a, 0x0f, 0x4f, 0x75,
+	0x74, 0x70, 0x75, 0x74, 0x50, 0x61, 0x74, 0x68, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x2e, 0x0a,
+	0x04, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x2a, 0x0a,
+	0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x58, 0x0a, 0x13, 0x66, 0x65, 0x74,
+	0x63, 0x68, 0x65, 0x64, 0x5f, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x5f, 0x74, 0x79, 0x70, 0x65,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x28, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61,
+	0x72, 0x6e, 0x2e, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x70, 0x61, 0x74, 0x68, 0x73, 0x2e, 0x46,
+	0x65, 0x74, 0x63, 0x68, 0x65, 0x64, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x54, 0x79, 0x70, 0x65,
+	0x52, 0x11, 0x66, 0x65, 0x74, 0x63, 0x68, 0x65, 0x64, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x54,
+	0x79, 0x70, 0x65, 0x22, 0x59, 0x0a, 0x0c, 0x46, 0x65, 0x74, 0x63, 0x68, 0x46, 0x61, 0x69, 0x6c,
+	0x75, 0x72, 0x65, 0x12, 0x49, 0x0a, 0x0b, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x5f, 0x74, 0x79,
+	0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x28, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64,
+	0x62, 0x61, 0x72, 0x6e, 0x2e, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x70, 0x61, 0x74, 0x68, 0x73,
+	0x2e, 0x46, 0x65, 0x74, 0x63, 0x68, 0x65, 0x64, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x54, 0x79,
+	0x70, 0x65, 0x52, 0x0a, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x54, 0x79, 0x70, 0x65, 0x22, 0x42,
+	0x0a, 0x11, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x54, 0x72, 0x65, 0x65, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x5f, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x49, 0x64, 0x12, 0x12,
+	0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61,
+	0x74, 0x68, 0x22, 0x5e, 0x0a, 0x12, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x54, 0x72, 0x65, 0x65,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x48, 0x0a, 0x0b, 0x74, 0x72, 0x65, 0x65,
+	0x5f, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x27, 0x2e,
+	0x62, 0x75, 0x69, 0x6c, 0x64, 0x2e, 0x62, 0x61, 0x7a, 0x65, 0x6c, 0x2e, 0x72, 0x65, 0x6d, 0x6f,
+	0x74, 0x65, 0x2e, 0x65, 0x78, 0x65, 0x63, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x76, 0x32, 0x2e,
+	0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x52, 0x0a, 0x74, 0x72, 0x65, 0x65, 0x44, 0x69, 0x67, 0x65,
+	0x73, 0x74, 0x22, 0x61, 0x0a, 0x18, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x52, 0x6f, 0x6f, 0x74,
+	0x53, 0x79, 0x6d, 0x6c, 0x69, 0x6e, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x19,
+	0x0a, 0x08, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x07, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x49, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x16, 0x0a,
+	0x06, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x74,
+	0x61, 0x72, 0x67, 0x65, 0x74, 0x22, 0x49, 0x0a, 0x18, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x52,
+	0x6f, 0x6f, 0x74, 0x53, 0x79, 0x6d, 0x6c, 0x69, 0x6e, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x19, 0x0a, 0x08, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x07, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x49, 0x64, 0x12, 0x12, 0x0a, 0x04,
+	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65,
+	0x22, 0xaf, 0x01, 0x0a, 0x1d, 0x42, 0x65, 0x73, 0x74, 0x45, 0x66, 0x66, 0x6f, 0x72, 0x74, 0x42,
+	0x61, 0x74, 0x63, 0x68, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x28, 0x0a, 0x05, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x12, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x53,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x05, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x12, 0x34, 0x0a, 0x0b,
+	0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x69, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x12, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x53,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x0b, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x69,
+	0x65, 0x73, 0x12, 0x2e, 0x0a, 0x08, 0x73, 0x79, 0x6d, 0x6c, 0x69, 0x6e, 0x6b, 0x73, 0x18, 0x03,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x72, 0x70,
+	0x63, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x08, 0x73, 0x79, 0x6d, 0x6c, 0x69, 0x6e,
+	0x6b, 0x73, 0x22, 0xc4, 0x02, 0x0a, 0x13, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x74, 0x72,
+	0x65, 0x61, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x43, 0x0a, 0x06, 0x68, 0x65,
+	0x61, 0x64, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x29, 0x2e, 0x62, 0x75, 0x69,
+	0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x70, 0x61, 0x74,
+	0x68, 0x73, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x48,
+	0x65, 0x61, 0x64, 0x65, 0x72, 0x48, 0x00, 0x52, 0x06, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x12,
+	0x41, 0x0a, 0x04, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2b, 0x2e,
+	0x62, 0x75, 0x69, 0x6c, 0x64, 0x2e, 0x62, 0x61, 0x7a, 0x65, 0x6c, 0x2e, 0x72, 0x65, 0x6d, 0x6f,
+	0x74, 0x65, 0x2e, 0x65, 0x78, 0x65, 0x63, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x76, 0x32, 0x2e,
+	0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x46, 0x69, 0x6c, 0x65, 0x48, 0x00, 0x52, 0x04, 0x66, 0x69,
+	0x6c, 0x65, 0x12, 0x50, 0x0a, 0x09, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x30, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x2e, 0x62, 0x61,
+	0x7a, 0x65, 0x6c, 0x2e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x2e, 0x65, 0x78, 0x65, 0x63, 0x75,
+	0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x76, 0x32, 0x2e, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x44, 0x69,
+	0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x48, 0x00, 0x52, 0x09, 0x64, 0x69, 0x72, 0x65, 0x63,
+	0x74, 0x6f, 0x72, 0x79, 0x12, 0x4a, 0x0a, 0x07, 0x73, 0x79, 0x6d, 0x6c, 0x69, 0x6e, 0x6b, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2e, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x2e, 0x62, 0x61,
+	0x7a, 0x65, 0x6c, 0x2e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x2e, 0x65, 0x78, 0x65, 0x63, 0x75,
+	0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x76, 0x32, 0x2e, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x53, 0x79,
+	0x6d, 0x6c, 0x69, 0x6e, 0x6b, 0x48, 0x00, 0x52, 0x07, 0x73, 0x79, 0x6d, 0x6c, 0x69, 0x6e, 0x6b,
+	0x42, 0x07, 0x0a, 0x05, 0x65, 0x6e, 0x74, 0x72, 0x79, 0x22, 0xae, 0x01, 0x0a, 0x12, 0x43, 0x72,
+	0x65, 0x61, 0x74, 0x65, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72,
+	0x12, 0x19, 0x0a, 0x08, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x07, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x49, 0x64, 0x12, 0x1f, 0x0a, 0x0b, 0x70,
+	0x61, 0x74, 0x68, 0x5f, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0a, 0x70, 0x61, 0x74, 0x68, 0x50, 0x72, 0x65, 0x66, 0x69, 0x78, 0x12, 0x2a, 0x0a, 0x11,
+	0x63, 0x6c, 0x65, 0x61, 0x6e, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x5f, 0x70, 0x72, 0x65, 0x66, 0x69,
+	0x78, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0f, 0x63, 0x6c, 0x65, 0x61, 0x6e, 0x50, 0x61,
+	0x74, 0x68, 0x50, 0x72, 0x65, 0x66, 0x69, 0x78, 0x12, 0x30, 0x0a, 0x14, 0x72, 0x65, 0x70, 0x6f,
+	0x72, 0x74, 0x5f, 0x72, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x64, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x73,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x12, 0x72, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x52, 0x65,
+	0x6d, 0x6f, 0x76, 0x65, 0x64, 0x50, 0x61, 0x74, 0x68, 0x73, 0x22, 0x8f, 0x01, 0x0a, 0x14, 0x43,
+	0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x05, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x69, 0x72,
+	0x65, 0x63, 0x74, 0x6f, 0x72, 0x69, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0b,
+	0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x69, 0x65, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x73,
+	0x79, 0x6d, 0x6c, 0x69, 0x6e, 0x6b, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x73,
+	0x79, 0x6d, 0x6c, 0x69, 0x6e, 0x6b, 0x73, 0x12, 0x23, 0x0a, 0x0d, 0x72, 0x65, 0x6d, 0x6f, 0x76,
+	0x65, 0x64, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0c,
+	0x72, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x64, 0x50, 0x61, 0x74, 0x68, 0x73, 0x22, 0xba, 0x01, 0x0a,
+	0x1a, 0x45, 0x78, 0x74, 0x65, 0x6e, 0x64, 0x65, 0x64, 0x42, 0x61, 0x74, 0x63, 0x68, 0x43, 0x72,
+	0x65, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x43, 0x0a, 0x07, 0x72,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x29, 0x2e, 0x72,
+	0x65, 0x6d, 0x6f, 0x74, 0x65, 0x5f, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x5f, 0x73, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x52, 0x07, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x32, 0x0a, 0x15, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x5f, 0x74, 0x72, 0x65,
+	0x65, 0x5f, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x13, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x54, 0x72, 0x65, 0x65, 0x44, 0x69, 0x67,
+	0x65, 0x73, 0x74, 0x73, 0x12, 0x23, 0x0a, 0x0d, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x5f,
+	0x73, 0x63, 0x6f, 0x70, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x61, 0x6c, 0x6c,
+	0x6f, 0x77, 0x65, 0x64, 0x53, 0x63, 0x6f, 0x70, 0x65, 0x22, 0x53, 0x0a, 0x1b, 0x45, 0x78, 0x74,
+	0x65, 0x6e, 0x64, 0x65, 0x64, 0x42, 0x61, 0x74, 0x63, 0x68, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x34, 0x0a, 0x0b, 0x64, 0x69, 0x72, 0x65,
+	0x63, 0x74, 0x6f, 0x72, 0x69, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x52, 0x0b, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x69, 0x65, 0x73, 0x22, 0x41,
+	0x0a, 0x19, 0x47, 0x65, 0x74, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x50, 0x61, 0x74, 0x68, 0x53,
+	0x74, 0x61, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x24, 0x0a, 0x0e, 0x6f,
+	0x75, 0x74, 0x70, 0x75, 0x74, 0x5f, 0x62, 0x61, 0x73, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0c, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x42, 0x61, 0x73, 0x65, 0x49,
+	0x64, 0x22, 0xa8, 0x02, 0x0a, 0x1a, 0x47, 0x65, 0x74, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x50,
+	0x61, 0x74, 0x68, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x5d, 0x0a, 0x12, 0x62, 0x6c, 0x6f, 0x62, 0x5f, 0x66, 0x65, 0x74, 0x63, 0x68, 0x5f, 0x6c,
+	0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2f, 0x2e, 0x62,
+	0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x70,
+	0x61, 0x74, 0x68, 0x73, 0x2e, 0x46, 0x65, 0x74, 0x63, 0x68, 0x4c, 0x61, 0x74, 0x65, 0x6e, 0x63,
+	0x79, 0x44, 0x69, 0x73, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x10, 0x62,
+	0x6c, 0x6f, 0x62, 0x46, 0x65, 0x74, 0x63, 0x68, 0x4c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x12,
+	0x5d, 0x0a, 0x12, 0x74, 0x72, 0x65, 0x65, 0x5f, 0x66, 0x65, 0x74, 0x63, 0x68, 0x5f, 0x6c, 0x61,
+	0x74, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2f, 0x2e, 0x62, 0x75,
+	0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x70, 0x61,
+	0x74, 0x68, 0x73, 0x2e, 0x46, 0x65, 0x74, 0x63, 0x68, 0x4c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79,
+	0x44, 0x69, 0x73, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x10, 0x74, 0x72,
+	0x65, 0x65, 0x46, 0x65, 0x74, 0x63, 0x68, 0x4c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x12, 0x4c,
+	0x0a, 0x14, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54,
+	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x12, 0x6c, 0x61, 0x73, 0x74, 0x56, 0x61,
+	0x6c, 0x69, 0x64, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x69, 0x6d, 0x65, 0x22, 0xb7, 0x01, 0x0a,
+	0x18, 0x46, 0x65, 0x74, 0x63, 0x68, 0x4c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x44, 0x69, 0x73,
+	0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x6f, 0x75,
+	0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12,
+	0x2b, 0x0a, 0x03, 0x70, 0x35, 0x30, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44,
+	0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x03, 0x70, 0x35, 0x30, 0x12, 0x2b, 0x0a, 0x03,
+	0x70, 0x39, 0x35, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x03, 0x70, 0x39, 0x35, 0x12, 0x2b, 0x0a, 0x03, 0x70, 0x39, 0x39,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x52, 0x03, 0x70, 0x39, 0x39, 0x22, 0xbb, 0x03, 0x0a, 0x18, 0x45, 0x78, 0x74, 0x65, 0x6e,
+	0x64, 0x65, 0x64, 0x42, 0x61, 0x74, 0x63, 0x68, 0x53, 0x74, 0x61, 0x74, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x41, 0x0a, 0x07, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x27, 0x2e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x5f, 0x6f, 0x75,
+	0x74, 0x70, 0x75, 0x74, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x42, 0x61, 0x74,
+	0x63, 0x68, 0x53, 0x74, 0x61, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x52, 0x07, 0x72,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x38, 0x0a, 0x18, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64,
+	0x65, 0x5f, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x5f, 0x64, 0x69, 0x67, 0x65,
+	0x73, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x16, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64,
+	0x65, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74,
+	0x12, 0x32, 0x0a, 0x15, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x5f, 0x72, 0x65, 0x73, 0x6f,
+	0x6c, 0x76, 0x65, 0x64, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x13, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x52, 0x65, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x64,
+	0x50, 0x61, 0x74, 0x68, 0x12, 0x32, 0x0a, 0x15, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x5f,
+	0x73, 0x79, 0x6d, 0x6c, 0x69, 0x6e, 0x6b, 0x5f, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x13, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x53, 0x79, 0x6d, 0x6c,
+	0x69, 0x6e, 0x6b, 0x43, 0x68, 0x61, 0x69, 0x6e, 0x12, 0x29, 0x0a, 0x10, 0x63, 0x61, 0x73, 0x65,
+	0x5f, 0x69, 0x6e, 0x73, 0x65, 0x6e, 0x73, 0x69, 0x74, 0x69, 0x76, 0x65, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x0f, 0x63, 0x61, 0x73, 0x65, 0x49, 0x6e, 0x73, 0x65, 0x6e, 0x73, 0x69, 0x74,
+	0x69, 0x76, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x62, 0x65, 0x73, 0x74, 0x5f, 0x65, 0x66, 0x66, 0x6f,
+	0x72, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x62, 0x65, 0x73, 0x74, 0x45, 0x66,
+	0x66, 0x6f, 0x72, 0x74, 0x12, 0x41, 0x0a, 0x1d, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x5f,
+	0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x5f, 0x65, 0x6e, 0x74, 0x72, 0x79, 0x5f,
+	0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x52, 0x1a, 0x69, 0x6e, 0x63,
+	0x6c, 0x75, 0x64, 0x65, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x45, 0x6e, 0x74,
+	0x72, 0x79, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x2b, 0x0a, 0x11, 0x64, 0x65, 0x64, 0x75, 0x70,
+	0x6c, 0x69, 0x63, 0x61, 0x74, 0x65, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x73, 0x18, 0x08, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x10, 0x64, 0x65, 0x64, 0x75, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x65, 0x50,
+	0x61, 0x74, 0x68, 0x73, 0x22, 0x66, 0x0a, 0x19, 0x45, 0x78, 0x74, 0x65, 0x6e, 0x64, 0x65, 0x64,
+	0x42, 0x61, 0x74, 0x63, 0x68, 0x53, 0x74, 0x61, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x49, 0x0a, 0x09, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x73, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x2b, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e,
+	0x2e, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x70, 0x61, 0x74, 0x68, 0x73, 0x2e, 0x45, 0x78, 0x74,
+	0x65, 0x6e, 0x64, 0x65, 0x64, 0x53, 0x74, 0x61, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x52, 0x09, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x73, 0x22, 0x8b, 0x03, 0x0a,
+	0x14, 0x45, 0x78, 0x74, 0x65, 0x6e, 0x64, 0x65, 0x64, 0x53, 0x74, 0x61, 0x74, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3f, 0x0a, 0x08, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x23, 0x2e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65,
+	0x5f, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e,
+	0x53, 0x74, 0x61, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x52, 0x08, 0x72, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x52, 0x0a, 0x10, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74,
+	0x6f, 0x72, 0x79, 0x5f, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x27, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x2e, 0x62, 0x61, 0x7a, 0x65, 0x6c, 0x2e, 0x72,
+	0x65, 0x6d, 0x6f, 0x74, 0x65, 0x2e, 0x65, 0x78, 0x65, 0x63, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x2e,
+	0x76, 0x32, 0x2e, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x52, 0x0f, 0x64, 0x69, 0x72, 0x65, 0x63,
+	0x74, 0x6f, 0x72, 0x79, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x12, 0x23, 0x0a, 0x0d, 0x72, 0x65,
+	0x73, 0x6f, 0x6c, 0x76, 0x65, 0x64, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0c, 0x72, 0x65, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x64, 0x50, 0x61, 0x74, 0x68, 0x12,
+	0x23, 0x0a, 0x0d, 0x73, 0x79, 0x6d, 0x6c, 0x69, 0x6e, 0x6b, 0x5f, 0x63, 0x68, 0x61, 0x69, 0x6e,
+	0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0c, 0x73, 0x79, 0x6d, 0x6c, 0x69, 0x6e, 0x6b, 0x43,
+	0x68, 0x61, 0x69, 0x6e, 0x12, 0x36, 0x0a, 0x17, 0x73, 0x79, 0x6d, 0x6c, 0x69, 0x6e, 0x6b, 0x5f,
+	0x63, 0x68, 0x61, 0x69, 0x6e, 0x5f, 0x74, 0x72, 0x75, 0x6e, 0x63, 0x61, 0x74, 0x65, 0x64, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x15, 0x73, 0x79, 0x6d, 0x6c, 0x69, 0x6e, 0x6b, 0x43, 0x68,
+	0x61, 0x69, 0x6e, 0x54, 0x72, 0x75, 0x6e, 0x63, 0x61, 0x74, 0x65, 0x64, 0x12, 0x28, 0x0a, 0x05,
+	0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52,
+	0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x32, 0x0a, 0x15, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74,
+	0x6f, 0x72, 0x79, 0x5f, 0x65, 0x6e, 0x74, 0x72, 0x79, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18,
+	0x07, 0x20, 0x01, 0x28, 0x04, 0x52, 0x13, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79,
+	0x45, 0x6e, 0x74, 0x72, 0x79, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0x5e, 0x0a, 0x16, 0x42, 0x61,
+	0x74, 0x63, 0x68, 0x53, 0x74, 0x61, 0x72, 0x74, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x44, 0x0a, 0x08, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73,
+	0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x28, 0x2e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x5f,
+	0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x53,
+	0x74, 0x61, 0x72, 0x74, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x52, 0x08, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x22, 0x62, 0x0a, 0x17, 0x42, 0x61,
+	0x74, 0x63, 0x68, 0x53, 0x74, 0x61, 0x72, 0x74, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x47, 0x0a, 0x09, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x29, 0x2e, 0x72, 0x65, 0x6d, 0x6f, 0x74,
+	0x65, 0x5f, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
+	0x2e, 0x53, 0x74, 0x61, 0x72, 0x74, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x52, 0x09, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x73, 0x22, 0xb1,
+	0x01, 0x0a, 0x14, 0x52, 0x65, 0x61, 0x64, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x62, 0x75, 0x69, 0x6c, 0x64,
+	0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x62, 0x75, 0x69, 0x6c, 0x64,
+	0x49, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x2e, 0x0a, 0x13, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64,
+	0x65, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x5f, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x11, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x46, 0x69, 0x6c, 0x65,
+	0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x61, 0x67, 0x65, 0x5f, 0x73,
+	0x69, 0x7a, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x70, 0x61, 0x67, 0x65, 0x53,
+	0x69, 0x7a, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x70, 0x61, 0x67, 0x65, 0x5f, 0x74, 0x6f, 0x6b, 0x65,
+	0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x70, 0x61, 0x67, 0x65, 0x54, 0x6f, 0x6b,
+	0x65, 0x6e, 0x22, 0x80, 0x01, 0x0a, 0x15, 0x52, 0x65, 0x61, 0x64, 0x44, 0x69, 0x72, 0x65, 0x63,
+	0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3f, 0x0a, 0x07,
+	0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x25, 0x2e,
+	0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74,
+	0x70, 0x61, 0x74, 0x68, 0x73, 0x2e, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x52, 0x07, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x12, 0x26, 0x0a,
+	0x0f, 0x6e, 0x65, 0x78, 0x74, 0x5f, 0x70, 0x61, 0x67, 0x65, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x6e, 0x65, 0x78, 0x74, 0x50, 0x61, 0x67, 0x65,
+	0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x22, 0x5f, 0x0a, 0x0e, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f,
+	0x72, 0x79, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x39, 0x0a, 0x06, 0x73,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x72, 0x65,
+	0x6d, 0x6f, 0x74, 0x65, 0x5f, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x5f, 0x73, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06,
+	0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0xdb, 0x02, 0x0a, 0x16, 0x47, 0x65, 0x74, 0x44, 0x61,
+	0x65, 0x6d, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x21, 0x0a, 0x0c, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x5f, 0x70, 0x61, 0x74, 0x68,
+	0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0b, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x50,
+	0x61, 0x74, 0x68, 0x73, 0x12, 0x23, 0x0a, 0x0d, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x5f, 0x62,
+	0x75, 0x69, 0x6c, 0x64, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0c, 0x61, 0x63, 0x74,
+	0x69, 0x76, 0x65, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x73, 0x12, 0x1b, 0x0a, 0x09, 0x63, 0x61, 0x73,
+	0x5f, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x63, 0x61,
+	0x73, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x67, 0x6f, 0x72, 0x6f, 0x75, 0x74,
+	0x69, 0x6e, 0x65, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0a, 0x67, 0x6f, 0x72, 0x6f,
+	0x75, 0x74, 0x69, 0x6e, 0x65, 0x73, 0x12, 0x5d, 0x0a, 0x12, 0x62, 0x6c, 0x6f, 0x62, 0x5f, 0x66,
+	0x65, 0x74, 0x63, 0x68, 0x5f, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x2f, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x6f,
+	0x75, 0x74, 0x70, 0x75, 0x74, 0x70, 0x61, 0x74, 0x68, 0x73, 0x2e, 0x46, 0x65, 0x74, 0x63, 0x68,
+	0x4c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x44, 0x69, 0x73, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74,
+	0x69, 0x6f, 0x6e, 0x52, 0x10, 0x62, 0x6c, 0x6f, 0x62, 0x46, 0x65, 0x74, 0x63, 0x68, 0x4c, 0x61,
+	0x74, 0x65, 0x6e, 0x63, 0x79, 0x12, 0x5d, 0x0a, 0x12, 0x74, 0x72, 0x65, 0x65, 0x5f, 0x66, 0x65,
+	0x74, 0x63, 0x68, 0x5f, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x2f, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x6f, 0x75,
+	0x74, 0x70, 0x75, 0x74, 0x70, 0x61, 0x74, 0x68, 0x73, 0x2e, 0x46, 0x65, 0x74, 0x63, 0x68, 0x4c,
+	0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x44, 0x69, 0x73, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x69,
+	0x6f, 0x6e, 0x52, 0x10, 0x74, 0x72, 0x65, 0x65, 0x46, 0x65, 0x74, 0x63, 0x68, 0x4c, 0x61, 0x74,
+	0x65, 0x6e, 0x63, 0x79, 0x22, 0x7c, 0x0a, 0x1c, 0x53, 0x65, 0x74, 0x45, 0x78, 0x74, 0x65, 0x72,
+	0x6e, 0x61, 0x6c, 0x50, 0x61, 0x74, 0x68, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x5f, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x49, 0x64, 0x12,
+	0x41, 0x0a, 0x06, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32,
+	0x29, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x6f, 0x75, 0x74, 0x70,
+	0x75, 0x74, 0x70, 0x61, 0x74, 0x68, 0x73, 0x2e, 0x45, 0x78, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c,
+	0x50, 0x61, 0x74, 0x68, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x52, 0x06, 0x70, 0x6f, 0x6c, 0x69,
+	0x63, 0x79, 0x22, 0x4d, 0x0a, 0x1c, 0x47, 0x65, 0x74, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f,
+	0x72, 0x79, 0x52, 0x65, 0x73, 0x69, 0x64, 0x65, 0x6e, 0x63, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x5f, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x49, 0x64, 0x12, 0x12, 0x0a,
+	0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74,
+	0x68, 0x22, 0x52, 0x0a, 0x1d, 0x47, 0x65, 0x74, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72,
+	0x79, 0x52, 0x65, 0x73, 0x69, 0x64, 0x65, 0x6e, 0x63, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x31, 0x0a, 0x14, 0x75, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x65, 0x64, 0x5f, 0x64,
+	0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x69, 0x65, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x13, 0x75, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x65, 0x64, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74,
+	0x6f, 0x72, 0x69, 0x65, 0x73, 0x22, 0x47, 0x0a, 0x16, 0x46, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a,
+	0x65, 0x53, 0x75, 0x62, 0x74, 0x72, 0x65, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x19, 0x0a, 0x08, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x07, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x49, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61,
+	0x74, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x22, 0x63,
+	0x0a, 0x17, 0x46, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x53, 0x75, 0x62, 0x74, 0x72, 0x65,
+	0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x48, 0x0a, 0x0b, 0x74, 0x72, 0x65,
+	0x65, 0x5f, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x27,
+	0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x2e, 0x62, 0x61, 0x7a, 0x65, 0x6c, 0x2e, 0x72, 0x65, 0x6d,
+	0x6f, 0x74, 0x65, 0x2e, 0x65, 0x78, 0x65, 0x63, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x76, 0x32,
+	0x2e, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x52, 0x0a, 0x74, 0x72, 0x65, 0x65, 0x44, 0x69, 0x67,
+	0x65, 0x73, 0x74, 0x22, 0x9e, 0x01, 0x0a, 0x14, 0x53, 0x65, 0x74, 0x4f, 0x75, 0x74, 0x70, 0x75,
+	0x74, 0x50, 0x61, 0x74, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x19, 0x0a, 0x08,
+	0x62, 0x75, 0x69, 0x6c, 0x64, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07,
+	0x62, 0x75, 0x69, 0x6c, 0x64, 0x49, 0x64, 0x12, 0x51, 0x0a, 0x10, 0x72, 0x6f, 0x6f, 0x74, 0x5f,
+	0x74, 0x72, 0x65, 0x65, 0x5f, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x27, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x2e, 0x62, 0x61, 0x7a, 0x65, 0x6c, 0x2e,
+	0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x2e, 0x65, 0x78, 0x65, 0x63, 0x75, 0x74, 0x69, 0x6f, 0x6e,
+	0x2e, 0x76, 0x32, 0x2e, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x52, 0x0e, 0x72, 0x6f, 0x6f, 0x74,
+	0x54, 0x72, 0x65, 0x65, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x6f, 0x76,
+	0x65, 0x72, 0x6c, 0x61, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x6f, 0x76, 0x65,
+	0x72, 0x6c, 0x61, 0x79, 0x22, 0x42, 0x0a, 0x1a, 0x4c, 0x69, 0x73, 0x74, 0x46, 0x69, 0x6e, 0x61,
+	0x6c, 0x69, 0x7a, 0x65, 0x64, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x24, 0x0a, 0x0e, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x5f, 0x62, 0x61, 0x73,
+	0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x6f, 0x75, 0x74, 0x70,
+	0x75, 0x74, 0x42, 0x61, 0x73, 0x65, 0x49, 0x64, 0x22, 0xc0, 0x03, 0x0a, 0x0e, 0x46, 0x69, 0x6e,
+	0x61, 0x6c, 0x69, 0x7a, 0x65, 0x64, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x12, 0x19, 0x0a, 0x08, 0x62,
+	0x75, 0x69, 0x6c, 0x64, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x62,
+	0x75, 0x69, 0x6c, 0x64, 0x49, 0x64, 0x12, 0x51, 0x0a, 0x10, 0x72, 0x6f, 0x6f, 0x74, 0x5f, 0x74,
+	0x72, 0x65, 0x65, 0x5f, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x27, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x2e, 0x62, 0x61, 0x7a, 0x65, 0x6c, 0x2e, 0x72,
+	0x65, 0x6d, 0x6f, 0x74, 0x65, 0x2e, 0x65, 0x78, 0x65, 0x63, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x2e,
+	0x76, 0x32, 0x2e, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x52, 0x0e, 0x72, 0x6f, 0x6f, 0x74, 0x54,
+	0x72, 0x65, 0x65, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x12, 0x3f, 0x0a, 0x0d, 0x66, 0x69, 0x6e,
+	0x61, 0x6c, 0x69, 0x7a, 0x65, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0c, 0x66, 0x69,
+	0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x2a, 0x0a, 0x11, 0x63, 0x61,
+	0x73, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x5f, 0x66, 0x65, 0x74, 0x63, 0x68, 0x65, 0x64, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0f, 0x63, 0x61, 0x73, 0x42, 0x79, 0x74, 0x65, 0x73, 0x46,
+	0x65, 0x74, 0x63, 0x68, 0x65, 0x64, 0x12, 0x62, 0x0a, 0x0f, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74,
+	0x5f, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x39, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x6f, 0x75, 0x74, 0x70,
+	0x75, 0x74, 0x70, 0x61, 0x74, 0x68, 0x73, 0x2e, 0x46, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65,
+	0x64, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x2e, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x4d, 0x65, 0x74,
+	0x61, 0x64, 0x61, 0x74, 0x61, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0e, 0x63, 0x6c, 0x69, 0x65,
+	0x6e, 0x74, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x12, 0x2c, 0x0a, 0x12, 0x63, 0x61,
+	0x73, 0x5f, 0x66, 0x65, 0x74, 0x63, 0x68, 0x5f, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x73,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x04, 0x52, 0x10, 0x63, 0x61, 0x73, 0x46, 0x65, 0x74, 0x63, 0x68,
+	0x46, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x73, 0x1a, 0x41, 0x0a, 0x13, 0x43, 0x6c, 0x69, 0x65,
+	0x6e, 0x74, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12,
+	0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65,
+	0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x5c, 0x0a, 0x1b, 0x4c,
+	0x69, 0x73, 0x74, 0x46, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x64, 0x42, 0x75, 0x69, 0x6c,
+	0x64, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3d, 0x0a, 0x06, 0x62, 0x75,
+	0x69, 0x6c, 0x64, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x25, 0x2e, 0x62, 0x75, 0x69,
+	0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x70, 0x61, 0x74,
+	0x68, 0x73, 0x2e, 0x46, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x64, 0x42, 0x75, 0x69, 0x6c,
+	0x64, 0x52, 0x06, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x73, 0x22, 0x59, 0x0a, 0x1b, 0x53, 0x65, 0x74,
+	0x44, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x50, 0x61, 0x74, 0x68, 0x50, 0x72, 0x65, 0x66, 0x69,
+	0x78, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x62, 0x75, 0x69, 0x6c,
+	0x64, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x62, 0x75, 0x69, 0x6c,
+	0x64, 0x49, 0x64, 0x12, 0x1f, 0x0a, 0x0b, 0x70, 0x61, 0x74, 0x68, 0x5f, 0x70, 0x72, 0x65, 0x66,
+	0x69, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x70, 0x61, 0x74, 0x68, 0x50, 0x72,
+	0x65, 0x66, 0x69, 0x78, 0x22, 0xe2, 0x01, 0x0a, 0x12, 0x4d, 0x61, 0x74, 0x65, 0x72, 0x69, 0x61,
+	0x6c, 0x69, 0x7a, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x62,
+	0x75, 0x69, 0x6c, 0x64, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x62,
+	0x75, 0x69, 0x6c, 0x64, 0x49, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x32, 0x0a, 0x15, 0x6d, 0x61,
+	0x78, 0x69, 0x6d, 0x75, 0x6d, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x5f, 0x66, 0x65, 0x74, 0x63,
+	0x68, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x13, 0x6d, 0x61, 0x78, 0x69, 0x6d,
+	0x75, 0x6d, 0x42, 0x79, 0x74, 0x65, 0x73, 0x46, 0x65, 0x74, 0x63, 0x68, 0x65, 0x64, 0x12, 0x44,
+	0x0a, 0x10, 0x6d, 0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d, 0x5f, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x52, 0x0f, 0x6d, 0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d, 0x44, 0x75, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x23, 0x0a, 0x0d, 0x6d, 0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d, 0x5f,
+	0x64, 0x65, 0x70, 0x74, 0x68, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0c, 0x6d, 0x61, 0x78,
+	0x69, 0x6d, 0x75, 0x6d, 0x44, 0x65, 0x70, 0x74, 0x68, 0x22, 0x8c, 0x02, 0x0a, 0x13, 0x4d, 0x61,
+	0x74, 0x65, 0x72, 0x69, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x33, 0x0a, 0x15, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x69, 0x65, 0x73,
+	0x5f, 0x74, 0x72, 0x61, 0x76, 0x65, 0x72, 0x73, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x14, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x69, 0x65, 0x73, 0x54, 0x72, 0x61,
+	0x76, 0x65, 0x72, 0x73, 0x65, 0x64, 0x12, 0x27, 0x0a, 0x0f, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x5f,
+	0x74, 0x72, 0x61, 0x76, 0x65, 0x72, 0x73, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x0e, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x54, 0x72, 0x61, 0x76, 0x65, 0x72, 0x73, 0x65, 0x64, 0x12,
+	0x23, 0x0a, 0x0d, 0x62, 0x79, 0x74, 0x65, 0x73, 0x5f, 0x66, 0x65, 0x74, 0x63, 0x68, 0x65, 0x64,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0c, 0x62, 0x79, 0x74, 0x65, 0x73, 0x46, 0x65, 0x74,
+	0x63, 0x68, 0x65, 0x64, 0x12, 0x29, 0x0a, 0x10, 0x62, 0x75, 0x64, 0x67, 0x65, 0x74, 0x5f, 0x65,
+	0x78, 0x68, 0x61, 0x75, 0x73, 0x74, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0f,
+	0x62, 0x75, 0x64, 0x67, 0x65, 0x74, 0x45, 0x78, 0x68, 0x61, 0x75, 0x73, 0x74, 0x65, 0x64, 0x12,
+	0x47, 0x0a, 0x20, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x69, 0x65, 0x73, 0x5f, 0x62,
+	0x65, 0x79, 0x6f, 0x6e, 0x64, 0x5f, 0x6d, 0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d, 0x5f, 0x64, 0x65,
+	0x70, 0x74, 0x68, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x52, 0x1d, 0x64, 0x69, 0x72, 0x65, 0x63,
+	0x74, 0x6f, 0x72, 0x69, 0x65, 0x73, 0x42, 0x65, 0x79, 0x6f, 0x6e, 0x64, 0x4d, 0x61, 0x78, 0x69,
+	0x6d, 0x75, 0x6d, 0x44, 0x65, 0x70, 0x74, 0x68, 0x22, 0x55, 0x0a, 0x12, 0x52, 0x65, 0x6f, 0x70,
+	0x65, 0x6e, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x24,
+	0x0a, 0x0e, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x5f, 0x62, 0x61, 0x73, 0x65, 0x5f, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x42, 0x61,
+	0x73, 0x65, 0x49, 0x64, 0x12, 0x19, 0x0a, 0x08, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x5f, 0x69, 0x64,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x49, 0x64, 0x22,
+	0x52, 0x0a, 0x16, 0x49, 0x6e, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x43, 0x61, 0x63,
+	0x68, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x24, 0x0a, 0x0e, 0x6f, 0x75, 0x74,
+	0x70, 0x75, 0x74, 0x5f, 0x62, 0x61, 0x73, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0c, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x42, 0x61, 0x73, 0x65, 0x49, 0x64, 0x12,
+	0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70,
+	0x61, 0x74, 0x68, 0x22, 0xb5, 0x01, 0x0a, 0x18, 0x53, 0x74, 0x61, 0x72, 0x74, 0x42, 0x75, 0x69,
+	0x6c, 0x64, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x47, 0x0a, 0x08, 0x70, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x29, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x6f,
+	0x75, 0x74, 0x70, 0x75, 0x74, 0x70, 0x61, 0x74, 0x68, 0x73, 0x2e, 0x53, 0x74, 0x61, 0x72, 0x74,
+	0x42, 0x75, 0x69, 0x6c, 0x64, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x48, 0x00, 0x52,
+	0x08, 0x70, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x12, 0x47, 0x0a, 0x08, 0x72, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x29, 0x2e, 0x72, 0x65,
+	0x6d, 0x6f, 0x74, 0x65, 0x5f, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x5f, 0x73, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x2e, 0x53, 0x74, 0x61, 0x72, 0x74, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x48, 0x00, 0x52, 0x08, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x42, 0x07, 0x0a, 0x05, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x22, 0xe0, 0x01, 0x0a, 0x12,
+	0x53, 0x74, 0x61, 0x72, 0x74, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65,
+	0x73, 0x73, 0x12, 0x27, 0x0a, 0x0f, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x5f, 0x73, 0x63,
+	0x61, 0x6e, 0x6e, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0e, 0x65, 0x6e, 0x74,
+	0x72, 0x69, 0x65, 0x73, 0x53, 0x63, 0x61, 0x6e, 0x6e, 0x65, 0x64, 0x12, 0x23, 0x0a, 0x0d, 0x62,
+	0x6c, 0x6f, 0x62, 0x73, 0x5f, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x0c, 0x62, 0x6c, 0x6f, 0x62, 0x73, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x65, 0x64,
+	0x12, 0x27, 0x0a, 0x0f, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x5f, 0x72, 0x65, 0x6d, 0x6f,
+	0x76, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0e, 0x65, 0x6e, 0x74, 0x72, 0x69,
+	0x65, 0x73, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x64, 0x12, 0x2c, 0x0a, 0x12, 0x66, 0x69, 0x6e,
+	0x64, 0x5f, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6e, 0x67, 0x5f, 0x63, 0x61, 0x6c, 0x6c, 0x73, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x10, 0x66, 0x69, 0x6e, 0x64, 0x4d, 0x69, 0x73, 0x73, 0x69,
+	0x6e, 0x67, 0x43, 0x61, 0x6c, 0x6c, 0x73, 0x12, 0x25, 0x0a, 0x0e, 0x71, 0x75, 0x65, 0x75, 0x65,
+	0x64, 0x5f, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x0d, 0x71, 0x75, 0x65, 0x75, 0x65, 0x64, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x73, 0x22, 0x3e,
+	0x0a, 0x13, 0x43, 0x6c, 0x65, 0x61, 0x6e, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x27, 0x0a, 0x0f, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73,
+	0x5f, 0x72, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0e,
+	0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x64, 0x22, 0x66,
+	0x0a, 0x16, 0x43, 0x6c, 0x6f, 0x6e, 0x65, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x50, 0x61, 0x74,
+	0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x62, 0x75, 0x69, 0x6c,
+	0x64, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x62, 0x75, 0x69, 0x6c,
+	0x64, 0x49, 0x64, 0x12, 0x31, 0x0a, 0x15, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x5f, 0x6f, 0x75,
+	0x74, 0x70, 0x75, 0x74, 0x5f, 0x62, 0x61, 0x73, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x12, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74,
+	0x42, 0x61, 0x73, 0x65, 0x49, 0x64, 0x22, 0x6c, 0x0a, 0x17, 0x43, 0x6c, 0x6f, 0x6e, 0x65, 0x4f,
+	0x75, 0x74, 0x70, 0x75, 0x74, 0x50, 0x61, 0x74, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x51, 0x0a, 0x10, 0x72, 0x6f, 0x6f, 0x74, 0x5f, 0x74, 0x72, 0x65, 0x65, 0x5f, 0x64,
+	0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x27, 0x2e, 0x62, 0x75,
+	0x69, 0x6c, 0x64, 0x2e, 0x62, 0x61, 0x7a, 0x65, 0x6c, 0x2e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65,
+	0x2e, 0x65, 0x78, 0x65, 0x63, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x76, 0x32, 0x2e, 0x44, 0x69,
+	0x67, 0x65, 0x73, 0x74, 0x52, 0x0e, 0x72, 0x6f, 0x6f, 0x74, 0x54, 0x72, 0x65, 0x65, 0x44, 0x69,
+	0x67, 0x65, 0x73, 0x74, 0x22, 0x6b, 0x0a, 0x11, 0x53, 0x74, 0x61, 0x74, 0x44, 0x69, 0x67, 0x65,
+	0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x62, 0x75, 0x69,
+	0x6c, 0x64, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x62, 0x75, 0x69,
+	0x6c, 0x64, 0x49, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x27, 0x0a, 0x0f, 0x66, 0x6f, 0x6c, 0x6c,
+	0x6f, 0x77, 0x5f, 0x73, 0x79, 0x6d, 0x6c, 0x69, 0x6e, 0x6b, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x0e, 0x66, 0x6f, 0x6c, 0x6c, 0x6f, 0x77, 0x53, 0x79, 0x6d, 0x6c, 0x69, 0x6e, 0x6b,
+	0x73, 0x22, 0xe6, 0x01, 0x0a, 0x12, 0x53, 0x74, 0x61, 0x74, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4f, 0x0a, 0x09, 0x66, 0x69, 0x6c, 0x65,
+	0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x32, 0x2e, 0x62, 0x75,
+	0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x70, 0x61,
+	0x74, 0x68, 0x73, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x54, 0x79, 0x70, 0x65, 0x52,
+	0x08, 0x66, 0x69, 0x6c, 0x65, 0x54, 0x79, 0x70, 0x65, 0x12, 0x3f, 0x0a, 0x06, 0x64, 0x69, 0x67,
+	0x65, 0x73, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x27, 0x2e, 0x62, 0x75, 0x69, 0x6c,
+	0x64, 0x2e, 0x62, 0x61, 0x7a, 0x65, 0x6c, 0x2e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x2e, 0x65,
+	0x78, 0x65, 0x63, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x76, 0x32, 0x2e, 0x44, 0x69, 0x67, 0x65,
+	0x73, 0x74, 0x52, 0x06, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x22, 0x3e, 0x0a, 0x08, 0x46, 0x69,
+	0x6c, 0x65, 0x54, 0x79, 0x70, 0x65, 0x12, 0x08, 0x0a, 0x04, 0x46, 0x49, 0x4c, 0x45, 0x10, 0x00,
+	0x12, 0x0d, 0x0a, 0x09, 0x44, 0x49, 0x52, 0x45, 0x43, 0x54, 0x4f, 0x52, 0x59, 0x10, 0x01, 0x12,
+	0x0b, 0x0a, 0x07, 0x53, 0x59, 0x4d, 0x4c, 0x49, 0x4e, 0x4b, 0x10, 0x02, 0x12, 0x0c, 0x0a, 0x08,
+	0x45, 0x58, 0x54, 0x45, 0x52, 0x4e, 0x41, 0x4c, 0x10, 0x03, 0x22, 0x6c, 0x0a, 0x17, 0x52, 0x65,
+	0x6e, 0x61, 0x6d, 0x65, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x42, 0x61, 0x73, 0x65, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x24, 0x0a, 0x0e, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x5f,
+	0x62, 0x61, 0x73, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x6f,
+	0x75, 0x74, 0x70, 0x75, 0x74, 0x42, 0x61, 0x73, 0x65, 0x49, 0x64, 0x12, 0x2b, 0x0a, 0x12, 0x6e,
+	0x65, 0x77, 0x5f, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x5f, 0x62, 0x61, 0x73, 0x65, 0x5f, 0x69,
+	0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x6e, 0x65, 0x77, 0x4f, 0x75, 0x74, 0x70,
+	0x75, 0x74, 0x42, 0x61, 0x73, 0x65, 0x49, 0x64, 0x22, 0x4b, 0x0a, 0x23, 0x47, 0x65, 0x74, 0x4f,
+	0x75, 0x74, 0x70, 0x75, 0x74, 0x50, 0x61, 0x74, 0x68, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x46,
+	0x75, 0x6e, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x24, 0x0a, 0x0e, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x5f, 0x62, 0x61, 0x73, 0x65, 0x5f, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x42,
+	0x61, 0x73, 0x65, 0x49, 0x64, 0x22, 0x7d, 0x0a, 0x24, 0x47, 0x65, 0x74, 0x4f, 0x75, 0x74, 0x70,
+	0x75, 0x74, 0x50, 0x61, 0x74, 0x68, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x46, 0x75, 0x6e, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x55, 0x0a,
+	0x10, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x5f, 0x66, 0x75, 0x6e, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2a, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62,
+	0x61, 0x72, 0x6e, 0x2e, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x70, 0x61, 0x74, 0x68, 0x73, 0x2e,
+	0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x46, 0x75, 0x6e, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x55, 0x73,
+	0x61, 0x67, 0x65, 0x52, 0x0f, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x46, 0x75, 0x6e, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x22, 0xb4, 0x01, 0x0a, 0x13, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x46,
+	0x75, 0x6e, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x55, 0x73, 0x61, 0x67, 0x65, 0x12, 0x23, 0x0a, 0x0d,
+	0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0c, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x4e, 0x61, 0x6d,
+	0x65, 0x12, 0x5e, 0x0a, 0x0f, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x5f, 0x66, 0x75, 0x6e, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x35, 0x2e, 0x62, 0x75, 0x69,
+	0x6c, 0x64, 0x2e, 0x62, 0x61, 0x7a, 0x65, 0x6c, 0x2e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x2e,
+	0x65, 0x78, 0x65, 0x63, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x76, 0x32, 0x2e, 0x44, 0x69, 0x67,
+	0x65, 0x73, 0x74, 0x46, 0x75, 0x6e, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x56, 0x61, 0x6c, 0x75,
+	0x65, 0x52, 0x0e, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x46, 0x75, 0x6e, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x12, 0x18, 0x0a, 0x07, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x73, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x07, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x73, 0x22, 0xc5, 0x01, 0x0a, 0x14,
+	0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x54, 0x61, 0x72, 0x62, 0x61, 0x6c, 0x6c, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x5f, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x49, 0x64, 0x12,
+	0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70,
+	0x61, 0x74, 0x68, 0x12, 0x29, 0x0a, 0x10, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x5f, 0x70,
+	0x61, 0x74, 0x74, 0x65, 0x72, 0x6e, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0f, 0x69,
+	0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x50, 0x61, 0x74, 0x74, 0x65, 0x72, 0x6e, 0x73, 0x12, 0x28,
+	0x0a, 0x10, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x5f, 0x62, 0x79, 0x74,
+	0x65, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0e, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x53,
+	0x69, 0x7a, 0x65, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x29, 0x0a, 0x10, 0x72, 0x65, 0x61, 0x64,
+	0x5f, 0x63, 0x6f, 0x6e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x0f, 0x72, 0x65, 0x61, 0x64, 0x43, 0x6f, 0x6e, 0x63, 0x75, 0x72, 0x72, 0x65,
+	0x6e, 0x63, 0x79, 0x22, 0x75, 0x0a, 0x15, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x54, 0x61, 0x72,
+	0x62, 0x61, 0x6c, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x12, 0x0a, 0x04,
+	0x64, 0x61, 0x74, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61,
+	0x12, 0x48, 0x0a, 0x08, 0x70, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x2c, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x6f,
+	0x75, 0x74, 0x70, 0x75, 0x74, 0x70, 0x61, 0x74, 0x68, 0x73, 0x2e, 0x45, 0x78, 0x70, 0x6f, 0x72,
+	0x74, 0x54, 0x61, 0x72, 0x62, 0x61, 0x6c, 0x6c, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73,
+	0x52, 0x08, 0x70, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x22, 0x72, 0x0a, 0x15, 0x45, 0x78,
+	0x70, 0x6f, 0x72, 0x74, 0x54, 0x61, 0x72, 0x62, 0x61, 0x6c, 0x6c, 0x50, 0x72, 0x6f, 0x67, 0x72,
+	0x65, 0x73, 0x73, 0x12, 0x29, 0x0a, 0x10, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x5f, 0x65,
+	0x78, 0x70, 0x6f, 0x72, 0x74, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0f, 0x65,
+	0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x65, 0x64, 0x12, 0x2e,
+	0x0a, 0x13, 0x66, 0x69, 0x6c, 0x65, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x5f, 0x65, 0x78, 0x70,
+	0x6f, 0x72, 0x74, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x11, 0x66, 0x69, 0x6c,
+	0x65, 0x42, 0x79, 0x74, 0x65, 0x73, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x65, 0x64, 0x22, 0x2d,
+	0x0a, 0x10, 0x53, 0x65, 0x61, 0x6c, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x5f, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x49, 0x64, 0x22, 0x34, 0x0a,
+	0x17, 0x52, 0x65, 0x70, 0x61, 0x69, 0x72, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x50, 0x61, 0x74,
+	0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x62, 0x75, 0x69, 0x6c,
+	0x64, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x62, 0x75, 0x69, 0x6c,
+	0x64, 0x49, 0x64, 0x22, 0x3f, 0x0a, 0x18, 0x52, 0x65, 0x70, 0x61, 0x69, 0x72, 0x4f, 0x75, 0x74,
+	0x70, 0x75, 0x74, 0x50, 0x61, 0x74, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x23, 0x0a, 0x0d, 0x72, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x64, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x73,
+	0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0c, 0x72, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x64, 0x50,
+	0x61, 0x74, 0x68, 0x73, 0x22, 0x31, 0x0a, 0x14, 0x47, 0x65, 0x74, 0x4f, 0x75, 0x74, 0x70, 0x75,
+	0x74, 0x50, 0x61, 0x74, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x19, 0x0a, 0x08,
+	0x62, 0x75, 0x69, 0x6c, 0x64, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07,
+	0x62, 0x75, 0x69, 0x6c, 0x64, 0x49, 0x64, 0x22, 0xa1, 0x02, 0x0a, 0x15, 0x47, 0x65, 0x74, 0x4f,
+	0x75, 0x74, 0x70, 0x75, 0x74, 0x50, 0x61, 0x74, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x5f, 0x70, 0x61, 0x74, 0x68,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x50, 0x61,
+	0x74, 0x68, 0x12, 0x2c, 0x0a, 0x12, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x5f, 0x70, 0x61, 0x74,
+	0x68, 0x5f, 0x73, 0x75, 0x66, 0x66, 0x69, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x10,
+	0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x50, 0x61, 0x74, 0x68, 0x53, 0x75, 0x66, 0x66, 0x69, 0x78,
+	0x12, 0x73, 0x0a, 0x13, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x5f,
+	0x61, 0x6c, 0x69, 0x61, 0x73, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x43, 0x2e,
+	0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74,
+	0x70, 0x61, 0x74, 0x68, 0x73, 0x2e, 0x47, 0x65, 0x74, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x50,
+	0x61, 0x74, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x4f, 0x75, 0x74, 0x70,
+	0x75, 0x74, 0x50, 0x61, 0x74, 0x68, 0x41, 0x6c, 0x69, 0x61, 0x73, 0x65, 0x73, 0x45, 0x6e, 0x74,
+	0x72, 0x79, 0x52, 0x11, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x50, 0x61, 0x74, 0x68, 0x41, 0x6c,
+	0x69, 0x61, 0x73, 0x65, 0x73, 0x1a, 0x44, 0x0a, 0x16, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x50,
+	0x61, 0x74, 0x68, 0x41, 0x6c, 0x69, 0x61, 0x73, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12,
+	0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65,
+	0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xe2, 0x01, 0x0a, 0x11,
+	0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x54, 0x72, 0x65, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x23, 0x0a, 0x0d, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x5f, 0x6e, 0x61,
+	0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e,
+	0x63, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x5e, 0x0a, 0x0f, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74,
+	0x5f, 0x66, 0x75, 0x6e, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32,
+	0x35, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x2e, 0x62, 0x61, 0x7a, 0x65, 0x6c, 0x2e, 0x72, 0x65,
+	0x6d, 0x6f, 0x74, 0x65, 0x2e, 0x65, 0x78, 0x65, 0x63, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x76,
+	0x32, 0x2e, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x46, 0x75, 0x6e, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x0e, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x46, 0x75,
+	0x6e, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x48, 0x0a, 0x0b, 0x74, 0x72, 0x65, 0x65, 0x5f, 0x64,
+	0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x27, 0x2e, 0x62, 0x75,
+	0x69, 0x6c, 0x64, 0x2e, 0x62, 0x61, 0x7a, 0x65, 0x6c, 0x2e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65,
+	0x2e, 0x65, 0x78, 0x65, 0x63, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x76, 0x32, 0x2e, 0x44, 0x69,
+	0x67, 0x65, 0x73, 0x74, 0x52, 0x0a, 0x74, 0x72, 0x65, 0x65, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74,
+	0x22, 0xd4, 0x01, 0x0a, 0x12, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x54, 0x72, 0x65, 0x65, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x63, 0x0a, 0x19, 0x6d, 0x69, 0x73, 0x73, 0x69,
+	0x6e, 0x67, 0x5f, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x5f, 0x64, 0x69, 0x67,
+	0x65, 0x73, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x27, 0x2e, 0x62, 0x75, 0x69,
+	0x6c, 0x64, 0x2e, 0x62, 0x61, 0x7a, 0x65, 0x6c, 0x2e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x2e,
+	0x65, 0x78, 0x65, 0x63, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x76, 0x32, 0x2e, 0x44, 0x69, 0x67,
+	0x65, 0x73, 0x74, 0x52, 0x17, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6e, 0x67, 0x44, 0x69, 0x72, 0x65,
+	0x63, 0x74, 0x6f, 0x72, 0x79, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x73, 0x12, 0x59, 0x0a, 0x14,
+	0x6d, 0x69, 0x73, 0x73, 0x69, 0x6e, 0x67, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x5f, 0x64, 0x69, 0x67,
+	0x65, 0x73, 0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x27, 0x2e, 0x62, 0x75, 0x69,
+	0x6c, 0x64, 0x2e, 0x62, 0x61, 0x7a, 0x65, 0x6c, 0x2e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x2e,
+	0x65, 0x78, 0x65, 0x63, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x76, 0x32, 0x2e, 0x44, 0x69, 0x67,
+	0x65, 0x73, 0x74, 0x52, 0x12, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6e, 0x67, 0x46, 0x69, 0x6c, 0x65,
+	0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x73, 0x22, 0xcb, 0x01, 0x0a, 0x17, 0x53, 0x65, 0x74, 0x42,
+	0x75, 0x69, 0x6c, 0x64, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x5f, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x49, 0x64, 0x12, 0x58,
+	0x0a, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x3c, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x6f, 0x75, 0x74,
+	0x70, 0x75, 0x74, 0x70, 0x61, 0x74, 0x68, 0x73, 0x2e, 0x53, 0x65, 0x74, 0x42, 0x75, 0x69, 0x6c,
+	0x64, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x2e, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x08,
+	0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x1a, 0x3b, 0x0a, 0x0d, 0x4d, 0x65, 0x74, 0x61,
+	0x64, 0x61, 0x74, 0x61, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xae, 0x01, 0x0a, 0x11, 0x44, 0x69, 0x66, 0x66, 0x42, 0x75,
+	0x69, 0x6c, 0x64, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x24, 0x0a, 0x0e, 0x6f,
+	0x75, 0x74, 0x70, 0x75, 0x74, 0x5f, 0x62, 0x61, 0x73, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0c, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x42, 0x61, 0x73, 0x65, 0x49,
+	0x64, 0x12, 0x20, 0x0a, 0x0c, 0x6f, 0x6c, 0x64, 0x5f, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x5f, 0x69,
+	0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6f, 0x6c, 0x64, 0x42, 0x75, 0x69, 0x6c,
+	0x64, 0x49, 0x64, 0x12, 0x20, 0x0a, 0x0c, 0x6e, 0x65, 0x77, 0x5f, 0x62, 0x75, 0x69, 0x6c, 0x64,
+	0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6e, 0x65, 0x77, 0x42, 0x75,
+	0x69, 0x6c, 0x64, 0x49, 0x64, 0x12, 0x2f, 0x0a, 0x13, 0x6d, 0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d,
+	0x5f, 0x64, 0x69, 0x66, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x73, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x12, 0x6d, 0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d, 0x44, 0x69, 0x66, 0x66, 0x65,
+	0x72, 0x65, 0x6e, 0x63, 0x65, 0x73, 0x22, 0x7b, 0x0a, 0x12, 0x44, 0x69, 0x66, 0x66, 0x42, 0x75,
+	0x69, 0x6c, 0x64, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x47, 0x0a, 0x0b,
+	0x64, 0x69, 0x66, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x25, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x6f, 0x75,
+	0x74, 0x70, 0x75, 0x74, 0x70, 0x61, 0x74, 0x68, 0x73, 0x2e, 0x50, 0x61, 0x74, 0x68, 0x44, 0x69,
+	0x66, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x52, 0x0b, 0x64, 0x69, 0x66, 0x66, 0x65, 0x72,
+	0x65, 0x6e, 0x63, 0x65, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x72, 0x75, 0x6e, 0x63, 0x61, 0x74,
+	0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x74, 0x72, 0x75, 0x6e, 0x63, 0x61,
+	0x74, 0x65, 0x64, 0x22, 0xa0, 0x01, 0x0a, 0x0e, 0x50, 0x61, 0x74, 0x68, 0x44, 0x69, 0x66, 0x66,
+	0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x3c, 0x0a, 0x08, 0x6f, 0x6c,
+	0x64, 0x5f, 0x6e, 0x6f, 0x64, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x62,
+	0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x70,
+	0x61, 0x74, 0x68, 0x73, 0x2e, 0x44, 0x69, 0x66, 0x66, 0x65, 0x64, 0x4e, 0x6f, 0x64, 0x65, 0x52,
+	0x07, 0x6f, 0x6c, 0x64, 0x4e, 0x6f, 0x64, 0x65, 0x12, 0x3c, 0x0a, 0x08, 0x6e, 0x65, 0x77, 0x5f,
+	0x6e, 0x6f, 0x64, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x62, 0x75, 0x69,
+	0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x70, 0x61, 0x74,
+	0x68, 0x73, 0x2e, 0x44, 0x69, 0x66, 0x66, 0x65, 0x64, 0x4e, 0x6f, 0x64, 0x65, 0x52, 0x07, 0x6e,
+	0x65, 0x77, 0x4e, 0x6f, 0x64, 0x65, 0x22, 0xf4, 0x01, 0x0a, 0x0a, 0x44, 0x69, 0x66, 0x66, 0x65,
+	0x64, 0x4e, 0x6f, 0x64, 0x65, 0x12, 0x3f, 0x0a, 0x04, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x29, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x2e, 0x62, 0x61, 0x7a, 0x65,
+	0x6c, 0x2e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x2e, 0x65, 0x78, 0x65, 0x63, 0x75, 0x74, 0x69,
+	0x6f, 0x6e, 0x2e, 0x76, 0x32, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x4e, 0x6f, 0x64, 0x65, 0x48, 0x00,
+	0x52, 0x04, 0x66, 0x69, 0x6c, 0x65, 0x12, 0x4e, 0x0a, 0x09, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74,
+	0x6f, 0x72, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2e, 0x2e, 0x62, 0x75, 0x69, 0x6c,
+	0x64, 0x2e, 0x62, 0x61, 0x7a, 0x65, 0x6c, 0x2e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x2e, 0x65,
+	0x78, 0x65, 0x63, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x76, 0x32, 0x2e, 0x44, 0x69, 0x72, 0x65,
+	0x63, 0x74, 0x6f, 0x72, 0x79, 0x4e, 0x6f, 0x64, 0x65, 0x48, 0x00, 0x52, 0x09, 0x64, 0x69, 0x72,
+	0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x48, 0x0a, 0x07, 0x73, 0x79, 0x6d, 0x6c, 0x69, 0x6e,
+	0x6b, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2c, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x2e,
+	0x62, 0x61, 0x7a, 0x65, 0x6c, 0x2e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x2e, 0x65, 0x78, 0x65,
+	0x63, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x76, 0x32, 0x2e, 0x53, 0x79, 0x6d, 0x6c, 0x69, 0x6e,
+	0x6b, 0x4e, 0x6f, 0x64, 0x65, 0x48, 0x00, 0x52, 0x07, 0x73, 0x79, 0x6d, 0x6c, 0x69, 0x6e, 0x6b,
+	0x42, 0x0b, 0x0a, 0x09, 0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x22, 0x6e, 0x0a,
+	0x18, 0x52, 0x65, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x50, 0x61,
+	0x74, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x24, 0x0a, 0x0e, 0x6f, 0x75, 0x74,
+	0x70, 0x75, 0x74, 0x5f, 0x62, 0x61, 0x73, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0c, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x42, 0x61, 0x73, 0x65, 0x49, 0x64, 0x12,
+	0x2c, 0x0a, 0x12, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x5f, 0x70,
+	0x72, 0x65, 0x66, 0x69, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x10, 0x6f, 0x75, 0x74,
+	0x70, 0x75, 0x74, 0x50, 0x61, 0x74, 0x68, 0x50, 0x72, 0x65, 0x66, 0x69, 0x78, 0x22, 0x6a, 0x0a,
+	0x19, 0x52, 0x65, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x50, 0x61,
+	0x74, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2c, 0x0a, 0x12, 0x6f, 0x75,
+	0x74, 0x70, 0x75, 0x74, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x5f, 0x73, 0x75, 0x66, 0x66, 0x69, 0x78,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x10, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x50, 0x61,
+	0x74, 0x68, 0x53, 0x75, 0x66, 0x66, 0x69, 0x78, 0x12, 0x1f, 0x0a, 0x0b, 0x6f, 0x75, 0x74, 0x70,
+	0x75, 0x74, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6f,
+	0x75, 0x74, 0x70, 0x75, 0x74, 0x50, 0x61, 0x74, 0x68, 0x2a, 0x34, 0x0a, 0x11, 0x46, 0x65, 0x74,
+	0x63, 0x68, 0x65, 0x64, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0b,
+	0x0a, 0x07, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x00, 0x12, 0x08, 0x0a, 0x04, 0x42,
+	0x4c, 0x4f, 0x42, 0x10, 0x01, 0x12, 0x08, 0x0a, 0x04, 0x54, 0x52, 0x45, 0x45, 0x10, 0x02, 0x2a,
+	0x78, 0x0a, 0x12, 0x45, 0x78, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x50, 0x61, 0x74, 0x68, 0x50,
+	0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x13, 0x0a, 0x0f, 0x52, 0x45, 0x54, 0x55, 0x52, 0x4e, 0x5f,
+	0x45, 0x58, 0x54, 0x45, 0x52, 0x4e, 0x41, 0x4c, 0x10, 0x00, 0x12, 0x10, 0x0a, 0x0c, 0x52, 0x45,
+	0x54, 0x55, 0x52, 0x4e, 0x5f, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x10, 0x01, 0x12, 0x15, 0x0a, 0x11,
+	0x52, 0x45, 0x54, 0x55, 0x52, 0x4e, 0x5f, 0x52, 0x41, 0x57, 0x5f, 0x54, 0x41, 0x52, 0x47, 0x45,
+	0x54, 0x10, 0x02, 0x12, 0x24, 0x0a, 0x20, 0x52, 0x45, 0x54, 0x55, 0x52, 0x4e, 0x5f, 0x45, 0x52,
+	0x52, 0x4f, 0x52, 0x5f, 0x4f, 0x4e, 0x5f, 0x50, 0x41, 0x52, 0x45, 0x4e, 0x54, 0x5f, 0x54, 0x52,
+	0x41, 0x56, 0x45, 0x52, 0x53, 0x41, 0x4c, 0x10, 0x03, 0x32, 0xb0, 0x22, 0x0a, 0x0b, 0x4f, 0x75,
+	0x74, 0x70, 0x75, 0x74, 0x50, 0x61, 0x74, 0x68, 0x73, 0x12, 0x62, 0x0a, 0x0a, 0x53, 0x74, 0x61,
+	0x74, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12, 0x27, 0x2e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65,
+	0x5f, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e,
+	0x42, 0x61, 0x74, 0x63, 0x68, 0x53, 0x74, 0x61, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x29, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x6f, 0x75, 0x74,
+	0x70, 0x75, 0x74, 0x70, 0x61, 0x74, 0x68, 0x73, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x53, 0x74, 0x72,
+	0x65, 0x61, 0x6d, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x30, 0x01, 0x12, 0x6d, 0x0a,
+	0x0e, 0x47, 0x65, 0x74, 0x41, 0x63, 0x74, 0x69, 0x76, 0x65, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x12,
+	0x2c, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x6f, 0x75, 0x74, 0x70,
+	0x75, 0x74, 0x70, 0x61, 0x74, 0x68, 0x73, 0x2e, 0x47, 0x65, 0x74, 0x41, 0x63, 0x74, 0x69, 0x76,
+	0x65, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2d, 0x2e,
+	0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74,
+	0x70, 0x61, 0x74, 0x68, 0x73, 0x2e, 0x47, 0x65, 0x74, 0x41, 0x63, 0x74, 0x69, 0x76, 0x65, 0x42,
+	0x75, 0x69, 0x6c, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4e, 0x0a, 0x0a,
+	0x41, 0x62, 0x6f, 0x72, 0x74, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x12, 0x28, 0x2e, 0x62, 0x75, 0x69,
+	0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x70, 0x61, 0x74,
+	0x68, 0x73, 0x2e, 0x41, 0x62, 0x6f, 0x72, 0x74, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x7c, 0x0a, 0x13,
+	0x47, 0x65, 0x74, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x50, 0x61, 0x74, 0x68, 0x45, 0x72, 0x72,
+	0x6f, 0x72, 0x73, 0x12, 0x31, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e,
+	0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x70, 0x61, 0x74, 0x68, 0x73, 0x2e, 0x47, 0x65, 0x74, 0x4f,
+	0x75, 0x74, 0x70, 0x75, 0x74, 0x50, 0x61, 0x74, 0x68, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x32, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61,
+	0x72, 0x6e, 0x2e, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x70, 0x61, 0x74, 0x68, 0x73, 0x2e, 0x47,
+	0x65, 0x74, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x50, 0x61, 0x74, 0x68, 0x45, 0x72, 0x72, 0x6f,
+	0x72, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x61, 0x0a, 0x0a, 0x45, 0x78,
+	0x70, 0x6f, 0x72, 0x74, 0x54, 0x72, 0x65, 0x65, 0x12, 0x28, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64,
+	0x62, 0x61, 0x72, 0x6e, 0x2e, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x70, 0x61, 0x74, 0x68, 0x73,
+	0x2e, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x54, 0x72, 0x65, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x29, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x6f,
+	0x75, 0x74, 0x70, 0x75, 0x74, 0x70, 0x61, 0x74, 0x68, 0x73, 0x2e, 0x45, 0x78, 0x70, 0x6f, 0x72,
+	0x74, 0x54, 0x72, 0x65, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x5c, 0x0a,
+	0x11, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x52, 0x6f, 0x6f, 0x74, 0x53, 0x79, 0x6d, 0x6c, 0x69,
+	0x6e, 0x6b, 0x12, 0x2f, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x6f,
+	0x75, 0x74, 0x70, 0x75, 0x74, 0x70, 0x61, 0x74, 0x68, 0x73, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74,
+	0x65, 0x52, 0x6f, 0x6f, 0x74, 0x53, 0x79, 0x6d, 0x6c, 0x69, 0x6e, 0x6b, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x5c, 0x0a, 0x11, 0x52,
+	0x65, 0x6d, 0x6f, 0x76, 0x65, 0x52, 0x6f, 0x6f, 0x74, 0x53, 0x79, 0x6d, 0x6c, 0x69, 0x6e, 0x6b,
+	0x12, 0x2f, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x6f, 0x75, 0x74,
+	0x70, 0x75, 0x74, 0x70, 0x61, 0x74, 0x68, 0x73, 0x2e, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x52,
+	0x6f, 0x6f, 0x74, 0x53, 0x79, 0x6d, 0x6c, 0x69, 0x6e, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x78, 0x0a, 0x15, 0x42, 0x65, 0x73,
+	0x74, 0x45, 0x66, 0x66, 0x6f, 0x72, 0x74, 0x42, 0x61, 0x74, 0x63, 0x68, 0x43, 0x72, 0x65, 0x61,
+	0x74, 0x65, 0x12, 0x29, 0x2e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x5f, 0x6f, 0x75, 0x74, 0x70,
+	0x75, 0x74, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68,
+	0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x34, 0x2e,
+	0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74,
+	0x70, 0x61, 0x74, 0x68, 0x73, 0x2e, 0x42, 0x65, 0x73, 0x74, 0x45, 0x66, 0x66, 0x6f, 0x72, 0x74,
+	0x42, 0x61, 0x74, 0x63, 0x68, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x69, 0x0a, 0x0c, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x74, 0x72,
+	0x65, 0x61, 0x6d, 0x12, 0x2a, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e,
+	0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x70, 0x61, 0x74, 0x68, 0x73, 0x2e, 0x43, 0x72, 0x65, 0x61,
+	0x74, 0x65, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x2b, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x6f, 0x75, 0x74, 0x70,
+	0x75, 0x74, 0x70, 0x61, 0x74, 0x68, 0x73, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x74,
+	0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x28, 0x01, 0x12, 0x7c,
+	0x0a, 0x13, 0x45, 0x78, 0x74, 0x65, 0x6e, 0x64, 0x65, 0x64, 0x42, 0x61, 0x74, 0x63, 0x68, 0x43,
+	0x72, 0x65, 0x61, 0x74, 0x65, 0x12, 0x31, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72,
+	0x6e, 0x2e, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x70, 0x61, 0x74, 0x68, 0x73, 0x2e, 0x45, 0x78,
+	0x74, 0x65, 0x6e, 0x64, 0x65, 0x64, 0x42, 0x61, 0x74, 0x63, 0x68, 0x43, 0x72, 0x65, 0x61, 0x74,
+	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x32, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64,
+	0x62, 0x61, 0x72, 0x6e, 0x2e, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x70, 0x61, 0x74, 0x68, 0x73,
+	0x2e, 0x45, 0x78, 0x74, 0x65, 0x6e, 0x64, 0x65, 0x64, 0x42, 0x61, 0x74, 0x63, 0x68, 0x43, 0x72,
+	0x65, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x79, 0x0a, 0x12,
+	0x47, 0x65, 0x74, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x50, 0x61, 0x74, 0x68, 0x53, 0x74, 0x61,
+	0x74, 0x73, 0x12, 0x30, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x6f,
+	0x75, 0x74, 0x70, 0x75, 0x74, 0x70, 0x61, 0x74, 0x68, 0x73, 0x2e, 0x47, 0x65, 0x74, 0x4f, 0x75,
+	0x74, 0x70, 0x75, 0x74, 0x50, 0x61, 0x74, 0x68, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x31, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e,
+	0x2e, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x70, 0x61, 0x74, 0x68, 0x73, 0x2e, 0x47, 0x65, 0x74,
+	0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x50, 0x61, 0x74, 0x68, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x76, 0x0a, 0x11, 0x45, 0x78, 0x74, 0x65, 0x6e,
+	0x64, 0x65, 0x64, 0x42, 0x61, 0x74, 0x63, 0x68, 0x53, 0x74, 0x61, 0x74, 0x12, 0x2f, 0x2e, 0x62,
+	0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x70,
+	0x61, 0x74, 0x68, 0x73, 0x2e, 0x45, 0x78, 0x74, 0x65, 0x6e, 0x64, 0x65, 0x64, 0x42, 0x61, 0x74,
+	0x63, 0x68, 0x53, 0x74, 0x61, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x30, 0x2e,
+	0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74,
+	0x70, 0x61, 0x74, 0x68, 0x73, 0x2e, 0x45, 0x78, 0x74, 0x65, 0x6e, 0x64, 0x65, 0x64, 0x42, 0x61,
+	0x74, 0x63, 0x68, 0x53, 0x74, 0x61, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x70, 0x0a, 0x0f, 0x42, 0x61, 0x74, 0x63, 0x68, 0x53, 0x74, 0x61, 0x72, 0x74, 0x42, 0x75, 0x69,
+	0x6c, 0x64, 0x12, 0x2d, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x6f,
+	0x75, 0x74, 0x70, 0x75, 0x74, 0x70, 0x61, 0x74, 0x68, 0x73, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68,
+	0x53, 0x74, 0x61, 0x72, 0x74, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x2e, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x6f, 0x75,
+	0x74, 0x70, 0x75, 0x74, 0x70, 0x61, 0x74, 0x68, 0x73, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x53,
+	0x74, 0x61, 0x72, 0x74, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x6a, 0x0a, 0x0d, 0x52, 0x65, 0x61, 0x64, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f,
+	0x72, 0x79, 0x12, 0x2b, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x6f,
+	0x75, 0x74, 0x70, 0x75, 0x74, 0x70, 0x61, 0x74, 0x68, 0x73, 0x2e, 0x52, 0x65, 0x61, 0x64, 0x44,
+	0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x2c, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x6f, 0x75, 0x74, 0x70,
+	0x75, 0x74, 0x70, 0x61, 0x74, 0x68, 0x73, 0x2e, 0x52, 0x65, 0x61, 0x64, 0x44, 0x69, 0x72, 0x65,
+	0x63, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x57, 0x0a,
+	0x0e, 0x47, 0x65, 0x74, 0x44, 0x61, 0x65, 0x6d, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x73, 0x12,
+	0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x2d, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62,
+	0x61, 0x72, 0x6e, 0x2e, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x70, 0x61, 0x74, 0x68, 0x73, 0x2e,
+	0x47, 0x65, 0x74, 0x44, 0x61, 0x65, 0x6d, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x64, 0x0a, 0x15, 0x53, 0x65, 0x74, 0x45, 0x78, 0x74,
+	0x65, 0x72, 0x6e, 0x61, 0x6c, 0x50, 0x61, 0x74, 0x68, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12,
+	0x33, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x6f, 0x75, 0x74, 0x70,
+	0x75, 0x74, 0x70, 0x61, 0x74, 0x68, 0x73, 0x2e, 0x53, 0x65, 0x74, 0x45, 0x78, 0x74, 0x65, 0x72,
+	0x6e, 0x61, 0x6c, 0x50, 0x61, 0x74, 0x68, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x82, 0x01, 0x0a,
+	0x15, 0x47, 0x65, 0x74, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x73,
+	0x69, 0x64, 0x65, 0x6e, 0x63, 0x79, 0x12, 0x33, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61,
+	0x72, 0x6e, 0x2e, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x70, 0x61, 0x74, 0x68, 0x73, 0x2e, 0x47,
+	0x65, 0x74, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x73, 0x69, 0x64,
+	0x65, 0x6e, 0x63, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x34, 0x2e, 0x62, 0x75,
+	0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x70, 0x61,
+	0x74, 0x68, 0x73, 0x2e, 0x47, 0x65, 0x74, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79,
+	0x52, 0x65, 0x73, 0x69, 0x64, 0x65, 0x6e, 0x63, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x54, 0x0a, 0x0d, 0x53, 0x65, 0x74, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x50, 0x61,
+	0x74, 0x68, 0x12, 0x2b, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x6f,
+	0x75, 0x74, 0x70, 0x75, 0x74, 0x70, 0x61, 0x74, 0x68, 0x73, 0x2e, 0x53, 0x65, 0x74, 0x4f, 0x75,
+	0x74, 0x70, 0x75, 0x74, 0x50, 0x61, 0x74, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x70, 0x0a, 0x0f, 0x46, 0x69, 0x6e, 0x61, 0x6c,
+	0x69, 0x7a, 0x65, 0x53, 0x75, 0x62, 0x74, 0x72, 0x65, 0x65, 0x12, 0x2d, 0x2e, 0x62, 0x75, 0x69,
+	0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x70, 0x61, 0x74,
+	0x68, 0x73, 0x2e, 0x46, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x53, 0x75, 0x62, 0x74, 0x72,
+	0x65, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2e, 0x2e, 0x62, 0x75, 0x69, 0x6c,
+	0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x70, 0x61, 0x74, 0x68,
+	0x73, 0x2e, 0x46, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x53, 0x75, 0x62, 0x74, 0x72, 0x65,
+	0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x7c, 0x0a, 0x13, 0x4c, 0x69, 0x73,
+	0x74, 0x46, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x64, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x73,
+	0x12, 0x31, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x6f, 0x75, 0x74,
+	0x70, 0x75, 0x74, 0x70, 0x61, 0x74, 0x68, 0x73, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x46, 0x69, 0x6e,
+	0x61, 0x6c, 0x69, 0x7a, 0x65, 0x64, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x32, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e,
+	0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x70, 0x61, 0x74, 0x68, 0x73, 0x2e, 0x4c, 0x69, 0x73, 0x74,
+	0x46, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x64, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x73, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x62, 0x0a, 0x14, 0x53, 0x65, 0x74, 0x44, 0x65,
+	0x66, 0x61, 0x75, 0x6c, 0x74, 0x50, 0x61, 0x74, 0x68, 0x50, 0x72, 0x65, 0x66, 0x69, 0x78, 0x12,
+	0x32, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x6f, 0x75, 0x74, 0x70,
+	0x75, 0x74, 0x70, 0x61, 0x74, 0x68, 0x73, 0x2e, 0x53, 0x65, 0x74, 0x44, 0x65, 0x66, 0x61, 0x75,
+	0x6c, 0x74, 0x50, 0x61, 0x74, 0x68, 0x50, 0x72, 0x65, 0x66, 0x69, 0x78, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x73, 0x0a, 0x10, 0x52,
+	0x65, 0x70, 0x61, 0x69, 0x72, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x50, 0x61, 0x74, 0x68, 0x12,
+	0x2e, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x6f, 0x75, 0x74, 0x70,
+	0x75, 0x74, 0x70, 0x61, 0x74, 0x68, 0x73, 0x2e, 0x52, 0x65, 0x70, 0x61, 0x69, 0x72, 0x4f, 0x75,
+	0x74, 0x70, 0x75, 0x74, 0x50, 0x61, 0x74, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x2f, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x6f, 0x75, 0x74, 0x70,
+	0x75, 0x74, 0x70, 0x61, 0x74, 0x68, 0x73, 0x2e, 0x52, 0x65, 0x70, 0x61, 0x69, 0x72, 0x4f, 0x75,
+	0x74, 0x70, 0x75, 0x74, 0x50, 0x61, 0x74, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x66, 0x0a, 0x0b, 0x4d, 0x61, 0x74, 0x65, 0x72, 0x69, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x12,
+	0x29, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x6f, 0x75, 0x74, 0x70,
+	0x75, 0x74, 0x70, 0x61, 0x74, 0x68, 0x73, 0x2e, 0x4d, 0x61, 0x74, 0x65, 0x72, 0x69, 0x61, 0x6c,
+	0x69, 0x7a, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2a, 0x2e, 0x62, 0x75, 0x69,
+	0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x70, 0x61, 0x74,
+	0x68, 0x73, 0x2e, 0x4d, 0x61, 0x74, 0x65, 0x72, 0x69, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x30, 0x01, 0x12, 0x63, 0x0a, 0x0b, 0x52, 0x65, 0x6f, 0x70,
+	0x65, 0x6e, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x12, 0x29, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62,
+	0x61, 0x72, 0x6e, 0x2e, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x70, 0x61, 0x74, 0x68, 0x73, 0x2e,
+	0x52, 0x65, 0x6f, 0x70, 0x65, 0x6e, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x29, 0x2e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x5f, 0x6f, 0x75, 0x74, 0x70,
+	0x75, 0x74, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x53, 0x74, 0x61, 0x72, 0x74,
+	0x42, 0x75, 0x69, 0x6c, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x58, 0x0a,
+	0x0f, 0x49, 0x6e, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x43, 0x61, 0x63, 0x68, 0x65,
+	0x12, 0x2d, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x6f, 0x75, 0x74,
+	0x70, 0x75, 0x74, 0x70, 0x61, 0x74, 0x68, 0x73, 0x2e, 0x49, 0x6e, 0x76, 0x61, 0x6c, 0x69, 0x64,
+	0x61, 0x74, 0x65, 0x43, 0x61, 0x63, 0x68, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x6f, 0x0a, 0x10, 0x53, 0x74, 0x61, 0x72, 0x74,
+	0x42, 0x75, 0x69, 0x6c, 0x64, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12, 0x28, 0x2e, 0x72, 0x65,
+	0x6d, 0x6f, 0x74, 0x65, 0x5f, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x5f, 0x73, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x2e, 0x53, 0x74, 0x61, 0x72, 0x74, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2f, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72,
+	0x6e, 0x2e, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x70, 0x61, 0x74, 0x68, 0x73, 0x2e, 0x53, 0x74,
+	0x61, 0x72, 0x74, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x30, 0x01, 0x12, 0x60, 0x0a, 0x0b, 0x43, 0x6c, 0x65, 0x61,
+	0x6e, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12, 0x23, 0x2e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65,
+	0x5f, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e,
+	0x43, 0x6c, 0x65, 0x61, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2a, 0x2e, 0x62,
+	0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x70,
+	0x61, 0x74, 0x68, 0x73, 0x2e, 0x43, 0x6c, 0x65, 0x61, 0x6e, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x30, 0x01, 0x12, 0x69, 0x0a, 0x12, 0x43, 0x6c,
+	0x65, 0x61, 0x6e, 0x41, 0x6e, 0x64, 0x53, 0x74, 0x61, 0x72, 0x74, 0x42, 0x75, 0x69, 0x6c, 0x64,
+	0x12, 0x28, 0x2e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x5f, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74,
+	0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x53, 0x74, 0x61, 0x72, 0x74, 0x42, 0x75,
+	0x69, 0x6c, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x29, 0x2e, 0x72, 0x65, 0x6d,
+	0x6f, 0x74, 0x65, 0x5f, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x2e, 0x53, 0x74, 0x61, 0x72, 0x74, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x70, 0x0a, 0x0f, 0x43, 0x6c, 0x6f, 0x6e, 0x65, 0x4f, 0x75,
+	0x74, 0x70, 0x75, 0x74, 0x50, 0x61, 0x74, 0x68, 0x12, 0x2d, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64,
+	0x62, 0x61, 0x72, 0x6e, 0x2e, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x70, 0x61, 0x74, 0x68, 0x73,
+	0x2e, 0x43, 0x6c, 0x6f, 0x6e, 0x65, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x50, 0x61, 0x74, 0x68,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2e, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62,
+	0x61, 0x72, 0x6e, 0x2e, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x70, 0x61, 0x74, 0x68, 0x73, 0x2e,
+	0x43, 0x6c, 0x6f, 0x6e, 0x65, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x50, 0x61, 0x74, 0x68, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x61, 0x0a, 0x0a, 0x53, 0x74, 0x61, 0x74, 0x44,
+	0x69, 0x67, 0x65, 0x73, 0x74, 0x12, 0x28, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72,
+	0x6e, 0x2e, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x70, 0x61, 0x74, 0x68, 0x73, 0x2e, 0x53, 0x74,
+	0x61, 0x74, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x29, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x6f, 0x75, 0x74, 0x70,
+	0x75, 0x74, 0x70, 0x61, 0x74, 0x68, 0x73, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x44, 0x69, 0x67, 0x65,
+	0x73, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x5a, 0x0a, 0x10, 0x52, 0x65,
+	0x6e, 0x61, 0x6d, 0x65, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x42, 0x61, 0x73, 0x65, 0x12, 0x2e,
+	0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x6f, 0x75, 0x74, 0x70, 0x75,
+	0x74, 0x70, 0x61, 0x74, 0x68, 0x73, 0x2e, 0x52, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x4f, 0x75, 0x74,
+	0x70, 0x75, 0x74, 0x42, 0x61, 0x73, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x97, 0x01, 0x0a, 0x1c, 0x47, 0x65, 0x74, 0x4f, 0x75,
+	0x74, 0x70, 0x75, 0x74, 0x50, 0x61, 0x74, 0x68, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x46, 0x75,
+	0x6e, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x3a, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62,
+	0x61, 0x72, 0x6e, 0x2e, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x70, 0x61, 0x74, 0x68, 0x73, 0x2e,
+	0x47, 0x65, 0x74, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x50, 0x61, 0x74, 0x68, 0x44, 0x69, 0x67,
+	0x65, 0x73, 0x74, 0x46, 0x75, 0x6e, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x3b, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e,
+	0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x70, 0x61, 0x74, 0x68, 0x73, 0x2e, 0x47, 0x65, 0x74, 0x4f,
+	0x75, 0x74, 0x70, 0x75, 0x74, 0x50, 0x61, 0x74, 0x68, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x46,
+	0x75, 0x6e, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x4c, 0x0a, 0x09, 0x53, 0x65, 0x61, 0x6c, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x12, 0x27, 0x2e,
+	0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74,
+	0x70, 0x61, 0x74, 0x68, 0x73, 0x2e, 0x53, 0x65, 0x61, 0x6c, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x6c,
+	0x0a, 0x0d, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x54, 0x61, 0x72, 0x62, 0x61, 0x6c, 0x6c, 0x12,
+	0x2b, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x6f, 0x75, 0x74, 0x70,
+	0x75, 0x74, 0x70, 0x61, 0x74, 0x68, 0x73, 0x2e, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x54, 0x61,
+	0x72, 0x62, 0x61, 0x6c, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2c, 0x2e, 0x62,
+	0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x70,
+	0x61, 0x74, 0x68, 0x73, 0x2e, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x54, 0x61, 0x72, 0x62, 0x61,
+	0x6c, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x30, 0x01, 0x12, 0x6a, 0x0a, 0x0d,
+	0x47, 0x65, 0x74, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x50, 0x61, 0x74, 0x68, 0x12, 0x2b, 0x2e,
+	0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74,
+	0x70, 0x61, 0x74, 0x68, 0x73, 0x2e, 0x47, 0x65, 0x74, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x50,
+	0x61, 0x74, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2c, 0x2e, 0x62, 0x75, 0x69,
+	0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x70, 0x61, 0x74,
+	0x68, 0x73, 0x2e, 0x47, 0x65, 0x74, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x50, 0x61, 0x74, 0x68,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x61, 0x0a, 0x0a, 0x56, 0x65, 0x72, 0x69,
+	0x66, 0x79, 0x54, 0x72, 0x65, 0x65, 0x12, 0x28, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61,
+	0x72, 0x6e, 0x2e, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x70, 0x61, 0x74, 0x68, 0x73, 0x2e, 0x56,
+	0x65, 0x72, 0x69, 0x66, 0x79, 0x54, 0x72, 0x65, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x29, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x6f, 0x75, 0x74,
+	0x70, 0x75, 0x74, 0x70, 0x61, 0x74, 0x68, 0x73, 0x2e, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x54,
+	0x72, 0x65, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x5a, 0x0a, 0x10, 0x53,
+	0x65, 0x74, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x12,
+	0x2e, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x6f, 0x75, 0x74, 0x70,
+	0x75, 0x74, 0x70, 0x61, 0x74, 0x68, 0x73, 0x2e, 0x53, 0x65, 0x74, 0x42, 0x75, 0x69, 0x6c, 0x64,
+	0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x61, 0x0a, 0x0a, 0x44, 0x69, 0x66, 0x66, 0x42,
+	0x75, 0x69, 0x6c, 0x64, 0x73, 0x12, 0x28, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72,
+	0x6e, 0x2e, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x70, 0x61, 0x74, 0x68, 0x73, 0x2e, 0x44, 0x69,
+	0x66, 0x66, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x29, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x6f, 0x75, 0x74, 0x70,
+	0x75, 0x74, 0x70, 0x61, 0x74, 0x68, 0x73, 0x2e, 0x44, 0x69, 0x66, 0x66, 0x42, 0x75, 0x69, 0x6c,
+	0x64, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x76, 0x0a, 0x11, 0x52, 0x65,
+	0x73, 0x6f, 0x6c, 0x76, 0x65, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x50, 0x61, 0x74, 0x68, 0x12,
+	0x2f, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x6f, 0x75, 0x74, 0x70,
+	0x75, 0x74, 0x70, 0x61, 0x74, 0x68, 0x73, 0x2e, 0x52, 0x65, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x4f,
+	0x75, 0x74, 0x70, 0x75, 0x74, 0x50, 0x61, 0x74, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x30, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x6f, 0x75, 0x74,
+	0x70, 0x75, 0x74, 0x70, 0x61, 0x74, 0x68, 0x73, 0x2e, 0x52, 0x65, 0x73, 0x6f, 0x6c, 0x76, 0x65,
+	0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x50, 0x61, 0x74, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x70, 0x0a, 0x0f, 0x4c, 0x69, 0x73, 0x74, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74,
+	0x50, 0x61, 0x74, 0x68, 0x73, 0x12, 0x2d, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72,
+	0x6e, 0x2e, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x70, 0x61, 0x74, 0x68, 0x73, 0x2e, 0x4c, 0x69,
+	0x73, 0x74, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x50, 0x61, 0x74, 0x68, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x2e, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e,
+	0x2e, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x70, 0x61, 0x74, 0x68, 0x73, 0x2e, 0x4c, 0x69, 0x73,
+	0x74, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x50, 0x61, 0x74, 0x68, 0x73, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x66, 0x0a, 0x0b, 0x57, 0x61, 0x74, 0x63, 0x68, 0x42, 0x75, 0x69,
+	0x6c, 0x64, 0x73, 0x12, 0x29, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e,
+	0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x70, 0x61, 0x74, 0x68, 0x73, 0x2e, 0x57, 0x61, 0x74, 0x63,
+	0x68, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2a,
+	0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x6f, 0x75, 0x74, 0x70, 0x75,
+	0x74, 0x70, 0x61, 0x74, 0x68, 0x73, 0x2e, 0x57, 0x61, 0x74, 0x63, 0x68, 0x42, 0x75, 0x69, 0x6c,
+	0x64, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x30, 0x01, 0x42, 0x37, 0x5a, 0x35,
+	0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x62, 0x75, 0x69, 0x6c, 0x64,
+	0x62, 0x61, 0x72, 0x6e, 0x2f, 0x62, 0x62, 0x2d, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x64, 0x2f,
+	0x70, 0x6b, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74,
+	0x70, 0x61, 0x74, 0x68, 0x73, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_pkg_proto_outputpaths_outputpaths_proto_rawDescOnce sync.Once
+	file_pkg_proto_outputpaths_outputpaths_proto_rawDescData = file_pkg_proto_outputpaths_outputpaths_proto_rawDesc
+)
+
+func file_pkg_proto_outputpaths_outputpaths_proto_rawDescGZIP() []byte {
+	file_pkg_proto_outputpaths_outputpaths_proto_rawDescOnce.Do(func() {
+		file_pkg_proto_outputpaths_outputpaths_proto_rawDescData = protoimpl.X.CompressGZIP(file_pkg_proto_outputpaths_outputpaths_proto_rawDescData)
+	})
+	return file_pkg_proto_outputpaths_outputpaths_proto_rawDescData
+}
+
+var file_pkg_proto_outputpaths_outputpaths_proto_enumTypes = make([]protoimpl.EnumInfo, 5)
+var file_pkg_proto_outputpaths_outputpaths_proto_msgTypes = make([]protoimpl.MessageInfo, 84)
+var file_pkg_proto_outputpaths_outputpaths_proto_goTypes = []interface{}{
+	(FetchedObjectType)(0),                       // 0: buildbarn.outputpaths.FetchedObjectType
+	(ExternalPathPolicy)(0),                      // 1: buildbarn.outputpaths.ExternalPathPolicy
+	(BuildEvent_Type)(0),                         // 2: buildbarn.outputpaths.BuildEvent.Type
+	(BuildEvent_EntryRemoval_Reason)(0),          // 3: buildbarn.outputpaths.BuildEvent.EntryRemoval.Reason
+	(StatDigestResponse_FileType)(0),             // 4: buildbarn.outputpaths.StatDigestResponse.FileType
+	(*WatchBuildsRequest)(nil),                   // 5: buildbarn.outputpaths.WatchBuildsRequest
+	(*WatchBuildsResponse)(nil),                  // 6: buildbarn.outputpaths.WatchBuildsResponse
+	(*BuildEvent)(nil),                           // 7: buildbarn.outputpaths.BuildEvent
+	(*ListOutputPathsRequest)(nil),               // 8: buildbarn.outputpaths.ListOutputPathsRequest
+	(*ListOutputPathsResponse)(nil),              // 9: buildbarn.outputpaths.ListOutputPathsResponse
+	(*StatStreamResponse)(nil),                   // 10: buildbarn.outputpaths.StatStreamResponse
+	(*GetActiveBuildRequest)(nil),                // 11: buildbarn.outputpaths.GetActiveBuildRequest
+	(*GetActiveBuildResponse)(nil),               // 12: buildbarn.outputpaths.GetActiveBuildResponse
+	(*ActiveBuild)(nil),                          // 13: buildbarn.outputpaths.ActiveBuild
+	(*AbortBuildRequest)(nil),                    // 14: buildbarn.outputpaths.AbortBuildRequest
+	(*GetOutputPathErrorsRequest)(nil),           // 15: buildbarn.outputpaths.GetOutputPathErrorsRequest
+	(*GetOutputPathErrorsResponse)(nil),          // 16: buildbarn.outputpaths.GetOutputPathErrorsResponse
+	(*OutputPathError)(nil),                      // 17: buildbarn.outputpaths.OutputPathError
+	(*FetchFailure)(nil),                         // 18: buildbarn.outputpaths.FetchFailure
+	(*ExportTreeRequest)(nil),                    // 19: buildbarn.outputpaths.ExportTreeRequest
+	(*ExportTreeResponse)(nil),                   // 20: buildbarn.outputpaths.ExportTreeResponse
+	(*CreateRootSymlinkRequest)(nil),             // 21: buildbarn.outputpaths.CreateRootSymlinkRequest
+	(*RemoveRootSymlinkRequest)(nil),             // 22: buildbarn.outputpaths.RemoveRootSymlinkRequest
+	(*BestEffortBatchCreateResponse)(nil),        // 23: buildbarn.outputpaths.BestEffortBatchCreateResponse
+	(*CreateStreamRequest)(nil),                  // 24: buildbarn.outputpaths.CreateStreamRequest
+	(*CreateStreamHeader)(nil),                   // 25: buildbarn.outputpaths.CreateStreamHeader
+	(*CreateStreamResponse)(nil),                 // 26: buildbarn.outputpaths.CreateStreamResponse
+	(*ExtendedBatchCreateRequest)(nil),           // 27: buildbarn.outputpaths.ExtendedBatchCreateRequest
+	(*ExtendedBatchCreateResponse)(nil),          // 28: buildbarn.outputpaths.ExtendedBatchCreateResponse
+	(*GetOutputPathStatsRequest)(nil),            // 29: buildbarn.outputpaths.GetOutputPathStatsRequest
+	(*GetOutputPathStatsResponse)(nil),           // 30: buildbarn.outputpaths.GetOutputPathStatsResponse
+	(*FetchLatencyDistribution)(nil),             // 31: buildbarn.outputpaths.FetchLatencyDistribution
+	(*ExtendedBatchStatRequest)(nil),             // 32: buildbarn.outputpaths.ExtendedBatchStatRequest
+	(*ExtendedBatchStatResponse)(nil),            // 33: buildbarn.outputpaths.ExtendedBatchStatResponse
+	(*ExtendedStatResponse)(nil),                 // 34: buildbarn.outputpaths.ExtendedStatResponse
+	(*BatchStartBuildRequest)(nil),               // 35: buildbarn.outputpaths.BatchStartBuildRequest
+	(*BatchStartBuildResponse)(nil),              // 36: buildbarn.outputpaths.BatchStartBuildResponse
+	(*ReadDirectoryRequest)(nil),                 // 37: buildbarn.outputpaths.ReadDirectoryRequest
+	(*ReadDirectoryResponse)(nil),                // 38: buildbarn.outputpaths.ReadDirectoryResponse
+	(*DirectoryEntry)(nil),                       // 39: buildbarn.outputpaths.DirectoryEntry
+	(*GetDaemonStatsResponse)(nil),               // 40: buildbarn.outputpaths.GetDaemonStatsResponse
+	(*SetExternalPathPolicyRequest)(nil),         // 41: buildbarn.outputpaths.SetExternalPathPolicyRequest
+	(*GetDirectoryResidencyRequest)(nil),         // 42: buildbarn.outputpaths.GetDirectoryResidencyRequest
+	(*GetDirectoryResidencyResponse)(nil),        // 43: buildbarn.outputpaths.GetDirectoryResidencyResponse
+	(*FinalizeSubtreeRequest)(nil),               // 44: buildbarn.outputpaths.FinalizeSubtreeRequest
+	(*FinalizeSubtreeResponse)(nil),              // 45: buildbarn.outputpaths.FinalizeSubtreeResponse
+	(*SetOutputPathRequest)(nil),                 // 46: buildbarn.outputpaths.SetOutputPathRequest
+	(*ListFinalizedBuildsRequest)(nil),           // 47: buildbarn.outputpaths.ListFinalizedBuildsRequest
+	(*FinalizedBuild)(nil),                       // 48: buildbarn.outputpaths.FinalizedBuild
+	(*ListFinalizedBuildsResponse)(nil),          // 49: buildbarn.outputpaths.ListFinalizedBuildsResponse
+	(*SetDefaultPathPrefixRequest)(nil),          // 50: buildbarn.outputpaths.SetDefaultPathPrefixRequest
+	(*MaterializeRequest)(nil),                   // 51: buildbarn.outputpaths.MaterializeRequest
+	(*MaterializeResponse)(nil),                  // 52: buildbarn.outputpaths.MaterializeResponse
+	(*ReopenBuildRequest)(nil),                   // 53: buildbarn.outputpaths.ReopenBuildRequest
+	(*InvalidateCacheRequest)(nil),               // 54: buildbarn.outputpaths.InvalidateCacheRequest
+	(*StartBuildStreamResponse)(nil),             // 55: buildbarn.outputpaths.StartBuildStreamResponse
+	(*StartBuildProgress)(nil),                   // 56: buildbarn.outputpaths.StartBuildProgress
+	(*CleanStreamResponse)(nil),                  // 57: buildbarn.outputpaths.CleanStreamResponse
+	(*CloneOutputPathRequest)(nil),               // 58: buildbarn.outputpaths.CloneOutputPathRequest
+	(*CloneOutputPathResponse)(nil),              // 59: buildbarn.outputpaths.CloneOutputPathResponse
+	(*StatDigestRequest)(nil),                    // 60: buildbarn.outputpaths.StatDigestRequest
+	(*StatDigestResponse)(nil),                   // 61: buildbarn.outputpaths.StatDigestResponse
+	(*RenameOutputBaseRequest)(nil),              // 62: buildbarn.outputpaths.RenameOutputBaseRequest
+	(*GetOutputPathDigestFunctionsRequest)(nil),  // 63: buildbarn.outputpaths.GetOutputPathDigestFunctionsRequest
+	(*GetOutputPathDigestFunctionsResponse)(nil), // 64: buildbarn.outputpaths.GetOutputPathDigestFunctionsResponse
+	(*DigestFunctionUsage)(nil),                  // 65: buildbarn.outputpaths.DigestFunctionUsage
+	(*ExportTarballRequest)(nil),                 // 66: buildbarn.outputpaths.ExportTarballRequest
+	(*ExportTarballResponse)(nil),                // 67: buildbarn.outputpaths.ExportTarballResponse
+	(*ExportTarballProgress)(nil),                // 68: buildbarn.outputpaths.ExportTarballProgress
+	(*SealBuildRequest)(nil),                     // 69: buildbarn.outputpaths.SealBuildRequest
+	(*RepairOutputPathRequest)(nil),              // 70: buildbarn.outputpaths.RepairOutputPathRequest
+	(*RepairOutputPathResponse)(nil),             // 71: buildbarn.outputpaths.RepairOutputPathResponse
+	(*GetOutputPathRequest)(nil),                 // 72: buildbarn.outputpaths.GetOutputPathRequest
+	(*GetOutputPathResponse)(nil),                // 73: buildbarn.outputpaths.GetOutputPathResponse
+	(*VerifyTreeRequest)(nil),                    // 74: buildbarn.outputpaths.VerifyTreeRequest
+	(*VerifyTreeResponse)(nil),                   // 75: buildbarn.outputpaths.VerifyTreeResponse
+	(*SetBuildMetadataRequest)(nil),              // 76: buildbarn.outputpaths.SetBuildMetadataRequest
+	(*DiffBuildsRequest)(nil),                    // 77: buildbarn.outputpaths.DiffBuildsRequest
+	(*DiffBuildsResponse)(nil),                   // 78: buildbarn.outputpaths.DiffBuildsResponse
+	(*PathDifference)(nil),                       // 79: buildbarn.outputpaths.PathDifference
+	(*DiffedNode)(nil),                           // 80: buildbarn.outputpaths.DiffedNode
+	(*ResolveOutputPathRequest)(nil),             // 81: buildbarn.outputpaths.ResolveOutputPathRequest
+	(*ResolveOutputPathResponse)(nil),            // 82: buildbarn.outputpaths.ResolveOutputPathResponse
+	(*BuildEvent_EntryRemoval)(nil),              // 83: buildbarn.outputpaths.BuildEvent.EntryRemoval
+	(*ListOutputPathsResponse_OutputPath)(nil),   // 84: buildbarn.outputpaths.ListOutputPathsResponse.OutputPath
+	nil,                                      // 85: buildbarn.outputpaths.ActiveBuild.ClientMetadataEntry
+	nil,                                      // 86: buildbarn.outputpaths.FinalizedBuild.ClientMetadataEntry
+	nil,                                      // 87: buildbarn.outputpaths.GetOutputPathResponse.OutputPathAliasesEntry
+	nil,                                      // 88: buildbarn.outputpaths.SetBuildMetadataRequest.MetadataEntry
+	(*emptypb.Empty)(nil),                    // 89: google.protobuf.Empty
+	(*timestamppb.Timestamp)(nil),            // 90: google.protobuf.Timestamp
+	(*remoteoutputservice.StatResponse)(nil), // 91: remote_output_service.StatResponse
+	(v2.DigestFunction_Value)(0),             // 92: build.bazel.remote.execution.v2.DigestFunction.Value
+	(*status.Status)(nil),                    // 93: google.rpc.Status
+	(*v2.Digest)(nil),                        // 94: build.bazel.remote.execution.v2.Digest
+	(*v2.OutputFile)(nil),                    // 95: build.bazel.remote.execution.v2.OutputFile
+	(*v2.OutputDirectory)(nil),               // 96: build.bazel.remote.execution.v2.OutputDirectory
+	(*v2.OutputSymlink)(nil),                 // 97: build.bazel.remote.execution.v2.OutputSymlink
+	(*remoteoutputservice.BatchCreateRequest)(nil), // 98: remote_output_service.BatchCreateRequest
+	(*durationpb.Duration)(nil),                    // 99: google.protobuf.Duration
+	(*remoteoutputservice.BatchStatRequest)(nil),   // 100: remote_output_service.BatchStatRequest
+	(*remoteoutputservice.StartBuildRequest)(nil),  // 101: remote_output_service.StartBuildRequest
+	(*remoteoutputservice.StartBuildResponse)(nil), // 102: remote_output_service.StartBuildResponse
+	(*remoteoutputservice.FileStatus)(nil),         // 103: remote_output_service.FileStatus
+	(*v2.FileNode)(nil),                            // 104: build.bazel.remote.execution.v2.FileNode
+	(*v2.DirectoryNode)(nil),                       // 105: build.bazel.remote.execution.v2.DirectoryNode
+	(*v2.SymlinkNode)(nil),                         // 106: build.bazel.remote.execution.v2.SymlinkNode
+	(*remoteoutputservice.CleanRequest)(nil),       // 107: remote_output_service.CleanRequest
+}
+var file_pkg_proto_outputpaths_outputpaths_proto_depIdxs = []int32{
+	89,  // 0: buildbarn.outputpaths.WatchBuildsRequest.now:type_name -> google.protobuf.Empty
+	7,   // 1: buildbarn.outputpaths.WatchBuildsResponse.events:type_name -> buildbarn.outputpaths.BuildEvent
+	2,   // 2: buildbarn.outputpaths.BuildEvent.type:type_name -> buildbarn.outputpaths.BuildEvent.Type
+	90,  // 3: buildbarn.outputpaths.BuildEvent.timestamp:type_name -> google.protobuf.Timestamp
+	83,  // 4: buildbarn.outputpaths.BuildEvent.entry_removal:type_name -> buildbarn.outputpaths.BuildEvent.EntryRemoval
+	90,  // 5: buildbarn.outputpaths.ListOutputPathsRequest.since:type_name -> google.protobuf.Timestamp
+	84,  // 6: buildbarn.outputpaths.ListOutputPathsResponse.output_paths:type_name -> buildbarn.outputpaths.ListOutputPathsResponse.OutputPath
+	91,  // 7: buildbarn.outputpaths.StatStreamResponse.response:type_name -> remote_output_service.StatResponse
+	13,  // 8: buildbarn.outputpaths.GetActiveBuildResponse.active_build:type_name -> buildbarn.outputpaths.ActiveBuild
+	90,  // 9: buildbarn.outputpaths.ActiveBuild.start_time:type_name -> google.protobuf.Timestamp
+	92,  // 10: buildbarn.outputpaths.ActiveBuild.digest_function:type_name -> build.bazel.remote.execution.v2.DigestFunction.Value
+	85,  // 11: buildbarn.outputpaths.ActiveBuild.client_metadata:type_name -> buildbarn.outputpaths.ActiveBuild.ClientMetadataEntry
+	56,  // 12: buildbarn.outputpaths.ActiveBuild.start_build_progress:type_name -> buildbarn.outputpaths.StartBuildProgress
+	17,  // 13: buildbarn.outputpaths.GetOutputPathErrorsResponse.errors:type_name -> buildbarn.outputpaths.OutputPathError
+	90,  // 14: buildbarn.outputpaths.OutputPathError.time:type_name -> google.protobuf.Timestamp
+	93,  // 15: buildbarn.outputpaths.OutputPathError.status:type_name -> google.rpc.Status
+	0,   // 16: buildbarn.outputpaths.OutputPathError.fetched_object_type:type_name -> buildbarn.outputpaths.FetchedObjectType
+	0,   // 17: buildbarn.outputpaths.FetchFailure.object_type:type_name -> buildbarn.outputpaths.FetchedObjectType
+	94,  // 18: buildbarn.outputpaths.ExportTreeResponse.tree_digest:type_name -> build.bazel.remote.execution.v2.Digest
+	93,  // 19: buildbarn.outputpaths.BestEffortBatchCreateResponse.files:type_name -> google.rpc.Status
+	93,  // 20: buildbarn.outputpaths.BestEffortBatchCreateResponse.directories:type_name -> google.rpc.Status
+	93,  // 21: buildbarn.outputpaths.BestEffortBatchCreateResponse.symlinks:type_name -> google.rpc.Status
+	25,  // 22: buildbarn.outputpaths.CreateStreamRequest.header:type_name -> buildbarn.outputpaths.CreateStreamHeader
+	95,  // 23: buildbarn.outputpaths.CreateStreamRequest.file:type_name -> build.bazel.remote.execution.v2.OutputFile
+	96,  // 24: buildbarn.outputpaths.CreateStreamRequest.directory:type_name -> build.bazel.remote.execution.v2.OutputDirectory
+	97,  // 25: buildbarn.outputpaths.CreateStreamRequest.symlink:type_name -> build.bazel.remote.execution.v2.OutputSymlink
+	98,  // 26: buildbarn.outputpaths.ExtendedBatchCreateRequest.request:type_name -> remote_output_service.BatchCreateRequest
+	93,  // 27: buildbarn.outputpaths.ExtendedBatchCreateResponse.directories:type_name -> google.rpc.Status
+	31,  // 28: buildbarn.outputpaths.GetOutputPathStatsResponse.blob_fetch_latency:type_name -> buildbarn.outputpaths.FetchLatencyDistribution
+	31,  // 29: buildbarn.outputpaths.GetOutputPathStatsResponse.tree_fetch_latency:type_name -> buildbarn.outputpaths.FetchLatencyDistribution
+	90,  // 30: buildbarn.outputpaths.GetOutputPathStatsResponse.last_validation_time:type_name -> google.protobuf.Timestamp
+	99,  // 31: buildbarn.outputpaths.FetchLatencyDistribution.p50:type_name -> google.protobuf.Duration
+	99,  // 32: buildbarn.outputpaths.FetchLatencyDistribution.p95:type_name -> google.protobuf.Duration
+	99,  // 33: buildbarn.outputpaths.FetchLatencyDistribution.p99:type_name -> google.protobuf.Duration
+	100, // 34: buildbarn.outputpaths.ExtendedBatchStatRequest.request:type_name -> remote_output_service.BatchStatRequest
+	34,  // 35: buildbarn.outputpaths.ExtendedBatchStatResponse.responses:type_name -> buildbarn.outputpaths.ExtendedStatResponse
+	91,  // 36: buildbarn.outputpaths.ExtendedStatResponse.response:type_name -> remote_output_service.StatResponse
+	94,  // 37: buildbarn.outputpaths.ExtendedStatResponse.directory_digest:type_name -> build.bazel.remote.execution.v2.Digest
+	93,  // 38: buildbarn.outputpaths.ExtendedStatResponse.error:type_name -> google.rpc.Status
+	101, // 39: buildbarn.outputpaths.BatchStartBuildRequest.requests:type_name -> remote_output_service.StartBuildRequest
+	102, // 40: buildbarn.outputpaths.BatchStartBuildResponse.responses:type_name -> remote_output_service.StartBuildResponse
+	39,  // 41: buildbarn.outputpaths.ReadDirectoryResponse.entries:type_name -> buildbarn.outputpaths.DirectoryEntry
+	103, // 42: buildbarn.outputpaths.DirectoryEntry.status:type_name -> remote_output_service.FileStatus
+	31,  // 43: buildbarn.outputpaths.GetDaemonStatsResponse.blob_fetch_latency:type_name -> buildbarn.outputpaths.FetchLatencyDistribution
+	31,  // 44: buildbarn.outputpaths.GetDaemonStatsResponse.tree_fetch_latency:type_name -> buildbarn.outputpaths.FetchLatencyDistribution
+	1,   // 45: buildbarn.outputpaths.SetExternalPathPolicyRequest.policy:type_name -> buildbarn.outputpaths.ExternalPathPolicy
+	94,  // 46: buildbarn.outputpaths.FinalizeSubtreeResponse.tree_digest:type_name -> build.bazel.remote.execution.v2.Digest
+	94,  // 47: buildbarn.outputpaths.SetOutputPathRequest.root_tree_digest:type_name -> build.bazel.remote.execution.v2.Digest
+	94,  // 48: buildbarn.outputpaths.FinalizedBuild.root_tree_digest:type_name -> build.bazel.remote.execution.v2.Digest
+	90,  // 49: buildbarn.outputpaths.FinalizedBuild.finalize_time:type_name -> google.protobuf.Timestamp
+	86,  // 50: buildbarn.outputpaths.FinalizedBuild.client_metadata:type_name -> buildbarn.outputpaths.FinalizedBuild.ClientMetadataEntry
+	48,  // 51: buildbarn.outputpaths.ListFinalizedBuildsResponse.builds:type_name -> buildbarn.outputpaths.FinalizedBuild
+	99,  // 52: buildbarn.outputpaths.MaterializeRequest.maximum_duration:type_name -> google.protobuf.Duration
+	56,  // 53: buildbarn.outputpaths.StartBuildStreamResponse.progress:type_name -> buildbarn.outputpaths.StartBuildProgress
+	102, // 54: buildbarn.outputpaths.StartBuildStreamResponse.response:type_name -> remote_output_service.StartBuildResponse
+	94,  // 55: buildbarn.outputpaths.CloneOutputPathResponse.root_tree_digest:type_name -> build.bazel.remote.execution.v2.Digest
+	4,   // 56: buildbarn.outputpaths.StatDigestResponse.file_type:type_name -> buildbarn.outputpaths.StatDigestResponse.FileType
+	94,  // 57: buildbarn.outputpaths.StatDigestResponse.digest:type_name -> build.bazel.remote.execution.v2.Digest
+	65,  // 58: buildbarn.outputpaths.GetOutputPathDigestFunctionsResponse.digest_functions:type_name -> buildbarn.outputpaths.DigestFunctionUsage
+	92,  // 59: buildbarn.outputpaths.DigestFunctionUsage.digest_function:type_name -> build.bazel.remote.execution.v2.DigestFunction.Value
+	68,  // 60: buildbarn.outputpaths.ExportTarballResponse.progress:type_name -> buildbarn.outputpaths.ExportTarballProgress
+	87,  // 61: buildbarn.outputpaths.GetOutputPathResponse.output_path_aliases:type_name -> buildbarn.outputpaths.GetOutputPathResponse.OutputPathAliasesEntry
+	92,  // 62: buildbarn.outputpaths.VerifyTreeRequest.digest_function:type_name -> build.bazel.remote.execution.v2.DigestFunction.Value
+	94,  // 63: buildbarn.outputpaths.VerifyTreeRequest.tree_digest:type_name -> build.bazel.remote.execution.v2.Digest
+	94,  // 64: buildbarn.outputpaths.VerifyTreeResponse.missing_directory_digests:type_name -> build.bazel.remote.execution.v2.Digest
+	94,  // 65: buildbarn.outputpaths.VerifyTreeResponse.missing_file_digests:type_name -> build.bazel.remote.execution.v2.Digest
+	88,  // 66: buildbarn.outputpaths.SetBuildMetadataRequest.metadata:type_name -> buildbarn.outputpaths.SetBuildMetadataRequest.MetadataEntry
+	79,  // 67: buildbarn.outputpaths.DiffBuildsResponse.differences:type_name -> buildbarn.outputpaths.PathDifference
+	80,  // 68: buildbarn.outputpaths.PathDifference.old_node:type_name -> buildbarn.outputpaths.DiffedNode
+	80,  // 69: buildbarn.outputpaths.PathDifference.new_node:type_name -> buildbarn.outputpaths.DiffedNode
+	104, // 70: buildbarn.outputpaths.DiffedNode.file:type_name -> build.bazel.remote.execution.v2.FileNode
+	105, // 71: buildbarn.outputpaths.DiffedNode.directory:type_name -> build.bazel.remote.execution.v2.DirectoryNode
+	106, // 72: buildbarn.outputpaths.DiffedNode.symlink:type_name -> build.bazel.remote.execution.v2.SymlinkNode
+	3,   // 73: buildbarn.outputpaths.BuildEvent.EntryRemoval.reason:type_name -> buildbarn.outputpaths.BuildEvent.EntryRemoval.Reason
+	94,  // 74: buildbarn.outputpaths.BuildEvent.EntryRemoval.digest:type_name -> build.bazel.remote.execution.v2.Digest
+	92,  // 75: buildbarn.outputpaths.BuildEvent.EntryRemoval.digest_function:type_name -> build.bazel.remote.execution.v2.DigestFunction.Value
+	90,  // 76: buildbarn.outputpaths.ListOutputPathsResponse.OutputPath.creation_time:type_name -> google.protobuf.Timestamp
+	90,  // 77: buildbarn.outputpaths.ListOutputPathsResponse.OutputPath.last_build_start_time:type_name -> google.protobuf.Timestamp
+	100, // 78: buildbarn.outputpaths.OutputPaths.StatStream:input_type -> remote_output_service.BatchStatRequest
+	11,  // 79: buildbarn.outputpaths.OutputPaths.GetActiveBuild:input_type -> buildbarn.outputpaths.GetActiveBuildRequest
+	14,  // 80: buildbarn.outputpaths.OutputPaths.AbortBuild:input_type -> buildbarn.outputpaths.AbortBuildRequest
+	15,  // 81: buildbarn.outputpaths.OutputPaths.GetOutputPathErrors:input_type -> buildbarn.outputpaths.GetOutputPathErrorsRequest
+	19,  // 82: buildbarn.outputpaths.OutputPaths.ExportTree:input_type -> buildbarn.outputpaths.ExportTreeRequest
+	21,  // 83: buildbarn.outputpaths.OutputPaths.CreateRootSymlink:input_type -> buildbarn.outputpaths.CreateRootSymlinkRequest
+	22,  // 84: buildbarn.outputpaths.OutputPaths.RemoveRootSymlink:input_type -> buildbarn.outputpaths.RemoveRootSymlinkRequest
+	98,  // 85: buildbarn.outputpaths.OutputPaths.BestEffortBatchCreate:input_type -> remote_output_service.BatchCreateRequest
+	24,  // 86: buildbarn.outputpaths.OutputPaths.CreateStream:input_type -> buildbarn.outputpaths.CreateStreamRequest
+	27,  // 87: buildbarn.outputpaths.OutputPaths.ExtendedBatchCreate:input_type -> buildbarn.outputpaths.ExtendedBatchCreateRequest
+	29,  // 88: buildbarn.outputpaths.OutputPaths.GetOutputPathStats:input_type -> buildbarn.outputpaths.GetOutputPathStatsRequest
+	32,  // 89: buildbarn.outputpaths.OutputPaths.ExtendedBatchStat:input_type -> buildbarn.outputpaths.ExtendedBatchStatRequest
+	35,  // 90: buildbarn.outputpaths.OutputPaths.BatchStartBuild:input_type -> buildbarn.outputpaths.BatchStartBuildRequest
+	37,  // 91: buildbarn.outputpaths.OutputPaths.ReadDirectory:input_type -> buildbarn.outputpaths.ReadDirectoryRequest
+	89,  // 92: buildbarn.outputpaths.OutputPaths.GetDaemonStats:input_type -> google.protobuf.Empty
+	41,  // 93: buildbarn.outputpaths.OutputPaths.SetExternalPathPolicy:input_type -> buildbarn.outputpaths.SetExternalPathPolicyRequest
+	42,  // 94: buildbarn.outputpaths.OutputPaths.GetDirectoryResidency:input_type -> buildbarn.outputpaths.GetDirectoryResidencyRequest
+	46,  // 95: buildbarn.outputpaths.OutputPaths.SetOutputPath:input_type -> buildbarn.outputpaths.SetOutputPathRequest
+	44,  // 96: buildbarn.outputpaths.OutputPaths.FinalizeSubtree:input_type -> buildbarn.outputpaths.FinalizeSubtreeRequest
+	47,  // 97: buildbarn.outputpaths.OutputPaths.ListFinalizedBuilds:input_type -> buildbarn.outputpaths.ListFinalizedBuildsRequest
+	50,  // 98: buildbarn.outputpaths.OutputPaths.SetDefaultPathPrefix:input_type -> buildbarn.outputpaths.SetDefaultPathPrefixRequest
+	70,  // 99: buildbarn.outputpaths.OutputPaths.RepairOutputPath:input_type -> buildbarn.outputpaths.RepairOutputPathRequest
+	51,  // 100: buildbarn.outputpaths.OutputPaths.Materialize:input_type -> buildbarn.outputpaths.MaterializeRequest
+	53,  // 101: buildbarn.outputpaths.OutputPaths.ReopenBuild:input_type -> buildbarn.outputpaths.ReopenBuildRequest
+	54,  // 102: buildbarn.outputpaths.OutputPaths.InvalidateCache:input_type -> buildbarn.outputpaths.InvalidateCacheRequest
+	101, // 103: buildbarn.outputpaths.OutputPaths.StartBuildStream:input_type -> remote_output_service.StartBuildRequest
+	107, // 104: buildbarn.outputpaths.OutputPaths.CleanStream:input_type -> remote_output_service.CleanRequest
+	101, // 105: buildbarn.outputpaths.OutputPaths.CleanAndStartBuild:input_type -> remote_output_service.StartBuildRequest
+	58,  // 106: buildbarn.outputpaths.OutputPaths.CloneOutputPath:input_type -> buildbarn.outputpaths.CloneOutputPathRequest
+	60,  // 107: buildbarn.outputpaths.OutputPaths.StatDigest:input_type -> buildbarn.outputpaths.StatDigestRequest
+	62,  // 108: buildbarn.outputpaths.OutputPaths.RenameOutputBase:input_type -> buildbarn.outputpaths.RenameOutputBaseRequest
+	63,  // 109: buildbarn.outputpaths.OutputPaths.GetOutputPathDigestFunctions:input_type -> buildbarn.outputpaths.GetOutputPathDigestFunctionsRequest
+	69,  // 110: buildbarn.outputpaths.OutputPaths.SealBuild:input_type -> buildbarn.outputpaths.SealBuildRequest
+	66,  // 111: buildbarn.outputpaths.OutputPaths.ExportTarball:input_type -> buildbarn.outputpaths.ExportTarballRequest
+	72,  // 112: buildbarn.outputpaths.OutputPaths.GetOutputPath:input_type -> buildbarn.outputpaths.GetOutputPathRequest
+	74,  // 113: buildbarn.outputpaths.OutputPaths.VerifyTree:input_type -> buildbarn.outputpaths.VerifyTreeRequest
+	76,  // 114: buildbarn.outputpaths.OutputPaths.SetBuildMetadata:input_type -> buildbarn.outputpaths.SetBuildMetadataRequest
+	77,  // 115: buildbarn.outputpaths.OutputPaths.DiffBuilds:input_type -> buildbarn.outputpaths.DiffBuildsRequest
+	81,  // 116: buildbarn.outputpaths.OutputPaths.ResolveOutputPath:input_type -> buildbarn.outputpaths.ResolveOutputPathRequest
+	8,   // 117: buildbarn.outputpaths.OutputPaths.ListOutputPaths:input_type -> buildbarn.outputpaths.ListOutputPathsRequest
+	5,   // 118: buildbarn.outputpaths.OutputPaths.WatchBuilds:input_type -> buildbarn.outputpaths.WatchBuildsRequest
+	10,  // 119: buildbarn.outputpaths.OutputPaths.StatStream:output_type -> buildbarn.outputpaths.StatStreamResponse
+	12,  // 120: buildbarn.outputpaths.OutputPaths.GetActiveBuild:output_type -> buildbarn.outputpaths.GetActiveBuildResponse
+	89,  // 121: buildbarn.outputpaths.OutputPaths.AbortBuild:output_type -> google.protobuf.Empty
+	16,  // 122: buildbarn.outputpaths.OutputPaths.GetOutputPathErrors:output_type -> buildbarn.outputpaths.GetOutputPathErrorsResponse
+	20,  // 123: buildbarn.outputpaths.OutputPaths.ExportTree:output_type -> buildbarn.outputpaths.ExportTreeResponse
+	89,  // 124: buildbarn.outputpaths.OutputPaths.CreateRootSymlink:output_type -> google.protobuf.Empty
+	89,  // 125: buildbarn.outputpaths.OutputPaths.RemoveRootSymlink:output_type -> google.protobuf.Empty
+	23,  // 126: buildbarn.outputpaths.OutputPaths.BestEffortBatchCreate:output_type -> buildbarn.outputpaths.BestEffortBatchCreateResponse
+	26,  // 127: buildbarn.outputpaths.OutputPaths.CreateStream:output_type -> buildbarn.outputpaths.CreateStreamResponse
+	28,  // 128: buildbarn.outputpaths.OutputPaths.ExtendedBatchCreate:output_type -> buildbarn.outputpaths.ExtendedBatchCreateResponse
+	30,  // 129: buildbarn.outputpaths.OutputPaths.GetOutputPathStats:output_type -> buildbarn.outputpaths.GetOutputPathStatsResponse
+	33,  // 130: buildbarn.outputpaths.OutputPaths.ExtendedBatchStat:output_type -> buildbarn.outputpaths.ExtendedBatchStatResponse
+	36,  // 131: buildbarn.outputpaths.OutputPaths.BatchStartBuild:output_type -> buildbarn.outputpaths.BatchStartBuildResponse
+	38,  // 132: buildbarn.outputpaths.OutputPaths.ReadDirectory:output_type -> buildbarn.outputpaths.ReadDirectoryResponse
+	40,  // 133: buildbarn.outputpaths.OutputPaths.GetDaemonStats:output_type -> buildbarn.outputpaths.GetDaemonStatsResponse
+	89,  // 134: buildbarn.outputpaths.OutputPaths.SetExternalPathPolicy:output_type -> google.protobuf.Empty
+	43,  // 135: buildbarn.outputpaths.OutputPaths.GetDirectoryResidency:output_type -> buildbarn.outputpaths.GetDirectoryResidencyResponse
+	89,  // 136: buildbarn.outputpaths.OutputPaths.SetOutputPath:output_type -> google.protobuf.Empty
+	45,  // 137: buildbarn.outputpaths.OutputPaths.FinalizeSubtree:output_type -> buildbarn.outputpaths.FinalizeSubtreeResponse
+	49,  // 138: buildbarn.outputpaths.OutputPaths.ListFinalizedBuilds:output_type -> buildbarn.outputpaths.ListFinalizedBuildsResponse
+	89,  // 139: buildbarn.outputpaths.OutputPaths.SetDefaultPathPrefix:output_type -> google.protobuf.Empty
+	71,  // 140: buildbarn.outputpaths.OutputPaths.RepairOutputPath:output_type -> buildbarn.outputpaths.RepairOutputPathResponse
+	52,  // 141: buildbarn.outputpaths.OutputPaths.Materialize:output_type -> buildbarn.outputpaths.MaterializeResponse
+	102, // 142: buildbarn.outputpaths.OutputPaths.ReopenBuild:output_type -> remote_output_service.StartBuildResponse
+	89,  // 143: buildbarn.outputpaths.OutputPaths.InvalidateCache:output_type -> google.protobuf.Empty
+	55,  // 144: buildbarn.outputpaths.OutputPaths.StartBuildStream:output_type -> buildbarn.outputpaths.StartBuildStreamResponse
+	57,  // 145: buildbarn.outputpaths.OutputPaths.CleanStream:output_type -> buildbarn.outputpaths.CleanStreamResponse
+	102, // 146: buildbarn.outputpaths.OutputPaths.CleanAndStartBuild:output_type -> remote_output_service.StartBuildResponse
+	59,  // 147: buildbarn.outputpaths.OutputPaths.CloneOutputPath:output_type -> buildbarn.outputpaths.CloneOutputPathResponse
+	61,  // 148: buildbarn.outputpaths.OutputPaths.StatDigest:output_type -> buildbarn.outputpaths.StatDigestResponse
+	89,  // 149: buildbarn.outputpaths.OutputPaths.RenameOutputBase:output_type -> google.protobuf.Empty
+	64,  // 150: buildbarn.outputpaths.OutputPaths.GetOutputPathDigestFunctions:output_type -> buildbarn.outputpaths.GetOutputPathDigestFunctionsResponse
+	89,  // 151: buildbarn.outputpaths.OutputPaths.SealBuild:output_type -> google.protobuf.Empty
+	67,  // 152: buildbarn.outputpaths.OutputPaths.ExportTarball:output_type -> buildbarn.outputpaths.ExportTarballResponse
+	73,  // 153: buildbarn.outputpaths.OutputPaths.GetOutputPath:output_type -> buildbarn.outputpaths.GetOutputPathResponse
+	75,  // 154: buildbarn.outputpaths.OutputPaths.VerifyTree:output_type -> buildbarn.outputpaths.VerifyTreeResponse
+	89,  // 155: buildbarn.outputpaths.OutputPaths.SetBuildMetadata:output_type -> google.protobuf.Empty
+	78,  // 156: buildbarn.outputpaths.OutputPaths.DiffBuilds:output_type -> buildbarn.outputpaths.DiffBuildsResponse
+	82,  // 157: buildbarn.outputpaths.OutputPaths.ResolveOutputPath:output_type -> buildbarn.outputpaths.ResolveOutputPathResponse
+	9,   // 158: buildbarn.outputpaths.OutputPaths.ListOutputPaths:output_type -> buildbarn.outputpaths.ListOutputPathsResponse
+	6,   // 159: buildbarn.outputpaths.OutputPaths.WatchBuilds:output_type -> buildbarn.outputpaths.WatchBuildsResponse
+	119, // [119:160] is the sub-list for method output_type
+	78,  // [78:119] is the sub-list for method input_type
+	78,  // [78:78] is the sub-list for extension type_name
+	78,  // [78:78] is the sub-list for extension extendee
+	0,   // [0:78] is the sub-list for field type_name
+}
+
+func init() { file_pkg_proto_outputpaths_outputpaths_proto_init() }
+func file_pkg_proto_outputpaths_outputpaths_proto_init() {
+	if File_pkg_proto_outputpaths_outputpaths_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WatchBuildsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WatchBuildsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BuildEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListOutputPathsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListOutputPathsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StatStreamResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetActiveBuildRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetActiveBuildResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ActiveBuild); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AbortBuildRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetOutputPathErrorsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetOutputPathErrorsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*OutputPathError); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FetchFailure); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExportTreeRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExportTreeResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CreateRootSymlinkRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RemoveRootSymlinkRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BestEffortBatchCreateResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CreateStreamRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CreateStreamHeader); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CreateStreamResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExtendedBatchCreateRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExtendedBatchCreateResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetOutputPathStatsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetOutputPathStatsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FetchLatencyDistribution); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExtendedBatchStatRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExtendedBatchStatResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExtendedStatResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BatchStartBuildRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[31].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BatchStartBuildResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[32].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReadDirectoryRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[33].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReadDirectoryResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[34].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DirectoryEntry); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[35].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetDaemonStatsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[36].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SetExternalPathPolicyRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[37].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetDirectoryResidencyRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[38].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetDirectoryResidencyResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[39].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FinalizeSubtreeRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[40].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FinalizeSubtreeResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[41].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SetOutputPathRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[42].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListFinalizedBuildsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[43].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FinalizedBuild); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[44].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListFinalizedBuildsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[45].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SetDefaultPathPrefixRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[46].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MaterializeRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[47].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MaterializeResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[48].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReopenBuildRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[49].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*InvalidateCacheRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[50].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StartBuildStreamResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[51].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StartBuildProgress); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[52].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CleanStreamResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[53].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CloneOutputPathRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[54].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CloneOutputPathResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[55].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StatDigestRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[56].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StatDigestResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[57].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RenameOutputBaseRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[58].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetOutputPathDigestFunctionsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[59].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetOutputPathDigestFunctionsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[60].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DigestFunctionUsage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[61].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExportTarballRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[62].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExportTarballResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[63].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExportTarballProgress); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[64].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SealBuildRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[65].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RepairOutputPathRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[66].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RepairOutputPathResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[67].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetOutputPathRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[68].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetOutputPathResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[69].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*VerifyTreeRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[70].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*VerifyTreeResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[71].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SetBuildMetadataRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[72].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DiffBuildsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[73].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DiffBuildsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[74].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PathDifference); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[75].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DiffedNode); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[76].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ResolveOutputPathRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[77].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ResolveOutputPathResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[78].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BuildEvent_EntryRemoval); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[79].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListOutputPathsResponse_OutputPath); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[0].OneofWrappers = []interface{}{
+		(*WatchBuildsRequest_Now)(nil),
+		(*WatchBuildsRequest_AfterCursor)(nil),
+	}
+	file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[19].OneofWrappers = []interface{}{
+		(*CreateStreamRequest_Header)(nil),
+		(*CreateStreamRequest_File)(nil),
+		(*CreateStreamRequest_Directory)(nil),
+		(*CreateStreamRequest_Symlink)(nil),
+	}
+	file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[50].OneofWrappers = []interface{}{
+		(*StartBuildStreamResponse_Progress)(nil),
+		(*StartBuildStreamResponse_Response)(nil),
+	}
+	file_pkg_proto_outputpaths_outputpaths_proto_msgTypes[75].OneofWrappers = []interface{}{
+		(*DiffedNode_File)(nil),
+		(*DiffedNode_Directory)(nil),
+		(*DiffedNode_Symlink)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_pkg_proto_outputpaths_outputpaths_proto_rawDesc,
+			NumEnums:      5,
+			NumMessages:   84,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_pkg_proto_outputpaths_outputpaths_proto_goTypes,
+		DependencyIndexes: file_pkg_proto_outputpaths_outputpaths_proto_depIdxs,
+		EnumInfos:         file_pkg_proto_outputpaths_outputpaths_proto_enumTypes,
+		MessageInfos:      file_pkg_proto_outputpaths_outputpaths_proto_msgTypes,
+	}.Build()
+	File_pkg_proto_outputpaths_outputpaths_proto = out.File
+	file_pkg_proto_outputpaths_outputpaths_proto_rawDesc = nil
+	file_pkg_proto_outputpaths_outputpaths_proto_goTypes = nil
+	file_pkg_proto_outputpaths_outputpaths_proto_depIdxs = nil
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// OutputPathsClient is the client API for OutputPaths service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type OutputPathsClient interface {
+	StatStream(ctx context.Context, in *remoteoutputservice.BatchStatRequest, opts ...grpc.CallOption) (OutputPaths_StatStreamClient, error)
+	GetActiveBuild(ctx context.Context, in *GetActiveBuildRequest, opts ...grpc.CallOption) (*GetActiveBuildResponse, error)
+	AbortBuild(ctx context.Context, in *AbortBuildRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	GetOutputPathErrors(ctx context.Context, in *GetOutputPathErrorsRequest, opts ...grpc.CallOption) (*GetOutputPathErrorsResponse, error)
+	ExportTree(ctx context.Context, in *ExportTreeRequest, opts ...grpc.CallOption) (*ExportTreeResponse, error)
+	CreateRootSymlink(ctx context.Context, in *CreateRootSymlinkRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	RemoveRootSymlink(ctx context.Context, in *RemoveRootSymlinkRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	BestEffortBatchCreate(ctx context.Context, in *remoteoutputservice.BatchCreateRequest, opts ...grpc.CallOption) (*BestEffortBatchCreateResponse, error)
+	CreateStream(ctx context.Context, opts ...grpc.CallOption) (OutputPaths_CreateStreamClient, error)
+	ExtendedBatchCreate(ctx context.Context, in *ExtendedBatchCreateRequest, opts ...grpc.CallOption) (*ExtendedBatchCreateResponse, error)
+	GetOutputPathStats(ctx context.Context, in *GetOutputPathStatsRequest, opts ...grpc.CallOption) (*GetOutputPathStatsResponse, error)
+	ExtendedBatchStat(ctx context.Context, in *ExtendedBatchStatRequest, opts ...grpc.CallOption) (*ExtendedBatchStatResponse, error)
+	BatchStartBuild(ctx context.Context, in *BatchStartBuildRequest, opts ...grpc.CallOption) (*BatchStartBuildResponse, error)
+	ReadDirectory(ctx context.Context, in *ReadDirectoryRequest, opts ...grpc.CallOption) (*ReadDirectoryResponse, error)
+	GetDaemonStats(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*GetDaemonStatsResponse, error)
+	SetExternalPathPolicy(ctx context.Context, in *SetExternalPathPolicyRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	GetDirectoryResidency(ctx context.Context, in *GetDirectoryResidencyRequest, opts ...grpc.CallOption) (*GetDirectoryResidencyResponse, error)
+	SetOutputPath(ctx context.Context, in *SetOutputPathRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	FinalizeSubtree(ctx context.Context, in *FinalizeSubtreeRequest, opts ...grpc.CallOption) (*FinalizeSubtreeResponse, error)
+	ListFinalizedBuilds(ctx context.Context, in *ListFinalizedBuildsRequest, opts ...grpc.CallOption) (*ListFinalizedBuildsResponse, error)
+	SetDefaultPathPrefix(ctx context.Context, in *SetDefaultPathPrefixRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	RepairOutputPath(ctx context.Context, in *RepairOutputPathRequest, opts ...grpc.CallOption) (*RepairOutputPathResponse, error)
+	Materialize(ctx context.Context, in *MaterializeRequest, opts ...grpc.CallOption) (OutputPaths_MaterializeClient, error)
+	ReopenBuild(ctx context.Context, in *ReopenBuildRequest, opts ...grpc.CallOption) (*remoteoutputservice.StartBuildResponse, error)
+	InvalidateCache(ctx context.Context, in *InvalidateCacheRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	StartBuildStream(ctx context.Context, in *remoteoutputservice.StartBuildRequest, opts ...grpc.CallOption) (OutputPaths_StartBuildStreamClient, error)
+	CleanStream(ctx context.Context, in *remoteoutputservice.CleanRequest, opts ...grpc.CallOption) (OutputPaths_CleanStreamClient, error)
+	CleanAndStartBuild(ctx context.Context, in *remoteoutputservice.StartBuildRequest, opts ...grpc.CallOption) (*remoteoutputservice.StartBuildResponse, error)
+	CloneOutputPath(ctx context.Context, in *CloneOutputPathRequest, opts ...grpc.CallOption) (*CloneOutputPathResponse, error)
+	StatDigest(ctx context.Context, in *StatDigestRequest, opts ...grpc.CallOption) (*StatDigestResponse, error)
+	RenameOutputBase(ctx context.Context, in *RenameOutputBaseRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	GetOutputPathDigestFunctions(ctx context.Context, in *GetOutputPathDigestFunctionsRequest, opts ...grpc.CallOption) (*GetOutputPathDigestFunctionsResponse, error)
+	SealBuild(ctx context.Context, in *SealBuildRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ExportTarball(ctx context.Context, in *ExportTarballRequest, opts ...grpc.CallOption) (OutputPaths_ExportTarballClient, error)
+	GetOutputPath(ctx context.Context, in *GetOutputPathRequest, opts ...grpc.CallOption) (*GetOutputPathResponse, error)
+	VerifyTree(ctx context.Context, in *VerifyTreeRequest, opts ...grpc.CallOption) (*VerifyTreeResponse, error)
+	SetBuildMetadata(ctx context.Context, in *SetBuildMetadataRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	DiffBuilds(ctx context.Context, in *DiffBuildsRequest, opts ...grpc.CallOption) (*DiffBuildsResponse, error)
+	ResolveOutputPath(ctx context.Context, in *ResolveOutputPathRequest, opts ...grpc.CallOption) (*ResolveOutputPathResponse, error)
+	ListOutputPaths(ctx context.Context, in *ListOutputPathsRequest, opts ...grpc.CallOption) (*ListOutputPathsResponse, error)
+	WatchBuilds(ctx context.Context, in *WatchBuildsRequest, opts ...grpc.CallOption) (OutputPaths_WatchBuildsClient, error)
+}
+
+type outputPathsClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewOutputPathsClient(cc grpc.ClientConnInterface) OutputPathsClient {
+	return &outputPathsClient{cc}
+}
+
+func (c *outputPathsClient) StatStream(ctx context.Context, in *remoteoutputservice.BatchStatRequest, opts ...grpc.CallOption) (OutputPaths_StatStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_OutputPaths_serviceDesc.Streams[0], "/buildbarn.outputpaths.OutputPaths/StatStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &outputPathsStatStreamClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type OutputPaths_StatStreamClient interface {
+	Recv() (*StatStreamResponse, error)
+	grpc.ClientStream
+}
+
+type outputPathsStatStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *outputPathsStatStreamClient) Recv() (*StatStreamResponse, error) {
+	m := new(StatStreamResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *outputPathsClient) GetActiveBuild(ctx context.Context, in *GetActiveBuildRequest, opts ...grpc.CallOption) (*GetActiveBuildResponse, error) {
+	out := new(GetActiveBuildResponse)
+	err := c.cc.Invoke(ctx, "/buildbarn.outputpaths.OutputPaths/GetActiveBuild", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *outputPathsClient) AbortBuild(ctx context.Context, in *AbortBuildRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/buildbarn.outputpaths.OutputPaths/AbortBuild", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *outputPathsClient) GetOutputPathErrors(ctx context.Context, in *GetOutputPathErrorsRequest, opts ...grpc.CallOption) (*GetOutputPathErrorsResponse, error) {
+	out := new(GetOutputPathErrorsResponse)
+	err := c.cc.Invoke(ctx, "/buildbarn.outputpaths.OutputPaths/GetOutputPathErrors", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *outputPathsClient) ExportTree(ctx context.Context, in *ExportTreeRequest, opts ...grpc.CallOption) (*ExportTreeResponse, error) {
+	out := new(ExportTreeResponse)
+	err := c.cc.Invoke(ctx, "/buildbarn.outputpaths.OutputPaths/ExportTree", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *outputPathsClient) CreateRootSymlink(ctx context.Context, in *CreateRootSymlinkRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/buildbarn.outputpaths.OutputPaths/CreateRootSymlink", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *outputPathsClient) RemoveRootSymlink(ctx context.Context, in *RemoveRootSymlinkRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/buildbarn.outputpaths.OutputPaths/RemoveRootSymlink", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *outputPathsClient) BestEffortBatchCreate(ctx context.Context, in *remoteoutputservice.BatchCreateRequest, opts ...grpc.CallOption) (*BestEffortBatchCreateResponse, error) {
+	out := new(BestEffortBatchCreateResponse)
+	err := c.cc.Invoke(ctx, "/buildbarn.outputpaths.OutputPaths/BestEffortBatchCreate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *outputPathsClient) CreateStream(ctx context.Context, opts ...grpc.CallOption) (OutputPaths_CreateStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_OutputPaths_serviceDesc.Streams[1], "/buildbarn.outputpaths.OutputPaths/CreateStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &outputPathsCreateStreamClient{stream}
+	return x, nil
+}
+
+type OutputPaths_CreateStreamClient interface {
+	Send(*CreateStreamRequest) error
+	CloseAndRecv() (*CreateStreamResponse, error)
+	grpc.ClientStream
+}
+
+type outputPathsCreateStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *outputPathsCreateStreamClient) Send(m *CreateStreamRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *outputPathsCreateStreamClient) CloseAndRecv() (*CreateStreamResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(CreateStreamResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *outputPathsClient) ExtendedBatchCreate(ctx context.Context, in *ExtendedBatchCreateRequest, opts ...grpc.CallOption) (*ExtendedBatchCreateResponse, error) {
+	out := new(ExtendedBatchCreateResponse)
+	err := c.cc.Invoke(ctx, "/buildbarn.outputpaths.OutputPaths/ExtendedBatchCreate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *outputPathsClient) GetOutputPathStats(ctx context.Context, in *GetOutputPathStatsRequest, opts ...grpc.CallOption) (*GetOutputPathStatsResponse, error) {
+	out := new(GetOutputPathStatsResponse)
+	err := c.cc.Invoke(ctx, "/buildbarn.outputpaths.OutputPaths/GetOutputPathStats", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *outputPathsClient) ExtendedBatchStat(ctx context.Context, in *ExtendedBatchStatRequest, opts ...grpc.CallOption) (*ExtendedBatchStatResponse, error) {
+	out := new(ExtendedBatchStatResponse)
+	err := c.cc.Invoke(ctx, "/buildbarn.outputpaths.OutputPaths/ExtendedBatchStat", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *outputPathsClient) BatchStartBuild(ctx context.Context, in *BatchStartBuildRequest, opts ...grpc.CallOption) (*BatchStartBuildResponse, error) {
+	out := new(BatchStartBuildResponse)
+	err := c.cc.Invoke(ctx, "/buildbarn.outputpaths.OutputPaths/BatchStartBuild", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *outputPathsClient) ReadDirectory(ctx context.Context, in *ReadDirectoryRequest, opts ...grpc.CallOption) (*ReadDirectoryResponse, error) {
+	out := new(ReadDirectoryResponse)
+	err := c.cc.Invoke(ctx, "/buildbarn.outputpaths.OutputPaths/ReadDirectory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *outputPathsClient) GetDaemonStats(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*GetDaemonStatsResponse, error) {
+	out := new(GetDaemonStatsResponse)
+	err := c.cc.Invoke(ctx, "/buildbarn.outputpaths.OutputPaths/GetDaemonStats", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *outputPathsClient) SetExternalPathPolicy(ctx context.Context, in *SetExternalPathPolicyRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/buildbarn.outputpaths.OutputPaths/SetExternalPathPolicy", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *outputPathsClient) GetDirectoryResidency(ctx context.Context, in *GetDirectoryResidencyRequest, opts ...grpc.CallOption) (*GetDirectoryResidencyResponse, error) {
+	out := new(GetDirectoryResidencyResponse)
+	err := c.cc.Invoke(ctx, "/buildbarn.outputpaths.OutputPaths/GetDirectoryResidency", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *outputPathsClient) SetOutputPath(ctx context.Context, in *SetOutputPathRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/buildbarn.outputpaths.OutputPaths/SetOutputPath", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *outputPathsClient) FinalizeSubtree(ctx context.Context, in *FinalizeSubtreeRequest, opts ...grpc.CallOption) (*FinalizeSubtreeResponse, error) {
+	out := new(FinalizeSubtreeResponse)
+	err := c.cc.Invoke(ctx, "/buildbarn.outputpaths.OutputPaths/FinalizeSubtree", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *outputPathsClient) ListFinalizedBuilds(ctx context.Context, in *ListFinalizedBuildsRequest, opts ...grpc.CallOption) (*ListFinalizedBuildsResponse, error) {
+	out := new(ListFinalizedBuildsResponse)
+	err := c.cc.Invoke(ctx, "/buildbarn.outputpaths.OutputPaths/ListFinalizedBuilds", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *outputPathsClient) SetDefaultPathPrefix(ctx context.Context, in *SetDefaultPathPrefixRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/buildbarn.outputpaths.OutputPaths/SetDefaultPathPrefix", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *outputPathsClient) RepairOutputPath(ctx context.Context, in *RepairOutputPathRequest, opts ...grpc.CallOption) (*RepairOutputPathResponse, error) {
+	out := new(RepairOutputPathResponse)
+	err := c.cc.Invoke(ctx, "/buildbarn.outputpaths.OutputPaths/RepairOutputPath", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *outputPathsClient) Materialize(ctx context.Context, in *MaterializeRequest, opts ...grpc.CallOption) (OutputPaths_MaterializeClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_OutputPaths_serviceDesc.Streams[2], "/buildbarn.outputpaths.OutputPaths/Materialize", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &outputPathsMaterializeClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type OutputPaths_MaterializeClient interface {
+	Recv() (*MaterializeResponse, error)
+	grpc.ClientStream
+}
+
+type outputPathsMaterializeClient struct {
+	grpc.ClientStream
+}
+
+func (x *outputPathsMaterializeClient) Recv() (*MaterializeResponse, error) {
+	m := new(MaterializeResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *outputPathsClient) ReopenBuild(ctx context.Context, in *ReopenBuildRequest, opts ...grpc.CallOption) (*remoteoutputservice.StartBuildResponse, error) {
+	out := new(remoteoutputservice.StartBuildResponse)
+	err := c.cc.Invoke(ctx, "/buildbarn.outputpaths.OutputPaths/ReopenBuild", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *outputPathsClient) InvalidateCache(ctx context.Context, in *InvalidateCacheRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/buildbarn.outputpaths.OutputPaths/InvalidateCache", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *outputPathsClient) StartBuildStream(ctx context.Context, in *remoteoutputservice.StartBuildRequest, opts ...grpc.CallOption) (OutputPaths_StartBuildStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_OutputPaths_serviceDesc.Streams[3], "/buildbarn.outputpaths.OutputPaths/StartBuildStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &outputPathsStartBuildStreamClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type OutputPaths_StartBuildStreamClient interface {
+	Recv() (*StartBuildStreamResponse, error)
+	grpc.ClientStream
+}
+
+type outputPathsStartBuildStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *outputPathsStartBuildStreamClient) Recv() (*StartBuildStreamResponse, error) {
+	m := new(StartBuildStreamResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *outputPathsClient) CleanStream(ctx context.Context, in *remoteoutputservice.CleanRequest, opts ...grpc.CallOption) (OutputPaths_CleanStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_OutputPaths_serviceDesc.Streams[4], "/buildbarn.outputpaths.OutputPaths/CleanStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &outputPathsCleanStreamClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type OutputPaths_CleanStreamClient interface {
+	Recv() (*CleanStreamResponse, error)
+	grpc.ClientStream
+}
+
+type outputPathsCleanStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *outputPathsCleanStreamClient) Recv() (*CleanStreamResponse, error) {
+	m := new(CleanStreamResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *outputPathsClient) CleanAndStartBuild(ctx context.Context, in *remoteoutputservice.StartBuildRequest, opts ...grpc.CallOption) (*remoteoutputservice.StartBuildResponse, error) {
+	out := new(remoteoutputservice.StartBuildResponse)
+	err := c.cc.Invoke(ctx, "/buildbarn.outputpaths.OutputPaths/CleanAndStartBuild", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *outputPathsClient) CloneOutputPath(ctx context.Context, in *CloneOutputPathRequest, opts ...grpc.CallOption) (*CloneOutputPathResponse, error) {
+	out := new(CloneOutputPathResponse)
+	err := c.cc.Invoke(ctx, "/buildbarn.outputpaths.OutputPaths/CloneOutputPath", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *outputPathsClient) StatDigest(ctx context.Context, in *StatDigestRequest, opts ...grpc.CallOption) (*StatDigestResponse, error) {
+	out := new(StatDigestResponse)
+	err := c.cc.Invoke(ctx, "/buildbarn.outputpaths.OutputPaths/StatDigest", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *outputPathsClient) RenameOutputBase(ctx context.Context, in *RenameOutputBaseRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/buildbarn.outputpaths.OutputPaths/RenameOutputBase", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *outputPathsClient) GetOutputPathDigestFunctions(ctx context.Context, in *GetOutputPathDigestFunctionsRequest, opts ...grpc.CallOption) (*GetOutputPathDigestFunctionsResponse, error) {
+	out := new(GetOutputPathDigestFunctionsResponse)
+	err := c.cc.Invoke(ctx, "/buildbarn.outputpaths.OutputPaths/GetOutputPathDigestFunctions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *outputPathsClient) SealBuild(ctx context.Context, in *SealBuildRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/buildbarn.outputpaths.OutputPaths/SealBuild", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *outputPathsClient) ExportTarball(ctx context.Context, in *ExportTarballRequest, opts ...grpc.CallOption) (OutputPaths_ExportTarballClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_OutputPaths_serviceDesc.Streams[5], "/buildbarn.outputpaths.OutputPaths/ExportTarball", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &outputPathsExportTarballClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type OutputPaths_ExportTarballClient interface {
+	Recv() (*ExportTarballResponse, error)
+	grpc.ClientStream
+}
+
+type outputPathsExportTarballClient struct {
+	grpc.ClientStream
+}
+
+func (x *outputPathsExportTarballClient) Recv() (*ExportTarballResponse, error) {
+	m := new(ExportTarballResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *outputPathsClient) GetOutputPath(ctx context.Context, in *GetOutputPathRequest, opts ...grpc.CallOption) (*GetOutputPathResponse, error) {
+	out := new(GetOutputPathResponse)
+	err := c.cc.Invoke(ctx, "/buildbarn.outputpaths.OutputPaths/GetOutputPath", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *outputPathsClient) VerifyTree(ctx context.Context, in *VerifyTreeRequest, opts ...grpc.CallOption) (*VerifyTreeResponse, error) {
+	out := new(VerifyTreeResponse)
+	err := c.cc.Invoke(ctx, "/buildbarn.outputpaths.OutputPaths/VerifyTree", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *outputPathsClient) SetBuildMetadata(ctx context.Context, in *SetBuildMetadataRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/buildbarn.outputpaths.OutputPaths/SetBuildMetadata", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *outputPathsClient) DiffBuilds(ctx context.Context, in *DiffBuildsRequest, opts ...grpc.CallOption) (*DiffBuildsResponse, error) {
+	out := new(DiffBuildsResponse)
+	err := c.cc.Invoke(ctx, "/buildbarn.outputpaths.OutputPaths/DiffBuilds", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *outputPathsClient) ResolveOutputPath(ctx context.Context, in *ResolveOutputPathRequest, opts ...grpc.CallOption) (*ResolveOutputPathResponse, error) {
+	out := new(ResolveOutputPathResponse)
+	err := c.cc.Invoke(ctx, "/buildbarn.outputpaths.OutputPaths/ResolveOutputPath", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *outputPathsClient) ListOutputPaths(ctx context.Context, in *ListOutputPathsRequest, opts ...grpc.CallOption) (*ListOutputPathsResponse, error) {
+	out := new(ListOutputPathsResponse)
+	err := c.cc.Invoke(ctx, "/buildbarn.outputpaths.OutputPaths/ListOutputPaths", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *outputPathsClient) WatchBuilds(ctx context.Context, in *WatchBuildsRequest, opts ...grpc.CallOption) (OutputPaths_WatchBuildsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_OutputPaths_serviceDesc.Streams[6], "/buildbarn.outputpaths.OutputPaths/WatchBuilds", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &outputPathsWatchBuildsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type OutputPaths_WatchBuildsClient interface {
+	Recv() (*WatchBuildsResponse, error)
+	grpc.ClientStream
+}
+
+type outputPathsWatchBuildsClient struct {
+	grpc.ClientStream
+}
+
+func (x *outputPathsWatchBuildsClient) Recv() (*WatchBuildsResponse, error) {
+	m := new(WatchBuildsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// OutputPathsServer is the server API for OutputPaths service.
+type OutputPathsServer interface {
+	StatStream(*remoteoutputservice.BatchStatRequest, OutputPaths_StatStreamServer) error
+	GetActiveBuild(context.Context, *GetActiveBuildRequest) (*GetActiveBuildResponse, error)
+	AbortBuild(context.Context, *AbortBuildRequest) (*emptypb.Empty, error)
+	GetOutputPathErrors(context.Context, *GetOutputPathErrorsRequest) (*GetOutputPathErrorsResponse, error)
+	ExportTree(context.Context, *ExportTreeRequest) (*ExportTreeResponse, error)
+	CreateRootSymlink(context.Context, *CreateRootSymlinkRequest) (*emptypb.Empty, error)
+	RemoveRootSymlink(context.Context, *RemoveRootSymlinkRequest) (*emptypb.Empty, error)
+	BestEffortBatchCreate(context.Context, *remoteoutputservice.BatchCreateRequest) (*BestEffortBatchCreateResponse, error)
+	CreateStream(OutputPaths_CreateStreamServer) error
+	ExtendedBatchCreate(context.Context, *ExtendedBatchCreateRequest) (*ExtendedBatchCreateResponse, error)
+	GetOutputPathStats(context.Context, *GetOutputPathStatsRequest) (*GetOutputPathStatsResponse, error)
+	ExtendedBatchStat(context.Context, *ExtendedBatchStatRequest) (*ExtendedBatchStatResponse, error)
+	BatchStartBuild(context.Context, *BatchStartBuildRequest) (*BatchStartBuildResponse, error)
+	ReadDirectory(context.Context, *ReadDirectoryRequest) (*ReadDirectoryResponse, error)
+	GetDaemonStats(context.Context, *emptypb.Empty) (*GetDaemonStatsResponse, error)
+	SetExternalPathPolicy(context.Context, *SetExternalPathPolicyRequest) (*emptypb.Empty, error)
+	GetDirectoryResidency(context.Context, *GetDirectoryResidencyRequest) (*GetDirectoryResidencyResponse, error)
+	SetOutputPath(context.Context, *SetOutputPathRequest) (*emptypb.Empty, error)
+	FinalizeSubtree(context.Context, *FinalizeSubtreeRequest) (*FinalizeSubtreeResponse, error)
+	ListFinalizedBuilds(context.Context, *ListFinalizedBuildsRequest) (*ListFinalizedBuildsResponse, error)
+	SetDefaultPathPrefix(context.Context, *SetDefaultPathPrefixRequest) (*emptypb.Empty, error)
+	RepairOutputPath(context.Context, *RepairOutputPathRequest) (*RepairOutputPathResponse, error)
+	Materialize(*MaterializeRequest, OutputPaths_MaterializeServer) error
+	ReopenBuild(context.Context, *ReopenBuildRequest) (*remoteoutputservice.StartBuildResponse, error)
+	InvalidateCache(context.Context, *InvalidateCacheRequest) (*emptypb.Empty, error)
+	StartBuildStream(*remoteoutputservice.StartBuildRequest, OutputPaths_StartBuildStreamServer) error
+	CleanStream(*remoteoutputservice.CleanRequest, OutputPaths_CleanStreamServer) error
+	CleanAndStartBuild(context.Context, *remoteoutputservice.StartBuildRequest) (*remoteoutputservice.StartBuildResponse, error)
+	CloneOutputPath(context.Context, *CloneOutputPathRequest) (*CloneOutputPathResponse, error)
+	StatDigest(context.Context, *StatDigestRequest) (*StatDigestResponse, error)
+	RenameOutputBase(context.Context, *RenameOutputBaseRequest) (*emptypb.Empty, error)
+	GetOutputPathDigestFunctions(context.Context, *GetOutputPathDigestFunctionsRequest) (*GetOutputPathDigestFunctionsResponse, error)
+	SealBuild(context.Context, *SealBuildRequest) (*emptypb.Empty, error)
+	ExportTarball(*ExportTarballRequest, OutputPaths_ExportTarballServer) error
+	GetOutputPath(context.Context, *GetOutputPathRequest) (*GetOutputPathResponse, error)
+	VerifyTree(context.Context, *VerifyTreeRequest) (*VerifyTreeResponse, error)
+	SetBuildMetadata(context.Context, *SetBuildMetadataRequest) (*emptypb.Empty, error)
+	DiffBuilds(context.Context, *DiffBuildsRequest) (*DiffBuildsResponse, error)
+	ResolveOutputPath(context.Context, *ResolveOutputPathRequest) (*ResolveOutputPathResponse, error)
+	ListOutputPaths(context.Context, *ListOutputPathsRequest) (*ListOutputPathsResponse, error)
+	WatchBuilds(*WatchBuildsRequest, OutputPaths_WatchBuildsServer) error
+}
+
+// UnimplementedOutputPathsServer can be embedded to have forward compatible implementations.
+type UnimplementedOutputPathsServer struct {
+}
+
+func (*UnimplementedOutputPathsServer) StatStream(*remoteoutputservice.BatchStatRequest, OutputPaths_StatStreamServer) error {
+	return status1.Errorf(codes.Unimplemented, "method StatStream not implemented")
+}
+func (*UnimplementedOutputPathsServer) GetActiveBuild(context.Context, *GetActiveBuildRequest) (*GetActiveBuildResponse, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method GetActiveBuild not implemented")
+}
+func (*UnimplementedOutputPathsServer) AbortBuild(context.Context, *AbortBuildRequest) (*emptypb.Empty, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method AbortBuild not implemented")
+}
+func (*UnimplementedOutputPathsServer) GetOutputPathErrors(context.Context, *GetOutputPathErrorsRequest) (*GetOutputPathErrorsResponse, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method GetOutputPathErrors not implemented")
+}
+func (*UnimplementedOutputPathsServer) ExportTree(context.Context, *ExportTreeRequest) (*ExportTreeResponse, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method ExportTree not implemented")
+}
+func (*UnimplementedOutputPathsServer) CreateRootSymlink(context.Context, *CreateRootSymlinkRequest) (*emptypb.Empty, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method CreateRootSymlink not implemented")
+}
+func (*UnimplementedOutputPathsServer) RemoveRootSymlink(context.Context, *RemoveRootSymlinkRequest) (*emptypb.Empty, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method RemoveRootSymlink not implemented")
+}
+func (*UnimplementedOutputPathsServer) BestEffortBatchCreate(context.Context, *remoteoutputservice.BatchCreateRequest) (*BestEffortBatchCreateResponse, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method BestEffortBatchCreate not implemented")
+}
+func (*UnimplementedOutputPathsServer) CreateStream(OutputPaths_CreateStreamServer) error {
+	return status1.Errorf(codes.Unimplemented, "method CreateStream not implemented")
+}
+func (*UnimplementedOutputPathsServer) ExtendedBatchCreate(context.Context, *ExtendedBatchCreateRequest) (*ExtendedBatchCreateResponse, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method ExtendedBatchCreate not implemented")
+}
+func (*UnimplementedOutputPathsServer) GetOutputPathStats(context.Context, *GetOutputPathStatsRequest) (*GetOutputPathStatsResponse, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method GetOutputPathStats not implemented")
+}
+func (*UnimplementedOutputPathsServer) ExtendedBatchStat(context.Context, *ExtendedBatchStatRequest) (*ExtendedBatchStatResponse, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method ExtendedBatchStat not implemented")
+}
+func (*UnimplementedOutputPathsServer) BatchStartBuild(context.Context, *BatchStartBuildRequest) (*BatchStartBuildResponse, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method BatchStartBuild not implemented")
+}
+func (*UnimplementedOutputPathsServer) ReadDirectory(context.Context, *ReadDirectoryRequest) (*ReadDirectoryResponse, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method ReadDirectory not implemented")
+}
+func (*UnimplementedOutputPathsServer) GetDaemonStats(context.Context, *emptypb.Empty) (*GetDaemonStatsResponse, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method GetDaemonStats not implemented")
+}
+func (*UnimplementedOutputPathsServer) SetExternalPathPolicy(context.Context, *SetExternalPathPolicyRequest) (*emptypb.Empty, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method SetExternalPathPolicy not implemented")
+}
+func (*UnimplementedOutputPathsServer) GetDirectoryResidency(context.Context, *GetDirectoryResidencyRequest) (*GetDirectoryResidencyResponse, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method GetDirectoryResidency not implemented")
+}
+func (*UnimplementedOutputPathsServer) SetOutputPath(context.Context, *SetOutputPathRequest) (*emptypb.Empty, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method SetOutputPath not implemented")
+}
+func (*UnimplementedOutputPathsServer) FinalizeSubtree(context.Context, *FinalizeSubtreeRequest) (*FinalizeSubtreeResponse, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method FinalizeSubtree not implemented")
+}
+func (*UnimplementedOutputPathsServer) ListFinalizedBuilds(context.Context, *ListFinalizedBuildsRequest) (*ListFinalizedBuildsResponse, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method ListFinalizedBuilds not implemented")
+}
+func (*UnimplementedOutputPathsServer) SetDefaultPathPrefix(context.Context, *SetDefaultPathPrefixRequest) (*emptypb.Empty, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method SetDefaultPathPrefix not implemented")
+}
+func (*UnimplementedOutputPathsServer) RepairOutputPath(context.Context, *RepairOutputPathRequest) (*RepairOutputPathResponse, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method RepairOutputPath not implemented")
+}
+func (*UnimplementedOutputPathsServer) Materialize(*MaterializeRequest, OutputPaths_MaterializeServer) error {
+	return status1.Errorf(codes.Unimplemented, "method Materialize not implemented")
+}
+func (*UnimplementedOutputPathsServer) ReopenBuild(context.Context, *ReopenBuildRequest) (*remoteoutputservice.StartBuildResponse, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method ReopenBuild not implemented")
+}
+func (*UnimplementedOutputPathsServer) InvalidateCache(context.Context, *InvalidateCacheRequest) (*emptypb.Empty, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method InvalidateCache not implemented")
+}
+func (*UnimplementedOutputPathsServer) StartBuildStream(*remoteoutputservice.StartBuildRequest, OutputPaths_StartBuildStreamServer) error {
+	return status1.Errorf(codes.Unimplemented, "method StartBuildStream not implemented")
+}
+func (*UnimplementedOutputPathsServer) CleanStream(*remoteoutputservice.CleanRequest, OutputPaths_CleanStreamServer) error {
+	return status1.Errorf(codes.Unimplemented, "method CleanStream not implemented")
+}
+func (*UnimplementedOutputPathsServer) CleanAndStartBuild(context.Context, *remoteoutputservice.StartBuildRequest) (*remoteoutputservice.StartBuildResponse, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method CleanAndStartBuild not implemented")
+}
+func (*UnimplementedOutputPathsServer) CloneOutputPath(context.Context, *CloneOutputPathRequest) (*CloneOutputPathResponse, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method CloneOutputPath not implemented")
+}
+func (*UnimplementedOutputPathsServer) StatDigest(context.Context, *StatDigestRequest) (*StatDigestResponse, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method StatDigest not implemented")
+}
+func (*UnimplementedOutputPathsServer) RenameOutputBase(context.Context, *RenameOutputBaseRequest) (*emptypb.Empty, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method RenameOutputBase not implemented")
+}
+func (*UnimplementedOutputPathsServer) GetOutputPathDigestFunctions(context.Context, *GetOutputPathDigestFunctionsRequest) (*GetOutputPathDigestFunctionsResponse, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method GetOutputPathDigestFunctions not implemented")
+}
+func (*UnimplementedOutputPathsServer) SealBuild(context.Context, *SealBuildRequest) (*emptypb.Empty, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method SealBuild not implemented")
+}
+func (*UnimplementedOutputPathsServer) ExportTarball(*ExportTarballRequest, OutputPaths_ExportTarballServer) error {
+	return status1.Errorf(codes.Unimplemented, "method ExportTarball not implemented")
+}
+func (*UnimplementedOutputPathsServer) GetOutputPath(context.Context, *GetOutputPathRequest) (*GetOutputPathResponse, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method GetOutputPath not implemented")
+}
+func (*UnimplementedOutputPathsServer) VerifyTree(context.Context, *VerifyTreeRequest) (*VerifyTreeResponse, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method VerifyTree not implemented")
+}
+func (*UnimplementedOutputPathsServer) SetBuildMetadata(context.Context, *SetBuildMetadataRequest) (*emptypb.Empty, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method SetBuildMetadata not implemented")
+}
+func (*UnimplementedOutputPathsServer) DiffBuilds(context.Context, *DiffBuildsRequest) (*DiffBuildsResponse, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method DiffBuilds not implemented")
+}
+func (*UnimplementedOutputPathsServer) ResolveOutputPath(context.Context, *ResolveOutputPathRequest) (*ResolveOutputPathResponse, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method ResolveOutputPath not implemented")
+}
+func (*UnimplementedOutputPathsServer) ListOutputPaths(context.Context, *ListOutputPathsRequest) (*ListOutputPathsResponse, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method ListOutputPaths not implemented")
+}
+func (*UnimplementedOutputPathsServer) WatchBuilds(*WatchBuildsRequest, OutputPaths_WatchBuildsServer) error {
+	return status1.Errorf(codes.Unimplemented, "method WatchBuilds not implemented")
+}
+
+func RegisterOutputPathsServer(s grpc.ServiceRegistrar, srv OutputPathsServer) {
+	s.RegisterService(&_OutputPaths_serviceDesc, srv)
+}
+
+func _OutputPaths_StatStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(remoteoutputservice.BatchStatRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(OutputPathsServer).StatStream(m, &outputPathsStatStreamServer{stream})
+}
+
+type OutputPaths_StatStreamServer interface {
+	Send(*StatStreamResponse) error
+	grpc.ServerStream
+}
+
+type outputPathsStatStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *outputPathsStatStreamServer) Send(m *StatStreamResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _OutputPaths_GetActiveBuild_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetActiveBuildRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OutputPathsServer).GetActiveBuild(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/buildbarn.outputpaths.OutputPaths/GetActiveBuild",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OutputPathsServer).GetActiveBuild(ctx, req.(*GetActiveBuildRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OutputPaths_AbortBuild_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AbortBuildRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OutputPathsServer).AbortBuild(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/buildbarn.outputpaths.OutputPaths/AbortBuild",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OutputPathsServer).AbortBuild(ctx, req.(*AbortBuildRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OutputPaths_GetOutputPathErrors_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetOutputPathErrorsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OutputPathsServer).GetOutputPathErrors(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/buildbarn.outputpaths.OutputPaths/GetOutputPathErrors",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OutputPathsServer).GetOutputPathErrors(ctx, req.(*GetOutputPathErrorsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OutputPaths_ExportTree_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportTreeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OutputPathsServer).ExportTree(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/buildbarn.outputpaths.OutputPaths/ExportTree",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OutputPathsServer).ExportTree(ctx, req.(*ExportTreeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OutputPaths_CreateRootSymlink_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateRootSymlinkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OutputPathsServer).CreateRootSymlink(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/buildbarn.outputpaths.OutputPaths/CreateRootSymlink",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OutputPathsServer).CreateRootSymlink(ctx, req.(*CreateRootSymlinkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OutputPaths_RemoveRootSymlink_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveRootSymlinkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OutputPathsServer).RemoveRootSymlink(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/buildbarn.outputpaths.OutputPaths/RemoveRootSymlink",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OutputPathsServer).RemoveRootSymlink(ctx, req.(*RemoveRootSymlinkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OutputPaths_BestEffortBatchCreate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(remoteoutputservice.BatchCreateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OutputPathsServer).BestEffortBatchCreate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/buildbarn.outputpaths.OutputPaths/BestEffortBatchCreate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OutputPathsServer).BestEffortBatchCreate(ctx, req.(*remoteoutputservice.BatchCreateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OutputPaths_CreateStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(OutputPathsServer).CreateStream(&outputPathsCreateStreamServer{stream})
+}
+
+type OutputPaths_CreateStreamServer interface {
+	SendAndClose(*CreateStreamResponse) error
+	Recv() (*CreateStreamRequest, error)
+	grpc.ServerStream
+}
+
+type outputPathsCreateStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *outputPathsCreateStreamServer) SendAndClose(m *CreateStreamResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *outputPathsCreateStreamServer) Recv() (*CreateStreamRequest, error) {
+	m := new(CreateStreamRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _OutputPaths_ExtendedBatchCreate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExtendedBatchCreateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OutputPathsServer).ExtendedBatchCreate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/buildbarn.outputpaths.OutputPaths/ExtendedBatchCreate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OutputPathsServer).ExtendedBatchCreate(ctx, req.(*ExtendedBatchCreateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OutputPaths_GetOutputPathStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetOutputPathStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OutputPathsServer).GetOutputPathStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/buildbarn.outputpaths.OutputPaths/GetOutputPathStats",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OutputPathsServer).GetOutputPathStats(ctx, req.(*GetOutputPathStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OutputPaths_ExtendedBatchStat_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExtendedBatchStatRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OutputPathsServer).ExtendedBatchStat(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/buildbarn.outputpaths.OutputPaths/ExtendedBatchStat",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OutputPathsServer).ExtendedBatchStat(ctx, req.(*ExtendedBatchStatRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OutputPaths_BatchStartBuild_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchStartBuildRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OutputPathsServer).BatchStartBuild(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/buildbarn.outputpaths.OutputPaths/BatchStartBuild",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OutputPathsServer).BatchStartBuild(ctx, req.(*BatchStartBuildRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OutputPaths_ReadDirectory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReadDirectoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OutputPathsServer).ReadDirectory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/buildbarn.outputpaths.OutputPaths/ReadDirectory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OutputPathsServer).ReadDirectory(ctx, req.(*ReadDirectoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OutputPaths_GetDaemonStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OutputPathsServer).GetDaemonStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/buildbarn.outputpaths.OutputPaths/GetDaemonStats",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OutputPathsServer).GetDaemonStats(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OutputPaths_SetExternalPathPolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetExternalPathPolicyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OutputPathsServer).SetExternalPathPolicy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/buildbarn.outputpaths.OutputPaths/SetExternalPathPolicy",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OutputPathsServer).SetExternalPathPolicy(ctx, req.(*SetExternalPathPolicyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OutputPaths_GetDirectoryResidency_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDirectoryResidencyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OutputPathsServer).GetDirectoryResidency(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/buildbarn.outputpaths.OutputPaths/GetDirectoryResidency",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OutputPathsServer).GetDirectoryResidency(ctx, req.(*GetDirectoryResidencyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OutputPaths_SetOutputPath_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetOutputPathRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OutputPathsServer).SetOutputPath(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/buildbarn.outputpaths.OutputPaths/SetOutputPath",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OutputPathsServer).SetOutputPath(ctx, req.(*SetOutputPathRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OutputPaths_FinalizeSubtree_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FinalizeSubtreeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OutputPathsServer).FinalizeSubtree(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/buildbarn.outputpaths.OutputPaths/FinalizeSubtree",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OutputPathsServer).FinalizeSubtree(ctx, req.(*FinalizeSubtreeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OutputPaths_ListFinalizedBuilds_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListFinalizedBuildsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OutputPathsServer).ListFinalizedBuilds(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/buildbarn.outputpaths.OutputPaths/ListFinalizedBuilds",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OutputPathsServer).ListFinalizedBuilds(ctx, req.(*ListFinalizedBuildsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OutputPaths_SetDefaultPathPrefix_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetDefaultPathPrefixRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OutputPathsServer).SetDefaultPathPrefix(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/buildbarn.outputpaths.OutputPaths/SetDefaultPathPrefix",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OutputPathsServer).SetDefaultPathPrefix(ctx, req.(*SetDefaultPathPrefixRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OutputPaths_RepairOutputPath_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RepairOutputPathRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OutputPathsServer).RepairOutputPath(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/buildbarn.outputpaths.OutputPaths/RepairOutputPath",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OutputPathsServer).RepairOutputPath(ctx, req.(*RepairOutputPathRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OutputPaths_Materialize_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(MaterializeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(OutputPathsServer).Materialize(m, &outputPathsMaterializeServer{stream})
+}
+
+type OutputPaths_MaterializeServer interface {
+	Send(*MaterializeResponse) error
+	grpc.ServerStream
+}
+
+type outputPathsMaterializeServer struct {
+	grpc.ServerStream
+}
+
+func (x *outputPathsMaterializeServer) Send(m *MaterializeResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _OutputPaths_ReopenBuild_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReopenBuildRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OutputPathsServer).ReopenBuild(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/buildbarn.outputpaths.OutputPaths/ReopenBuild",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OutputPathsServer).ReopenBuild(ctx, req.(*ReopenBuildRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OutputPaths_InvalidateCache_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InvalidateCacheRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OutputPathsServer).InvalidateCache(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/buildbarn.outputpaths.OutputPaths/InvalidateCache",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OutputPathsServer).InvalidateCache(ctx, req.(*InvalidateCacheRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OutputPaths_StartBuildStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(remoteoutputservice.StartBuildRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(OutputPathsServer).StartBuildStream(m, &outputPathsStartBuildStreamServer{stream})
+}
+
+type OutputPaths_StartBuildStreamServer interface {
+	Send(*StartBuildStreamResponse) error
+	grpc.ServerStream
+}
+
+type outputPathsStartBuildStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *outputPathsStartBuildStreamServer) Send(m *StartBuildStreamResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _OutputPaths_CleanStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(remoteoutputservice.CleanRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(OutputPathsServer).CleanStream(m, &outputPathsCleanStreamServer{stream})
+}
+
+type OutputPaths_CleanStreamServer interface {
+	Send(*CleanStreamResponse) error
+	grpc.ServerStream
+}
+
+type outputPathsCleanStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *outputPathsCleanStreamServer) Send(m *CleanStreamResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _OutputPaths_CleanAndStartBuild_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(remoteoutputservice.StartBuildRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OutputPathsServer).CleanAndStartBuild(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/buildbarn.outputpaths.OutputPaths/CleanAndStartBuild",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OutputPathsServer).CleanAndStartBuild(ctx, req.(*remoteoutputservice.StartBuildRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OutputPaths_CloneOutputPath_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CloneOutputPathRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OutputPathsServer).CloneOutputPath(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/buildbarn.outputpaths.OutputPaths/CloneOutputPath",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OutputPathsServer).CloneOutputPath(ctx, req.(*CloneOutputPathRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OutputPaths_StatDigest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StatDigestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OutputPathsServer).StatDigest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/buildbarn.outputpaths.OutputPaths/StatDigest",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OutputPathsServer).StatDigest(ctx, req.(*StatDigestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OutputPaths_RenameOutputBase_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RenameOutputBaseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OutputPathsServer).RenameOutputBase(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/buildbarn.outputpaths.OutputPaths/RenameOutputBase",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OutputPathsServer).RenameOutputBase(ctx, req.(*RenameOutputBaseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OutputPaths_GetOutputPathDigestFunctions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetOutputPathDigestFunctionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OutputPathsServer).GetOutputPathDigestFunctions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/buildbarn.outputpaths.OutputPaths/GetOutputPathDigestFunctions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OutputPathsServer).GetOutputPathDigestFunctions(ctx, req.(*GetOutputPathDigestFunctionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OutputPaths_SealBuild_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SealBuildRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OutputPathsServer).SealBuild(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/buildbarn.outputpaths.OutputPaths/SealBuild",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OutputPathsServer).SealBuild(ctx, req.(*SealBuildRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OutputPaths_ExportTarball_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportTarballRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(OutputPathsServer).ExportTarball(m, &outputPathsExportTarballServer{stream})
+}
+
+type OutputPaths_ExportTarballServer interface {
+	Send(*ExportTarballResponse) error
+	grpc.ServerStream
+}
+
+type outputPathsExportTarballServer struct {
+	grpc.ServerStream
+}
+
+func (x *outputPathsExportTarballServer) Send(m *ExportTarballResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _OutputPaths_GetOutputPath_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetOutputPathRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OutputPathsServer).GetOutputPath(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/buildbarn.outputpaths.OutputPaths/GetOutputPath",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OutputPathsServer).GetOutputPath(ctx, req.(*GetOutputPathRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OutputPaths_VerifyTree_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyTreeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OutputPathsServer).VerifyTree(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/buildbarn.outputpaths.OutputPaths/VerifyTree",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OutputPathsServer).VerifyTree(ctx, req.(*VerifyTreeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OutputPaths_SetBuildMetadata_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetBuildMetadataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OutputPathsServer).SetBuildMetadata(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/buildbarn.outputpaths.OutputPaths/SetBuildMetadata",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OutputPathsServer).SetBuildMetadata(ctx, req.(*SetBuildMetadataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OutputPaths_DiffBuilds_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DiffBuildsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OutputPathsServer).DiffBuilds(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/buildbarn.outputpaths.OutputPaths/DiffBuilds",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OutputPathsServer).DiffBuilds(ctx, req.(*DiffBuildsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OutputPaths_ResolveOutputPath_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResolveOutputPathRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OutputPathsServer).ResolveOutputPath(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/buildbarn.outputpaths.OutputPaths/ResolveOutputPath",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OutputPathsServer).ResolveOutputPath(ctx, req.(*ResolveOutputPathRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OutputPaths_ListOutputPaths_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListOutputPathsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OutputPathsServer).ListOutputPaths(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/buildbarn.outputpaths.OutputPaths/ListOutputPaths",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OutputPathsServer).ListOutputPaths(ctx, req.(*ListOutputPathsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OutputPaths_WatchBuilds_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchBuildsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(OutputPathsServer).WatchBuilds(m, &outputPathsWatchBuildsServer{stream})
+}
+
+type OutputPaths_WatchBuildsServer interface {
+	Send(*WatchBuildsResponse) error
+	grpc.ServerStream
+}
+
+type outputPathsWatchBuildsServer struct {
+	grpc.ServerStream
+}
+
+func (x *outputPathsWatchBuildsServer) Send(m *WatchBuildsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _OutputPaths_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "buildbarn.outputpaths.OutputPaths",
+	HandlerType: (*OutputPathsServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetActiveBuild",
+			Handler:    _OutputPaths_GetActiveBuild_Handler,
+		},
+		{
+			MethodName: "AbortBuild",
+			Handler:    _OutputPaths_AbortBuild_Handler,
+		},
+		{
+			MethodName: "GetOutputPathErrors",
+			Handler:    _OutputPaths_GetOutputPathErrors_Handler,
+		},
+		{
+			MethodName: "ExportTree",
+			Handler:    _OutputPaths_ExportTree_Handler,
+		},
+		{
+			MethodName: "CreateRootSymlink",
+			Handler:    _OutputPaths_CreateRootSymlink_Handler,
+		},
+		{
+			MethodName: "RemoveRootSymlink",
+			Handler:    _OutputPaths_RemoveRootSymlink_Handler,
+		},
+		{
+			MethodName: "BestEffortBatchCreate",
+			Handler:    _OutputPaths_BestEffortBatchCreate_Handler,
+		},
+		{
+			MethodName: "ExtendedBatchCreate",
+			Handler:    _OutputPaths_ExtendedBatchCreate_Handler,
+		},
+		{
+			MethodName: "GetOutputPathStats",
+			Handler:    _OutputPaths_GetOutputPathStats_Handler,
+		},
+		{
+			MethodName: "ExtendedBatchStat",
+			Handler:    _OutputPaths_ExtendedBatchStat_Handler,
+		},
+		{
+			MethodName: "BatchStartBuild",
+			Handler:    _OutputPaths_BatchStartBuild_Handler,
+		},
+		{
+			MethodName: "ReadDirectory",
+			Handler:    _OutputPaths_ReadDirectory_Handler,
+		},
+		{
+			MethodName: "GetDaemonStats",
+			Handler:    _OutputPaths_GetDaemonStats_Handler,
+		},
+		{
+			MethodName: "SetExternalPathPolicy",
+			Handler:    _OutputPaths_SetExternalPathPolicy_Handler,
+		},
+		{
+			MethodName: "GetDirectoryResidency",
+			Handler:    _OutputPaths_GetDirectoryResidency_Handler,
+		},
+		{
+			MethodName: "SetOutputPath",
+			Handler:    _OutputPaths_SetOutputPath_Handler,
+		},
+		{
+			MethodName: "FinalizeSubtree",
+			Handler:    _OutputPaths_FinalizeSubtree_Handler,
+		},
+		{
+			MethodName: "ListFinalizedBuilds",
+			Handler:    _OutputPaths_ListFinalizedBuilds_Handler,
+		},
+		{
+			MethodName: "SetDefaultPathPrefix",
+			Handler:    _OutputPaths_SetDefaultPathPrefix_Handler,
+		},
+		{
+			MethodName: "RepairOutputPath",
+			Handler:    _OutputPaths_RepairOutputPath_Handler,
+		},
+		{
+			MethodName: "ReopenBuild",
+			Handler:    _OutputPaths_ReopenBuild_Handler,
+		},
+		{
+			MethodName: "InvalidateCache",
+			Handler:    _OutputPaths_InvalidateCache_Handler,
+		},
+		{
+			MethodName: "CleanAndStartBuild",
+			Handler:    _OutputPaths_CleanAndStartBuild_Handler,
+		},
+		{
+			MethodName: "CloneOutputPath",
+			Handler:    _OutputPaths_CloneOutputPath_Handler,
+		},
+		{
+			MethodName: "StatDigest",
+			Handler:    _OutputPaths_StatDigest_Handler,
+		},
+		{
+			MethodName: "RenameOutputBase",
+			Handler:    _OutputPaths_RenameOutputBase_Handler,
+		},
+		{
+			MethodName: "GetOutputPathDigestFunctions",
+			Handler:    _OutputPaths_GetOutputPathDigestFunctions_Handler,
+		},
+		{
+			MethodName: "SealBuild",
+			Handler:    _OutputPaths_SealBuild_Handler,
+		},
+		{
+			MethodName: "GetOutputPath",
+			Handler:    _OutputPaths_GetOutputPath_Handler,
+		},
+		{
+			MethodName: "VerifyTree",
+			Handler:    _OutputPaths_VerifyTree_Handler,
+		},
+		{
+			MethodName: "SetBuildMetadata",
+			Handler:    _OutputPaths_SetBuildMetadata_Handler,
+		},
+		{
+			MethodName: "DiffBuilds",
+			Handler:    _OutputPaths_DiffBuilds_Handler,
+		},
+		{
+			MethodName: "ResolveOutputPath",
+			Handler:    _OutputPaths_ResolveOutputPath_Handler,
+		},
+		{
+			MethodName: "ListOutputPaths",
+			Handler:    _OutputPaths_ListOutputPaths_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StatStream",
+			Handler:       _OutputPaths_StatStream_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "CreateStream",
+			Handler:       _OutputPaths_CreateStream_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "Materialize",
+			Handler:       _OutputPaths_Materialize_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StartBuildStream",
+			Handler:       _OutputPaths_StartBuildStream_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "CleanStream",
+			Handler:       _OutputPaths_CleanStream_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ExportTarball",
+			Handler:       _OutputPaths_ExportTarball_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "WatchBuilds",
+			Handler:       _OutputPaths_WatchBuilds_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "pkg/proto/outputpaths/outputpaths.proto",
+}
@@ -0,0 +1,266 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.31.0
+// 	protoc        v4.23.4
+// source: pkg/proto/remoteoutputservicerecording/remoteoutputservicerecording.proto
+
+package remoteoutputservicerecording
+
+import (
+	remoteoutputservice "github.com/buildbarn/bb-remote-execution/pkg/proto/remoteoutputservice"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type RecordedCall struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Time *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=time,proto3" json:"time,omitempty"`
+	// Types that are assignable to Request:
+	//	*RecordedCall_StartBuild
+	//	*RecordedCall_BatchCreate
+	//	*RecordedCall_BatchStat
+	//	*RecordedCall_FinalizeBuild
+	Request isRecordedCall_Request `protobuf_oneof:"request"`
+}
+
+func (x *RecordedCall) Reset() {
+	*x = RecordedCall{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_remoteoutputservicerecording_remoteoutputservicerecording_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RecordedCall) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecordedCall) ProtoMessage() {}
+
+func (x *RecordedCall) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_remoteoutputservicerecording_remoteoutputservicerecording_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RecordedCall.ProtoReflect.Descriptor instead.
+func (*RecordedCall) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_remoteoutputservicerecording_remoteoutputservicerecording_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *RecordedCall) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (m *RecordedCall) GetRequest() isRecordedCall_Request {
+	if m != nil {
+		return m.Request
+	}
+	return nil
+}
+
+func (x *RecordedCall) GetStartBuild() *remoteoutputservice.StartBuildRequest {
+	if x, ok := x.GetRequest().(*RecordedCall_StartBuild); ok {
+		return x.StartBuild
+	}
+	return nil
+}
+
+func (x *RecordedCall) GetBatchCreate() *remoteoutputservice.BatchCreateRequest {
+	if x, ok := x.GetRequest().(*RecordedCall_BatchCreate); ok {
+		return x.BatchCreate
+	}
+	return nil
+}
+
+func (x *RecordedCall) GetBatchStat() *remoteoutputservice.BatchStatRequest {
+	if x, ok := x.GetRequest().(*RecordedCall_BatchStat); ok {
+		return x.BatchStat
+	}
+	return nil
+}
+
+func (x *RecordedCall) GetFinalizeBuild() *remoteoutputservice.FinalizeBuildRequest {
+	if x, ok := x.GetRequest().(*RecordedCall_FinalizeBuild); ok {
+		return x.FinalizeBuild
+	}
+	return nil
+}
+
+type isRecordedCall_Request interface {
+	isRecordedCall_Request()
+}
+
+type RecordedCall_StartBuild struct {
+	StartBuild *remoteoutputservice.StartBuildRequest `protobuf:"bytes,2,opt,name=start_build,json=startBuild,proto3,oneof"`
+}
+
+type RecordedCall_BatchCreate struct {
+	BatchCreate *remoteoutputservice.BatchCreateRequest `protobuf:"bytes,3,opt,name=batch_create,json=batchCreate,proto3,oneof"`
+}
+
+type RecordedCall_BatchStat struct {
+	BatchStat *remoteoutputservice.BatchStatRequest `protobuf:"bytes,4,opt,name=batch_stat,json=batchStat,proto3,oneof"`
+}
+
+type RecordedCall_FinalizeBuild struct {
+	FinalizeBuild *remoteoutputservice.FinalizeBuildRequest `protobuf:"bytes,5,opt,name=finalize_build,json=finalizeBuild,proto3,oneof"`
+}
+
+func (*RecordedCall_StartBuild) isRecordedCall_Request() {}
+
+func (*RecordedCall_BatchCreate) isRecordedCall_Request() {}
+
+func (*RecordedCall_BatchStat) isRecordedCall_Request() {}
+
+func (*RecordedCall_FinalizeBuild) isRecordedCall_Request() {}
+
+var File_pkg_proto_remoteoutputservicerecording_remoteoutputservicerecording_proto protoreflect.FileDescriptor
+
+var file_pkg_proto_remoteoutputservicerecording_remoteoutputservicerecording_proto_rawDesc = []byte{
+	0x0a, 0x49, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x72, 0x65, 0x6d, 0x6f,
+	0x74, 0x65, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x72,
+	0x65, 0x63, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x2f, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x6f,
+	0x75, 0x74, 0x70, 0x75, 0x74, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x72, 0x65, 0x63, 0x6f,
+	0x72, 0x64, 0x69, 0x6e, 0x67, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x26, 0x62, 0x75, 0x69,
+	0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x6f, 0x75, 0x74,
+	0x70, 0x75, 0x74, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64,
+	0x69, 0x6e, 0x67, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x39, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f,
+	0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x73, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x2f, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x5f, 0x6f, 0x75, 0x74, 0x70, 0x75,
+	0x74, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22,
+	0x86, 0x03, 0x0a, 0x0c, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x65, 0x64, 0x43, 0x61, 0x6c, 0x6c,
+	0x12, 0x2e, 0x0a, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x04, 0x74, 0x69, 0x6d, 0x65,
+	0x12, 0x4b, 0x0a, 0x0b, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x28, 0x2e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x5f, 0x6f,
+	0x75, 0x74, 0x70, 0x75, 0x74, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x53, 0x74,
+	0x61, 0x72, 0x74, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x48,
+	0x00, 0x52, 0x0a, 0x73, 0x74, 0x61, 0x72, 0x74, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x12, 0x4e, 0x0a,
+	0x0c, 0x62, 0x61, 0x74, 0x63, 0x68, 0x5f, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x29, 0x2e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x5f, 0x6f, 0x75, 0x74,
+	0x70, 0x75, 0x74, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x42, 0x61, 0x74, 0x63,
+	0x68, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x48, 0x00,
+	0x52, 0x0b, 0x62, 0x61, 0x74, 0x63, 0x68, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x12, 0x48, 0x0a,
+	0x0a, 0x62, 0x61, 0x74, 0x63, 0x68, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x27, 0x2e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x5f, 0x6f, 0x75, 0x74, 0x70, 0x75,
+	0x74, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x53,
+	0x74, 0x61, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x48, 0x00, 0x52, 0x09, 0x62, 0x61,
+	0x74, 0x63, 0x68, 0x53, 0x74, 0x61, 0x74, 0x12, 0x54, 0x0a, 0x0e, 0x66, 0x69, 0x6e, 0x61, 0x6c,
+	0x69, 0x7a, 0x65, 0x5f, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x2b, 0x2e, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x5f, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x5f,
+	0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x46, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65,
+	0x42, 0x75, 0x69, 0x6c, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x48, 0x00, 0x52, 0x0d,
+	0x66, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x42, 0x09, 0x0a,
+	0x07, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x42, 0x48, 0x5a, 0x46, 0x67, 0x69, 0x74, 0x68,
+	0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e,
+	0x2f, 0x62, 0x62, 0x2d, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x64, 0x2f, 0x70, 0x6b, 0x67, 0x2f,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x6f, 0x75, 0x74, 0x70,
+	0x75, 0x74, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x69,
+	0x6e, 0x67, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_pkg_proto_remoteoutputservicerecording_remoteoutputservicerecording_proto_rawDescOnce sync.Once
+	file_pkg_proto_remoteoutputservicerecording_remoteoutputservicerecording_proto_rawDescData = file_pkg_proto_remoteoutputservicerecording_remoteoutputservicerecording_proto_rawDesc
+)
+
+func file_pkg_proto_remoteoutputservicerecording_remoteoutputservicerecording_proto_rawDescGZIP() []byte {
+	file_pkg_proto_remoteoutputservicerecording_remoteoutputservicerecording_proto_rawDescOnce.Do(func() {
+		file_pkg_proto_remoteoutputservicerecording_remoteoutputservicerecording_proto_rawDescData = protoimpl.X.CompressGZIP(file_pkg_proto_remoteoutputservicerecording_remoteoutputservicerecording_proto_rawDescData)
+	})
+	return file_pkg_proto_remoteoutputservicerecording_remoteoutputservicerecording_proto_rawDescData
+}
+
+var file_pkg_proto_remoteoutputservicerecording_remoteoutputservicerecording_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_pkg_proto_remoteoutputservicerecording_remoteoutputservicerecording_proto_goTypes = []interface{}{
+	(*RecordedCall)(nil),                             // 0: buildbarn.remoteoutputservicerecording.RecordedCall
+	(*timestamppb.Timestamp)(nil),                    // 1: google.protobuf.Timestamp
+	(*remoteoutputservice.StartBuildRequest)(nil),    // 2: remote_output_service.StartBuildRequest
+	(*remoteoutputservice.BatchCreateRequest)(nil),   // 3: remote_output_service.BatchCreateRequest
+	(*remoteoutputservice.BatchStatRequest)(nil),     // 4: remote_output_service.BatchStatRequest
+	(*remoteoutputservice.FinalizeBuildRequest)(nil), // 5: remote_output_service.FinalizeBuildRequest
+}
+var file_pkg_proto_remoteoutputservicerecording_remoteoutputservicerecording_proto_depIdxs = []int32{
+	1, // 0: buildbarn.remoteoutputservicerecording.RecordedCall.time:type_name -> google.protobuf.Timestamp
+	2, // 1: buildbarn.remoteoutputservicerecording.RecordedCall.start_build:type_name -> remote_output_service.StartBuildRequest
+	3, // 2: buildbarn.remoteoutputservicerecording.RecordedCall.batch_create:type_name -> remote_output_service.BatchCreateRequest
+	4, // 3: buildbarn.remoteoutputservicerecording.RecordedCall.batch_stat:type_name -> remote_output_service.BatchStatRequest
+	5, // 4: buildbarn.remoteoutputservicerecording.RecordedCall.finalize_build:type_name -> remote_output_service.FinalizeBuildRequest
+	5, // [5:5] is the sub-list for method output_type
+	5, // [5:5] is the sub-list for method input_type
+	5, // [5:5] is the sub-list for extension type_name
+	5, // [5:5] is the sub-list for extension extendee
+	0, // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_pkg_proto_remoteoutputservicerecording_remoteoutputservicerecording_proto_init() }
+func file_pkg_proto_remoteoutputservicerecording_remoteoutputservicerecording_proto_init() {
+	if File_pkg_proto_remoteoutputservicerecording_remoteoutputservicerecording_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_pkg_proto_remoteoutputservicerecording_remoteoutputservicerecording_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RecordedCall); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_pkg_proto_remoteoutputservicerecording_remoteoutputservicerecording_proto_msgTypes[0].OneofWrappers = []interface{}{
+		(*RecordedCall_StartBuild)(nil),
+		(*RecordedCall_BatchCreate)(nil),
+		(*RecordedCall_BatchStat)(nil),
+		(*RecordedCall_FinalizeBuild)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_pkg_proto_remoteoutputservicerecording_remoteoutputservicerecording_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_pkg_proto_remoteoutputservicerecording_remoteoutputservicerecording_proto_goTypes,
+		DependencyIndexes: file_pkg_proto_remoteoutputservicerecording_remoteoutputservicerecording_proto_depIdxs,
+		MessageInfos:      file_pkg_proto_remoteoutputservicerecording_remoteoutputservicerecording_proto_msgTypes,
+	}.Build()
+	File_pkg_proto_remoteoutputservicerecording_remoteoutputservicerecording_proto = out.File
+	file_pkg_proto_remoteoutputservicerecording_remoteoutputservicerecording_proto_rawDesc = nil
+	file_pkg_proto_remoteoutputservicerecording_remoteoutputservicerecording_proto_goTypes = nil
+	file_pkg_proto_remoteoutputservicerecording_remoteoutputservicerecording_proto_depIdxs = nil
+}
@@ -0,0 +1,192 @@
+package remoteoutputservice
+
+import (
+	"context"
+	"sync"
+
+	"github.com/buildbarn/bb-clientd/pkg/proto/remoteoutputservicerecording"
+	remoteoutputservice_pb "github.com/buildbarn/bb-remote-execution/pkg/proto/remoteoutputservice"
+	"github.com/buildbarn/bb-storage/pkg/clock"
+	"github.com/buildbarn/bb-storage/pkg/filesystem"
+	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type recording struct {
+	buildID   string
+	appender  filesystem.FileAppender
+	sizeBytes int64
+}
+
+type recordingServer struct {
+	base             remoteoutputservice_pb.RemoteOutputServiceServer
+	directory        filesystem.Directory
+	clock            clock.Clock
+	errorLogger      util.ErrorLogger
+	maximumSizeBytes int64
+
+	lock                     sync.Mutex
+	recordingsByBuildID      map[string]*recording
+	recordingsByOutputBaseID map[string]*recording
+}
+
+// NewRecordingServer creates a decorator for RemoteOutputServiceServer
+// that records calls to StartBuild(), BatchCreate(), BatchStat() and
+// FinalizeBuild(). Calls are written to a file in the provided
+// directory that is named after the build ID, so that they can be
+// replayed against another instance of bb_clientd.
+//
+// Once the recording of a build reaches the provided maximum size,
+// successive calls for that build are no longer recorded.
+func NewRecordingServer(base remoteoutputservice_pb.RemoteOutputServiceServer, directory filesystem.Directory, clock clock.Clock, errorLogger util.ErrorLogger, maximumSizeBytes int64) remoteoutputservice_pb.RemoteOutputServiceServer {
+	return &recordingServer{
+		base:             base,
+		directory:        directory,
+		clock:            clock,
+		errorLogger:      errorLogger,
+		maximumSizeBytes: maximumSizeBytes,
+
+		recordingsByBuildID:      map[string]*recording{},
+		recordingsByOutputBaseID: map[string]*recording{},
+	}
+}
+
+// closeRecordingLocked closes the file to which calls for a build are
+// written, and stops recording calls for that build.
+func (s *recordingServer) closeRecordingLocked(r *recording) {
+	if s.recordingsByBuildID[r.buildID] == r {
+		delete(s.recordingsByBuildID, r.buildID)
+		if err := r.appender.Close(); err != nil {
+			s.errorLogger.Log(util.StatusWrapf(err, "Failed to close recording for build ID %#v", r.buildID))
+		}
+	}
+}
+
+// startRecording creates a new file to which calls for a build are
+// written. Any recording for a previous build of the same output base
+// is closed, as the build is forcefully finalized.
+func (s *recordingServer) startRecording(outputBaseID, buildID string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if r, ok := s.recordingsByOutputBaseID[outputBaseID]; ok {
+		if r.buildID == buildID {
+			// StartBuild() is called again for the same
+			// build. Continue the existing recording.
+			return
+		}
+		s.closeRecordingLocked(r)
+		delete(s.recordingsByOutputBaseID, outputBaseID)
+	}
+
+	// Build IDs are provided by the client. Only permit the ones
+	// that are valid filenames, to prevent recordings from being
+	// written to arbitrary locations.
+	name, ok := path.NewComponent(buildID)
+	if !ok {
+		s.errorLogger.Log(status.Errorf(codes.InvalidArgument, "Cannot record calls for build ID %#v, as it is not a valid filename", buildID))
+		return
+	}
+	appender, err := s.directory.OpenAppend(name, filesystem.CreateReuse(0o666))
+	if err != nil {
+		s.errorLogger.Log(util.StatusWrapf(err, "Failed to create recording for build ID %#v", buildID))
+		return
+	}
+	r := &recording{
+		buildID:  buildID,
+		appender: appender,
+	}
+	s.recordingsByBuildID[buildID] = r
+	s.recordingsByOutputBaseID[outputBaseID] = r
+}
+
+// record a single call against a build, if calls for the build are
+// being recorded.
+func (s *recordingServer) record(buildID string, call *remoteoutputservicerecording.RecordedCall) {
+	call.Time = timestamppb.New(s.clock.Now())
+	data, err := proto.Marshal(call)
+	if err != nil {
+		s.errorLogger.Log(util.StatusWrapf(err, "Failed to marshal call for build ID %#v", buildID))
+		return
+	}
+	data = append(protowire.AppendVarint(nil, uint64(len(data))), data...)
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	r, ok := s.recordingsByBuildID[buildID]
+	if !ok {
+		return
+	}
+	if newSizeBytes := r.sizeBytes + int64(len(data)); newSizeBytes > s.maximumSizeBytes {
+		s.errorLogger.Log(status.Errorf(codes.ResourceExhausted, "Recording for build ID %#v would reach %d bytes in size, which exceeds the permitted maximum of %d bytes. Subsequent calls will not be recorded.", buildID, newSizeBytes, s.maximumSizeBytes))
+		s.closeRecordingLocked(r)
+		return
+	}
+	if _, err := r.appender.Write(data); err != nil {
+		s.errorLogger.Log(util.StatusWrapf(err, "Failed to write to recording for build ID %#v", buildID))
+		s.closeRecordingLocked(r)
+		return
+	}
+	r.sizeBytes += int64(len(data))
+}
+
+// finishRecording closes the file to which calls for a build are
+// written, if calls for the build are being recorded.
+func (s *recordingServer) finishRecording(buildID string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if r, ok := s.recordingsByBuildID[buildID]; ok {
+		s.closeRecordingLocked(r)
+	}
+}
+
+func (s *recordingServer) Clean(ctx context.Context, request *remoteoutputservice_pb.CleanRequest) (*emptypb.Empty, error) {
+	return s.base.Clean(ctx, request)
+}
+
+func (s *recordingServer) StartBuild(ctx context.Context, request *remoteoutputservice_pb.StartBuildRequest) (*remoteoutputservice_pb.StartBuildResponse, error) {
+	s.startRecording(request.OutputBaseId, request.BuildId)
+	s.record(request.BuildId, &remoteoutputservicerecording.RecordedCall{
+		Request: &remoteoutputservicerecording.RecordedCall_StartBuild{
+			StartBuild: request,
+		},
+	})
+	return s.base.StartBuild(ctx, request)
+}
+
+func (s *recordingServer) BatchCreate(ctx context.Context, request *remoteoutputservice_pb.BatchCreateRequest) (*emptypb.Empty, error) {
+	s.record(request.BuildId, &remoteoutputservicerecording.RecordedCall{
+		Request: &remoteoutputservicerecording.RecordedCall_BatchCreate{
+			BatchCreate: request,
+		},
+	})
+	return s.base.BatchCreate(ctx, request)
+}
+
+func (s *recordingServer) BatchStat(ctx context.Context, request *remoteoutputservice_pb.BatchStatRequest) (*remoteoutputservice_pb.BatchStatResponse, error) {
+	s.record(request.BuildId, &remoteoutputservicerecording.RecordedCall{
+		Request: &remoteoutputservicerecording.RecordedCall_BatchStat{
+			BatchStat: request,
+		},
+	})
+	return s.base.BatchStat(ctx, request)
+}
+
+func (s *recordingServer) FinalizeBuild(ctx context.Context, request *remoteoutputservice_pb.FinalizeBuildRequest) (*emptypb.Empty, error) {
+	s.record(request.BuildId, &remoteoutputservicerecording.RecordedCall{
+		Request: &remoteoutputservicerecording.RecordedCall_FinalizeBuild{
+			FinalizeBuild: request,
+		},
+	})
+	s.finishRecording(request.BuildId)
+	return s.base.FinalizeBuild(ctx, request)
+}
@@ -0,0 +1,189 @@
+package remoteoutputservice_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/buildbarn/bb-clientd/internal/mock"
+	"github.com/buildbarn/bb-clientd/pkg/proto/remoteoutputservicerecording"
+	"github.com/buildbarn/bb-clientd/pkg/remoteoutputservice"
+	remoteoutputservice_pb "github.com/buildbarn/bb-remote-execution/pkg/proto/remoteoutputservice"
+	"github.com/buildbarn/bb-storage/pkg/filesystem/path"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protodelim"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestRecordingServer(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	baseServer := mock.NewMockRemoteOutputServiceServer(ctrl)
+	directory := mock.NewMockDirectory(ctrl)
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(1000, 0)).AnyTimes()
+	errorLogger := mock.NewMockErrorLogger(ctrl)
+	server := remoteoutputservice.NewRecordingServer(baseServer, directory, clock, errorLogger, 1000)
+
+	t.Run("InvalidBuildID", func(t *testing.T) {
+		// Build IDs that are not valid filenames cannot be
+		// recorded. Calls should still be forwarded.
+		startBuildRequest := &remoteoutputservice_pb.StartBuildRequest{
+			OutputBaseId: "9da951b8cb759233037166e28f7ea186",
+			BuildId:      "../../etc/passwd",
+		}
+		errorLogger.EXPECT().Log(testutil.EqStatus(t, status.Error(codes.InvalidArgument, "Cannot record calls for build ID \"../../etc/passwd\", as it is not a valid filename")))
+		baseServer.EXPECT().StartBuild(ctx, startBuildRequest).Return(&remoteoutputservice_pb.StartBuildResponse{}, nil)
+
+		_, err := server.StartBuild(ctx, startBuildRequest)
+		require.NoError(t, err)
+
+		batchStatRequest := &remoteoutputservice_pb.BatchStatRequest{
+			BuildId: "../../etc/passwd",
+			Paths:   []string{"hello.txt"},
+		}
+		baseServer.EXPECT().BatchStat(ctx, batchStatRequest).Return(&remoteoutputservice_pb.BatchStatResponse{}, nil)
+
+		_, err = server.BatchStat(ctx, batchStatRequest)
+		require.NoError(t, err)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		// All calls for a build should be written to a file
+		// named after the build ID, until the build is
+		// finalized.
+		appender := mock.NewMockFileAppender(ctrl)
+		directory.EXPECT().OpenAppend(path.MustNewComponent("37f5dbef-b117-4fb6-bce8-5c147cb603b4"), gomock.Any()).Return(appender, nil)
+		var recorded bytes.Buffer
+		appender.EXPECT().Write(gomock.Any()).DoAndReturn(recorded.Write).Times(3)
+		appender.EXPECT().Close()
+
+		startBuildRequest := &remoteoutputservice_pb.StartBuildRequest{
+			OutputBaseId: "9da951b8cb759233037166e28f7ea186",
+			BuildId:      "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		}
+		baseServer.EXPECT().StartBuild(ctx, startBuildRequest).Return(&remoteoutputservice_pb.StartBuildResponse{}, nil)
+		_, err := server.StartBuild(ctx, startBuildRequest)
+		require.NoError(t, err)
+
+		batchStatRequest := &remoteoutputservice_pb.BatchStatRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Paths:   []string{"hello.txt"},
+		}
+		baseServer.EXPECT().BatchStat(ctx, batchStatRequest).Return(nil, status.Error(codes.Internal, "Disk failure"))
+		_, err = server.BatchStat(ctx, batchStatRequest)
+		testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Disk failure"), err)
+
+		finalizeBuildRequest := &remoteoutputservice_pb.FinalizeBuildRequest{
+			BuildId:         "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			BuildSuccessful: true,
+		}
+		baseServer.EXPECT().FinalizeBuild(ctx, finalizeBuildRequest).Return(&emptypb.Empty{}, nil)
+		_, err = server.FinalizeBuild(ctx, finalizeBuildRequest)
+		require.NoError(t, err)
+
+		// Calls after the build is finalized should not be
+		// recorded.
+		baseServer.EXPECT().BatchStat(ctx, batchStatRequest).Return(&remoteoutputservice_pb.BatchStatResponse{}, nil)
+		_, err = server.BatchStat(ctx, batchStatRequest)
+		require.NoError(t, err)
+
+		reader := bufio.NewReader(&recorded)
+		for _, expectedCall := range []*remoteoutputservicerecording.RecordedCall{
+			{
+				Time: &timestamppb.Timestamp{Seconds: 1000},
+				Request: &remoteoutputservicerecording.RecordedCall_StartBuild{
+					StartBuild: startBuildRequest,
+				},
+			},
+			{
+				Time: &timestamppb.Timestamp{Seconds: 1000},
+				Request: &remoteoutputservicerecording.RecordedCall_BatchStat{
+					BatchStat: batchStatRequest,
+				},
+			},
+			{
+				Time: &timestamppb.Timestamp{Seconds: 1000},
+				Request: &remoteoutputservicerecording.RecordedCall_FinalizeBuild{
+					FinalizeBuild: finalizeBuildRequest,
+				},
+			},
+		} {
+			var call remoteoutputservicerecording.RecordedCall
+			require.NoError(t, protodelim.UnmarshalFrom(reader, &call))
+			testutil.RequireEqualProto(t, expectedCall, &call)
+		}
+		_, err = reader.ReadByte()
+		require.Error(t, err)
+	})
+
+	t.Run("MaximumSizeExceeded", func(t *testing.T) {
+		// Once the recording reaches the maximum size, it
+		// should be closed. Successive calls should not be
+		// recorded.
+		appender := mock.NewMockFileAppender(ctrl)
+		directory.EXPECT().OpenAppend(path.MustNewComponent("ef9162d6-838b-4dfe-904d-ec0fc8cf154b"), gomock.Any()).Return(appender, nil)
+		appender.EXPECT().Write(gomock.Any()).DoAndReturn(func(p []byte) (int, error) { return len(p), nil })
+		appender.EXPECT().Close()
+
+		startBuildRequest := &remoteoutputservice_pb.StartBuildRequest{
+			OutputBaseId: "9da951b8cb759233037166e28f7ea186",
+			BuildId:      "ef9162d6-838b-4dfe-904d-ec0fc8cf154b",
+		}
+		baseServer.EXPECT().StartBuild(ctx, startBuildRequest).Return(&remoteoutputservice_pb.StartBuildResponse{}, nil)
+		_, err := server.StartBuild(ctx, startBuildRequest)
+		require.NoError(t, err)
+
+		batchStatRequest := &remoteoutputservice_pb.BatchStatRequest{
+			BuildId: "ef9162d6-838b-4dfe-904d-ec0fc8cf154b",
+			Paths:   []string{strings.Repeat("a", 1000)},
+		}
+		errorLogger.EXPECT().Log(gomock.Any()).Do(func(err error) {
+			require.Equal(t, codes.ResourceExhausted, status.Code(err))
+		})
+		baseServer.EXPECT().BatchStat(ctx, batchStatRequest).Return(&remoteoutputservice_pb.BatchStatResponse{}, nil).Times(2)
+		_, err = server.BatchStat(ctx, batchStatRequest)
+		require.NoError(t, err)
+		_, err = server.BatchStat(ctx, batchStatRequest)
+		require.NoError(t, err)
+	})
+
+	t.Run("PreviousBuildNotFinalized", func(t *testing.T) {
+		// Starting a build for an output base for which a
+		// build is still being recorded should close the
+		// previous recording.
+		appender1 := mock.NewMockFileAppender(ctrl)
+		directory.EXPECT().OpenAppend(path.MustNewComponent("5a8a8a0c-e7eb-41d1-b8c1-2d2e1a6cd5b6"), gomock.Any()).Return(appender1, nil)
+		appender1.EXPECT().Write(gomock.Any()).DoAndReturn(func(p []byte) (int, error) { return len(p), nil })
+
+		startBuildRequest1 := &remoteoutputservice_pb.StartBuildRequest{
+			OutputBaseId: "9da951b8cb759233037166e28f7ea186",
+			BuildId:      "5a8a8a0c-e7eb-41d1-b8c1-2d2e1a6cd5b6",
+		}
+		baseServer.EXPECT().StartBuild(ctx, startBuildRequest1).Return(&remoteoutputservice_pb.StartBuildResponse{}, nil)
+		_, err := server.StartBuild(ctx, startBuildRequest1)
+		require.NoError(t, err)
+
+		appender1.EXPECT().Close()
+		appender2 := mock.NewMockFileAppender(ctrl)
+		directory.EXPECT().OpenAppend(path.MustNewComponent("4c1fb2c4-0bb6-4a4b-8c4f-1e9b0f5bd4f1"), gomock.Any()).Return(appender2, nil)
+		appender2.EXPECT().Write(gomock.Any()).DoAndReturn(func(p []byte) (int, error) { return len(p), nil })
+
+		startBuildRequest2 := &remoteoutputservice_pb.StartBuildRequest{
+			OutputBaseId: "9da951b8cb759233037166e28f7ea186",
+			BuildId:      "4c1fb2c4-0bb6-4a4b-8c4f-1e9b0f5bd4f1",
+		}
+		baseServer.EXPECT().StartBuild(ctx, startBuildRequest2).Return(&remoteoutputservice_pb.StartBuildResponse{}, nil)
+		_, err = server.StartBuild(ctx, startBuildRequest2)
+		require.NoError(t, err)
+	})
+}
@@ -0,0 +1,90 @@
+package remoteoutputservice
+
+import (
+	"context"
+	"time"
+
+	remoteoutputservice_pb "github.com/buildbarn/bb-remote-execution/pkg/proto/remoteoutputservice"
+	"github.com/buildbarn/bb-storage/pkg/clock"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type slowRequestLoggingServer struct {
+	base        remoteoutputservice_pb.RemoteOutputServiceServer
+	clock       clock.Clock
+	errorLogger util.ErrorLogger
+	threshold   time.Duration
+}
+
+// NewSlowRequestLoggingServer creates a decorator for
+// RemoteOutputServiceServer that measures how long calls take to
+// complete. Calls that take longer than a given threshold are logged,
+// together with parameters of the request. This makes it possible to
+// identify pathological builds, without logging every individual
+// call.
+func NewSlowRequestLoggingServer(base remoteoutputservice_pb.RemoteOutputServiceServer, clock clock.Clock, errorLogger util.ErrorLogger, threshold time.Duration) remoteoutputservice_pb.RemoteOutputServiceServer {
+	return &slowRequestLoggingServer{
+		base:        base,
+		clock:       clock,
+		errorLogger: errorLogger,
+		threshold:   threshold,
+	}
+}
+
+// logIfSlow logs a message if the amount of time that has passed
+// since the start of a call exceeds the threshold.
+func (s *slowRequestLoggingServer) logIfSlow(start time.Time, format string, args ...any) {
+	if duration := s.clock.Now().Sub(start); duration > s.threshold {
+		s.errorLogger.Log(util.StatusWrapf(
+			status.Errorf(codes.DeadlineExceeded, format, args...),
+			"Call took %s, which exceeds the threshold of %s",
+			duration,
+			s.threshold))
+	}
+}
+
+func (s *slowRequestLoggingServer) Clean(ctx context.Context, request *remoteoutputservice_pb.CleanRequest) (*emptypb.Empty, error) {
+	start := s.clock.Now()
+	response, err := s.base.Clean(ctx, request)
+	s.logIfSlow(start, "Clean() for output base %#v", request.OutputBaseId)
+	return response, err
+}
+
+func (s *slowRequestLoggingServer) StartBuild(ctx context.Context, request *remoteoutputservice_pb.StartBuildRequest) (*remoteoutputservice_pb.StartBuildResponse, error) {
+	start := s.clock.Now()
+	response, err := s.base.StartBuild(ctx, request)
+	s.logIfSlow(start, "StartBuild() for output base %#v with build ID %#v", request.OutputBaseId, request.BuildId)
+	return response, err
+}
+
+func (s *slowRequestLoggingServer) BatchCreate(ctx context.Context, request *remoteoutputservice_pb.BatchCreateRequest) (*emptypb.Empty, error) {
+	start := s.clock.Now()
+	response, err := s.base.BatchCreate(ctx, request)
+	s.logIfSlow(
+		start,
+		"BatchCreate() for build ID %#v with path prefix %#v, %d file(s), %d directories and %d symbolic link(s)",
+		request.BuildId,
+		request.PathPrefix,
+		len(request.Files),
+		len(request.Directories),
+		len(request.Symlinks))
+	return response, err
+}
+
+func (s *slowRequestLoggingServer) BatchStat(ctx context.Context, request *remoteoutputservice_pb.BatchStatRequest) (*remoteoutputservice_pb.BatchStatResponse, error) {
+	start := s.clock.Now()
+	response, err := s.base.BatchStat(ctx, request)
+	s.logIfSlow(start, "BatchStat() for build ID %#v with %d path(s)", request.BuildId, len(request.Paths))
+	return response, err
+}
+
+func (s *slowRequestLoggingServer) FinalizeBuild(ctx context.Context, request *remoteoutputservice_pb.FinalizeBuildRequest) (*emptypb.Empty, error) {
+	start := s.clock.Now()
+	response, err := s.base.FinalizeBuild(ctx, request)
+	s.logIfSlow(start, "FinalizeBuild() for build ID %#v", request.BuildId)
+	return response, err
+}
@@ -0,0 +1,75 @@
+package remoteoutputservice_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-clientd/internal/mock"
+	"github.com/buildbarn/bb-clientd/pkg/remoteoutputservice"
+	remoteoutputservice_pb "github.com/buildbarn/bb-remote-execution/pkg/proto/remoteoutputservice"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func TestSlowRequestLoggingServer(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	baseServer := mock.NewMockRemoteOutputServiceServer(ctrl)
+	clock := mock.NewMockClock(ctrl)
+	errorLogger := mock.NewMockErrorLogger(ctrl)
+	server := remoteoutputservice.NewSlowRequestLoggingServer(baseServer, clock, errorLogger, 5*time.Second)
+
+	t.Run("Fast", func(t *testing.T) {
+		// Calls completing within the threshold should not be
+		// logged.
+		request := &remoteoutputservice_pb.BatchStatRequest{
+			BuildId: "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			Paths:   []string{"a", "b", "c"},
+		}
+		clock.EXPECT().Now().Return(time.Unix(1000, 0))
+		baseServer.EXPECT().BatchStat(ctx, request).Return(&remoteoutputservice_pb.BatchStatResponse{}, nil)
+		clock.EXPECT().Now().Return(time.Unix(1005, 0))
+
+		response, err := server.BatchStat(ctx, request)
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &remoteoutputservice_pb.BatchStatResponse{}, response)
+	})
+
+	t.Run("SlowSuccess", func(t *testing.T) {
+		request := &remoteoutputservice_pb.BatchCreateRequest{
+			BuildId:    "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+			PathPrefix: "bazel-out/k8-fastbuild",
+			Files:      make([]*remoteexecution.OutputFile, 3),
+		}
+		clock.EXPECT().Now().Return(time.Unix(1000, 0))
+		baseServer.EXPECT().BatchCreate(ctx, request).Return(&emptypb.Empty{}, nil)
+		clock.EXPECT().Now().Return(time.Unix(1007, 500000000))
+		errorLogger.EXPECT().Log(testutil.EqStatus(t, status.Error(codes.DeadlineExceeded, "Call took 7.5s, which exceeds the threshold of 5s: BatchCreate() for build ID \"37f5dbef-b117-4fb6-bce8-5c147cb603b4\" with path prefix \"bazel-out/k8-fastbuild\", 3 file(s), 0 directories and 0 symbolic link(s)")))
+
+		_, err := server.BatchCreate(ctx, request)
+		require.NoError(t, err)
+	})
+
+	t.Run("SlowFailure", func(t *testing.T) {
+		// Errors returned by the backend should be propagated,
+		// even if the call is slow.
+		request := &remoteoutputservice_pb.StartBuildRequest{
+			OutputBaseId: "9da951b8cb759233037166e28f7ea186",
+			BuildId:      "37f5dbef-b117-4fb6-bce8-5c147cb603b4",
+		}
+		clock.EXPECT().Now().Return(time.Unix(1000, 0))
+		baseServer.EXPECT().StartBuild(ctx, request).Return(nil, status.Error(codes.Internal, "Failed to find missing blobs"))
+		clock.EXPECT().Now().Return(time.Unix(1060, 0))
+		errorLogger.EXPECT().Log(testutil.EqStatus(t, status.Error(codes.DeadlineExceeded, "Call took 1m0s, which exceeds the threshold of 5s: StartBuild() for output base \"9da951b8cb759233037166e28f7ea186\" with build ID \"37f5dbef-b117-4fb6-bce8-5c147cb603b4\"")))
+
+		_, err := server.StartBuild(ctx, request)
+		testutil.RequireEqualStatus(t, status.Error(codes.Internal, "Failed to find missing blobs"), err)
+	})
+}